@@ -4,13 +4,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 
+	"github.com/nemonet1337/zaiGoFramework/pkg/auth"
 	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
 )
 
@@ -41,38 +44,51 @@ type APIResponse struct {
 // AddStockRequest represents request to add stock
 // 在庫追加リクエストを表現
 type AddStockRequest struct {
-	ItemID     string `json:"item_id"`
-	LocationID string `json:"location_id"`
-	Quantity   int64  `json:"quantity"`
-	Reference  string `json:"reference"`
+	ItemID      string                  `json:"item_id"`
+	LocationID  string                  `json:"location_id"`
+	Quantity    inventory.QuantityInput `json:"quantity"`
+	Reference   string                  `json:"reference"`
+	PostingDate *time.Time              `json:"posting_date,omitempty"` // 計上日時（省略時は現在日時）
+	UnitCost    *inventory.Money        `json:"unit_cost,omitempty"`    // 仕入単価（省略時は単価なし）
+	Currency    string                  `json:"currency,omitempty"`     // 仕入単価の通貨（省略時は基準通貨）
 }
 
 // RemoveStockRequest represents request to remove stock
 // 在庫削除リクエストを表現
 type RemoveStockRequest struct {
-	ItemID     string `json:"item_id"`
-	LocationID string `json:"location_id"`
-	Quantity   int64  `json:"quantity"`
-	Reference  string `json:"reference"`
+	ItemID      string                  `json:"item_id"`
+	LocationID  string                  `json:"location_id"`
+	Quantity    inventory.QuantityInput `json:"quantity"`
+	Reference   string                  `json:"reference"`
+	PostingDate *time.Time              `json:"posting_date,omitempty"` // 計上日時（省略時は現在日時）
 }
 
 // TransferStockRequest represents request to transfer stock
 // 在庫移動リクエストを表現
 type TransferStockRequest struct {
-	ItemID         string `json:"item_id"`
-	FromLocationID string `json:"from_location_id"`
-	ToLocationID   string `json:"to_location_id"`
-	Quantity       int64  `json:"quantity"`
-	Reference      string `json:"reference"`
+	ItemID         string                  `json:"item_id"`
+	FromLocationID string                  `json:"from_location_id"`
+	ToLocationID   string                  `json:"to_location_id"`
+	Quantity       inventory.QuantityInput `json:"quantity"`
+	Reference      string                  `json:"reference"`
+	PostingDate    *time.Time              `json:"posting_date,omitempty"` // 計上日時（省略時は現在日時）
 }
 
 // AdjustStockRequest represents request to adjust stock
 // 在庫調整リクエストを表現
 type AdjustStockRequest struct {
-	ItemID      string `json:"item_id"`
-	LocationID  string `json:"location_id"`
-	NewQuantity int64  `json:"new_quantity"`
-	Reference   string `json:"reference"`
+	ItemID      string                  `json:"item_id"`
+	LocationID  string                  `json:"location_id"`
+	NewQuantity inventory.QuantityInput `json:"new_quantity"`
+	Reference   string                  `json:"reference"`
+	PostingDate *time.Time              `json:"posting_date,omitempty"` // 計上日時（省略時は現在日時）
+}
+
+// ReverseTransactionRequest represents request to reverse a posted transaction
+// 計上済みトランザクションの取消リクエストを表現
+type ReverseTransactionRequest struct {
+	Reference string `json:"reference"`
+	Reason    string `json:"reason"`
 }
 
 // HealthCheck handles health check requests
@@ -109,8 +125,27 @@ func (h *Handlers) AddStock(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := context.WithValue(r.Context(), "user_id", "api_user")
-	if err := h.manager.Add(ctx, req.ItemID, req.LocationID, req.Quantity, req.Reference); err != nil {
-		h.sendError(w, http.StatusInternalServerError, err.Error())
+	if req.PostingDate != nil {
+		ctx = inventory.WithPostingDate(ctx, *req.PostingDate)
+	}
+	quantity, ok := h.requestQuantity(w, ctx, req.ItemID, req.Quantity)
+	if !ok {
+		return
+	}
+
+	// 仕入単価付きの入庫は計上日の為替レートで基準通貨に換算
+	if req.UnitCost != nil {
+		rateManager, ok := h.manager.(inventory.ExchangeRateManager)
+		if !ok {
+			h.sendError(w, http.StatusNotImplemented, "仕入単価付きの入庫がサポートされていません")
+			return
+		}
+		if err := rateManager.AddWithCost(ctx, req.ItemID, req.LocationID, quantity, *req.UnitCost, req.Currency, req.Reference); err != nil {
+			h.sendPostingError(w, err)
+			return
+		}
+	} else if err := h.manager.Add(ctx, req.ItemID, req.LocationID, quantity, req.Reference); err != nil {
+		h.sendPostingError(w, err)
 		return
 	}
 
@@ -129,8 +164,15 @@ func (h *Handlers) RemoveStock(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := context.WithValue(r.Context(), "user_id", "api_user")
-	if err := h.manager.Remove(ctx, req.ItemID, req.LocationID, req.Quantity, req.Reference); err != nil {
-		h.sendError(w, http.StatusInternalServerError, err.Error())
+	if req.PostingDate != nil {
+		ctx = inventory.WithPostingDate(ctx, *req.PostingDate)
+	}
+	quantity, ok := h.requestQuantity(w, ctx, req.ItemID, req.Quantity)
+	if !ok {
+		return
+	}
+	if err := h.manager.Remove(ctx, req.ItemID, req.LocationID, quantity, req.Reference); err != nil {
+		h.sendPostingError(w, err)
 		return
 	}
 
@@ -149,8 +191,15 @@ func (h *Handlers) TransferStock(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := context.WithValue(r.Context(), "user_id", "api_user")
-	if err := h.manager.Transfer(ctx, req.ItemID, req.FromLocationID, req.ToLocationID, req.Quantity, req.Reference); err != nil {
-		h.sendError(w, http.StatusInternalServerError, err.Error())
+	if req.PostingDate != nil {
+		ctx = inventory.WithPostingDate(ctx, *req.PostingDate)
+	}
+	quantity, ok := h.requestQuantity(w, ctx, req.ItemID, req.Quantity)
+	if !ok {
+		return
+	}
+	if err := h.manager.Transfer(ctx, req.ItemID, req.FromLocationID, req.ToLocationID, quantity, req.Reference); err != nil {
+		h.sendPostingError(w, err)
 		return
 	}
 
@@ -169,8 +218,15 @@ func (h *Handlers) AdjustStock(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := context.WithValue(r.Context(), "user_id", "api_user")
-	if err := h.manager.Adjust(ctx, req.ItemID, req.LocationID, req.NewQuantity, req.Reference); err != nil {
-		h.sendError(w, http.StatusInternalServerError, err.Error())
+	if req.PostingDate != nil {
+		ctx = inventory.WithPostingDate(ctx, *req.PostingDate)
+	}
+	newQuantity, ok := h.requestQuantity(w, ctx, req.ItemID, req.NewQuantity)
+	if !ok {
+		return
+	}
+	if err := h.manager.Adjust(ctx, req.ItemID, req.LocationID, newQuantity, req.Reference); err != nil {
+		h.sendPostingError(w, err)
 		return
 	}
 
@@ -179,6 +235,53 @@ func (h *Handlers) AdjustStock(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// sendPostingError maps stock posting errors to HTTP status codes
+// 在庫計上のエラーをHTTPステータスに変換して送信
+func (h *Handlers) sendPostingError(w http.ResponseWriter, err error) {
+	if _, isValidation := err.(*inventory.ValidationError); isValidation {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err == inventory.ErrPeriodClosed || err == inventory.ErrPostingBeforeSnapshot || err == inventory.ErrExchangeRateNotFound ||
+		err == inventory.ErrItemArchived || err == inventory.ErrLocationArchived {
+		h.sendError(w, http.StatusConflict, err.Error())
+		return
+	}
+	h.sendError(w, http.StatusInternalServerError, err.Error())
+}
+
+// ReverseTransaction handles transaction reversal requests
+// トランザクション取消リクエストを処理
+func (h *Handlers) ReverseTransaction(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	transactionID := vars["transactionId"]
+
+	var req ReverseTransactionRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
+			return
+		}
+	}
+
+	ctx := context.WithValue(r.Context(), "user_id", "api_user")
+	reversal, err := h.manager.ReverseTransaction(ctx, transactionID, req.Reference, req.Reason)
+	if err != nil {
+		switch err {
+		case inventory.ErrTransactionNotFound:
+			h.sendError(w, http.StatusNotFound, err.Error())
+		case inventory.ErrTransactionAlreadyReversed, inventory.ErrReversalNotReversible, inventory.ErrTransferLegNotReversible,
+			inventory.ErrPeriodClosed, inventory.ErrPostingBeforeSnapshot, inventory.ErrInsufficientStock, inventory.ErrVersionMismatch:
+			h.sendError(w, http.StatusConflict, err.Error())
+		default:
+			h.sendError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	h.sendSuccess(w, h.newQuantityFormatter(r.Context()).transaction(reversal))
+}
+
 // BatchOperation handles batch operations
 // バッチ操作を処理
 func (h *Handlers) BatchOperation(w http.ResponseWriter, r *http.Request) {
@@ -205,6 +308,24 @@ func (h *Handlers) GetStock(w http.ResponseWriter, r *http.Request) {
 	itemID := vars["itemId"]
 	locationID := vars["locationId"]
 
+	// as_of指定時は台帳から時点在庫を再構築
+	if asOfStr := r.URL.Query().Get("as_of"); asOfStr != "" {
+		asOf, err := parseAsOf(asOfStr)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, "無効なas_of形式です（形式：2006-01-02 または RFC3339）")
+			return
+		}
+
+		snapshot, err := h.manager.GetStockAsOf(r.Context(), itemID, locationID, asOf)
+		if err != nil {
+			h.sendStockAsOfError(w, err)
+			return
+		}
+
+		h.sendSuccess(w, h.newQuantityFormatter(r.Context()).stockSnapshot(snapshot))
+		return
+	}
+
 	stock, err := h.manager.GetStock(r.Context(), itemID, locationID)
 	if err != nil {
 		if err == inventory.ErrStockNotFound {
@@ -215,7 +336,21 @@ func (h *Handlers) GetStock(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.sendSuccess(w, stock)
+	h.sendSuccess(w, h.newQuantityFormatter(r.Context()).stock(stock))
+}
+
+// sendStockAsOfError maps point-in-time stock errors to HTTP status codes
+// 時点在庫照会のエラーをHTTPステータスに変換して送信
+func (h *Handlers) sendStockAsOfError(w http.ResponseWriter, err error) {
+	if _, isValidation := err.(*inventory.ValidationError); isValidation {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err == inventory.ErrItemNotFound || err == inventory.ErrLocationNotFound {
+		h.sendError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	h.sendError(w, http.StatusInternalServerError, err.Error())
 }
 
 // GetTotalStock handles get total stock requests
@@ -230,8 +365,8 @@ func (h *Handlers) GetTotalStock(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.sendSuccess(w, map[string]int64{
-		"total_quantity": total,
+	h.sendSuccess(w, map[string]interface{}{
+		"total_quantity": h.newQuantityFormatter(r.Context()).quantity(itemID, total),
 	})
 }
 
@@ -241,13 +376,36 @@ func (h *Handlers) GetStockByLocation(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	locationID := vars["locationId"]
 
+	// as_of指定時は台帳から時点在庫を再構築
+	if asOfStr := r.URL.Query().Get("as_of"); asOfStr != "" {
+		asOf, err := parseAsOf(asOfStr)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, "無効なas_of形式です（形式：2006-01-02 または RFC3339）")
+			return
+		}
+
+		snapshots, err := h.manager.GetStockByLocationAsOf(r.Context(), locationID, asOf)
+		if err != nil {
+			h.sendStockAsOfError(w, err)
+			return
+		}
+
+		h.sendSuccess(w, map[string]interface{}{
+			"stocks":      h.newQuantityFormatter(r.Context()).stockSnapshots(snapshots),
+			"location_id": locationID,
+			"as_of":       asOf,
+			"count":       len(snapshots),
+		})
+		return
+	}
+
 	stocks, err := h.manager.GetStockByLocation(r.Context(), locationID)
 	if err != nil {
 		h.sendError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	h.sendSuccess(w, stocks)
+	h.sendSuccess(w, h.newQuantityFormatter(r.Context()).stocks(stocks))
 }
 
 // GetHistory handles get history requests
@@ -270,7 +428,7 @@ func (h *Handlers) GetHistory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.sendSuccess(w, history)
+	h.sendSuccess(w, h.newQuantityFormatter(r.Context()).transactions(history))
 }
 
 // GetAlerts handles get alerts requests
@@ -307,7 +465,8 @@ func (h *Handlers) ResolveAlert(w http.ResponseWriter, r *http.Request) {
 // CreateItem handles create item requests
 // 商品作成リクエストを処理
 func (h *Handlers) CreateItem(w http.ResponseWriter, r *http.Request) {
-	var item inventory.Item
+	// is_activeの指定がない場合は有効として作成
+	item := inventory.Item{IsActive: true}
 	if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
 		h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
 		return
@@ -326,7 +485,7 @@ func (h *Handlers) CreateItem(w http.ResponseWriter, r *http.Request) {
 	// ItemManagerを使用して商品を作成
 	if itemManager, ok := h.manager.(inventory.ItemManager); ok {
 		if err := itemManager.CreateItem(r.Context(), &item); err != nil {
-			h.sendError(w, http.StatusInternalServerError, err.Error())
+			h.sendItemError(w, err)
 			return
 		}
 	} else {
@@ -369,7 +528,8 @@ func (h *Handlers) UpdateItem(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	itemID := vars["itemId"]
 
-	var item inventory.Item
+	// is_activeの指定がない場合は有効として更新
+	item := inventory.Item{IsActive: true}
 	if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
 		h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
 		return
@@ -381,11 +541,7 @@ func (h *Handlers) UpdateItem(w http.ResponseWriter, r *http.Request) {
 	// ItemManagerを使用して商品を更新
 	if itemManager, ok := h.manager.(inventory.ItemManager); ok {
 		if err := itemManager.UpdateItem(r.Context(), &item); err != nil {
-			if err == inventory.ErrItemNotFound {
-				h.sendError(w, http.StatusNotFound, "商品が見つかりません")
-			} else {
-				h.sendError(w, http.StatusInternalServerError, err.Error())
-			}
+			h.sendItemError(w, err)
 			return
 		}
 		h.sendSuccess(w, map[string]interface{}{
@@ -397,6 +553,28 @@ func (h *Handlers) UpdateItem(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// sendItemError maps item master errors to HTTP status codes
+// 商品マスタのエラーをHTTPステータスに変換して送信
+func (h *Handlers) sendItemError(w http.ResponseWriter, err error) {
+	switch err.(type) {
+	case *inventory.ValidationError:
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	case *inventory.BusinessRuleError:
+		h.sendError(w, http.StatusConflict, err.Error())
+		return
+	}
+
+	switch err {
+	case inventory.ErrItemNotFound:
+		h.sendError(w, http.StatusNotFound, "商品が見つかりません")
+	case inventory.ErrDuplicateItem, inventory.ErrItemArchived:
+		h.sendError(w, http.StatusConflict, err.Error())
+	default:
+		h.sendError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
 // CreateLocation handles create location requests
 // ロケーション作成リクエストを処理
 func (h *Handlers) CreateLocation(w http.ResponseWriter, r *http.Request) {
@@ -465,15 +643,11 @@ func (h *Handlers) DeleteItem(w http.ResponseWriter, r *http.Request) {
 	// ItemManagerを使用して商品を削除
 	if itemManager, ok := h.manager.(inventory.ItemManager); ok {
 		if err := itemManager.DeleteItem(r.Context(), itemID); err != nil {
-			if err == inventory.ErrItemNotFound {
-				h.sendError(w, http.StatusNotFound, "商品が見つかりません")
-			} else {
-				h.sendError(w, http.StatusInternalServerError, err.Error())
-			}
+			h.sendItemError(w, err)
 			return
 		}
 		h.sendSuccess(w, map[string]string{
-			"message": "商品が削除されました",
+			"message": "商品をアーカイブしました",
 		})
 	} else {
 		h.sendError(w, http.StatusNotImplemented, "商品管理機能がサポートされていません")
@@ -482,35 +656,27 @@ func (h *Handlers) DeleteItem(w http.ResponseWriter, r *http.Request) {
 
 // ListItems handles list items requests
 // 商品一覧リクエストを処理
+//
+// クエリパラメータはparseItemFilterを参照（カーソルページング、レスポンスのnext_cursorを次ページのcursorに指定）
 func (h *Handlers) ListItems(w http.ResponseWriter, r *http.Request) {
-	// offsetとlimitのパラメータを取得
-	offset := 0
-	limit := 20 // デフォルト
-
-	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
-		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
-			offset = parsedOffset
-		}
-	}
-
-	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
-		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 && parsedLimit <= 100 {
-			limit = parsedLimit
-		}
+	filter, err := parseItemFilter(r)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
 	}
 
 	// ItemManagerを使用して商品一覧を取得
 	if itemManager, ok := h.manager.(inventory.ItemManager); ok {
-		items, err := itemManager.ListItems(r.Context(), offset, limit)
+		page, err := itemManager.ListItems(r.Context(), filter)
 		if err != nil {
-			h.sendError(w, http.StatusInternalServerError, err.Error())
+			h.sendItemError(w, err)
 			return
 		}
 		h.sendSuccess(w, map[string]interface{}{
-			"items":  items,
-			"offset": offset,
-			"limit":  limit,
-			"count":  len(items),
+			"items":       page.Items,
+			"total_count": page.TotalCount,
+			"next_cursor": page.NextCursor,
+			"count":       len(page.Items),
 		})
 	} else {
 		h.sendError(w, http.StatusNotImplemented, "商品管理機能がサポートされていません")
@@ -519,30 +685,105 @@ func (h *Handlers) ListItems(w http.ResponseWriter, r *http.Request) {
 
 // SearchItems handles search items requests
 // 商品検索リクエストを処理
+//
+// qはトライグラムによるあいまい検索で、既定では類似度順に並べます。その他のクエリパラメータはparseItemFilterを参照
 func (h *Handlers) SearchItems(w http.ResponseWriter, r *http.Request) {
-	query := r.URL.Query().Get("q")
-	if query == "" {
+	filter, err := parseItemFilter(r)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if filter.Query == "" && filter.Category == "" && filter.ParentItemID == "" && len(filter.Attributes) == 0 &&
+		filter.Active == nil && filter.MinUnitCost == nil && filter.MaxUnitCost == nil && filter.HasStock == nil {
 		h.sendError(w, http.StatusBadRequest, "検索クエリが指定されていません")
 		return
 	}
 
 	// ItemManagerを使用して商品を検索
 	if itemManager, ok := h.manager.(inventory.ItemManager); ok {
-		items, err := itemManager.SearchItems(r.Context(), query)
+		page, err := itemManager.SearchItems(r.Context(), filter)
 		if err != nil {
-			h.sendError(w, http.StatusInternalServerError, err.Error())
+			h.sendItemError(w, err)
 			return
 		}
 		h.sendSuccess(w, map[string]interface{}{
-			"items": items,
-			"query": query,
-			"count": len(items),
+			"items":       page.Items,
+			"query":       filter.Query,
+			"total_count": page.TotalCount,
+			"next_cursor": page.NextCursor,
+			"count":       len(page.Items),
 		})
 	} else {
 		h.sendError(w, http.StatusNotImplemented, "商品管理機能がサポートされていません")
 	}
 }
 
+// parseItemFilter parses item list and search query parameters
+// 商品一覧・検索のクエリパラメータを解析
+//
+// q, category, parent_item_id, attr.<属性名>=<値>, active, has_stock（true/false）, min_cost, max_cost,
+// include_archived（true/false）, sort（name/sku/unit_cost/created_at/updated_at/relevance）, order（asc/desc）, cursor, limit
+func parseItemFilter(r *http.Request) (inventory.ItemSearchFilter, error) {
+	params := r.URL.Query()
+	filter := inventory.ItemSearchFilter{
+		Query:        params.Get("q"),
+		Category:     params.Get("category"),
+		ParentItemID: params.Get("parent_item_id"),
+		Sort:         inventory.ItemSortField(params.Get("sort")),
+		Cursor:       params.Get("cursor"),
+	}
+	for key, values := range params {
+		if name := strings.TrimPrefix(key, "attr."); name != key && len(values) > 0 {
+			if filter.Attributes == nil {
+				filter.Attributes = make(map[string]string)
+			}
+			filter.Attributes[name] = values[0]
+		}
+	}
+
+	for name, target := range map[string]**bool{"active": &filter.Active, "has_stock": &filter.HasStock} {
+		if value := params.Get(name); value != "" {
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				return filter, fmt.Errorf("%sはtrueまたはfalseで指定してください", name)
+			}
+			*target = &parsed
+		}
+	}
+	if value := params.Get("include_archived"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, fmt.Errorf("include_archivedはtrueまたはfalseで指定してください")
+		}
+		filter.IncludeArchived = parsed
+	}
+	for name, target := range map[string]**inventory.Money{"min_cost": &filter.MinUnitCost, "max_cost": &filter.MaxUnitCost} {
+		if value := params.Get(name); value != "" {
+			parsed, err := inventory.ParseMoney(value)
+			if err != nil {
+				return filter, fmt.Errorf("%sが無効な金額です", name)
+			}
+			*target = &parsed
+		}
+	}
+
+	switch order := params.Get("order"); order {
+	case "", "asc":
+	case "desc":
+		filter.Desc = true
+	default:
+		return filter, fmt.Errorf("無効な並び順です（asc または desc）")
+	}
+	if value := params.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			return filter, fmt.Errorf("limitが無効な件数です")
+		}
+		filter.Limit = limit
+	}
+	return filter, nil
+}
+
 // UpdateLocation handles update location requests
 // ロケーション更新リクエストを処理
 func (h *Handlers) UpdateLocation(w http.ResponseWriter, r *http.Request) {
@@ -586,15 +827,11 @@ func (h *Handlers) DeleteLocation(w http.ResponseWriter, r *http.Request) {
 	// LocationManagerを使用してロケーションを削除
 	if locationManager, ok := h.manager.(inventory.LocationManager); ok {
 		if err := locationManager.DeleteLocation(r.Context(), locationID); err != nil {
-			if err == inventory.ErrLocationNotFound {
-				h.sendError(w, http.StatusNotFound, "ロケーションが見つかりません")
-			} else {
-				h.sendError(w, http.StatusInternalServerError, err.Error())
-			}
+			h.sendLocationError(w, err)
 			return
 		}
 		h.sendSuccess(w, map[string]string{
-			"message": "ロケーションが削除されました",
+			"message": "ロケーションをアーカイブしました",
 		})
 	} else {
 		h.sendError(w, http.StatusNotImplemented, "ロケーション管理機能がサポートされていません")
@@ -602,27 +839,24 @@ func (h *Handlers) DeleteLocation(w http.ResponseWriter, r *http.Request) {
 }
 
 // ListLocations handles list locations requests
-// ロケーション一覧リクエストを処理
+// ロケーション一覧リクエストを処理（include_archived=trueでアーカイブ済みも含める）
 func (h *Handlers) ListLocations(w http.ResponseWriter, r *http.Request) {
 	// offsetとlimitのパラメータを取得
-	offset := 0
-	limit := 20 // デフォルト
+	offset, limit := parsePagination(r)
 
-	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
-		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
-			offset = parsedOffset
-		}
-	}
-
-	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
-		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 && parsedLimit <= 100 {
-			limit = parsedLimit
+	includeArchived := false
+	if value := r.URL.Query().Get("include_archived"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, "include_archivedはtrueまたはfalseで指定してください")
+			return
 		}
+		includeArchived = parsed
 	}
 
 	// LocationManagerを使用してロケーション一覧を取得
 	if locationManager, ok := h.manager.(inventory.LocationManager); ok {
-		locations, err := locationManager.ListLocations(r.Context(), offset, limit)
+		locations, err := locationManager.ListLocations(r.Context(), offset, limit, includeArchived)
 		if err != nil {
 			h.sendError(w, http.StatusInternalServerError, err.Error())
 			return
@@ -638,6 +872,114 @@ func (h *Handlers) ListLocations(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// sendLocationError maps location master errors to HTTP status codes
+// ロケーションマスタのエラーをHTTPステータスに変換して送信
+func (h *Handlers) sendLocationError(w http.ResponseWriter, err error) {
+	switch err.(type) {
+	case *inventory.ValidationError:
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	case *inventory.BusinessRuleError:
+		h.sendError(w, http.StatusConflict, err.Error())
+		return
+	}
+
+	switch err {
+	case inventory.ErrLocationNotFound:
+		h.sendError(w, http.StatusNotFound, "ロケーションが見つかりません")
+	case inventory.ErrDuplicateLocation, inventory.ErrLocationArchived:
+		h.sendError(w, http.StatusConflict, err.Error())
+	default:
+		h.sendError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// マスタのアーカイブハンドラー
+
+// GetItemUsage handles requests for the stock and open documents that block archiving an item
+// 商品のアーカイブを妨げる在庫・未完了の伝票の照会リクエストを処理
+func (h *Handlers) GetItemUsage(w http.ResponseWriter, r *http.Request) {
+	archiveManager, ok := h.manager.(inventory.MasterArchiveManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "マスタのアーカイブ機能がサポートされていません")
+		return
+	}
+
+	itemID := mux.Vars(r)["itemId"]
+	usage, err := archiveManager.GetItemUsage(r.Context(), itemID)
+	if err != nil {
+		h.sendItemError(w, err)
+		return
+	}
+	h.sendSuccess(w, map[string]interface{}{
+		"usage":     usage,
+		"blockers":  usage.Blockers(),
+		"deletable": len(usage.Blockers()) == 0,
+	})
+}
+
+// RestoreItem handles requests to restore an archived item
+// アーカイブ済み商品の復元リクエストを処理
+func (h *Handlers) RestoreItem(w http.ResponseWriter, r *http.Request) {
+	archiveManager, ok := h.manager.(inventory.MasterArchiveManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "マスタのアーカイブ機能がサポートされていません")
+		return
+	}
+
+	itemID := mux.Vars(r)["itemId"]
+	ctx := context.WithValue(r.Context(), "user_id", "api_user")
+	if err := archiveManager.RestoreItem(ctx, itemID); err != nil {
+		h.sendItemError(w, err)
+		return
+	}
+	h.sendSuccess(w, map[string]string{
+		"message": "商品のアーカイブを解除しました",
+	})
+}
+
+// GetLocationUsage handles requests for the stock and open documents that block archiving a location
+// ロケーションのアーカイブを妨げる在庫・未完了の伝票の照会リクエストを処理
+func (h *Handlers) GetLocationUsage(w http.ResponseWriter, r *http.Request) {
+	archiveManager, ok := h.manager.(inventory.MasterArchiveManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "マスタのアーカイブ機能がサポートされていません")
+		return
+	}
+
+	locationID := mux.Vars(r)["locationId"]
+	usage, err := archiveManager.GetLocationUsage(r.Context(), locationID)
+	if err != nil {
+		h.sendLocationError(w, err)
+		return
+	}
+	h.sendSuccess(w, map[string]interface{}{
+		"usage":     usage,
+		"blockers":  usage.Blockers(),
+		"deletable": len(usage.Blockers()) == 0,
+	})
+}
+
+// RestoreLocation handles requests to restore an archived location
+// アーカイブ済みロケーションの復元リクエストを処理
+func (h *Handlers) RestoreLocation(w http.ResponseWriter, r *http.Request) {
+	archiveManager, ok := h.manager.(inventory.MasterArchiveManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "マスタのアーカイブ機能がサポートされていません")
+		return
+	}
+
+	locationID := mux.Vars(r)["locationId"]
+	ctx := context.WithValue(r.Context(), "user_id", "api_user")
+	if err := archiveManager.RestoreLocation(ctx, locationID); err != nil {
+		h.sendLocationError(w, err)
+		return
+	}
+	h.sendSuccess(w, map[string]string{
+		"message": "ロケーションのアーカイブを解除しました",
+	})
+}
+
 // ロット管理ハンドラー
 
 // CreateLot handles create lot requests
@@ -771,10 +1113,10 @@ func (h *Handlers) GetExpiredLots(w http.ResponseWriter, r *http.Request) {
 // 在庫予約リクエストを処理
 func (h *Handlers) ReserveStock(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		ItemID     string `json:"item_id"`
-		LocationID string `json:"location_id"`
-		Quantity   int64  `json:"quantity"`
-		Reference  string `json:"reference"`
+		ItemID     string                  `json:"item_id"`
+		LocationID string                  `json:"location_id"`
+		Quantity   inventory.QuantityInput `json:"quantity"`
+		Reference  string                  `json:"reference"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -783,7 +1125,11 @@ func (h *Handlers) ReserveStock(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := context.WithValue(r.Context(), "user_id", "api_user")
-	if err := h.manager.Reserve(ctx, req.ItemID, req.LocationID, req.Quantity, req.Reference); err != nil {
+	quantity, ok := h.requestQuantity(w, ctx, req.ItemID, req.Quantity)
+	if !ok {
+		return
+	}
+	if err := h.manager.Reserve(ctx, req.ItemID, req.LocationID, quantity, req.Reference); err != nil {
 		h.sendError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -797,10 +1143,10 @@ func (h *Handlers) ReserveStock(w http.ResponseWriter, r *http.Request) {
 // 予約解除リクエストを処理
 func (h *Handlers) ReleaseReservation(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		ItemID     string `json:"item_id"`
-		LocationID string `json:"location_id"`
-		Quantity   int64  `json:"quantity"`
-		Reference  string `json:"reference"`
+		ItemID     string                  `json:"item_id"`
+		LocationID string                  `json:"location_id"`
+		Quantity   inventory.QuantityInput `json:"quantity"`
+		Reference  string                  `json:"reference"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -809,7 +1155,11 @@ func (h *Handlers) ReleaseReservation(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := context.WithValue(r.Context(), "user_id", "api_user")
-	if err := h.manager.ReleaseReservation(ctx, req.ItemID, req.LocationID, req.Quantity, req.Reference); err != nil {
+	quantity, ok := h.requestQuantity(w, ctx, req.ItemID, req.Quantity)
+	if !ok {
+		return
+	}
+	if err := h.manager.ReleaseReservation(ctx, req.ItemID, req.LocationID, quantity, req.Reference); err != nil {
 		h.sendError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -842,7 +1192,7 @@ func (h *Handlers) GetHistoryByLocation(w http.ResponseWriter, r *http.Request)
 	}
 
 	h.sendSuccess(w, map[string]interface{}{
-		"history":     history,
+		"history":     h.newQuantityFormatter(r.Context()).transactions(history),
 		"location_id": locationID,
 		"limit":       limit,
 		"count":       len(history),
@@ -886,7 +1236,7 @@ func (h *Handlers) GetHistoryByDateRange(w http.ResponseWriter, r *http.Request)
 	}
 
 	h.sendSuccess(w, map[string]interface{}{
-		"history": history,
+		"history": h.newQuantityFormatter(r.Context()).transactions(history),
 		"item_id": itemID,
 		"from":    fromStr,
 		"to":      toStr,
@@ -904,6 +1254,15 @@ func (h *Handlers) GetBatchStatus(w http.ResponseWriter, r *http.Request) {
 
 	batch, err := h.manager.GetBatchStatus(r.Context(), batchID)
 	if err != nil {
+		switch err.(type) {
+		case *inventory.ValidationError:
+			h.sendError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err == inventory.ErrBatchNotFound {
+			h.sendError(w, http.StatusNotFound, err.Error())
+			return
+		}
 		h.sendError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -935,12 +1294,14 @@ func (h *Handlers) CalculateValue(w http.ResponseWriter, r *http.Request) {
 			h.sendError(w, http.StatusInternalServerError, err.Error())
 			return
 		}
-		h.sendSuccess(w, map[string]interface{}{
+		response := map[string]interface{}{
 			"value":       value,
-			"item_id":    itemID,
+			"item_id":     itemID,
 			"location_id": locationID,
 			"method":      method,
-		})
+		}
+		h.addRoundedValue(response, "rounded_value", value)
+		h.sendSuccess(w, response)
 	} else {
 		h.sendError(w, http.StatusNotImplemented, "在庫評価機能がサポートされていません")
 	}
@@ -967,11 +1328,13 @@ func (h *Handlers) CalculateTotalValue(w http.ResponseWriter, r *http.Request) {
 			h.sendError(w, http.StatusInternalServerError, err.Error())
 			return
 		}
-		h.sendSuccess(w, map[string]interface{}{
+		response := map[string]interface{}{
 			"total_value": totalValue,
 			"location_id": locationID,
 			"method":      method,
-		})
+		}
+		h.addRoundedValue(response, "rounded_total_value", totalValue)
+		h.sendSuccess(w, response)
 	} else {
 		h.sendError(w, http.StatusNotImplemented, "在庫評価機能がサポートされていません")
 	}
@@ -999,6 +1362,20 @@ func (h *Handlers) GetAverageCost(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// addRoundedValue adds the configured currency and the value rounded to its minor unit
+// 設定された通貨と、その最小単位に丸めた評価額をレスポンスに追加
+//
+// 評価額そのものは小数4桁の正確な値のまま返し、丸めは表示用の値にのみ適用します。
+func (h *Handlers) addRoundedValue(response map[string]interface{}, key string, value inventory.Money) {
+	provider, ok := h.manager.(inventory.CurrencyProvider)
+	if !ok {
+		return
+	}
+	currency := provider.Currency()
+	response["currency"] = currency.Code
+	response[key] = value.Round(currency)
+}
+
 // 在庫分析エンジンハンドラー
 
 // CalculateABCClassification handles ABC classification requests
@@ -1024,48 +1401,238 @@ func (h *Handlers) CalculateABCClassification(w http.ResponseWriter, r *http.Req
 	}
 }
 
-// GetTurnoverRate handles turnover rate requests
-// 回転率取得リクエストを処理
-func (h *Handlers) GetTurnoverRate(w http.ResponseWriter, r *http.Request) {
+// ClassifyItems handles ABC/XYZ classification runs that are stored as history
+// ABC/XYZ分類の実行リクエストを処理（結果は履歴として保存）
+func (h *Handlers) ClassifyItems(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
-	itemID := vars["itemId"]
+	locationID := vars["locationId"]
 
-	// 期間パラメータを取得（日数）
-	periodDays := 30 // デフォルト30日
-	if periodStr := r.URL.Query().Get("period_days"); periodStr != "" {
-		if parsedDays, err := strconv.Atoi(periodStr); err == nil && parsedDays > 0 {
-			periodDays = parsedDays
+	if classifier, ok := h.manager.(inventory.ItemClassifier); ok {
+		classifications, err := classifier.ClassifyItems(r.Context(), locationID)
+		if err != nil {
+			h.sendError(w, http.StatusInternalServerError, err.Error())
+			return
 		}
+		h.sendSuccess(w, map[string]interface{}{
+			"classifications": classifications,
+			"location_id":     locationID,
+			"count":           len(classifications),
+		})
+	} else {
+		h.sendError(w, http.StatusNotImplemented, "商品分類機能がサポートされていません")
 	}
+}
 
-	period := time.Duration(periodDays) * 24 * time.Hour
+// GetItemClassifications handles requests for the latest stored classification of a location
+// ロケーションの最新の分類結果取得リクエストを処理
+func (h *Handlers) GetItemClassifications(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	locationID := vars["locationId"]
 
-	// AnalyticsEngineを使用して回転率を取得
-	if analyticsEngine, ok := h.manager.(inventory.AnalyticsEngine); ok {
-		turnoverRate, err := analyticsEngine.GetTurnoverRate(r.Context(), itemID, period)
+	if classifier, ok := h.manager.(inventory.ItemClassifier); ok {
+		classifications, err := classifier.GetItemClassifications(r.Context(), locationID)
 		if err != nil {
 			h.sendError(w, http.StatusInternalServerError, err.Error())
 			return
 		}
 		h.sendSuccess(w, map[string]interface{}{
-			"turnover_rate": turnoverRate,
-			"item_id":       itemID,
-			"period_days":   periodDays,
+			"classifications": classifications,
+			"location_id":     locationID,
+			"count":           len(classifications),
 		})
 	} else {
-		h.sendError(w, http.StatusNotImplemented, "在庫分析機能がサポートされていません")
+		h.sendError(w, http.StatusNotImplemented, "商品分類機能がサポートされていません")
 	}
 }
 
-// GetSlowMovingItems handles slow moving items requests
-// 低回転商品取得リクエストを処理
-func (h *Handlers) GetSlowMovingItems(w http.ResponseWriter, r *http.Request) {
+// GetItemClassificationHistory handles classification history requests for an item
+// 商品の分類履歴取得リクエストを処理
+func (h *Handlers) GetItemClassificationHistory(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	locationID := vars["locationId"]
+	itemID := vars["itemId"]
 
-	// 闾値パラメータを取得（日数）
-	thresholdDays := 90 // デフォルト90日
-	if thresholdStr := r.URL.Query().Get("threshold_days"); thresholdStr != "" {
+	limit := 50
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+
+	if classifier, ok := h.manager.(inventory.ItemClassifier); ok {
+		history, err := classifier.GetItemClassificationHistory(r.Context(), itemID, locationID, limit)
+		if err != nil {
+			h.sendError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		h.sendSuccess(w, map[string]interface{}{
+			"history":     history,
+			"item_id":     itemID,
+			"location_id": locationID,
+			"count":       len(history),
+		})
+	} else {
+		h.sendError(w, http.StatusNotImplemented, "商品分類機能がサポートされていません")
+	}
+}
+
+// GetTurnoverRate handles turnover rate requests
+// 回転率取得リクエストを処理
+func (h *Handlers) GetTurnoverRate(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	itemID := vars["itemId"]
+
+	// 期間パラメータを取得（日数）
+	periodDays := 30 // デフォルト30日
+	if periodStr := r.URL.Query().Get("period_days"); periodStr != "" {
+		if parsedDays, err := strconv.Atoi(periodStr); err == nil && parsedDays > 0 {
+			periodDays = parsedDays
+		}
+	}
+
+	period := time.Duration(periodDays) * 24 * time.Hour
+
+	// AnalyticsEngineを使用して回転率を取得
+	if analyticsEngine, ok := h.manager.(inventory.AnalyticsEngine); ok {
+		turnoverRate, err := analyticsEngine.GetTurnoverRate(r.Context(), itemID, period)
+		if err != nil {
+			h.sendError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		h.sendSuccess(w, map[string]interface{}{
+			"turnover_rate": turnoverRate,
+			"item_id":       itemID,
+			"period_days":   periodDays,
+		})
+	} else {
+		h.sendError(w, http.StatusNotImplemented, "在庫分析機能がサポートされていません")
+	}
+}
+
+// GetKPIs handles inventory KPI requests (turnover, days of supply, stockout days, fill rate)
+// 在庫KPI（回転率・在庫日数・欠品日数・充足率）取得リクエストを処理
+func (h *Handlers) GetKPIs(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	itemID := query.Get("item_id")
+	locationID := query.Get("location_id")
+
+	// 集計終了日時（日付のみの場合はその日の終わり、デフォルトは現在）
+	to := time.Now()
+	if toStr := query.Get("to"); toStr != "" {
+		parsed, err := parseAsOf(toStr)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, "無効なto形式です（形式：2006-01-02 または RFC3339）")
+			return
+		}
+		to = parsed
+	}
+
+	// 集計開始日時（デフォルトはperiod_days日前、period_daysのデフォルトは30日）
+	periodDays := 30
+	if periodStr := query.Get("period_days"); periodStr != "" {
+		if parsedDays, err := strconv.Atoi(periodStr); err == nil && parsedDays > 0 {
+			periodDays = parsedDays
+		}
+	}
+	from := to.AddDate(0, 0, -periodDays)
+	if fromStr := query.Get("from"); fromStr != "" {
+		parsed, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			if parsed, err = time.Parse("2006-01-02", fromStr); err != nil {
+				h.sendError(w, http.StatusBadRequest, "無効なfrom形式です（形式：2006-01-02 または RFC3339）")
+				return
+			}
+		}
+		from = parsed
+	}
+
+	if kpiReporter, ok := h.manager.(inventory.KPIReporter); ok {
+		report, err := kpiReporter.CalculateKPIs(r.Context(), itemID, locationID, from, to)
+		if err != nil {
+			if _, isValidation := err.(*inventory.ValidationError); isValidation {
+				h.sendError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			h.sendError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		h.sendSuccess(w, report)
+	} else {
+		h.sendError(w, http.StatusNotImplemented, "在庫KPI機能がサポートされていません")
+	}
+}
+
+// ForecastDemand handles demand forecast requests
+// 需要予測リクエストを処理
+func (h *Handlers) ForecastDemand(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	itemID := vars["itemId"]
+	locationID := vars["locationId"]
+
+	// 予測手法（デフォルトは単純指数平滑）と予測日数（デフォルト28日）
+	method := inventory.ForecastMethodExponentialSmoothing
+	if methodStr := r.URL.Query().Get("method"); methodStr != "" {
+		method = inventory.ForecastMethod(methodStr)
+	}
+	horizonDays := 28
+	if horizonStr := r.URL.Query().Get("horizon_days"); horizonStr != "" {
+		if parsedDays, err := strconv.Atoi(horizonStr); err == nil && parsedDays > 0 {
+			horizonDays = parsedDays
+		}
+	}
+
+	if forecaster, ok := h.manager.(inventory.DemandForecaster); ok {
+		forecast, err := forecaster.ForecastDemand(r.Context(), itemID, locationID, method, horizonDays)
+		if err != nil {
+			if _, isValidation := err.(*inventory.ValidationError); isValidation {
+				h.sendError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			h.sendError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		h.sendSuccess(w, forecast)
+	} else {
+		h.sendError(w, http.StatusNotImplemented, "需要予測機能がサポートされていません")
+	}
+}
+
+// GetReorderSuggestions handles reorder suggestion report requests
+// 発注提案レポートリクエストを処理
+func (h *Handlers) GetReorderSuggestions(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	locationID := vars["locationId"]
+
+	method := inventory.ForecastMethodExponentialSmoothing
+	if methodStr := r.URL.Query().Get("method"); methodStr != "" {
+		method = inventory.ForecastMethod(methodStr)
+	}
+
+	if forecaster, ok := h.manager.(inventory.DemandForecaster); ok {
+		report, err := forecaster.GetReorderSuggestions(r.Context(), locationID, method)
+		if err != nil {
+			if _, isValidation := err.(*inventory.ValidationError); isValidation {
+				h.sendError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			h.sendError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		h.sendSuccess(w, report)
+	} else {
+		h.sendError(w, http.StatusNotImplemented, "需要予測機能がサポートされていません")
+	}
+}
+
+// GetSlowMovingItems handles slow moving items requests
+// 低回転商品取得リクエストを処理
+func (h *Handlers) GetSlowMovingItems(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	locationID := vars["locationId"]
+
+	// 闾値パラメータを取得（日数）
+	thresholdDays := 90 // デフォルト90日
+	if thresholdStr := r.URL.Query().Get("threshold_days"); thresholdStr != "" {
 		if parsedDays, err := strconv.Atoi(thresholdStr); err == nil && parsedDays > 0 {
 			thresholdDays = parsedDays
 		}
@@ -1091,39 +1658,2912 @@ func (h *Handlers) GetSlowMovingItems(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// GetAgingReport handles inventory aging and dead-stock report requests
+// 在庫エイジング・滞留在庫レポート取得リクエストを処理
+func (h *Handlers) GetAgingReport(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	// "all" は全ロケーションを対象とする
+	locationID := vars["locationId"]
+	if locationID == "all" {
+		locationID = ""
+	}
+
+	// 滞留在庫とみなす移動なし日数（省略時は設定値）
+	deadStockDays := 0
+	if daysStr := r.URL.Query().Get("dead_stock_days"); daysStr != "" {
+		parsedDays, err := strconv.Atoi(daysStr)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, "無効なdead_stock_daysです")
+			return
+		}
+		deadStockDays = parsedDays
+	}
+
+	analyzer, ok := h.manager.(inventory.AgingAnalyzer)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "在庫エイジング機能がサポートされていません")
+		return
+	}
+
+	report, err := analyzer.GetAgingReport(r.Context(), locationID, deadStockDays)
+	if err != nil {
+		if _, isValidation := err.(*inventory.ValidationError); isValidation {
+			h.sendError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.sendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.sendSuccess(w, report)
+}
+
 // GenerateStockReport handles stock report generation requests
 // 在庫レポート生成リクエストを処理
 func (h *Handlers) GenerateStockReport(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	locationID := vars["locationId"]
+	query := r.URL.Query()
 
 	// レポートタイプを取得
-	reportTypeStr := r.URL.Query().Get("type")
+	reportTypeStr := query.Get("type")
 	if reportTypeStr == "" {
 		reportTypeStr = string(inventory.ReportTypeStock) // デフォルト
 	}
 
 	reportType := inventory.ReportType(reportTypeStr)
 
-	// AnalyticsEngineを使用してレポートを生成
-	if analyticsEngine, ok := h.manager.(inventory.AnalyticsEngine); ok {
-		reportData, err := analyticsEngine.GenerateStockReport(r.Context(), locationID, reportType)
+	// 出力形式（デフォルトはCSV）
+	format := inventory.ReportFormatCSV
+	if formatStr := query.Get("format"); formatStr != "" {
+		format = inventory.ReportFormat(formatStr)
+	}
+	contentType, ok := reportContentTypes[format]
+	if !ok {
+		h.sendError(w, http.StatusBadRequest, "無効なformatです（csv / json / xlsx）")
+		return
+	}
+
+	// as_of指定時は時点在庫レポートを生成（在庫レポートのCSVのみ対応）
+	if asOfStr := query.Get("as_of"); asOfStr != "" {
+		if reportType != inventory.ReportTypeStock || format != inventory.ReportFormatCSV {
+			h.sendError(w, http.StatusBadRequest, "as_ofはCSV形式の在庫レポートでのみ指定できます")
+			return
+		}
+		asOf, err := parseAsOf(asOfStr)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, "無効なas_of形式です（形式：2006-01-02 または RFC3339）")
+			return
+		}
+
+		analyticsEngine, ok := h.manager.(inventory.AnalyticsEngine)
+		if !ok {
+			h.sendError(w, http.StatusNotImplemented, "在庫分析機能がサポートされていません")
+			return
+		}
+		reportData, err := analyticsEngine.GenerateStockReportAsOf(r.Context(), locationID, asOf)
 		if err != nil {
 			h.sendError(w, http.StatusInternalServerError, err.Error())
 			return
 		}
 
-		// レポートをバイナリデータとして返す
-		w.Header().Set("Content-Type", "application/octet-stream")
-		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=stock_report_%s_%s.pdf", locationID, reportType))
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=stock_report_%s_%s.%s", locationID, reportType, format))
 		w.WriteHeader(http.StatusOK)
 		w.Write(reportData)
-	} else {
-		h.sendError(w, http.StatusNotImplemented, "在庫分析機能がサポートされていません")
+		return
+	}
+
+	// 集計期間（デフォルトはtoから過去period_days日間、period_daysのデフォルトは30日）
+	to := time.Now()
+	if toStr := query.Get("to"); toStr != "" {
+		parsed, err := parseAsOf(toStr)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, "無効なto形式です（形式：2006-01-02 または RFC3339）")
+			return
+		}
+		to = parsed
+	}
+	periodDays := 30
+	if periodStr := query.Get("period_days"); periodStr != "" {
+		if parsedDays, err := strconv.Atoi(periodStr); err == nil && parsedDays > 0 {
+			periodDays = parsedDays
+		}
+	}
+	from := to.AddDate(0, 0, -periodDays)
+	if fromStr := query.Get("from"); fromStr != "" {
+		parsed, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			if parsed, err = time.Parse("2006-01-02", fromStr); err != nil {
+				h.sendError(w, http.StatusBadRequest, "無効なfrom形式です（形式：2006-01-02 または RFC3339）")
+				return
+			}
+		}
+		from = parsed
+	}
+
+	// "all" は全ロケーションを対象とする
+	filterLocationID := locationID
+	if filterLocationID == "all" {
+		filterLocationID = ""
+	}
+
+	reportWriter, ok := h.manager.(inventory.ReportWriter)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "レポート出力機能がサポートされていません")
+		return
+	}
+
+	request := inventory.ReportRequest{
+		Type:   reportType,
+		Format: format,
+		Filter: inventory.ReportFilter{
+			LocationID: filterLocationID,
+			Category:   query.Get("category"),
+			From:       from,
+			To:         to,
+		},
+	}
+
+	// 最初の書き込みまでヘッダーを確定させず、出力前のエラーはJSONで返す
+	out := &reportResponseWriter{
+		w:           w,
+		contentType: contentType,
+		filename:    fmt.Sprintf("stock_report_%s_%s.%s", locationID, reportType, format),
+	}
+	if err := reportWriter.WriteReport(r.Context(), out, request); err != nil {
+		if out.started {
+			h.logger.Error("レポート出力に失敗しました", zap.Error(err))
+			return
+		}
+		if _, isValidation := err.(*inventory.ValidationError); isValidation {
+			h.sendError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.sendError(w, http.StatusInternalServerError, err.Error())
 	}
 }
 
-// ヘルパーメソッド
+// reportContentTypes maps report formats to response content types
+// レポート出力形式とContent-Typeの対応
+var reportContentTypes = map[inventory.ReportFormat]string{
+	inventory.ReportFormatCSV:  "text/csv; charset=utf-8",
+	inventory.ReportFormatJSON: "application/json; charset=utf-8",
+	inventory.ReportFormatXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// reportResponseWriter defers the response headers until the report writes its first byte
+// レポートの最初の書き込みまでレスポンスヘッダーの送信を遅延
+type reportResponseWriter struct {
+	w           http.ResponseWriter
+	contentType string
+	filename    string
+	started     bool
+}
+
+func (rw *reportResponseWriter) Write(p []byte) (int, error) {
+	if !rw.started {
+		rw.started = true
+		rw.w.Header().Set("Content-Type", rw.contentType)
+		rw.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", rw.filename))
+		rw.w.WriteHeader(http.StatusOK)
+	}
+	return rw.w.Write(p)
+}
+
+// 移動指示ハンドラー
+
+// CreateTransferOrderRequest represents request to create a transfer order
+// 移動指示作成リクエストを表現
+type CreateTransferOrderRequest struct {
+	inventory.TransferOrder
+	Quantity inventory.QuantityInput `json:"quantity"`
+}
+
+// ReceiveTransferOrderRequest represents request to receive a transfer order
+// 移動指示の入荷リクエストを表現
+type ReceiveTransferOrderRequest struct {
+	Quantity inventory.QuantityInput `json:"quantity"`
+}
+
+// TransferDiscrepancyRequest represents request to record a transfer discrepancy
+// 移動指示の差異計上リクエストを表現
+type TransferDiscrepancyRequest struct {
+	Reason string `json:"reason"`
+}
+
+// CreateTransferOrder handles create transfer order requests
+// 移動指示作成リクエストを処理
+func (h *Handlers) CreateTransferOrder(w http.ResponseWriter, r *http.Request) {
+	var req CreateTransferOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
+		return
+	}
+
+	transferManager, ok := h.manager.(inventory.TransferOrderManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "移動指示機能がサポートされていません")
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), "user_id", "api_user")
+	quantity, ok := h.requestQuantity(w, ctx, req.ItemID, req.Quantity)
+	if !ok {
+		return
+	}
+
+	order := req.TransferOrder
+	order.Quantity = quantity
+	if err := transferManager.CreateTransferOrder(ctx, &order); err != nil {
+		h.sendTransferOrderError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message":        "移動指示が作成されました",
+		"transfer_order": h.newQuantityFormatter(ctx).transferOrder(&order),
+	})
+}
+
+// GetTransferOrder handles get transfer order requests
+// 移動指示取得リクエストを処理
+func (h *Handlers) GetTransferOrder(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	orderID := vars["orderId"]
+
+	transferManager, ok := h.manager.(inventory.TransferOrderManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "移動指示機能がサポートされていません")
+		return
+	}
+
+	order, err := transferManager.GetTransferOrder(r.Context(), orderID)
+	if err != nil {
+		h.sendTransferOrderError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, h.newQuantityFormatter(r.Context()).transferOrder(order))
+}
+
+// ListTransferOrders handles list transfer order requests (filterable by status)
+// 移動指示一覧リクエストを処理（ステータスで絞り込み可能）
+func (h *Handlers) ListTransferOrders(w http.ResponseWriter, r *http.Request) {
+	offset, limit := parsePagination(r)
+	status := inventory.TransferOrderStatus(r.URL.Query().Get("status"))
+
+	transferManager, ok := h.manager.(inventory.TransferOrderManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "移動指示機能がサポートされていません")
+		return
+	}
+
+	orders, err := transferManager.ListTransferOrders(r.Context(), status, offset, limit)
+	if err != nil {
+		h.sendTransferOrderError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"transfer_orders": h.newQuantityFormatter(r.Context()).transferOrders(orders),
+		"offset":          offset,
+		"limit":           limit,
+		"count":           len(orders),
+	})
+}
+
+// ShipTransferOrder handles ship transfer order requests
+// 移動指示出荷リクエストを処理
+func (h *Handlers) ShipTransferOrder(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	orderID := vars["orderId"]
+
+	transferManager, ok := h.manager.(inventory.TransferOrderManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "移動指示機能がサポートされていません")
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), "user_id", "api_user")
+	order, err := transferManager.ShipTransferOrder(ctx, orderID)
+	if err != nil {
+		h.sendTransferOrderError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, h.newQuantityFormatter(r.Context()).transferOrder(order))
+}
+
+// ReceiveTransferOrder handles full or partial receive requests
+// 移動指示の入荷（全量・一部）リクエストを処理
+func (h *Handlers) ReceiveTransferOrder(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	orderID := vars["orderId"]
+
+	var req ReceiveTransferOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
+		return
+	}
+
+	transferManager, ok := h.manager.(inventory.TransferOrderManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "移動指示機能がサポートされていません")
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), "user_id", "api_user")
+	// 数量は移動指示の商品の小数桁数で解釈
+	current, err := transferManager.GetTransferOrder(ctx, orderID)
+	if err != nil {
+		h.sendTransferOrderError(w, err)
+		return
+	}
+	quantity, ok := h.requestQuantity(w, ctx, current.ItemID, req.Quantity)
+	if !ok {
+		return
+	}
+	order, err := transferManager.ReceiveTransferOrder(ctx, orderID, quantity)
+	if err != nil {
+		h.sendTransferOrderError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, h.newQuantityFormatter(r.Context()).transferOrder(order))
+}
+
+// RecordTransferDiscrepancy handles discrepancy recording requests
+// 移動指示の差異計上リクエストを処理
+func (h *Handlers) RecordTransferDiscrepancy(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	orderID := vars["orderId"]
+
+	var req TransferDiscrepancyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
+		return
+	}
+
+	transferManager, ok := h.manager.(inventory.TransferOrderManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "移動指示機能がサポートされていません")
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), "user_id", "api_user")
+	order, err := transferManager.RecordTransferDiscrepancy(ctx, orderID, req.Reason)
+	if err != nil {
+		h.sendTransferOrderError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, h.newQuantityFormatter(r.Context()).transferOrder(order))
+}
+
+// sendTransferOrderError maps transfer order errors to HTTP status codes
+// 移動指示のエラーをHTTPステータスに変換して送信
+func (h *Handlers) sendTransferOrderError(w http.ResponseWriter, err error) {
+	switch err.(type) {
+	case *inventory.ValidationError:
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	switch err {
+	case inventory.ErrTransferOrderNotFound:
+		h.sendError(w, http.StatusNotFound, err.Error())
+	case inventory.ErrInvalidTransferOrderStatus, inventory.ErrInsufficientStock, inventory.ErrItemArchived, inventory.ErrLocationArchived:
+		h.sendError(w, http.StatusConflict, err.Error())
+	default:
+		h.sendError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// 仕入先・発注ハンドラー
+
+// ReceivePurchaseOrderRequest represents request to receive goods against a purchase order
+// 発注の入荷リクエストを表現
+type ReceivePurchaseOrderRequest struct {
+	LocationID string                   `json:"location_id"`
+	Receipts   []PurchaseReceiptRequest `json:"receipts"`
+}
+
+// PurchaseReceiptRequest represents a received quantity against a purchase order line
+// 発注明細に対する入荷数量を表現
+type PurchaseReceiptRequest struct {
+	inventory.PurchaseReceipt
+	Quantity inventory.QuantityInput `json:"quantity"`
+}
+
+// CreatePurchaseOrderRequest represents request to create a purchase order
+// 発注作成リクエストを表現
+type CreatePurchaseOrderRequest struct {
+	inventory.PurchaseOrder
+	Lines []PurchaseOrderLineRequest `json:"lines"`
+}
+
+// PurchaseOrderLineRequest represents one line of a purchase order request
+// 発注作成リクエストの明細を表現
+type PurchaseOrderLineRequest struct {
+	inventory.PurchaseOrderLine
+	Quantity inventory.QuantityInput `json:"quantity"`
+}
+
+// CreateSupplier handles create supplier requests
+// 仕入先作成リクエストを処理
+func (h *Handlers) CreateSupplier(w http.ResponseWriter, r *http.Request) {
+	var supplier inventory.Supplier
+	if err := json.NewDecoder(r.Body).Decode(&supplier); err != nil {
+		h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
+		return
+	}
+
+	supplierManager, ok := h.manager.(inventory.SupplierManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "仕入先管理機能がサポートされていません")
+		return
+	}
+
+	if err := supplierManager.CreateSupplier(r.Context(), &supplier); err != nil {
+		h.sendPurchaseOrderError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message":  "仕入先が作成されました",
+		"supplier": supplier,
+	})
+}
+
+// GetSupplier handles get supplier requests
+// 仕入先取得リクエストを処理
+func (h *Handlers) GetSupplier(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	supplierID := vars["supplierId"]
+
+	supplierManager, ok := h.manager.(inventory.SupplierManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "仕入先管理機能がサポートされていません")
+		return
+	}
+
+	supplier, err := supplierManager.GetSupplier(r.Context(), supplierID)
+	if err != nil {
+		h.sendPurchaseOrderError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, supplier)
+}
+
+// UpdateSupplier handles update supplier requests
+// 仕入先更新リクエストを処理
+func (h *Handlers) UpdateSupplier(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	supplierID := vars["supplierId"]
+
+	var supplier inventory.Supplier
+	if err := json.NewDecoder(r.Body).Decode(&supplier); err != nil {
+		h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
+		return
+	}
+	supplier.ID = supplierID
+
+	supplierManager, ok := h.manager.(inventory.SupplierManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "仕入先管理機能がサポートされていません")
+		return
+	}
+
+	if err := supplierManager.UpdateSupplier(r.Context(), &supplier); err != nil {
+		h.sendPurchaseOrderError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message":  "仕入先が更新されました",
+		"supplier": supplier,
+	})
+}
+
+// ListSuppliers handles list supplier requests
+// 仕入先一覧リクエストを処理
+func (h *Handlers) ListSuppliers(w http.ResponseWriter, r *http.Request) {
+	offset, limit := parsePagination(r)
+
+	supplierManager, ok := h.manager.(inventory.SupplierManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "仕入先管理機能がサポートされていません")
+		return
+	}
+
+	suppliers, err := supplierManager.ListSuppliers(r.Context(), offset, limit)
+	if err != nil {
+		h.sendPurchaseOrderError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"suppliers": suppliers,
+		"offset":    offset,
+		"limit":     limit,
+		"count":     len(suppliers),
+	})
+}
+
+// CreatePurchaseOrder handles create purchase order requests
+// 発注作成リクエストを処理
+func (h *Handlers) CreatePurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	var req CreatePurchaseOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
+		return
+	}
+
+	purchaseManager, ok := h.manager.(inventory.PurchaseOrderManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "発注機能がサポートされていません")
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), "user_id", "api_user")
+	order := req.PurchaseOrder
+	order.Lines = make([]inventory.PurchaseOrderLine, len(req.Lines))
+	for i, line := range req.Lines {
+		order.Lines[i] = line.PurchaseOrderLine
+		if order.Lines[i].Quantity, ok = h.requestQuantity(w, ctx, line.ItemID, line.Quantity); !ok {
+			return
+		}
+	}
+	if err := purchaseManager.CreatePurchaseOrder(ctx, &order); err != nil {
+		h.sendPurchaseOrderError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message":        "発注が作成されました",
+		"purchase_order": h.newQuantityFormatter(ctx).purchaseOrder(&order),
+	})
+}
+
+// GetPurchaseOrder handles get purchase order requests
+// 発注取得リクエストを処理
+func (h *Handlers) GetPurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	orderID := vars["orderId"]
+
+	purchaseManager, ok := h.manager.(inventory.PurchaseOrderManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "発注機能がサポートされていません")
+		return
+	}
+
+	order, err := purchaseManager.GetPurchaseOrder(r.Context(), orderID)
+	if err != nil {
+		h.sendPurchaseOrderError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, h.newQuantityFormatter(r.Context()).purchaseOrder(order))
+}
+
+// ListPurchaseOrders handles list purchase order requests (filterable by status and supplier)
+// 発注一覧リクエストを処理（ステータス・仕入先で絞り込み可能）
+func (h *Handlers) ListPurchaseOrders(w http.ResponseWriter, r *http.Request) {
+	offset, limit := parsePagination(r)
+	status := inventory.PurchaseOrderStatus(r.URL.Query().Get("status"))
+	supplierID := r.URL.Query().Get("supplier_id")
+
+	purchaseManager, ok := h.manager.(inventory.PurchaseOrderManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "発注機能がサポートされていません")
+		return
+	}
+
+	orders, err := purchaseManager.ListPurchaseOrders(r.Context(), status, supplierID, offset, limit)
+	if err != nil {
+		h.sendPurchaseOrderError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"purchase_orders": h.newQuantityFormatter(r.Context()).purchaseOrders(orders),
+		"offset":          offset,
+		"limit":           limit,
+		"count":           len(orders),
+	})
+}
+
+// ReceivePurchaseOrder handles goods receipt requests against purchase order lines
+// 発注明細に対する入荷リクエストを処理
+func (h *Handlers) ReceivePurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	orderID := vars["orderId"]
+
+	var req ReceivePurchaseOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
+		return
+	}
+
+	purchaseManager, ok := h.manager.(inventory.PurchaseOrderManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "発注機能がサポートされていません")
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), "user_id", "api_user")
+
+	// 入荷数量は明細の商品の小数桁数で解釈
+	current, err := purchaseManager.GetPurchaseOrder(ctx, orderID)
+	if err != nil {
+		h.sendPurchaseOrderError(w, err)
+		return
+	}
+	lineItems := make(map[string]string, len(current.Lines))
+	for _, line := range current.Lines {
+		lineItems[line.ID] = line.ItemID
+	}
+	receipts := make([]inventory.PurchaseReceipt, len(req.Receipts))
+	for i, receipt := range req.Receipts {
+		receipts[i] = receipt.PurchaseReceipt
+		if receipts[i].Quantity, ok = h.requestQuantity(w, ctx, lineItems[receipt.LineID], receipt.Quantity); !ok {
+			return
+		}
+	}
+
+	order, err := purchaseManager.ReceivePurchaseOrder(ctx, orderID, req.LocationID, receipts)
+	if err != nil {
+		h.sendPurchaseOrderError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, h.newQuantityFormatter(r.Context()).purchaseOrder(order))
+}
+
+// ClosePurchaseOrder handles close purchase order requests
+// 発注打ち切りリクエストを処理
+func (h *Handlers) ClosePurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	orderID := vars["orderId"]
+
+	purchaseManager, ok := h.manager.(inventory.PurchaseOrderManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "発注機能がサポートされていません")
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), "user_id", "api_user")
+	order, err := purchaseManager.ClosePurchaseOrder(ctx, orderID)
+	if err != nil {
+		h.sendPurchaseOrderError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, h.newQuantityFormatter(r.Context()).purchaseOrder(order))
+}
+
+// GetOnOrderQuantity handles on-order quantity requests for an item
+// 商品の発注残数量リクエストを処理
+func (h *Handlers) GetOnOrderQuantity(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	itemID := vars["itemId"]
+
+	purchaseManager, ok := h.manager.(inventory.PurchaseOrderManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "発注機能がサポートされていません")
+		return
+	}
+
+	onOrder, err := purchaseManager.GetOnOrderQuantity(r.Context(), itemID)
+	if err != nil {
+		h.sendPurchaseOrderError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"item_id":  itemID,
+		"on_order": h.newQuantityFormatter(r.Context()).quantity(itemID, onOrder),
+	})
+}
+
+// sendPurchaseOrderError maps supplier and purchase order errors to HTTP status codes
+// 仕入先・発注のエラーをHTTPステータスに変換して送信
+func (h *Handlers) sendPurchaseOrderError(w http.ResponseWriter, err error) {
+	switch err.(type) {
+	case *inventory.ValidationError:
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	switch err {
+	case inventory.ErrSupplierNotFound, inventory.ErrPurchaseOrderNotFound, inventory.ErrItemNotFound, inventory.ErrLocationNotFound:
+		h.sendError(w, http.StatusNotFound, err.Error())
+	case inventory.ErrPurchaseOrderNotOpen, inventory.ErrVersionMismatch, inventory.ErrExchangeRateNotFound, inventory.ErrItemArchived, inventory.ErrLocationArchived:
+		h.sendError(w, http.StatusConflict, err.Error())
+	default:
+		h.sendError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// 出荷指示・ウェーブハンドラー
+
+// ConfirmPicksRequest represents request to confirm picked quantities
+// ピッキング確定リクエストを表現
+type ConfirmPicksRequest struct {
+	Confirmations []PickConfirmationRequest `json:"confirmations"`
+}
+
+// PickConfirmationRequest represents the quantity actually picked for an allocation
+// 引当に対する実ピッキング数量を表現
+type PickConfirmationRequest struct {
+	inventory.PickConfirmation
+	PickedQuantity inventory.QuantityInput `json:"picked_quantity"`
+}
+
+// CreateOutboundOrderRequest represents request to create an outbound order
+// 出荷指示作成リクエストを表現
+type CreateOutboundOrderRequest struct {
+	inventory.OutboundOrder
+	Lines []OutboundOrderLineRequest `json:"lines"`
+}
+
+// OutboundOrderLineRequest represents one line of an outbound order request
+// 出荷指示作成リクエストの明細を表現
+type OutboundOrderLineRequest struct {
+	inventory.OutboundOrderLine
+	Quantity inventory.QuantityInput `json:"quantity"`
+}
+
+// CreateOutboundOrder handles create outbound order requests
+// 出荷指示作成リクエストを処理
+func (h *Handlers) CreateOutboundOrder(w http.ResponseWriter, r *http.Request) {
+	var req CreateOutboundOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
+		return
+	}
+
+	outboundManager, ok := h.manager.(inventory.OutboundOrderManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "出荷指示機能がサポートされていません")
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), "user_id", "api_user")
+	order := req.OutboundOrder
+	order.Lines = make([]inventory.OutboundOrderLine, len(req.Lines))
+	for i, line := range req.Lines {
+		order.Lines[i] = line.OutboundOrderLine
+		if order.Lines[i].Quantity, ok = h.requestQuantity(w, ctx, line.ItemID, line.Quantity); !ok {
+			return
+		}
+	}
+	if err := outboundManager.CreateOutboundOrder(ctx, &order); err != nil {
+		h.sendOutboundOrderError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message":        "出荷指示が作成されました",
+		"outbound_order": h.newQuantityFormatter(ctx).outboundOrder(&order),
+	})
+}
+
+// GetOutboundOrder handles get outbound order requests
+// 出荷指示取得リクエストを処理
+func (h *Handlers) GetOutboundOrder(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	orderID := vars["orderId"]
+
+	outboundManager, ok := h.manager.(inventory.OutboundOrderManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "出荷指示機能がサポートされていません")
+		return
+	}
+
+	order, err := outboundManager.GetOutboundOrder(r.Context(), orderID)
+	if err != nil {
+		h.sendOutboundOrderError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, h.newQuantityFormatter(r.Context()).outboundOrder(order))
+}
+
+// ListOutboundOrders handles list outbound order requests (filterable by status)
+// 出荷指示一覧リクエストを処理（ステータスで絞り込み可能）
+func (h *Handlers) ListOutboundOrders(w http.ResponseWriter, r *http.Request) {
+	offset, limit := parsePagination(r)
+	status := inventory.OutboundOrderStatus(r.URL.Query().Get("status"))
+
+	outboundManager, ok := h.manager.(inventory.OutboundOrderManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "出荷指示機能がサポートされていません")
+		return
+	}
+
+	orders, err := outboundManager.ListOutboundOrders(r.Context(), status, offset, limit)
+	if err != nil {
+		h.sendOutboundOrderError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"outbound_orders": h.newQuantityFormatter(r.Context()).outboundOrders(orders),
+		"offset":          offset,
+		"limit":           limit,
+		"count":           len(orders),
+	})
+}
+
+// AllocateOutboundOrder handles stock allocation requests
+// 出荷指示の引当リクエストを処理
+func (h *Handlers) AllocateOutboundOrder(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	orderID := vars["orderId"]
+
+	outboundManager, ok := h.manager.(inventory.OutboundOrderManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "出荷指示機能がサポートされていません")
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), "user_id", "api_user")
+	order, err := outboundManager.AllocateOutboundOrder(ctx, orderID)
+	if err != nil {
+		h.sendOutboundOrderError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, h.newQuantityFormatter(r.Context()).outboundOrder(order))
+}
+
+// CancelOutboundOrder handles cancel outbound order requests
+// 出荷指示キャンセルリクエストを処理
+func (h *Handlers) CancelOutboundOrder(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	orderID := vars["orderId"]
+
+	outboundManager, ok := h.manager.(inventory.OutboundOrderManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "出荷指示機能がサポートされていません")
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), "user_id", "api_user")
+	order, err := outboundManager.CancelOutboundOrder(ctx, orderID)
+	if err != nil {
+		h.sendOutboundOrderError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, h.newQuantityFormatter(r.Context()).outboundOrder(order))
+}
+
+// GetPickList handles pick list requests for an outbound order
+// 出荷指示のピッキングリストリクエストを処理
+func (h *Handlers) GetPickList(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	orderID := vars["orderId"]
+
+	outboundManager, ok := h.manager.(inventory.OutboundOrderManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "出荷指示機能がサポートされていません")
+		return
+	}
+
+	pickList, err := outboundManager.GeneratePickList(r.Context(), orderID)
+	if err != nil {
+		h.sendOutboundOrderError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, h.newQuantityFormatter(r.Context()).pickList(pickList))
+}
+
+// ConfirmPicks handles pick confirmation requests with actual quantities
+// 実数量でのピッキング確定リクエストを処理
+func (h *Handlers) ConfirmPicks(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	orderID := vars["orderId"]
+
+	var req ConfirmPicksRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
+		return
+	}
+
+	outboundManager, ok := h.manager.(inventory.OutboundOrderManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "出荷指示機能がサポートされていません")
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), "user_id", "api_user")
+
+	// 実ピッキング数量は引当の商品の小数桁数で解釈
+	current, err := outboundManager.GetOutboundOrder(ctx, orderID)
+	if err != nil {
+		h.sendOutboundOrderError(w, err)
+		return
+	}
+	allocationItems := make(map[string]string, len(current.Allocations))
+	for _, allocation := range current.Allocations {
+		allocationItems[allocation.ID] = allocation.ItemID
+	}
+	confirmations := make([]inventory.PickConfirmation, len(req.Confirmations))
+	for i, confirmation := range req.Confirmations {
+		confirmations[i] = confirmation.PickConfirmation
+		if confirmations[i].PickedQuantity, ok = h.requestQuantity(w, ctx, allocationItems[confirmation.AllocationID], confirmation.PickedQuantity); !ok {
+			return
+		}
+	}
+
+	order, err := outboundManager.ConfirmPicks(ctx, orderID, confirmations)
+	if err != nil {
+		h.sendOutboundOrderError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, h.newQuantityFormatter(r.Context()).outboundOrder(order))
+}
+
+// CreateWave handles create wave requests
+// ウェーブ作成リクエストを処理
+func (h *Handlers) CreateWave(w http.ResponseWriter, r *http.Request) {
+	var wave inventory.Wave
+	if err := json.NewDecoder(r.Body).Decode(&wave); err != nil {
+		h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
+		return
+	}
+
+	outboundManager, ok := h.manager.(inventory.OutboundOrderManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "出荷指示機能がサポートされていません")
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), "user_id", "api_user")
+	if err := outboundManager.CreateWave(ctx, &wave); err != nil {
+		h.sendOutboundOrderError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message": "ウェーブが作成されました",
+		"wave":    wave,
+	})
+}
+
+// GetWave handles get wave requests
+// ウェーブ取得リクエストを処理
+func (h *Handlers) GetWave(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	waveID := vars["waveId"]
+
+	outboundManager, ok := h.manager.(inventory.OutboundOrderManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "出荷指示機能がサポートされていません")
+		return
+	}
+
+	wave, err := outboundManager.GetWave(r.Context(), waveID)
+	if err != nil {
+		h.sendOutboundOrderError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, wave)
+}
+
+// GetWavePickList handles combined pick list requests for a wave
+// ウェーブのまとめピッキングリストリクエストを処理
+func (h *Handlers) GetWavePickList(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	waveID := vars["waveId"]
+
+	outboundManager, ok := h.manager.(inventory.OutboundOrderManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "出荷指示機能がサポートされていません")
+		return
+	}
+
+	pickList, err := outboundManager.GenerateWavePickList(r.Context(), waveID)
+	if err != nil {
+		h.sendOutboundOrderError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, h.newQuantityFormatter(r.Context()).pickList(pickList))
+}
+
+// sendOutboundOrderError maps outbound order errors to HTTP status codes
+// 出荷指示のエラーをHTTPステータスに変換して送信
+func (h *Handlers) sendOutboundOrderError(w http.ResponseWriter, err error) {
+	switch err.(type) {
+	case *inventory.ValidationError:
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	switch err {
+	case inventory.ErrOutboundOrderNotFound, inventory.ErrWaveNotFound, inventory.ErrItemNotFound:
+		h.sendError(w, http.StatusNotFound, err.Error())
+	case inventory.ErrInvalidOutboundOrderStatus, inventory.ErrInsufficientStock, inventory.ErrInsufficientReservation,
+		inventory.ErrItemArchived, inventory.ErrLocationArchived:
+		h.sendError(w, http.StatusConflict, err.Error())
+	default:
+		h.sendError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// 部品表・キット組立ハンドラー
+
+// KitOperationRequest represents request to assemble or disassemble kits
+// キット組立・分解リクエストを表現
+type KitOperationRequest struct {
+	ItemID     string                  `json:"item_id"`
+	LocationID string                  `json:"location_id"`
+	Quantity   inventory.QuantityInput `json:"quantity"`
+	Reference  string                  `json:"reference"`
+}
+
+// SetBillOfMaterials handles bill of materials registration requests
+// 部品表登録リクエストを処理
+func (h *Handlers) SetBillOfMaterials(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	itemID := vars["itemId"]
+
+	var bom inventory.BillOfMaterials
+	if err := json.NewDecoder(r.Body).Decode(&bom); err != nil {
+		h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
+		return
+	}
+	bom.ItemID = itemID
+
+	kitManager, ok := h.manager.(inventory.KitManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "キット管理機能がサポートされていません")
+		return
+	}
+
+	if err := kitManager.SetBillOfMaterials(r.Context(), &bom); err != nil {
+		h.sendKitError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message": "部品表が登録されました",
+		"bom":     bom,
+	})
+}
+
+// GetBillOfMaterials handles bill of materials requests
+// 部品表取得リクエストを処理
+func (h *Handlers) GetBillOfMaterials(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	itemID := vars["itemId"]
+
+	kitManager, ok := h.manager.(inventory.KitManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "キット管理機能がサポートされていません")
+		return
+	}
+
+	bom, err := kitManager.GetBillOfMaterials(r.Context(), itemID)
+	if err != nil {
+		h.sendKitError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, bom)
+}
+
+// GetKitCost handles kit cost roll-up requests
+// キット原価の積み上げ計算リクエストを処理
+func (h *Handlers) GetKitCost(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	itemID := vars["itemId"]
+
+	kitManager, ok := h.manager.(inventory.KitManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "キット管理機能がサポートされていません")
+		return
+	}
+
+	cost, err := kitManager.CalculateKitCost(r.Context(), itemID)
+	if err != nil {
+		h.sendKitError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"item_id":   itemID,
+		"unit_cost": cost,
+	})
+}
+
+// AssembleKit handles kit assembly requests
+// キット組立リクエストを処理
+func (h *Handlers) AssembleKit(w http.ResponseWriter, r *http.Request) {
+	var req KitOperationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
+		return
+	}
+
+	kitManager, ok := h.manager.(inventory.KitManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "キット管理機能がサポートされていません")
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), "user_id", "api_user")
+	quantity, ok := h.requestQuantity(w, ctx, req.ItemID, req.Quantity)
+	if !ok {
+		return
+	}
+	if err := kitManager.Assemble(ctx, req.ItemID, req.LocationID, quantity, req.Reference); err != nil {
+		h.sendKitError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]string{
+		"message": "キット組立が完了しました",
+	})
+}
+
+// DisassembleKit handles kit disassembly requests
+// キット分解リクエストを処理
+func (h *Handlers) DisassembleKit(w http.ResponseWriter, r *http.Request) {
+	var req KitOperationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
+		return
+	}
+
+	kitManager, ok := h.manager.(inventory.KitManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "キット管理機能がサポートされていません")
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), "user_id", "api_user")
+	quantity, ok := h.requestQuantity(w, ctx, req.ItemID, req.Quantity)
+	if !ok {
+		return
+	}
+	if err := kitManager.Disassemble(ctx, req.ItemID, req.LocationID, quantity, req.Reference); err != nil {
+		h.sendKitError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]string{
+		"message": "キット分解が完了しました",
+	})
+}
+
+// sendKitError maps bill of materials and kit errors to HTTP status codes
+// 部品表・キット操作のエラーをHTTPステータスに変換して送信
+func (h *Handlers) sendKitError(w http.ResponseWriter, err error) {
+	switch err.(type) {
+	case *inventory.ValidationError:
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	switch err {
+	case inventory.ErrBOMNotFound, inventory.ErrItemNotFound, inventory.ErrLocationNotFound:
+		h.sendError(w, http.StatusNotFound, err.Error())
+	case inventory.ErrInsufficientStock, inventory.ErrVersionMismatch, inventory.ErrItemArchived, inventory.ErrLocationArchived:
+		h.sendError(w, http.StatusConflict, err.Error())
+	default:
+		h.sendError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// 循環棚卸ハンドラー
+
+// RecordCycleCountRequest represents request to record a counted quantity
+// 実棚数量記録リクエストを表現
+type RecordCycleCountRequest struct {
+	CountedQuantity inventory.QuantityInput `json:"counted_quantity"`
+}
+
+// ReviewCycleCountRequest represents request to approve or reject a count discrepancy
+// 棚卸差異の承認・却下リクエストを表現
+type ReviewCycleCountRequest struct {
+	Approve bool   `json:"approve"`
+	Note    string `json:"note"`
+}
+
+// GenerateCycleCountTasks handles cycle count task generation requests
+// 棚卸タスク生成リクエストを処理（location_id未指定時は全ロケーション）
+func (h *Handlers) GenerateCycleCountTasks(w http.ResponseWriter, r *http.Request) {
+	date := time.Now()
+	if dateStr := r.URL.Query().Get("date"); dateStr != "" {
+		parsed, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, "無効なdate形式です（形式：2006-01-02）")
+			return
+		}
+		date = parsed
+	}
+
+	cycleCounter, ok := h.manager.(inventory.CycleCounter)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "循環棚卸機能がサポートされていません")
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), "user_id", "api_user")
+	locationID := r.URL.Query().Get("location_id")
+	if locationID == "" {
+		created, err := cycleCounter.GenerateDailyCycleCounts(ctx, date)
+		if err != nil {
+			h.sendCycleCountError(w, err)
+			return
+		}
+		h.sendSuccess(w, map[string]interface{}{
+			"message": "棚卸タスクが生成されました",
+			"count":   created,
+		})
+		return
+	}
+
+	tasks, err := cycleCounter.GenerateCycleCountTasks(ctx, locationID, date)
+	if err != nil {
+		h.sendCycleCountError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message": "棚卸タスクが生成されました",
+		"tasks":   h.newQuantityFormatter(r.Context()).cycleCountTasks(tasks),
+		"count":   len(tasks),
+	})
+}
+
+// ListCycleCountTasks handles list cycle count task requests (filterable by location and status)
+// 棚卸タスク一覧リクエストを処理（ロケーション・ステータスで絞り込み可能）
+func (h *Handlers) ListCycleCountTasks(w http.ResponseWriter, r *http.Request) {
+	offset, limit := parsePagination(r)
+	locationID := r.URL.Query().Get("location_id")
+	status := inventory.CycleCountStatus(r.URL.Query().Get("status"))
+
+	cycleCounter, ok := h.manager.(inventory.CycleCounter)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "循環棚卸機能がサポートされていません")
+		return
+	}
+
+	tasks, err := cycleCounter.ListCycleCountTasks(r.Context(), locationID, status, offset, limit)
+	if err != nil {
+		h.sendCycleCountError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"tasks":  h.newQuantityFormatter(r.Context()).cycleCountTasks(tasks),
+		"offset": offset,
+		"limit":  limit,
+		"count":  len(tasks),
+	})
+}
+
+// GetCycleCountTask handles get cycle count task requests
+// 棚卸タスク取得リクエストを処理
+func (h *Handlers) GetCycleCountTask(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	taskID := vars["taskId"]
+
+	cycleCounter, ok := h.manager.(inventory.CycleCounter)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "循環棚卸機能がサポートされていません")
+		return
+	}
+
+	task, err := cycleCounter.GetCycleCountTask(r.Context(), taskID)
+	if err != nil {
+		h.sendCycleCountError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, h.newQuantityFormatter(r.Context()).cycleCountTask(task))
+}
+
+// RecordCycleCount handles counted quantity requests
+// 実棚数量記録リクエストを処理（許容差異内は自動承認）
+func (h *Handlers) RecordCycleCount(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	taskID := vars["taskId"]
+
+	var req RecordCycleCountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
+		return
+	}
+
+	cycleCounter, ok := h.manager.(inventory.CycleCounter)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "循環棚卸機能がサポートされていません")
+		return
+	}
+
+	ctx := h.auditContext(r)
+	// 数量は棚卸タスクの商品の小数桁数で解釈
+	current, err := cycleCounter.GetCycleCountTask(ctx, taskID)
+	if err != nil {
+		h.sendCycleCountError(w, err)
+		return
+	}
+	countedQuantity, ok := h.requestQuantity(w, ctx, current.ItemID, req.CountedQuantity)
+	if !ok {
+		return
+	}
+	task, err := cycleCounter.RecordCycleCount(ctx, taskID, countedQuantity)
+	if err != nil {
+		h.sendCycleCountError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, h.newQuantityFormatter(r.Context()).cycleCountTask(task))
+}
+
+// ReviewCycleCount handles approve/reject requests for count discrepancies
+// 棚卸差異の承認・却下リクエストを処理
+func (h *Handlers) ReviewCycleCount(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	taskID := vars["taskId"]
+
+	var req ReviewCycleCountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
+		return
+	}
+
+	cycleCounter, ok := h.manager.(inventory.CycleCounter)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "循環棚卸機能がサポートされていません")
+		return
+	}
+
+	ctx := h.auditContext(r)
+	task, err := cycleCounter.ReviewCycleCount(ctx, taskID, req.Approve, req.Note)
+	if err != nil {
+		h.sendCycleCountError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, h.newQuantityFormatter(r.Context()).cycleCountTask(task))
+}
+
+// sendCycleCountError maps cycle count errors to HTTP status codes
+// 循環棚卸のエラーをHTTPステータスに変換して送信
+func (h *Handlers) sendCycleCountError(w http.ResponseWriter, err error) {
+	switch err.(type) {
+	case *inventory.ValidationError:
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	switch err {
+	case inventory.ErrCycleCountTaskNotFound, inventory.ErrLocationNotFound, inventory.ErrItemNotFound:
+		h.sendError(w, http.StatusNotFound, err.Error())
+	case inventory.ErrInvalidCycleCountStatus, inventory.ErrVersionMismatch, inventory.ErrPeriodClosed, inventory.ErrItemArchived, inventory.ErrLocationArchived:
+		h.sendError(w, http.StatusConflict, err.Error())
+	default:
+		h.sendError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// 店舗補充ハンドラー
+
+// SetReplenishmentPolicyRequest represents request to create or update a min/max replenishment policy
+// 補充ポリシー登録・更新リクエストを表現
+type SetReplenishmentPolicyRequest struct {
+	SourceLocationID string                  `json:"source_location_id"`
+	MinQuantity      inventory.QuantityInput `json:"min_quantity"`
+	MaxQuantity      inventory.QuantityInput `json:"max_quantity"`
+	IsActive         *bool                   `json:"is_active"`
+}
+
+// ReviewReplenishmentProposalRequest represents request to approve or reject a replenishment proposal
+// 補充提案の承認・却下リクエストを表現（quantity指定時は承認数量を変更）
+type ReviewReplenishmentProposalRequest struct {
+	Approve  bool                    `json:"approve"`
+	Quantity inventory.QuantityInput `json:"quantity"`
+}
+
+// ExecuteReplenishmentProposalsRequest represents request to convert approved proposals into transfers
+// 承認済み補充提案の移動実行リクエストを表現
+type ExecuteReplenishmentProposalsRequest struct {
+	ProposalIDs []string `json:"proposal_ids"`
+}
+
+// SetReplenishmentPolicy handles replenishment policy upsert requests
+// 補充ポリシー登録・更新リクエストを処理
+func (h *Handlers) SetReplenishmentPolicy(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	var req SetReplenishmentPolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
+		return
+	}
+
+	replenisher, ok := h.manager.(inventory.ReplenishmentManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "店舗補充機能がサポートされていません")
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), "user_id", "api_user")
+	minQuantity, ok := h.requestQuantity(w, ctx, vars["itemId"], req.MinQuantity)
+	if !ok {
+		return
+	}
+	maxQuantity, ok := h.requestQuantity(w, ctx, vars["itemId"], req.MaxQuantity)
+	if !ok {
+		return
+	}
+
+	policy := &inventory.ReplenishmentPolicy{
+		LocationID:       vars["locationId"],
+		ItemID:           vars["itemId"],
+		SourceLocationID: req.SourceLocationID,
+		MinQuantity:      minQuantity,
+		MaxQuantity:      maxQuantity,
+		IsActive:         req.IsActive == nil || *req.IsActive,
+	}
+
+	if err := replenisher.SetReplenishmentPolicy(ctx, policy); err != nil {
+		h.sendReplenishmentError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, h.newQuantityFormatter(r.Context()).replenishmentPolicy(policy))
+}
+
+// ListReplenishmentPolicies handles replenishment policy list requests
+// 補充ポリシー一覧取得リクエストを処理
+func (h *Handlers) ListReplenishmentPolicies(w http.ResponseWriter, r *http.Request) {
+	replenisher, ok := h.manager.(inventory.ReplenishmentManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "店舗補充機能がサポートされていません")
+		return
+	}
+
+	policies, err := replenisher.ListReplenishmentPolicies(r.Context(), r.URL.Query().Get("location_id"))
+	if err != nil {
+		h.sendReplenishmentError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"policies": h.newQuantityFormatter(r.Context()).replenishmentPolicies(policies),
+		"count":    len(policies),
+	})
+}
+
+// DeleteReplenishmentPolicy handles replenishment policy deletion requests
+// 補充ポリシー削除リクエストを処理
+func (h *Handlers) DeleteReplenishmentPolicy(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	replenisher, ok := h.manager.(inventory.ReplenishmentManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "店舗補充機能がサポートされていません")
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), "user_id", "api_user")
+	if err := replenisher.DeleteReplenishmentPolicy(ctx, vars["locationId"], vars["itemId"]); err != nil {
+		h.sendReplenishmentError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]string{"message": "補充ポリシーが削除されました"})
+}
+
+// GenerateReplenishmentProposals handles replenishment proposal generation requests
+// 補充提案生成リクエストを処理（location_id未指定時は全店舗）
+func (h *Handlers) GenerateReplenishmentProposals(w http.ResponseWriter, r *http.Request) {
+	replenisher, ok := h.manager.(inventory.ReplenishmentManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "店舗補充機能がサポートされていません")
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), "user_id", "api_user")
+	proposals, err := replenisher.GenerateReplenishmentProposals(ctx, r.URL.Query().Get("location_id"))
+	if err != nil {
+		h.sendReplenishmentError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"proposals": h.newQuantityFormatter(r.Context()).replenishmentProposals(proposals),
+		"count":     len(proposals),
+	})
+}
+
+// ListReplenishmentProposals handles replenishment proposal list requests
+// 補充提案一覧取得リクエストを処理
+func (h *Handlers) ListReplenishmentProposals(w http.ResponseWriter, r *http.Request) {
+	offset, limit := parsePagination(r)
+	locationID := r.URL.Query().Get("location_id")
+	status := inventory.ReplenishmentProposalStatus(r.URL.Query().Get("status"))
+
+	replenisher, ok := h.manager.(inventory.ReplenishmentManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "店舗補充機能がサポートされていません")
+		return
+	}
+
+	proposals, err := replenisher.ListReplenishmentProposals(r.Context(), locationID, status, offset, limit)
+	if err != nil {
+		h.sendReplenishmentError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"proposals": h.newQuantityFormatter(r.Context()).replenishmentProposals(proposals),
+		"offset":    offset,
+		"limit":     limit,
+		"count":     len(proposals),
+	})
+}
+
+// GetReplenishmentProposal handles get replenishment proposal requests
+// 補充提案取得リクエストを処理
+func (h *Handlers) GetReplenishmentProposal(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	replenisher, ok := h.manager.(inventory.ReplenishmentManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "店舗補充機能がサポートされていません")
+		return
+	}
+
+	proposal, err := replenisher.GetReplenishmentProposal(r.Context(), vars["proposalId"])
+	if err != nil {
+		h.sendReplenishmentError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, h.newQuantityFormatter(r.Context()).replenishmentProposal(proposal))
+}
+
+// ReviewReplenishmentProposal handles replenishment proposal review requests
+// 補充提案の承認・却下リクエストを処理
+func (h *Handlers) ReviewReplenishmentProposal(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	var req ReviewReplenishmentProposalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
+		return
+	}
+
+	replenisher, ok := h.manager.(inventory.ReplenishmentManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "店舗補充機能がサポートされていません")
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), "user_id", "api_user")
+	// 承認数量の変更は提案の商品の小数桁数で解釈
+	var quantity int64
+	if req.Quantity != "" {
+		current, err := replenisher.GetReplenishmentProposal(ctx, vars["proposalId"])
+		if err != nil {
+			h.sendReplenishmentError(w, err)
+			return
+		}
+		if quantity, ok = h.requestQuantity(w, ctx, current.ItemID, req.Quantity); !ok {
+			return
+		}
+	}
+	proposal, err := replenisher.ReviewReplenishmentProposal(ctx, vars["proposalId"], req.Approve, quantity)
+	if err != nil {
+		h.sendReplenishmentError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, h.newQuantityFormatter(r.Context()).replenishmentProposal(proposal))
+}
+
+// ExecuteReplenishmentProposals handles requests to execute approved proposals as one transfer batch
+// 承認済み補充提案を1回のバッチで在庫移動として実行するリクエストを処理
+func (h *Handlers) ExecuteReplenishmentProposals(w http.ResponseWriter, r *http.Request) {
+	var req ExecuteReplenishmentProposalsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
+		return
+	}
+
+	replenisher, ok := h.manager.(inventory.ReplenishmentManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "店舗補充機能がサポートされていません")
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), "user_id", "api_user")
+	batch, err := replenisher.ExecuteReplenishmentProposals(ctx, req.ProposalIDs)
+	if err != nil {
+		h.sendReplenishmentError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, batch)
+}
+
+// sendReplenishmentError maps replenishment errors to HTTP status codes
+// 店舗補充のエラーをHTTPステータスに変換して送信
+func (h *Handlers) sendReplenishmentError(w http.ResponseWriter, err error) {
+	switch err.(type) {
+	case *inventory.ValidationError:
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	switch err {
+	case inventory.ErrReplenishmentPolicyNotFound, inventory.ErrReplenishmentProposalNotFound,
+		inventory.ErrLocationNotFound, inventory.ErrItemNotFound:
+		h.sendError(w, http.StatusNotFound, err.Error())
+	case inventory.ErrInvalidReplenishmentStatus, inventory.ErrItemArchived, inventory.ErrLocationArchived:
+		h.sendError(w, http.StatusConflict, err.Error())
+	default:
+		h.sendError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// 定期レポートハンドラー
+
+// ReportScheduleRequest represents request to create or update a report schedule
+// レポートスケジュール登録・更新リクエストを表現
+type ReportScheduleRequest struct {
+	Name           string `json:"name"`
+	CronExpression string `json:"cron_expression"`
+	Timezone       string `json:"timezone"`
+	ReportType     string `json:"report_type"`
+	Format         string `json:"format"`
+	LocationID     string `json:"location_id"`
+	Category       string `json:"category"`
+	PeriodDays     int    `json:"period_days"`
+	IsActive       *bool  `json:"is_active"`
+}
+
+// toSchedule converts the request into a report schedule
+// リクエストをレポートスケジュールに変換
+func (req ReportScheduleRequest) toSchedule(scheduleID string) *inventory.ReportSchedule {
+	return &inventory.ReportSchedule{
+		ID:             scheduleID,
+		Name:           req.Name,
+		CronExpression: req.CronExpression,
+		Timezone:       req.Timezone,
+		ReportType:     inventory.ReportType(req.ReportType),
+		Format:         inventory.ReportFormat(req.Format),
+		LocationID:     req.LocationID,
+		Category:       req.Category,
+		PeriodDays:     req.PeriodDays,
+		IsActive:       req.IsActive == nil || *req.IsActive,
+	}
+}
+
+// CreateReportSchedule handles report schedule creation requests
+// レポートスケジュール作成リクエストを処理
+func (h *Handlers) CreateReportSchedule(w http.ResponseWriter, r *http.Request) {
+	var req ReportScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
+		return
+	}
+
+	scheduler, ok := h.manager.(inventory.ReportScheduler)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "定期レポート機能がサポートされていません")
+		return
+	}
+
+	schedule := req.toSchedule("")
+	ctx := context.WithValue(r.Context(), "user_id", "api_user")
+	if err := scheduler.CreateReportSchedule(ctx, schedule); err != nil {
+		h.sendReportScheduleError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, schedule)
+}
+
+// ListReportSchedules handles report schedule list requests
+// レポートスケジュール一覧取得リクエストを処理
+func (h *Handlers) ListReportSchedules(w http.ResponseWriter, r *http.Request) {
+	scheduler, ok := h.manager.(inventory.ReportScheduler)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "定期レポート機能がサポートされていません")
+		return
+	}
+
+	schedules, err := scheduler.ListReportSchedules(r.Context())
+	if err != nil {
+		h.sendReportScheduleError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"schedules": schedules,
+		"count":     len(schedules),
+	})
+}
+
+// GetReportSchedule handles get report schedule requests
+// レポートスケジュール取得リクエストを処理
+func (h *Handlers) GetReportSchedule(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	scheduler, ok := h.manager.(inventory.ReportScheduler)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "定期レポート機能がサポートされていません")
+		return
+	}
+
+	schedule, err := scheduler.GetReportSchedule(r.Context(), vars["scheduleId"])
+	if err != nil {
+		h.sendReportScheduleError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, schedule)
+}
+
+// UpdateReportSchedule handles report schedule update requests
+// レポートスケジュール更新リクエストを処理
+func (h *Handlers) UpdateReportSchedule(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	var req ReportScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
+		return
+	}
+
+	scheduler, ok := h.manager.(inventory.ReportScheduler)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "定期レポート機能がサポートされていません")
+		return
+	}
+
+	schedule := req.toSchedule(vars["scheduleId"])
+	ctx := context.WithValue(r.Context(), "user_id", "api_user")
+	if err := scheduler.UpdateReportSchedule(ctx, schedule); err != nil {
+		h.sendReportScheduleError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, schedule)
+}
+
+// DeleteReportSchedule handles report schedule deletion requests
+// レポートスケジュール削除リクエストを処理
+func (h *Handlers) DeleteReportSchedule(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	scheduler, ok := h.manager.(inventory.ReportScheduler)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "定期レポート機能がサポートされていません")
+		return
+	}
+
+	if err := scheduler.DeleteReportSchedule(r.Context(), vars["scheduleId"]); err != nil {
+		h.sendReportScheduleError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]string{"message": "レポートスケジュールが正常に削除されました"})
+}
+
+// RunReportSchedule handles requests to generate a scheduled report immediately
+// スケジュールのレポート即時生成リクエストを処理
+func (h *Handlers) RunReportSchedule(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	scheduler, ok := h.manager.(inventory.ReportScheduler)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "定期レポート機能がサポートされていません")
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), "user_id", "api_user")
+	archive, err := scheduler.RunReportSchedule(ctx, vars["scheduleId"])
+	if err != nil {
+		h.sendReportScheduleError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, archive)
+}
+
+// ListReportArchives handles archived report list requests
+// アーカイブ済みレポート一覧取得リクエストを処理
+func (h *Handlers) ListReportArchives(w http.ResponseWriter, r *http.Request) {
+	offset, limit := parsePagination(r)
+	scheduleID := r.URL.Query().Get("schedule_id")
+	reportType := inventory.ReportType(r.URL.Query().Get("type"))
+
+	scheduler, ok := h.manager.(inventory.ReportScheduler)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "定期レポート機能がサポートされていません")
+		return
+	}
+
+	archives, err := scheduler.ListReportArchives(r.Context(), scheduleID, reportType, offset, limit)
+	if err != nil {
+		h.sendReportScheduleError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"archives": archives,
+		"offset":   offset,
+		"limit":    limit,
+		"count":    len(archives),
+	})
+}
+
+// GetReportArchive handles get archived report metadata requests
+// アーカイブ済みレポートのメタデータ取得リクエストを処理
+func (h *Handlers) GetReportArchive(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	scheduler, ok := h.manager.(inventory.ReportScheduler)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "定期レポート機能がサポートされていません")
+		return
+	}
+
+	archive, err := scheduler.GetReportArchive(r.Context(), vars["archiveId"])
+	if err != nil {
+		h.sendReportScheduleError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, archive)
+}
+
+// DownloadReportArchive handles archived report download requests
+// アーカイブ済みレポートのダウンロードリクエストを処理
+func (h *Handlers) DownloadReportArchive(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	scheduler, ok := h.manager.(inventory.ReportScheduler)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "定期レポート機能がサポートされていません")
+		return
+	}
+
+	archive, file, err := scheduler.OpenReportArchive(r.Context(), vars["archiveId"])
+	if err != nil {
+		h.sendReportScheduleError(w, err)
+		return
+	}
+	defer file.Close()
+
+	contentType, ok := reportContentTypes[archive.Format]
+	if !ok {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", archive.FileName))
+	w.Header().Set("Content-Length", strconv.FormatInt(archive.SizeBytes, 10))
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, file); err != nil {
+		h.logger.Error("アーカイブ済みレポートの送信に失敗しました", zap.String("archive_id", archive.ID), zap.Error(err))
+	}
+}
+
+// sendReportScheduleError maps report scheduler errors to HTTP status codes
+// 定期レポート関連のエラーをHTTPステータスに変換して送信
+func (h *Handlers) sendReportScheduleError(w http.ResponseWriter, err error) {
+	switch err.(type) {
+	case *inventory.ValidationError:
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	switch err {
+	case inventory.ErrReportScheduleNotFound, inventory.ErrReportArchiveNotFound:
+		h.sendError(w, http.StatusNotFound, err.Error())
+	default:
+		h.sendError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// 会計期間ハンドラー
+
+// FiscalPeriodRequest represents request to define a fiscal period
+// 会計期間作成リクエストを表現
+type FiscalPeriodRequest struct {
+	Name      string `json:"name"`
+	StartDate string `json:"start_date"` // YYYY-MM-DD
+	EndDate   string `json:"end_date"`   // YYYY-MM-DD（当日を含む）
+}
+
+// ReopenFiscalPeriodRequest represents request to reopen a closed fiscal period
+// 会計期間の締め解除リクエストを表現
+type ReopenFiscalPeriodRequest struct {
+	Reason string `json:"reason"`
+}
+
+// CreateFiscalPeriod handles fiscal period creation requests
+// 会計期間作成リクエストを処理
+func (h *Handlers) CreateFiscalPeriod(w http.ResponseWriter, r *http.Request) {
+	var req FiscalPeriodRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
+		return
+	}
+
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "無効な開始日です（YYYY-MM-DD形式で指定してください）")
+		return
+	}
+	endDate, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "無効な終了日です（YYYY-MM-DD形式で指定してください）")
+		return
+	}
+
+	closer, ok := h.manager.(inventory.PeriodCloser)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "会計期間機能がサポートされていません")
+		return
+	}
+
+	period := &inventory.FiscalPeriod{
+		Name:      req.Name,
+		StartDate: startDate,
+		EndDate:   endDate,
+	}
+	if err := closer.CreateFiscalPeriod(h.auditContext(r), period); err != nil {
+		h.sendFiscalPeriodError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, period)
+}
+
+// ListFiscalPeriods handles fiscal period list requests
+// 会計期間一覧取得リクエストを処理
+func (h *Handlers) ListFiscalPeriods(w http.ResponseWriter, r *http.Request) {
+	closer, ok := h.manager.(inventory.PeriodCloser)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "会計期間機能がサポートされていません")
+		return
+	}
+
+	periods, err := closer.ListFiscalPeriods(r.Context())
+	if err != nil {
+		h.sendFiscalPeriodError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"periods": periods,
+		"count":   len(periods),
+	})
+}
+
+// GetFiscalPeriod handles get fiscal period requests
+// 会計期間取得リクエストを処理
+func (h *Handlers) GetFiscalPeriod(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	periodID := vars["periodId"]
+
+	closer, ok := h.manager.(inventory.PeriodCloser)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "会計期間機能がサポートされていません")
+		return
+	}
+
+	period, err := closer.GetFiscalPeriod(r.Context(), periodID)
+	if err != nil {
+		h.sendFiscalPeriodError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, period)
+}
+
+// CloseFiscalPeriod handles period close requests
+// 会計期間の締めリクエストを処理
+func (h *Handlers) CloseFiscalPeriod(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	periodID := vars["periodId"]
+
+	closer, ok := h.manager.(inventory.PeriodCloser)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "会計期間機能がサポートされていません")
+		return
+	}
+
+	period, err := closer.CloseFiscalPeriod(h.auditContext(r), periodID)
+	if err != nil {
+		h.sendFiscalPeriodError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, period)
+}
+
+// ReopenFiscalPeriod handles admin requests to reopen a closed period
+// 管理者による会計期間の締め解除リクエストを処理
+func (h *Handlers) ReopenFiscalPeriod(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	periodID := vars["periodId"]
+
+	var req ReopenFiscalPeriodRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
+		return
+	}
+
+	closer, ok := h.manager.(inventory.PeriodCloser)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "会計期間機能がサポートされていません")
+		return
+	}
+
+	period, err := closer.ReopenFiscalPeriod(h.auditContext(r), periodID, req.Reason)
+	if err != nil {
+		h.sendFiscalPeriodError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, period)
+}
+
+// ListPeriodEndBalances handles period-end balance list requests
+// 期末残高一覧取得リクエストを処理
+func (h *Handlers) ListPeriodEndBalances(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	periodID := vars["periodId"]
+	offset, limit := parsePagination(r)
+
+	closer, ok := h.manager.(inventory.PeriodCloser)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "会計期間機能がサポートされていません")
+		return
+	}
+
+	balances, err := closer.ListPeriodEndBalances(r.Context(), periodID, r.URL.Query().Get("location_id"), offset, limit)
+	if err != nil {
+		h.sendFiscalPeriodError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"balances": balances,
+		"count":    len(balances),
+	})
+}
+
+// ListFiscalPeriodEvents handles requests for the close and reopen audit trail of a period
+// 会計期間の締め・締め解除の監査記録取得リクエストを処理
+func (h *Handlers) ListFiscalPeriodEvents(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	periodID := vars["periodId"]
+
+	closer, ok := h.manager.(inventory.PeriodCloser)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "会計期間機能がサポートされていません")
+		return
+	}
+
+	events, err := closer.ListFiscalPeriodEvents(r.Context(), periodID)
+	if err != nil {
+		h.sendFiscalPeriodError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"events": events,
+		"count":  len(events),
+	})
+}
+
+// auditContext returns a request context carrying the authenticated user for audit records
+// 監査記録のため認証済みユーザーを設定したコンテキストを返す
+func (h *Handlers) auditContext(r *http.Request) context.Context {
+	userID := auth.GetUserIDFromContext(r.Context())
+	if userID == "" {
+		userID = "api_user"
+	}
+	return context.WithValue(r.Context(), "user_id", userID)
+}
+
+// sendFiscalPeriodError maps fiscal period errors to HTTP status codes
+// 会計期間のエラーをHTTPステータスに変換して送信
+func (h *Handlers) sendFiscalPeriodError(w http.ResponseWriter, err error) {
+	switch err.(type) {
+	case *inventory.ValidationError:
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	case *inventory.BusinessRuleError:
+		h.sendError(w, http.StatusConflict, err.Error())
+		return
+	}
+
+	switch err {
+	case inventory.ErrFiscalPeriodNotFound:
+		h.sendError(w, http.StatusNotFound, err.Error())
+	case inventory.ErrInvalidFiscalPeriodStatus:
+		h.sendError(w, http.StatusConflict, err.Error())
+	default:
+		h.sendError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// 商品属性・バリアントハンドラー
+
+// SetCategoryAttributeSchema handles category attribute schema registration requests
+// カテゴリ属性スキーマ登録リクエストを処理
+func (h *Handlers) SetCategoryAttributeSchema(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	var schema inventory.CategoryAttributeSchema
+	if err := json.NewDecoder(r.Body).Decode(&schema); err != nil {
+		h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
+		return
+	}
+	schema.Category = vars["category"]
+
+	attributeManager, ok := h.manager.(inventory.ItemAttributeManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "商品属性管理機能がサポートされていません")
+		return
+	}
+
+	if err := attributeManager.SetCategoryAttributeSchema(r.Context(), &schema); err != nil {
+		h.sendItemAttributeError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message": "属性スキーマが登録されました",
+		"schema":  schema,
+	})
+}
+
+// ListCategoryAttributeSchemas handles category attribute schema list requests
+// カテゴリ属性スキーマ一覧リクエストを処理
+func (h *Handlers) ListCategoryAttributeSchemas(w http.ResponseWriter, r *http.Request) {
+	attributeManager, ok := h.manager.(inventory.ItemAttributeManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "商品属性管理機能がサポートされていません")
+		return
+	}
+
+	schemas, err := attributeManager.ListCategoryAttributeSchemas(r.Context())
+	if err != nil {
+		h.sendItemAttributeError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"schemas": schemas,
+		"count":   len(schemas),
+	})
+}
+
+// GetCategoryAttributeSchema handles category attribute schema requests
+// カテゴリ属性スキーマ取得リクエストを処理
+func (h *Handlers) GetCategoryAttributeSchema(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	attributeManager, ok := h.manager.(inventory.ItemAttributeManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "商品属性管理機能がサポートされていません")
+		return
+	}
+
+	schema, err := attributeManager.GetCategoryAttributeSchema(r.Context(), vars["category"])
+	if err != nil {
+		h.sendItemAttributeError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, schema)
+}
+
+// VariantGroupRequest represents request to register the variant axes of a parent item
+// 親商品のバリアント軸登録リクエストを表現
+type VariantGroupRequest struct {
+	Axes []string `json:"axes"` // バリアント軸となる属性名（size, colourなど）
+}
+
+// SetVariantGroup handles variant group registration requests
+// バリアントグループ登録リクエストを処理
+func (h *Handlers) SetVariantGroup(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	var req VariantGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
+		return
+	}
+
+	attributeManager, ok := h.manager.(inventory.ItemAttributeManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "商品属性管理機能がサポートされていません")
+		return
+	}
+
+	group := inventory.VariantGroup{ParentItemID: vars["itemId"], Axes: req.Axes}
+	if err := attributeManager.SetVariantGroup(r.Context(), &group); err != nil {
+		h.sendItemAttributeError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message":       "バリアントグループが登録されました",
+		"variant_group": group,
+	})
+}
+
+// GetVariantGroup handles variant group requests including the child SKUs
+// 子SKUを含むバリアントグループ取得リクエストを処理
+func (h *Handlers) GetVariantGroup(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	attributeManager, ok := h.manager.(inventory.ItemAttributeManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "商品属性管理機能がサポートされていません")
+		return
+	}
+
+	group, err := attributeManager.GetVariantGroup(r.Context(), vars["itemId"])
+	if err != nil {
+		h.sendItemAttributeError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, group)
+}
+
+// sendItemAttributeError maps item attribute and variant errors to HTTP responses
+// 商品属性・バリアントのエラーをHTTPレスポンスに変換
+func (h *Handlers) sendItemAttributeError(w http.ResponseWriter, err error) {
+	switch err {
+	case inventory.ErrAttributeSchemaNotFound, inventory.ErrVariantGroupNotFound:
+		h.sendError(w, http.StatusNotFound, err.Error())
+	default:
+		h.sendItemError(w, err)
+	}
+}
+
+// バーコード・スキャンハンドラー
+
+// AddItemBarcode handles item barcode registration requests
+// 商品バーコード登録リクエストを処理
+func (h *Handlers) AddItemBarcode(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	var barcode inventory.ItemBarcode
+	if err := json.NewDecoder(r.Body).Decode(&barcode); err != nil {
+		h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
+		return
+	}
+	barcode.ItemID = vars["itemId"]
+
+	barcodeManager, ok := h.manager.(inventory.BarcodeManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "バーコード管理機能がサポートされていません")
+		return
+	}
+
+	if err := barcodeManager.AddItemBarcode(r.Context(), &barcode); err != nil {
+		h.sendBarcodeError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message": "バーコードが登録されました",
+		"barcode": barcode,
+	})
+}
+
+// ListItemBarcodes handles item barcode list requests
+// 商品バーコード一覧リクエストを処理
+func (h *Handlers) ListItemBarcodes(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	barcodeManager, ok := h.manager.(inventory.BarcodeManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "バーコード管理機能がサポートされていません")
+		return
+	}
+
+	barcodes, err := barcodeManager.ListItemBarcodes(r.Context(), vars["itemId"])
+	if err != nil {
+		h.sendBarcodeError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"barcodes": barcodes,
+		"count":    len(barcodes),
+	})
+}
+
+// RemoveItemBarcode handles item barcode removal requests
+// 商品バーコード削除リクエストを処理
+func (h *Handlers) RemoveItemBarcode(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	barcodeManager, ok := h.manager.(inventory.BarcodeManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "バーコード管理機能がサポートされていません")
+		return
+	}
+
+	if err := barcodeManager.RemoveItemBarcode(r.Context(), vars["itemId"], vars["code"]); err != nil {
+		h.sendBarcodeError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]string{
+		"message": "バーコードが削除されました",
+	})
+}
+
+// ScanRequest represents a scanned barcode or GS1 label
+// 読み取ったバーコードまたはGS1ラベルを表現
+type ScanRequest struct {
+	Code string `json:"code"` // 読み取った文字列（JAN/GTINまたは"(01)...(10)..."・GS区切りのGS1データ）
+}
+
+// ResolveScan handles scan resolution requests
+// スキャン解決リクエストを処理
+func (h *Handlers) ResolveScan(w http.ResponseWriter, r *http.Request) {
+	var req ScanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
+		return
+	}
+
+	barcodeManager, ok := h.manager.(inventory.BarcodeManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "バーコード管理機能がサポートされていません")
+		return
+	}
+
+	result, err := barcodeManager.ResolveScan(r.Context(), req.Code)
+	if err != nil {
+		h.sendBarcodeError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, result)
+}
+
+// sendBarcodeError maps barcode and scan errors to HTTP responses
+// バーコード・スキャンのエラーをHTTPレスポンスに変換
+func (h *Handlers) sendBarcodeError(w http.ResponseWriter, err error) {
+	switch err {
+	case inventory.ErrBarcodeNotFound:
+		h.sendError(w, http.StatusNotFound, err.Error())
+	case inventory.ErrDuplicateBarcode:
+		h.sendError(w, http.StatusConflict, err.Error())
+	default:
+		h.sendItemError(w, err)
+	}
+}
+
+// 為替レートハンドラー
+
+// ExchangeRateRequest represents request to register an exchange rate into the base currency
+// 基準通貨への為替レート登録リクエストを表現
+type ExchangeRateRequest struct {
+	Currency      string         `json:"currency"`
+	Rate          inventory.Rate `json:"rate"`           // 外貨1単位あたりの基準通貨額
+	EffectiveDate string         `json:"effective_date"` // YYYY-MM-DD（適用開始日）
+}
+
+// SetExchangeRate handles exchange rate registration requests
+// 為替レート登録リクエストを処理
+func (h *Handlers) SetExchangeRate(w http.ResponseWriter, r *http.Request) {
+	var req ExchangeRateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
+		return
+	}
+
+	effectiveDate, err := time.Parse("2006-01-02", req.EffectiveDate)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "無効な適用開始日です（YYYY-MM-DD形式で指定してください）")
+		return
+	}
+
+	rateManager, ok := h.manager.(inventory.ExchangeRateManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "為替レート機能がサポートされていません")
+		return
+	}
+
+	rate := &inventory.ExchangeRate{
+		Currency:      req.Currency,
+		Rate:          req.Rate,
+		EffectiveDate: effectiveDate,
+	}
+	if err := rateManager.SetExchangeRate(h.auditContext(r), rate); err != nil {
+		h.sendExchangeRateError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, rate)
+}
+
+// ListExchangeRates handles exchange rate list requests
+// 為替レート一覧取得リクエストを処理
+func (h *Handlers) ListExchangeRates(w http.ResponseWriter, r *http.Request) {
+	rateManager, ok := h.manager.(inventory.ExchangeRateManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "為替レート機能がサポートされていません")
+		return
+	}
+
+	rates, err := rateManager.ListExchangeRates(r.Context(), r.URL.Query().Get("currency"))
+	if err != nil {
+		h.sendExchangeRateError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"rates": rates,
+		"count": len(rates),
+	})
+}
+
+// GetExchangeRate handles requests for the rate effective on a date (today when omitted)
+// 指定日（省略時は当日）に有効な為替レートの取得リクエストを処理
+func (h *Handlers) GetExchangeRate(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	currency := vars["currency"]
+
+	at := time.Now()
+	if dateStr := r.URL.Query().Get("date"); dateStr != "" {
+		date, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, "無効な日付です（YYYY-MM-DD形式で指定してください）")
+			return
+		}
+		at = date
+	}
+
+	rateManager, ok := h.manager.(inventory.ExchangeRateManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "為替レート機能がサポートされていません")
+		return
+	}
+
+	rate, err := rateManager.GetExchangeRate(r.Context(), currency, at)
+	if err != nil {
+		h.sendExchangeRateError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, rate)
+}
+
+// sendExchangeRateError maps exchange rate errors to HTTP status codes
+// 為替レートのエラーをHTTPステータスに変換して送信
+func (h *Handlers) sendExchangeRateError(w http.ResponseWriter, err error) {
+	if _, isValidation := err.(*inventory.ValidationError); isValidation {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err == inventory.ErrExchangeRateNotFound {
+		h.sendError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	h.sendError(w, http.StatusInternalServerError, err.Error())
+}
+
+// マスタ一括取り込み・出力ハンドラー
+
+// maxMasterImportBytes is the largest import file accepted by the master import endpoint
+// マスタ取り込みで受け付けるファイルサイズの上限
+const maxMasterImportBytes = 32 << 20
+
+// StartMasterImport handles CSV/XLSX uploads of item or location masters and starts a background import job
+// 商品・ロケーションマスタのCSV・XLSXを受け付け、バックグラウンドの取り込みジョブを開始
+//
+// リクエスト本文はファイルそのもの。クエリ: format（csv/xlsx）, mode（create/upsert）, dry_run（true/false）
+func (h *Handlers) StartMasterImport(w http.ResponseWriter, r *http.Request) {
+	masterDataManager, ok := h.manager.(inventory.MasterDataManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "マスタ一括取り込み機能がサポートされていません")
+		return
+	}
+
+	query := r.URL.Query()
+	request := inventory.MasterImportRequest{
+		Target: inventory.MasterDataType(mux.Vars(r)["target"]),
+		Format: inventory.ReportFormat(query.Get("format")),
+		Mode:   inventory.MasterImportMode(query.Get("mode")),
+	}
+	if dryRunStr := query.Get("dry_run"); dryRunStr != "" {
+		dryRun, err := strconv.ParseBool(dryRunStr)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, "無効なdry_runです（true / false）")
+			return
+		}
+		request.DryRun = dryRun
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxMasterImportBytes))
+	if err != nil {
+		h.sendError(w, http.StatusRequestEntityTooLarge, "取り込みファイルのサイズが上限を超えています")
+		return
+	}
+
+	ctx := h.auditContext(r)
+	job, err := masterDataManager.StartMasterImport(ctx, request, data)
+	if err != nil {
+		h.sendMasterDataError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message": "取り込みジョブを開始しました",
+		"job":     job,
+	})
+}
+
+// GetMasterImportJob handles requests for the progress and row errors of a master import job
+// マスタ取り込みジョブの進捗・行エラーの照会リクエストを処理
+func (h *Handlers) GetMasterImportJob(w http.ResponseWriter, r *http.Request) {
+	masterDataManager, ok := h.manager.(inventory.MasterDataManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "マスタ一括取り込み機能がサポートされていません")
+		return
+	}
+
+	job, err := masterDataManager.GetMasterImportJob(r.Context(), mux.Vars(r)["jobId"])
+	if err != nil {
+		h.sendMasterDataError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, job)
+}
+
+// ListMasterImportJobs handles list master import job requests
+// マスタ取り込みジョブ一覧リクエストを処理
+func (h *Handlers) ListMasterImportJobs(w http.ResponseWriter, r *http.Request) {
+	offset, limit := parsePagination(r)
+
+	masterDataManager, ok := h.manager.(inventory.MasterDataManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "マスタ一括取り込み機能がサポートされていません")
+		return
+	}
+
+	jobs, err := masterDataManager.ListMasterImportJobs(r.Context(), offset, limit)
+	if err != nil {
+		h.sendMasterDataError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"jobs":   jobs,
+		"offset": offset,
+		"limit":  limit,
+		"count":  len(jobs),
+	})
+}
+
+// ExportMasterData handles item or location master exports in the import file layout
+// 商品・ロケーションマスタを取り込みファイルと同じ列構成で出力
+//
+// クエリ: format（csv/json/xlsx）, include_archived（true/false）
+func (h *Handlers) ExportMasterData(w http.ResponseWriter, r *http.Request) {
+	masterDataManager, ok := h.manager.(inventory.MasterDataManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "マスタ一括取り込み機能がサポートされていません")
+		return
+	}
+
+	query := r.URL.Query()
+	target := inventory.MasterDataType(mux.Vars(r)["target"])
+	format := inventory.ReportFormatCSV
+	if formatStr := query.Get("format"); formatStr != "" {
+		format = inventory.ReportFormat(formatStr)
+	}
+	contentType, ok := reportContentTypes[format]
+	if !ok {
+		h.sendError(w, http.StatusBadRequest, "無効なformatです（csv / json / xlsx）")
+		return
+	}
+	includeArchived := false
+	if includeStr := query.Get("include_archived"); includeStr != "" {
+		parsed, err := strconv.ParseBool(includeStr)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, "無効なinclude_archivedです（true / false）")
+			return
+		}
+		includeArchived = parsed
+	}
+
+	// 最初の書き込みまでヘッダーを確定させず、出力前のエラーはJSONで返す
+	out := &reportResponseWriter{
+		w:           w,
+		contentType: contentType,
+		filename:    fmt.Sprintf("%s_%s.%s", target, time.Now().Format("20060102"), format),
+	}
+	if err := masterDataManager.ExportMasterData(r.Context(), out, target, format, includeArchived); err != nil {
+		if out.started {
+			h.logger.Error("マスタ出力に失敗しました", zap.Error(err))
+			return
+		}
+		h.sendMasterDataError(w, err)
+	}
+}
+
+// sendMasterDataError maps master import and export errors to HTTP status codes
+// マスタ一括取り込み・出力のエラーをHTTPステータスに変換して送信
+func (h *Handlers) sendMasterDataError(w http.ResponseWriter, err error) {
+	if _, isValidation := err.(*inventory.ValidationError); isValidation {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err == inventory.ErrMasterImportJobNotFound {
+		h.sendError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	h.sendError(w, http.StatusInternalServerError, err.Error())
+}
+
+// 期首在庫ハンドラー
+
+// RollbackOpeningBalanceRequest represents request to roll back an opening balance load
+// 期首在庫の一括取消リクエストを表現
+type RollbackOpeningBalanceRequest struct {
+	Reason string `json:"reason"`
+}
+
+// ImportOpeningBalances handles CSV/XLSX uploads of opening balance stock
+// 期首在庫のCSV・XLSXの取り込みリクエストを処理
+//
+// リクエスト本文はファイルそのもの。クエリ: as_of（移行基準日時、必須）, format（csv/xlsx）, reference, dry_run（true/false）
+// 検証エラーがある場合は計上せず、状態rejectedと行エラーを返します。
+func (h *Handlers) ImportOpeningBalances(w http.ResponseWriter, r *http.Request) {
+	openingBalanceManager, ok := h.manager.(inventory.OpeningBalanceManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "期首在庫の取り込み機能がサポートされていません")
+		return
+	}
+
+	query := r.URL.Query()
+	asOfStr := query.Get("as_of")
+	if asOfStr == "" {
+		h.sendError(w, http.StatusBadRequest, "as_ofは必須です")
+		return
+	}
+	asOf, err := parseAsOf(asOfStr)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "無効なas_of形式です（形式：2006-01-02 または RFC3339）")
+		return
+	}
+	request := inventory.OpeningBalanceRequest{
+		Format:    inventory.ReportFormat(query.Get("format")),
+		AsOf:      asOf,
+		Reference: query.Get("reference"),
+	}
+	if dryRunStr := query.Get("dry_run"); dryRunStr != "" {
+		dryRun, err := strconv.ParseBool(dryRunStr)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, "無効なdry_runです（true / false）")
+			return
+		}
+		request.DryRun = dryRun
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxMasterImportBytes))
+	if err != nil {
+		h.sendError(w, http.StatusRequestEntityTooLarge, "取り込みファイルのサイズが上限を超えています")
+		return
+	}
+
+	ctx := h.auditContext(r)
+	load, err := openingBalanceManager.ImportOpeningBalances(ctx, request, data)
+	if err != nil {
+		h.sendOpeningBalanceError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, load)
+}
+
+// GetOpeningBalanceLoad handles requests for an opening balance load with its lines
+// 期首在庫の取り込みの明細付き照会リクエストを処理
+func (h *Handlers) GetOpeningBalanceLoad(w http.ResponseWriter, r *http.Request) {
+	openingBalanceManager, ok := h.manager.(inventory.OpeningBalanceManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "期首在庫の取り込み機能がサポートされていません")
+		return
+	}
+
+	load, err := openingBalanceManager.GetOpeningBalanceLoad(r.Context(), mux.Vars(r)["loadId"])
+	if err != nil {
+		h.sendOpeningBalanceError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, load)
+}
+
+// ListOpeningBalanceLoads handles list opening balance load requests
+// 期首在庫の取り込み一覧リクエストを処理
+func (h *Handlers) ListOpeningBalanceLoads(w http.ResponseWriter, r *http.Request) {
+	offset, limit := parsePagination(r)
+
+	openingBalanceManager, ok := h.manager.(inventory.OpeningBalanceManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "期首在庫の取り込み機能がサポートされていません")
+		return
+	}
+
+	loads, err := openingBalanceManager.ListOpeningBalanceLoads(r.Context(), offset, limit)
+	if err != nil {
+		h.sendOpeningBalanceError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"loads":  loads,
+		"offset": offset,
+		"limit":  limit,
+		"count":  len(loads),
+	})
+}
+
+// RollbackOpeningBalanceLoad handles requests to reverse an opening balance load as a unit
+// 期首在庫の一括取消リクエストを処理
+func (h *Handlers) RollbackOpeningBalanceLoad(w http.ResponseWriter, r *http.Request) {
+	var req RollbackOpeningBalanceRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
+			return
+		}
+	}
+
+	openingBalanceManager, ok := h.manager.(inventory.OpeningBalanceManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "期首在庫の取り込み機能がサポートされていません")
+		return
+	}
+
+	ctx := h.auditContext(r)
+	load, err := openingBalanceManager.RollbackOpeningBalanceLoad(ctx, mux.Vars(r)["loadId"], req.Reason)
+	if err != nil {
+		h.sendOpeningBalanceError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message": "期首在庫を取り消しました",
+		"load":    load,
+	})
+}
+
+// sendOpeningBalanceError maps opening balance errors to HTTP status codes
+// 期首在庫のエラーをHTTPステータスに変換して送信
+func (h *Handlers) sendOpeningBalanceError(w http.ResponseWriter, err error) {
+	switch err.(type) {
+	case *inventory.ValidationError:
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	case *inventory.BusinessRuleError:
+		h.sendError(w, http.StatusConflict, err.Error())
+		return
+	}
+
+	switch err {
+	case inventory.ErrOpeningBalanceLoadNotFound:
+		h.sendError(w, http.StatusNotFound, err.Error())
+	case inventory.ErrPeriodClosed, inventory.ErrPostingBeforeSnapshot, inventory.ErrInsufficientStock, inventory.ErrVersionMismatch,
+		inventory.ErrTransactionAlreadyReversed, inventory.ErrItemArchived, inventory.ErrLocationArchived:
+		h.sendError(w, http.StatusConflict, err.Error())
+	default:
+		h.sendError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// ReconcileLedger compares stock balances with the ledger (POST also posts corrections)
+// 在庫数量と台帳を照合（POSTの場合は差異の補正トランザクションも記録）
+func (h *Handlers) ReconcileLedger(w http.ResponseWriter, r *http.Request) {
+	reconciler, ok := h.manager.(inventory.LedgerReconciler)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "台帳照合機能がサポートされていません")
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		h.sendError(w, http.StatusBadRequest, "無効な出力形式です（json または csv）")
+		return
+	}
+
+	fix := r.Method == http.MethodPost
+	ctx := context.WithValue(r.Context(), "user_id", "api_user")
+
+	report, err := reconciler.ReconcileLedger(ctx, fix)
+	if err != nil {
+		h.logger.Error("台帳照合に失敗しました", zap.Error(err))
+		h.sendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=reconciliation_%s.csv", report.GeneratedAt.Format("20060102T150405")))
+		// BOM付きUTF-8
+		w.Write([]byte{0xEF, 0xBB, 0xBF})
+		if err := report.WriteCSV(w); err != nil {
+			h.logger.Error("照合レポート出力に失敗しました", zap.Error(err))
+		}
+		return
+	}
+
+	h.sendSuccess(w, report)
+}
+
+// ヘルパーメソッド
+
+// parsePagination reads offset/limit query parameters (limit defaults to 20, max 100)
+// offset・limitクエリパラメータを取得（limitの既定値は20、最大100）
+func parsePagination(r *http.Request) (int, int) {
+	offset := 0
+	limit := 20 // デフォルト
+
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
+			offset = parsedOffset
+		}
+	}
+
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 && parsedLimit <= 100 {
+			limit = parsedLimit
+		}
+	}
+
+	return offset, limit
+}
+
+// parseAsOf parses an as_of query value (date or RFC3339 timestamp)
+// as_ofパラメータを解析（日付のみの場合はその日の終わりを基準とする）
+func parseAsOf(value string) (time.Time, error) {
+	if asOf, err := time.Parse(time.RFC3339, value); err == nil {
+		return asOf, nil
+	}
+
+	asOf, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// 日付のみの場合は23:59:59に設定
+	return asOf.Add(23*time.Hour + 59*time.Minute + 59*time.Second), nil
+}
 
 // sendSuccess sends a successful API response
 // 成功APIレスポンスを送信
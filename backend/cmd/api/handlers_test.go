@@ -42,6 +42,14 @@ func (m *MockInventoryManager) Adjust(ctx context.Context, itemID, locationID st
 	return args.Error(0)
 }
 
+func (m *MockInventoryManager) ReverseTransaction(ctx context.Context, transactionID, reference, reason string) (*inventory.Transaction, error) {
+	args := m.Called(ctx, transactionID, reference, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*inventory.Transaction), args.Error(1)
+}
+
 func (m *MockInventoryManager) GetStock(ctx context.Context, itemID, locationID string) (*inventory.Stock, error) {
 	args := m.Called(ctx, itemID, locationID)
 	if args.Get(0) == nil {
@@ -63,6 +71,22 @@ func (m *MockInventoryManager) GetStockByLocation(ctx context.Context, locationI
 	return args.Get(0).([]inventory.Stock), args.Error(1)
 }
 
+func (m *MockInventoryManager) GetStockAsOf(ctx context.Context, itemID, locationID string, asOf time.Time) (*inventory.StockSnapshot, error) {
+	args := m.Called(ctx, itemID, locationID, asOf)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*inventory.StockSnapshot), args.Error(1)
+}
+
+func (m *MockInventoryManager) GetStockByLocationAsOf(ctx context.Context, locationID string, asOf time.Time) ([]inventory.StockSnapshot, error) {
+	args := m.Called(ctx, locationID, asOf)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]inventory.StockSnapshot), args.Error(1)
+}
+
 func (m *MockInventoryManager) GetHistory(ctx context.Context, itemID string, limit int) ([]inventory.Transaction, error) {
 	args := m.Called(ctx, itemID, limit)
 	if args.Get(0) == nil {
@@ -134,6 +158,59 @@ func setupTestHandler() (*Handlers, *MockInventoryManager) {
 	return handlers, mockManager
 }
 
+// MockItemInventoryManager は商品マスタ（小数桁数）を持つテスト用のモックマネージャー
+type MockItemInventoryManager struct {
+	MockInventoryManager
+}
+
+func (m *MockItemInventoryManager) CreateItem(ctx context.Context, item *inventory.Item) error {
+	args := m.Called(ctx, item)
+	return args.Error(0)
+}
+
+func (m *MockItemInventoryManager) GetItem(ctx context.Context, itemID string) (*inventory.Item, error) {
+	args := m.Called(ctx, itemID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*inventory.Item), args.Error(1)
+}
+
+func (m *MockItemInventoryManager) UpdateItem(ctx context.Context, item *inventory.Item) error {
+	args := m.Called(ctx, item)
+	return args.Error(0)
+}
+
+func (m *MockItemInventoryManager) DeleteItem(ctx context.Context, itemID string) error {
+	args := m.Called(ctx, itemID)
+	return args.Error(0)
+}
+
+func (m *MockItemInventoryManager) ListItems(ctx context.Context, filter inventory.ItemSearchFilter) (*inventory.ItemPage, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*inventory.ItemPage), args.Error(1)
+}
+
+func (m *MockItemInventoryManager) SearchItems(ctx context.Context, filter inventory.ItemSearchFilter) (*inventory.ItemPage, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*inventory.ItemPage), args.Error(1)
+}
+
+// setupItemTestHandler は小数桁数3の商品（kg単位）を持つハンドラーを作成
+func setupItemTestHandler() (*Handlers, *MockItemInventoryManager) {
+	mockManager := new(MockItemInventoryManager)
+	mockManager.On("GetItem", mock.Anything, "item-kg").Return(&inventory.Item{ID: "item-kg", QuantityPrecision: 3}, nil)
+	logger, _ := zap.NewDevelopment()
+	handlers := NewHandlers(mockManager, logger)
+	return handlers, mockManager
+}
+
 // =====================
 // ヘルスチェックテスト
 // =====================
@@ -167,7 +244,7 @@ func TestAddStock_Success(t *testing.T) {
 	reqBody := AddStockRequest{
 		ItemID:     "item-1",
 		LocationID: "loc-1",
-		Quantity:   100,
+		Quantity:   "100",
 		Reference:  "TEST-REF",
 	}
 	body, _ := json.Marshal(reqBody)
@@ -200,6 +277,48 @@ func TestAddStock_InvalidRequest(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, rec.Code)
 }
 
+func TestAddStock_FractionalQuantity(t *testing.T) {
+	tests := []struct {
+		name     string
+		quantity string
+		expected int64
+	}{
+		{name: "JSON数値", quantity: `1.5`, expected: 1500},
+		{name: "数値文字列", quantity: `"0.25"`, expected: 250},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handlers, mockManager := setupItemTestHandler()
+			mockManager.On("Add", mock.Anything, "item-kg", "loc-1", tt.expected, "KG-REF").Return(nil)
+
+			body := `{"item_id":"item-kg","location_id":"loc-1","quantity":` + tt.quantity + `,"reference":"KG-REF"}`
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/inventory/add", bytes.NewReader([]byte(body)))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			handlers.AddStock(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			mockManager.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAddStock_QuantityExceedsPrecision(t *testing.T) {
+	handlers, mockManager := setupItemTestHandler()
+
+	body := `{"item_id":"item-kg","location_id":"loc-1","quantity":1.2345,"reference":"KG-REF"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/inventory/add", bytes.NewReader([]byte(body)))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	handlers.AddStock(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockManager.AssertNotCalled(t, "Add", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 // =====================
 // 在庫削除テスト
 // =====================
@@ -212,7 +331,7 @@ func TestRemoveStock_Success(t *testing.T) {
 	reqBody := RemoveStockRequest{
 		ItemID:     "item-1",
 		LocationID: "loc-1",
-		Quantity:   50,
+		Quantity:   "50",
 		Reference:  "REMOVE-REF",
 	}
 	body, _ := json.Marshal(reqBody)
@@ -235,7 +354,7 @@ func TestRemoveStock_InsufficientStock(t *testing.T) {
 	reqBody := RemoveStockRequest{
 		ItemID:     "item-1",
 		LocationID: "loc-1",
-		Quantity:   1000,
+		Quantity:   "1000",
 		Reference:  "REMOVE-REF",
 	}
 	body, _ := json.Marshal(reqBody)
@@ -263,7 +382,7 @@ func TestTransferStock_Success(t *testing.T) {
 		ItemID:         "item-1",
 		FromLocationID: "loc-A",
 		ToLocationID:   "loc-B",
-		Quantity:       30,
+		Quantity:       "30",
 		Reference:      "TRANSFER-REF",
 	}
 	body, _ := json.Marshal(reqBody)
@@ -290,7 +409,7 @@ func TestAdjustStock_Success(t *testing.T) {
 	reqBody := AdjustStockRequest{
 		ItemID:      "item-1",
 		LocationID:  "loc-1",
-		NewQuantity: 200,
+		NewQuantity: "200",
 		Reference:   "ADJUST-REF",
 	}
 	body, _ := json.Marshal(reqBody)
@@ -351,6 +470,152 @@ func TestGetStock_NotFound(t *testing.T) {
 	mockManager.AssertExpectations(t)
 }
 
+func TestGetStock_AsOf(t *testing.T) {
+	handlers, mockManager := setupTestHandler()
+
+	asOf := time.Date(2024, 3, 31, 23, 59, 59, 0, time.UTC)
+	expectedSnapshot := &inventory.StockSnapshot{
+		ItemID:     "item-1",
+		LocationID: "loc-1",
+		Quantity:   80,
+		AsOf:       asOf,
+	}
+
+	mockManager.On("GetStockAsOf", mock.Anything, "item-1", "loc-1", asOf).Return(expectedSnapshot, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/inventory/item-1/loc-1?as_of=2024-03-31", nil)
+	req = mux.SetURLVars(req, map[string]string{
+		"itemId":     "item-1",
+		"locationId": "loc-1",
+	})
+	rec := httptest.NewRecorder()
+
+	handlers.GetStock(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockManager.AssertExpectations(t)
+	mockManager.AssertNotCalled(t, "GetStock", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetStock_AsOfNotFound(t *testing.T) {
+	handlers, mockManager := setupTestHandler()
+
+	asOf := time.Date(2024, 3, 31, 23, 59, 59, 0, time.UTC)
+	mockManager.On("GetStockAsOf", mock.Anything, "item-x", "loc-1", asOf).Return(nil, inventory.ErrItemNotFound)
+	mockManager.On("GetStockByLocationAsOf", mock.Anything, "loc-x", asOf).Return(nil, inventory.ErrLocationNotFound)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/inventory/item-x/loc-1?as_of=2024-03-31", nil)
+	req = mux.SetURLVars(req, map[string]string{
+		"itemId":     "item-x",
+		"locationId": "loc-1",
+	})
+	rec := httptest.NewRecorder()
+	handlers.GetStock(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/inventory/location/loc-x?as_of=2024-03-31", nil)
+	req = mux.SetURLVars(req, map[string]string{"locationId": "loc-x"})
+	rec = httptest.NewRecorder()
+	handlers.GetStockByLocation(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	mockManager.AssertExpectations(t)
+}
+
+func TestGetStock_InvalidAsOf(t *testing.T) {
+	handlers, _ := setupTestHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/inventory/item-1/loc-1?as_of=31-03-2024", nil)
+	req = mux.SetURLVars(req, map[string]string{
+		"itemId":     "item-1",
+		"locationId": "loc-1",
+	})
+	rec := httptest.NewRecorder()
+
+	handlers.GetStock(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestGetStock_FractionalQuantity(t *testing.T) {
+	handlers, mockManager := setupItemTestHandler()
+
+	mockManager.On("GetStock", mock.Anything, "item-kg", "loc-1").Return(&inventory.Stock{
+		ItemID:     "item-kg",
+		LocationID: "loc-1",
+		Quantity:   1500,
+		Reserved:   250,
+		Available:  1250,
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/inventory/item-kg/loc-1", nil)
+	req = mux.SetURLVars(req, map[string]string{
+		"itemId":     "item-kg",
+		"locationId": "loc-1",
+	})
+	rec := httptest.NewRecorder()
+
+	handlers.GetStock(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// 保存単位ではなく商品の小数桁数に従った10進数で返す
+	var response struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	decoder := json.NewDecoder(rec.Body)
+	decoder.UseNumber()
+	assert.NoError(t, decoder.Decode(&response))
+	assert.Equal(t, json.Number("1.500"), response.Data["quantity"])
+	assert.Equal(t, json.Number("0.250"), response.Data["reserved"])
+	assert.Equal(t, json.Number("1.250"), response.Data["available"])
+	mockManager.AssertExpectations(t)
+}
+
+// =====================
+// トランザクション取消テスト
+// =====================
+
+func TestReverseTransaction_Success(t *testing.T) {
+	handlers, mockManager := setupTestHandler()
+
+	originalID := "tx-1"
+	reversal := &inventory.Transaction{
+		ID:         "tx-2",
+		Type:       inventory.TransactionTypeOutbound,
+		ItemID:     "item-1",
+		Quantity:   10,
+		Reference:  "REV-REF",
+		ReversalOf: &originalID,
+	}
+
+	mockManager.On("ReverseTransaction", mock.Anything, "tx-1", "REV-REF", "誤入力").Return(reversal, nil)
+
+	body, _ := json.Marshal(ReverseTransactionRequest{Reference: "REV-REF", Reason: "誤入力"})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/inventory/transactions/tx-1/reverse", bytes.NewReader(body))
+	req = mux.SetURLVars(req, map[string]string{"transactionId": "tx-1"})
+	rec := httptest.NewRecorder()
+
+	handlers.ReverseTransaction(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockManager.AssertExpectations(t)
+}
+
+func TestReverseTransaction_AlreadyReversed(t *testing.T) {
+	handlers, mockManager := setupTestHandler()
+
+	mockManager.On("ReverseTransaction", mock.Anything, "tx-1", "", "").Return(nil, inventory.ErrTransactionAlreadyReversed)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/inventory/transactions/tx-1/reverse", nil)
+	req = mux.SetURLVars(req, map[string]string{"transactionId": "tx-1"})
+	rec := httptest.NewRecorder()
+
+	handlers.ReverseTransaction(rec, req)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	mockManager.AssertExpectations(t)
+}
+
 // =====================
 // 総在庫取得テスト
 // =====================
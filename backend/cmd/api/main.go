@@ -55,9 +55,58 @@ func main() {
 		AuditEnabled:       cfg.Inventory.AuditEnabled,
 		LowStockThreshold:  cfg.Inventory.LowStockThreshold,
 		AlertTimeout:       time.Duration(cfg.Inventory.AlertTimeoutHours) * time.Hour,
+		InTransitLocation:  cfg.Inventory.InTransitLocation,
+		CycleCountPolicy:   make(inventory.CycleCountPolicy, len(cfg.Inventory.CycleCount)),
+		BaseCurrency:       cfg.Inventory.Currency,
+	}
+	for class, policy := range cfg.Inventory.CycleCount {
+		inventoryConfig.CycleCountPolicy[class] = inventory.CycleCountClassPolicy{
+			IntervalDays:     policy.IntervalDays,
+			TolerancePercent: policy.TolerancePercent,
+		}
 	}
 
 	manager := inventory.NewManager(storage, nil, logger, inventoryConfig)
+	valuation := inventory.NewValuationEngine(storage, logger)
+	if err := valuation.SetCurrency(cfg.Inventory.Currency); err != nil {
+		logger.Fatal("在庫評価の通貨設定に失敗しました", zap.Error(err))
+	}
+	manager.SetValuationEngine(valuation)
+	analytics := inventory.NewAnalyticsEngine(storage, logger)
+	analytics.SetClassificationConfig(inventory.ClassificationConfig{
+		WindowDays:     cfg.Inventory.Classification.WindowDays,
+		ACutoffPercent: cfg.Inventory.Classification.ACutoffPercent,
+		BCutoffPercent: cfg.Inventory.Classification.BCutoffPercent,
+		BucketDays:     cfg.Inventory.Classification.BucketDays,
+		XMaxVariation:  cfg.Inventory.Classification.XMaxVariation,
+		YMaxVariation:  cfg.Inventory.Classification.YMaxVariation,
+	})
+	analytics.SetAgingConfig(inventory.AgingConfig{
+		BucketDays:    cfg.Inventory.Aging.BucketDays,
+		DeadStockDays: cfg.Inventory.Aging.DeadStockDays,
+	})
+	manager.SetAnalyticsEngine(analytics)
+	forecast := inventory.NewForecastEngine(storage, logger)
+	forecast.SetForecastConfig(inventory.ForecastConfig{
+		HistoryDays:         cfg.Inventory.Forecast.HistoryDays,
+		MovingAverageDays:   cfg.Inventory.Forecast.MovingAverageDays,
+		Alpha:               cfg.Inventory.Forecast.Alpha,
+		Beta:                cfg.Inventory.Forecast.Beta,
+		Gamma:               cfg.Inventory.Forecast.Gamma,
+		SeasonLength:        cfg.Inventory.Forecast.SeasonLength,
+		ServiceLevel:        cfg.Inventory.Forecast.ServiceLevel,
+		DefaultLeadTimeDays: cfg.Inventory.Forecast.DefaultLeadTimeDays,
+		OrderingCost:        cfg.Inventory.Forecast.OrderingCost,
+		HoldingCostRate:     cfg.Inventory.Forecast.HoldingCostRate,
+	})
+	reportScheduler := inventory.NewReportScheduler(storage, analytics, cfg.Inventory.ReportArchiveDir, logger)
+	service := &inventoryService{
+		Manager:             manager,
+		ValuationEngineImpl: valuation,
+		AnalyticsEngineImpl: analytics,
+		ForecastEngineImpl:  forecast,
+		ReportSchedulerImpl: reportScheduler,
+	}
 
 	// 認証サービス初期化
 	jwtConfig := auth.DefaultConfig()
@@ -67,7 +116,7 @@ func main() {
 	authMiddleware := auth.NewMiddleware(jwtService, logger)
 
 	// HTTPハンドラー設定
-	handlers := NewHandlers(manager, logger)
+	handlers := NewHandlers(service, logger)
 	router := setupRouter(handlers, authHandler, authMiddleware)
 
 	// HTTPサーバー設定
@@ -79,6 +128,25 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
+	// 定期処理（スナップショット・循環棚卸・定期レポート）
+	loopCtx, stopLoops := context.WithCancel(context.Background())
+	defer stopLoops()
+
+	// 時点在庫照会用スナップショットの定期作成
+	if cfg.Inventory.SnapshotIntervalHours > 0 {
+		go runSnapshotLoop(loopCtx, manager, time.Duration(cfg.Inventory.SnapshotIntervalHours)*time.Hour, logger)
+	}
+
+	// 循環棚卸タスクの日次生成
+	if cfg.Inventory.CycleCountEnabled {
+		go runCycleCountLoop(loopCtx, manager, logger)
+	}
+
+	// スケジュールに従ったレポートの定期生成
+	if cfg.Inventory.ReportScheduleEnabled {
+		go runReportScheduleLoop(loopCtx, reportScheduler, logger)
+	}
+
 	// グレースフルシャットダウン設定
 	go func() {
 		logger.Info("在庫管理APIサーバーを開始します", zap.Int("port", cfg.API.Port))
@@ -105,6 +173,85 @@ func main() {
 	logger.Info("サーバーが正常に停止しました")
 }
 
+// inventoryService bundles the manager with the valuation, analytics and forecast engines and the report scheduler
+// マネージャーと評価・分析・需要予測エンジン、レポートスケジューラーを束ね、ハンドラーから各インターフェースを利用可能にする
+type inventoryService struct {
+	*inventory.Manager
+	*inventory.ValuationEngineImpl
+	*inventory.AnalyticsEngineImpl
+	*inventory.ForecastEngineImpl
+	*inventory.ReportSchedulerImpl
+}
+
+// runSnapshotLoop periodically records stock snapshots for point-in-time queries
+// 時点在庫照会用の在庫スナップショットを定期的に記録
+func runSnapshotLoop(ctx context.Context, manager *inventory.Manager, interval time.Duration, logger *zap.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if _, err := manager.CreateStockSnapshots(ctx, now); err != nil {
+				logger.Error("在庫スナップショット作成に失敗しました", zap.Error(err))
+			}
+		}
+	}
+}
+
+// runCycleCountLoop generates the day's cycle count tasks at startup and then once a day
+// 循環棚卸タスクを起動時とその後1日1回生成
+func runCycleCountLoop(ctx context.Context, manager *inventory.Manager, logger *zap.Logger) {
+	generate := func(now time.Time) {
+		created, err := manager.GenerateDailyCycleCounts(ctx, now)
+		if err != nil {
+			logger.Error("循環棚卸タスク生成に失敗しました", zap.Error(err))
+			return
+		}
+		logger.Info("循環棚卸タスク生成完了", zap.Int("tasks", created))
+	}
+
+	// 再起動のたびに最初の生成が24時間遅れないよう、起動時にも当日分を生成（未完了のタスクがある商品は対象外）
+	generate(time.Now())
+
+	ticker := time.NewTicker(24 * time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			generate(now)
+		}
+	}
+}
+
+// runReportScheduleLoop generates the reports of due schedules every minute
+// 実行予定日時を迎えたスケジュールのレポートを1分ごとに生成
+func runReportScheduleLoop(ctx context.Context, scheduler *inventory.ReportSchedulerImpl, logger *zap.Logger) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			generated, err := scheduler.RunDueReportSchedules(ctx, now)
+			if err != nil {
+				logger.Error("定期レポート生成に失敗しました", zap.Error(err))
+				continue
+			}
+			if generated > 0 {
+				logger.Info("定期レポート生成完了", zap.Int("reports", generated))
+			}
+		}
+	}
+}
+
 // setupRouter sets up HTTP routes
 // HTTPルートを設定
 func setupRouter(handlers *Handlers, authHandler *auth.Handler, authMiddleware *auth.Middleware) *mux.Router {
@@ -134,6 +281,7 @@ func setupRouter(handlers *Handlers, authHandler *auth.Handler, authMiddleware *
 	protectedApi.HandleFunc("/inventory/transfer", handlers.TransferStock).Methods("POST")
 	protectedApi.HandleFunc("/inventory/adjust", handlers.AdjustStock).Methods("POST")
 	protectedApi.HandleFunc("/inventory/batch", handlers.BatchOperation).Methods("POST")
+	protectedApi.HandleFunc("/inventory/transactions/{transactionId}/reverse", handlers.ReverseTransaction).Methods("POST")
 
 	// 在庫照会（認証必須）
 	protectedApi.HandleFunc("/inventory/{itemId}/{locationId}", handlers.GetStock).Methods("GET")
@@ -156,6 +304,14 @@ func setupRouter(handlers *Handlers, authHandler *auth.Handler, authMiddleware *
 	protectedApi.HandleFunc("/items/{itemId}", handlers.GetItem).Methods("GET")
 	protectedApi.HandleFunc("/items/{itemId}", handlers.UpdateItem).Methods("PUT")
 	protectedApi.HandleFunc("/items/{itemId}", handlers.DeleteItem).Methods("DELETE")
+	protectedApi.HandleFunc("/items/{itemId}/usage", handlers.GetItemUsage).Methods("GET")
+	protectedApi.HandleFunc("/items/{itemId}/restore", handlers.RestoreItem).Methods("POST")
+	protectedApi.HandleFunc("/items/{itemId}/variants", handlers.SetVariantGroup).Methods("PUT")
+	protectedApi.HandleFunc("/items/{itemId}/variants", handlers.GetVariantGroup).Methods("GET")
+	protectedApi.HandleFunc("/items/{itemId}/barcodes", handlers.AddItemBarcode).Methods("POST")
+	protectedApi.HandleFunc("/items/{itemId}/barcodes", handlers.ListItemBarcodes).Methods("GET")
+	protectedApi.HandleFunc("/items/{itemId}/barcodes/{code}", handlers.RemoveItemBarcode).Methods("DELETE")
+	protectedApi.HandleFunc("/scan", handlers.ResolveScan).Methods("POST")
 
 	// ロケーション管理（認証必須）
 	protectedApi.HandleFunc("/locations", handlers.CreateLocation).Methods("POST")
@@ -163,6 +319,8 @@ func setupRouter(handlers *Handlers, authHandler *auth.Handler, authMiddleware *
 	protectedApi.HandleFunc("/locations/{locationId}", handlers.GetLocation).Methods("GET")
 	protectedApi.HandleFunc("/locations/{locationId}", handlers.UpdateLocation).Methods("PUT")
 	protectedApi.HandleFunc("/locations/{locationId}", handlers.DeleteLocation).Methods("DELETE")
+	protectedApi.HandleFunc("/locations/{locationId}/usage", handlers.GetLocationUsage).Methods("GET")
+	protectedApi.HandleFunc("/locations/{locationId}/restore", handlers.RestoreLocation).Methods("POST")
 
 	// ロット管理（認証必須）
 	protectedApi.HandleFunc("/lots", handlers.CreateLot).Methods("POST")
@@ -171,6 +329,62 @@ func setupRouter(handlers *Handlers, authHandler *auth.Handler, authMiddleware *
 	protectedApi.HandleFunc("/lots/expiring", handlers.GetExpiringLots).Methods("GET")
 	protectedApi.HandleFunc("/lots/expired", handlers.GetExpiredLots).Methods("GET")
 
+	// 移動指示（出荷→輸送中→入荷）
+	protectedApi.HandleFunc("/transfer-orders", handlers.CreateTransferOrder).Methods("POST")
+	protectedApi.HandleFunc("/transfer-orders", handlers.ListTransferOrders).Methods("GET")
+	protectedApi.HandleFunc("/transfer-orders/{orderId}", handlers.GetTransferOrder).Methods("GET")
+	protectedApi.HandleFunc("/transfer-orders/{orderId}/ship", handlers.ShipTransferOrder).Methods("POST")
+	protectedApi.HandleFunc("/transfer-orders/{orderId}/receive", handlers.ReceiveTransferOrder).Methods("POST")
+	protectedApi.HandleFunc("/transfer-orders/{orderId}/discrepancy", handlers.RecordTransferDiscrepancy).Methods("POST")
+
+	// 仕入先・発注（認証必須）
+	protectedApi.HandleFunc("/suppliers", handlers.CreateSupplier).Methods("POST")
+	protectedApi.HandleFunc("/suppliers", handlers.ListSuppliers).Methods("GET")
+	protectedApi.HandleFunc("/suppliers/{supplierId}", handlers.GetSupplier).Methods("GET")
+	protectedApi.HandleFunc("/suppliers/{supplierId}", handlers.UpdateSupplier).Methods("PUT")
+	protectedApi.HandleFunc("/purchase-orders", handlers.CreatePurchaseOrder).Methods("POST")
+	protectedApi.HandleFunc("/purchase-orders", handlers.ListPurchaseOrders).Methods("GET")
+	protectedApi.HandleFunc("/purchase-orders/{orderId}", handlers.GetPurchaseOrder).Methods("GET")
+	protectedApi.HandleFunc("/purchase-orders/{orderId}/receive", handlers.ReceivePurchaseOrder).Methods("POST")
+	protectedApi.HandleFunc("/purchase-orders/{orderId}/close", handlers.ClosePurchaseOrder).Methods("POST")
+	protectedApi.HandleFunc("/items/{itemId}/on-order", handlers.GetOnOrderQuantity).Methods("GET")
+
+	// 出荷指示・ウェーブピッキング（認証必須）
+	protectedApi.HandleFunc("/outbound-orders", handlers.CreateOutboundOrder).Methods("POST")
+	protectedApi.HandleFunc("/outbound-orders", handlers.ListOutboundOrders).Methods("GET")
+	protectedApi.HandleFunc("/outbound-orders/{orderId}", handlers.GetOutboundOrder).Methods("GET")
+	protectedApi.HandleFunc("/outbound-orders/{orderId}/allocate", handlers.AllocateOutboundOrder).Methods("POST")
+	protectedApi.HandleFunc("/outbound-orders/{orderId}/pick-list", handlers.GetPickList).Methods("GET")
+	protectedApi.HandleFunc("/outbound-orders/{orderId}/confirm-picks", handlers.ConfirmPicks).Methods("POST")
+	protectedApi.HandleFunc("/outbound-orders/{orderId}/cancel", handlers.CancelOutboundOrder).Methods("POST")
+	protectedApi.HandleFunc("/waves", handlers.CreateWave).Methods("POST")
+	protectedApi.HandleFunc("/waves/{waveId}", handlers.GetWave).Methods("GET")
+	protectedApi.HandleFunc("/waves/{waveId}/pick-list", handlers.GetWavePickList).Methods("GET")
+
+	// 部品表・キット組立（認証必須）
+	protectedApi.HandleFunc("/items/{itemId}/bom", handlers.SetBillOfMaterials).Methods("PUT")
+	protectedApi.HandleFunc("/items/{itemId}/bom", handlers.GetBillOfMaterials).Methods("GET")
+	protectedApi.HandleFunc("/items/{itemId}/bom/cost", handlers.GetKitCost).Methods("GET")
+	protectedApi.HandleFunc("/inventory/assemble", handlers.AssembleKit).Methods("POST")
+	protectedApi.HandleFunc("/inventory/disassemble", handlers.DisassembleKit).Methods("POST")
+
+	// 循環棚卸（認証必須）
+	protectedApi.HandleFunc("/cycle-counts/generate", handlers.GenerateCycleCountTasks).Methods("POST")
+	protectedApi.HandleFunc("/cycle-counts", handlers.ListCycleCountTasks).Methods("GET")
+	protectedApi.HandleFunc("/cycle-counts/{taskId}", handlers.GetCycleCountTask).Methods("GET")
+	protectedApi.HandleFunc("/cycle-counts/{taskId}/record", handlers.RecordCycleCount).Methods("POST")
+	protectedApi.HandleFunc("/cycle-counts/{taskId}/review", handlers.ReviewCycleCount).Methods("POST")
+
+	// 店舗補充・最小最大在庫（認証必須）
+	protectedApi.HandleFunc("/replenishment/policies", handlers.ListReplenishmentPolicies).Methods("GET")
+	protectedApi.HandleFunc("/replenishment/policies/{locationId}/{itemId}", handlers.SetReplenishmentPolicy).Methods("PUT")
+	protectedApi.HandleFunc("/replenishment/policies/{locationId}/{itemId}", handlers.DeleteReplenishmentPolicy).Methods("DELETE")
+	protectedApi.HandleFunc("/replenishment/proposals/generate", handlers.GenerateReplenishmentProposals).Methods("POST")
+	protectedApi.HandleFunc("/replenishment/proposals/execute", handlers.ExecuteReplenishmentProposals).Methods("POST")
+	protectedApi.HandleFunc("/replenishment/proposals", handlers.ListReplenishmentProposals).Methods("GET")
+	protectedApi.HandleFunc("/replenishment/proposals/{proposalId}", handlers.GetReplenishmentProposal).Methods("GET")
+	protectedApi.HandleFunc("/replenishment/proposals/{proposalId}/review", handlers.ReviewReplenishmentProposal).Methods("POST")
+
 	// 予約管理（認証必須）
 	protectedApi.HandleFunc("/inventory/reserve", handlers.ReserveStock).Methods("POST")
 	protectedApi.HandleFunc("/inventory/release-reservation", handlers.ReleaseReservation).Methods("POST")
@@ -185,10 +399,71 @@ func setupRouter(handlers *Handlers, authHandler *auth.Handler, authMiddleware *
 
 	// 在庫分析エンジン（認証必須）
 	protectedApi.HandleFunc("/analytics/abc/{locationId}", handlers.CalculateABCClassification).Methods("GET")
+	protectedApi.HandleFunc("/analytics/classifications/{locationId}", handlers.ClassifyItems).Methods("POST")
+	protectedApi.HandleFunc("/analytics/classifications/{locationId}", handlers.GetItemClassifications).Methods("GET")
+	protectedApi.HandleFunc("/analytics/classifications/{locationId}/items/{itemId}", handlers.GetItemClassificationHistory).Methods("GET")
 	protectedApi.HandleFunc("/analytics/turnover/{itemId}", handlers.GetTurnoverRate).Methods("GET")
+	protectedApi.HandleFunc("/analytics/kpis", handlers.GetKPIs).Methods("GET")
+	protectedApi.HandleFunc("/analytics/forecast/{itemId}/{locationId}", handlers.ForecastDemand).Methods("GET")
+	protectedApi.HandleFunc("/analytics/reorder-suggestions/{locationId}", handlers.GetReorderSuggestions).Methods("GET")
 	protectedApi.HandleFunc("/analytics/slow-moving/{locationId}", handlers.GetSlowMovingItems).Methods("GET")
+	protectedApi.HandleFunc("/analytics/aging/{locationId}", handlers.GetAgingReport).Methods("GET")
 	protectedApi.HandleFunc("/analytics/report/{locationId}", handlers.GenerateStockReport).Methods("GET")
 
+	// 会計期間・締め（マスタ更新権限が必要、締め解除は管理者API）
+	periodApi := protectedApi.PathPrefix("/periods").Subrouter()
+	periodApi.Use(authMiddleware.RequirePermission(auth.PermissionMasterWrite))
+	periodApi.HandleFunc("", handlers.CreateFiscalPeriod).Methods("POST")
+	periodApi.HandleFunc("", handlers.ListFiscalPeriods).Methods("GET")
+	periodApi.HandleFunc("/{periodId}", handlers.GetFiscalPeriod).Methods("GET")
+	periodApi.HandleFunc("/{periodId}/close", handlers.CloseFiscalPeriod).Methods("POST")
+	periodApi.HandleFunc("/{periodId}/balances", handlers.ListPeriodEndBalances).Methods("GET")
+	periodApi.HandleFunc("/{periodId}/events", handlers.ListFiscalPeriodEvents).Methods("GET")
+
+	// 為替レート（マスタ更新権限が必要）
+	rateApi := protectedApi.PathPrefix("/exchange-rates").Subrouter()
+	rateApi.Use(authMiddleware.RequirePermission(auth.PermissionMasterWrite))
+	rateApi.HandleFunc("", handlers.SetExchangeRate).Methods("POST")
+	rateApi.HandleFunc("", handlers.ListExchangeRates).Methods("GET")
+	rateApi.HandleFunc("/{currency}", handlers.GetExchangeRate).Methods("GET")
+
+	// カテゴリ属性スキーマ（マスタ更新権限が必要）
+	schemaApi := protectedApi.PathPrefix("/item-attribute-schemas").Subrouter()
+	schemaApi.Use(authMiddleware.RequirePermission(auth.PermissionMasterWrite))
+	schemaApi.HandleFunc("", handlers.ListCategoryAttributeSchemas).Methods("GET")
+	schemaApi.HandleFunc("/{category}", handlers.SetCategoryAttributeSchema).Methods("PUT")
+	schemaApi.HandleFunc("/{category}", handlers.GetCategoryAttributeSchema).Methods("GET")
+
+	// 商品・ロケーションマスタの一括取り込み・出力（マスタ更新権限が必要）
+	masterDataApi := protectedApi.PathPrefix("/master-data").Subrouter()
+	masterDataApi.Use(authMiddleware.RequirePermission(auth.PermissionMasterWrite))
+	masterDataApi.HandleFunc("/imports", handlers.ListMasterImportJobs).Methods("GET")
+	masterDataApi.HandleFunc("/imports/{jobId}", handlers.GetMasterImportJob).Methods("GET")
+	masterDataApi.HandleFunc("/{target}/import", handlers.StartMasterImport).Methods("POST")
+	masterDataApi.HandleFunc("/{target}/export", handlers.ExportMasterData).Methods("GET")
+
+	// 期首在庫の取り込み・一括取消（在庫更新権限が必要）
+	openingBalanceApi := protectedApi.PathPrefix("/opening-balances").Subrouter()
+	openingBalanceApi.Use(authMiddleware.RequirePermission(auth.PermissionInventoryWrite))
+	openingBalanceApi.HandleFunc("", handlers.ListOpeningBalanceLoads).Methods("GET")
+	openingBalanceApi.HandleFunc("/import", handlers.ImportOpeningBalances).Methods("POST")
+	openingBalanceApi.HandleFunc("/{loadId}", handlers.GetOpeningBalanceLoad).Methods("GET")
+	openingBalanceApi.HandleFunc("/{loadId}/rollback", handlers.RollbackOpeningBalanceLoad).Methods("POST")
+
+	// 定期レポート・レポートアーカイブ（レポート閲覧権限が必要、スケジュールの変更・実行はレポート更新権限も必要）
+	reportApi := protectedApi.PathPrefix("/reports").Subrouter()
+	reportApi.Use(authMiddleware.RequirePermission(auth.PermissionReportRead))
+	reportWrite := authMiddleware.RequirePermission(auth.PermissionReportWrite)
+	reportApi.Handle("/schedules", reportWrite(http.HandlerFunc(handlers.CreateReportSchedule))).Methods("POST")
+	reportApi.HandleFunc("/schedules", handlers.ListReportSchedules).Methods("GET")
+	reportApi.HandleFunc("/schedules/{scheduleId}", handlers.GetReportSchedule).Methods("GET")
+	reportApi.Handle("/schedules/{scheduleId}", reportWrite(http.HandlerFunc(handlers.UpdateReportSchedule))).Methods("PUT")
+	reportApi.Handle("/schedules/{scheduleId}", reportWrite(http.HandlerFunc(handlers.DeleteReportSchedule))).Methods("DELETE")
+	reportApi.Handle("/schedules/{scheduleId}/run", reportWrite(http.HandlerFunc(handlers.RunReportSchedule))).Methods("POST")
+	reportApi.HandleFunc("/archive", handlers.ListReportArchives).Methods("GET")
+	reportApi.HandleFunc("/archive/{archiveId}", handlers.GetReportArchive).Methods("GET")
+	reportApi.HandleFunc("/archive/{archiveId}/download", handlers.DownloadReportArchive).Methods("GET")
+
 	// ユーザー管理（認証必須）
 	protectedApi.HandleFunc("/users", authHandler.ListUsers).Methods("GET")
 	protectedApi.HandleFunc("/users", authHandler.CreateUser).Methods("POST")
@@ -203,6 +478,12 @@ func setupRouter(handlers *Handlers, authHandler *auth.Handler, authMiddleware *
 	protectedApi.HandleFunc("/audit", authHandler.ListAuditLogs).Methods("GET")
 	protectedApi.HandleFunc("/audit/export", authHandler.ExportAuditLogs).Methods("GET")
 
+	// 管理者API（ユーザー管理権限が必要）
+	adminApi := protectedApi.PathPrefix("/admin").Subrouter()
+	adminApi.Use(authMiddleware.RequirePermission(auth.PermissionUserManage))
+	adminApi.HandleFunc("/reconcile", handlers.ReconcileLedger).Methods("GET", "POST")
+	adminApi.HandleFunc("/periods/{periodId}/reopen", handlers.ReopenFiscalPeriod).Methods("POST")
+
 	// CORS設定（開発用）
 	router.Use(func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,378 @@
+package main
+
+import (
+	"context"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+// 数量の入出力（商品の小数桁数に従った10進数表記）
+
+// itemPrecision returns the quantity precision of an item (0 when items are not managed or the item is unknown)
+// 商品の数量の小数桁数を返す（商品管理がない場合・商品が存在しない場合は0）
+func (h *Handlers) itemPrecision(ctx context.Context, itemID string) (int, error) {
+	itemManager, ok := h.manager.(inventory.ItemManager)
+	if !ok || itemID == "" {
+		return 0, nil
+	}
+
+	item, err := itemManager.GetItem(ctx, itemID)
+	if err == inventory.ErrItemNotFound {
+		// 商品の存在チェックは在庫操作側のエラーに任せる
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return item.QuantityPrecision, nil
+}
+
+// requestQuantity converts a request quantity to stored units at the item's precision, sending an error response on failure
+// リクエストの数量を商品の小数桁数で保存単位に変換（失敗時はエラーレスポンスを送信してfalseを返す）
+func (h *Handlers) requestQuantity(w http.ResponseWriter, ctx context.Context, itemID string, quantity inventory.QuantityInput) (int64, bool) {
+	precision, err := h.itemPrecision(ctx, itemID)
+	if err != nil {
+		h.sendError(w, http.StatusInternalServerError, err.Error())
+		return 0, false
+	}
+
+	units, err := quantity.Units(precision)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return 0, false
+	}
+	return units, true
+}
+
+// quantityFormatter renders stored quantities as decimals, caching each item's precision for one response
+// 保存単位の数量を10進数に変換（1レスポンス内で商品ごとの小数桁数をキャッシュ）
+type quantityFormatter struct {
+	h          *Handlers
+	ctx        context.Context
+	precisions map[string]int
+}
+
+// newQuantityFormatter creates a formatter for one response
+// 1レスポンス分の数量フォーマッターを作成
+func (h *Handlers) newQuantityFormatter(ctx context.Context) *quantityFormatter {
+	return &quantityFormatter{h: h, ctx: ctx, precisions: make(map[string]int)}
+}
+
+// quantity pairs stored units with the item's precision
+// 保存単位の数量に商品の小数桁数を付与
+func (f *quantityFormatter) quantity(itemID string, units int64) inventory.DecimalQuantity {
+	precision, ok := f.precisions[itemID]
+	if !ok {
+		var err error
+		precision, err = f.h.itemPrecision(f.ctx, itemID)
+		if err != nil {
+			// 表示のみのため保存単位のまま返す
+			f.h.logger.Warn("商品の小数桁数取得に失敗", zap.String("item_id", itemID), zap.Error(err))
+		}
+		f.precisions[itemID] = precision
+	}
+	return inventory.DecimalQuantity{Units: units, Precision: precision}
+}
+
+// stockView renders a stock record with decimal quantities
+// 在庫を10進数の数量で表現
+type stockView struct {
+	*inventory.Stock
+	Quantity  inventory.DecimalQuantity `json:"quantity"`
+	Reserved  inventory.DecimalQuantity `json:"reserved"`
+	Available inventory.DecimalQuantity `json:"available"`
+}
+
+func (f *quantityFormatter) stock(stock *inventory.Stock) stockView {
+	return stockView{
+		Stock:     stock,
+		Quantity:  f.quantity(stock.ItemID, stock.Quantity),
+		Reserved:  f.quantity(stock.ItemID, stock.Reserved),
+		Available: f.quantity(stock.ItemID, stock.Available),
+	}
+}
+
+func (f *quantityFormatter) stocks(stocks []inventory.Stock) []stockView {
+	views := make([]stockView, len(stocks))
+	for i := range stocks {
+		views[i] = f.stock(&stocks[i])
+	}
+	return views
+}
+
+// stockSnapshotView renders a point-in-time stock with a decimal quantity
+// 時点在庫を10進数の数量で表現
+type stockSnapshotView struct {
+	*inventory.StockSnapshot
+	Quantity inventory.DecimalQuantity `json:"quantity"`
+}
+
+func (f *quantityFormatter) stockSnapshot(snapshot *inventory.StockSnapshot) stockSnapshotView {
+	return stockSnapshotView{StockSnapshot: snapshot, Quantity: f.quantity(snapshot.ItemID, snapshot.Quantity)}
+}
+
+func (f *quantityFormatter) stockSnapshots(snapshots []inventory.StockSnapshot) []stockSnapshotView {
+	views := make([]stockSnapshotView, len(snapshots))
+	for i := range snapshots {
+		views[i] = f.stockSnapshot(&snapshots[i])
+	}
+	return views
+}
+
+// transactionView renders a transaction with a decimal quantity
+// トランザクションを10進数の数量で表現
+type transactionView struct {
+	*inventory.Transaction
+	Quantity inventory.DecimalQuantity `json:"quantity"`
+}
+
+func (f *quantityFormatter) transaction(tx *inventory.Transaction) transactionView {
+	return transactionView{Transaction: tx, Quantity: f.quantity(tx.ItemID, tx.Quantity)}
+}
+
+func (f *quantityFormatter) transactions(txs []inventory.Transaction) []transactionView {
+	views := make([]transactionView, len(txs))
+	for i := range txs {
+		views[i] = f.transaction(&txs[i])
+	}
+	return views
+}
+
+// transferOrderView renders a transfer order with decimal quantities
+// 移動指示を10進数の数量で表現
+type transferOrderView struct {
+	*inventory.TransferOrder
+	Quantity            inventory.DecimalQuantity `json:"quantity"`
+	ReceivedQuantity    inventory.DecimalQuantity `json:"received_quantity"`
+	DiscrepancyQuantity inventory.DecimalQuantity `json:"discrepancy_quantity"`
+}
+
+func (f *quantityFormatter) transferOrder(order *inventory.TransferOrder) transferOrderView {
+	return transferOrderView{
+		TransferOrder:       order,
+		Quantity:            f.quantity(order.ItemID, order.Quantity),
+		ReceivedQuantity:    f.quantity(order.ItemID, order.ReceivedQuantity),
+		DiscrepancyQuantity: f.quantity(order.ItemID, order.DiscrepancyQuantity),
+	}
+}
+
+func (f *quantityFormatter) transferOrders(orders []inventory.TransferOrder) []transferOrderView {
+	views := make([]transferOrderView, len(orders))
+	for i := range orders {
+		views[i] = f.transferOrder(&orders[i])
+	}
+	return views
+}
+
+// cycleCountTaskView renders a cycle count task with decimal quantities
+// 循環棚卸タスクを10進数の数量で表現
+type cycleCountTaskView struct {
+	*inventory.CycleCountTask
+	ExpectedQuantity inventory.DecimalQuantity  `json:"expected_quantity"`
+	CountedQuantity  *inventory.DecimalQuantity `json:"counted_quantity"`
+	Variance         inventory.DecimalQuantity  `json:"variance"`
+}
+
+func (f *quantityFormatter) cycleCountTask(task *inventory.CycleCountTask) cycleCountTaskView {
+	view := cycleCountTaskView{
+		CycleCountTask:   task,
+		ExpectedQuantity: f.quantity(task.ItemID, task.ExpectedQuantity),
+		Variance:         f.quantity(task.ItemID, task.Variance),
+	}
+	if task.CountedQuantity != nil {
+		counted := f.quantity(task.ItemID, *task.CountedQuantity)
+		view.CountedQuantity = &counted
+	}
+	return view
+}
+
+func (f *quantityFormatter) cycleCountTasks(tasks []inventory.CycleCountTask) []cycleCountTaskView {
+	views := make([]cycleCountTaskView, len(tasks))
+	for i := range tasks {
+		views[i] = f.cycleCountTask(&tasks[i])
+	}
+	return views
+}
+
+// replenishmentPolicyView renders a replenishment policy with decimal quantities
+// 補充ポリシーを10進数の数量で表現
+type replenishmentPolicyView struct {
+	*inventory.ReplenishmentPolicy
+	MinQuantity inventory.DecimalQuantity `json:"min_quantity"`
+	MaxQuantity inventory.DecimalQuantity `json:"max_quantity"`
+}
+
+func (f *quantityFormatter) replenishmentPolicy(policy *inventory.ReplenishmentPolicy) replenishmentPolicyView {
+	return replenishmentPolicyView{
+		ReplenishmentPolicy: policy,
+		MinQuantity:         f.quantity(policy.ItemID, policy.MinQuantity),
+		MaxQuantity:         f.quantity(policy.ItemID, policy.MaxQuantity),
+	}
+}
+
+func (f *quantityFormatter) replenishmentPolicies(policies []inventory.ReplenishmentPolicy) []replenishmentPolicyView {
+	views := make([]replenishmentPolicyView, len(policies))
+	for i := range policies {
+		views[i] = f.replenishmentPolicy(&policies[i])
+	}
+	return views
+}
+
+// replenishmentProposalView renders a replenishment proposal with decimal quantities
+// 補充提案を10進数の数量で表現
+type replenishmentProposalView struct {
+	*inventory.ReplenishmentProposal
+	CurrentQuantity  inventory.DecimalQuantity `json:"current_quantity"`
+	MinQuantity      inventory.DecimalQuantity `json:"min_quantity"`
+	MaxQuantity      inventory.DecimalQuantity `json:"max_quantity"`
+	RequiredQuantity inventory.DecimalQuantity `json:"required_quantity"`
+	SourceAvailable  inventory.DecimalQuantity `json:"source_available"`
+	Quantity         inventory.DecimalQuantity `json:"quantity"`
+}
+
+func (f *quantityFormatter) replenishmentProposal(proposal *inventory.ReplenishmentProposal) replenishmentProposalView {
+	return replenishmentProposalView{
+		ReplenishmentProposal: proposal,
+		CurrentQuantity:       f.quantity(proposal.ItemID, proposal.CurrentQuantity),
+		MinQuantity:           f.quantity(proposal.ItemID, proposal.MinQuantity),
+		MaxQuantity:           f.quantity(proposal.ItemID, proposal.MaxQuantity),
+		RequiredQuantity:      f.quantity(proposal.ItemID, proposal.RequiredQuantity),
+		SourceAvailable:       f.quantity(proposal.ItemID, proposal.SourceAvailable),
+		Quantity:              f.quantity(proposal.ItemID, proposal.Quantity),
+	}
+}
+
+func (f *quantityFormatter) replenishmentProposals(proposals []inventory.ReplenishmentProposal) []replenishmentProposalView {
+	views := make([]replenishmentProposalView, len(proposals))
+	for i := range proposals {
+		views[i] = f.replenishmentProposal(&proposals[i])
+	}
+	return views
+}
+
+// purchaseOrderLineView renders a purchase order line with decimal quantities
+// 発注明細を10進数の数量で表現
+type purchaseOrderLineView struct {
+	*inventory.PurchaseOrderLine
+	Quantity         inventory.DecimalQuantity `json:"quantity"`
+	ReceivedQuantity inventory.DecimalQuantity `json:"received_quantity"`
+	OverReceived     inventory.DecimalQuantity `json:"over_received"`
+	UnderReceived    inventory.DecimalQuantity `json:"under_received"`
+}
+
+// purchaseOrderView renders a purchase order with decimal line quantities
+// 発注を10進数の明細数量で表現
+type purchaseOrderView struct {
+	*inventory.PurchaseOrder
+	Lines []purchaseOrderLineView `json:"lines"`
+}
+
+func (f *quantityFormatter) purchaseOrder(order *inventory.PurchaseOrder) purchaseOrderView {
+	view := purchaseOrderView{PurchaseOrder: order, Lines: make([]purchaseOrderLineView, len(order.Lines))}
+	for i := range order.Lines {
+		line := &order.Lines[i]
+		view.Lines[i] = purchaseOrderLineView{
+			PurchaseOrderLine: line,
+			Quantity:          f.quantity(line.ItemID, line.Quantity),
+			ReceivedQuantity:  f.quantity(line.ItemID, line.ReceivedQuantity),
+			OverReceived:      f.quantity(line.ItemID, line.OverReceived),
+			UnderReceived:     f.quantity(line.ItemID, line.UnderReceived),
+		}
+	}
+	return view
+}
+
+func (f *quantityFormatter) purchaseOrders(orders []inventory.PurchaseOrder) []purchaseOrderView {
+	views := make([]purchaseOrderView, len(orders))
+	for i := range orders {
+		views[i] = f.purchaseOrder(&orders[i])
+	}
+	return views
+}
+
+// outboundOrderLineView renders an outbound order line with decimal quantities
+// 出荷指示明細を10進数の数量で表現
+type outboundOrderLineView struct {
+	*inventory.OutboundOrderLine
+	Quantity            inventory.DecimalQuantity `json:"quantity"`
+	AllocatedQuantity   inventory.DecimalQuantity `json:"allocated_quantity"`
+	PickedQuantity      inventory.DecimalQuantity `json:"picked_quantity"`
+	BackorderedQuantity inventory.DecimalQuantity `json:"backordered_quantity"`
+}
+
+// pickAllocationView renders a pick allocation with decimal quantities
+// 引当明細を10進数の数量で表現
+type pickAllocationView struct {
+	*inventory.PickAllocation
+	Quantity       inventory.DecimalQuantity `json:"quantity"`
+	PickedQuantity inventory.DecimalQuantity `json:"picked_quantity"`
+}
+
+// outboundOrderView renders an outbound order with decimal line and allocation quantities
+// 出荷指示を10進数の明細・引当数量で表現
+type outboundOrderView struct {
+	*inventory.OutboundOrder
+	Lines       []outboundOrderLineView `json:"lines"`
+	Allocations []pickAllocationView    `json:"allocations"`
+}
+
+func (f *quantityFormatter) outboundOrder(order *inventory.OutboundOrder) outboundOrderView {
+	view := outboundOrderView{
+		OutboundOrder: order,
+		Lines:         make([]outboundOrderLineView, len(order.Lines)),
+		Allocations:   make([]pickAllocationView, len(order.Allocations)),
+	}
+	for i := range order.Lines {
+		line := &order.Lines[i]
+		view.Lines[i] = outboundOrderLineView{
+			OutboundOrderLine:   line,
+			Quantity:            f.quantity(line.ItemID, line.Quantity),
+			AllocatedQuantity:   f.quantity(line.ItemID, line.AllocatedQuantity),
+			PickedQuantity:      f.quantity(line.ItemID, line.PickedQuantity),
+			BackorderedQuantity: f.quantity(line.ItemID, line.BackorderedQuantity),
+		}
+	}
+	for i := range order.Allocations {
+		allocation := &order.Allocations[i]
+		view.Allocations[i] = pickAllocationView{
+			PickAllocation: allocation,
+			Quantity:       f.quantity(allocation.ItemID, allocation.Quantity),
+			PickedQuantity: f.quantity(allocation.ItemID, allocation.PickedQuantity),
+		}
+	}
+	return view
+}
+
+func (f *quantityFormatter) outboundOrders(orders []inventory.OutboundOrder) []outboundOrderView {
+	views := make([]outboundOrderView, len(orders))
+	for i := range orders {
+		views[i] = f.outboundOrder(&orders[i])
+	}
+	return views
+}
+
+// pickListEntryView renders a pick instruction with a decimal quantity
+// ピッキング指示を10進数の数量で表現
+type pickListEntryView struct {
+	*inventory.PickListEntry
+	Quantity inventory.DecimalQuantity `json:"quantity"`
+}
+
+// pickListView renders a pick list with decimal quantities
+// ピッキングリストを10進数の数量で表現
+type pickListView struct {
+	*inventory.PickList
+	Entries []pickListEntryView `json:"entries"`
+}
+
+func (f *quantityFormatter) pickList(pickList *inventory.PickList) pickListView {
+	view := pickListView{PickList: pickList, Entries: make([]pickListEntryView, len(pickList.Entries))}
+	for i := range pickList.Entries {
+		entry := &pickList.Entries[i]
+		view.Entries[i] = pickListEntryView{PickListEntry: entry, Quantity: f.quantity(entry.ItemID, entry.Quantity)}
+	}
+	return view
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/nemonet1337/zaiGoFramework/internal/config"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/storage"
+)
+
+// 差異が検出され、補正されなかった場合の終了コード
+const exitDriftDetected = 2
+
+func main() {
+	format := flag.String("format", "json", "出力形式 (json または csv)")
+	output := flag.String("output", "", "出力先ファイル (省略時は標準出力)")
+	fix := flag.Bool("fix", false, "差異を補正する調整トランザクションを記録する")
+	flag.Parse()
+
+	if *format != "json" && *format != "csv" {
+		log.Fatalf("無効な出力形式です: %s", *format)
+	}
+
+	log.Println("zaiGoFramework 台帳照合ツール")
+
+	// 設定読み込み
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal("設定読み込みに失敗しました:", err)
+	}
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatal("ログ初期化に失敗しました:", err)
+	}
+	defer logger.Sync()
+
+	// データベース接続
+	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
+		cfg.Database.Host, cfg.Database.Port, cfg.Database.User,
+		cfg.Database.Password, cfg.Database.DBName)
+
+	store, err := storage.NewPostgreSQLStorage(dsn, logger)
+	if err != nil {
+		log.Fatal("データベース接続に失敗しました:", err)
+	}
+	defer store.Close()
+
+	manager := inventory.NewManager(store, nil, logger, &inventory.Config{
+		AllowNegativeStock: cfg.Inventory.AllowNegativeStock,
+		DefaultLocation:    cfg.Inventory.DefaultLocation,
+		AuditEnabled:       cfg.Inventory.AuditEnabled,
+		LowStockThreshold:  cfg.Inventory.LowStockThreshold,
+		AlertTimeout:       time.Duration(cfg.Inventory.AlertTimeoutHours) * time.Hour,
+		InTransitLocation:  cfg.Inventory.InTransitLocation,
+	})
+
+	// 台帳照合実行
+	ctx := context.WithValue(context.Background(), "user_id", "reconcile")
+	report, err := manager.ReconcileLedger(ctx, *fix)
+	if err != nil {
+		log.Fatal("台帳照合に失敗しました:", err)
+	}
+
+	// レポート出力
+	var out io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			log.Fatal("出力ファイル作成に失敗しました:", err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	if err := writeReport(out, report, *format); err != nil {
+		log.Fatal("レポート出力に失敗しました:", err)
+	}
+
+	log.Printf("照合完了: 差異 %d 件, 補正 %d 件 (参照番号: %s)", report.DriftCount, report.FixedCount, report.Reference)
+
+	if report.DriftCount > report.FixedCount {
+		os.Exit(exitDriftDetected)
+	}
+}
+
+// writeReport 照合レポートを指定形式で出力
+func writeReport(w io.Writer, report *inventory.ReconciliationReport, format string) error {
+	if format == "csv" {
+		return report.WriteCSV(w)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
@@ -45,6 +45,72 @@ type InventoryConfig struct {
 	AuditEnabled       bool   `yaml:"audit_enabled"`
 	LowStockThreshold  int64  `yaml:"low_stock_threshold"`
 	AlertTimeoutHours  int    `yaml:"alert_timeout_hours"`
+	// 時点在庫照会用スナップショットの作成間隔（0で無効）
+	SnapshotIntervalHours int `yaml:"snapshot_interval_hours"`
+	// 倉庫間移動の輸送中在庫を保持する仮想ロケーション
+	InTransitLocation string `yaml:"in_transit_location"`
+	// 循環棚卸タスクの日次自動生成を有効化
+	CycleCountEnabled bool `yaml:"cycle_count_enabled"`
+	// ABC区分ごとの棚卸間隔と許容差異率
+	CycleCount map[string]CycleCountClassConfig `yaml:"cycle_count"`
+	// 出庫実績に基づくABC/XYZ分析の設定
+	Classification ClassificationConfig `yaml:"classification"`
+	// 需要予測と発注提案の設定
+	Forecast ForecastConfig `yaml:"forecast"`
+	// 在庫エイジング・滞留在庫の設定
+	Aging AgingConfig `yaml:"aging"`
+	// レポートの定期生成を有効化
+	ReportScheduleEnabled bool `yaml:"report_schedule_enabled"`
+	// 生成したレポートファイルの保存先ディレクトリ
+	ReportArchiveDir string `yaml:"report_archive_dir" env:"REPORT_ARCHIVE_DIR"`
+	// 在庫評価額の表示に使う通貨（ISO 4217、丸めルールを決定）
+	Currency string `yaml:"currency" env:"INVENTORY_CURRENCY"`
+}
+
+// ClassificationConfig ABC/XYZ分析設定
+type ClassificationConfig struct {
+	// 出庫実績の集計期間（日）
+	WindowDays int `yaml:"window_days"`
+	// A区分・B区分とする出庫金額の累積構成比の上限（%）
+	ACutoffPercent float64 `yaml:"a_cutoff_percent"`
+	BCutoffPercent float64 `yaml:"b_cutoff_percent"`
+	// 需要変動（XYZ）を測る集計単位（日）と変動係数の上限
+	BucketDays    int     `yaml:"bucket_days"`
+	XMaxVariation float64 `yaml:"x_max_variation"`
+	YMaxVariation float64 `yaml:"y_max_variation"`
+}
+
+// ForecastConfig 需要予測・発注提案設定
+type ForecastConfig struct {
+	// 予測に用いる出庫履歴の日数と移動平均の期間（日）
+	HistoryDays       int `yaml:"history_days"`
+	MovingAverageDays int `yaml:"moving_average_days"`
+	// 水準・トレンド・季節性の平滑化係数と季節周期（日）
+	Alpha        float64 `yaml:"alpha"`
+	Beta         float64 `yaml:"beta"`
+	Gamma        float64 `yaml:"gamma"`
+	SeasonLength int     `yaml:"season_length"`
+	// 安全在庫の目標サービス率（0〜1）
+	ServiceLevel float64 `yaml:"service_level"`
+	// 発注実績がない商品のリードタイム（日）
+	DefaultLeadTimeDays int `yaml:"default_lead_time_days"`
+	// 経済的発注量の算出に用いる1回あたりの発注費用と年間在庫保管費率
+	OrderingCost    float64 `yaml:"ordering_cost"`
+	HoldingCostRate float64 `yaml:"holding_cost_rate"`
+}
+
+// AgingConfig 在庫エイジング設定
+type AgingConfig struct {
+	// 受入からの経過日数区分の上限（日、昇順）
+	BucketDays []int `yaml:"bucket_days"`
+	// 滞留在庫とみなす移動なし日数
+	DeadStockDays int `yaml:"dead_stock_days"`
+}
+
+// CycleCountClassConfig ABC区分ごとの循環棚卸設定
+type CycleCountClassConfig struct {
+	IntervalDays     int     `yaml:"interval_days"`
+	TolerancePercent float64 `yaml:"tolerance_percent"`
 }
 
 // LogConfig ログ設定
@@ -73,11 +139,46 @@ func Load() (*Config, error) {
 			EnableAuth:   false,
 		},
 		Inventory: InventoryConfig{
-			AllowNegativeStock: false,
-			DefaultLocation:    "DEFAULT",
-			AuditEnabled:       true,
-			LowStockThreshold:  10,
-			AlertTimeoutHours:  24,
+			AllowNegativeStock:    false,
+			DefaultLocation:       "DEFAULT",
+			AuditEnabled:          true,
+			LowStockThreshold:     10,
+			AlertTimeoutHours:     24,
+			SnapshotIntervalHours: 24,
+			InTransitLocation:     "IN-TRANSIT",
+			CycleCountEnabled:     true,
+			CycleCount: map[string]CycleCountClassConfig{
+				"A": {IntervalDays: 30, TolerancePercent: 2},
+				"B": {IntervalDays: 90, TolerancePercent: 5},
+				"C": {IntervalDays: 365, TolerancePercent: 10},
+			},
+			Classification: ClassificationConfig{
+				WindowDays:     365,
+				ACutoffPercent: 80,
+				BCutoffPercent: 95,
+				BucketDays:     7,
+				XMaxVariation:  0.5,
+				YMaxVariation:  1.0,
+			},
+			Forecast: ForecastConfig{
+				HistoryDays:         182,
+				MovingAverageDays:   28,
+				Alpha:               0.3,
+				Beta:                0.1,
+				Gamma:               0.3,
+				SeasonLength:        7,
+				ServiceLevel:        0.95,
+				DefaultLeadTimeDays: 14,
+				OrderingCost:        100,
+				HoldingCostRate:     0.25,
+			},
+			Aging: AgingConfig{
+				BucketDays:    []int{30, 90, 180},
+				DeadStockDays: 180,
+			},
+			ReportScheduleEnabled: true,
+			ReportArchiveDir:      "data/reports",
+			Currency:              "JPY",
 		},
 		Log: LogConfig{
 			Level:      "info",
@@ -238,6 +339,71 @@ func (c *Config) validate() error {
 	if c.Inventory.LowStockThreshold < 0 {
 		return fmt.Errorf("低在庫閾値は0以上である必要があります")
 	}
+	if c.Inventory.SnapshotIntervalHours < 0 {
+		return fmt.Errorf("スナップショット作成間隔は0以上である必要があります")
+	}
+	if c.Inventory.InTransitLocation == "" {
+		return fmt.Errorf("輸送中ロケーションが指定されていません")
+	}
+	for class, policy := range c.Inventory.CycleCount {
+		if policy.IntervalDays <= 0 {
+			return fmt.Errorf("循環棚卸の棚卸間隔は1日以上である必要があります: %s", class)
+		}
+		if policy.TolerancePercent < 0 {
+			return fmt.Errorf("循環棚卸の許容差異率は0以上である必要があります: %s", class)
+		}
+	}
+
+	classification := c.Inventory.Classification
+	if classification.WindowDays <= 0 || classification.BucketDays <= 0 || classification.BucketDays > classification.WindowDays {
+		return fmt.Errorf("ABC/XYZ分析の集計期間・集計単位が不正です: %d日/%d日", classification.WindowDays, classification.BucketDays)
+	}
+	if classification.ACutoffPercent <= 0 || classification.ACutoffPercent > classification.BCutoffPercent || classification.BCutoffPercent > 100 {
+		return fmt.Errorf("ABC分析の累積構成比の閾値が不正です: A=%.1f%%, B=%.1f%%", classification.ACutoffPercent, classification.BCutoffPercent)
+	}
+	if classification.XMaxVariation < 0 || classification.XMaxVariation > classification.YMaxVariation {
+		return fmt.Errorf("XYZ分析の変動係数の閾値が不正です: X=%.2f, Y=%.2f", classification.XMaxVariation, classification.YMaxVariation)
+	}
+
+	forecast := c.Inventory.Forecast
+	if forecast.HistoryDays <= 0 || forecast.MovingAverageDays <= 0 || forecast.MovingAverageDays > forecast.HistoryDays {
+		return fmt.Errorf("需要予測の履歴日数・移動平均期間が不正です: %d日/%d日", forecast.HistoryDays, forecast.MovingAverageDays)
+	}
+	if forecast.SeasonLength <= 0 || forecast.SeasonLength > forecast.HistoryDays {
+		return fmt.Errorf("需要予測の季節周期が不正です: %d日", forecast.SeasonLength)
+	}
+	for name, value := range map[string]float64{"alpha": forecast.Alpha, "beta": forecast.Beta, "gamma": forecast.Gamma} {
+		if value < 0 || value > 1 {
+			return fmt.Errorf("需要予測の平滑化係数%sは0〜1の範囲で指定してください: %.2f", name, value)
+		}
+	}
+	if forecast.ServiceLevel <= 0 || forecast.ServiceLevel >= 1 {
+		return fmt.Errorf("安全在庫のサービス率は0より大きく1未満である必要があります: %.3f", forecast.ServiceLevel)
+	}
+	if forecast.DefaultLeadTimeDays <= 0 || forecast.OrderingCost < 0 || forecast.HoldingCostRate < 0 {
+		return fmt.Errorf("発注提案のリードタイム・費用設定が不正です: %d日, 発注費用=%.2f, 保管費率=%.2f",
+			forecast.DefaultLeadTimeDays, forecast.OrderingCost, forecast.HoldingCostRate)
+	}
+
+	aging := c.Inventory.Aging
+	if len(aging.BucketDays) == 0 {
+		return fmt.Errorf("在庫エイジングの経過日数区分が指定されていません")
+	}
+	for i, days := range aging.BucketDays {
+		if days <= 0 || (i > 0 && days <= aging.BucketDays[i-1]) {
+			return fmt.Errorf("在庫エイジングの経過日数区分は正の昇順で指定してください: %v", aging.BucketDays)
+		}
+	}
+	if aging.DeadStockDays <= 0 {
+		return fmt.Errorf("滞留在庫の判定日数は1日以上である必要があります: %d日", aging.DeadStockDays)
+	}
+
+	if c.Inventory.ReportArchiveDir == "" {
+		return fmt.Errorf("レポートの保存先ディレクトリが指定されていません")
+	}
+	if c.Inventory.Currency == "" {
+		return fmt.Errorf("在庫評価の通貨が指定されていません")
+	}
 
 	// ログ設定チェック
 	validLogLevels := map[string]bool{
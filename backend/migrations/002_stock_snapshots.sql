@@ -0,0 +1,30 @@
+-- 時点在庫照会のための台帳ビューと在庫スナップショット
+-- Ledger view and periodic stock snapshots for point-in-time queries
+
+-- 台帳エントリビュー：トランザクションを(商品, ロケーション, 増減数量)に展開
+-- 移動(transfer)はManager.Transferが出庫・入庫レッグを別途記録するため残高計算から除外する
+CREATE OR REPLACE VIEW ledger_entries AS
+SELECT id AS transaction_id, item_id, to_location AS location_id, quantity AS delta, created_at
+FROM transactions
+WHERE type IN ('inbound', 'adjust') AND to_location IS NOT NULL
+UNION ALL
+SELECT id AS transaction_id, item_id, from_location AS location_id, -quantity AS delta, created_at
+FROM transactions
+WHERE type = 'outbound' AND from_location IS NOT NULL;
+
+-- 在庫スナップショットテーブル
+CREATE TABLE stock_snapshots (
+    item_id VARCHAR(255) NOT NULL,
+    location_id VARCHAR(255) NOT NULL,
+    quantity BIGINT NOT NULL,
+    snapshot_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (item_id, location_id, snapshot_at),
+    FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE CASCADE,
+    FOREIGN KEY (location_id) REFERENCES locations(id) ON DELETE CASCADE
+);
+
+-- パフォーマンス向上のためのインデックス
+CREATE INDEX idx_stock_snapshots_location_at ON stock_snapshots(location_id, snapshot_at DESC);
+CREATE INDEX idx_transactions_to_location_created_at ON transactions(to_location, created_at);
+CREATE INDEX idx_transactions_from_location_created_at ON transactions(from_location, created_at);
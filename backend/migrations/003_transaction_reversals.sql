@@ -0,0 +1,9 @@
+-- 取引の取消（逆仕訳）のための相互参照
+-- Cross-references between a transaction and its reversal
+
+ALTER TABLE transactions
+    ADD COLUMN reversal_of VARCHAR(255) REFERENCES transactions(id) ON DELETE SET NULL,
+    ADD COLUMN reversed_by VARCHAR(255) REFERENCES transactions(id) ON DELETE SET NULL;
+
+-- 同一トランザクションの二重取消を防止
+CREATE UNIQUE INDEX idx_transactions_reversal_of ON transactions(reversal_of) WHERE reversal_of IS NOT NULL;
@@ -0,0 +1,16 @@
+-- 会計期間テーブル
+-- Fiscal periods; postings dated inside a closed period are locked
+CREATE TABLE fiscal_periods (
+    id VARCHAR(255) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'closed')),
+    closed_at TIMESTAMP,
+    closed_by VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (start_date <= end_date)
+);
+
+-- パフォーマンス向上のためのインデックス
+CREATE INDEX idx_fiscal_periods_dates ON fiscal_periods(start_date, end_date);
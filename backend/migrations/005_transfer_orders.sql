@@ -0,0 +1,31 @@
+-- 倉庫間移動指示（出荷→輸送中→入荷の2段階移動）
+-- Two-step transfer orders via a virtual in-transit location
+-- 輸送中ロケーション（inventory.in_transit_location）は初回出荷時に自動作成される
+
+-- 移動指示テーブル
+CREATE TABLE transfer_orders (
+    id VARCHAR(255) PRIMARY KEY,
+    item_id VARCHAR(255) NOT NULL,
+    from_location VARCHAR(255) NOT NULL,
+    to_location VARCHAR(255) NOT NULL,
+    quantity BIGINT NOT NULL CHECK (quantity > 0),
+    received_quantity BIGINT NOT NULL DEFAULT 0,
+    discrepancy_quantity BIGINT NOT NULL DEFAULT 0,
+    discrepancy_reason TEXT,
+    status VARCHAR(50) NOT NULL DEFAULT 'pending',
+    reference VARCHAR(500),
+    shipped_at TIMESTAMP,
+    completed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(255) NOT NULL DEFAULT 'system',
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    version BIGINT NOT NULL DEFAULT 1, -- 楽観的ロック用（出荷・入荷の二重計上防止）
+    FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE CASCADE,
+    FOREIGN KEY (from_location) REFERENCES locations(id),
+    FOREIGN KEY (to_location) REFERENCES locations(id),
+    CHECK (from_location <> to_location)
+);
+
+-- パフォーマンス向上のためのインデックス
+CREATE INDEX idx_transfer_orders_status ON transfer_orders(status, created_at DESC);
+CREATE INDEX idx_transfer_orders_item_id ON transfer_orders(item_id);
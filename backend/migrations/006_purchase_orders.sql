@@ -0,0 +1,51 @@
+-- 仕入先マスタと発注・入荷予定
+-- Supplier master, purchase orders and receiving against expected receipts
+
+-- 仕入先テーブル
+CREATE TABLE suppliers (
+    id VARCHAR(255) PRIMARY KEY,
+    name VARCHAR(500) NOT NULL,
+    contact_name VARCHAR(255),
+    email VARCHAR(255),
+    phone VARCHAR(100),
+    address TEXT,
+    lead_time_days INTEGER NOT NULL DEFAULT 0,
+    is_active BOOLEAN DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- 発注テーブル
+CREATE TABLE purchase_orders (
+    id VARCHAR(255) PRIMARY KEY,
+    supplier_id VARCHAR(255) NOT NULL,
+    status VARCHAR(50) NOT NULL DEFAULT 'open',
+    reference VARCHAR(500),
+    order_date TIMESTAMP NOT NULL DEFAULT NOW(),
+    notes TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(255) NOT NULL DEFAULT 'system',
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    version BIGINT NOT NULL DEFAULT 1, -- 楽観的ロック用（入荷の二重計上防止）
+    FOREIGN KEY (supplier_id) REFERENCES suppliers(id)
+);
+
+-- 発注明細テーブル
+CREATE TABLE purchase_order_lines (
+    id VARCHAR(255) PRIMARY KEY,
+    purchase_order_id VARCHAR(255) NOT NULL,
+    line_number INTEGER NOT NULL,
+    item_id VARCHAR(255) NOT NULL,
+    quantity BIGINT NOT NULL CHECK (quantity > 0),
+    received_quantity BIGINT NOT NULL DEFAULT 0,
+    unit_cost DECIMAL(12,4) NOT NULL DEFAULT 0,
+    expected_date DATE,
+    UNIQUE (purchase_order_id, line_number),
+    FOREIGN KEY (purchase_order_id) REFERENCES purchase_orders(id) ON DELETE CASCADE,
+    FOREIGN KEY (item_id) REFERENCES items(id)
+);
+
+-- パフォーマンス向上のためのインデックス
+CREATE INDEX idx_purchase_orders_status ON purchase_orders(status, created_at DESC);
+CREATE INDEX idx_purchase_orders_supplier_id ON purchase_orders(supplier_id);
+CREATE INDEX idx_purchase_order_lines_item_id ON purchase_order_lines(item_id);
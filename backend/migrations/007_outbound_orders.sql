@@ -0,0 +1,64 @@
+-- 出荷指示・引当・ウェーブピッキング
+-- Outbound orders with allocation, pick lists and wave picking
+
+-- ピッキング巡回順（未設定の場合はロケーションID順）
+ALTER TABLE locations ADD COLUMN pick_path VARCHAR(255) NOT NULL DEFAULT '';
+
+-- ウェーブテーブル
+CREATE TABLE waves (
+    id VARCHAR(255) PRIMARY KEY,
+    reference VARCHAR(500),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(255) NOT NULL DEFAULT 'system'
+);
+
+-- 出荷指示テーブル
+CREATE TABLE outbound_orders (
+    id VARCHAR(255) PRIMARY KEY,
+    customer VARCHAR(500) NOT NULL,
+    status VARCHAR(50) NOT NULL DEFAULT 'pending',
+    reference VARCHAR(500),
+    wave_id VARCHAR(255),
+    notes TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(255) NOT NULL DEFAULT 'system',
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    version BIGINT NOT NULL DEFAULT 1, -- 楽観的ロック用（引当・出庫の二重計上防止）
+    FOREIGN KEY (wave_id) REFERENCES waves(id) ON DELETE SET NULL
+);
+
+-- 出荷指示明細テーブル
+CREATE TABLE outbound_order_lines (
+    id VARCHAR(255) PRIMARY KEY,
+    order_id VARCHAR(255) NOT NULL,
+    line_number INTEGER NOT NULL,
+    item_id VARCHAR(255) NOT NULL,
+    quantity BIGINT NOT NULL CHECK (quantity > 0),
+    allocated_quantity BIGINT NOT NULL DEFAULT 0,
+    picked_quantity BIGINT NOT NULL DEFAULT 0,
+    backordered_quantity BIGINT NOT NULL DEFAULT 0,
+    UNIQUE (order_id, line_number),
+    FOREIGN KEY (order_id) REFERENCES outbound_orders(id) ON DELETE CASCADE,
+    FOREIGN KEY (item_id) REFERENCES items(id)
+);
+
+-- 引当テーブル（明細×ロケーション）
+CREATE TABLE pick_allocations (
+    id VARCHAR(255) PRIMARY KEY,
+    order_id VARCHAR(255) NOT NULL,
+    line_id VARCHAR(255) NOT NULL,
+    item_id VARCHAR(255) NOT NULL,
+    location_id VARCHAR(255) NOT NULL,
+    quantity BIGINT NOT NULL CHECK (quantity > 0),
+    picked_quantity BIGINT NOT NULL DEFAULT 0,
+    confirmed BOOLEAN NOT NULL DEFAULT false,
+    FOREIGN KEY (order_id) REFERENCES outbound_orders(id) ON DELETE CASCADE,
+    FOREIGN KEY (line_id) REFERENCES outbound_order_lines(id) ON DELETE CASCADE,
+    FOREIGN KEY (location_id) REFERENCES locations(id)
+);
+
+-- パフォーマンス向上のためのインデックス
+CREATE INDEX idx_outbound_orders_status ON outbound_orders(status, created_at DESC);
+CREATE INDEX idx_outbound_orders_wave_id ON outbound_orders(wave_id);
+CREATE INDEX idx_outbound_order_lines_item_id ON outbound_order_lines(item_id);
+CREATE INDEX idx_pick_allocations_order_id ON pick_allocations(order_id);
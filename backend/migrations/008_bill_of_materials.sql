@@ -0,0 +1,16 @@
+-- キット商品の部品表（BOM）
+-- Bill of materials for kit items assembled from component items
+
+CREATE TABLE bom_components (
+    kit_item_id VARCHAR(255) NOT NULL,
+    component_item_id VARCHAR(255) NOT NULL,
+    quantity BIGINT NOT NULL CHECK (quantity > 0),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (kit_item_id, component_item_id),
+    CHECK (kit_item_id <> component_item_id),
+    FOREIGN KEY (kit_item_id) REFERENCES items(id) ON DELETE CASCADE,
+    FOREIGN KEY (component_item_id) REFERENCES items(id)
+);
+
+-- 部品から使用先キットを逆引きするためのインデックス
+CREATE INDEX idx_bom_components_component ON bom_components(component_item_id);
@@ -0,0 +1,27 @@
+-- ABC区分に基づく循環棚卸タスク
+-- Cycle count tasks scheduled by ABC class
+
+CREATE TABLE cycle_count_tasks (
+    id VARCHAR(255) PRIMARY KEY,
+    location_id VARCHAR(255) NOT NULL,
+    item_id VARCHAR(255) NOT NULL,
+    abc_class VARCHAR(10) NOT NULL,
+    status VARCHAR(50) NOT NULL DEFAULT 'pending',
+    scheduled_date DATE NOT NULL,
+    expected_quantity BIGINT NOT NULL DEFAULT 0,
+    counted_quantity BIGINT CHECK (counted_quantity >= 0),
+    variance BIGINT NOT NULL DEFAULT 0,
+    variance_percent DECIMAL(9,4) NOT NULL DEFAULT 0,
+    counted_by VARCHAR(255),
+    counted_at TIMESTAMP,
+    reviewed_by VARCHAR(255),
+    reviewed_at TIMESTAMP,
+    review_note TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (location_id) REFERENCES locations(id),
+    FOREIGN KEY (item_id) REFERENCES items(id)
+);
+
+-- パフォーマンス向上のためのインデックス
+CREATE INDEX idx_cycle_count_tasks_location_item ON cycle_count_tasks(location_id, item_id, scheduled_date DESC);
+CREATE INDEX idx_cycle_count_tasks_status ON cycle_count_tasks(status, scheduled_date DESC);
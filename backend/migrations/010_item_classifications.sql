@@ -0,0 +1,22 @@
+-- 出庫実績に基づくABC/XYZ分類の履歴
+-- History of ABC/XYZ classifications computed from outbound consumption
+
+CREATE TABLE item_classifications (
+    id BIGSERIAL PRIMARY KEY,
+    item_id VARCHAR(255) NOT NULL,
+    location_id VARCHAR(255) NOT NULL,
+    abc_class VARCHAR(10) NOT NULL,
+    xyz_class VARCHAR(10) NOT NULL,
+    consumption_value DECIMAL(18,4) NOT NULL DEFAULT 0,
+    consumption_quantity BIGINT NOT NULL DEFAULT 0,
+    cumulative_percent DECIMAL(9,4) NOT NULL DEFAULT 0,
+    variation_coefficient DECIMAL(12,6) NOT NULL DEFAULT 0,
+    window_days INTEGER NOT NULL,
+    classified_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (item_id) REFERENCES items(id),
+    FOREIGN KEY (location_id) REFERENCES locations(id)
+);
+
+-- パフォーマンス向上のためのインデックス
+CREATE INDEX idx_item_classifications_location ON item_classifications(location_id, classified_at DESC);
+CREATE INDEX idx_item_classifications_item ON item_classifications(item_id, location_id, classified_at DESC);
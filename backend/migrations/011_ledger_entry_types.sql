@@ -0,0 +1,14 @@
+-- 台帳ビューに取引種別と消費フラグを追加（KPI集計で出庫と調整・移動レッグ・取消を区別するため）
+-- Expose the transaction type and a consumption flag on the ledger view so KPIs can tell real outbound from
+-- adjustments, transfer legs and reversals
+
+CREATE OR REPLACE VIEW ledger_entries AS
+SELECT id AS transaction_id, item_id, to_location AS location_id, quantity AS delta, created_at, type,
+    FALSE AS consumption
+FROM transactions
+WHERE type IN ('inbound', 'adjust') AND to_location IS NOT NULL
+UNION ALL
+SELECT id AS transaction_id, item_id, from_location AS location_id, -quantity AS delta, created_at, type,
+    (reversed_by IS NULL AND reversal_of IS NULL AND NOT COALESCE(metadata ? 'transfer', FALSE)) AS consumption
+FROM transactions
+WHERE type = 'outbound' AND from_location IS NOT NULL;
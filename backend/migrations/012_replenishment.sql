@@ -0,0 +1,48 @@
+-- 店舗補充（最小・最大在庫）ポリシーと補充提案
+-- Min/max replenishment policies and proposed store transfers
+
+CREATE TABLE replenishment_policies (
+    location_id VARCHAR(255) NOT NULL,
+    item_id VARCHAR(255) NOT NULL,
+    source_location_id VARCHAR(255) NOT NULL,
+    min_quantity BIGINT NOT NULL CHECK (min_quantity >= 0),
+    max_quantity BIGINT NOT NULL CHECK (max_quantity > 0),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (location_id, item_id),
+    FOREIGN KEY (location_id) REFERENCES locations(id),
+    FOREIGN KEY (item_id) REFERENCES items(id),
+    FOREIGN KEY (source_location_id) REFERENCES locations(id),
+    CHECK (max_quantity >= min_quantity),
+    CHECK (source_location_id <> location_id)
+);
+
+CREATE TABLE replenishment_proposals (
+    id VARCHAR(255) PRIMARY KEY,
+    location_id VARCHAR(255) NOT NULL,
+    source_location_id VARCHAR(255) NOT NULL,
+    item_id VARCHAR(255) NOT NULL,
+    status VARCHAR(50) NOT NULL DEFAULT 'pending',
+    current_quantity BIGINT NOT NULL DEFAULT 0,
+    min_quantity BIGINT NOT NULL DEFAULT 0,
+    max_quantity BIGINT NOT NULL DEFAULT 0,
+    required_quantity BIGINT NOT NULL DEFAULT 0,
+    source_available BIGINT NOT NULL DEFAULT 0,
+    quantity BIGINT NOT NULL CHECK (quantity > 0),
+    batch_id VARCHAR(255),
+    error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(255) NOT NULL,
+    reviewed_by VARCHAR(255),
+    reviewed_at TIMESTAMP,
+    executed_at TIMESTAMP,
+    FOREIGN KEY (location_id) REFERENCES locations(id),
+    FOREIGN KEY (source_location_id) REFERENCES locations(id),
+    FOREIGN KEY (item_id) REFERENCES items(id)
+);
+
+-- パフォーマンス向上のためのインデックス
+CREATE INDEX idx_replenishment_policies_source ON replenishment_policies(source_location_id, item_id);
+CREATE INDEX idx_replenishment_proposals_location ON replenishment_proposals(location_id, created_at DESC);
+CREATE INDEX idx_replenishment_proposals_status ON replenishment_proposals(status, created_at DESC);
@@ -0,0 +1,45 @@
+-- レポートの定期生成スケジュールとアーカイブ済みレポート
+-- Scheduled report generation and the report archive
+
+CREATE TABLE report_schedules (
+    id VARCHAR(255) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    cron_expression VARCHAR(255) NOT NULL,
+    timezone VARCHAR(100) NOT NULL DEFAULT '',
+    report_type VARCHAR(50) NOT NULL,
+    format VARCHAR(20) NOT NULL DEFAULT 'csv',
+    location_id VARCHAR(255) NOT NULL DEFAULT '',
+    category VARCHAR(100) NOT NULL DEFAULT '',
+    period_days INTEGER NOT NULL DEFAULT 0 CHECK (period_days >= 0),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    next_run_at TIMESTAMP,
+    last_run_at TIMESTAMP,
+    last_error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(255) NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- ファイル本体はアーカイブディレクトリに保存し、ここにはメタデータのみを記録する
+CREATE TABLE report_archives (
+    id VARCHAR(255) PRIMARY KEY,
+    schedule_id VARCHAR(255),
+    report_type VARCHAR(50) NOT NULL,
+    format VARCHAR(20) NOT NULL,
+    location_id VARCHAR(255) NOT NULL DEFAULT '',
+    category VARCHAR(100) NOT NULL DEFAULT '',
+    period_from TIMESTAMP NOT NULL,
+    period_to TIMESTAMP NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    file_path VARCHAR(500) NOT NULL,
+    size_bytes BIGINT NOT NULL DEFAULT 0,
+    checksum VARCHAR(64) NOT NULL,
+    generated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    generated_by VARCHAR(255) NOT NULL,
+    FOREIGN KEY (schedule_id) REFERENCES report_schedules(id) ON DELETE SET NULL
+);
+
+-- パフォーマンス向上のためのインデックス
+CREATE INDEX idx_report_schedules_due ON report_schedules(next_run_at) WHERE is_active = TRUE;
+CREATE INDEX idx_report_archives_schedule ON report_archives(schedule_id, generated_at DESC);
+CREATE INDEX idx_report_archives_type ON report_archives(report_type, generated_at DESC);
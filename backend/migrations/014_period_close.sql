@@ -0,0 +1,34 @@
+-- 会計期間の締め：期末残高と締め・締め解除の監査記録
+-- Period close: period-end balances and the close/reopen audit trail
+
+ALTER TABLE fiscal_periods ADD COLUMN created_by VARCHAR(255) NOT NULL DEFAULT 'system';
+
+-- 期末残高テーブル（締め時点の商品・ロケーション別数量と金額）
+CREATE TABLE period_end_balances (
+    period_id VARCHAR(255) NOT NULL,
+    item_id VARCHAR(255) NOT NULL,
+    location_id VARCHAR(255) NOT NULL,
+    quantity BIGINT NOT NULL,
+    unit_cost DECIMAL(12,4) NOT NULL DEFAULT 0,
+    value DECIMAL(18,4) NOT NULL DEFAULT 0,
+    PRIMARY KEY (period_id, item_id, location_id),
+    FOREIGN KEY (period_id) REFERENCES fiscal_periods(id) ON DELETE CASCADE,
+    FOREIGN KEY (item_id) REFERENCES items(id),
+    FOREIGN KEY (location_id) REFERENCES locations(id)
+);
+
+-- 会計期間の締め・締め解除の監査記録
+CREATE TABLE fiscal_period_events (
+    id VARCHAR(255) PRIMARY KEY,
+    period_id VARCHAR(255) NOT NULL,
+    action VARCHAR(20) NOT NULL CHECK (action IN ('close', 'reopen')),
+    reason TEXT NOT NULL DEFAULT '',
+    balance_count BIGINT NOT NULL DEFAULT 0,
+    performed_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    performed_by VARCHAR(255) NOT NULL,
+    FOREIGN KEY (period_id) REFERENCES fiscal_periods(id) ON DELETE CASCADE
+);
+
+-- パフォーマンス向上のためのインデックス
+CREATE INDEX idx_period_end_balances_location ON period_end_balances(period_id, location_id);
+CREATE INDEX idx_fiscal_period_events_period ON fiscal_period_events(period_id, performed_at);
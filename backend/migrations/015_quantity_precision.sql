@@ -0,0 +1,8 @@
+-- 商品ごとの数量の小数桁数（重量・容量で管理する商品）
+-- Per-item quantity precision for weight- and volume-based items
+--
+-- 在庫・トランザクション・予約などの数量は、商品の最小単位（10^-quantity_precision）の
+-- 整数としてBIGINT列に保存します。既存の商品は小数桁数0のため保存値は変わりません。
+
+ALTER TABLE items ADD COLUMN quantity_precision SMALLINT NOT NULL DEFAULT 0
+    CHECK (quantity_precision BETWEEN 0 AND 6);
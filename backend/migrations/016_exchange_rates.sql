@@ -0,0 +1,26 @@
+-- 外貨建て仕入単価と為替レート
+-- Multi-currency purchase costs and exchange rates
+--
+-- transactions.unit_cost は常に基準通貨建てで保存し、外貨建ての入庫は
+-- 仕入通貨・仕入通貨建ての単価・換算に使用した為替レートを併せて記録します。
+
+-- 為替レートテーブル（外貨1単位あたりの基準通貨額、適用開始日から次のレートまで有効）
+CREATE TABLE exchange_rates (
+    id VARCHAR(255) PRIMARY KEY,
+    currency VARCHAR(3) NOT NULL,
+    base_currency VARCHAR(3) NOT NULL,
+    rate DECIMAL(18,8) NOT NULL CHECK (rate > 0),
+    effective_date DATE NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(255) NOT NULL,
+    UNIQUE (currency, base_currency, effective_date),
+    CHECK (currency <> base_currency)
+);
+
+-- 入庫トランザクションの仕入通貨・換算前単価・為替レート（基準通貨建ての入庫はNULL）
+ALTER TABLE transactions ADD COLUMN cost_currency VARCHAR(3);
+ALTER TABLE transactions ADD COLUMN source_unit_cost DECIMAL(12,4);
+ALTER TABLE transactions ADD COLUMN exchange_rate DECIMAL(18,8);
+
+-- 発注単価の通貨（空の場合は基準通貨）
+ALTER TABLE purchase_orders ADD COLUMN currency VARCHAR(3) NOT NULL DEFAULT '';
@@ -0,0 +1,27 @@
+-- 商品のカスタム属性とバリアント
+-- Custom item attributes and variant groups
+--
+-- カスタム属性はJSONBで保存し、カテゴリ属性スキーマが定義されたカテゴリでは
+-- 商品の作成・更新時にアプリケーション側で型・必須・選択肢を検証します。
+
+-- 商品のカスタム属性（ブランド、寸法、危険物区分など）とバリアントの親商品
+ALTER TABLE items ADD COLUMN attributes JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE items ADD COLUMN parent_item_id VARCHAR(255) REFERENCES items(id);
+
+CREATE INDEX idx_items_parent_item_id ON items(parent_item_id);
+CREATE INDEX idx_items_attributes ON items USING GIN (attributes);
+
+-- カテゴリ属性スキーマテーブル（attributesは属性定義の配列）
+CREATE TABLE category_attribute_schemas (
+    category VARCHAR(255) PRIMARY KEY,
+    attributes JSONB NOT NULL DEFAULT '[]',
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- バリアントグループテーブル（axesはバリアント軸となる属性名の配列）
+CREATE TABLE item_variant_groups (
+    parent_item_id VARCHAR(255) PRIMARY KEY REFERENCES items(id),
+    axes JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
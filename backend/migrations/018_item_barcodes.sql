@@ -0,0 +1,17 @@
+-- 商品バーコード（JAN/EAN/UPC/GTIN）
+-- Item barcodes with packaging levels
+--
+-- バーコードは14桁に正規化したGTINを主キーとし、JAN-13とGTIN-14（先頭0）のように
+-- 桁数の異なる同一番号を重複登録できないようにします。
+
+CREATE TABLE item_barcodes (
+    gtin VARCHAR(14) PRIMARY KEY,
+    code VARCHAR(14) NOT NULL,
+    item_id VARCHAR(255) NOT NULL REFERENCES items(id),
+    type VARCHAR(10) NOT NULL CHECK (type IN ('JAN', 'EAN', 'UPC', 'GTIN')),
+    package_level VARCHAR(20) NOT NULL CHECK (package_level IN ('each', 'inner', 'case', 'pallet')),
+    units_per_package BIGINT NOT NULL CHECK (units_per_package > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_item_barcodes_item_id ON item_barcodes(item_id);
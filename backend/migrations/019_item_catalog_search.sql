@@ -0,0 +1,21 @@
+-- 商品カタログの検索・絞り込み・カーソルページング
+-- Item catalog search, filtering and cursor pagination
+--
+-- 商品検索のあいまい一致はpg_trgmのトライグラム類似度で判定します。
+-- 一覧は(並び順のキー, id)のキーセットでページングするため、主な並び順に複合インデックスを作成します。
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- 商品の有効フラグ
+ALTER TABLE items ADD COLUMN is_active BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- あいまい検索用のトライグラムインデックス
+CREATE INDEX idx_items_name_trgm ON items USING GIN (name gin_trgm_ops);
+CREATE INDEX idx_items_sku_trgm ON items USING GIN (sku gin_trgm_ops);
+CREATE INDEX idx_items_description_trgm ON items USING GIN (description gin_trgm_ops);
+
+-- キーセットページング・絞り込み用のインデックス
+CREATE INDEX idx_items_name_id ON items(name, id);
+CREATE INDEX idx_items_unit_cost_id ON items(unit_cost, id);
+CREATE INDEX idx_items_created_at_id ON items(created_at, id);
+CREATE INDEX idx_items_updated_at_id ON items(updated_at, id);
@@ -0,0 +1,53 @@
+-- 商品・ロケーションのアーカイブ（論理削除）
+-- Soft archiving of items and locations
+--
+-- 商品・ロケーションの削除はアーカイブ日時の設定で行い、行は削除しません。
+-- 行の物理削除で在庫・台帳・ロット・履歴が連鎖削除されないよう、参照制約をON DELETE RESTRICTに変更します。
+
+ALTER TABLE items ADD COLUMN archived_at TIMESTAMP;
+ALTER TABLE locations ADD COLUMN archived_at TIMESTAMP;
+
+-- 在庫
+ALTER TABLE stocks
+    DROP CONSTRAINT stocks_item_id_fkey,
+    ADD CONSTRAINT stocks_item_id_fkey FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE RESTRICT,
+    DROP CONSTRAINT stocks_location_id_fkey,
+    ADD CONSTRAINT stocks_location_id_fkey FOREIGN KEY (location_id) REFERENCES locations(id) ON DELETE RESTRICT;
+
+-- 台帳（ロケーションの削除でSET NULLになると移動元・移動先の履歴が失われるため）
+ALTER TABLE transactions
+    DROP CONSTRAINT transactions_item_id_fkey,
+    ADD CONSTRAINT transactions_item_id_fkey FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE RESTRICT,
+    DROP CONSTRAINT transactions_from_location_fkey,
+    ADD CONSTRAINT transactions_from_location_fkey FOREIGN KEY (from_location) REFERENCES locations(id) ON DELETE RESTRICT,
+    DROP CONSTRAINT transactions_to_location_fkey,
+    ADD CONSTRAINT transactions_to_location_fkey FOREIGN KEY (to_location) REFERENCES locations(id) ON DELETE RESTRICT;
+
+-- ロット
+ALTER TABLE lots
+    DROP CONSTRAINT lots_item_id_fkey,
+    ADD CONSTRAINT lots_item_id_fkey FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE RESTRICT;
+
+-- アラート
+ALTER TABLE stock_alerts
+    DROP CONSTRAINT stock_alerts_item_id_fkey,
+    ADD CONSTRAINT stock_alerts_item_id_fkey FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE RESTRICT,
+    DROP CONSTRAINT stock_alerts_location_id_fkey,
+    ADD CONSTRAINT stock_alerts_location_id_fkey FOREIGN KEY (location_id) REFERENCES locations(id) ON DELETE RESTRICT;
+
+-- 在庫スナップショット
+ALTER TABLE stock_snapshots
+    DROP CONSTRAINT stock_snapshots_item_id_fkey,
+    ADD CONSTRAINT stock_snapshots_item_id_fkey FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE RESTRICT,
+    DROP CONSTRAINT stock_snapshots_location_id_fkey,
+    ADD CONSTRAINT stock_snapshots_location_id_fkey FOREIGN KEY (location_id) REFERENCES locations(id) ON DELETE RESTRICT;
+
+-- 移動指示
+ALTER TABLE transfer_orders
+    DROP CONSTRAINT transfer_orders_item_id_fkey,
+    ADD CONSTRAINT transfer_orders_item_id_fkey FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE RESTRICT;
+
+-- 部品表
+ALTER TABLE bom_components
+    DROP CONSTRAINT bom_components_kit_item_id_fkey,
+    ADD CONSTRAINT bom_components_kit_item_id_fkey FOREIGN KEY (kit_item_id) REFERENCES items(id) ON DELETE RESTRICT;
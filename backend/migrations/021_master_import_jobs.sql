@@ -0,0 +1,26 @@
+-- 商品・ロケーションマスタの一括取り込みジョブ
+-- Background jobs for bulk item and location master imports
+
+CREATE TABLE master_import_jobs (
+    id VARCHAR(255) PRIMARY KEY,
+    target VARCHAR(50) NOT NULL,
+    format VARCHAR(20) NOT NULL,
+    mode VARCHAR(20) NOT NULL,
+    dry_run BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    total_rows INTEGER NOT NULL DEFAULT 0,
+    processed_rows INTEGER NOT NULL DEFAULT 0,
+    created_count INTEGER NOT NULL DEFAULT 0,
+    updated_count INTEGER NOT NULL DEFAULT 0,
+    error_count INTEGER NOT NULL DEFAULT 0,
+    -- 行エラー（最大1000件、件数はerror_countに全件を計上）
+    errors JSONB NOT NULL DEFAULT '[]',
+    error TEXT NOT NULL DEFAULT '',
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    started_at TIMESTAMP,
+    completed_at TIMESTAMP
+);
+
+-- パフォーマンス向上のためのインデックス
+CREATE INDEX idx_master_import_jobs_created ON master_import_jobs(created_at DESC);
@@ -0,0 +1,45 @@
+-- 旧システムから移行する期首在庫の取り込み
+-- Opening balance loads migrated from a previous system
+--
+-- 明細ごとの入庫トランザクションは移行基準日時で計上し、metadataのopening_balance_loadに取り込みIDを記録します。
+-- 一括取消では各入庫の取消トランザクションをreversal_idに記録します。
+
+CREATE TABLE opening_balance_loads (
+    id VARCHAR(255) PRIMARY KEY,
+    reference VARCHAR(255) NOT NULL,
+    as_of TIMESTAMP NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    line_count INTEGER NOT NULL DEFAULT 0,
+    total_value DECIMAL(18,4) NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    posted_at TIMESTAMP,
+    rolled_back_at TIMESTAMP,
+    rolled_back_by VARCHAR(255) NOT NULL DEFAULT '',
+    rollback_reason TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE opening_balance_lines (
+    load_id VARCHAR(255) NOT NULL,
+    row_number INTEGER NOT NULL,
+    item_id VARCHAR(255) NOT NULL,
+    location_id VARCHAR(255) NOT NULL,
+    quantity BIGINT NOT NULL CHECK (quantity > 0),
+    unit_cost DECIMAL(12,4) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    value DECIMAL(18,4) NOT NULL,
+    lot_number VARCHAR(255),
+    expiry_date TIMESTAMP,
+    transaction_id VARCHAR(255),
+    lot_id VARCHAR(255),
+    reversal_id VARCHAR(255),
+    PRIMARY KEY (load_id, row_number),
+    FOREIGN KEY (load_id) REFERENCES opening_balance_loads(id) ON DELETE CASCADE,
+    FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE RESTRICT,
+    FOREIGN KEY (location_id) REFERENCES locations(id) ON DELETE RESTRICT
+);
+
+-- パフォーマンス向上のためのインデックス
+CREATE INDEX idx_opening_balance_loads_created ON opening_balance_loads(created_at DESC);
+CREATE INDEX idx_opening_balance_lines_item_location ON opening_balance_lines(item_id, location_id);
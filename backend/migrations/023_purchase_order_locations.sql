@@ -0,0 +1,9 @@
+-- 発注の入荷先ロケーション
+-- Destination location of purchase orders
+--
+-- 発注残数量をロケーション単位で集計できるよう、発注ヘッダに入荷先ロケーションを保持します。
+-- 既存の発注はNULLのままとし、商品全体の発注残にのみ計上されます。
+
+ALTER TABLE purchase_orders ADD COLUMN location_id VARCHAR(255) REFERENCES locations(id);
+
+CREATE INDEX idx_purchase_orders_location_id ON purchase_orders(location_id);
@@ -0,0 +1,19 @@
+-- バッチ在庫操作の実行結果
+-- Results of batch inventory operations
+--
+-- バッチIDで実行結果を参照できるよう、操作・成功/失敗件数・操作ごとのエラーを保存します。
+-- 補充提案のbatch_idはこのテーブルのIDを参照します（既存データのため外部キーは設定しない）。
+
+CREATE TABLE batch_operations (
+    id VARCHAR(255) PRIMARY KEY,
+    status VARCHAR(20) NOT NULL,
+    operations JSONB NOT NULL DEFAULT '[]',
+    success_count INTEGER NOT NULL DEFAULT 0,
+    failure_count INTEGER NOT NULL DEFAULT 0,
+    errors JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP
+);
+
+-- パフォーマンス向上のためのインデックス
+CREATE INDEX idx_batch_operations_created ON batch_operations(created_at DESC);
@@ -44,6 +44,7 @@ const (
 	PermissionStocktakingRead Permission = "stocktaking:read"
 	PermissionStocktakingWrite Permission = "stocktaking:write"
 	PermissionReportRead      Permission = "report:read"
+	PermissionReportWrite     Permission = "report:write"
 	PermissionAuditRead       Permission = "audit:read"
 	PermissionUserManage      Permission = "user:manage"
 )
@@ -54,13 +55,13 @@ var RolePermissions = map[Role][]Permission{
 		PermissionInventoryRead, PermissionInventoryWrite,
 		PermissionMasterRead, PermissionMasterWrite,
 		PermissionStocktakingRead, PermissionStocktakingWrite,
-		PermissionReportRead, PermissionAuditRead, PermissionUserManage,
+		PermissionReportRead, PermissionReportWrite, PermissionAuditRead, PermissionUserManage,
 	},
 	RoleInventoryManager: {
 		PermissionInventoryRead, PermissionInventoryWrite,
 		PermissionMasterRead, PermissionMasterWrite,
 		PermissionStocktakingRead, PermissionStocktakingWrite,
-		PermissionReportRead,
+		PermissionReportRead, PermissionReportWrite,
 	},
 	RoleFieldOperator: {
 		PermissionInventoryRead, PermissionInventoryWrite,
@@ -0,0 +1,211 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// AgingConfig holds the receipt age buckets and the dead-stock threshold
+// 在庫エイジングの経過日数区分と滞留在庫の判定日数を保持
+type AgingConfig struct {
+	BucketDays    []int `yaml:"bucket_days"`     // 経過日数区分の上限（昇順、最後の上限を超える分は最終区分）
+	DeadStockDays int   `yaml:"dead_stock_days"` // 滞留在庫とみなす移動なし日数
+}
+
+// DefaultAgingConfig returns 0-30, 31-90, 91-180 and 181+ day buckets and a 180-day dead-stock threshold
+// デフォルトの区分（0-30、31-90、91-180、181日以上）と滞留在庫の判定日数（180日）を返す
+func DefaultAgingConfig() AgingConfig {
+	return AgingConfig{
+		BucketDays:    []int{30, 90, 180},
+		DeadStockDays: 180,
+	}
+}
+
+// SetAgingConfig sets the receipt age buckets and the default dead-stock threshold
+// 在庫エイジングの経過日数区分と滞留在庫の判定日数を設定
+func (a *AnalyticsEngineImpl) SetAgingConfig(config AgingConfig) {
+	a.aging = config
+}
+
+// GetAgingReport buckets on-hand quantity and value by receipt age and flags dead stock
+// 現在庫の数量と金額を受入からの経過日数で区分し、滞留在庫を判定
+//
+// 現在庫は先入先出で払い出された残りとみなし、新しい受入（入庫・移動入庫・増加調整）から順に
+// 割り当てて経過日数と受入単価を求めます。受入履歴で説明できない数量（台帳導入前の在庫など）は
+// 最も古い区分に商品マスタの単価で計上し、平均経過日数の計算からは除きます。
+// deadStockDaysの間に入出庫・調整のない在庫を滞留在庫とし、0の場合は設定値を使用します。
+func (a *AnalyticsEngineImpl) GetAgingReport(ctx context.Context, locationID string, deadStockDays int) (*AgingReport, error) {
+	if deadStockDays < 0 {
+		return nil, NewValidationError("dead_stock_days", "滞留在庫の判定日数は0以上である必要があります", fmt.Sprintf("%d", deadStockDays))
+	}
+	if deadStockDays == 0 {
+		deadStockDays = a.aging.DeadStockDays
+	}
+
+	positions, err := a.storage.ListStockPositions(ctx, locationID)
+	if err != nil {
+		return nil, NewStorageError("list_stock_positions", "在庫状況の取得に失敗しました", err)
+	}
+	layers, err := a.storage.ListReceiptLayers(ctx, locationID)
+	if err != nil {
+		return nil, NewStorageError("list_receipt_layers", "受入履歴の取得に失敗しました", err)
+	}
+
+	// 商品・ロケーションごとの受入（新しい順）
+	layersByStock := make(map[string][]ReceiptLayer)
+	for _, layer := range layers {
+		key := layer.ItemID + "|" + layer.LocationID
+		layersByStock[key] = append(layersByStock[key], layer)
+	}
+
+	asOf := time.Now()
+	report := &AgingReport{
+		LocationID:    locationID,
+		AsOf:          asOf,
+		DeadStockDays: deadStockDays,
+		Items:         []ItemAging{},
+		Locations:     []LocationAging{},
+	}
+
+	locationIndex := make(map[string]int)
+	for _, position := range positions {
+		aging := a.ageStock(position, layersByStock[position.ItemID+"|"+position.LocationID], asOf, deadStockDays)
+		report.Items = append(report.Items, aging)
+
+		index, ok := locationIndex[position.LocationID]
+		if !ok {
+			index = len(report.Locations)
+			locationIndex[position.LocationID] = index
+			report.Locations = append(report.Locations, LocationAging{
+				LocationID: position.LocationID,
+				Buckets:    a.newAgingBuckets(),
+			})
+		}
+		summary := &report.Locations[index]
+		summary.Quantity += aging.Quantity
+		summary.Value += aging.Value
+		for i, bucket := range aging.Buckets {
+			summary.Buckets[i].Quantity += bucket.Quantity
+			summary.Buckets[i].Value += bucket.Value
+		}
+		if aging.IsDeadStock {
+			summary.DeadStockItems++
+			summary.DeadStockQuantity += aging.Quantity
+			summary.ValueAtRisk += aging.Value
+		}
+
+		report.TotalValue += aging.Value
+		if aging.IsDeadStock {
+			report.ValueAtRisk += aging.Value
+		}
+	}
+
+	return report, nil
+}
+
+// ageStock allocates the on-hand quantity of a stock position to its receipts, newest first
+// 在庫の現在数量を新しい受入から順に割り当て、経過日数区分と滞留在庫を判定
+func (a *AnalyticsEngineImpl) ageStock(position StockPosition, layers []ReceiptLayer, asOf time.Time, deadStockDays int) ItemAging {
+	aging := ItemAging{
+		ItemID:            position.ItemID,
+		LocationID:        position.LocationID,
+		Quantity:          position.Quantity,
+		QuantityPrecision: position.QuantityPrecision,
+		Buckets:           a.newAgingBuckets(),
+		LastMovementAt:    position.LastMovementAt,
+	}
+
+	remaining := position.Quantity
+	var agedQuantity int64
+	var ageSum float64
+	for _, layer := range layers {
+		if remaining <= 0 {
+			break
+		}
+		quantity := layer.Quantity
+		if quantity > remaining {
+			quantity = remaining
+		}
+
+		unitCost := position.UnitCost
+		if layer.UnitCost != nil {
+			unitCost = *layer.UnitCost
+		}
+		ageDays := daysBetween(layer.ReceivedAt, asOf)
+		bucket := &aging.Buckets[a.agingBucketIndex(ageDays)]
+		bucket.Quantity += quantity
+		bucket.Value += unitCost.MulQuantity(quantity, position.QuantityPrecision)
+		aging.Value += unitCost.MulQuantity(quantity, position.QuantityPrecision)
+
+		agedQuantity += quantity
+		ageSum += float64(quantity) * float64(ageDays)
+		receivedAt := layer.ReceivedAt
+		aging.OldestReceiptAt = &receivedAt
+		remaining -= quantity
+	}
+
+	// 受入履歴で説明できない数量は最も古い区分に計上
+	if remaining > 0 {
+		bucket := &aging.Buckets[len(aging.Buckets)-1]
+		bucket.Quantity += remaining
+		bucket.Value += position.UnitCost.MulQuantity(remaining, position.QuantityPrecision)
+		aging.Value += position.UnitCost.MulQuantity(remaining, position.QuantityPrecision)
+	}
+
+	if agedQuantity > 0 {
+		aging.AverageAgeDays = ageSum / float64(agedQuantity)
+	}
+
+	if position.LastMovementAt != nil {
+		days := daysBetween(*position.LastMovementAt, asOf)
+		aging.DaysSinceMovement = &days
+		aging.IsDeadStock = days >= deadStockDays
+	} else {
+		aging.IsDeadStock = true
+	}
+
+	return aging
+}
+
+// newAgingBuckets creates empty buckets from the configured day bounds
+// 設定された経過日数の上限から空の区分を作成
+func (a *AnalyticsEngineImpl) newAgingBuckets() []AgingBucket {
+	buckets := make([]AgingBucket, 0, len(a.aging.BucketDays)+1)
+	minDays := 0
+	for _, bound := range a.aging.BucketDays {
+		maxDays := bound
+		buckets = append(buckets, AgingBucket{
+			Label:   fmt.Sprintf("%d-%d", minDays, maxDays),
+			MinDays: minDays,
+			MaxDays: &maxDays,
+		})
+		minDays = bound + 1
+	}
+	buckets = append(buckets, AgingBucket{
+		Label:   fmt.Sprintf("%d+", minDays),
+		MinDays: minDays,
+	})
+	return buckets
+}
+
+// agingBucketIndex returns the index of the bucket that contains the given age
+// 経過日数が属する区分の位置を返す
+func (a *AnalyticsEngineImpl) agingBucketIndex(ageDays int) int {
+	for i, bound := range a.aging.BucketDays {
+		if ageDays <= bound {
+			return i
+		}
+	}
+	return len(a.aging.BucketDays)
+}
+
+// daysBetween returns the number of whole days elapsed from one time to another (never negative)
+// 2つの日時の間の経過日数（端数切り捨て、負の場合は0）を返す
+func daysBetween(from, to time.Time) int {
+	days := int(to.Sub(from).Hours() / 24)
+	if days < 0 {
+		return 0
+	}
+	return days
+}
@@ -0,0 +1,77 @@
+package inventory
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+// DefaultItemPageSize is the number of items returned per page when no limit is given
+// 件数の指定がない場合の商品一覧の1ページの件数
+const DefaultItemPageSize = 20
+
+// MaxItemPageSize is the largest number of items returned per page
+// 商品一覧の1ページの件数の上限
+const MaxItemPageSize = 100
+
+// ItemSortField represents the key items are ordered by in lists and searches
+// 商品一覧・検索の並び順のキーを表現
+type ItemSortField string
+
+const (
+	ItemSortName      ItemSortField = "name"       // 商品名
+	ItemSortSKU       ItemSortField = "sku"        // SKU
+	ItemSortUnitCost  ItemSortField = "unit_cost"  // 単価
+	ItemSortCreatedAt ItemSortField = "created_at" // 作成日時
+	ItemSortUpdatedAt ItemSortField = "updated_at" // 更新日時
+	ItemSortRelevance ItemSortField = "relevance"  // 検索キーワードとの類似度（検索時のみ）
+)
+
+// IsValid reports whether the sort field is one of the supported keys
+// 並び順のキーが対応しているものかを判定
+func (f ItemSortField) IsValid() bool {
+	switch f {
+	case ItemSortName, ItemSortSKU, ItemSortUnitCost, ItemSortCreatedAt, ItemSortUpdatedAt, ItemSortRelevance:
+		return true
+	}
+	return false
+}
+
+// ItemPage represents one page of an item list or search
+// 商品一覧・検索の1ページを表現
+type ItemPage struct {
+	Items      []Item `json:"items"`                 // 商品
+	TotalCount int64  `json:"total_count"`           // 条件に一致する全件数（ページに依存しない）
+	NextCursor string `json:"next_cursor,omitempty"` // 次ページのカーソル（最終ページでは空）
+}
+
+// ItemCursor is the keyset position encoded in an opaque item page cursor
+// 商品一覧の不透明なカーソルに格納するキーセット位置
+//
+// 並び順のキーの値と商品IDの組で前ページの最後の商品を表し、並び順が異なるカーソルは使用できません。
+type ItemCursor struct {
+	Sort ItemSortField `json:"s"` // 並び順のキー
+	Desc bool          `json:"d"` // 降順かどうか
+	Key  string        `json:"k"` // 最後の商品の並び順のキーの値（文字列表現）
+	ID   string        `json:"i"` // 最後の商品ID
+}
+
+// EncodeItemCursor encodes a keyset position as an opaque URL-safe string
+// キーセット位置をURLで使用できる不透明な文字列に変換
+func EncodeItemCursor(cursor ItemCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeItemCursor decodes an opaque cursor produced by EncodeItemCursor
+// EncodeItemCursorで生成したカーソルを復元
+func DecodeItemCursor(value string) (*ItemCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, NewValidationError("cursor", "無効なカーソルです", value)
+	}
+	var cursor ItemCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == "" || !cursor.Sort.IsValid() {
+		return nil, NewValidationError("cursor", "無効なカーソルです", value)
+	}
+	return &cursor, nil
+}
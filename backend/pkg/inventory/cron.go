@@ -0,0 +1,195 @@
+package inventory
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSchedule is a parsed five-field cron expression (minute hour day-of-month month day-of-week)
+// 5項目（分 時 日 月 曜日）のcron式を解析したスケジュール
+//
+// 各項目は「*」「5」「1-5」「*/15」「1,15」の形式に対応し、日の項目には月末を表す「L」を指定できます。
+// 日と曜日の両方を指定した場合は、標準のcronと同様にいずれかに一致した日に実行します。
+type CronSchedule struct {
+	minute         uint64 // 分（0〜59）のビット集合
+	hour           uint64 // 時（0〜23）のビット集合
+	dayOfMonth     uint64 // 日（1〜31）のビット集合
+	month          uint64 // 月（1〜12）のビット集合
+	dayOfWeek      uint64 // 曜日（0〜6、日曜が0）のビット集合
+	lastDayOfMonth bool   // 月末日に実行（日の項目のL）
+	dayRestricted  bool   // 日の項目が「*」以外
+	weekRestricted bool   // 曜日の項目が「*」以外
+}
+
+// cronMacros maps the predefined schedules to their five-field expressions
+// 定義済みスケジュールと5項目のcron式の対応
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@monthend": "0 0 L * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseCronSchedule parses a five-field cron expression or one of the @daily style macros
+// 5項目のcron式または@daily形式の定義済みスケジュールを解析
+func ParseCronSchedule(expression string) (*CronSchedule, error) {
+	spec := strings.TrimSpace(expression)
+	if macro, ok := cronMacros[spec]; ok {
+		spec = macro
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, NewValidationError("cron_expression", "cron式は「分 時 日 月 曜日」の5項目で指定してください", expression)
+	}
+
+	schedule := &CronSchedule{
+		dayRestricted:  fields[2] != "*",
+		weekRestricted: fields[4] != "*",
+	}
+
+	var err error
+	if schedule.minute, err = parseCronField(fields[0], 0, 59); err != nil {
+		return nil, cronFieldError(expression, "分", err)
+	}
+	if schedule.hour, err = parseCronField(fields[1], 0, 23); err != nil {
+		return nil, cronFieldError(expression, "時", err)
+	}
+	if schedule.month, err = parseCronField(fields[3], 1, 12); err != nil {
+		return nil, cronFieldError(expression, "月", err)
+	}
+
+	// 日の項目から月末指定（L）を取り出す
+	var days []string
+	for _, part := range strings.Split(fields[2], ",") {
+		if part == "L" {
+			schedule.lastDayOfMonth = true
+			continue
+		}
+		days = append(days, part)
+	}
+	if len(days) > 0 {
+		if schedule.dayOfMonth, err = parseCronField(strings.Join(days, ","), 1, 31); err != nil {
+			return nil, cronFieldError(expression, "日", err)
+		}
+	}
+
+	// 曜日は7も日曜として扱う
+	if schedule.dayOfWeek, err = parseCronField(fields[4], 0, 7); err != nil {
+		return nil, cronFieldError(expression, "曜日", err)
+	}
+	if schedule.dayOfWeek&(1<<7) != 0 {
+		schedule.dayOfWeek = schedule.dayOfWeek&^(1<<7) | 1
+	}
+
+	return schedule, nil
+}
+
+// Next returns the first time after the given time that matches the schedule, in the given time's location
+// 指定日時より後でスケジュールに一致する最初の日時を返す（指定日時のタイムゾーンで評価）
+//
+// 5年以内に一致する日時がない場合（2月30日など）はゼロ値を返します。
+func (c *CronSchedule) Next(after time.Time) time.Time {
+	loc := after.Location()
+	t := time.Date(after.Year(), after.Month(), after.Day(), after.Hour(), after.Minute(), 0, 0, loc).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !c.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
+
+// matchDay reports whether the day of t matches the day-of-month and day-of-week fields
+// 日付が日・曜日の項目に一致するか判定
+func (c *CronSchedule) matchDay(t time.Time) bool {
+	dayMatch := c.dayOfMonth&(1<<uint(t.Day())) != 0 || (c.lastDayOfMonth && t.AddDate(0, 0, 1).Day() == 1)
+	weekMatch := c.dayOfWeek&(1<<uint(t.Weekday())) != 0
+
+	switch {
+	case c.dayRestricted && c.weekRestricted:
+		return dayMatch || weekMatch
+	case c.weekRestricted:
+		return weekMatch
+	case c.dayRestricted:
+		return dayMatch
+	default:
+		return true
+	}
+}
+
+// parseCronField parses a comma-separated list of values, ranges and steps into a bit set
+// カンマ区切りの値・範囲・間隔指定をビット集合に変換
+func parseCronField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			parsed, err := strconv.Atoi(part[i+1:])
+			if err != nil || parsed <= 0 {
+				return 0, fmt.Errorf("間隔が不正です: %s", part)
+			}
+			rangePart, step = part[:i], parsed
+		}
+
+		start, end := min, max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if start, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, fmt.Errorf("範囲が不正です: %s", part)
+			}
+			if end, err = strconv.Atoi(bounds[1]); err != nil {
+				return 0, fmt.Errorf("範囲が不正です: %s", part)
+			}
+		default:
+			value, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return 0, fmt.Errorf("値が不正です: %s", part)
+			}
+			start = value
+			if step == 1 {
+				end = value
+			}
+		}
+
+		if start < min || end > max || start > end {
+			return 0, fmt.Errorf("%d〜%dの範囲で指定してください: %s", min, max, part)
+		}
+		for value := start; value <= end; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+	return bits, nil
+}
+
+// cronFieldError wraps a field parse error as a validation error of the expression
+// 項目の解析エラーをcron式のバリデーションエラーに変換
+func cronFieldError(expression, field string, err error) error {
+	return NewValidationError("cron_expression", fmt.Sprintf("cron式の%sの項目が不正です（%v）", field, err), expression)
+}
@@ -64,6 +64,142 @@ var (
 	// ErrInsufficientReservation is returned when trying to release more than reserved
 	// 予約量を超えて解除しようとした場合のエラー
 	ErrInsufficientReservation = errors.New("予約量が不足しています")
+
+	// ErrTransactionNotFound is returned when a transaction doesn't exist
+	// トランザクションが存在しない場合のエラー
+	ErrTransactionNotFound = errors.New("トランザクションが見つかりません")
+
+	// ErrTransactionAlreadyReversed is returned when reversing a transaction that was already reversed
+	// 既に取消済みのトランザクションを取り消そうとした場合のエラー
+	ErrTransactionAlreadyReversed = errors.New("トランザクションは既に取消済みです")
+
+	// ErrReversalNotReversible is returned when trying to reverse a reversal entry
+	// 取消トランザクション自体を取り消そうとした場合のエラー
+	ErrReversalNotReversible = errors.New("取消トランザクションは取り消せません")
+
+	// ErrTransferLegNotReversible is returned when trying to reverse an outbound or inbound leg of a transfer on its own
+	// 移動の出庫・入庫レッグを単独で取り消そうとした場合のエラー（移動トランザクションを取り消す）
+	ErrTransferLegNotReversible = errors.New("移動の出庫・入庫レッグは単独で取り消せません")
+
+	// ErrPeriodClosed is returned when posting into a closed fiscal period
+	// 締め済みの会計期間に計上しようとした場合のエラー
+	ErrPeriodClosed = errors.New("会計期間は締め済みです")
+
+	// ErrPostingBeforeSnapshot is returned when backdating a posting to or before a stock snapshot of the location
+	// 在庫スナップショット作成済みの日時以前に過去日付で計上しようとした場合のエラー
+	ErrPostingBeforeSnapshot = errors.New("在庫スナップショット作成済みの日時以前には計上できません")
+
+	// ErrFiscalPeriodNotFound is returned when a fiscal period doesn't exist
+	// 会計期間が存在しない場合のエラー
+	ErrFiscalPeriodNotFound = errors.New("会計期間が見つかりません")
+
+	// ErrInvalidFiscalPeriodStatus is returned when closing a closed period or reopening an open one
+	// 締め済み期間の締め、未締め期間の締め解除など現在のステータスでは実行できない場合のエラー
+	ErrInvalidFiscalPeriodStatus = errors.New("会計期間のステータスが不正です")
+
+	// ErrExchangeRateNotFound is returned when no exchange rate is effective for a currency on a date
+	// 指定日に有効な為替レートが登録されていない場合のエラー
+	ErrExchangeRateNotFound = errors.New("為替レートが見つかりません")
+
+	// ErrAttributeSchemaNotFound is returned when no attribute schema is defined for a category
+	// カテゴリに属性スキーマが定義されていない場合のエラー
+	ErrAttributeSchemaNotFound = errors.New("属性スキーマが見つかりません")
+
+	// ErrVariantGroupNotFound is returned when an item has no variant group
+	// 商品にバリアントグループが存在しない場合のエラー
+	ErrVariantGroupNotFound = errors.New("バリアントグループが見つかりません")
+
+	// ErrBarcodeNotFound is returned when no item is registered for a barcode
+	// バーコードに対応する商品が登録されていない場合のエラー
+	ErrBarcodeNotFound = errors.New("バーコードが見つかりません")
+
+	// ErrDuplicateBarcode is returned when a barcode is already registered
+	// バーコードが既に登録されている場合のエラー
+	ErrDuplicateBarcode = errors.New("バーコードは既に登録されています")
+
+	// ErrTransferOrderNotFound is returned when a transfer order doesn't exist
+	// 移動指示が存在しない場合のエラー
+	ErrTransferOrderNotFound = errors.New("移動指示が見つかりません")
+
+	// ErrInvalidTransferOrderStatus is returned when an action isn't allowed in the order's current status
+	// 現在のステータスでは実行できない移動指示操作の場合のエラー
+	ErrInvalidTransferOrderStatus = errors.New("移動指示のステータスが不正です")
+
+	// ErrSupplierNotFound is returned when a supplier doesn't exist
+	// 仕入先が存在しない場合のエラー
+	ErrSupplierNotFound = errors.New("仕入先が見つかりません")
+
+	// ErrPurchaseOrderNotFound is returned when a purchase order doesn't exist
+	// 発注が存在しない場合のエラー
+	ErrPurchaseOrderNotFound = errors.New("発注が見つかりません")
+
+	// ErrPurchaseOrderNotOpen is returned when receiving or closing a purchase order that is no longer open
+	// 発注残のない発注に入荷・打ち切りしようとした場合のエラー
+	ErrPurchaseOrderNotOpen = errors.New("発注は既に完了しています")
+
+	// ErrOutboundOrderNotFound is returned when an outbound order doesn't exist
+	// 出荷指示が存在しない場合のエラー
+	ErrOutboundOrderNotFound = errors.New("出荷指示が見つかりません")
+
+	// ErrInvalidOutboundOrderStatus is returned when an action isn't allowed in the outbound order's current status
+	// 現在のステータスでは実行できない出荷指示操作の場合のエラー
+	ErrInvalidOutboundOrderStatus = errors.New("出荷指示のステータスが不正です")
+
+	// ErrWaveNotFound is returned when a wave doesn't exist
+	// ウェーブが存在しない場合のエラー
+	ErrWaveNotFound = errors.New("ウェーブが見つかりません")
+
+	// ErrBOMNotFound is returned when an item has no bill of materials
+	// 商品に部品表が登録されていない場合のエラー
+	ErrBOMNotFound = errors.New("部品表が見つかりません")
+
+	// ErrCycleCountTaskNotFound is returned when a cycle count task doesn't exist
+	// 循環棚卸タスクが存在しない場合のエラー
+	ErrCycleCountTaskNotFound = errors.New("棚卸タスクが見つかりません")
+
+	// ErrInvalidCycleCountStatus is returned when an action isn't allowed in the cycle count task's current status
+	// 現在のステータスでは実行できない棚卸タスク操作の場合のエラー
+	ErrInvalidCycleCountStatus = errors.New("棚卸タスクのステータスが不正です")
+
+	// ErrReplenishmentPolicyNotFound is returned when a replenishment policy doesn't exist
+	// 補充ポリシーが存在しない場合のエラー
+	ErrReplenishmentPolicyNotFound = errors.New("補充ポリシーが見つかりません")
+
+	// ErrReplenishmentProposalNotFound is returned when a replenishment proposal doesn't exist
+	// 補充提案が存在しない場合のエラー
+	ErrReplenishmentProposalNotFound = errors.New("補充提案が見つかりません")
+
+	// ErrInvalidReplenishmentStatus is returned when an action isn't allowed in the proposal's current status
+	// 現在のステータスでは実行できない補充提案操作の場合のエラー
+	ErrInvalidReplenishmentStatus = errors.New("補充提案のステータスが不正です")
+
+	// ErrReportScheduleNotFound is returned when a report schedule doesn't exist
+	// レポートスケジュールが存在しない場合のエラー
+	ErrReportScheduleNotFound = errors.New("レポートスケジュールが見つかりません")
+
+	// ErrReportArchiveNotFound is returned when an archived report doesn't exist
+	// アーカイブ済みレポートが存在しない場合のエラー
+	ErrReportArchiveNotFound = errors.New("アーカイブ済みレポートが見つかりません")
+
+	// ErrItemArchived is returned when a new movement or document references an archived item
+	// アーカイブ済みの商品を新しい在庫移動・伝票で使用しようとした場合のエラー
+	ErrItemArchived = errors.New("商品はアーカイブ済みです")
+
+	// ErrLocationArchived is returned when a new movement or document references an archived location
+	// アーカイブ済みのロケーションを新しい在庫移動・伝票で使用しようとした場合のエラー
+	ErrLocationArchived = errors.New("ロケーションはアーカイブ済みです")
+
+	// ErrMasterImportJobNotFound is returned when a master import job is not found
+	// マスタ取り込みジョブが見つからない場合のエラー
+	ErrMasterImportJobNotFound = errors.New("取り込みジョブが見つかりません")
+
+	// ErrOpeningBalanceLoadNotFound is returned when an opening balance load is not found
+	// 期首在庫の取り込みが見つからない場合のエラー
+	ErrOpeningBalanceLoadNotFound = errors.New("期首在庫の取り込みが見つかりません")
+
+	// ErrBatchNotFound is returned when a batch operation is not found
+	// バッチ操作が見つからない場合のエラー
+	ErrBatchNotFound = errors.New("バッチが見つかりません")
 )
 
 // ValidationError represents a validation error with details
@@ -0,0 +1,411 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ForecastEngineImpl implements the DemandForecaster interface
+// DemandForecasterインターフェースの実装
+type ForecastEngineImpl struct {
+	storage Storage
+	logger  *zap.Logger
+	config  ForecastConfig
+}
+
+// ForecastConfig holds the parameters of demand forecasting and reorder suggestions
+// 需要予測と発注提案のパラメータを保持
+type ForecastConfig struct {
+	HistoryDays         int     `yaml:"history_days"`           // 予測に用いる出庫履歴の日数
+	MovingAverageDays   int     `yaml:"moving_average_days"`    // 移動平均の期間（日）
+	Alpha               float64 `yaml:"alpha"`                  // 水準の平滑化係数
+	Beta                float64 `yaml:"beta"`                   // トレンドの平滑化係数（Holt-Winters）
+	Gamma               float64 `yaml:"gamma"`                  // 季節性の平滑化係数（Holt-Winters）
+	SeasonLength        int     `yaml:"season_length"`          // 季節周期（日）
+	ServiceLevel        float64 `yaml:"service_level"`          // 安全在庫の目標サービス率（0〜1）
+	DefaultLeadTimeDays int     `yaml:"default_lead_time_days"` // 発注実績がない商品のリードタイム（日）
+	OrderingCost        float64 `yaml:"ordering_cost"`          // 1回あたりの発注費用
+	HoldingCostRate     float64 `yaml:"holding_cost_rate"`      // 年間在庫保管費率（単価に対する割合）
+}
+
+// DefaultForecastConfig returns a half-year history, weekly seasonality and a 95% service level
+// デフォルトの予測パラメータ（履歴半年、週次の季節性、サービス率95%）を返す
+func DefaultForecastConfig() ForecastConfig {
+	return ForecastConfig{
+		HistoryDays:         182,
+		MovingAverageDays:   28,
+		Alpha:               0.3,
+		Beta:                0.1,
+		Gamma:               0.3,
+		SeasonLength:        7,
+		ServiceLevel:        0.95,
+		DefaultLeadTimeDays: 14,
+		OrderingCost:        100,
+		HoldingCostRate:     0.25,
+	}
+}
+
+// NewForecastEngine creates a new forecast engine
+// 新しい需要予測エンジンを作成
+func NewForecastEngine(storage Storage, logger *zap.Logger) *ForecastEngineImpl {
+	return &ForecastEngineImpl{
+		storage: storage,
+		logger:  logger,
+		config:  DefaultForecastConfig(),
+	}
+}
+
+// SetForecastConfig sets the smoothing, service level and cost parameters
+// 平滑化係数・サービス率・費用のパラメータを設定
+func (f *ForecastEngineImpl) SetForecastConfig(config ForecastConfig) {
+	f.config = config
+}
+
+// ForecastDemand forecasts the daily demand of an item at a location from its outbound history
+// 出庫履歴からロケーション別商品の日次需要を予測
+func (f *ForecastEngineImpl) ForecastDemand(ctx context.Context, itemID, locationID string, method ForecastMethod, horizonDays int) (*DemandForecast, error) {
+	if itemID == "" {
+		return nil, NewValidationError("item_id", "商品IDは必須です", itemID)
+	}
+	if locationID == "" {
+		return nil, NewValidationError("location_id", "ロケーションIDは必須です", locationID)
+	}
+	if err := validateForecastMethod(method); err != nil {
+		return nil, err
+	}
+	if horizonDays <= 0 {
+		return nil, NewValidationError("horizon_days", "予測日数は1以上である必要があります", fmt.Sprintf("%d", horizonDays))
+	}
+
+	now := time.Now()
+	series, err := f.demandSeries(ctx, locationID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.forecast(itemID, locationID, series[itemID], method, horizonDays, now), nil
+}
+
+// GetReorderSuggestions lists the items of a location whose inventory position has fallen to the reorder point
+// 在庫ポジションが発注点以下になった商品を推奨発注数量とともに一覧
+func (f *ForecastEngineImpl) GetReorderSuggestions(ctx context.Context, locationID string, method ForecastMethod) (*ReorderReport, error) {
+	if locationID == "" {
+		return nil, NewValidationError("location_id", "ロケーションIDは必須です", locationID)
+	}
+	if err := validateForecastMethod(method); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	series, err := f.demandSeries(ctx, locationID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	stocks, err := f.storage.ListStockByLocation(ctx, locationID)
+	if err != nil {
+		return nil, NewStorageError("list_stock_by_location", "ロケーション在庫取得に失敗しました", err)
+	}
+
+	report := &ReorderReport{
+		LocationID:   locationID,
+		Method:       method,
+		ServiceLevel: f.config.ServiceLevel,
+		GeneratedAt:  now,
+		Items:        make([]ReorderSuggestion, 0),
+	}
+
+	for _, stock := range stocks {
+		// 出庫実績のない商品は需要が見込めないため発注対象外
+		if _, ok := series[stock.ItemID]; !ok {
+			continue
+		}
+
+		suggestion, err := f.suggestReorder(ctx, stock, series[stock.ItemID], method, now)
+		if err != nil {
+			return nil, err
+		}
+		if suggestion.NeedsOrder {
+			report.Items = append(report.Items, *suggestion)
+		}
+	}
+
+	// 発注点に対する不足数量の大きい順
+	sort.Slice(report.Items, func(i, j int) bool {
+		si := report.Items[i].ReorderPoint - float64(report.Items[i].InventoryPosition)
+		sj := report.Items[j].ReorderPoint - float64(report.Items[j].InventoryPosition)
+		if si != sj {
+			return si > sj
+		}
+		return report.Items[i].ItemID < report.Items[j].ItemID
+	})
+
+	return report, nil
+}
+
+// suggestReorder derives safety stock, reorder point and EOQ for one stock row
+// 在庫1行について安全在庫・発注点・経済的発注量を算出
+func (f *ForecastEngineImpl) suggestReorder(ctx context.Context, stock Stock, series []float64, method ForecastMethod, now time.Time) (*ReorderSuggestion, error) {
+	leadTimeDays, err := f.storage.GetItemLeadTimeDays(ctx, stock.ItemID)
+	if err != nil {
+		return nil, NewStorageError("get_item_lead_time_days", "リードタイム取得に失敗しました", err)
+	}
+	if leadTimeDays <= 0 {
+		leadTimeDays = f.config.DefaultLeadTimeDays
+	}
+
+	// 発注残は入荷先がこのロケーションの発注のみを計上する
+	onOrder, err := f.storage.GetOnOrderQuantity(ctx, stock.ItemID, stock.LocationID)
+	if err != nil {
+		return nil, NewStorageError("get_on_order_quantity", "発注残数量取得に失敗しました", err)
+	}
+
+	item, err := f.storage.GetItem(ctx, stock.ItemID)
+	if err != nil {
+		return nil, NewStorageError("get_item", "商品取得に失敗しました", err)
+	}
+
+	forecast := f.forecast(stock.ItemID, stock.LocationID, series, method, leadTimeDays, now)
+
+	suggestion := &ReorderSuggestion{
+		ItemID:              stock.ItemID,
+		LocationID:          stock.LocationID,
+		Method:              forecast.Method,
+		OnHand:              stock.Quantity,
+		Reserved:            stock.Reserved,
+		OnOrder:             onOrder,
+		InventoryPosition:   stock.Quantity - stock.Reserved + onOrder,
+		ForecastDailyDemand: forecast.ForecastDailyDemand,
+		DemandStdDev:        forecast.DemandStdDev,
+		LeadTimeDays:        leadTimeDays,
+	}
+
+	for _, demand := range forecast.Forecast {
+		suggestion.LeadTimeDemand += demand
+	}
+
+	// 安全在庫 = z(サービス率) × σ × √リードタイム
+	z := serviceLevelZ(f.config.ServiceLevel)
+	suggestion.SafetyStock = z * forecast.DemandStdDev * math.Sqrt(float64(leadTimeDays))
+	suggestion.ReorderPoint = suggestion.LeadTimeDemand + suggestion.SafetyStock
+
+	// EOQ = √(2 × 年間需要 × 発注費用 / 年間保管費用)
+	// 需要は保存単位のため、保管費用も保存単位あたりに換算
+	holdingCost := item.UnitCost.Float64() / float64(item.QuantityScale()) * f.config.HoldingCostRate
+	annualDemand := forecast.ForecastDailyDemand * 365
+	if holdingCost > 0 && annualDemand > 0 {
+		suggestion.EOQ = math.Sqrt(2 * annualDemand * f.config.OrderingCost / holdingCost)
+	}
+
+	if forecast.ForecastDailyDemand > 0 && float64(suggestion.InventoryPosition) <= suggestion.ReorderPoint {
+		suggestion.NeedsOrder = true
+		quantity := math.Max(suggestion.EOQ, suggestion.ReorderPoint-float64(suggestion.InventoryPosition))
+		suggestion.SuggestedQuantity = int64(math.Max(math.Ceil(quantity), 1))
+	}
+
+	return suggestion, nil
+}
+
+// demandSeries builds daily outbound quantities per item over the configured history
+// 設定された履歴期間の商品別日次出庫数量を作成
+func (f *ForecastEngineImpl) demandSeries(ctx context.Context, locationID string, now time.Time) (map[string][]float64, error) {
+	end := now.UTC().Truncate(24 * time.Hour)
+	start := end.AddDate(0, 0, -f.config.HistoryDays)
+
+	transactions, err := f.storage.ListOutboundTransactions(ctx, locationID, start, end)
+	if err != nil {
+		return nil, NewStorageError("list_outbound_transactions", "出庫トランザクション取得に失敗しました", err)
+	}
+
+	series := make(map[string][]float64)
+	for _, tx := range transactions {
+		day := int(tx.CreatedAt.Sub(start) / (24 * time.Hour))
+		if day < 0 || day >= f.config.HistoryDays {
+			continue
+		}
+		if _, ok := series[tx.ItemID]; !ok {
+			series[tx.ItemID] = make([]float64, f.config.HistoryDays)
+		}
+		series[tx.ItemID][day] += float64(tx.Quantity)
+	}
+
+	return series, nil
+}
+
+// forecast fits the requested method to a daily demand series and projects it over the horizon
+// 日次需要系列に指定手法を当てはめて予測期間の需要を算出
+func (f *ForecastEngineImpl) forecast(itemID, locationID string, series []float64, method ForecastMethod, horizonDays int, now time.Time) *DemandForecast {
+	if len(series) == 0 {
+		series = make([]float64, f.config.HistoryDays)
+	}
+
+	result := &DemandForecast{
+		ItemID:      itemID,
+		LocationID:  locationID,
+		Method:      method,
+		HistoryDays: len(series),
+		GeneratedAt: now,
+	}
+
+	var projection []float64
+	var errors []float64
+	switch method {
+	case ForecastMethodMovingAverage:
+		projection, errors = movingAverageForecast(series, f.config.MovingAverageDays, horizonDays)
+	case ForecastMethodHoltWinters:
+		if len(series) >= 2*f.config.SeasonLength {
+			projection, errors = holtWintersForecast(series, f.config.SeasonLength, f.config.Alpha, f.config.Beta, f.config.Gamma, horizonDays)
+			break
+		}
+		// 2周期分の履歴がない場合は季節性を推定できないため単純指数平滑に切り替え
+		result.Method = ForecastMethodExponentialSmoothing
+		projection, errors = exponentialSmoothingForecast(series, f.config.Alpha, horizonDays)
+	default:
+		projection, errors = exponentialSmoothingForecast(series, f.config.Alpha, horizonDays)
+	}
+
+	result.Forecast = projection
+	result.AverageDailyDemand = mean(series)
+	result.ForecastDailyDemand = mean(projection)
+	if len(errors) > 0 {
+		result.DemandStdDev = rootMeanSquare(errors)
+	} else {
+		result.DemandStdDev = standardDeviation(series)
+	}
+
+	return result
+}
+
+// movingAverageForecast projects the mean of the last window days
+// 直近window日の平均を予測値とする
+func movingAverageForecast(series []float64, window, horizonDays int) ([]float64, []float64) {
+	if window > len(series) {
+		window = len(series)
+	}
+
+	var errors []float64
+	for t := window; t < len(series); t++ {
+		errors = append(errors, series[t]-mean(series[t-window:t]))
+	}
+
+	return flatForecast(mean(series[len(series)-window:]), horizonDays), errors
+}
+
+// exponentialSmoothingForecast projects the exponentially smoothed level
+// 指数平滑した水準を予測値とする
+func exponentialSmoothingForecast(series []float64, alpha float64, horizonDays int) ([]float64, []float64) {
+	level := series[0]
+	errors := make([]float64, 0, len(series)-1)
+	for _, y := range series[1:] {
+		errors = append(errors, y-level)
+		level = alpha*y + (1-alpha)*level
+	}
+
+	return flatForecast(level, horizonDays), errors
+}
+
+// holtWintersForecast projects level, trend and additive seasonality
+// 水準・トレンド・加法型季節性を推定して予測する
+func holtWintersForecast(series []float64, seasonLength int, alpha, beta, gamma float64, horizonDays int) ([]float64, []float64) {
+	// 初期値は最初の2周期から推定
+	first := mean(series[:seasonLength])
+	second := mean(series[seasonLength : 2*seasonLength])
+	level := first
+	trend := (second - first) / float64(seasonLength)
+	seasonal := make([]float64, seasonLength)
+	for i := 0; i < seasonLength; i++ {
+		seasonal[i] = series[i] - first
+	}
+
+	errors := make([]float64, 0, len(series)-seasonLength)
+	for t := seasonLength; t < len(series); t++ {
+		y := series[t]
+		s := seasonal[t%seasonLength]
+		errors = append(errors, y-(level+trend+s))
+
+		previousLevel := level
+		level = alpha*(y-s) + (1-alpha)*(level+trend)
+		trend = beta*(level-previousLevel) + (1-beta)*trend
+		seasonal[t%seasonLength] = gamma*(y-level) + (1-gamma)*s
+	}
+
+	projection := make([]float64, horizonDays)
+	for h := 1; h <= horizonDays; h++ {
+		value := level + float64(h)*trend + seasonal[(len(series)+h-1)%seasonLength]
+		projection[h-1] = math.Max(value, 0)
+	}
+
+	return projection, errors
+}
+
+// flatForecast repeats a non-negative level over the horizon
+// 水準（負の場合は0）を予測期間にわたって並べる
+func flatForecast(level float64, horizonDays int) []float64 {
+	projection := make([]float64, horizonDays)
+	for i := range projection {
+		projection[i] = math.Max(level, 0)
+	}
+	return projection
+}
+
+// serviceLevelZ returns the standard normal quantile of the service level
+// サービス率に対応する標準正規分布の分位点を返す
+func serviceLevelZ(serviceLevel float64) float64 {
+	if serviceLevel <= 0.5 {
+		return 0
+	}
+	return math.Sqrt2 * math.Erfinv(2*serviceLevel-1)
+}
+
+// validateForecastMethod checks that the method is a supported forecasting method
+// 予測手法が対応しているものかを検証
+func validateForecastMethod(method ForecastMethod) error {
+	switch method {
+	case ForecastMethodMovingAverage, ForecastMethodExponentialSmoothing, ForecastMethodHoltWinters:
+		return nil
+	default:
+		return NewValidationError("method", "未対応の予測手法です", string(method))
+	}
+}
+
+// mean returns the arithmetic mean (0 for an empty slice)
+// 算術平均を返す（空の場合は0）
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// standardDeviation returns the population standard deviation
+// 母標準偏差を返す
+func standardDeviation(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	m := mean(values)
+	sum := 0.0
+	for _, v := range values {
+		sum += (v - m) * (v - m)
+	}
+	return math.Sqrt(sum / float64(len(values)))
+}
+
+// rootMeanSquare returns the root mean square of forecast errors
+// 予測誤差の二乗平均平方根を返す
+func rootMeanSquare(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v * v
+	}
+	return math.Sqrt(sum / float64(len(values)))
+}
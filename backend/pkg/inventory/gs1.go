@@ -0,0 +1,233 @@
+package inventory
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// gs1GroupSeparator is the ASCII GS character scanners emit for FNC1 after variable-length fields
+// 可変長データの終端としてスキャナが出力するFNC1（ASCII GS文字）
+const gs1GroupSeparator = "\x1d"
+
+// gs1AI describes the data format of a GS1 application identifier
+// GS1アプリケーション識別子（AI）のデータ形式を表現
+type gs1AI struct {
+	length   int  // 固定長の桁数、または可変長の最大桁数
+	variable bool // 可変長かどうか
+	numeric  bool // 数字のみかどうか
+}
+
+// gs1AIs lists the application identifiers understood by ParseGS1
+// ParseGS1が解釈するアプリケーション識別子
+var gs1AIs = map[string]gs1AI{
+	"00": {length: 18, numeric: true},                // SSCC（出荷梱包シリアル）
+	"01": {length: 14, numeric: true},                // GTIN
+	"02": {length: 14, numeric: true},                // 内容物のGTIN
+	"10": {length: 20, variable: true},               // ロット番号
+	"11": {length: 6, numeric: true},                 // 製造日（YYMMDD）
+	"13": {length: 6, numeric: true},                 // 包装日（YYMMDD）
+	"15": {length: 6, numeric: true},                 // 品質保持期限（YYMMDD）
+	"17": {length: 6, numeric: true},                 // 有効期限（YYMMDD）
+	"20": {length: 2, numeric: true},                 // 品目バリアント
+	"21": {length: 20, variable: true},               // シリアル番号
+	"30": {length: 8, variable: true, numeric: true}, // 数量
+	"37": {length: 8, variable: true, numeric: true}, // 内容物の数量
+}
+
+// GS1Data holds the application identifiers parsed from a GS1-128 / GS1 DataMatrix label
+// GS1-128・GS1 DataMatrixラベルから解析したアプリケーション識別子の値を保持
+type GS1Data struct {
+	GTIN       string            `json:"gtin,omitempty"`        // (01)または(02)のGTIN
+	LotNumber  string            `json:"lot_number,omitempty"`  // (10)ロット番号
+	ExpiryDate *time.Time        `json:"expiry_date,omitempty"` // (17)有効期限
+	Serial     string            `json:"serial,omitempty"`      // (21)シリアル番号
+	Count      int64             `json:"count,omitempty"`       // (30)/(37)数量（包装数）
+	Elements   map[string]string `json:"elements"`              // 解析したすべてのAIと値
+}
+
+// ParseGS1 parses GS1 element strings in bracketed form "(01)04912345678904(10)LOT1"
+// or raw scanner form "]C1010491234567890410LOT1" with GS separators after variable-length fields
+// 括弧付き表記または、可変長データの後にGS区切り文字を含むスキャナ出力形式のGS1データを解析
+func ParseGS1(input string) (*GS1Data, error) {
+	text := strings.TrimSpace(input)
+	// シンボル識別子（]C1: GS1-128, ]d2: GS1 DataMatrix, ]Q3: GS1 QR, ]e0: GS1 DataBar）を除去
+	for _, prefix := range []string{"]C1", "]d2", "]Q3", "]e0"} {
+		text = strings.TrimPrefix(text, prefix)
+	}
+	if text == "" {
+		return nil, NewValidationError("code", "GS1データが空です", input)
+	}
+
+	elements := make(map[string]string)
+	var err error
+	if strings.HasPrefix(text, "(") {
+		err = parseGS1Bracketed(text, elements)
+	} else {
+		err = parseGS1Raw(text, elements)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data := &GS1Data{Elements: elements}
+	data.GTIN = elements["01"]
+	if data.GTIN == "" {
+		data.GTIN = elements["02"]
+	}
+	if data.GTIN != "" {
+		if err := ValidateGTIN(data.GTIN); err != nil {
+			return nil, err
+		}
+	}
+	data.LotNumber = elements["10"]
+	data.Serial = elements["21"]
+	if expiry, ok := elements["17"]; ok {
+		date, err := parseGS1Date(expiry)
+		if err != nil {
+			return nil, err
+		}
+		data.ExpiryDate = &date
+	}
+	for _, ai := range []string{"30", "37"} {
+		if count, ok := elements[ai]; ok {
+			data.Count, _ = strconv.ParseInt(count, 10, 64)
+		}
+	}
+	return data, nil
+}
+
+// parseGS1Bracketed parses "(AI)value(AI)value..." into elements
+// "(AI)値(AI)値..."形式を解析
+func parseGS1Bracketed(text string, elements map[string]string) error {
+	for text != "" {
+		if !strings.HasPrefix(text, "(") {
+			return NewValidationError("code", "GS1データの形式が不正です", text)
+		}
+		end := strings.Index(text, ")")
+		if end < 0 {
+			return NewValidationError("code", "GS1データの括弧が閉じられていません", text)
+		}
+		ai := text[1:end]
+		text = text[end+1:]
+		next := strings.Index(text, "(")
+		if next < 0 {
+			next = len(text)
+		}
+		if err := setGS1Element(elements, ai, strings.TrimSuffix(text[:next], gs1GroupSeparator)); err != nil {
+			return err
+		}
+		text = text[next:]
+	}
+	return nil
+}
+
+// parseGS1Raw parses a concatenated element string using fixed lengths and GS separators
+// 固定長とGS区切り文字に基づいて連結されたGS1データを解析
+func parseGS1Raw(text string, elements map[string]string) error {
+	for text != "" {
+		text = strings.TrimPrefix(text, gs1GroupSeparator)
+		if len(text) < 2 {
+			return NewValidationError("code", "GS1データの形式が不正です", text)
+		}
+		ai := text[:2]
+		spec, ok := gs1AIs[ai]
+		if !ok {
+			return NewValidationError("code", "未対応のGS1アプリケーション識別子です", ai)
+		}
+		text = text[2:]
+
+		var value string
+		if spec.variable {
+			end := strings.Index(text, gs1GroupSeparator)
+			if end < 0 {
+				end = len(text)
+			}
+			value, text = text[:end], text[end:]
+		} else {
+			if len(text) < spec.length {
+				return NewValidationError("code", fmt.Sprintf("AI(%s)の桁数が不足しています", ai), text)
+			}
+			value, text = text[:spec.length], text[spec.length:]
+		}
+		if err := setGS1Element(elements, ai, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setGS1Element validates an AI value against its format and stores it
+// AIの値を形式に照らして検証し格納
+func setGS1Element(elements map[string]string, ai, value string) error {
+	spec, ok := gs1AIs[ai]
+	if !ok {
+		return NewValidationError("code", "未対応のGS1アプリケーション識別子です", ai)
+	}
+	if _, exists := elements[ai]; exists {
+		return NewValidationError("code", fmt.Sprintf("AI(%s)が重複しています", ai), value)
+	}
+	if value == "" || len(value) > spec.length || (!spec.variable && len(value) != spec.length) {
+		return NewValidationError("code", fmt.Sprintf("AI(%s)の桁数が不正です", ai), value)
+	}
+	if spec.numeric && !isDigits(value) {
+		return NewValidationError("code", fmt.Sprintf("AI(%s)は数字のみ指定できます", ai), value)
+	}
+	elements[ai] = value
+	return nil
+}
+
+// parseGS1Date parses a GS1 YYMMDD date; day 00 means the last day of the month
+// GS1の日付（YYMMDD）を解析（日が00の場合は月末日）
+//
+// 世紀はGS1一般仕様に従い、現在年から見て51年以上先なら前世紀、50年以上前なら次世紀と判定します。
+func parseGS1Date(value string) (time.Time, error) {
+	yy, _ := strconv.Atoi(value[0:2])
+	month, _ := strconv.Atoi(value[2:4])
+	day, _ := strconv.Atoi(value[4:6])
+	if month < 1 || month > 12 {
+		return time.Time{}, NewValidationError("expiry_date", "GS1日付の月が不正です", value)
+	}
+
+	current := time.Now().Year()
+	year := current/100*100 + yy
+	switch diff := yy - current%100; {
+	case diff >= 51:
+		year -= 100
+	case diff <= -50:
+		year += 100
+	}
+
+	if day == 0 {
+		return time.Date(year, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC), nil
+	}
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if date.Day() != day {
+		return time.Time{}, NewValidationError("expiry_date", "GS1日付の日が不正です", value)
+	}
+	return date, nil
+}
+
+// isDigits reports whether s is non-empty and consists of ASCII digits only
+// 文字列が空でなく数字のみで構成されているかを判定
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// NormalizeGTIN left-pads a GTIN-8/12/13 with zeros to the 14-digit form used as the lookup key
+// GTIN-8/12/13を左ゼロ埋めで14桁に正規化（検索キーとして使用）
+func NormalizeGTIN(code string) string {
+	if len(code) >= 14 {
+		return code
+	}
+	return strings.Repeat("0", 14-len(code)) + code
+}
@@ -0,0 +1,480 @@
+package inventory
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// MaxImportRows is the largest number of data rows accepted in one import file
+// 1ファイルで取り込める明細行数の上限
+const MaxImportRows = 100000
+
+// MaxImportErrors is the largest number of row errors kept for one import
+// 1回の取り込みで記録する行エラーの上限（件数は全件を計上）
+const MaxImportErrors = 1000
+
+// ImportRowError represents a problem found in one row of an import file
+// 取り込みファイルの1行で見つかった問題を表現
+type ImportRowError struct {
+	Row     int    `json:"row"`             // 行番号（見出し行が1行目）
+	ID      string `json:"id,omitempty"`    // 行のID
+	Field   string `json:"field,omitempty"` // 列（キー）
+	Message string `json:"message"`         // エラーメッセージ
+	Value   string `json:"value,omitempty"` // 問題のある値
+}
+
+// appendImportRowError appends a row error unless MaxImportErrors entries are already kept
+// 行エラーを追加（保持済みがMaxImportErrors件に達している場合は追加しない）
+func appendImportRowError(errors []ImportRowError, rowError ImportRowError) []ImportRowError {
+	if len(errors) >= MaxImportErrors {
+		return errors
+	}
+	return append(errors, rowError)
+}
+
+// importRow is one data row of an import file keyed by column key
+// 取り込みファイルの明細1行（列キーと値）
+type importRow struct {
+	number  int               // 行番号
+	values  map[string]string // 列キーごとの値（ファイルにない列は含まない）
+	numeric map[string]bool   // XLSXで数値として保存された列
+}
+
+// has reports whether the file contains the column
+// ファイルに列が含まれるかを判定
+func (r importRow) has(key string) bool {
+	_, ok := r.values[key]
+	return ok
+}
+
+// quantity parses a quantity column at the given precision
+// 数量の列を指定の小数桁数で解析
+//
+// XLSXの数値セルは浮動小数点で保存されるため（1.1が1.1000000000000001になるなど）、
+// 差が浮動小数点の誤差に収まる場合は小数桁数に丸めてから解析します。
+func (r importRow) quantity(key string, precision int) (int64, error) {
+	value := r.values[key]
+	if r.numeric[key] {
+		if number, err := strconv.ParseFloat(value, 64); err == nil {
+			scaled := number * float64(QuantityScale(precision))
+			if rounded := math.Round(scaled); math.Abs(scaled-rounded) <= xlsxFloatTolerance*math.Max(1, math.Abs(scaled)) {
+				value = FormatQuantity(int64(rounded), precision)
+			}
+		}
+	}
+	return ParseQuantity(value, precision)
+}
+
+// importRecord is one physical row of an import file
+// 取り込みファイルの物理行
+type importRecord struct {
+	number  int      // 行番号
+	cells   []string // セルの値
+	numeric []bool   // 数値として保存されたセル（XLSXのみ）
+}
+
+// importTable is the parsed content of an import file
+// 解析済みの取り込みファイル
+type importTable struct {
+	rows []importRow
+}
+
+// parseImportFile reads a CSV or XLSX import file and maps its header to the given columns
+// CSV・XLSXの取り込みファイルを読み込み、見出しを指定の列に対応付ける
+//
+// 見出しは列キーまたは出力時の日本語名で指定し、requiredの列は必須です。空行は無視します。
+func parseImportFile(columns []reportColumn, format ReportFormat, data []byte, required ...string) (*importTable, error) {
+	var records []importRecord
+	var err error
+	switch format {
+	case ReportFormatCSV, "":
+		records, err = readCSVRecords(data)
+	case ReportFormatXLSX:
+		records, err = readXLSXRecords(data)
+	default:
+		return nil, NewValidationError("format", "未対応の取り込み形式です（csv / xlsx）", string(format))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// 最初の空でない行を見出しとする
+	start := 0
+	for start < len(records) && isBlankRecord(records[start].cells) {
+		start++
+	}
+	if start == len(records) {
+		return nil, NewValidationError("file", "見出し行がありません", "")
+	}
+
+	lookup := make(map[string]string, len(columns)*2)
+	for _, column := range columns {
+		lookup[column.key] = column.key
+		lookup[strings.ToLower(column.label)] = column.key
+	}
+	keys := make([]string, len(records[start].cells))
+	seen := make(map[string]bool)
+	for i, heading := range records[start].cells {
+		heading = strings.ToLower(strings.TrimSpace(heading))
+		if heading == "" {
+			continue
+		}
+		key, ok := lookup[heading]
+		if !ok {
+			return nil, NewValidationError("header", "不明な列です", heading)
+		}
+		if seen[key] {
+			return nil, NewValidationError("header", "列が重複しています", heading)
+		}
+		seen[key] = true
+		keys[i] = key
+	}
+	for _, key := range required {
+		if !seen[key] {
+			return nil, NewValidationError("header", "必須の列がありません", key)
+		}
+	}
+
+	table := &importTable{}
+	for _, record := range records[start+1:] {
+		if isBlankRecord(record.cells) {
+			continue
+		}
+		if len(table.rows) == MaxImportRows {
+			return nil, errTooManyImportRows()
+		}
+		row := importRow{number: record.number, values: make(map[string]string, len(keys))}
+		for j, key := range keys {
+			if key == "" {
+				continue
+			}
+			value := ""
+			if j < len(record.cells) {
+				value = strings.TrimSpace(record.cells[j])
+			}
+			row.values[key] = value
+			if j < len(record.numeric) && record.numeric[j] {
+				if row.numeric == nil {
+					row.numeric = make(map[string]bool)
+				}
+				row.numeric[key] = true
+			}
+		}
+		table.rows = append(table.rows, row)
+	}
+	if len(table.rows) == 0 {
+		return nil, NewValidationError("file", "明細行がありません", "")
+	}
+	return table, nil
+}
+
+// errTooManyImportRows reports a file with more data rows than MaxImportRows
+// 明細行数が上限を超えた場合のエラー
+func errTooManyImportRows() error {
+	return NewValidationError("file", fmt.Sprintf("明細行は%d行以内にしてください", MaxImportRows), "")
+}
+
+// importRecordCounter stops reading a file once it has more non-blank rows than a header and MaxImportRows
+// 見出しと上限行数を超える空でない行を読み込んだ時点で読み込みを打ち切る
+type importRecordCounter int
+
+func (c *importRecordCounter) add(cells []string) error {
+	if isBlankRecord(cells) {
+		return nil
+	}
+	if *c++; *c > MaxImportRows+1 {
+		return errTooManyImportRows()
+	}
+	return nil
+}
+
+// isBlankRecord reports whether every cell of a record is empty
+// すべてのセルが空の行かを判定
+func isBlankRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// readCSVRecords reads UTF-8 CSV (with or without BOM) and returns records with their row numbers
+// UTF-8のCSV（BOMの有無は問わない）を読み込み、行番号とともに返す
+func readCSVRecords(data []byte) ([]importRecord, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte{0xEF, 0xBB, 0xBF})))
+	reader.FieldsPerRecord = -1
+
+	var records []importRecord
+	var counter importRecordCounter
+	for {
+		cells, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, NewValidationError("file", "CSVを読み込めません", err.Error())
+		}
+		if err := counter.add(cells); err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		records = append(records, importRecord{number: line, cells: cells})
+	}
+	return records, nil
+}
+
+// xlsxText is rich or plain text in a shared string or inline string cell
+// 共有文字列・インライン文字列のテキスト（書式付きの場合は連結）
+type xlsxText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.Text
+	}
+	var text strings.Builder
+	for _, run := range t.Runs {
+		text.WriteString(run.Text)
+	}
+	return text.String()
+}
+
+// xlsxWorkbook, xlsxRelationships and xlsxSharedStrings are the parts read from a workbook
+// ブックから読み込む構成部品
+type xlsxWorkbook struct {
+	Sheets []struct {
+		RelationshipID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+// xlsxRow is one row of a worksheet; rows are decoded one at a time
+// シートの1行（行単位でデコード）
+type xlsxRow struct {
+	Number int `xml:"r,attr"`
+	Cells  []struct {
+		Ref    string   `xml:"r,attr"`
+		Type   string   `xml:"t,attr"`
+		Value  string   `xml:"v"`
+		Inline xlsxText `xml:"is"`
+	} `xml:"c"`
+}
+
+// xlsxMaxColumns is the number of columns in a worksheet (A to XFD)
+// シートの列数（A〜XFD）
+const xlsxMaxColumns = 16384
+
+// xlsxMaxPartSize is the largest uncompressed size read from one part of a workbook
+// ブックの1部品から読み込む展開後サイズの上限
+const xlsxMaxPartSize = 256 << 20
+
+// xlsxFloatTolerance is the relative difference treated as floating-point error in numeric cells
+// 数値セルで浮動小数点の誤差とみなす相対差
+const xlsxFloatTolerance = 1e-12
+
+// readXLSXRecords reads the first worksheet of an XLSX workbook and returns records with their row numbers
+// XLSXブックの最初のシートを読み込み、行番号とともに返す
+func readXLSXRecords(data []byte) ([]importRecord, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, NewValidationError("file", "XLSXを読み込めません", err.Error())
+	}
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+
+	// 最初のシートの部品名をブックの関係定義から解決（解決できない場合は既定の名前）
+	sheetPath := "xl/worksheets/sheet1.xml"
+	var workbook xlsxWorkbook
+	var relationships xlsxRelationships
+	if readXLSXPart(files, "xl/workbook.xml", &workbook) == nil && len(workbook.Sheets) > 0 &&
+		readXLSXPart(files, "xl/_rels/workbook.xml.rels", &relationships) == nil {
+		for _, relationship := range relationships.Relationships {
+			if relationship.ID == workbook.Sheets[0].RelationshipID {
+				if strings.HasPrefix(relationship.Target, "/") {
+					sheetPath = strings.TrimPrefix(relationship.Target, "/")
+				} else {
+					sheetPath = "xl/" + relationship.Target
+				}
+			}
+		}
+	}
+
+	var shared xlsxSharedStrings
+	if _, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := readXLSXPart(files, "xl/sharedStrings.xml", &shared); err != nil {
+			return nil, NewValidationError("file", "XLSXの共有文字列を読み込めません", err.Error())
+		}
+	}
+
+	sheet, err := openXLSXPart(files, sheetPath)
+	if err != nil {
+		return nil, NewValidationError("file", "XLSXのシートを読み込めません", err.Error())
+	}
+	defer sheet.Close()
+
+	// 行単位でデコードし、上限行数を超えた時点で打ち切る
+	var records []importRecord
+	var counter importRecordCounter
+	decoder := xml.NewDecoder(sheet)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, NewValidationError("file", "XLSXのシートを読み込めません", err.Error())
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "row" {
+			continue
+		}
+		var row xlsxRow
+		if err := decoder.DecodeElement(&row, &start); err != nil {
+			return nil, NewValidationError("file", "XLSXのシートを読み込めません", err.Error())
+		}
+
+		record := importRecord{number: row.Number}
+		if record.number == 0 {
+			record.number = len(records) + 1
+		}
+		for j, cell := range row.Cells {
+			column := j
+			if cell.Ref != "" {
+				column = xlsxColumnIndex(cell.Ref)
+			}
+			if column < 0 || column >= xlsxMaxColumns {
+				return nil, NewValidationError("file", "XLSXのセル参照が不正です", cell.Ref)
+			}
+			for len(record.cells) <= column {
+				record.cells = append(record.cells, "")
+				record.numeric = append(record.numeric, false)
+			}
+
+			switch cell.Type {
+			case "s":
+				index, err := strconv.Atoi(cell.Value)
+				if err != nil || index < 0 || index >= len(shared.Items) {
+					return nil, NewValidationError("file", "XLSXの共有文字列の参照が不正です", cell.Ref)
+				}
+				record.cells[column] = shared.Items[index].String()
+			case "inlineStr":
+				record.cells[column] = cell.Inline.String()
+			case "b":
+				record.cells[column] = strconv.FormatBool(cell.Value == "1")
+			case "", "n":
+				record.numeric[column] = cell.Value != ""
+				// 指数表記の数値（1E-3など）は10進表記に戻す
+				if strings.ContainsAny(cell.Value, "eE") {
+					if number, err := strconv.ParseFloat(cell.Value, 64); err == nil {
+						record.cells[column] = strconv.FormatFloat(number, 'f', -1, 64)
+						continue
+					}
+				}
+				record.cells[column] = cell.Value
+			default:
+				record.cells[column] = cell.Value
+			}
+		}
+		if err := counter.add(record.cells); err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// readXLSXPart decodes an XML part of a workbook package
+// ブックのXML部品をデコード
+func readXLSXPart(files map[string]*zip.File, name string, v interface{}) error {
+	reader, err := openXLSXPart(files, name)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	return xml.NewDecoder(reader).Decode(v)
+}
+
+// openXLSXPart opens a part of a workbook package, reading at most xlsxMaxPartSize bytes
+// ブックの部品を開く（展開後xlsxMaxPartSizeバイトまで読み込む）
+func openXLSXPart(files map[string]*zip.File, name string) (io.ReadCloser, error) {
+	file, ok := files[name]
+	if !ok {
+		return nil, fmt.Errorf("%sがありません", name)
+	}
+	if file.UncompressedSize64 > xlsxMaxPartSize {
+		return nil, fmt.Errorf("%sが大きすぎます", name)
+	}
+	reader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	// 展開後サイズの申告が実際と異なる場合も上限で打ち切る
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(reader, xlsxMaxPartSize), reader}, nil
+}
+
+// xlsxColumnIndex converts the column letters of a cell reference to a zero-based index (A1 → 0, AA3 → 26)
+// セル参照の列名を0始まりの列番号に変換（A1 → 0、AA3 → 26）
+//
+// 列名がない参照やXFDを超える列は-1を返します。
+func xlsxColumnIndex(ref string) int {
+	index := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		index = index*26 + int(r-'A'+1)
+		if index > xlsxMaxColumns {
+			return -1
+		}
+	}
+	return index - 1
+}
+
+// parseImportBool parses a boolean cell; an empty cell yields the default
+// 真偽値のセルを解析（空の場合は既定値）
+func parseImportBool(value string, defaultValue bool) (bool, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	return strconv.ParseBool(value)
+}
+
+// rowErrorFrom converts a validation or business rule error into a row error
+// バリデーション・ビジネスルールのエラーを行エラーに変換
+func rowErrorFrom(row int, id string, err error) ImportRowError {
+	rowError := ImportRowError{Row: row, ID: id, Message: err.Error()}
+	switch e := err.(type) {
+	case *ValidationError:
+		rowError.Field, rowError.Message, rowError.Value = e.Field, e.Message, e.Value
+	case *BusinessRuleError:
+		rowError.Field, rowError.Message, rowError.Value = e.Rule, e.Message, e.Context
+	}
+	return rowError
+}
@@ -2,6 +2,7 @@ package inventory
 
 import (
 	"context"
+	"io"
 	"time"
 )
 
@@ -13,11 +14,14 @@ type InventoryManager interface {
 	Remove(ctx context.Context, itemID, locationID string, quantity int64, reference string) error
 	Transfer(ctx context.Context, itemID, fromLocationID, toLocationID string, quantity int64, reference string) error
 	Adjust(ctx context.Context, itemID, locationID string, newQuantity int64, reference string) error
+	ReverseTransaction(ctx context.Context, transactionID, reference, reason string) (*Transaction, error)
 
 	// 在庫照会 - Stock inquiry
 	GetStock(ctx context.Context, itemID, locationID string) (*Stock, error)
 	GetTotalStock(ctx context.Context, itemID string) (int64, error)
 	GetStockByLocation(ctx context.Context, locationID string) ([]Stock, error)
+	GetStockAsOf(ctx context.Context, itemID, locationID string, asOf time.Time) (*StockSnapshot, error)
+	GetStockByLocationAsOf(ctx context.Context, locationID string, asOf time.Time) ([]StockSnapshot, error)
 
 	// 履歴管理 - History management
 	GetHistory(ctx context.Context, itemID string, limit int) ([]Transaction, error)
@@ -44,8 +48,27 @@ type ItemManager interface {
 	GetItem(ctx context.Context, itemID string) (*Item, error)
 	UpdateItem(ctx context.Context, item *Item) error
 	DeleteItem(ctx context.Context, itemID string) error
-	ListItems(ctx context.Context, offset, limit int) ([]Item, error)
-	SearchItems(ctx context.Context, query string) ([]Item, error)
+	ListItems(ctx context.Context, filter ItemSearchFilter) (*ItemPage, error)
+	SearchItems(ctx context.Context, filter ItemSearchFilter) (*ItemPage, error)
+}
+
+// ItemAttributeManager defines interface for category attribute schemas and item variant groups
+// カテゴリ属性スキーマと商品バリアントグループのインターフェースを定義
+type ItemAttributeManager interface {
+	SetCategoryAttributeSchema(ctx context.Context, schema *CategoryAttributeSchema) error
+	GetCategoryAttributeSchema(ctx context.Context, category string) (*CategoryAttributeSchema, error)
+	ListCategoryAttributeSchemas(ctx context.Context) ([]CategoryAttributeSchema, error)
+	SetVariantGroup(ctx context.Context, group *VariantGroup) error
+	GetVariantGroup(ctx context.Context, parentItemID string) (*VariantGroup, error)
+}
+
+// BarcodeManager defines interface for item barcodes and scan resolution
+// 商品バーコードとスキャン解決のインターフェースを定義
+type BarcodeManager interface {
+	AddItemBarcode(ctx context.Context, barcode *ItemBarcode) error
+	ListItemBarcodes(ctx context.Context, itemID string) ([]ItemBarcode, error)
+	RemoveItemBarcode(ctx context.Context, itemID, code string) error
+	ResolveScan(ctx context.Context, input string) (*ScanResult, error)
 }
 
 // LocationManager defines interface for location management
@@ -55,7 +78,34 @@ type LocationManager interface {
 	GetLocation(ctx context.Context, locationID string) (*Location, error)
 	UpdateLocation(ctx context.Context, location *Location) error
 	DeleteLocation(ctx context.Context, locationID string) error
-	ListLocations(ctx context.Context, offset, limit int) ([]Location, error)
+	ListLocations(ctx context.Context, offset, limit int, includeArchived bool) ([]Location, error)
+}
+
+// MasterArchiveManager defines interface for inspecting and restoring archived items and locations
+// 商品・ロケーションのアーカイブ可否の確認とアーカイブ解除のインターフェースを定義
+type MasterArchiveManager interface {
+	GetItemUsage(ctx context.Context, itemID string) (*MasterUsage, error)
+	GetLocationUsage(ctx context.Context, locationID string) (*MasterUsage, error)
+	RestoreItem(ctx context.Context, itemID string) error
+	RestoreLocation(ctx context.Context, locationID string) error
+}
+
+// MasterDataManager defines interface for bulk import and export of item and location masters
+// 商品・ロケーションマスタの一括取り込み（バックグラウンドジョブ）と出力のインターフェースを定義
+type MasterDataManager interface {
+	StartMasterImport(ctx context.Context, request MasterImportRequest, data []byte) (*MasterImportJob, error)
+	GetMasterImportJob(ctx context.Context, jobID string) (*MasterImportJob, error)
+	ListMasterImportJobs(ctx context.Context, offset, limit int) ([]MasterImportJob, error)
+	ExportMasterData(ctx context.Context, w io.Writer, target MasterDataType, format ReportFormat, includeArchived bool) error
+}
+
+// OpeningBalanceManager defines interface for loading starting stock migrated from another system
+// 旧システムから移行する期首在庫の取り込みと一括取消のインターフェースを定義
+type OpeningBalanceManager interface {
+	ImportOpeningBalances(ctx context.Context, request OpeningBalanceRequest, data []byte) (*OpeningBalanceLoad, error)
+	GetOpeningBalanceLoad(ctx context.Context, loadID string) (*OpeningBalanceLoad, error)
+	ListOpeningBalanceLoads(ctx context.Context, offset, limit int) ([]OpeningBalanceLoad, error)
+	RollbackOpeningBalanceLoad(ctx context.Context, loadID, reason string) (*OpeningBalanceLoad, error)
 }
 
 // LotManager defines interface for lot/batch management
@@ -68,12 +118,125 @@ type LotManager interface {
 	GetExpiredLots(ctx context.Context) ([]Lot, error)
 }
 
+// TransferOrderManager defines interface for two-step transfers through an in-transit location
+// 輸送中ロケーションを経由する2段階移動のインターフェースを定義
+type TransferOrderManager interface {
+	CreateTransferOrder(ctx context.Context, order *TransferOrder) error
+	GetTransferOrder(ctx context.Context, orderID string) (*TransferOrder, error)
+	ListTransferOrders(ctx context.Context, status TransferOrderStatus, offset, limit int) ([]TransferOrder, error)
+	ShipTransferOrder(ctx context.Context, orderID string) (*TransferOrder, error)
+	ReceiveTransferOrder(ctx context.Context, orderID string, quantity int64) (*TransferOrder, error)
+	RecordTransferDiscrepancy(ctx context.Context, orderID, reason string) (*TransferOrder, error)
+}
+
+// SupplierManager defines interface for supplier master management
+// 仕入先マスタ管理のインターフェースを定義
+type SupplierManager interface {
+	CreateSupplier(ctx context.Context, supplier *Supplier) error
+	GetSupplier(ctx context.Context, supplierID string) (*Supplier, error)
+	UpdateSupplier(ctx context.Context, supplier *Supplier) error
+	ListSuppliers(ctx context.Context, offset, limit int) ([]Supplier, error)
+}
+
+// PurchaseOrderManager defines interface for purchase orders and receiving
+// 発注と入荷受付のインターフェースを定義
+type PurchaseOrderManager interface {
+	CreatePurchaseOrder(ctx context.Context, order *PurchaseOrder) error
+	GetPurchaseOrder(ctx context.Context, orderID string) (*PurchaseOrder, error)
+	ListPurchaseOrders(ctx context.Context, status PurchaseOrderStatus, supplierID string, offset, limit int) ([]PurchaseOrder, error)
+	ReceivePurchaseOrder(ctx context.Context, orderID, locationID string, receipts []PurchaseReceipt) (*PurchaseOrder, error)
+	ClosePurchaseOrder(ctx context.Context, orderID string) (*PurchaseOrder, error)
+	GetOnOrderQuantity(ctx context.Context, itemID string) (int64, error)
+}
+
+// OutboundOrderManager defines interface for outbound orders, allocation and picking
+// 出荷指示・引当・ピッキングのインターフェースを定義
+type OutboundOrderManager interface {
+	CreateOutboundOrder(ctx context.Context, order *OutboundOrder) error
+	GetOutboundOrder(ctx context.Context, orderID string) (*OutboundOrder, error)
+	ListOutboundOrders(ctx context.Context, status OutboundOrderStatus, offset, limit int) ([]OutboundOrder, error)
+	AllocateOutboundOrder(ctx context.Context, orderID string) (*OutboundOrder, error)
+	CancelOutboundOrder(ctx context.Context, orderID string) (*OutboundOrder, error)
+	GeneratePickList(ctx context.Context, orderID string) (*PickList, error)
+	ConfirmPicks(ctx context.Context, orderID string, confirmations []PickConfirmation) (*OutboundOrder, error)
+	CreateWave(ctx context.Context, wave *Wave) error
+	GetWave(ctx context.Context, waveID string) (*Wave, error)
+	GenerateWavePickList(ctx context.Context, waveID string) (*PickList, error)
+}
+
+// KitManager defines interface for bills of materials and kit assembly
+// 部品表とキット組立・分解のインターフェースを定義
+type KitManager interface {
+	SetBillOfMaterials(ctx context.Context, bom *BillOfMaterials) error
+	GetBillOfMaterials(ctx context.Context, itemID string) (*BillOfMaterials, error)
+	Assemble(ctx context.Context, kitItemID, locationID string, quantity int64, reference string) error
+	Disassemble(ctx context.Context, kitItemID, locationID string, quantity int64, reference string) error
+	CalculateKitCost(ctx context.Context, kitItemID string) (Money, error)
+}
+
+// CycleCounter defines interface for ABC-driven cycle counting
+// ABC区分に基づく循環棚卸のインターフェースを定義
+type CycleCounter interface {
+	GenerateCycleCountTasks(ctx context.Context, locationID string, date time.Time) ([]CycleCountTask, error)
+	GenerateDailyCycleCounts(ctx context.Context, date time.Time) (int, error)
+	GetCycleCountTask(ctx context.Context, taskID string) (*CycleCountTask, error)
+	ListCycleCountTasks(ctx context.Context, locationID string, status CycleCountStatus, offset, limit int) ([]CycleCountTask, error)
+	RecordCycleCount(ctx context.Context, taskID string, countedQuantity int64) (*CycleCountTask, error)
+	ReviewCycleCount(ctx context.Context, taskID string, approve bool, note string) (*CycleCountTask, error)
+}
+
+// PeriodCloser defines interface for fiscal period definitions, period close and admin reopen
+// 会計期間の定義・締め・管理者による締め解除のインターフェースを定義
+type PeriodCloser interface {
+	CreateFiscalPeriod(ctx context.Context, period *FiscalPeriod) error
+	GetFiscalPeriod(ctx context.Context, periodID string) (*FiscalPeriod, error)
+	ListFiscalPeriods(ctx context.Context) ([]FiscalPeriod, error)
+	CloseFiscalPeriod(ctx context.Context, periodID string) (*FiscalPeriod, error)
+	ReopenFiscalPeriod(ctx context.Context, periodID, reason string) (*FiscalPeriod, error)
+	ListPeriodEndBalances(ctx context.Context, periodID, locationID string, offset, limit int) ([]PeriodEndBalance, error)
+	ListFiscalPeriodEvents(ctx context.Context, periodID string) ([]FiscalPeriodEvent, error)
+}
+
+// ExchangeRateManager defines interface for exchange rates and inbound costs in foreign currencies
+// 為替レートと外貨建て仕入単価での入庫のインターフェースを定義
+type ExchangeRateManager interface {
+	SetExchangeRate(ctx context.Context, rate *ExchangeRate) error
+	ListExchangeRates(ctx context.Context, currency string) ([]ExchangeRate, error)
+	GetExchangeRate(ctx context.Context, currency string, at time.Time) (*ExchangeRate, error)
+	AddWithCost(ctx context.Context, itemID, locationID string, quantity int64, unitCost Money, currency, reference string) error
+}
+
+// ReplenishmentManager defines interface for min/max replenishment of stores from source locations
+// 最小・最大在庫に基づく店舗への補充提案と移動実行のインターフェースを定義
+type ReplenishmentManager interface {
+	SetReplenishmentPolicy(ctx context.Context, policy *ReplenishmentPolicy) error
+	ListReplenishmentPolicies(ctx context.Context, locationID string) ([]ReplenishmentPolicy, error)
+	DeleteReplenishmentPolicy(ctx context.Context, locationID, itemID string) error
+	GenerateReplenishmentProposals(ctx context.Context, locationID string) ([]ReplenishmentProposal, error)
+	GetReplenishmentProposal(ctx context.Context, proposalID string) (*ReplenishmentProposal, error)
+	ListReplenishmentProposals(ctx context.Context, locationID string, status ReplenishmentProposalStatus, offset, limit int) ([]ReplenishmentProposal, error)
+	ReviewReplenishmentProposal(ctx context.Context, proposalID string, approve bool, quantity int64) (*ReplenishmentProposal, error)
+	ExecuteReplenishmentProposals(ctx context.Context, proposalIDs []string) (*BatchOperation, error)
+}
+
+// LedgerReconciler defines interface for reconciling stock balances with the transaction ledger
+// 在庫数量と取引台帳の照合のインターフェースを定義
+type LedgerReconciler interface {
+	ReconcileLedger(ctx context.Context, fix bool) (*ReconciliationReport, error)
+}
+
 // ValuationEngine defines interface for inventory valuation
 // 在庫評価エンジンのインターフェースを定義
 type ValuationEngine interface {
-	CalculateValue(ctx context.Context, itemID, locationID string, method ValuationMethod) (float64, error)
-	CalculateTotalValue(ctx context.Context, locationID string, method ValuationMethod) (float64, error)
-	GetAverageCost(ctx context.Context, itemID string) (float64, error)
+	CalculateValue(ctx context.Context, itemID, locationID string, method ValuationMethod) (Money, error)
+	CalculateTotalValue(ctx context.Context, locationID string, method ValuationMethod) (Money, error)
+	GetAverageCost(ctx context.Context, itemID string) (Money, error)
+}
+
+// CurrencyProvider exposes the currency whose rounding rule applies to reported values
+// 評価額の表示に適用する通貨の丸めルールを提供
+type CurrencyProvider interface {
+	Currency() CurrencyRule
 }
 
 // ValuationMethod defines inventory valuation methods
@@ -94,6 +257,55 @@ type AnalyticsEngine interface {
 	GetTurnoverRate(ctx context.Context, itemID string, period time.Duration) (float64, error)
 	GetSlowMovingItems(ctx context.Context, locationID string, threshold time.Duration) ([]string, error)
 	GenerateStockReport(ctx context.Context, locationID string, reportType ReportType) ([]byte, error)
+	GenerateStockReportAsOf(ctx context.Context, locationID string, asOf time.Time) ([]byte, error)
+}
+
+// ItemClassifier defines interface for persisted ABC/XYZ classification runs
+// ABC/XYZ分類の実行・保存と履歴照会のインターフェースを定義
+type ItemClassifier interface {
+	ClassifyItems(ctx context.Context, locationID string) ([]ItemClassification, error)
+	GetItemClassifications(ctx context.Context, locationID string) ([]ItemClassification, error)
+	GetItemClassificationHistory(ctx context.Context, itemID, locationID string, limit int) ([]ItemClassification, error)
+}
+
+// KPIReporter defines interface for ledger-based inventory KPIs
+// 台帳に基づく在庫KPI（回転率・在庫日数・欠品日数・充足率）のインターフェースを定義
+type KPIReporter interface {
+	CalculateKPIs(ctx context.Context, itemID, locationID string, from, to time.Time) (*KPIReport, error)
+}
+
+// ReportWriter defines interface for streaming filtered inventory reports in several formats
+// 絞り込み条件付きの在庫レポートを各形式でストリーム出力するインターフェースを定義
+type ReportWriter interface {
+	WriteReport(ctx context.Context, w io.Writer, request ReportRequest) error
+}
+
+// AgingAnalyzer defines interface for inventory aging and dead-stock analysis
+// 受入からの経過日数による在庫エイジングと滞留在庫分析のインターフェースを定義
+type AgingAnalyzer interface {
+	GetAgingReport(ctx context.Context, locationID string, deadStockDays int) (*AgingReport, error)
+}
+
+// ReportScheduler defines interface for scheduled report generation and the report archive
+// レポートの定期生成とレポートアーカイブのインターフェースを定義
+type ReportScheduler interface {
+	CreateReportSchedule(ctx context.Context, schedule *ReportSchedule) error
+	GetReportSchedule(ctx context.Context, scheduleID string) (*ReportSchedule, error)
+	ListReportSchedules(ctx context.Context) ([]ReportSchedule, error)
+	UpdateReportSchedule(ctx context.Context, schedule *ReportSchedule) error
+	DeleteReportSchedule(ctx context.Context, scheduleID string) error
+	RunReportSchedule(ctx context.Context, scheduleID string) (*ReportArchive, error)
+	RunDueReportSchedules(ctx context.Context, now time.Time) (int, error)
+	ListReportArchives(ctx context.Context, scheduleID string, reportType ReportType, offset, limit int) ([]ReportArchive, error)
+	GetReportArchive(ctx context.Context, archiveID string) (*ReportArchive, error)
+	OpenReportArchive(ctx context.Context, archiveID string) (*ReportArchive, io.ReadCloser, error)
+}
+
+// DemandForecaster defines interface for demand forecasting and reorder suggestions
+// 需要予測と発注点・安全在庫・経済的発注量に基づく発注提案のインターフェースを定義
+type DemandForecaster interface {
+	ForecastDemand(ctx context.Context, itemID, locationID string, method ForecastMethod, horizonDays int) (*DemandForecast, error)
+	GetReorderSuggestions(ctx context.Context, locationID string, method ForecastMethod) (*ReorderReport, error)
 }
 
 // ReportType defines types of inventory reports
@@ -108,6 +320,24 @@ const (
 	ReportTypeTurnover  ReportType = "turnover"  // 回転率レポート
 )
 
+// ReportFormat defines output formats of inventory reports
+// 在庫レポートの出力形式を定義
+type ReportFormat string
+
+const (
+	ReportFormatCSV  ReportFormat = "csv"  // BOM付きUTF-8のCSV
+	ReportFormatJSON ReportFormat = "json" // JSON配列
+	ReportFormatXLSX ReportFormat = "xlsx" // Excelブック
+)
+
+// ReportRequest describes the type, output format and filters of a report
+// レポートのタイプ・出力形式・絞り込み条件を表現
+type ReportRequest struct {
+	Type   ReportType   // レポートタイプ
+	Format ReportFormat // 出力形式
+	Filter ReportFilter // 絞り込み条件
+}
+
 // Storage defines the interface for data persistence layer
 // データ永続化層のインターフェースを定義
 //
@@ -129,6 +359,10 @@ type Storage interface {
 	GetStock(ctx context.Context, itemID, locationID string) (*Stock, error)
 	// 指定されたロケーションの全ての在庫情報を取得します
 	ListStockByLocation(ctx context.Context, locationID string) ([]Stock, error)
+	// 複数の在庫記録（新規はVersion=1）とトランザクションを1つのデータベーストランザクションで書き込みます
+	ApplyStockChanges(ctx context.Context, stocks []*Stock, transactions []*Transaction) error
+	// 指定された商品の全ロケーションの在庫情報を取得します（ロケーションID順）
+	ListStockByItem(ctx context.Context, itemID string) ([]Stock, error)
 	// 指定された商品の全ロケーションでの合計在庫数を取得します
 	GetTotalStockByItem(ctx context.Context, itemID string) (int64, error)
 
@@ -141,6 +375,192 @@ type Storage interface {
 	GetTransactionHistoryByLocation(ctx context.Context, locationID string, limit int) ([]Transaction, error)
 	// 指定された商品の指定日付範囲のトランザクション履歴を取得します
 	GetTransactionHistoryByDateRange(ctx context.Context, itemID string, from, to time.Time) ([]Transaction, error)
+	// 指定ロケーションから指定期間に出庫された（取消されていない）トランザクションを取得します（取消・移動レッグを除く、古い順）
+	ListOutboundTransactions(ctx context.Context, locationID string, from, to time.Time) ([]Transaction, error)
+	// 指定されたIDのトランザクションを取得します
+	GetTransaction(ctx context.Context, transactionID string) (*Transaction, error)
+	// 取消の在庫記録・トランザクションの書き込みと元トランザクションへの取消トランザクションIDの記録を1つのデータベーストランザクションで行います。
+	// 取消済みの場合はErrTransactionAlreadyReversedを返し、何も書き込みません
+	ApplyReversal(ctx context.Context, transactionID, reversalID string, stocks []*Stock, transactions []*Transaction) error
+
+	// Fiscal periods - 会計期間
+	// 指定日時が締め済みの会計期間に含まれるかを返します
+	IsPeriodClosed(ctx context.Context, at time.Time) (bool, error)
+	// 新しい会計期間を作成します
+	CreateFiscalPeriod(ctx context.Context, period *FiscalPeriod) error
+	// 指定されたIDの会計期間を取得します
+	GetFiscalPeriod(ctx context.Context, periodID string) (*FiscalPeriod, error)
+	// 全ての会計期間を開始日順に取得します
+	ListFiscalPeriods(ctx context.Context) ([]FiscalPeriod, error)
+	// 未締めの会計期間を締め、期末の商品・ロケーション別数量と金額を保存し、締めの監査記録とともに保存件数を返します
+	// 未締めでない場合はErrInvalidFiscalPeriodStatusを返します
+	CloseFiscalPeriod(ctx context.Context, period *FiscalPeriod, event *FiscalPeriodEvent) (int64, error)
+	// 締め済みの会計期間を未締めに戻して期末残高を削除し、締め解除の監査記録を保存します
+	// 締め済みでない場合はErrInvalidFiscalPeriodStatusを返します
+	ReopenFiscalPeriod(ctx context.Context, periodID string, event *FiscalPeriodEvent) error
+	// 会計期間の期末残高を取得します（locationIDが空の場合は全ロケーション）
+	ListPeriodEndBalances(ctx context.Context, periodID, locationID string, offset, limit int) ([]PeriodEndBalance, error)
+	// 会計期間の締め・締め解除の監査記録を古い順に取得します
+	ListFiscalPeriodEvents(ctx context.Context, periodID string) ([]FiscalPeriodEvent, error)
+
+	// Exchange rates - 為替レート
+	// 為替レートを保存します（同じ通貨・基準通貨・適用開始日のレートは上書き）
+	SaveExchangeRate(ctx context.Context, rate *ExchangeRate) error
+	// 指定日時に有効な（適用開始日が指定日以前で最新の）為替レートを取得します。存在しない場合はErrExchangeRateNotFoundを返します
+	GetExchangeRate(ctx context.Context, currency, baseCurrency string, at time.Time) (*ExchangeRate, error)
+	// 基準通貨への為替レートを通貨・適用開始日の新しい順に取得します（currencyが空の場合は全通貨）
+	ListExchangeRates(ctx context.Context, currency, baseCurrency string) ([]ExchangeRate, error)
+
+	// Point-in-time stock - 時点在庫
+	// 現在の全在庫について台帳から再計算した数量をスナップショットとして保存し、作成件数を返します
+	CreateStockSnapshots(ctx context.Context, at time.Time) (int64, error)
+	// 直近のスナップショットとそれ以降の台帳から指定時点の在庫数量を再構築します
+	GetStockAsOf(ctx context.Context, itemID, locationID string, at time.Time) (int64, error)
+	// 指定ロケーションの指定時点における商品別在庫数量を再構築します（数量0は除外）
+	ListStockAsOfByLocation(ctx context.Context, locationID string, at time.Time) ([]StockSnapshot, error)
+	// 指定ロケーションの最新のスナップショット日時を取得します（スナップショットがない場合はnil）
+	GetLatestStockSnapshotAt(ctx context.Context, locationID string) (*time.Time, error)
+	// 指定された商品・ロケーションの台帳明細を取得します（fromより後、to以前、古い順）
+	ListLedgerEntries(ctx context.Context, itemID, locationID string, from, to time.Time) ([]LedgerEntry, error)
+
+	// Report streaming - レポート出力
+	// 条件に合う在庫を商品マスタとともに1行ずつ渡します（日付条件は使用しません）
+	IterateStock(ctx context.Context, filter ReportFilter, fn func(Stock, Item) error) error
+	// 条件に合う期間中のトランザクションを商品マスタとともに古い順に1行ずつ渡します
+	IterateTransactions(ctx context.Context, filter ReportFilter, fn func(Transaction, Item) error) error
+	// 台帳から集計した期間中の商品・ロケーション別の入出庫を商品マスタとともに1行ずつ渡します
+	IterateStockMovementSummaries(ctx context.Context, filter ReportFilter, fn func(StockMovementSummary, Item) error) error
+
+	// Aging - 在庫エイジング
+	// 在庫のある商品・ロケーションごとに現在庫と最終移動・最終出庫日時を取得します（locationIDが空の場合は全ロケーション）
+	ListStockPositions(ctx context.Context, locationID string) ([]StockPosition, error)
+	// 在庫のある商品・ロケーションの受入を新しい順に、先入先出で現在庫を構成する分だけ取得します
+	ListReceiptLayers(ctx context.Context, locationID string) ([]ReceiptLayer, error)
+
+	// Report schedules - レポート定期生成
+	// 新しいレポートスケジュールを作成します
+	CreateReportSchedule(ctx context.Context, schedule *ReportSchedule) error
+	// 指定されたIDのレポートスケジュールを取得します
+	GetReportSchedule(ctx context.Context, scheduleID string) (*ReportSchedule, error)
+	// レポートスケジュール一覧を取得します（作成日時順）
+	ListReportSchedules(ctx context.Context) ([]ReportSchedule, error)
+	// レポートスケジュールの設定と実行状況を更新します
+	UpdateReportSchedule(ctx context.Context, schedule *ReportSchedule) error
+	// レポートスケジュールを削除します（アーカイブ済みレポートは残ります）
+	DeleteReportSchedule(ctx context.Context, scheduleID string) error
+	// 次回実行予定日時がnow以前の有効なレポートスケジュールを取得します
+	ListDueReportSchedules(ctx context.Context, now time.Time) ([]ReportSchedule, error)
+	// 次回実行予定日時がscheduledAtのままの場合に限りnextRunAtへ進めます（複数プロセスでの二重実行防止）
+	ClaimReportScheduleRun(ctx context.Context, scheduleID string, scheduledAt, nextRunAt time.Time) (bool, error)
+	// アーカイブ済みレポートのメタデータを登録します
+	CreateReportArchive(ctx context.Context, archive *ReportArchive) error
+	// 指定されたIDのアーカイブ済みレポートを取得します
+	GetReportArchive(ctx context.Context, archiveID string) (*ReportArchive, error)
+	// アーカイブ済みレポート一覧を取得します（scheduleID・reportTypeが空の場合は全件、生成日時の新しい順）
+	ListReportArchives(ctx context.Context, scheduleID string, reportType ReportType, offset, limit int) ([]ReportArchive, error)
+
+	// Transfer orders - 移動指示
+	// 新しい移動指示を作成します
+	CreateTransferOrder(ctx context.Context, order *TransferOrder) error
+	// 指定されたIDの移動指示を取得します
+	GetTransferOrder(ctx context.Context, orderID string) (*TransferOrder, error)
+	// 移動指示の数量・ステータスを更新します（Versionは更新後の値、前バージョンの行のみ更新）
+	// 読み込み後に他の処理が更新済みの場合はErrInvalidTransferOrderStatusを返します
+	UpdateTransferOrder(ctx context.Context, order *TransferOrder) error
+	// 移動指示一覧を取得します（statusが空の場合は全件、作成日時の新しい順）
+	ListTransferOrders(ctx context.Context, status TransferOrderStatus, offset, limit int) ([]TransferOrder, error)
+
+	// Suppliers - 仕入先
+	// 新しい仕入先を作成します
+	CreateSupplier(ctx context.Context, supplier *Supplier) error
+	// 指定されたIDの仕入先を取得します
+	GetSupplier(ctx context.Context, supplierID string) (*Supplier, error)
+	// 既存の仕入先を更新します
+	UpdateSupplier(ctx context.Context, supplier *Supplier) error
+	// 仕入先一覧を取得します
+	ListSuppliers(ctx context.Context, offset, limit int) ([]Supplier, error)
+
+	// Purchase orders - 発注
+	// 発注と明細を作成します
+	CreatePurchaseOrder(ctx context.Context, order *PurchaseOrder) error
+	// 指定されたIDの発注を明細付きで取得します
+	GetPurchaseOrder(ctx context.Context, orderID string) (*PurchaseOrder, error)
+	// 発注のステータスと明細の入荷済み数量を更新します。楽観的ロックによる同時実行制御を行います
+	UpdatePurchaseOrder(ctx context.Context, order *PurchaseOrder) error
+	// 入荷の在庫記録・トランザクション・ロットと更新後の発注を1つのデータベーストランザクションで書き込みます
+	ApplyPurchaseReceipt(ctx context.Context, order *PurchaseOrder, stocks []*Stock, transactions []*Transaction, lots []*Lot) error
+	// 発注一覧を明細付きで取得します（status・supplierIDが空の場合は絞り込みなし）
+	ListPurchaseOrders(ctx context.Context, status PurchaseOrderStatus, supplierID string, offset, limit int) ([]PurchaseOrder, error)
+	// 未完了の発注における指定商品の発注残数量を取得します（locationIDが空の場合は全入荷先の合計）
+	GetOnOrderQuantity(ctx context.Context, itemID, locationID string) (int64, error)
+	// 指定商品の直近の発注先の標準リードタイム（日）を取得します（発注実績がない場合は0）
+	GetItemLeadTimeDays(ctx context.Context, itemID string) (int, error)
+
+	// Outbound orders - 出荷指示
+	// 出荷指示と明細を作成します
+	CreateOutboundOrder(ctx context.Context, order *OutboundOrder) error
+	// 指定されたIDの出荷指示を明細・引当付きで取得します
+	GetOutboundOrder(ctx context.Context, orderID string) (*OutboundOrder, error)
+	// 出荷指示のステータス・明細数量を更新し、引当を登録・更新します
+	UpdateOutboundOrder(ctx context.Context, order *OutboundOrder) error
+	// 出荷指示一覧を明細・引当付きで取得します（statusが空の場合は全件、作成日時の新しい順）
+	ListOutboundOrders(ctx context.Context, status OutboundOrderStatus, offset, limit int) ([]OutboundOrder, error)
+	// 期間内に出荷完了・一部出荷となった出荷指示における指定商品の受注数量と出荷数量を取得します
+	GetOrderFillQuantities(ctx context.Context, itemID string, from, to time.Time) (ordered, shipped int64, err error)
+	// ウェーブを作成し出荷指示を割り当てます。割り当て済みの出荷指示が含まれる場合はErrInvalidOutboundOrderStatusを返します
+	CreateWave(ctx context.Context, wave *Wave) error
+	// 指定されたIDのウェーブを出荷指示ID付きで取得します
+	GetWave(ctx context.Context, waveID string) (*Wave, error)
+
+	// Bill of materials - 部品表
+	// キット商品の構成部品を置き換えます
+	SetBillOfMaterials(ctx context.Context, bom *BillOfMaterials) error
+	// キット商品の部品表を取得します。未登録の場合はErrBOMNotFoundを返します
+	GetBillOfMaterials(ctx context.Context, itemID string) (*BillOfMaterials, error)
+
+	// Replenishment - 店舗補充
+	// 補充ポリシーを作成または更新します（補充先ロケーション・商品単位）
+	UpsertReplenishmentPolicy(ctx context.Context, policy *ReplenishmentPolicy) error
+	// 補充ポリシー一覧を取得します（locationIDが空の場合は全件）
+	ListReplenishmentPolicies(ctx context.Context, locationID string) ([]ReplenishmentPolicy, error)
+	// 補充ポリシーを削除します
+	DeleteReplenishmentPolicy(ctx context.Context, locationID, itemID string) error
+	// 補充提案を作成します
+	CreateReplenishmentProposal(ctx context.Context, proposal *ReplenishmentProposal) error
+	// 指定されたIDの補充提案を取得します
+	GetReplenishmentProposal(ctx context.Context, proposalID string) (*ReplenishmentProposal, error)
+	// 指定ステータスのままの補充提案のステータス・数量・実行結果を更新します
+	UpdateReplenishmentProposal(ctx context.Context, proposal *ReplenishmentProposal, fromStatus ReplenishmentProposalStatus) error
+	// 補充提案一覧を取得します（locationID・statusが空の場合は絞り込みなし、作成日時の新しい順）
+	ListReplenishmentProposals(ctx context.Context, locationID string, status ReplenishmentProposalStatus, offset, limit int) ([]ReplenishmentProposal, error)
+	// 承認待ち・承認済み（未実行）の補充提案をすべて取得します
+	ListOpenReplenishmentProposals(ctx context.Context) ([]ReplenishmentProposal, error)
+
+	// Cycle counts - 循環棚卸
+	// 新しい棚卸タスクを作成します
+	CreateCycleCountTask(ctx context.Context, task *CycleCountTask) error
+	// 指定されたIDの棚卸タスクを取得します
+	GetCycleCountTask(ctx context.Context, taskID string) (*CycleCountTask, error)
+	// 指定ステータスのままの棚卸タスクの実棚結果・ステータスを更新します
+	UpdateCycleCountTask(ctx context.Context, task *CycleCountTask, fromStatus CycleCountStatus) error
+	// 指定ステータスのままの棚卸タスクの承認と差異の在庫調整を1つのトランザクションで反映します
+	ApplyCycleCountSettlement(ctx context.Context, task *CycleCountTask, fromStatus CycleCountStatus, stocks []*Stock, transactions []*Transaction) error
+	// 棚卸タスク一覧を取得します（locationID・statusが空の場合は絞り込みなし、予定日の新しい順）
+	ListCycleCountTasks(ctx context.Context, locationID string, status CycleCountStatus, offset, limit int) ([]CycleCountTask, error)
+	// 指定ロケーションの商品ごとに最新（予定日が最も新しい）の棚卸タスクを取得します
+	ListLatestCycleCountTasks(ctx context.Context, locationID string) ([]CycleCountTask, error)
+
+	// Item classifications - 商品分類
+	// 1回の分類結果（同一の分類日時）を履歴として保存します
+	SaveItemClassifications(ctx context.Context, classifications []ItemClassification) error
+	// 指定ロケーションの最新の分類結果を取得します
+	ListLatestItemClassifications(ctx context.Context, locationID string) ([]ItemClassification, error)
+	// 指定された商品・ロケーションの分類履歴を取得します（新しい順）
+	ListItemClassificationHistory(ctx context.Context, itemID, locationID string, limit int) ([]ItemClassification, error)
+
+	// Reconciliation - 台帳照合
+	// 在庫テーブルの数量と台帳から再計算した残高が一致しない(商品, ロケーション)を取得します
+	ListStockDrifts(ctx context.Context) ([]StockDrift, error)
 
 	// Item management - 商品管理
 	// 新しい商品を作成します。重複するIDの場合はエラーを返します
@@ -149,12 +569,81 @@ type Storage interface {
 	GetItem(ctx context.Context, itemID string) (*Item, error)
 	// 既存の商品情報を更新します
 	UpdateItem(ctx context.Context, item *Item) error
+	// 条件に一致する商品を指定の並び順でカーソル位置から取得し、全件数と次ページのカーソルを返します
+	// キーワードは部分一致で判定し、Limitが0の場合は全件を返します
+	ListItems(ctx context.Context, filter ItemSearchFilter) (*ItemPage, error)
+	// ListItemsと同様ですが、キーワードを商品名・SKU・説明へのトライグラム類似度であいまい検索し、類似度順に並べられます
+	SearchItems(ctx context.Context, filter ItemSearchFilter) (*ItemPage, error)
+	// 商品を参照している在庫・引当・未完了の伝票・バリアント・キットの件数を集計します
+	GetItemUsage(ctx context.Context, itemID string) (*MasterUsage, error)
+	// 商品のアーカイブ日時を設定します（nilでアーカイブ解除）。存在しない場合はErrItemNotFoundを返します
+	SetItemArchivedAt(ctx context.Context, itemID string, archivedAt *time.Time) error
+
+	// Item barcodes - 商品バーコード
+	// バーコードを保存します。同じGTINが登録済みの場合はErrDuplicateBarcodeを返します
+	SaveItemBarcode(ctx context.Context, barcode *ItemBarcode) error
+	// 14桁に正規化したGTINでバーコードを取得します。存在しない場合はErrBarcodeNotFoundを返します
+	GetItemBarcode(ctx context.Context, gtin string) (*ItemBarcode, error)
+	// 商品のバーコードを登録順に取得します
+	ListItemBarcodes(ctx context.Context, itemID string) ([]ItemBarcode, error)
+	// 商品のバーコードを削除します。存在しない場合はErrBarcodeNotFoundを返します
+	DeleteItemBarcode(ctx context.Context, itemID, gtin string) error
+
+	// Item attributes and variants - 商品属性とバリアント
+	// カテゴリの属性スキーマを保存します（同じカテゴリのスキーマは上書き）
+	SaveCategoryAttributeSchema(ctx context.Context, schema *CategoryAttributeSchema) error
+	// カテゴリの属性スキーマを取得します。存在しない場合はErrAttributeSchemaNotFoundを返します
+	GetCategoryAttributeSchema(ctx context.Context, category string) (*CategoryAttributeSchema, error)
+	// 全カテゴリの属性スキーマをカテゴリ順に取得します
+	ListCategoryAttributeSchemas(ctx context.Context) ([]CategoryAttributeSchema, error)
+	// バリアントグループを保存します（同じ親商品のグループは上書き）
+	SaveVariantGroup(ctx context.Context, group *VariantGroup) error
+	// 親商品のバリアントグループを取得します（Variantsは設定しません）。存在しない場合はErrVariantGroupNotFoundを返します
+	GetVariantGroup(ctx context.Context, parentItemID string) (*VariantGroup, error)
 
 	// Location management - ロケーション管理
 	// 新しいロケーションを作成します
 	CreateLocation(ctx context.Context, location *Location) error
 	// 指定されたIDのロケーション情報を取得します
 	GetLocation(ctx context.Context, locationID string) (*Location, error)
+	// ロケーション情報を更新します。存在しない場合はErrLocationNotFoundを返します
+	UpdateLocation(ctx context.Context, location *Location) error
+	// ロケーション一覧を取得します（作成日時の新しい順）。includeArchivedがfalseの場合はアーカイブ済みを除外します
+	ListLocations(ctx context.Context, offset, limit int, includeArchived bool) ([]Location, error)
+	// ロケーションを参照している在庫・引当・未完了の伝票の件数を集計します
+	GetLocationUsage(ctx context.Context, locationID string) (*MasterUsage, error)
+	// ロケーションのアーカイブ日時を設定します（nilでアーカイブ解除）。存在しない場合はErrLocationNotFoundを返します
+	SetLocationArchivedAt(ctx context.Context, locationID string, archivedAt *time.Time) error
+
+	// Batch operations - バッチ操作
+	// バッチ操作の実行結果を保存します
+	CreateBatchOperation(ctx context.Context, batch *BatchOperation) error
+	// 指定されたIDのバッチ操作の実行結果を取得します。存在しない場合はErrBatchNotFoundを返します
+	GetBatchOperation(ctx context.Context, batchID string) (*BatchOperation, error)
+
+	// Master import jobs - マスタ取り込みジョブ
+	// マスタ取り込みジョブを登録します
+	CreateMasterImportJob(ctx context.Context, job *MasterImportJob) error
+	// マスタ取り込みジョブの状態・進捗・行エラーを更新します
+	UpdateMasterImportJob(ctx context.Context, job *MasterImportJob) error
+	// 指定されたIDのマスタ取り込みジョブを取得します。存在しない場合はErrMasterImportJobNotFoundを返します
+	GetMasterImportJob(ctx context.Context, jobID string) (*MasterImportJob, error)
+	// マスタ取り込みジョブ一覧を取得します（受付日時の新しい順）
+	ListMasterImportJobs(ctx context.Context, offset, limit int) ([]MasterImportJob, error)
+
+	// Opening balances - 期首在庫
+	// 期首在庫の取り込みを明細付きで登録します
+	CreateOpeningBalanceLoad(ctx context.Context, load *OpeningBalanceLoad) error
+	// 期首在庫の取り込みの状態と明細の計上・取消結果を更新します
+	UpdateOpeningBalanceLoad(ctx context.Context, load *OpeningBalanceLoad) error
+	// 計上済みの期首在庫の取り込み・明細と在庫記録・入庫トランザクション・ロットを1つのデータベーストランザクションで書き込みます
+	ApplyOpeningBalanceLoad(ctx context.Context, load *OpeningBalanceLoad, stocks []*Stock, transactions []*Transaction, lots []*Lot) error
+	// 期首在庫の取消の在庫記録・取消トランザクション・取消参照・ロット削除と取り込みの更新を1つのデータベーストランザクションで書き込みます
+	ApplyOpeningBalanceRollback(ctx context.Context, load *OpeningBalanceLoad, stocks []*Stock, transactions []*Transaction, lotIDs []string) error
+	// 指定されたIDの期首在庫の取り込みを明細付きで取得します。存在しない場合はErrOpeningBalanceLoadNotFoundを返します
+	GetOpeningBalanceLoad(ctx context.Context, loadID string) (*OpeningBalanceLoad, error)
+	// 期首在庫の取り込み一覧を取得します（明細なし、取り込み日時の新しい順）
+	ListOpeningBalanceLoads(ctx context.Context, offset, limit int) ([]OpeningBalanceLoad, error)
 
 	// Lot management - ロット管理
 	// 新しいロット（バッチ）を作成します
@@ -163,6 +652,8 @@ type Storage interface {
 	GetLot(ctx context.Context, lotID string) (*Lot, error)
 	// 指定された商品の全てのロット情報を取得します
 	GetLotsByItem(ctx context.Context, itemID string) ([]Lot, error)
+	// ロットを削除します（期首在庫の取消時）。存在しない場合はErrLotNotFoundを返します
+	DeleteLot(ctx context.Context, lotID string) error
 
 	// Alert management - アラート管理
 	// 新しいアラートを作成します（低在庫、期限切れなど）
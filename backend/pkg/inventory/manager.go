@@ -3,6 +3,9 @@ package inventory
 import (
 	"context"
 	"fmt"
+	"math"
+	"sort"
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
@@ -11,30 +14,42 @@ import (
 // Manager implements the InventoryManager interface
 // InventoryManagerインターフェースの実装
 type Manager struct {
-	storage   Storage        // ストレージ層
-	publisher EventPublisher // イベント発行者
-	logger    *zap.Logger    // ログ
-	config    *Config        // 設定
+	storage   Storage         // ストレージ層
+	publisher EventPublisher  // イベント発行者
+	valuation ValuationEngine // 在庫評価エンジン（キット原価の積み上げに使用）
+	analytics AnalyticsEngine // 在庫分析エンジン（循環棚卸のABC区分に使用）
+	logger    *zap.Logger     // ログ
+	config    *Config         // 設定
 }
 
 // すべてのインターフェースを実装することを明示
 var (
-	_ InventoryManager = (*Manager)(nil)
-	_ ItemManager      = (*Manager)(nil)
-	_ LocationManager  = (*Manager)(nil)
-	_ LotManager       = (*Manager)(nil)
+	_ InventoryManager      = (*Manager)(nil)
+	_ ItemManager           = (*Manager)(nil)
+	_ LocationManager       = (*Manager)(nil)
+	_ LotManager            = (*Manager)(nil)
+	_ MasterArchiveManager  = (*Manager)(nil)
+	_ MasterDataManager     = (*Manager)(nil)
+	_ OpeningBalanceManager = (*Manager)(nil)
 )
 
 // Config holds configuration for the inventory manager
 // 在庫マネージャーの設定を保持
 type Config struct {
-	AllowNegativeStock bool          `yaml:"allow_negative_stock"` // 負の在庫を許可
-	DefaultLocation    string        `yaml:"default_location"`     // デフォルトロケーション
-	AuditEnabled       bool          `yaml:"audit_enabled"`        // 監査ログ有効
-	LowStockThreshold  int64         `yaml:"low_stock_threshold"`  // 低在庫閾値
-	AlertTimeout       time.Duration `yaml:"alert_timeout"`        // アラートタイムアウト
+	AllowNegativeStock bool             `yaml:"allow_negative_stock"` // 負の在庫を許可
+	DefaultLocation    string           `yaml:"default_location"`     // デフォルトロケーション
+	AuditEnabled       bool             `yaml:"audit_enabled"`        // 監査ログ有効
+	LowStockThreshold  int64            `yaml:"low_stock_threshold"`  // 低在庫閾値
+	AlertTimeout       time.Duration    `yaml:"alert_timeout"`        // アラートタイムアウト
+	InTransitLocation  string           `yaml:"in_transit_location"`  // 輸送中在庫の仮想ロケーション
+	CycleCountPolicy   CycleCountPolicy `yaml:"cycle_count_policy"`   // ABC区分ごとの棚卸頻度と許容差異率
+	BaseCurrency       string           `yaml:"base_currency"`        // 在庫評価の基準通貨（外貨建ての仕入単価はこの通貨に換算）
 }
 
+// DefaultInTransitLocation is the virtual location holding stock shipped but not yet received
+// 出荷済み・未入荷の在庫を保持する仮想ロケーションの既定ID
+const DefaultInTransitLocation = "IN-TRANSIT"
+
 // NewManager creates a new inventory manager
 // 新しい在庫マネージャーを作成
 func NewManager(storage Storage, publisher EventPublisher, logger *zap.Logger, config *Config) *Manager {
@@ -45,6 +60,9 @@ func NewManager(storage Storage, publisher EventPublisher, logger *zap.Logger, c
 			AuditEnabled:       true,
 			LowStockThreshold:  10,
 			AlertTimeout:       time.Hour * 24,
+			InTransitLocation:  DefaultInTransitLocation,
+			CycleCountPolicy:   DefaultCycleCountPolicy(),
+			BaseCurrency:       DefaultCurrency,
 		}
 	}
 
@@ -59,19 +77,76 @@ func NewManager(storage Storage, publisher EventPublisher, logger *zap.Logger, c
 // Add adds inventory to a specific location
 // 指定ロケーションに在庫を追加
 func (m *Manager) Add(ctx context.Context, itemID, locationID string, quantity int64, reference string) error {
+	_, err := m.addStock(ctx, itemID, locationID, quantity, reference, nil)
+	return err
+}
+
+// AddWithCost adds inventory received at a unit cost in the given currency, converting the cost to the base currency
+// 指定通貨建ての仕入単価で在庫を追加（単価は計上日に有効な為替レートで基準通貨に換算）
+func (m *Manager) AddWithCost(ctx context.Context, itemID, locationID string, quantity int64, unitCost Money, currency, reference string) error {
+	if err := ValidateUnitCost(unitCost); err != nil {
+		return err
+	}
+	_, err := m.addStock(ctx, itemID, locationID, quantity, reference, &inboundDetails{UnitCost: &unitCost, Currency: currency})
+	return err
+}
+
+// inboundDetails carries optional cost and lot information recorded on an inbound transaction
+// 入庫トランザクションに記録する単価・ロット情報（任意）
+type inboundDetails struct {
+	UnitCost   *Money
+	Currency   string // 単価の通貨（空の場合は基準通貨）
+	LotNumber  *string
+	ExpiryDate *time.Time
+	Metadata   map[string]string // 追加メタデータ（期首在庫の取り込みIDなど）
+}
+
+// addStock adds inventory and records the inbound transaction with optional details
+// 在庫を追加し、単価・ロット情報付きの入庫トランザクションを記録して返す
+func (m *Manager) addStock(ctx context.Context, itemID, locationID string, quantity int64, reference string, details *inboundDetails) (*Transaction, error) {
 	if quantity <= 0 {
-		return NewValidationError("quantity", "数量は正の値である必要があります", fmt.Sprintf("%d", quantity))
+		return nil, NewValidationError("quantity", "数量は正の値である必要があります", fmt.Sprintf("%d", quantity))
+	}
+
+	// 計上日時と会計期間の確認
+	postedAt, err := m.postingTime(ctx, locationID)
+	if err != nil {
+		return nil, err
 	}
 
 	// 商品とロケーションの存在確認
-	if err := m.validateItemAndLocation(ctx, itemID, locationID); err != nil {
-		return err
+	item, err := m.validateItemAndLocation(ctx, itemID, locationID)
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateItemQuantity(quantity, item.QuantityPrecision, false); err != nil {
+		return nil, err
+	}
+
+	tx := &Transaction{
+		ID:         NewTransactionID(),
+		Type:       TransactionTypeInbound,
+		ItemID:     itemID,
+		ToLocation: &locationID,
+		Quantity:   quantity,
+		Reference:  reference,
+		CreatedAt:  postedAt,
+		CreatedBy:  m.getUserFromContext(ctx),
+	}
+	if details != nil {
+		tx.LotNumber = details.LotNumber
+		tx.ExpiryDate = details.ExpiryDate
+		tx.Metadata = details.Metadata
+		// 外貨建ての単価は在庫を更新する前に換算（為替レート未登録なら計上しない）
+		if err := m.convertInboundCost(ctx, tx, details.UnitCost, details.Currency); err != nil {
+			return nil, err
+		}
 	}
 
 	// 現在の在庫を取得または初期化
 	stock, err := m.storage.GetStock(ctx, itemID, locationID)
 	if err != nil && err != ErrStockNotFound {
-		return NewStorageError("get_stock", "在庫取得に失敗しました", err)
+		return nil, NewStorageError("get_stock", "在庫取得に失敗しました", err)
 	}
 
 	oldQuantity := int64(0)
@@ -89,7 +164,7 @@ func (m *Manager) Add(ctx context.Context, itemID, locationID string, quantity i
 		stock.CalculateAvailable()
 
 		if err := m.storage.CreateStock(ctx, stock); err != nil {
-			return NewStorageError("create_stock", "在庫作成に失敗しました", err)
+			return nil, NewStorageError("create_stock", "在庫作成に失敗しました", err)
 		}
 	} else {
 		// 既存の在庫を更新
@@ -101,7 +176,7 @@ func (m *Manager) Add(ctx context.Context, itemID, locationID string, quantity i
 		stock.CalculateAvailable()
 
 		if err := m.storage.UpdateStock(ctx, stock); err != nil {
-			return NewStorageError("update_stock", "在庫更新に失敗しました", err)
+			return nil, NewStorageError("update_stock", "在庫更新に失敗しました", err)
 		}
 	}
 
@@ -124,6 +199,60 @@ func (m *Manager) Add(ctx context.Context, itemID, locationID string, quantity i
 	}
 
 	// トランザクション記録
+	if err := m.storage.CreateTransaction(ctx, tx); err != nil {
+		m.logger.Error("トランザクション記録に失敗しました", zap.Error(err))
+	}
+
+	m.logger.Info("在庫追加完了",
+		zap.String("item_id", itemID),
+		zap.String("location_id", locationID),
+		zap.Int64("quantity", quantity),
+		zap.String("reference", reference),
+	)
+
+	return tx, nil
+}
+
+// stockChangeSet collects stock records and transactions that are written together in one database transaction
+// 1つのデータベーストランザクションでまとめて書き込む在庫記録とトランザクション
+type stockChangeSet struct {
+	stocks        []*Stock
+	byKey         map[string]*Stock
+	oldQuantities map[*Stock]int64
+	transactions  []*Transaction
+}
+
+// newStockChangeSet creates an empty stock change set
+// 空の在庫変更セットを作成
+func newStockChangeSet() *stockChangeSet {
+	return &stockChangeSet{
+		byKey:         make(map[string]*Stock),
+		oldQuantities: make(map[*Stock]int64),
+	}
+}
+
+// stageInbound validates an inbound movement and adds it to the change set without writing it
+// 入庫を検証し、書き込まずに在庫変更セットへ追加（同じ商品・ロケーションの入庫は1件の在庫記録にまとめる）
+func (m *Manager) stageInbound(ctx context.Context, changes *stockChangeSet, itemID, locationID string, quantity int64, reference string, details *inboundDetails) (*Transaction, error) {
+	if quantity <= 0 {
+		return nil, NewValidationError("quantity", "数量は正の値である必要があります", fmt.Sprintf("%d", quantity))
+	}
+
+	// 計上日時と会計期間の確認
+	postedAt, err := m.postingTime(ctx, locationID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 商品とロケーションの存在確認
+	item, err := m.validateItemAndLocation(ctx, itemID, locationID)
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateItemQuantity(quantity, item.QuantityPrecision, false); err != nil {
+		return nil, err
+	}
+
 	tx := &Transaction{
 		ID:         NewTransactionID(),
 		Type:       TransactionTypeInbound,
@@ -131,33 +260,126 @@ func (m *Manager) Add(ctx context.Context, itemID, locationID string, quantity i
 		ToLocation: &locationID,
 		Quantity:   quantity,
 		Reference:  reference,
-		CreatedAt:  time.Now(),
+		CreatedAt:  postedAt,
 		CreatedBy:  m.getUserFromContext(ctx),
 	}
+	if details != nil {
+		tx.LotNumber = details.LotNumber
+		tx.ExpiryDate = details.ExpiryDate
+		tx.Metadata = details.Metadata
+		if err := m.convertInboundCost(ctx, tx, details.UnitCost, details.Currency); err != nil {
+			return nil, err
+		}
+	}
 
-	if err := m.storage.CreateTransaction(ctx, tx); err != nil {
-		m.logger.Error("トランザクション記録に失敗しました", zap.Error(err))
+	stock, err := m.stagedStock(ctx, changes, itemID, locationID)
+	if err != nil {
+		return nil, err
 	}
+	stock.Quantity += quantity
+	stock.UpdatedAt = time.Now()
+	stock.UpdatedBy = tx.CreatedBy
+	stock.CalculateAvailable()
+	changes.transactions = append(changes.transactions, tx)
 
-	m.logger.Info("在庫追加完了",
-		zap.String("item_id", itemID),
-		zap.String("location_id", locationID),
-		zap.Int64("quantity", quantity),
-		zap.String("reference", reference),
-	)
+	return tx, nil
+}
+
+// stagedStock returns the stock record of the change set for an item and location, reading it on first use
+// 在庫変更セット内の商品・ロケーションの在庫記録を返す（初回は読み込み、未作成の場合は新規として追加）
+func (m *Manager) stagedStock(ctx context.Context, changes *stockChangeSet, itemID, locationID string) (*Stock, error) {
+	key := itemID + "\x00" + locationID
+	if stock, ok := changes.byKey[key]; ok {
+		return stock, nil
+	}
+
+	stock, err := m.storage.GetStock(ctx, itemID, locationID)
+	if err != nil && err != ErrStockNotFound {
+		return nil, NewStorageError("get_stock", "在庫取得に失敗しました", err)
+	}
+	if stock == nil {
+		stock = &Stock{ItemID: itemID, LocationID: locationID, Version: 0}
+	}
+	// 書き込み時は読み込んだバージョンの次で楽観的ロックを取る
+	changes.oldQuantities[stock] = stock.Quantity
+	stock.Version++
+	changes.byKey[key] = stock
+	changes.stocks = append(changes.stocks, stock)
+	return stock, nil
+}
+
+// publishStockChanges publishes stock changed events for a change set that has been written
+// 書き込み済みの在庫変更セットの在庫変更イベントを発行
+func (m *Manager) publishStockChanges(ctx context.Context, changes *stockChangeSet, changeType, reference string) {
+	if m.publisher == nil {
+		return
+	}
+	for _, stock := range changes.stocks {
+		event := StockChangedEvent{
+			ItemID:        stock.ItemID,
+			LocationID:    stock.LocationID,
+			OldQuantity:   changes.oldQuantities[stock],
+			NewQuantity:   stock.Quantity,
+			ChangeType:    changeType,
+			Reference:     reference,
+			TransactionID: NewTransactionID(),
+			Timestamp:     time.Now(),
+			UserID:        m.getUserFromContext(ctx),
+		}
+		if err := m.publisher.PublishStockChanged(ctx, event); err != nil {
+			m.logger.Error("イベント発行に失敗しました", zap.Error(err))
+		}
+	}
+}
+
+// convertInboundCost sets the inbound unit cost in the base currency, keeping the source currency, cost and rate
+// 入庫単価を基準通貨で設定し、外貨建ての場合は換算元の通貨・単価・為替レートをトランザクションに残す
+func (m *Manager) convertInboundCost(ctx context.Context, tx *Transaction, unitCost *Money, currency string) error {
+	tx.UnitCost = unitCost
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if unitCost == nil || currency == "" || currency == m.baseCurrency() {
+		return nil
+	}
+
+	rate, err := m.GetExchangeRate(ctx, currency, tx.CreatedAt)
+	if err != nil {
+		return err
+	}
 
+	sourceCost := *unitCost
+	baseCost := sourceCost.Convert(rate.Rate)
+	tx.UnitCost = &baseCost
+	tx.SourceUnitCost = &sourceCost
+	tx.CostCurrency = &currency
+	tx.ExchangeRate = &rate.Rate
 	return nil
 }
 
 // Remove removes inventory from a specific location
 // 指定ロケーションから在庫を削除
 func (m *Manager) Remove(ctx context.Context, itemID, locationID string, quantity int64, reference string) error {
+	return m.removeStock(ctx, itemID, locationID, quantity, reference, nil)
+}
+
+// removeStock removes inventory and records the outbound transaction with optional metadata
+// 在庫を削除し、追加メタデータ付きの出庫トランザクションを記録
+func (m *Manager) removeStock(ctx context.Context, itemID, locationID string, quantity int64, reference string, metadata map[string]string) error {
 	if quantity <= 0 {
 		return NewValidationError("quantity", "数量は正の値である必要があります", fmt.Sprintf("%d", quantity))
 	}
 
+	// 計上日時と会計期間の確認
+	postedAt, err := m.postingTime(ctx, locationID)
+	if err != nil {
+		return err
+	}
+
 	// 商品とロケーションの存在確認
-	if err := m.validateItemAndLocation(ctx, itemID, locationID); err != nil {
+	item, err := m.validateItemAndLocation(ctx, itemID, locationID)
+	if err != nil {
+		return err
+	}
+	if err := ValidateItemQuantity(quantity, item.QuantityPrecision, false); err != nil {
 		return err
 	}
 
@@ -210,9 +432,9 @@ func (m *Manager) Remove(ctx context.Context, itemID, locationID string, quantit
 		}
 	}
 
-	// 低在庫アラートチェック
-	if stock.Quantity <= m.config.LowStockThreshold {
-		m.triggerLowStockAlert(ctx, itemID, locationID, stock.Quantity)
+	// 低在庫アラートチェック（輸送中ロケーションは対象外）
+	if stock.Quantity <= m.lowStockThreshold(item.QuantityPrecision) && locationID != m.inTransitLocation() {
+		m.triggerLowStockAlert(ctx, itemID, locationID, stock.Quantity, item.QuantityPrecision)
 	}
 
 	// トランザクション記録
@@ -223,7 +445,8 @@ func (m *Manager) Remove(ctx context.Context, itemID, locationID string, quantit
 		FromLocation: &locationID,
 		Quantity:     quantity,
 		Reference:    reference,
-		CreatedAt:    time.Now(),
+		Metadata:     metadata,
+		CreatedAt:    postedAt,
 		CreatedBy:    m.getUserFromContext(ctx),
 	}
 
@@ -241,6 +464,12 @@ func (m *Manager) Remove(ctx context.Context, itemID, locationID string, quantit
 	return nil
 }
 
+// TransferMetadataKey is the transaction metadata key linking outbound and inbound legs to their transfer transaction
+// 移動の出庫・入庫レッグを移動トランザクションに紐付けるトランザクションメタデータのキー
+//
+// レッグは台帳残高のための記録であり、出庫実績（消費量）の集計には含めません。
+const TransferMetadataKey = "transfer"
+
 // Transfer moves inventory between locations
 // ロケーション間で在庫を移動
 func (m *Manager) Transfer(ctx context.Context, itemID, fromLocationID, toLocationID string, quantity int64, reference string) error {
@@ -252,23 +481,33 @@ func (m *Manager) Transfer(ctx context.Context, itemID, fromLocationID, toLocati
 		return NewValidationError("location", "移動元と移動先が同じです", fmt.Sprintf("%s -> %s", fromLocationID, toLocationID))
 	}
 
+	// 計上日時と会計期間の確認
+	postedAt, err := m.postingTime(ctx, fromLocationID, toLocationID)
+	if err != nil {
+		return err
+	}
+
 	// 商品とロケーションの存在確認
-	if err := m.validateItemAndLocation(ctx, itemID, fromLocationID); err != nil {
+	if _, err := m.validateItemAndLocation(ctx, itemID, fromLocationID); err != nil {
 		return err
 	}
-	if err := m.validateItemAndLocation(ctx, itemID, toLocationID); err != nil {
+	if _, err := m.validateItemAndLocation(ctx, itemID, toLocationID); err != nil {
 		return err
 	}
 
+	// 出庫・入庫レッグは移動トランザクションのIDで紐付け
+	transferID := NewTransactionID()
+	legMetadata := map[string]string{TransferMetadataKey: transferID}
+
 	// 移動元から在庫を削除
-	if err := m.Remove(ctx, itemID, fromLocationID, quantity, reference); err != nil {
+	if err := m.removeStock(ctx, itemID, fromLocationID, quantity, reference, legMetadata); err != nil {
 		return err
 	}
 
 	// 移動先に在庫を追加
-	if err := m.Add(ctx, itemID, toLocationID, quantity, reference); err != nil {
+	if _, err := m.addStock(ctx, itemID, toLocationID, quantity, reference, &inboundDetails{Metadata: legMetadata}); err != nil {
 		// ロールバック処理（移動元に戻す）
-		if rollbackErr := m.Add(ctx, itemID, fromLocationID, quantity, reference+"_ROLLBACK"); rollbackErr != nil {
+		if _, rollbackErr := m.addStock(ctx, itemID, fromLocationID, quantity, reference+"_ROLLBACK", &inboundDetails{Metadata: legMetadata}); rollbackErr != nil {
 			m.logger.Error("ロールバック失敗", zap.Error(rollbackErr))
 		}
 		return err
@@ -282,7 +521,7 @@ func (m *Manager) Transfer(ctx context.Context, itemID, fromLocationID, toLocati
 			ToLocationID:   toLocationID,
 			Quantity:       quantity,
 			Reference:      reference,
-			TransactionID:  NewTransactionID(),
+			TransactionID:  transferID,
 			Timestamp:      time.Now(),
 			UserID:         m.getUserFromContext(ctx),
 		}
@@ -293,14 +532,14 @@ func (m *Manager) Transfer(ctx context.Context, itemID, fromLocationID, toLocati
 
 	// 移動トランザクション記録
 	tx := &Transaction{
-		ID:           NewTransactionID(),
+		ID:           transferID,
 		Type:         TransactionTypeTransfer,
 		ItemID:       itemID,
 		FromLocation: &fromLocationID,
 		ToLocation:   &toLocationID,
 		Quantity:     quantity,
 		Reference:    reference,
-		CreatedAt:    time.Now(),
+		CreatedAt:    postedAt,
 		CreatedBy:    m.getUserFromContext(ctx),
 	}
 
@@ -326,8 +565,18 @@ func (m *Manager) Adjust(ctx context.Context, itemID, locationID string, newQuan
 		return NewValidationError("quantity", "負の在庫は許可されていません", fmt.Sprintf("%d", newQuantity))
 	}
 
+	// 計上日時と会計期間の確認
+	postedAt, err := m.postingTime(ctx, locationID)
+	if err != nil {
+		return err
+	}
+
 	// 商品とロケーションの存在確認
-	if err := m.validateItemAndLocation(ctx, itemID, locationID); err != nil {
+	item, err := m.validateItemAndLocation(ctx, itemID, locationID)
+	if err != nil {
+		return err
+	}
+	if err := ValidateItemQuantity(newQuantity, item.QuantityPrecision, m.config.AllowNegativeStock); err != nil {
 		return err
 	}
 
@@ -394,7 +643,7 @@ func (m *Manager) Adjust(ctx context.Context, itemID, locationID string, newQuan
 		ToLocation: &locationID,
 		Quantity:   newQuantity - oldQuantity, // 差分を記録
 		Reference:  reference,
-		CreatedAt:  time.Now(),
+		CreatedAt:  postedAt,
 		CreatedBy:  m.getUserFromContext(ctx),
 	}
 
@@ -413,6 +662,177 @@ func (m *Manager) Adjust(ctx context.Context, itemID, locationID string, newQuan
 	return nil
 }
 
+// ReverseTransaction posts the exact opposite movement of a transaction and links both records
+// トランザクションの逆仕訳を計上し、元取引と取消取引を相互参照させる
+func (m *Manager) ReverseTransaction(ctx context.Context, transactionID, reference, reason string) (*Transaction, error) {
+	if transactionID == "" {
+		return nil, NewValidationError("transaction_id", "トランザクションIDは必須です", transactionID)
+	}
+
+	original, err := m.storage.GetTransaction(ctx, transactionID)
+	if err != nil {
+		if err == ErrTransactionNotFound {
+			return nil, ErrTransactionNotFound
+		}
+		return nil, NewStorageError("get_transaction", "トランザクション取得に失敗しました", err)
+	}
+
+	if original.ReversedBy != nil {
+		return nil, ErrTransactionAlreadyReversed
+	}
+	if original.ReversalOf != nil {
+		return nil, ErrReversalNotReversible
+	}
+	// 移動のレッグは移動トランザクションの取消でのみ戻す
+	if original.Metadata[TransferMetadataKey] != "" {
+		return nil, ErrTransferLegNotReversible
+	}
+
+	// 締め済み期間の取引は取消不可
+	closed, err := m.storage.IsPeriodClosed(ctx, original.CreatedAt)
+	if err != nil {
+		return nil, NewStorageError("is_period_closed", "会計期間の確認に失敗しました", err)
+	}
+	if closed {
+		return nil, ErrPeriodClosed
+	}
+
+	// 取消の計上日時と会計期間の確認
+	postedAt, err := m.postingTime(ctx, transactionLocations(original)...)
+	if err != nil {
+		return nil, err
+	}
+
+	if reference == "" {
+		reference = "REVERSAL-" + original.Reference
+	}
+
+	reversal := &Transaction{
+		ID:         NewTransactionID(),
+		Type:       original.Type,
+		ItemID:     original.ItemID,
+		Quantity:   original.Quantity,
+		UnitCost:   original.UnitCost,
+		Reference:  reference,
+		LotNumber:  original.LotNumber,
+		ExpiryDate: original.ExpiryDate,
+		Metadata:   map[string]string{"reason": reason},
+		CreatedAt:  postedAt,
+		CreatedBy:  m.getUserFromContext(ctx),
+		ReversalOf: &original.ID,
+
+		// 外貨建ての入庫は計上時の為替レートのまま取り消す
+		CostCurrency:   original.CostCurrency,
+		SourceUnitCost: original.SourceUnitCost,
+		ExchangeRate:   original.ExchangeRate,
+	}
+
+	// 逆方向の移動と在庫増減を決定
+	var deltas []stockDelta
+	switch original.Type {
+	case TransactionTypeInbound:
+		if original.ToLocation == nil {
+			return nil, NewBusinessRuleError("reversal", "入庫先のないトランザクションは取り消せません", original.ID)
+		}
+		reversal.Type = TransactionTypeOutbound
+		reversal.FromLocation = original.ToLocation
+		deltas = []stockDelta{{*original.ToLocation, -original.Quantity}}
+	case TransactionTypeOutbound:
+		if original.FromLocation == nil {
+			return nil, NewBusinessRuleError("reversal", "出庫元のないトランザクションは取り消せません", original.ID)
+		}
+		reversal.Type = TransactionTypeInbound
+		reversal.ToLocation = original.FromLocation
+		deltas = []stockDelta{{*original.FromLocation, original.Quantity}}
+	case TransactionTypeTransfer:
+		if original.FromLocation == nil || original.ToLocation == nil {
+			return nil, NewBusinessRuleError("reversal", "移動元・移動先のないトランザクションは取り消せません", original.ID)
+		}
+		reversal.FromLocation = original.ToLocation
+		reversal.ToLocation = original.FromLocation
+		deltas = []stockDelta{{*original.ToLocation, -original.Quantity}, {*original.FromLocation, original.Quantity}}
+	case TransactionTypeAdjust:
+		if original.ToLocation == nil {
+			return nil, NewBusinessRuleError("reversal", "調整先のないトランザクションは取り消せません", original.ID)
+		}
+		reversal.ToLocation = original.ToLocation
+		reversal.Quantity = -original.Quantity
+		deltas = []stockDelta{{*original.ToLocation, -original.Quantity}}
+	default:
+		return nil, NewValidationError("type", "取消できないトランザクションタイプです", string(original.Type))
+	}
+
+	// 取消後の在庫を算出
+	now := time.Now()
+	stocks := make([]*Stock, 0, len(deltas))
+	for _, d := range deltas {
+		stock, err := m.storage.GetStock(ctx, original.ItemID, d.locationID)
+		if err != nil && err != ErrStockNotFound {
+			return nil, NewStorageError("get_stock", "在庫取得に失敗しました", err)
+		}
+		if stock == nil {
+			stock = &Stock{
+				ItemID:     original.ItemID,
+				LocationID: d.locationID,
+				Version:    0,
+			}
+		}
+		if d.delta < 0 && !m.config.AllowNegativeStock && stock.Available < -d.delta {
+			return nil, ErrInsufficientStock
+		}
+
+		stock.Quantity += d.delta
+		stock.Version++
+		stock.UpdatedAt = now
+		stock.UpdatedBy = reversal.CreatedBy
+		stock.CalculateAvailable()
+		stocks = append(stocks, stock)
+	}
+
+	transactions := []*Transaction{reversal}
+
+	// 移動の取消は台帳残高の整合のため出庫・入庫レッグも記録（取消トランザクションに紐付け）
+	if original.Type == TransactionTypeTransfer {
+		legs := []*Transaction{
+			{Type: TransactionTypeOutbound, FromLocation: reversal.FromLocation},
+			{Type: TransactionTypeInbound, ToLocation: reversal.ToLocation},
+		}
+		for _, leg := range legs {
+			leg.ID = NewTransactionID()
+			leg.ItemID = reversal.ItemID
+			leg.Quantity = reversal.Quantity
+			leg.UnitCost = reversal.UnitCost
+			leg.Reference = reference
+			leg.LotNumber = reversal.LotNumber
+			leg.ExpiryDate = reversal.ExpiryDate
+			leg.Metadata = map[string]string{TransferMetadataKey: reversal.ID}
+			leg.CreatedAt = reversal.CreatedAt
+			leg.CreatedBy = reversal.CreatedBy
+			transactions = append(transactions, leg)
+		}
+	}
+
+	// 在庫・取消トランザクション・元取引の取消参照は1つのデータベーストランザクションで記録
+	if err := m.storage.ApplyReversal(ctx, original.ID, reversal.ID, stocks, transactions); err != nil {
+		switch err {
+		case ErrTransactionAlreadyReversed, ErrVersionMismatch:
+			return nil, err
+		}
+		return nil, NewStorageError("apply_reversal", "取消の記録に失敗しました", err)
+	}
+
+	original.ReversedBy = &reversal.ID
+
+	m.logger.Info("トランザクション取消完了",
+		zap.String("transaction_id", original.ID),
+		zap.String("reversal_id", reversal.ID),
+		zap.String("type", string(original.Type)),
+		zap.String("reference", reference),
+	)
+
+	return reversal, nil
+}
+
 // GetStock gets current stock for an item at a location
 // 指定ロケーションの商品在庫を取得
 func (m *Manager) GetStock(ctx context.Context, itemID, locationID string) (*Stock, error) {
@@ -422,6 +842,10 @@ func (m *Manager) GetStock(ctx context.Context, itemID, locationID string) (*Sto
 // GetTotalStock gets total stock across all locations for an item
 // 商品の全ロケーション合計在庫を取得
 func (m *Manager) GetTotalStock(ctx context.Context, itemID string) (int64, error) {
+	if itemID == "" {
+		return 0, NewValidationError("item_id", "商品IDは必須です", itemID)
+	}
+
 	// 商品の存在確認
 	if _, err := m.storage.GetItem(ctx, itemID); err != nil {
 		if err == ErrItemNotFound {
@@ -430,6 +854,7 @@ func (m *Manager) GetTotalStock(ctx context.Context, itemID string) (int64, erro
 		return 0, NewStorageError("get_item", "商品取得に失敗しました", err)
 	}
 
+	// 輸送中ロケーションの在庫も全ロケーション合計に含まれる
 	totalStock, err := m.storage.GetTotalStockByItem(ctx, itemID)
 	if err != nil {
 		m.logger.Error("合計在庫数取得に失敗しました", zap.String("item_id", itemID), zap.Error(err))
@@ -450,6 +875,62 @@ func (m *Manager) GetStockByLocation(ctx context.Context, locationID string) ([]
 	return m.storage.ListStockByLocation(ctx, locationID)
 }
 
+// GetStockAsOf reconstructs stock for an item at a location as of the given time
+// 指定時点における商品・ロケーションの在庫を台帳から再構築
+func (m *Manager) GetStockAsOf(ctx context.Context, itemID, locationID string, asOf time.Time) (*StockSnapshot, error) {
+	// 商品とロケーションの存在確認（過去時点の照会のためアーカイブ済みでも可）
+	if _, _, err := m.getItemAndLocation(ctx, itemID, locationID); err != nil {
+		return nil, err
+	}
+
+	quantity, err := m.storage.GetStockAsOf(ctx, itemID, locationID, asOf)
+	if err != nil {
+		return nil, NewStorageError("get_stock_as_of", "時点在庫取得に失敗しました", err)
+	}
+
+	return &StockSnapshot{
+		ItemID:     itemID,
+		LocationID: locationID,
+		Quantity:   quantity,
+		AsOf:       asOf,
+	}, nil
+}
+
+// GetStockByLocationAsOf reconstructs all stock at a location as of the given time
+// 指定時点におけるロケーションの全在庫を台帳から再構築
+func (m *Manager) GetStockByLocationAsOf(ctx context.Context, locationID string, asOf time.Time) ([]StockSnapshot, error) {
+	// ロケーションの存在確認
+	if _, err := m.storage.GetLocation(ctx, locationID); err != nil {
+		if err == ErrLocationNotFound {
+			return nil, ErrLocationNotFound
+		}
+		return nil, NewStorageError("get_location", "ロケーション取得に失敗しました", err)
+	}
+
+	snapshots, err := m.storage.ListStockAsOfByLocation(ctx, locationID, asOf)
+	if err != nil {
+		return nil, NewStorageError("list_stock_as_of_by_location", "ロケーション時点在庫取得に失敗しました", err)
+	}
+
+	return snapshots, nil
+}
+
+// CreateStockSnapshots records ledger-based stock snapshots used by point-in-time queries
+// 時点在庫照会で使用する台帳ベースの在庫スナップショットを記録
+func (m *Manager) CreateStockSnapshots(ctx context.Context, at time.Time) (int64, error) {
+	count, err := m.storage.CreateStockSnapshots(ctx, at)
+	if err != nil {
+		return 0, NewStorageError("create_stock_snapshots", "在庫スナップショット作成に失敗しました", err)
+	}
+
+	m.logger.Info("在庫スナップショット作成完了",
+		zap.Time("snapshot_at", at),
+		zap.Int64("count", count),
+	)
+
+	return count, nil
+}
+
 // GetHistory gets transaction history for an item
 // 商品のトランザクション履歴を取得
 func (m *Manager) GetHistory(ctx context.Context, itemID string, limit int) ([]Transaction, error) {
@@ -551,6 +1032,10 @@ func (m *Manager) ExecuteBatch(ctx context.Context, operations []InventoryOperat
 			}
 		case OperationTypeAdjust:
 			err = m.Adjust(ctx, op.ItemID, op.LocationID, op.Quantity, op.Reference)
+		case OperationTypeAssemble:
+			err = m.Assemble(ctx, op.ItemID, op.LocationID, op.Quantity, op.Reference)
+		case OperationTypeDisassemble:
+			err = m.Disassemble(ctx, op.ItemID, op.LocationID, op.Quantity, op.Reference)
 		default:
 			err = fmt.Errorf("未知の操作タイプ: %s", op.Type)
 		}
@@ -575,6 +1060,14 @@ func (m *Manager) ExecuteBatch(ctx context.Context, operations []InventoryOperat
 		batch.Status = BatchStatusCompleted
 	}
 
+	// 在庫操作は完了しているため、実行結果の保存失敗はログに残して結果を返す
+	if err := m.storage.CreateBatchOperation(ctx, batch); err != nil {
+		m.logger.Error("バッチ操作の実行結果の保存に失敗しました",
+			zap.String("batch_id", batch.ID),
+			zap.Error(err),
+		)
+	}
+
 	return batch, nil
 }
 
@@ -585,17 +1078,12 @@ func (m *Manager) GetBatchStatus(ctx context.Context, batchID string) (*BatchOpe
 		return nil, NewValidationError("batch_id", "バッチIDが指定されていません", "")
 	}
 
-	// TODO: 実際の実装では、バッチ操作の状態をストレージに永続化し、
-	// ここで取得する必要がある。現在は簡易実装として固定値を返す。
-	batch := &BatchOperation{
-		ID:           batchID,
-		Operations:   make([]InventoryOperation, 0),
-		Status:       BatchStatusCompleted,
-		SuccessCount: 0,
-		FailureCount: 0,
-		Errors:       make([]BatchOperationError, 0),
-		CreatedAt:    time.Now().Add(-time.Hour), // 1時間前に作成されたと仮定
-		CompletedAt:  &[]time.Time{time.Now()}[0],
+	batch, err := m.storage.GetBatchOperation(ctx, batchID)
+	if err != nil {
+		if err == ErrBatchNotFound {
+			return nil, ErrBatchNotFound
+		}
+		return nil, NewStorageError("get_batch_operation", "バッチ操作取得に失敗しました", err)
 	}
 
 	m.logger.Info("バッチステータス取得完了",
@@ -607,7 +1095,7 @@ func (m *Manager) GetBatchStatus(ctx context.Context, batchID string) (*BatchOpe
 }
 
 // Reserve reserves inventory
-// 在庫を予約
+// 在庫を予約（数量は商品の保存単位）
 func (m *Manager) Reserve(ctx context.Context, itemID, locationID string, quantity int64, reference string) error {
 	if quantity <= 0 {
 		return NewValidationError("quantity", "数量は正の値である必要があります", fmt.Sprintf("%d", quantity))
@@ -646,7 +1134,7 @@ func (m *Manager) Reserve(ctx context.Context, itemID, locationID string, quanti
 }
 
 // ReleaseReservation releases reserved inventory
-// 予約された在庫を解除
+// 予約された在庫を解除（数量は商品の保存単位）
 func (m *Manager) ReleaseReservation(ctx context.Context, itemID, locationID string, quantity int64, reference string) error {
 	if quantity <= 0 {
 		return NewValidationError("quantity", "数量は正の値である必要があります", fmt.Sprintf("%d", quantity))
@@ -698,48 +1186,200 @@ func (m *Manager) ResolveAlert(ctx context.Context, alertID string) error {
 
 // ヘルパーメソッド
 
-// validateItemAndLocation validates that item and location exist
-// 商品とロケーションの存在を確認
-func (m *Manager) validateItemAndLocation(ctx context.Context, itemID, locationID string) error {
+// validateItemAndLocation validates that item and location exist and are not archived, and returns the item
+// 商品とロケーションが存在しアーカイブされていないことを確認し、商品を返す
+func (m *Manager) validateItemAndLocation(ctx context.Context, itemID, locationID string) (*Item, error) {
+	item, location, err := m.getItemAndLocation(ctx, itemID, locationID)
+	if err != nil {
+		return nil, err
+	}
+
+	// アーカイブ済みのマスタは新しい在庫移動に使用できない
+	if item.ArchivedAt != nil {
+		return nil, ErrItemArchived
+	}
+	if location.ArchivedAt != nil {
+		return nil, ErrLocationArchived
+	}
+
+	return item, nil
+}
+
+// getItemAndLocation gets an item and a location, including archived ones
+// 商品とロケーションを取得（アーカイブ済みを含む）
+func (m *Manager) getItemAndLocation(ctx context.Context, itemID, locationID string) (*Item, *Location, error) {
 	// 商品の存在確認
-	if _, err := m.storage.GetItem(ctx, itemID); err != nil {
+	item, err := m.storage.GetItem(ctx, itemID)
+	if err != nil {
 		if err == ErrItemNotFound {
-			return ErrItemNotFound
+			return nil, nil, ErrItemNotFound
 		}
-		return NewStorageError("get_item", "商品取得に失敗しました", err)
+		return nil, nil, NewStorageError("get_item", "商品取得に失敗しました", err)
 	}
 
 	// ロケーションの存在確認
-	if _, err := m.storage.GetLocation(ctx, locationID); err != nil {
+	location, err := m.storage.GetLocation(ctx, locationID)
+	if err != nil {
 		if err == ErrLocationNotFound {
-			return ErrLocationNotFound
+			return nil, nil, ErrLocationNotFound
 		}
-		return NewStorageError("get_location", "ロケーション取得に失敗しました", err)
+		return nil, nil, NewStorageError("get_location", "ロケーション取得に失敗しました", err)
 	}
 
-	return nil
+	return item, location, nil
 }
 
-// getUserFromContext extracts user ID from context
-// コンテキストからユーザーIDを取得
-func (m *Manager) getUserFromContext(ctx context.Context) string {
-	if userID, ok := ctx.Value("user_id").(string); ok {
-		return userID
+// validateItemQuantity validates that an item exists, is not archived and a quantity is within the range of its precision
+// 商品が存在しアーカイブされていないことと、数量が商品の小数桁数に応じた範囲内であることを確認
+func (m *Manager) validateItemQuantity(ctx context.Context, itemID string, quantity int64) error {
+	item, err := m.storage.GetItem(ctx, itemID)
+	if err != nil {
+		if err == ErrItemNotFound {
+			return ErrItemNotFound
+		}
+		return NewStorageError("get_item", "商品取得に失敗しました", err)
 	}
-	return "system"
+	if item.ArchivedAt != nil {
+		return ErrItemArchived
+	}
+	return ValidateItemQuantity(quantity, item.QuantityPrecision, false)
 }
 
-// triggerLowStockAlert creates a low stock alert
-// 低在庫アラートを作成
-func (m *Manager) triggerLowStockAlert(ctx context.Context, itemID, locationID string, currentQty int64) {
-	alert := &StockAlert{
-		ID:         NewTransactionID(),
-		Type:       AlertTypeLowStock,
+// lowStockThreshold returns the configured low stock threshold in the item's stored quantity units
+// 設定の低在庫閾値（商品の単位）を商品の保存単位に換算して返す
+func (m *Manager) lowStockThreshold(precision int) int64 {
+	return m.config.LowStockThreshold * QuantityScale(precision)
+}
+
+// stockDelta is a signed quantity change at a location
+// ロケーション単位の在庫増減
+type stockDelta struct {
+	locationID string
+	delta      int64
+}
+
+// inTransitLocation returns the virtual location used for stock in transit
+// 輸送中在庫の仮想ロケーションIDを返す
+func (m *Manager) inTransitLocation() string {
+	if m.config.InTransitLocation == "" {
+		return DefaultInTransitLocation
+	}
+	return m.config.InTransitLocation
+}
+
+// ensureInTransitLocation creates the in-transit virtual location if it doesn't exist yet
+// 輸送中の仮想ロケーションが未作成の場合に作成
+func (m *Manager) ensureInTransitLocation(ctx context.Context) error {
+	locationID := m.inTransitLocation()
+	if _, err := m.storage.GetLocation(ctx, locationID); err == nil {
+		return nil
+	} else if err != ErrLocationNotFound {
+		return NewStorageError("get_location", "ロケーション取得に失敗しました", err)
+	}
+
+	now := time.Now()
+	location := &Location{
+		ID:        locationID,
+		Name:      "輸送中",
+		Type:      "virtual",
+		IsActive:  true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := m.storage.CreateLocation(ctx, location); err != nil && err != ErrDuplicateLocation {
+		return NewStorageError("create_location", "輸送中ロケーション作成に失敗しました", err)
+	}
+	return nil
+}
+
+// getUserFromContext extracts user ID from context
+// コンテキストからユーザーIDを取得
+func (m *Manager) getUserFromContext(ctx context.Context) string {
+	if userID, ok := ctx.Value("user_id").(string); ok {
+		return userID
+	}
+	return "system"
+}
+
+// WithPostingDate returns a context whose postings are recorded at the given date instead of the current time
+// 計上日時を現在日時ではなく指定日時とするコンテキストを返す（過去日付での計上）
+func WithPostingDate(ctx context.Context, at time.Time) context.Context {
+	return context.WithValue(ctx, "posting_date", at)
+}
+
+// postingTime validates the posting date of the context and returns the time to record postings at
+// コンテキストの計上日時を検証し、トランザクションに記録する日時を返す
+//
+// 締めは終了日を過ぎた期間のみ可能なため、現在日時での計上は締め済み期間に含まれません。
+// 過去日付で計上する場合のみ会計期間を確認し、締め済み期間であればErrPeriodClosedを返します。
+// また、計上先ロケーションの在庫スナップショット以前の日付は時点在庫に反映されないため、ErrPostingBeforeSnapshotを返します。
+func (m *Manager) postingTime(ctx context.Context, locationIDs ...string) (time.Time, error) {
+	at, ok := ctx.Value("posting_date").(time.Time)
+	if !ok {
+		return time.Now(), nil
+	}
+	if at.After(time.Now()) {
+		return time.Time{}, NewValidationError("posting_date", "未来日付では計上できません", at.Format(time.RFC3339))
+	}
+
+	closed, err := m.storage.IsPeriodClosed(ctx, at)
+	if err != nil {
+		return time.Time{}, NewStorageError("is_period_closed", "会計期間の確認に失敗しました", err)
+	}
+	if closed {
+		return time.Time{}, ErrPeriodClosed
+	}
+
+	for _, locationID := range locationIDs {
+		if err := m.checkSnapshotCutoff(ctx, locationID, at); err != nil {
+			return time.Time{}, err
+		}
+	}
+	return at, nil
+}
+
+// checkSnapshotCutoff rejects a backdated posting at or before the latest stock snapshot of a location
+// ロケーションの最新の在庫スナップショット以前の過去日付計上を拒否
+//
+// 時点在庫はスナップショットにそれ以降の台帳を加算して求めるため、スナップショット以前の計上は反映されません。
+func (m *Manager) checkSnapshotCutoff(ctx context.Context, locationID string, at time.Time) error {
+	latest, err := m.storage.GetLatestStockSnapshotAt(ctx, locationID)
+	if err != nil {
+		return NewStorageError("get_latest_stock_snapshot_at", "在庫スナップショット日時の取得に失敗しました", err)
+	}
+	if latest != nil && !at.After(*latest) {
+		return ErrPostingBeforeSnapshot
+	}
+	return nil
+}
+
+// transactionLocations returns the locations whose stock a transaction changes
+// トランザクションで在庫が増減するロケーションを返す
+func transactionLocations(tx *Transaction) []string {
+	var locations []string
+	if tx.FromLocation != nil {
+		locations = append(locations, *tx.FromLocation)
+	}
+	if tx.ToLocation != nil {
+		locations = append(locations, *tx.ToLocation)
+	}
+	return locations
+}
+
+// triggerLowStockAlert creates a low stock alert
+// 低在庫アラートを作成（数量・閾値は商品の保存単位）
+func (m *Manager) triggerLowStockAlert(ctx context.Context, itemID, locationID string, currentQty int64, precision int) {
+	threshold := m.lowStockThreshold(precision)
+	message := fmt.Sprintf("商品 %s のロケーション %s での在庫が低下しています (現在: %s, 閾値: %s)", itemID, locationID,
+		FormatQuantity(currentQty, precision), FormatQuantity(threshold, precision))
+	alert := &StockAlert{
+		ID:         NewTransactionID(),
+		Type:       AlertTypeLowStock,
 		ItemID:     itemID,
 		LocationID: locationID,
 		CurrentQty: currentQty,
-		Threshold:  m.config.LowStockThreshold,
-		Message:    fmt.Sprintf("商品 %s のロケーション %s での在庫が低下しています (現在: %d, 閾値: %d)", itemID, locationID, currentQty, m.config.LowStockThreshold),
+		Threshold:  threshold,
+		Message:    message,
 		IsActive:   true,
 		CreatedAt:  time.Now(),
 	}
@@ -755,7 +1395,7 @@ func (m *Manager) triggerLowStockAlert(ctx context.Context, itemID, locationID s
 			ItemID:     itemID,
 			LocationID: locationID,
 			CurrentQty: currentQty,
-			Threshold:  m.config.LowStockThreshold,
+			Threshold:  threshold,
 			Timestamp:  time.Now(),
 		}
 		if err := m.publisher.PublishLowStockAlert(ctx, event); err != nil {
@@ -764,92 +1404,3381 @@ func (m *Manager) triggerLowStockAlert(ctx context.Context, itemID, locationID s
 	}
 }
 
-// ===== ItemManager実装 =====
+// ===== TransferOrderManager実装 =====
 
-// CreateItem creates a new item
-// 新しい商品を作成
-func (m *Manager) CreateItem(ctx context.Context, item *Item) error {
-	if err := ValidateItem(item); err != nil {
+// CreateTransferOrder creates a pending two-step transfer order
+// 出荷待ちの2段階移動指示を作成
+func (m *Manager) CreateTransferOrder(ctx context.Context, order *TransferOrder) error {
+	if order.Quantity <= 0 {
+		return NewValidationError("quantity", "数量は正の値である必要があります", fmt.Sprintf("%d", order.Quantity))
+	}
+	if order.FromLocation == order.ToLocation {
+		return NewValidationError("location", "移動元と移動先が同じです", fmt.Sprintf("%s -> %s", order.FromLocation, order.ToLocation))
+	}
+	if order.FromLocation == m.inTransitLocation() || order.ToLocation == m.inTransitLocation() {
+		return NewValidationError("location", "輸送中ロケーションは移動元・移動先に指定できません", m.inTransitLocation())
+	}
+
+	// 商品とロケーションの存在確認
+	if _, err := m.validateItemAndLocation(ctx, order.ItemID, order.FromLocation); err != nil {
 		return err
 	}
-	return m.storage.CreateItem(ctx, item)
+	if _, err := m.validateItemAndLocation(ctx, order.ItemID, order.ToLocation); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if order.ID == "" {
+		order.ID = NewTransactionID()
+	}
+	order.Status = TransferOrderStatusPending
+	order.ReceivedQuantity = 0
+	order.DiscrepancyQuantity = 0
+	order.ShippedAt = nil
+	order.CompletedAt = nil
+	order.CreatedAt = now
+	order.CreatedBy = m.getUserFromContext(ctx)
+	order.UpdatedAt = now
+	order.Version = 1
+
+	if err := m.storage.CreateTransferOrder(ctx, order); err != nil {
+		return NewStorageError("create_transfer_order", "移動指示作成に失敗しました", err)
+	}
+
+	m.logger.Info("移動指示作成完了",
+		zap.String("order_id", order.ID),
+		zap.String("item_id", order.ItemID),
+		zap.String("from_location", order.FromLocation),
+		zap.String("to_location", order.ToLocation),
+		zap.Int64("quantity", order.Quantity),
+	)
+
+	return nil
 }
 
-// GetItem gets an item by ID
-// IDで商品を取得
-func (m *Manager) GetItem(ctx context.Context, itemID string) (*Item, error) {
-	return m.storage.GetItem(ctx, itemID)
+// GetTransferOrder retrieves a transfer order
+// 移動指示を取得
+func (m *Manager) GetTransferOrder(ctx context.Context, orderID string) (*TransferOrder, error) {
+	order, err := m.storage.GetTransferOrder(ctx, orderID)
+	if err != nil {
+		if err == ErrTransferOrderNotFound {
+			return nil, ErrTransferOrderNotFound
+		}
+		return nil, NewStorageError("get_transfer_order", "移動指示取得に失敗しました", err)
+	}
+	return order, nil
 }
 
-// UpdateItem updates an existing item
-// 既存の商品を更新
-func (m *Manager) UpdateItem(ctx context.Context, item *Item) error {
-	if err := ValidateItem(item); err != nil {
-		return err
+// ListTransferOrders lists transfer orders, optionally filtered by status
+// 移動指示一覧を取得（ステータスで絞り込み可能）
+func (m *Manager) ListTransferOrders(ctx context.Context, status TransferOrderStatus, offset, limit int) ([]TransferOrder, error) {
+	switch status {
+	case "", TransferOrderStatusPending, TransferOrderStatusInTransit, TransferOrderStatusPartiallyReceived,
+		TransferOrderStatusReceived, TransferOrderStatusClosed:
+	default:
+		return nil, NewValidationError("status", "無効な移動指示ステータスです", string(status))
 	}
-	return m.storage.UpdateItem(ctx, item)
+
+	orders, err := m.storage.ListTransferOrders(ctx, status, offset, limit)
+	if err != nil {
+		return nil, NewStorageError("list_transfer_orders", "移動指示一覧取得に失敗しました", err)
+	}
+	return orders, nil
 }
 
-// DeleteItem deletes an item
-// 商品を削除
-func (m *Manager) DeleteItem(ctx context.Context, itemID string) error {
-	// TODO: 在庫が存在する場合は削除不可としてエラーを返す
-	return fmt.Errorf("DeleteItem is not implemented")
+// ShipTransferOrder moves the order quantity from the source into the in-transit location
+// 出荷元から輸送中ロケーションへ在庫を移動し、移動指示を輸送中にする
+func (m *Manager) ShipTransferOrder(ctx context.Context, orderID string) (*TransferOrder, error) {
+	order, err := m.GetTransferOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != TransferOrderStatusPending {
+		return nil, ErrInvalidTransferOrderStatus
+	}
+
+	if err := m.ensureInTransitLocation(ctx); err != nil {
+		return nil, err
+	}
+
+	// 在庫を移動する前に輸送中へ更新して移動指示を確保（同時出荷は片方のみ成功）
+	previous := *order
+	now := time.Now()
+	order.Status = TransferOrderStatusInTransit
+	order.ShippedAt = &now
+	order.UpdatedAt = now
+	if err := m.claimTransferOrder(ctx, order); err != nil {
+		return nil, err
+	}
+
+	reference := transferOrderReference(order)
+	if err := m.Transfer(ctx, order.ItemID, order.FromLocation, m.inTransitLocation(), order.Quantity, reference); err != nil {
+		m.releaseTransferOrder(ctx, order, previous)
+		return nil, err
+	}
+
+	m.logger.Info("移動指示出荷完了",
+		zap.String("order_id", order.ID),
+		zap.Int64("quantity", order.Quantity),
+	)
+
+	return order, nil
+}
+
+// ReceiveTransferOrder receives all or part of the in-transit quantity at the destination
+// 輸送中在庫の全部または一部を入荷先で受け入れる
+func (m *Manager) ReceiveTransferOrder(ctx context.Context, orderID string, quantity int64) (*TransferOrder, error) {
+	if quantity <= 0 {
+		return nil, NewValidationError("quantity", "数量は正の値である必要があります", fmt.Sprintf("%d", quantity))
+	}
+
+	order, err := m.GetTransferOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != TransferOrderStatusInTransit && order.Status != TransferOrderStatusPartiallyReceived {
+		return nil, ErrInvalidTransferOrderStatus
+	}
+	if quantity > order.Outstanding() {
+		return nil, NewValidationError("quantity", "入荷数量が輸送中数量を超えています", fmt.Sprintf("%d > %d", quantity, order.Outstanding()))
+	}
+
+	// 在庫を移動する前に入荷済み数量を更新して移動指示を確保（同時入荷は片方のみ成功）
+	previous := *order
+	now := time.Now()
+	order.ReceivedQuantity += quantity
+	order.UpdatedAt = now
+	if order.Outstanding() == 0 {
+		order.Status = TransferOrderStatusReceived
+		order.CompletedAt = &now
+	} else {
+		order.Status = TransferOrderStatusPartiallyReceived
+	}
+	if err := m.claimTransferOrder(ctx, order); err != nil {
+		return nil, err
+	}
+
+	reference := transferOrderReference(order)
+	if err := m.Transfer(ctx, order.ItemID, m.inTransitLocation(), order.ToLocation, quantity, reference); err != nil {
+		m.releaseTransferOrder(ctx, order, previous)
+		return nil, err
+	}
+
+	m.logger.Info("移動指示入荷完了",
+		zap.String("order_id", order.ID),
+		zap.Int64("quantity", quantity),
+		zap.Int64("outstanding", order.Outstanding()),
+		zap.String("status", string(order.Status)),
+	)
+
+	return order, nil
+}
+
+// RecordTransferDiscrepancy writes off the quantity still in transit and closes the order
+// 未入荷のまま輸送中に残る数量を差異として出庫計上し、移動指示を完了する
+func (m *Manager) RecordTransferDiscrepancy(ctx context.Context, orderID, reason string) (*TransferOrder, error) {
+	if reason == "" {
+		return nil, NewValidationError("reason", "差異理由は必須です", reason)
+	}
+
+	order, err := m.GetTransferOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != TransferOrderStatusInTransit && order.Status != TransferOrderStatusPartiallyReceived {
+		return nil, ErrInvalidTransferOrderStatus
+	}
+
+	// 在庫を出庫する前に完了へ更新して移動指示を確保（入荷・差異計上との同時実行は片方のみ成功）
+	previous := *order
+	discrepancy := order.Outstanding()
+	now := time.Now()
+	order.DiscrepancyQuantity = discrepancy
+	order.DiscrepancyReason = reason
+	order.Status = TransferOrderStatusClosed
+	order.CompletedAt = &now
+	order.UpdatedAt = now
+	if err := m.claimTransferOrder(ctx, order); err != nil {
+		return nil, err
+	}
+
+	reference := transferOrderReference(order) + "_DISCREPANCY"
+	if err := m.Remove(ctx, order.ItemID, m.inTransitLocation(), discrepancy, reference); err != nil {
+		m.releaseTransferOrder(ctx, order, previous)
+		return nil, err
+	}
+
+	m.logger.Info("移動指示差異計上完了",
+		zap.String("order_id", order.ID),
+		zap.Int64("discrepancy", discrepancy),
+		zap.String("reason", reason),
+	)
+
+	return order, nil
 }
 
-// ListItems lists items with pagination
-// ページネーション付きで商品一覧を取得
-func (m *Manager) ListItems(ctx context.Context, offset, limit int) ([]Item, error) {
-	// TODO: Storage interfaceにListItemsを追加後に実装
-	return nil, fmt.Errorf("ListItems is not implemented")
+// claimTransferOrder saves the next state of a transfer order unless another request changed it first
+// 移動指示の更新後の状態を保存（読み込み後に他の処理が更新済みの場合はErrInvalidTransferOrderStatus）
+func (m *Manager) claimTransferOrder(ctx context.Context, order *TransferOrder) error {
+	order.Version++
+	if err := m.storage.UpdateTransferOrder(ctx, order); err != nil {
+		if err == ErrInvalidTransferOrderStatus {
+			return ErrInvalidTransferOrderStatus
+		}
+		return NewStorageError("update_transfer_order", "移動指示更新に失敗しました", err)
+	}
+	return nil
 }
 
-// SearchItems searches items by query
-// クエリで商品を検索
-func (m *Manager) SearchItems(ctx context.Context, query string) ([]Item, error) {
-	// TODO: Storage interfaceにSearchItemsを追加後に実装
-	return nil, fmt.Errorf("SearchItems is not implemented")
+// releaseTransferOrder restores the state before a claim whose stock movement failed
+// 在庫移動に失敗した場合に確保前の状態へ戻す
+func (m *Manager) releaseTransferOrder(ctx context.Context, claimed *TransferOrder, previous TransferOrder) {
+	previous.Version = claimed.Version
+	previous.UpdatedAt = time.Now()
+	if err := m.claimTransferOrder(ctx, &previous); err != nil {
+		m.logger.Error("ロールバック失敗", zap.String("order_id", claimed.ID), zap.Error(err))
+	}
 }
 
-// ===== LocationManager実装 =====
+// transferOrderReference returns the ledger reference used for a transfer order's movements
+// 移動指示の在庫移動に使用する参照番号を返す
+func transferOrderReference(order *TransferOrder) string {
+	if order.Reference != "" {
+		return order.Reference
+	}
+	return "TO-" + order.ID
+}
 
-// CreateLocation creates a new location
-// 新しいロケーションを作成
-func (m *Manager) CreateLocation(ctx context.Context, location *Location) error {
-	if err := ValidateLocation(location); err != nil {
+// ===== SupplierManager実装 =====
+
+// CreateSupplier creates a new supplier
+// 新しい仕入先を作成
+func (m *Manager) CreateSupplier(ctx context.Context, supplier *Supplier) error {
+	if err := ValidateSupplier(supplier); err != nil {
 		return err
 	}
-	return m.storage.CreateLocation(ctx, location)
+
+	now := time.Now()
+	if supplier.ID == "" {
+		supplier.ID = NewTransactionID()
+	}
+	supplier.CreatedAt = now
+	supplier.UpdatedAt = now
+
+	if err := m.storage.CreateSupplier(ctx, supplier); err != nil {
+		return NewStorageError("create_supplier", "仕入先作成に失敗しました", err)
+	}
+	return nil
 }
 
-// GetLocation gets a location by ID
-// IDでロケーションを取得
-func (m *Manager) GetLocation(ctx context.Context, locationID string) (*Location, error) {
-	return m.storage.GetLocation(ctx, locationID)
+// GetSupplier gets a supplier by ID
+// IDで仕入先を取得
+func (m *Manager) GetSupplier(ctx context.Context, supplierID string) (*Supplier, error) {
+	return m.storage.GetSupplier(ctx, supplierID)
 }
 
-// UpdateLocation updates an existing location
-// 既存のロケーションを更新
-func (m *Manager) UpdateLocation(ctx context.Context, location *Location) error {
-	if err := ValidateLocation(location); err != nil {
+// UpdateSupplier updates an existing supplier
+// 既存の仕入先を更新
+func (m *Manager) UpdateSupplier(ctx context.Context, supplier *Supplier) error {
+	if err := ValidateSupplier(supplier); err != nil {
 		return err
 	}
-	// TODO: Storage interfaceにUpdateLocationを追加後に実装
-	return fmt.Errorf("UpdateLocation is not implemented")
+	supplier.UpdatedAt = time.Now()
+	return m.storage.UpdateSupplier(ctx, supplier)
 }
 
-// DeleteLocation deletes a location
-// ロケーションを削除
-func (m *Manager) DeleteLocation(ctx context.Context, locationID string) error {
-	// TODO: 在庫が存在する場合は削除不可としてエラーを返す
-	return fmt.Errorf("DeleteLocation is not implemented")
+// ListSuppliers lists suppliers with pagination
+// ページネーション付きで仕入先一覧を取得
+func (m *Manager) ListSuppliers(ctx context.Context, offset, limit int) ([]Supplier, error) {
+	return m.storage.ListSuppliers(ctx, offset, limit)
+}
+
+// ===== PurchaseOrderManager実装 =====
+
+// CreatePurchaseOrder creates an open purchase order with its lines
+// 明細付きの発注を作成（発注済み状態）
+func (m *Manager) CreatePurchaseOrder(ctx context.Context, order *PurchaseOrder) error {
+	if err := ValidatePurchaseOrder(order); err != nil {
+		return err
+	}
+
+	// 仕入先・入荷先ロケーション・商品の存在確認
+	if _, err := m.storage.GetSupplier(ctx, order.SupplierID); err != nil {
+		if err == ErrSupplierNotFound {
+			return ErrSupplierNotFound
+		}
+		return NewStorageError("get_supplier", "仕入先取得に失敗しました", err)
+	}
+	if order.LocationID == "" {
+		locationID, err := m.defaultPurchaseOrderLocation(ctx, order)
+		if err != nil {
+			return err
+		}
+		order.LocationID = locationID
+	}
+	location, err := m.storage.GetLocation(ctx, order.LocationID)
+	if err != nil {
+		if err == ErrLocationNotFound {
+			return ErrLocationNotFound
+		}
+		return NewStorageError("get_location", "ロケーション取得に失敗しました", err)
+	}
+	if location.ArchivedAt != nil {
+		return ErrLocationArchived
+	}
+	for _, line := range order.Lines {
+		if err := m.validateItemQuantity(ctx, line.ItemID, line.Quantity); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	if order.ID == "" {
+		order.ID = NewTransactionID()
+	}
+	if order.OrderDate.IsZero() {
+		order.OrderDate = now
+	}
+	order.Currency = strings.ToUpper(order.Currency)
+	if order.Currency == "" {
+		order.Currency = m.baseCurrency()
+	}
+	order.Status = PurchaseOrderStatusOpen
+	order.CreatedAt = now
+	order.CreatedBy = m.getUserFromContext(ctx)
+	order.UpdatedAt = now
+	order.Version = 1
+
+	for i := range order.Lines {
+		line := &order.Lines[i]
+		line.ID = NewTransactionID()
+		line.PurchaseOrderID = order.ID
+		line.LineNumber = i + 1
+		line.ReceivedQuantity = 0
+		line.CalculateReceiptVariance()
+	}
+
+	if err := m.storage.CreatePurchaseOrder(ctx, order); err != nil {
+		return NewStorageError("create_purchase_order", "発注作成に失敗しました", err)
+	}
+
+	m.logger.Info("発注作成完了",
+		zap.String("order_id", order.ID),
+		zap.String("supplier_id", order.SupplierID),
+		zap.Int("lines", len(order.Lines)),
+	)
+
+	return nil
+}
+
+// defaultPurchaseOrderLocation returns the destination for an order without one: the replenishment source of its items
+// 入荷先ロケーションが指定されていない発注の入荷先を返す
+//
+// 明細の商品の有効な補充ポリシーの補充元ロケーションが1つに決まればそのロケーション、
+// 決まらない場合は設定のデフォルトロケーションを使用します。
+func (m *Manager) defaultPurchaseOrderLocation(ctx context.Context, order *PurchaseOrder) (string, error) {
+	items := make(map[string]bool, len(order.Lines))
+	for _, line := range order.Lines {
+		items[line.ItemID] = true
+	}
+
+	policies, err := m.storage.ListReplenishmentPolicies(ctx, "")
+	if err != nil {
+		return "", NewStorageError("list_replenishment_policies", "補充ポリシー一覧取得に失敗しました", err)
+	}
+	sources := make(map[string]bool)
+	for _, policy := range policies {
+		if policy.IsActive && items[policy.ItemID] {
+			sources[policy.SourceLocationID] = true
+		}
+	}
+	if len(sources) == 1 {
+		for source := range sources {
+			return source, nil
+		}
+	}
+
+	if m.config.DefaultLocation == "" {
+		return "", NewValidationError("location_id", "入荷先ロケーションを決定できません。入荷先ロケーションを指定してください", "")
+	}
+	return m.config.DefaultLocation, nil
+}
+
+// GetPurchaseOrder gets a purchase order with its lines
+// 明細付きで発注を取得
+func (m *Manager) GetPurchaseOrder(ctx context.Context, orderID string) (*PurchaseOrder, error) {
+	order, err := m.storage.GetPurchaseOrder(ctx, orderID)
+	if err != nil {
+		if err == ErrPurchaseOrderNotFound {
+			return nil, ErrPurchaseOrderNotFound
+		}
+		return nil, NewStorageError("get_purchase_order", "発注取得に失敗しました", err)
+	}
+
+	for i := range order.Lines {
+		order.Lines[i].CalculateReceiptVariance()
+	}
+	return order, nil
+}
+
+// ListPurchaseOrders lists purchase orders filtered by status and supplier
+// ステータス・仕入先で絞り込んだ発注一覧を取得
+func (m *Manager) ListPurchaseOrders(ctx context.Context, status PurchaseOrderStatus, supplierID string, offset, limit int) ([]PurchaseOrder, error) {
+	orders, err := m.storage.ListPurchaseOrders(ctx, status, supplierID, offset, limit)
+	if err != nil {
+		return nil, NewStorageError("list_purchase_orders", "発注一覧取得に失敗しました", err)
+	}
+	return orders, nil
 }
 
-// ListLocations lists locations with pagination
-// ページネーション付きでロケーション一覧を取得
-func (m *Manager) ListLocations(ctx context.Context, offset, limit int) ([]Location, error) {
-	// TODO: Storage interfaceにListLocationsを追加後に実装
-	return nil, fmt.Errorf("ListLocations is not implemented")
+// ReceivePurchaseOrder posts inbound stock for receipts against purchase order lines
+// 発注明細に対する入荷を受け付け、単価・ロット付きで入庫を計上
+func (m *Manager) ReceivePurchaseOrder(ctx context.Context, orderID, locationID string, receipts []PurchaseReceipt) (*PurchaseOrder, error) {
+	if len(receipts) == 0 {
+		return nil, NewValidationError("receipts", "入荷明細がありません", "0")
+	}
+
+	order, err := m.GetPurchaseOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !order.Status.IsOpen() {
+		return nil, ErrPurchaseOrderNotOpen
+	}
+	// 入荷先の指定がなければ発注の入荷先ロケーションに入庫する
+	if locationID == "" {
+		locationID = order.LocationID
+	}
+	// 入荷先ロケーション導入前の発注は入荷時に指定が必要
+	if locationID == "" {
+		return nil, NewValidationError("location_id", "入荷先ロケーションを指定してください", "")
+	}
+
+	lines := make(map[string]*PurchaseOrderLine, len(order.Lines))
+	for i := range order.Lines {
+		lines[order.Lines[i].ID] = &order.Lines[i]
+	}
+	for _, receipt := range receipts {
+		if _, ok := lines[receipt.LineID]; !ok {
+			return nil, NewValidationError("line_id", "発注明細が見つかりません", receipt.LineID)
+		}
+		if receipt.Quantity <= 0 {
+			return nil, NewValidationError("quantity", "数量は正の値である必要があります", fmt.Sprintf("%d", receipt.Quantity))
+		}
+	}
+
+	reference := order.Reference
+	if reference == "" {
+		reference = "PO-" + order.ID
+	}
+
+	// 入庫・ロット・発注の更新はまとめて1トランザクションで書き込む
+	changes := newStockChangeSet()
+	var lots []*Lot
+	for _, receipt := range receipts {
+		line := lines[receipt.LineID]
+		unitCost := line.UnitCost
+		details := &inboundDetails{
+			UnitCost:   &unitCost,
+			Currency:   order.Currency,
+			LotNumber:  receipt.LotNumber,
+			ExpiryDate: receipt.ExpiryDate,
+		}
+		tx, err := m.stageInbound(ctx, changes, line.ItemID, locationID, receipt.Quantity, reference, details)
+		if err != nil {
+			return nil, err
+		}
+
+		if receipt.LotNumber != nil && *receipt.LotNumber != "" {
+			lots = append(lots, &Lot{
+				ID:         NewTransactionID(),
+				Number:     *receipt.LotNumber,
+				ItemID:     line.ItemID,
+				Quantity:   receipt.Quantity,
+				UnitCost:   *tx.UnitCost,
+				ExpiryDate: receipt.ExpiryDate,
+				CreatedAt:  time.Now(),
+			})
+		}
+
+		line.ReceivedQuantity += receipt.Quantity
+		line.CalculateReceiptVariance()
+	}
+
+	// 全明細が発注数量に達したら入荷完了
+	order.Status = PurchaseOrderStatusReceived
+	for _, line := range order.Lines {
+		if line.ReceivedQuantity < line.Quantity {
+			order.Status = PurchaseOrderStatusPartiallyReceived
+			break
+		}
+	}
+	order.UpdatedAt = time.Now()
+	order.Version++
+
+	// 読み込み後に他の入荷・打ち切りで発注が更新されていれば何も書き込まない
+	if err := m.storage.ApplyPurchaseReceipt(ctx, order, changes.stocks, changes.transactions, lots); err != nil {
+		if err == ErrVersionMismatch {
+			return nil, ErrVersionMismatch
+		}
+		return nil, NewStorageError("apply_purchase_receipt", "発注入荷の計上に失敗しました", err)
+	}
+	m.publishStockChanges(ctx, changes, "add", reference)
+
+	m.logger.Info("発注入荷完了",
+		zap.String("order_id", order.ID),
+		zap.String("location_id", locationID),
+		zap.Int("receipts", len(receipts)),
+		zap.String("status", string(order.Status)),
+	)
+
+	return order, nil
+}
+
+// ClosePurchaseOrder closes an open purchase order, cancelling any remaining quantity
+// 発注を打ち切り、未入荷数量を発注残から除外
+func (m *Manager) ClosePurchaseOrder(ctx context.Context, orderID string) (*PurchaseOrder, error) {
+	order, err := m.GetPurchaseOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !order.Status.IsOpen() {
+		return nil, ErrPurchaseOrderNotOpen
+	}
+
+	order.Status = PurchaseOrderStatusClosed
+	order.UpdatedAt = time.Now()
+	order.Version++
+
+	if err := m.storage.UpdatePurchaseOrder(ctx, order); err != nil {
+		if err == ErrVersionMismatch {
+			return nil, ErrVersionMismatch
+		}
+		return nil, NewStorageError("update_purchase_order", "発注更新に失敗しました", err)
+	}
+
+	m.logger.Info("発注打ち切り完了", zap.String("order_id", order.ID))
+
+	return order, nil
+}
+
+// GetOnOrderQuantity returns the quantity of an item still on order with suppliers
+// 仕入先に発注済みで未入荷の数量（発注残）を取得
+func (m *Manager) GetOnOrderQuantity(ctx context.Context, itemID string) (int64, error) {
+	if itemID == "" {
+		return 0, NewValidationError("item_id", "商品IDは必須です", itemID)
+	}
+
+	onOrder, err := m.storage.GetOnOrderQuantity(ctx, itemID, "")
+	if err != nil {
+		return 0, NewStorageError("get_on_order_quantity", "発注残数量取得に失敗しました", err)
+	}
+	return onOrder, nil
+}
+
+// ===== OutboundOrderManager実装 =====
+
+// CreateOutboundOrder creates a pending outbound order with its lines
+// 明細付きの出荷指示を作成（引当待ち状態）
+func (m *Manager) CreateOutboundOrder(ctx context.Context, order *OutboundOrder) error {
+	if err := ValidateOutboundOrder(order); err != nil {
+		return err
+	}
+
+	// 商品の存在確認
+	for _, line := range order.Lines {
+		if err := m.validateItemQuantity(ctx, line.ItemID, line.Quantity); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	if order.ID == "" {
+		order.ID = NewTransactionID()
+	}
+	order.Status = OutboundOrderStatusPending
+	order.WaveID = nil
+	order.Allocations = nil
+	order.CreatedAt = now
+	order.CreatedBy = m.getUserFromContext(ctx)
+	order.UpdatedAt = now
+	order.Version = 1
+
+	for i := range order.Lines {
+		line := &order.Lines[i]
+		line.ID = NewTransactionID()
+		line.OrderID = order.ID
+		line.LineNumber = i + 1
+		line.AllocatedQuantity = 0
+		line.PickedQuantity = 0
+		line.BackorderedQuantity = 0
+	}
+
+	if err := m.storage.CreateOutboundOrder(ctx, order); err != nil {
+		return NewStorageError("create_outbound_order", "出荷指示作成に失敗しました", err)
+	}
+
+	m.logger.Info("出荷指示作成完了",
+		zap.String("order_id", order.ID),
+		zap.String("customer", order.Customer),
+		zap.Int("lines", len(order.Lines)),
+	)
+
+	return nil
+}
+
+// GetOutboundOrder gets an outbound order with its lines and allocations
+// 明細・引当付きで出荷指示を取得
+func (m *Manager) GetOutboundOrder(ctx context.Context, orderID string) (*OutboundOrder, error) {
+	order, err := m.storage.GetOutboundOrder(ctx, orderID)
+	if err != nil {
+		if err == ErrOutboundOrderNotFound {
+			return nil, ErrOutboundOrderNotFound
+		}
+		return nil, NewStorageError("get_outbound_order", "出荷指示取得に失敗しました", err)
+	}
+	return order, nil
+}
+
+// ListOutboundOrders lists outbound orders filtered by status
+// ステータスで絞り込んだ出荷指示一覧を取得
+func (m *Manager) ListOutboundOrders(ctx context.Context, status OutboundOrderStatus, offset, limit int) ([]OutboundOrder, error) {
+	orders, err := m.storage.ListOutboundOrders(ctx, status, offset, limit)
+	if err != nil {
+		return nil, NewStorageError("list_outbound_orders", "出荷指示一覧取得に失敗しました", err)
+	}
+	return orders, nil
+}
+
+// AllocateOutboundOrder reserves available stock for each line, location by location
+// 各明細に対してロケーション順に利用可能在庫を予約（引当）
+func (m *Manager) AllocateOutboundOrder(ctx context.Context, orderID string) (*OutboundOrder, error) {
+	order, err := m.GetOutboundOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != OutboundOrderStatusPending {
+		return nil, ErrInvalidOutboundOrderStatus
+	}
+
+	// 在庫を予約する前に引当済みへ更新して出荷指示を確保（同時引当・キャンセルは片方のみ成功）
+	previous := order.clone()
+	order.Status = OutboundOrderStatusAllocated
+	order.UpdatedAt = time.Now()
+	if err := m.claimOutboundOrder(ctx, order); err != nil {
+		return nil, err
+	}
+
+	reference := outboundOrderReference(order)
+
+	// 引当済み分の予約解除と出荷指示の確保解除（失敗時のロールバック用）
+	var allocated []PickAllocation
+	rollback := func() {
+		for _, allocation := range allocated {
+			if err := m.ReleaseReservation(ctx, allocation.ItemID, allocation.LocationID, allocation.Quantity, reference+"_ROLLBACK"); err != nil {
+				m.logger.Error("ロールバック失敗", zap.Error(err))
+			}
+		}
+		m.releaseOutboundOrder(ctx, order, previous)
+	}
+
+	for i := range order.Lines {
+		line := &order.Lines[i]
+		remaining := line.Quantity - line.AllocatedQuantity
+
+		stocks, err := m.storage.ListStockByItem(ctx, line.ItemID)
+		if err != nil {
+			rollback()
+			return nil, NewStorageError("list_stock_by_item", "商品在庫取得に失敗しました", err)
+		}
+
+		for _, stock := range stocks {
+			if remaining == 0 {
+				break
+			}
+			if stock.LocationID == m.inTransitLocation() || stock.Available <= 0 {
+				continue
+			}
+
+			quantity := stock.Available
+			if quantity > remaining {
+				quantity = remaining
+			}
+			if err := m.Reserve(ctx, line.ItemID, stock.LocationID, quantity, reference); err != nil {
+				if err == ErrInsufficientStock {
+					// 取得後に他の操作で在庫が減った場合は次のロケーションへ
+					continue
+				}
+				rollback()
+				return nil, err
+			}
+
+			allocated = append(allocated, PickAllocation{
+				ID:         NewTransactionID(),
+				OrderID:    order.ID,
+				LineID:     line.ID,
+				ItemID:     line.ItemID,
+				LocationID: stock.LocationID,
+				Quantity:   quantity,
+			})
+			line.AllocatedQuantity += quantity
+			remaining -= quantity
+		}
+
+		if remaining > 0 {
+			m.logger.Warn("引当不足",
+				zap.String("order_id", order.ID),
+				zap.String("item_id", line.ItemID),
+				zap.Int64("shortage", remaining),
+			)
+		}
+	}
+
+	order.Allocations = append(order.Allocations, allocated...)
+	order.UpdatedAt = time.Now()
+	if err := m.claimOutboundOrder(ctx, order); err != nil {
+		rollback()
+		return nil, err
+	}
+
+	m.logger.Info("出荷指示引当完了",
+		zap.String("order_id", order.ID),
+		zap.Int("allocations", len(allocated)),
+	)
+
+	return order, nil
+}
+
+// CancelOutboundOrder cancels an order that has not been picked and releases its reservations
+// ピッキング前の出荷指示をキャンセルし、引当（予約）を解除
+func (m *Manager) CancelOutboundOrder(ctx context.Context, orderID string) (*OutboundOrder, error) {
+	order, err := m.GetOutboundOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != OutboundOrderStatusPending && order.Status != OutboundOrderStatusAllocated {
+		return nil, ErrInvalidOutboundOrderStatus
+	}
+	for _, allocation := range order.Allocations {
+		if allocation.Confirmed {
+			return nil, ErrInvalidOutboundOrderStatus
+		}
+	}
+
+	// 予約を解除する前にキャンセルへ更新して出荷指示を確保（同時のピッキング確定・引当は片方のみ成功）
+	previous := order.clone()
+	order.Status = OutboundOrderStatusCancelled
+	order.UpdatedAt = time.Now()
+	if err := m.claimOutboundOrder(ctx, order); err != nil {
+		return nil, err
+	}
+
+	reference := outboundOrderReference(order)
+
+	var released []PickAllocation
+	rollback := func() {
+		for _, allocation := range released {
+			if err := m.Reserve(ctx, allocation.ItemID, allocation.LocationID, allocation.Quantity, reference+"_ROLLBACK"); err != nil {
+				m.logger.Error("ロールバック失敗", zap.Error(err))
+			}
+		}
+		m.releaseOutboundOrder(ctx, order, previous)
+	}
+
+	for _, allocation := range order.Allocations {
+		if err := m.ReleaseReservation(ctx, allocation.ItemID, allocation.LocationID, allocation.Quantity, reference); err != nil {
+			rollback()
+			return nil, err
+		}
+		released = append(released, allocation)
+	}
+
+	m.logger.Info("出荷指示キャンセル完了", zap.String("order_id", order.ID))
+
+	return order, nil
+}
+
+// GeneratePickList builds the pick list of an allocated order sorted by location path
+// 引当済み出荷指示のピッキングリストをロケーションの巡回順で作成
+func (m *Manager) GeneratePickList(ctx context.Context, orderID string) (*PickList, error) {
+	order, err := m.GetOutboundOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != OutboundOrderStatusAllocated {
+		return nil, ErrInvalidOutboundOrderStatus
+	}
+
+	entries, err := m.pickListEntries(ctx, []OutboundOrder{*order})
+	if err != nil {
+		return nil, err
+	}
+
+	return &PickList{
+		OrderID:     order.ID,
+		GeneratedAt: time.Now(),
+		Entries:     entries,
+	}, nil
+}
+
+// ConfirmPicks records actual picked quantities, posting outbound transactions and backorders
+// 実ピッキング数量を確定して出庫を計上し、欠品分をバックオーダーとして記録
+func (m *Manager) ConfirmPicks(ctx context.Context, orderID string, confirmations []PickConfirmation) (*OutboundOrder, error) {
+	if len(confirmations) == 0 {
+		return nil, NewValidationError("confirmations", "ピッキング実績がありません", "0")
+	}
+
+	order, err := m.GetOutboundOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != OutboundOrderStatusAllocated {
+		return nil, ErrInvalidOutboundOrderStatus
+	}
+
+	allocations := make(map[string]*PickAllocation, len(order.Allocations))
+	for i := range order.Allocations {
+		allocations[order.Allocations[i].ID] = &order.Allocations[i]
+	}
+	seen := make(map[string]bool, len(confirmations))
+	for _, confirmation := range confirmations {
+		allocation, ok := allocations[confirmation.AllocationID]
+		if !ok {
+			return nil, NewValidationError("allocation_id", "引当が見つかりません", confirmation.AllocationID)
+		}
+		if allocation.Confirmed || seen[allocation.ID] {
+			return nil, NewValidationError("allocation_id", "ピッキング確定済みの引当です", confirmation.AllocationID)
+		}
+		if confirmation.PickedQuantity < 0 || confirmation.PickedQuantity > allocation.Quantity {
+			return nil, NewValidationError("picked_quantity", "ピッキング数量は0以上かつ引当数量以下である必要があります", fmt.Sprintf("%d", confirmation.PickedQuantity))
+		}
+		seen[allocation.ID] = true
+	}
+
+	// 出庫する前に確定後の状態へ更新して出荷指示を確保（同時確定・キャンセルは片方のみ成功）
+	previous := order.clone()
+	for _, confirmation := range confirmations {
+		allocation := allocations[confirmation.AllocationID]
+		allocation.PickedQuantity = confirmation.PickedQuantity
+		allocation.Confirmed = true
+	}
+
+	// 明細ごとの出荷数量を集計
+	picked := make(map[string]int64, len(order.Lines))
+	allConfirmed := true
+	for _, allocation := range order.Allocations {
+		picked[allocation.LineID] += allocation.PickedQuantity
+		if !allocation.Confirmed {
+			allConfirmed = false
+		}
+	}
+	for i := range order.Lines {
+		order.Lines[i].PickedQuantity = picked[order.Lines[i].ID]
+	}
+
+	// 全引当の確定後、未出荷分をバックオーダーとして確定
+	if allConfirmed {
+		order.Status = OutboundOrderStatusShipped
+		for i := range order.Lines {
+			line := &order.Lines[i]
+			line.BackorderedQuantity = line.Quantity - line.PickedQuantity
+			if line.BackorderedQuantity > 0 {
+				order.Status = OutboundOrderStatusBackordered
+			}
+		}
+	}
+	order.UpdatedAt = time.Now()
+	if err := m.claimOutboundOrder(ctx, order); err != nil {
+		return nil, err
+	}
+
+	reference := outboundOrderReference(order)
+
+	// 確定済み分の出庫と予約解除を戻し、出荷指示の確保を解除する（失敗時のロールバック用）
+	var posted []PickConfirmation
+	rollback := func() {
+		for _, confirmation := range posted {
+			allocation := allocations[confirmation.AllocationID]
+			if confirmation.PickedQuantity > 0 {
+				if err := m.Add(ctx, allocation.ItemID, allocation.LocationID, confirmation.PickedQuantity, reference+"_ROLLBACK"); err != nil {
+					m.logger.Error("ロールバック失敗", zap.Error(err))
+				}
+			}
+			if err := m.Reserve(ctx, allocation.ItemID, allocation.LocationID, allocation.Quantity, reference+"_ROLLBACK"); err != nil {
+				m.logger.Error("ロールバック失敗", zap.Error(err))
+			}
+		}
+		m.releaseOutboundOrder(ctx, order, previous)
+	}
+
+	for _, confirmation := range confirmations {
+		allocation := allocations[confirmation.AllocationID]
+
+		// 予約を解除してから実数量を出庫
+		if err := m.ReleaseReservation(ctx, allocation.ItemID, allocation.LocationID, allocation.Quantity, reference); err != nil {
+			rollback()
+			return nil, err
+		}
+		if confirmation.PickedQuantity > 0 {
+			if err := m.Remove(ctx, allocation.ItemID, allocation.LocationID, confirmation.PickedQuantity, reference); err != nil {
+				if rollbackErr := m.Reserve(ctx, allocation.ItemID, allocation.LocationID, allocation.Quantity, reference+"_ROLLBACK"); rollbackErr != nil {
+					m.logger.Error("ロールバック失敗", zap.Error(rollbackErr))
+				}
+				rollback()
+				return nil, err
+			}
+		}
+		posted = append(posted, confirmation)
+	}
+
+	m.logger.Info("ピッキング確定完了",
+		zap.String("order_id", order.ID),
+		zap.Int("confirmations", len(confirmations)),
+		zap.String("status", string(order.Status)),
+	)
+
+	return order, nil
+}
+
+// claimOutboundOrder saves the next state of an outbound order unless another request changed it first
+// 出荷指示の更新後の状態を保存（読み込み後に他の処理が更新済みの場合はErrInvalidOutboundOrderStatus）
+func (m *Manager) claimOutboundOrder(ctx context.Context, order *OutboundOrder) error {
+	order.Version++
+	if err := m.storage.UpdateOutboundOrder(ctx, order); err != nil {
+		if err == ErrInvalidOutboundOrderStatus {
+			return ErrInvalidOutboundOrderStatus
+		}
+		return NewStorageError("update_outbound_order", "出荷指示更新に失敗しました", err)
+	}
+	return nil
+}
+
+// releaseOutboundOrder restores the state before a claim whose stock movement failed
+// 在庫の予約・出庫に失敗した場合に確保前の状態へ戻す
+func (m *Manager) releaseOutboundOrder(ctx context.Context, claimed *OutboundOrder, previous *OutboundOrder) {
+	previous.Version = claimed.Version
+	previous.UpdatedAt = time.Now()
+	if err := m.claimOutboundOrder(ctx, previous); err != nil {
+		m.logger.Error("ロールバック失敗", zap.String("order_id", claimed.ID), zap.Error(err))
+	}
+}
+
+// CreateWave groups allocated outbound orders into a wave for combined picking
+// 引当済みの出荷指示をまとめてピッキングするウェーブを作成
+func (m *Manager) CreateWave(ctx context.Context, wave *Wave) error {
+	if wave == nil || len(wave.OrderIDs) == 0 {
+		return NewValidationError("order_ids", "出荷指示が指定されていません", "0")
+	}
+	if err := ValidateReference(wave.Reference); err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool, len(wave.OrderIDs))
+	for _, orderID := range wave.OrderIDs {
+		if seen[orderID] {
+			return NewValidationError("order_ids", "出荷指示が重複しています", orderID)
+		}
+		seen[orderID] = true
+
+		order, err := m.GetOutboundOrder(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		if order.Status != OutboundOrderStatusAllocated || order.WaveID != nil {
+			return ErrInvalidOutboundOrderStatus
+		}
+	}
+
+	wave.ID = NewTransactionID()
+	wave.CreatedAt = time.Now()
+	wave.CreatedBy = m.getUserFromContext(ctx)
+
+	if err := m.storage.CreateWave(ctx, wave); err != nil {
+		if err == ErrInvalidOutboundOrderStatus {
+			return ErrInvalidOutboundOrderStatus
+		}
+		return NewStorageError("create_wave", "ウェーブ作成に失敗しました", err)
+	}
+
+	m.logger.Info("ウェーブ作成完了",
+		zap.String("wave_id", wave.ID),
+		zap.Int("orders", len(wave.OrderIDs)),
+	)
+
+	return nil
+}
+
+// GetWave gets a wave with the IDs of its outbound orders
+// 出荷指示ID付きでウェーブを取得
+func (m *Manager) GetWave(ctx context.Context, waveID string) (*Wave, error) {
+	wave, err := m.storage.GetWave(ctx, waveID)
+	if err != nil {
+		if err == ErrWaveNotFound {
+			return nil, ErrWaveNotFound
+		}
+		return nil, NewStorageError("get_wave", "ウェーブ取得に失敗しました", err)
+	}
+	return wave, nil
+}
+
+// GenerateWavePickList builds one pick list covering the unpicked orders of a wave
+// ウェーブ内の未ピッキング出荷指示をまとめたピッキングリストを作成
+func (m *Manager) GenerateWavePickList(ctx context.Context, waveID string) (*PickList, error) {
+	wave, err := m.GetWave(ctx, waveID)
+	if err != nil {
+		return nil, err
+	}
+
+	orders := make([]OutboundOrder, 0, len(wave.OrderIDs))
+	for _, orderID := range wave.OrderIDs {
+		order, err := m.GetOutboundOrder(ctx, orderID)
+		if err != nil {
+			return nil, err
+		}
+		if order.Status == OutboundOrderStatusAllocated {
+			orders = append(orders, *order)
+		}
+	}
+
+	entries, err := m.pickListEntries(ctx, orders)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PickList{
+		WaveID:      wave.ID,
+		GeneratedAt: time.Now(),
+		Entries:     entries,
+	}, nil
+}
+
+// pickListEntries lists the unconfirmed allocations of the orders in pick path order
+// 出荷指示の未確定引当をピッキング指示として巡回順に列挙
+func (m *Manager) pickListEntries(ctx context.Context, orders []OutboundOrder) ([]PickListEntry, error) {
+	paths := make(map[string]string)
+	entries := make([]PickListEntry, 0)
+
+	for _, order := range orders {
+		for _, allocation := range order.Allocations {
+			if allocation.Confirmed {
+				continue
+			}
+
+			path, ok := paths[allocation.LocationID]
+			if !ok {
+				location, err := m.storage.GetLocation(ctx, allocation.LocationID)
+				if err != nil {
+					return nil, NewStorageError("get_location", "ロケーション取得に失敗しました", err)
+				}
+				// パス未設定のロケーションはIDで代用
+				path = location.PickPath
+				if path == "" {
+					path = location.ID
+				}
+				paths[allocation.LocationID] = path
+			}
+
+			entries = append(entries, PickListEntry{
+				AllocationID: allocation.ID,
+				OrderID:      order.ID,
+				LineID:       allocation.LineID,
+				ItemID:       allocation.ItemID,
+				LocationID:   allocation.LocationID,
+				PickPath:     path,
+				Quantity:     allocation.Quantity,
+			})
+		}
+	}
+
+	SortPickListEntries(entries)
+	return entries, nil
+}
+
+// outboundOrderReference returns the ledger reference used for an outbound order's movements
+// 出荷指示の在庫移動に使用する参照番号を返す
+func outboundOrderReference(order *OutboundOrder) string {
+	if order.Reference != "" {
+		return order.Reference
+	}
+	return "SO-" + order.ID
+}
+
+// ===== KitManager実装 =====
+
+// SetBillOfMaterials registers or replaces the bill of materials of a kit item
+// キット商品の部品表を登録（既存の場合は置き換え）
+func (m *Manager) SetBillOfMaterials(ctx context.Context, bom *BillOfMaterials) error {
+	if err := ValidateBillOfMaterials(bom); err != nil {
+		return err
+	}
+
+	// キットと構成部品の存在確認
+	itemIDs := []string{bom.ItemID}
+	for _, component := range bom.Components {
+		itemIDs = append(itemIDs, component.ItemID)
+	}
+	for _, itemID := range itemIDs {
+		item, err := m.storage.GetItem(ctx, itemID)
+		if err != nil {
+			if err == ErrItemNotFound {
+				return ErrItemNotFound
+			}
+			return NewStorageError("get_item", "商品取得に失敗しました", err)
+		}
+		if item.ArchivedAt != nil {
+			return ErrItemArchived
+		}
+	}
+
+	bom.UpdatedAt = time.Now()
+	if err := m.storage.SetBillOfMaterials(ctx, bom); err != nil {
+		return NewStorageError("set_bill_of_materials", "部品表登録に失敗しました", err)
+	}
+
+	m.logger.Info("部品表登録完了",
+		zap.String("item_id", bom.ItemID),
+		zap.Int("components", len(bom.Components)),
+	)
+
+	return nil
+}
+
+// GetBillOfMaterials gets the bill of materials of a kit item
+// キット商品の部品表を取得
+func (m *Manager) GetBillOfMaterials(ctx context.Context, itemID string) (*BillOfMaterials, error) {
+	bom, err := m.storage.GetBillOfMaterials(ctx, itemID)
+	if err != nil {
+		if err == ErrBOMNotFound {
+			return nil, ErrBOMNotFound
+		}
+		return nil, NewStorageError("get_bill_of_materials", "部品表取得に失敗しました", err)
+	}
+	return bom, nil
+}
+
+// Assemble consumes the BOM components and produces kits at one location in a single storage transaction
+// 部品表の構成部品を消費してキットを組み立てる（同一ロケーション、1トランザクションで計上）
+func (m *Manager) Assemble(ctx context.Context, kitItemID, locationID string, quantity int64, reference string) error {
+	return m.applyKitOperation(ctx, kitItemID, locationID, quantity, reference, OperationTypeAssemble)
+}
+
+// Disassemble consumes kits and returns their BOM components to stock at one location
+// キットを分解して構成部品を在庫に戻す（同一ロケーション、1トランザクションで計上）
+func (m *Manager) Disassemble(ctx context.Context, kitItemID, locationID string, quantity int64, reference string) error {
+	return m.applyKitOperation(ctx, kitItemID, locationID, quantity, reference, OperationTypeDisassemble)
+}
+
+// CalculateKitCost rolls up the unit cost of a kit from the valuation of its components
+// 構成部品の評価額からキット1個あたりの原価を積み上げ計算
+func (m *Manager) CalculateKitCost(ctx context.Context, kitItemID string) (Money, error) {
+	bom, err := m.GetBillOfMaterials(ctx, kitItemID)
+	if err != nil {
+		return 0, err
+	}
+
+	kitCost, _, err := m.rollUpKitCost(ctx, bom)
+	return kitCost, err
+}
+
+// SetValuationEngine sets the valuation engine used to cost kit components
+// キット部品の原価計算に使用する在庫評価エンジンを設定
+func (m *Manager) SetValuationEngine(valuation ValuationEngine) {
+	m.valuation = valuation
+}
+
+// valuationEngine returns the configured valuation engine, or one over the manager's storage
+// 設定済みの在庫評価エンジン（未設定の場合は同じストレージを使うエンジン）を返す
+func (m *Manager) valuationEngine() ValuationEngine {
+	if m.valuation == nil {
+		m.valuation = NewValuationEngine(m.storage, m.logger)
+	}
+	return m.valuation
+}
+
+// kitComponent is the unit cost and quantity precision of a kit component
+// キット構成部品の単価と数量の小数桁数
+type kitComponent struct {
+	unitCost  Money
+	precision int
+}
+
+// rollUpKitCost returns the kit unit cost and the unit cost and precision of each component
+// キット1個あたりの原価と構成部品ごとの単価・小数桁数を返す
+//
+// 構成数量は部品の保存単位のため、部品の小数桁数で単位あたりの原価に換算します。
+func (m *Manager) rollUpKitCost(ctx context.Context, bom *BillOfMaterials) (Money, map[string]kitComponent, error) {
+	components := make(map[string]kitComponent, len(bom.Components))
+	var kitCost Money
+
+	for _, component := range bom.Components {
+		item, err := m.storage.GetItem(ctx, component.ItemID)
+		if err != nil {
+			return 0, nil, NewStorageError("get_item", "商品取得に失敗しました", err)
+		}
+
+		unitCost, err := m.valuationEngine().GetAverageCost(ctx, component.ItemID)
+		if err != nil || unitCost <= 0 {
+			// 入庫原価の実績がない部品は商品マスタの単価で代用
+			unitCost = item.UnitCost
+		}
+
+		components[component.ItemID] = kitComponent{unitCost: unitCost, precision: item.QuantityPrecision}
+		kitCost += unitCost.MulQuantity(component.Quantity, item.QuantityPrecision)
+	}
+
+	return kitCost, components, nil
+}
+
+// kitMovement is the signed quantity change of one item in an assembly or disassembly
+// 組立・分解における商品ごとの在庫増減
+type kitMovement struct {
+	itemID    string
+	delta     int64
+	unitCost  Money
+	precision int
+}
+
+// applyKitOperation posts the stock movements of an assembly or disassembly atomically
+// 組立・分解の在庫増減をまとめて1トランザクションで計上
+func (m *Manager) applyKitOperation(ctx context.Context, kitItemID, locationID string, quantity int64, reference string, operation OperationType) error {
+	if quantity <= 0 {
+		return NewValidationError("quantity", "数量は正の値である必要があります", fmt.Sprintf("%d", quantity))
+	}
+
+	kitItem, err := m.validateItemAndLocation(ctx, kitItemID, locationID)
+	if err != nil {
+		return err
+	}
+	if err := ValidateItemQuantity(quantity, kitItem.QuantityPrecision, false); err != nil {
+		return err
+	}
+
+	bom, err := m.GetBillOfMaterials(ctx, kitItemID)
+	if err != nil {
+		return err
+	}
+
+	kitCost, components, err := m.rollUpKitCost(ctx, bom)
+	if err != nil {
+		return err
+	}
+
+	// 組立はキット入庫・部品出庫、分解はその逆
+	// 構成数量はキット単位1あたりのため、キットの数量を単位に換算して掛ける
+	sign := int64(1)
+	if operation == OperationTypeDisassemble {
+		sign = -1
+	}
+	kitScale := kitItem.QuantityScale()
+	deltas := []kitMovement{{itemID: kitItemID, delta: sign * quantity, unitCost: kitCost, precision: kitItem.QuantityPrecision}}
+	for _, component := range bom.Components {
+		units := component.Quantity * quantity
+		if units%kitScale != 0 {
+			return NewValidationError("quantity", "部品の数量が部品の小数桁数で表せません",
+				fmt.Sprintf("%s: %s", component.ItemID, FormatQuantity(quantity, kitItem.QuantityPrecision)))
+		}
+		deltas = append(deltas, kitMovement{
+			itemID:    component.ItemID,
+			delta:     -sign * units / kitScale,
+			unitCost:  components[component.ItemID].unitCost,
+			precision: components[component.ItemID].precision,
+		})
+	}
+
+	// 計上日時と会計期間の確認
+	postedAt, err := m.postingTime(ctx, locationID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	userID := m.getUserFromContext(ctx)
+	stocks := make([]*Stock, 0, len(deltas))
+	transactions := make([]*Transaction, 0, len(deltas))
+	oldQuantities := make(map[string]int64, len(deltas))
+	precisions := make(map[string]int, len(deltas))
+
+	for _, d := range deltas {
+		precisions[d.itemID] = d.precision
+		stock, err := m.storage.GetStock(ctx, d.itemID, locationID)
+		if err != nil && err != ErrStockNotFound {
+			return NewStorageError("get_stock", "在庫取得に失敗しました", err)
+		}
+
+		if d.delta < 0 && (stock == nil || stock.Available < -d.delta) {
+			return ErrInsufficientStock
+		}
+
+		if stock == nil {
+			stock = &Stock{
+				ItemID:     d.itemID,
+				LocationID: locationID,
+				Version:    0,
+			}
+		}
+		oldQuantities[d.itemID] = stock.Quantity
+		stock.Quantity += d.delta
+		stock.Version++
+		stock.UpdatedAt = now
+		stock.UpdatedBy = userID
+		stock.CalculateAvailable()
+		stocks = append(stocks, stock)
+
+		tx := &Transaction{
+			ID:        NewTransactionID(),
+			ItemID:    d.itemID,
+			Reference: reference,
+			CreatedAt: postedAt,
+			CreatedBy: userID,
+		}
+		if d.delta > 0 {
+			unitCost := d.unitCost
+			tx.Type = TransactionTypeInbound
+			tx.ToLocation = &locationID
+			tx.Quantity = d.delta
+			tx.UnitCost = &unitCost
+		} else {
+			tx.Type = TransactionTypeOutbound
+			tx.FromLocation = &locationID
+			tx.Quantity = -d.delta
+		}
+		transactions = append(transactions, tx)
+	}
+
+	if err := m.storage.ApplyStockChanges(ctx, stocks, transactions); err != nil {
+		if err == ErrVersionMismatch {
+			return ErrVersionMismatch
+		}
+		return NewStorageError("apply_stock_changes", "キット組立・分解の在庫更新に失敗しました", err)
+	}
+
+	for _, stock := range stocks {
+		// イベント発行
+		if m.publisher != nil {
+			event := StockChangedEvent{
+				ItemID:        stock.ItemID,
+				LocationID:    locationID,
+				OldQuantity:   oldQuantities[stock.ItemID],
+				NewQuantity:   stock.Quantity,
+				ChangeType:    string(operation),
+				Reference:     reference,
+				TransactionID: NewTransactionID(),
+				Timestamp:     now,
+				UserID:        userID,
+			}
+			if err := m.publisher.PublishStockChanged(ctx, event); err != nil {
+				m.logger.Error("イベント発行に失敗しました", zap.Error(err))
+			}
+		}
+
+		// 低在庫アラートチェック（消費側のみ）
+		precision := precisions[stock.ItemID]
+		if stock.Quantity < oldQuantities[stock.ItemID] && stock.Quantity <= m.lowStockThreshold(precision) {
+			m.triggerLowStockAlert(ctx, stock.ItemID, locationID, stock.Quantity, precision)
+		}
+	}
+
+	m.logger.Info("キット組立・分解完了",
+		zap.String("operation", string(operation)),
+		zap.String("item_id", kitItemID),
+		zap.String("location_id", locationID),
+		zap.Int64("quantity", quantity),
+		zap.Stringer("kit_cost", kitCost),
+		zap.String("reference", reference),
+	)
+
+	return nil
+}
+
+// ===== CycleCounter実装 =====
+
+// SetAnalyticsEngine sets the analytics engine used to classify items for cycle counting
+// 循環棚卸のABC区分に使用する在庫分析エンジンを設定
+func (m *Manager) SetAnalyticsEngine(analytics AnalyticsEngine) {
+	m.analytics = analytics
+}
+
+// analyticsEngine returns the configured analytics engine, or one over the manager's storage
+// 設定済みの在庫分析エンジン（未設定の場合は同じストレージを使うエンジン）を返す
+func (m *Manager) analyticsEngine() AnalyticsEngine {
+	if m.analytics == nil {
+		m.analytics = NewAnalyticsEngine(m.storage, m.logger)
+	}
+	return m.analytics
+}
+
+// cycleCountPolicy returns the count frequency and tolerance of an ABC class
+// ABC区分の棚卸頻度と許容差異率を返す（設定にない区分はデフォルトを使用）
+func (m *Manager) cycleCountPolicy(class string) (CycleCountClassPolicy, bool) {
+	if policy, ok := m.config.CycleCountPolicy[class]; ok {
+		return policy, true
+	}
+	policy, ok := DefaultCycleCountPolicy()[class]
+	return policy, ok
+}
+
+// cycleCountCandidate is an item due for counting and the date it was last scheduled
+// 棚卸対象の商品と前回の棚卸予定日（未棚卸の場合はゼロ値）
+type cycleCountCandidate struct {
+	itemID        string
+	lastScheduled time.Time
+}
+
+// GenerateCycleCountTasks creates the day's count tasks for a location from its ABC classification
+// ロケーションのABC区分に基づき当日分の棚卸タスクを作成
+//
+// 区分ごとに「区分内の商品数 ÷ 棚卸間隔」件（切り上げ）を1日の件数とし、
+// 未棚卸・前回予定日の古い商品から順に割り当てます。未完了のタスクがある商品は対象外です。
+func (m *Manager) GenerateCycleCountTasks(ctx context.Context, locationID string, date time.Time) ([]CycleCountTask, error) {
+	location, err := m.storage.GetLocation(ctx, locationID)
+	if err != nil {
+		if err == ErrLocationNotFound {
+			return nil, ErrLocationNotFound
+		}
+		return nil, NewStorageError("get_location", "ロケーション取得に失敗しました", err)
+	}
+	if location.ArchivedAt != nil {
+		return nil, ErrLocationArchived
+	}
+
+	classification, err := m.analyticsEngine().CalculateABCClassification(ctx, locationID)
+	if err != nil {
+		return nil, err
+	}
+
+	latest, err := m.storage.ListLatestCycleCountTasks(ctx, locationID)
+	if err != nil {
+		return nil, NewStorageError("list_latest_cycle_count_tasks", "棚卸タスク取得に失敗しました", err)
+	}
+	lastTasks := make(map[string]CycleCountTask, len(latest))
+	for _, task := range latest {
+		lastTasks[task.ItemID] = task
+	}
+
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+
+	classSizes := make(map[string]int)
+	due := make(map[string][]cycleCountCandidate)
+	for itemID, class := range classification {
+		policy, ok := m.cycleCountPolicy(class)
+		if !ok || policy.IntervalDays <= 0 {
+			continue
+		}
+		classSizes[class]++
+
+		candidate := cycleCountCandidate{itemID: itemID}
+		if last, ok := lastTasks[itemID]; ok {
+			switch {
+			case last.Status == CycleCountStatusPending || last.Status == CycleCountStatusDiscrepancy:
+				continue
+			case last.Status == CycleCountStatusRejected:
+				// 却下された棚卸は間隔に関係なく再棚卸の対象
+			case day.Before(last.ScheduledDate.AddDate(0, 0, policy.IntervalDays)):
+				continue
+			}
+			candidate.lastScheduled = last.ScheduledDate
+		}
+		due[class] = append(due[class], candidate)
+	}
+
+	classes := make([]string, 0, len(due))
+	for class := range due {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+
+	now := time.Now()
+	var tasks []CycleCountTask
+	for _, class := range classes {
+		policy, _ := m.cycleCountPolicy(class)
+		candidates := due[class]
+		sort.Slice(candidates, func(i, j int) bool {
+			if !candidates[i].lastScheduled.Equal(candidates[j].lastScheduled) {
+				return candidates[i].lastScheduled.Before(candidates[j].lastScheduled)
+			}
+			return candidates[i].itemID < candidates[j].itemID
+		})
+
+		// 区分内の全商品を棚卸間隔内に一巡させる1日あたりの件数
+		quota := (classSizes[class] + policy.IntervalDays - 1) / policy.IntervalDays
+		if quota > len(candidates) {
+			quota = len(candidates)
+		}
+
+		for _, candidate := range candidates[:quota] {
+			task := CycleCountTask{
+				ID:            NewTransactionID(),
+				LocationID:    locationID,
+				ItemID:        candidate.itemID,
+				ABCClass:      class,
+				Status:        CycleCountStatusPending,
+				ScheduledDate: day,
+				CreatedAt:     now,
+			}
+			if err := m.storage.CreateCycleCountTask(ctx, &task); err != nil {
+				return tasks, NewStorageError("create_cycle_count_task", "棚卸タスク作成に失敗しました", err)
+			}
+			tasks = append(tasks, task)
+		}
+	}
+
+	m.logger.Info("棚卸タスク生成完了",
+		zap.String("location_id", locationID),
+		zap.Time("scheduled_date", day),
+		zap.Int("tasks", len(tasks)),
+	)
+
+	return tasks, nil
+}
+
+// GenerateDailyCycleCounts creates the day's count tasks for every active location
+// 全ての有効なロケーションについて当日分の棚卸タスクを作成し、作成件数を返す
+func (m *Manager) GenerateDailyCycleCounts(ctx context.Context, date time.Time) (int, error) {
+	const pageSize = 100
+
+	created := 0
+	for offset := 0; ; offset += pageSize {
+		locations, err := m.storage.ListLocations(ctx, offset, pageSize, false)
+		if err != nil {
+			return created, NewStorageError("list_locations", "ロケーション一覧取得に失敗しました", err)
+		}
+
+		for _, location := range locations {
+			// 輸送中の仮想ロケーションは実棚できないため対象外
+			if !location.IsActive || location.ID == m.inTransitLocation() {
+				continue
+			}
+			tasks, err := m.GenerateCycleCountTasks(ctx, location.ID, date)
+			created += len(tasks)
+			if err != nil {
+				m.logger.Error("棚卸タスク生成に失敗しました",
+					zap.String("location_id", location.ID),
+					zap.Error(err),
+				)
+			}
+		}
+
+		if len(locations) < pageSize {
+			break
+		}
+	}
+
+	return created, nil
+}
+
+// GetCycleCountTask gets a cycle count task by ID
+// IDで棚卸タスクを取得
+func (m *Manager) GetCycleCountTask(ctx context.Context, taskID string) (*CycleCountTask, error) {
+	task, err := m.storage.GetCycleCountTask(ctx, taskID)
+	if err != nil {
+		if err == ErrCycleCountTaskNotFound {
+			return nil, ErrCycleCountTaskNotFound
+		}
+		return nil, NewStorageError("get_cycle_count_task", "棚卸タスク取得に失敗しました", err)
+	}
+	return task, nil
+}
+
+// ListCycleCountTasks lists cycle count tasks, optionally filtered by location and status
+// 棚卸タスク一覧を取得（ロケーション・ステータスで絞り込み可能）
+func (m *Manager) ListCycleCountTasks(ctx context.Context, locationID string, status CycleCountStatus, offset, limit int) ([]CycleCountTask, error) {
+	tasks, err := m.storage.ListCycleCountTasks(ctx, locationID, status, offset, limit)
+	if err != nil {
+		return nil, NewStorageError("list_cycle_count_tasks", "棚卸タスク一覧取得に失敗しました", err)
+	}
+	return tasks, nil
+}
+
+// RecordCycleCount records the counted quantity of a pending task
+// 棚卸タスクの実棚数量を記録
+//
+// 帳簿数量との差異率が区分の許容差異率以内であれば自動承認して差異を在庫に反映し、
+// 超える場合は在庫を変更せず差異として承認待ちにします。
+func (m *Manager) RecordCycleCount(ctx context.Context, taskID string, countedQuantity int64) (*CycleCountTask, error) {
+	if countedQuantity < 0 {
+		return nil, NewValidationError("counted_quantity", "実棚数量は0以上である必要があります", fmt.Sprintf("%d", countedQuantity))
+	}
+
+	task, err := m.GetCycleCountTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.Status != CycleCountStatusPending {
+		return nil, ErrInvalidCycleCountStatus
+	}
+
+	stock, err := m.storage.GetStock(ctx, task.ItemID, task.LocationID)
+	if err != nil && err != ErrStockNotFound {
+		return nil, NewStorageError("get_stock", "在庫取得に失敗しました", err)
+	}
+	expected := int64(0)
+	if stock != nil {
+		expected = stock.Quantity
+	}
+
+	now := time.Now()
+	task.ExpectedQuantity = expected
+	task.CountedQuantity = &countedQuantity
+	task.Variance = countedQuantity - expected
+	task.VariancePercent = cycleCountVariancePercent(expected, task.Variance)
+	task.CountedBy = m.getUserFromContext(ctx)
+	task.CountedAt = &now
+
+	policy, _ := m.cycleCountPolicy(task.ABCClass)
+	if task.VariancePercent > policy.TolerancePercent {
+		task.Status = CycleCountStatusDiscrepancy
+		if err := m.storage.UpdateCycleCountTask(ctx, task, CycleCountStatusPending); err != nil {
+			if err == ErrInvalidCycleCountStatus {
+				return nil, ErrInvalidCycleCountStatus
+			}
+			return nil, NewStorageError("update_cycle_count_task", "棚卸タスク更新に失敗しました", err)
+		}
+		// アラートの数量は商品の小数桁数で表示（取得できない場合は保存単位のまま）
+		precision := 0
+		if item, err := m.storage.GetItem(ctx, task.ItemID); err == nil {
+			precision = item.QuantityPrecision
+		} else {
+			m.logger.Error("商品取得に失敗しました", zap.String("item_id", task.ItemID), zap.Error(err))
+		}
+		m.triggerDiscrepancyAlert(ctx, task, policy, precision)
+
+		m.logger.Warn("棚卸差異が許容範囲を超えています",
+			zap.String("task_id", task.ID),
+			zap.String("item_id", task.ItemID),
+			zap.String("location_id", task.LocationID),
+			zap.Int64("variance", task.Variance),
+			zap.Float64("variance_percent", task.VariancePercent),
+		)
+		return task, nil
+	}
+
+	task.Status = CycleCountStatusApproved
+	task.ReviewedAt = &now
+	task.ReviewNote = "許容差異内のため自動承認"
+	if err := m.settleCycleCount(ctx, task, CycleCountStatusPending); err != nil {
+		return nil, err
+	}
+
+	m.logger.Info("棚卸結果自動承認",
+		zap.String("task_id", task.ID),
+		zap.String("item_id", task.ItemID),
+		zap.String("location_id", task.LocationID),
+		zap.Int64("variance", task.Variance),
+	)
+
+	return task, nil
+}
+
+// ReviewCycleCount approves or rejects a count whose variance exceeded the tolerance
+// 許容差異を超えた棚卸結果を承認（差異を在庫に反映）または却下（帳簿数量を維持）
+func (m *Manager) ReviewCycleCount(ctx context.Context, taskID string, approve bool, note string) (*CycleCountTask, error) {
+	task, err := m.GetCycleCountTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.Status != CycleCountStatusDiscrepancy {
+		return nil, ErrInvalidCycleCountStatus
+	}
+
+	now := time.Now()
+	task.ReviewedBy = m.getUserFromContext(ctx)
+	task.ReviewedAt = &now
+	task.ReviewNote = note
+
+	if approve {
+		task.Status = CycleCountStatusApproved
+		if err := m.settleCycleCount(ctx, task, CycleCountStatusDiscrepancy); err != nil {
+			return nil, err
+		}
+	} else {
+		task.Status = CycleCountStatusRejected
+		if err := m.storage.UpdateCycleCountTask(ctx, task, CycleCountStatusDiscrepancy); err != nil {
+			if err == ErrInvalidCycleCountStatus {
+				return nil, ErrInvalidCycleCountStatus
+			}
+			return nil, NewStorageError("update_cycle_count_task", "棚卸タスク更新に失敗しました", err)
+		}
+	}
+
+	m.resolveDiscrepancyAlerts(ctx, task)
+
+	m.logger.Info("棚卸差異承認処理完了",
+		zap.String("task_id", task.ID),
+		zap.String("status", string(task.Status)),
+		zap.String("reviewed_by", task.ReviewedBy),
+	)
+
+	return task, nil
+}
+
+// settleCycleCount posts the counted variance as an adjustment and saves the approved task in one database transaction
+// 棚卸差異を在庫調整として反映し、承認済みのタスクを1つのデータベーストランザクションで保存
+//
+// タスクが fromStatus のままの場合のみ反映し、同時に承認・却下された場合はErrInvalidCycleCountStatusを返します。
+func (m *Manager) settleCycleCount(ctx context.Context, task *CycleCountTask, fromStatus CycleCountStatus) error {
+	reference := cycleCountReference(task)
+
+	changes := newStockChangeSet()
+	if task.Variance != 0 {
+		// 計上日時と会計期間の確認
+		postedAt, err := m.postingTime(ctx, task.LocationID)
+		if err != nil {
+			return err
+		}
+
+		// 商品とロケーションの存在確認
+		item, err := m.validateItemAndLocation(ctx, task.ItemID, task.LocationID)
+		if err != nil {
+			return err
+		}
+
+		// 実棚記録後の入出庫を残すため、実棚数量ではなく差異分だけ調整
+		stock, err := m.stagedStock(ctx, changes, task.ItemID, task.LocationID)
+		if err != nil {
+			return err
+		}
+		newQuantity := stock.Quantity + task.Variance
+		if newQuantity < 0 && !m.config.AllowNegativeStock {
+			return NewValidationError("quantity", "負の在庫は許可されていません", FormatQuantity(newQuantity, item.QuantityPrecision))
+		}
+		stock.Quantity = newQuantity
+		stock.UpdatedAt = time.Now()
+		stock.UpdatedBy = m.getUserFromContext(ctx)
+		stock.CalculateAvailable()
+
+		locationID := task.LocationID
+		changes.transactions = append(changes.transactions, &Transaction{
+			ID:         NewTransactionID(),
+			Type:       TransactionTypeAdjust,
+			ItemID:     task.ItemID,
+			ToLocation: &locationID,
+			Quantity:   task.Variance, // 差分を記録
+			Reference:  reference,
+			CreatedAt:  postedAt,
+			CreatedBy:  stock.UpdatedBy,
+		})
+	}
+
+	if err := m.storage.ApplyCycleCountSettlement(ctx, task, fromStatus, changes.stocks, changes.transactions); err != nil {
+		if err == ErrInvalidCycleCountStatus || err == ErrVersionMismatch {
+			return err
+		}
+		return NewStorageError("apply_cycle_count_settlement", "棚卸結果の反映に失敗しました", err)
+	}
+
+	m.publishStockChanges(ctx, changes, "adjust", reference)
+
+	return nil
+}
+
+// triggerDiscrepancyAlert creates an alert for a count outside the class tolerance
+// 許容差異を超えた棚卸結果のアラートを作成（数量は商品の保存単位）
+func (m *Manager) triggerDiscrepancyAlert(ctx context.Context, task *CycleCountTask, policy CycleCountClassPolicy, precision int) {
+	alert := &StockAlert{
+		ID:         NewTransactionID(),
+		Type:       AlertTypeDiscrepancy,
+		ItemID:     task.ItemID,
+		LocationID: task.LocationID,
+		CurrentQty: *task.CountedQuantity,
+		Threshold:  task.ExpectedQuantity,
+		Message: fmt.Sprintf("商品 %s のロケーション %s で棚卸差異が許容範囲を超えています (帳簿: %s, 実棚: %s, 差異率: %.1f%%, 許容: %.1f%%)",
+			task.ItemID, task.LocationID, FormatQuantity(task.ExpectedQuantity, precision), FormatQuantity(*task.CountedQuantity, precision),
+			task.VariancePercent, policy.TolerancePercent),
+		IsActive:  true,
+		CreatedAt: time.Now(),
+	}
+
+	if err := m.storage.CreateAlert(ctx, alert); err != nil {
+		m.logger.Error("アラート作成に失敗しました", zap.Error(err))
+	}
+}
+
+// resolveDiscrepancyAlerts resolves the active discrepancy alerts of a reviewed task's item
+// 承認処理済みの商品・ロケーションの棚卸差異アラートを解決済みにする
+func (m *Manager) resolveDiscrepancyAlerts(ctx context.Context, task *CycleCountTask) {
+	alerts, err := m.storage.GetActiveAlerts(ctx, task.LocationID)
+	if err != nil {
+		m.logger.Error("アラート取得に失敗しました", zap.Error(err))
+		return
+	}
+
+	for _, alert := range alerts {
+		if alert.Type != AlertTypeDiscrepancy || alert.ItemID != task.ItemID {
+			continue
+		}
+		if err := m.storage.ResolveAlert(ctx, alert.ID); err != nil {
+			m.logger.Error("アラート解決に失敗しました", zap.String("alert_id", alert.ID), zap.Error(err))
+		}
+	}
+}
+
+// cycleCountVariancePercent returns the absolute variance as a percentage of the book quantity
+// 帳簿数量に対する差異の割合（絶対値、%）を返す。帳簿数量0で差異がある場合は100%
+func cycleCountVariancePercent(expected, variance int64) float64 {
+	if variance == 0 {
+		return 0
+	}
+	if expected <= 0 {
+		return 100
+	}
+	return math.Abs(float64(variance)) / float64(expected) * 100
+}
+
+// cycleCountReference returns the reference recorded on a cycle count adjustment
+// 棚卸差異の在庫調整に記録する参照番号を返す
+func cycleCountReference(task *CycleCountTask) string {
+	return "CC-" + task.ID
+}
+
+// ===== ReplenishmentManager実装 =====
+
+// SetReplenishmentPolicy creates or updates the min/max policy of an item at a store
+// 店舗ロケーションにおける商品の補充ポリシー（最小・最大在庫と補充元）を登録・更新
+func (m *Manager) SetReplenishmentPolicy(ctx context.Context, policy *ReplenishmentPolicy) error {
+	if policy.LocationID == "" {
+		return NewValidationError("location_id", "補充先ロケーションIDは必須です", "")
+	}
+	if policy.SourceLocationID == "" {
+		return NewValidationError("source_location_id", "補充元ロケーションIDは必須です", "")
+	}
+	if policy.LocationID == policy.SourceLocationID {
+		return NewValidationError("source_location_id", "補充元と補充先が同じです", policy.SourceLocationID)
+	}
+	if policy.MinQuantity < 0 {
+		return NewValidationError("min_quantity", "最小在庫は0以上である必要があります", fmt.Sprintf("%d", policy.MinQuantity))
+	}
+	if policy.MaxQuantity <= 0 || policy.MaxQuantity < policy.MinQuantity {
+		return NewValidationError("max_quantity", "最大在庫は1以上かつ最小在庫以上である必要があります",
+			fmt.Sprintf("min=%d, max=%d", policy.MinQuantity, policy.MaxQuantity))
+	}
+
+	if _, err := m.validateItemAndLocation(ctx, policy.ItemID, policy.LocationID); err != nil {
+		return err
+	}
+	source, err := m.storage.GetLocation(ctx, policy.SourceLocationID)
+	if err != nil {
+		if err == ErrLocationNotFound {
+			return ErrLocationNotFound
+		}
+		return NewStorageError("get_location", "ロケーション取得に失敗しました", err)
+	}
+	if source.ArchivedAt != nil {
+		return ErrLocationArchived
+	}
+
+	now := time.Now()
+	if policy.CreatedAt.IsZero() {
+		policy.CreatedAt = now
+	}
+	policy.UpdatedAt = now
+
+	if err := m.storage.UpsertReplenishmentPolicy(ctx, policy); err != nil {
+		return NewStorageError("upsert_replenishment_policy", "補充ポリシー登録に失敗しました", err)
+	}
+
+	m.logger.Info("補充ポリシー登録完了",
+		zap.String("location_id", policy.LocationID),
+		zap.String("item_id", policy.ItemID),
+		zap.String("source_location_id", policy.SourceLocationID),
+		zap.Int64("min_quantity", policy.MinQuantity),
+		zap.Int64("max_quantity", policy.MaxQuantity),
+	)
+
+	return nil
+}
+
+// ListReplenishmentPolicies lists replenishment policies, optionally filtered by store location
+// 補充ポリシー一覧を取得（補充先ロケーションで絞り込み可能）
+func (m *Manager) ListReplenishmentPolicies(ctx context.Context, locationID string) ([]ReplenishmentPolicy, error) {
+	policies, err := m.storage.ListReplenishmentPolicies(ctx, locationID)
+	if err != nil {
+		return nil, NewStorageError("list_replenishment_policies", "補充ポリシー一覧取得に失敗しました", err)
+	}
+	return policies, nil
+}
+
+// DeleteReplenishmentPolicy deletes the replenishment policy of an item at a store
+// 店舗ロケーションにおける商品の補充ポリシーを削除
+func (m *Manager) DeleteReplenishmentPolicy(ctx context.Context, locationID, itemID string) error {
+	if err := m.storage.DeleteReplenishmentPolicy(ctx, locationID, itemID); err != nil {
+		if err == ErrReplenishmentPolicyNotFound {
+			return ErrReplenishmentPolicyNotFound
+		}
+		return NewStorageError("delete_replenishment_policy", "補充ポリシー削除に失敗しました", err)
+	}
+	return nil
+}
+
+// GenerateReplenishmentProposals proposes transfers for stores whose stock has fallen below the min level
+// 最小在庫を下回った店舗在庫について、最大在庫までの移動を提案
+//
+// 移動数量は補充元の利用可能数量（予約分を除く）から、未実行の提案で見込んでいる数量を
+// 差し引いた範囲に制限します。同じ店舗・商品に未実行の提案がある場合は新たに提案しません。
+func (m *Manager) GenerateReplenishmentProposals(ctx context.Context, locationID string) ([]ReplenishmentProposal, error) {
+	policies, err := m.storage.ListReplenishmentPolicies(ctx, locationID)
+	if err != nil {
+		return nil, NewStorageError("list_replenishment_policies", "補充ポリシー一覧取得に失敗しました", err)
+	}
+
+	openProposals, err := m.storage.ListOpenReplenishmentProposals(ctx)
+	if err != nil {
+		return nil, NewStorageError("list_open_replenishment_proposals", "未実行の補充提案取得に失敗しました", err)
+	}
+
+	// 補充元ごとに見込み済みの数量と、提案済みの店舗・商品
+	committed := make(map[string]int64)
+	proposed := make(map[string]bool)
+	for _, proposal := range openProposals {
+		committed[proposal.SourceLocationID+"/"+proposal.ItemID] += proposal.Quantity
+		proposed[proposal.LocationID+"/"+proposal.ItemID] = true
+	}
+
+	createdBy := m.getUserFromContext(ctx)
+	proposals := make([]ReplenishmentProposal, 0)
+	for _, policy := range policies {
+		if !policy.IsActive || proposed[policy.LocationID+"/"+policy.ItemID] {
+			continue
+		}
+
+		current, err := m.stockQuantity(ctx, policy.ItemID, policy.LocationID)
+		if err != nil {
+			return nil, err
+		}
+		if current.Quantity >= policy.MinQuantity {
+			continue
+		}
+
+		source, err := m.stockQuantity(ctx, policy.ItemID, policy.SourceLocationID)
+		if err != nil {
+			return nil, err
+		}
+		sourceKey := policy.SourceLocationID + "/" + policy.ItemID
+		available := source.Available - committed[sourceKey]
+		if available <= 0 {
+			m.logger.Warn("補充元の利用可能在庫が不足しているため補充提案をスキップしました",
+				zap.String("location_id", policy.LocationID),
+				zap.String("item_id", policy.ItemID),
+				zap.String("source_location_id", policy.SourceLocationID),
+				zap.Int64("source_available", source.Available),
+			)
+			continue
+		}
+
+		required := policy.MaxQuantity - current.Quantity
+		quantity := required
+		if quantity > available {
+			quantity = available
+		}
+
+		proposal := ReplenishmentProposal{
+			ID:               NewTransactionID(),
+			LocationID:       policy.LocationID,
+			SourceLocationID: policy.SourceLocationID,
+			ItemID:           policy.ItemID,
+			Status:           ReplenishmentProposalStatusPending,
+			CurrentQuantity:  current.Quantity,
+			MinQuantity:      policy.MinQuantity,
+			MaxQuantity:      policy.MaxQuantity,
+			RequiredQuantity: required,
+			SourceAvailable:  source.Available,
+			Quantity:         quantity,
+			CreatedAt:        time.Now(),
+			CreatedBy:        createdBy,
+		}
+		if err := m.storage.CreateReplenishmentProposal(ctx, &proposal); err != nil {
+			return nil, NewStorageError("create_replenishment_proposal", "補充提案作成に失敗しました", err)
+		}
+
+		committed[sourceKey] += quantity
+		proposals = append(proposals, proposal)
+	}
+
+	m.logger.Info("補充提案作成完了",
+		zap.String("location_id", locationID),
+		zap.Int("proposal_count", len(proposals)),
+	)
+
+	return proposals, nil
+}
+
+// stockQuantity returns the stock of an item at a location, treating a missing row as zero
+// ロケーションの商品在庫を取得（在庫行がない場合は0）
+func (m *Manager) stockQuantity(ctx context.Context, itemID, locationID string) (*Stock, error) {
+	stock, err := m.storage.GetStock(ctx, itemID, locationID)
+	if err != nil {
+		if err == ErrStockNotFound {
+			return &Stock{ItemID: itemID, LocationID: locationID}, nil
+		}
+		return nil, NewStorageError("get_stock", "在庫取得に失敗しました", err)
+	}
+	return stock, nil
+}
+
+// GetReplenishmentProposal gets a replenishment proposal by ID
+// IDで補充提案を取得
+func (m *Manager) GetReplenishmentProposal(ctx context.Context, proposalID string) (*ReplenishmentProposal, error) {
+	proposal, err := m.storage.GetReplenishmentProposal(ctx, proposalID)
+	if err != nil {
+		if err == ErrReplenishmentProposalNotFound {
+			return nil, ErrReplenishmentProposalNotFound
+		}
+		return nil, NewStorageError("get_replenishment_proposal", "補充提案取得に失敗しました", err)
+	}
+	return proposal, nil
+}
+
+// ListReplenishmentProposals lists replenishment proposals, optionally filtered by store location and status
+// 補充提案一覧を取得（補充先ロケーション・ステータスで絞り込み可能）
+func (m *Manager) ListReplenishmentProposals(ctx context.Context, locationID string, status ReplenishmentProposalStatus, offset, limit int) ([]ReplenishmentProposal, error) {
+	proposals, err := m.storage.ListReplenishmentProposals(ctx, locationID, status, offset, limit)
+	if err != nil {
+		return nil, NewStorageError("list_replenishment_proposals", "補充提案一覧取得に失敗しました", err)
+	}
+	return proposals, nil
+}
+
+// ReviewReplenishmentProposal approves or rejects a pending proposal, optionally changing the quantity on approval
+// 承認待ちの補充提案を承認または却下（承認時は数量の変更が可能、0の場合は提案数量のまま）
+func (m *Manager) ReviewReplenishmentProposal(ctx context.Context, proposalID string, approve bool, quantity int64) (*ReplenishmentProposal, error) {
+	if quantity < 0 {
+		return nil, NewValidationError("quantity", "数量は0以上である必要があります", fmt.Sprintf("%d", quantity))
+	}
+
+	proposal, err := m.GetReplenishmentProposal(ctx, proposalID)
+	if err != nil {
+		return nil, err
+	}
+	if proposal.Status != ReplenishmentProposalStatusPending {
+		return nil, ErrInvalidReplenishmentStatus
+	}
+
+	now := time.Now()
+	proposal.ReviewedBy = m.getUserFromContext(ctx)
+	proposal.ReviewedAt = &now
+	if approve {
+		proposal.Status = ReplenishmentProposalStatusApproved
+		if quantity > 0 {
+			proposal.Quantity = quantity
+		}
+	} else {
+		proposal.Status = ReplenishmentProposalStatusRejected
+	}
+
+	if err := m.storage.UpdateReplenishmentProposal(ctx, proposal, ReplenishmentProposalStatusPending); err != nil {
+		if err == ErrInvalidReplenishmentStatus {
+			return nil, ErrInvalidReplenishmentStatus
+		}
+		return nil, NewStorageError("update_replenishment_proposal", "補充提案更新に失敗しました", err)
+	}
+
+	m.logger.Info("補充提案承認処理完了",
+		zap.String("proposal_id", proposal.ID),
+		zap.String("status", string(proposal.Status)),
+		zap.Int64("quantity", proposal.Quantity),
+		zap.String("reviewed_by", proposal.ReviewedBy),
+	)
+
+	return proposal, nil
+}
+
+// ExecuteReplenishmentProposals converts approved proposals into transfers in a single batch
+// 承認済みの補充提案を1回のバッチ処理で在庫移動に変換
+//
+// 各提案は移動前に実行中へ更新して確保し、他の実行に先に確保された提案はスキップします。
+// 各提案の実行結果（成功・失敗）はバッチの操作単位で提案に記録します。
+func (m *Manager) ExecuteReplenishmentProposals(ctx context.Context, proposalIDs []string) (*BatchOperation, error) {
+	if len(proposalIDs) == 0 {
+		return nil, NewValidationError("proposal_ids", "補充提案IDを指定してください", "")
+	}
+
+	// 全提案のステータスを確認してから実行
+	seen := make(map[string]bool, len(proposalIDs))
+	proposals := make([]*ReplenishmentProposal, 0, len(proposalIDs))
+	for _, proposalID := range proposalIDs {
+		if seen[proposalID] {
+			return nil, NewValidationError("proposal_ids", "補充提案IDが重複しています", proposalID)
+		}
+		seen[proposalID] = true
+
+		proposal, err := m.GetReplenishmentProposal(ctx, proposalID)
+		if err != nil {
+			return nil, err
+		}
+		if proposal.Status != ReplenishmentProposalStatusApproved {
+			return nil, ErrInvalidReplenishmentStatus
+		}
+		proposals = append(proposals, proposal)
+	}
+
+	// 移動前に実行中へ更新し、同じ提案の二重実行を防止
+	claimed := make([]*ReplenishmentProposal, 0, len(proposals))
+	for _, proposal := range proposals {
+		proposal.Status = ReplenishmentProposalStatusExecuting
+		if err := m.storage.UpdateReplenishmentProposal(ctx, proposal, ReplenishmentProposalStatusApproved); err != nil {
+			if err == ErrInvalidReplenishmentStatus {
+				m.logger.Warn("補充提案は他の処理で更新済みのためスキップします", zap.String("proposal_id", proposal.ID))
+				continue
+			}
+			m.releaseReplenishmentProposals(ctx, claimed)
+			return nil, NewStorageError("update_replenishment_proposal", "補充提案更新に失敗しました", err)
+		}
+		claimed = append(claimed, proposal)
+	}
+	if len(claimed) == 0 {
+		return nil, ErrInvalidReplenishmentStatus
+	}
+
+	operations := make([]InventoryOperation, 0, len(claimed))
+	for _, proposal := range claimed {
+		toLocationID := proposal.LocationID
+		operations = append(operations, InventoryOperation{
+			Type:         OperationTypeTransfer,
+			ItemID:       proposal.ItemID,
+			LocationID:   proposal.SourceLocationID,
+			Quantity:     proposal.Quantity,
+			Reference:    "RP-" + proposal.ID,
+			ToLocationID: &toLocationID,
+		})
+	}
+
+	batch, err := m.ExecuteBatch(ctx, operations)
+	if err != nil {
+		m.releaseReplenishmentProposals(ctx, claimed)
+		return nil, err
+	}
+
+	failures := make(map[int]string, len(batch.Errors))
+	for _, batchErr := range batch.Errors {
+		failures[batchErr.OperationIndex] = batchErr.Error
+	}
+
+	executedAt := time.Now()
+	for i, proposal := range claimed {
+		proposal.BatchID = batch.ID
+		proposal.ExecutedAt = &executedAt
+		if message, failed := failures[i]; failed {
+			proposal.Status = ReplenishmentProposalStatusFailed
+			proposal.Error = message
+		} else {
+			proposal.Status = ReplenishmentProposalStatusExecuted
+		}
+
+		// 在庫移動は完了しているため、提案の更新失敗はログに残して処理を続行
+		if err := m.storage.UpdateReplenishmentProposal(ctx, proposal, ReplenishmentProposalStatusExecuting); err != nil {
+			m.logger.Error("補充提案の実行結果の保存に失敗しました",
+				zap.String("proposal_id", proposal.ID),
+				zap.String("batch_id", batch.ID),
+				zap.Error(err),
+			)
+		}
+	}
+
+	m.logger.Info("補充提案実行完了",
+		zap.String("batch_id", batch.ID),
+		zap.Int("success_count", batch.SuccessCount),
+		zap.Int("failure_count", batch.FailureCount),
+		zap.Int("skipped_count", len(proposals)-len(claimed)),
+	)
+
+	return batch, nil
+}
+
+// releaseReplenishmentProposals returns proposals claimed for execution to approved after the batch could not run
+// バッチを実行できなかった場合に、実行中として確保した補充提案を承認済みに戻す
+func (m *Manager) releaseReplenishmentProposals(ctx context.Context, claimed []*ReplenishmentProposal) {
+	for _, proposal := range claimed {
+		proposal.Status = ReplenishmentProposalStatusApproved
+		if err := m.storage.UpdateReplenishmentProposal(ctx, proposal, ReplenishmentProposalStatusExecuting); err != nil {
+			m.logger.Error("ロールバック失敗", zap.String("proposal_id", proposal.ID), zap.Error(err))
+		}
+	}
+}
+
+// ===== PeriodCloser実装 =====
+
+// CreateFiscalPeriod defines a new open fiscal period that must not overlap existing periods
+// 既存の期間と重複しない未締めの会計期間を作成
+func (m *Manager) CreateFiscalPeriod(ctx context.Context, period *FiscalPeriod) error {
+	if period.Name == "" {
+		return NewValidationError("name", "期間名は必須です", period.Name)
+	}
+	if period.StartDate.IsZero() || period.EndDate.IsZero() {
+		return NewValidationError("start_date", "開始日と終了日は必須です", "")
+	}
+
+	// 会計期間は日単位で管理する
+	period.StartDate = truncateToDate(period.StartDate)
+	period.EndDate = truncateToDate(period.EndDate)
+	if period.EndDate.Before(period.StartDate) {
+		return NewValidationError("end_date", "終了日は開始日以降である必要があります", period.EndDate.Format("2006-01-02"))
+	}
+
+	periods, err := m.storage.ListFiscalPeriods(ctx)
+	if err != nil {
+		return NewStorageError("list_fiscal_periods", "会計期間一覧取得に失敗しました", err)
+	}
+	for _, existing := range periods {
+		if !period.StartDate.After(existing.EndDate) && !existing.StartDate.After(period.EndDate) {
+			return NewValidationError("start_date", "既存の会計期間と重複しています", existing.Name)
+		}
+	}
+
+	if period.ID == "" {
+		period.ID = NewTransactionID()
+	}
+	period.Status = FiscalPeriodStatusOpen
+	period.ClosedAt = nil
+	period.ClosedBy = ""
+	period.CreatedAt = time.Now()
+	period.CreatedBy = m.getUserFromContext(ctx)
+
+	if err := m.storage.CreateFiscalPeriod(ctx, period); err != nil {
+		return NewStorageError("create_fiscal_period", "会計期間作成に失敗しました", err)
+	}
+
+	m.logger.Info("会計期間作成完了",
+		zap.String("period_id", period.ID),
+		zap.String("name", period.Name),
+		zap.Time("start_date", period.StartDate),
+		zap.Time("end_date", period.EndDate),
+	)
+
+	return nil
+}
+
+// GetFiscalPeriod retrieves a fiscal period
+// 会計期間を取得
+func (m *Manager) GetFiscalPeriod(ctx context.Context, periodID string) (*FiscalPeriod, error) {
+	period, err := m.storage.GetFiscalPeriod(ctx, periodID)
+	if err != nil {
+		if err == ErrFiscalPeriodNotFound {
+			return nil, ErrFiscalPeriodNotFound
+		}
+		return nil, NewStorageError("get_fiscal_period", "会計期間取得に失敗しました", err)
+	}
+	return period, nil
+}
+
+// ListFiscalPeriods lists all fiscal periods in start date order
+// 会計期間一覧を開始日順に取得
+func (m *Manager) ListFiscalPeriods(ctx context.Context) ([]FiscalPeriod, error) {
+	periods, err := m.storage.ListFiscalPeriods(ctx)
+	if err != nil {
+		return nil, NewStorageError("list_fiscal_periods", "会計期間一覧取得に失敗しました", err)
+	}
+	return periods, nil
+}
+
+// CloseFiscalPeriod closes an ended period, snapshotting quantities and values per item and location
+// 終了日を過ぎた会計期間を締め、商品・ロケーション別の期末数量と金額を保存
+//
+// 締め後は期間内の日付での計上・取消がErrPeriodClosedで拒否されます。
+func (m *Manager) CloseFiscalPeriod(ctx context.Context, periodID string) (*FiscalPeriod, error) {
+	period, err := m.GetFiscalPeriod(ctx, periodID)
+	if err != nil {
+		return nil, err
+	}
+	if period.Status != FiscalPeriodStatusOpen {
+		return nil, ErrInvalidFiscalPeriodStatus
+	}
+
+	// 期間中の計上が終わっていない期間は締められない
+	now := time.Now()
+	if now.Before(period.PeriodEnd()) {
+		return nil, NewBusinessRuleError("period_not_ended", "終了日を過ぎていない会計期間は締められません", period.EndDate.Format("2006-01-02"))
+	}
+
+	userID := m.getUserFromContext(ctx)
+	period.Status = FiscalPeriodStatusClosed
+	period.ClosedAt = &now
+	period.ClosedBy = userID
+
+	event := &FiscalPeriodEvent{
+		ID:          NewTransactionID(),
+		PeriodID:    period.ID,
+		Action:      FiscalPeriodActionClose,
+		PerformedAt: now,
+		PerformedBy: userID,
+	}
+
+	count, err := m.storage.CloseFiscalPeriod(ctx, period, event)
+	if err != nil {
+		if err == ErrInvalidFiscalPeriodStatus {
+			return nil, ErrInvalidFiscalPeriodStatus
+		}
+		return nil, NewStorageError("close_fiscal_period", "会計期間の締めに失敗しました", err)
+	}
+
+	m.logger.Info("会計期間締め完了",
+		zap.String("period_id", period.ID),
+		zap.String("name", period.Name),
+		zap.Int64("balance_count", count),
+		zap.String("closed_by", userID),
+	)
+
+	return period, nil
+}
+
+// ReopenFiscalPeriod reopens a closed period with a mandatory reason recorded in the audit trail
+// 締め済みの会計期間を締め解除（理由は必須、監査記録に保存）
+//
+// 期末残高は削除され、再度締めた時点の数量と金額で作り直されます。
+func (m *Manager) ReopenFiscalPeriod(ctx context.Context, periodID, reason string) (*FiscalPeriod, error) {
+	if reason == "" {
+		return nil, NewValidationError("reason", "締め解除の理由は必須です", reason)
+	}
+
+	period, err := m.GetFiscalPeriod(ctx, periodID)
+	if err != nil {
+		return nil, err
+	}
+	if period.Status != FiscalPeriodStatusClosed {
+		return nil, ErrInvalidFiscalPeriodStatus
+	}
+
+	userID := m.getUserFromContext(ctx)
+	event := &FiscalPeriodEvent{
+		ID:          NewTransactionID(),
+		PeriodID:    period.ID,
+		Action:      FiscalPeriodActionReopen,
+		Reason:      reason,
+		PerformedAt: time.Now(),
+		PerformedBy: userID,
+	}
+
+	if err := m.storage.ReopenFiscalPeriod(ctx, period.ID, event); err != nil {
+		if err == ErrInvalidFiscalPeriodStatus {
+			return nil, ErrInvalidFiscalPeriodStatus
+		}
+		return nil, NewStorageError("reopen_fiscal_period", "会計期間の締め解除に失敗しました", err)
+	}
+
+	period.Status = FiscalPeriodStatusOpen
+	period.ClosedAt = nil
+	period.ClosedBy = ""
+
+	m.logger.Warn("会計期間を締め解除しました",
+		zap.String("period_id", period.ID),
+		zap.String("name", period.Name),
+		zap.String("reason", reason),
+		zap.String("reopened_by", userID),
+	)
+
+	return period, nil
+}
+
+// ListPeriodEndBalances lists the period-end balances saved when the period was closed
+// 締め時に保存した期末残高を取得（ロケーションで絞り込み可能）
+func (m *Manager) ListPeriodEndBalances(ctx context.Context, periodID, locationID string, offset, limit int) ([]PeriodEndBalance, error) {
+	if _, err := m.GetFiscalPeriod(ctx, periodID); err != nil {
+		return nil, err
+	}
+
+	balances, err := m.storage.ListPeriodEndBalances(ctx, periodID, locationID, offset, limit)
+	if err != nil {
+		return nil, NewStorageError("list_period_end_balances", "期末残高取得に失敗しました", err)
+	}
+	return balances, nil
+}
+
+// ListFiscalPeriodEvents lists the close and reopen audit records of a period
+// 会計期間の締め・締め解除の監査記録を取得
+func (m *Manager) ListFiscalPeriodEvents(ctx context.Context, periodID string) ([]FiscalPeriodEvent, error) {
+	if _, err := m.GetFiscalPeriod(ctx, periodID); err != nil {
+		return nil, err
+	}
+
+	events, err := m.storage.ListFiscalPeriodEvents(ctx, periodID)
+	if err != nil {
+		return nil, NewStorageError("list_fiscal_period_events", "会計期間の監査記録取得に失敗しました", err)
+	}
+	return events, nil
+}
+
+// truncateToDate drops the time of day, keeping the calendar date in UTC
+// 時刻を切り捨て、日付のみをUTCで返す
+func truncateToDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// ===== ExchangeRateManager実装 =====
+
+// baseCurrency returns the currency inventory is valued in
+// 在庫評価の基準通貨を返す
+func (m *Manager) baseCurrency() string {
+	if m.config.BaseCurrency == "" {
+		return DefaultCurrency
+	}
+	return strings.ToUpper(m.config.BaseCurrency)
+}
+
+// SetExchangeRate registers the rate of a foreign currency into the base currency from its effective date
+// 外貨から基準通貨への為替レートを適用開始日付きで登録（同じ適用開始日のレートは上書き）
+//
+// 計上済みのトランザクションは計上時の為替レートを保持するため、登録・上書きの影響を受けません。
+func (m *Manager) SetExchangeRate(ctx context.Context, rate *ExchangeRate) error {
+	rate.Currency = strings.ToUpper(strings.TrimSpace(rate.Currency))
+	if err := ValidateCurrency(rate.Currency); err != nil {
+		return err
+	}
+	base := m.baseCurrency()
+	if rate.Currency == base {
+		return NewValidationError("currency", "基準通貨の為替レートは登録できません", rate.Currency)
+	}
+	if rate.Rate <= 0 {
+		return NewValidationError("rate", "為替レートは正の値である必要があります", rate.Rate.String())
+	}
+	if rate.EffectiveDate.IsZero() {
+		return NewValidationError("effective_date", "適用開始日は必須です", "")
+	}
+
+	// 為替レートは日単位で管理する
+	rate.EffectiveDate = truncateToDate(rate.EffectiveDate)
+	if rate.ID == "" {
+		rate.ID = NewTransactionID()
+	}
+	rate.BaseCurrency = base
+	rate.CreatedAt = time.Now()
+	rate.CreatedBy = m.getUserFromContext(ctx)
+
+	if err := m.storage.SaveExchangeRate(ctx, rate); err != nil {
+		return NewStorageError("save_exchange_rate", "為替レート保存に失敗しました", err)
+	}
+
+	m.logger.Info("為替レート登録完了",
+		zap.String("currency", rate.Currency),
+		zap.String("base_currency", rate.BaseCurrency),
+		zap.String("rate", rate.Rate.String()),
+		zap.Time("effective_date", rate.EffectiveDate),
+	)
+
+	return nil
+}
+
+// ListExchangeRates lists the registered rates into the base currency, newest effective date first
+// 基準通貨への為替レート一覧を適用開始日の新しい順に取得（currencyが空の場合は全通貨）
+func (m *Manager) ListExchangeRates(ctx context.Context, currency string) ([]ExchangeRate, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	rates, err := m.storage.ListExchangeRates(ctx, currency, m.baseCurrency())
+	if err != nil {
+		return nil, NewStorageError("list_exchange_rates", "為替レート一覧取得に失敗しました", err)
+	}
+	return rates, nil
+}
+
+// GetExchangeRate returns the rate of a currency into the base currency effective at the given time
+// 指定日時に有効な基準通貨への為替レートを取得（基準通貨自身はレート1）
+func (m *Manager) GetExchangeRate(ctx context.Context, currency string, at time.Time) (*ExchangeRate, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if err := ValidateCurrency(currency); err != nil {
+		return nil, err
+	}
+
+	base := m.baseCurrency()
+	if currency == base {
+		return &ExchangeRate{
+			Currency:      base,
+			BaseCurrency:  base,
+			Rate:          rateScale,
+			EffectiveDate: truncateToDate(at),
+		}, nil
+	}
+
+	rate, err := m.storage.GetExchangeRate(ctx, currency, base, at)
+	if err != nil {
+		if err == ErrExchangeRateNotFound {
+			return nil, ErrExchangeRateNotFound
+		}
+		return nil, NewStorageError("get_exchange_rate", "為替レート取得に失敗しました", err)
+	}
+	return rate, nil
+}
+
+// ===== LedgerReconciler実装 =====
+
+// ReconcileLedger compares stock balances with the ledger and optionally posts corrective adjustments
+// 在庫数量と台帳残高を照合し、fix指定時は差異分の調整トランザクションを台帳に記録
+func (m *Manager) ReconcileLedger(ctx context.Context, fix bool) (*ReconciliationReport, error) {
+	drifts, err := m.storage.ListStockDrifts(ctx)
+	if err != nil {
+		return nil, NewStorageError("list_stock_drifts", "在庫差異の取得に失敗しました", err)
+	}
+	if drifts == nil {
+		drifts = []StockDrift{}
+	}
+
+	now := time.Now()
+	report := &ReconciliationReport{
+		Reference:   NewReconciliationReference(now),
+		GeneratedAt: now,
+		DriftCount:  len(drifts),
+		Drifts:      drifts,
+	}
+
+	if fix {
+		// 在庫テーブルを正として、台帳側に差異分の調整を記録する
+		// 照合時の在庫バージョンで楽観的ロックを取り、照合後に在庫が動いた商品は補正せずエラーとして報告する
+		for i := range report.Drifts {
+			drift := &report.Drifts[i]
+			if err := m.postLedgerCorrection(ctx, drift, report.Reference, now); err != nil {
+				drift.Error = err.Error()
+				m.logger.Warn("台帳補正に失敗しました",
+					zap.String("item_id", drift.ItemID),
+					zap.String("location_id", drift.LocationID),
+					zap.Error(err),
+				)
+				continue
+			}
+			drift.Fixed = true
+			report.FixedCount++
+		}
+	}
+
+	m.logger.Info("台帳照合完了",
+		zap.String("reference", report.Reference),
+		zap.Int("drift_count", report.DriftCount),
+		zap.Int("fixed_count", report.FixedCount),
+	)
+
+	return report, nil
+}
+
+// postLedgerCorrection posts the corrective adjustment for one drift, guarded by the compared stock version
+// 1件の差異の補正トランザクションを、照合時の在庫バージョンによる楽観的ロック付きで記録
+func (m *Manager) postLedgerCorrection(ctx context.Context, drift *StockDrift, reference string, now time.Time) error {
+	stock, err := m.storage.GetStock(ctx, drift.ItemID, drift.LocationID)
+	if err != nil {
+		if err != ErrStockNotFound {
+			return NewStorageError("get_stock", "在庫取得に失敗しました", err)
+		}
+		// 在庫記録のない台帳残高は数量0の在庫記録を新規作成して補正する
+		stock = &Stock{ItemID: drift.ItemID, LocationID: drift.LocationID}
+	}
+	if stock.Version != drift.StockVersion || stock.Quantity != drift.StockQuantity {
+		return ErrVersionMismatch
+	}
+
+	// 数量は変えずにバージョンのみ進め、照合後の在庫更新と競合した場合は補正を記録しない
+	stock.Version++
+	stock.UpdatedAt = now
+	stock.UpdatedBy = m.getUserFromContext(ctx)
+	stock.CalculateAvailable()
+
+	locationID := drift.LocationID
+	tx := &Transaction{
+		ID:         NewTransactionID(),
+		Type:       TransactionTypeAdjust,
+		ItemID:     drift.ItemID,
+		ToLocation: &locationID,
+		Quantity:   drift.Difference,
+		Reference:  reference,
+		CreatedAt:  now,
+		CreatedBy:  stock.UpdatedBy,
+	}
+
+	if err := m.storage.ApplyStockChanges(ctx, []*Stock{stock}, []*Transaction{tx}); err != nil {
+		if err == ErrVersionMismatch {
+			return err
+		}
+		return NewStorageError("apply_stock_changes", "補正トランザクション記録に失敗しました", err)
+	}
+	return nil
+}
+
+// ===== ItemManager実装 =====
+
+// CreateItem creates a new item
+// 新しい商品を作成
+//
+// カテゴリに属性スキーマがある場合はカスタム属性を検証し、親商品を指定した場合はバリアントとして検証します。
+func (m *Manager) CreateItem(ctx context.Context, item *Item) error {
+	if err := m.validateItem(ctx, item); err != nil {
+		return err
+	}
+	return m.storage.CreateItem(ctx, item)
+}
+
+// GetItem gets an item by ID
+// IDで商品を取得
+func (m *Manager) GetItem(ctx context.Context, itemID string) (*Item, error) {
+	return m.storage.GetItem(ctx, itemID)
+}
+
+// UpdateItem updates an existing item
+// 既存の商品を更新
+//
+// 数量は小数桁数に応じた保存単位で記録されるため、取引のある商品の小数桁数は変更できません。
+// バリアントグループの親商品はカテゴリを変更できません（子SKUは親商品と同じカテゴリである必要があるため）。
+func (m *Manager) UpdateItem(ctx context.Context, item *Item) error {
+	if err := m.validateItem(ctx, item); err != nil {
+		return err
+	}
+
+	current, err := m.storage.GetItem(ctx, item.ID)
+	if err != nil {
+		if err == ErrItemNotFound {
+			return ErrItemNotFound
+		}
+		return NewStorageError("get_item", "商品取得に失敗しました", err)
+	}
+	if err := m.checkItemUpdate(ctx, current, item); err != nil {
+		return err
+	}
+
+	return m.storage.UpdateItem(ctx, item)
+}
+
+// checkItemUpdate checks the changes that are locked once an item is in use
+// 使用中の商品で変更できない項目の変更を検査
+//
+// 取引のある商品の数量の小数桁数と、バリアントグループの親商品のカテゴリは変更できません。
+func (m *Manager) checkItemUpdate(ctx context.Context, current, item *Item) error {
+	if current.QuantityPrecision != item.QuantityPrecision {
+		history, err := m.storage.GetTransactionHistory(ctx, item.ID, 1)
+		if err != nil {
+			return NewStorageError("get_transaction_history", "トランザクション履歴取得に失敗しました", err)
+		}
+		if len(history) > 0 {
+			return NewBusinessRuleError("quantity_precision_locked", "取引のある商品の数量の小数桁数は変更できません",
+				fmt.Sprintf("商品ID: %s, 小数桁数: %d -> %d", item.ID, current.QuantityPrecision, item.QuantityPrecision))
+		}
+	}
+	if current.Category != item.Category {
+		if _, err := m.storage.GetVariantGroup(ctx, item.ID); err == nil {
+			return NewBusinessRuleError("variant_category_locked", "バリアントグループの親商品のカテゴリは変更できません",
+				fmt.Sprintf("商品ID: %s, カテゴリ: %s -> %s", item.ID, current.Category, item.Category))
+		} else if err != ErrVariantGroupNotFound {
+			return NewStorageError("get_variant_group", "バリアントグループ取得に失敗しました", err)
+		}
+	}
+	return nil
+}
+
+// DeleteItem archives an item that no longer has stock, reservations or open documents
+// 在庫・引当・未完了の伝票がない商品をアーカイブ（論理削除）
+//
+// 台帳の履歴を保持するため商品の行は削除せず、アーカイブ日時を設定します。
+// アーカイブ済みの商品は既定の一覧・検索から除外され、新しい在庫移動・伝票には使用できません。既にアーカイブ済みの場合は何もしません。
+func (m *Manager) DeleteItem(ctx context.Context, itemID string) error {
+	item, err := m.storage.GetItem(ctx, itemID)
+	if err != nil {
+		if err == ErrItemNotFound {
+			return ErrItemNotFound
+		}
+		return NewStorageError("get_item", "商品取得に失敗しました", err)
+	}
+	if item.ArchivedAt != nil {
+		return nil
+	}
+
+	usage, err := m.storage.GetItemUsage(ctx, itemID)
+	if err != nil {
+		return NewStorageError("get_item_usage", "商品の使用状況の取得に失敗しました", err)
+	}
+	if blockers := usage.Blockers(); len(blockers) > 0 {
+		return NewBusinessRuleError("item_in_use", "在庫・引当または未完了の伝票がある商品は削除できません",
+			fmt.Sprintf("商品ID: %s, %s", itemID, strings.Join(blockers, ", ")))
+	}
+
+	now := time.Now()
+	if err := m.storage.SetItemArchivedAt(ctx, itemID, &now); err != nil {
+		if err == ErrItemNotFound {
+			return ErrItemNotFound
+		}
+		return NewStorageError("set_item_archived_at", "商品のアーカイブに失敗しました", err)
+	}
+
+	m.logger.Info("商品アーカイブ完了",
+		zap.String("item_id", itemID),
+		zap.String("user", m.getUserFromContext(ctx)),
+	)
+
+	return nil
+}
+
+// ListItems lists items matching the filter with cursor pagination and the total count
+// 条件に一致する商品をカーソルページング付きで全件数とともに取得
+//
+// 既定の並び順は商品名の昇順、1ページの件数は20件（最大100件）です。キーワードは部分一致で判定します。
+func (m *Manager) ListItems(ctx context.Context, filter ItemSearchFilter) (*ItemPage, error) {
+	if err := normalizeItemFilter(&filter, ItemSortName); err != nil {
+		return nil, err
+	}
+	if filter.Sort == ItemSortRelevance {
+		return nil, NewValidationError("sort", "類似度順は商品検索でのみ指定できます", string(filter.Sort))
+	}
+
+	page, err := m.storage.ListItems(ctx, filter)
+	if err != nil {
+		return nil, NewStorageError("list_items", "商品一覧取得に失敗しました", err)
+	}
+	return page, nil
+}
+
+// SearchItems fuzzy-searches items by keyword and narrows them by the other filter criteria
+// キーワードによるあいまい検索と、カテゴリ・親商品・カスタム属性などの条件で商品を検索（条件はすべてAND結合）
+//
+// キーワードは商品名・SKU・説明へのトライグラム類似度で判定し、キーワードがある場合の既定の並び順は類似度順です。
+func (m *Manager) SearchItems(ctx context.Context, filter ItemSearchFilter) (*ItemPage, error) {
+	filter.Query = strings.TrimSpace(filter.Query)
+	if filter.Query == "" && filter.Category == "" && filter.ParentItemID == "" && len(filter.Attributes) == 0 &&
+		filter.Active == nil && filter.MinUnitCost == nil && filter.MaxUnitCost == nil && filter.HasStock == nil {
+		return nil, NewValidationError("query", "検索条件が指定されていません", "")
+	}
+
+	defaultSort := ItemSortName
+	if filter.Query != "" {
+		defaultSort = ItemSortRelevance
+	}
+	if err := normalizeItemFilter(&filter, defaultSort); err != nil {
+		return nil, err
+	}
+	if filter.Sort == ItemSortRelevance && filter.Query == "" {
+		return nil, NewValidationError("sort", "類似度順にはキーワードが必要です", string(filter.Sort))
+	}
+
+	page, err := m.storage.SearchItems(ctx, filter)
+	if err != nil {
+		return nil, NewStorageError("search_items", "商品検索に失敗しました", err)
+	}
+	return page, nil
+}
+
+// normalizeItemFilter validates an item filter and fills in the default sort and page size
+// 商品一覧・検索の条件を検証し、並び順と1ページの件数の既定値を設定
+func normalizeItemFilter(filter *ItemSearchFilter, defaultSort ItemSortField) error {
+	filter.Query = strings.TrimSpace(filter.Query)
+	if len(filter.Query) > 255 {
+		return NewValidationError("query", "検索キーワードが長すぎます", filter.Query)
+	}
+	for name := range filter.Attributes {
+		if err := ValidateAttributeName(name); err != nil {
+			return err
+		}
+	}
+	if filter.MinUnitCost != nil && filter.MaxUnitCost != nil && *filter.MinUnitCost > *filter.MaxUnitCost {
+		return NewValidationError("min_unit_cost", "単価の下限が上限を超えています",
+			fmt.Sprintf("%s > %s", filter.MinUnitCost.String(), filter.MaxUnitCost.String()))
+	}
+
+	if filter.Sort == "" {
+		filter.Sort = defaultSort
+	}
+	if !filter.Sort.IsValid() {
+		return NewValidationError("sort", "無効な並び順です", string(filter.Sort))
+	}
+	switch {
+	case filter.Limit == 0:
+		filter.Limit = DefaultItemPageSize
+	case filter.Limit < 0 || filter.Limit > MaxItemPageSize:
+		return NewValidationError("limit", fmt.Sprintf("件数は1〜%d件である必要があります", MaxItemPageSize), fmt.Sprintf("%d", filter.Limit))
+	}
+
+	if filter.Cursor != "" {
+		cursor, err := DecodeItemCursor(filter.Cursor)
+		if err != nil {
+			return err
+		}
+		if cursor.Sort != filter.Sort || cursor.Desc != filter.Desc {
+			return NewValidationError("cursor", "カーソルの並び順が検索条件と一致しません", filter.Cursor)
+		}
+	}
+	return nil
+}
+
+// validateItem validates an item against its category attribute schema and, for variants, its variant group
+// カテゴリの属性スキーマと（バリアントの場合は）バリアントグループに照らして商品を検証
+func (m *Manager) validateItem(ctx context.Context, item *Item) error {
+	if item == nil {
+		return ValidateItem(nil, nil)
+	}
+	schema, err := m.categoryAttributeSchema(ctx, item.Category)
+	if err != nil {
+		return err
+	}
+	if err := ValidateItem(item, schema); err != nil {
+		return err
+	}
+	if item.ParentItemID != nil {
+		return m.validateVariant(ctx, item)
+	}
+	return nil
+}
+
+// categoryAttributeSchema returns the attribute schema of a category, or nil if none is defined
+// カテゴリの属性スキーマを取得（カテゴリが空またはスキーマ未定義の場合はnil）
+func (m *Manager) categoryAttributeSchema(ctx context.Context, category string) (*CategoryAttributeSchema, error) {
+	if category == "" {
+		return nil, nil
+	}
+	schema, err := m.storage.GetCategoryAttributeSchema(ctx, category)
+	if err != nil {
+		if err == ErrAttributeSchemaNotFound {
+			return nil, nil
+		}
+		return nil, NewStorageError("get_category_attribute_schema", "属性スキーマ取得に失敗しました", err)
+	}
+	return schema, nil
+}
+
+// validateVariant checks that a child SKU fits its parent's variant group
+// 子SKUが親商品のバリアントグループに適合するかを検証
+//
+// 親商品と同じカテゴリであること、すべてのバリアント軸の値を属性に持つこと、
+// 軸の値の組み合わせが他の子SKUと重複しないこと、自身がバリアントグループの親商品でないことを確認します。
+func (m *Manager) validateVariant(ctx context.Context, item *Item) error {
+	parentID := *item.ParentItemID
+	group, err := m.storage.GetVariantGroup(ctx, parentID)
+	if err != nil {
+		if err == ErrVariantGroupNotFound {
+			return NewValidationError("parent_item_id", "親商品にバリアントグループがありません", parentID)
+		}
+		return NewStorageError("get_variant_group", "バリアントグループ取得に失敗しました", err)
+	}
+	parent, err := m.storage.GetItem(ctx, parentID)
+	if err != nil {
+		if err == ErrItemNotFound {
+			return NewValidationError("parent_item_id", "親商品が見つかりません", parentID)
+		}
+		return NewStorageError("get_item", "商品取得に失敗しました", err)
+	}
+	if parent.ArchivedAt != nil {
+		return NewValidationError("parent_item_id", "親商品はアーカイブ済みです", parentID)
+	}
+	if parent.Category != item.Category {
+		return NewValidationError("category", "バリアントは親商品と同じカテゴリである必要があります", item.Category)
+	}
+	for _, axis := range group.Axes {
+		if _, ok := item.Attributes[axis]; !ok {
+			return NewValidationError("attributes", "バリアント軸の属性が指定されていません", axis)
+		}
+	}
+
+	if _, err := m.storage.GetVariantGroup(ctx, item.ID); err == nil {
+		return NewBusinessRuleError("nested_variant", "バリアントグループの親商品はバリアントにできません",
+			fmt.Sprintf("商品ID: %s", item.ID))
+	} else if err != ErrVariantGroupNotFound {
+		return NewStorageError("get_variant_group", "バリアントグループ取得に失敗しました", err)
+	}
+
+	siblings, err := m.listVariants(ctx, parentID)
+	if err != nil {
+		return err
+	}
+	for _, sibling := range siblings {
+		if sibling.ID != item.ID && sameVariantAxes(group.Axes, sibling.Attributes, item.Attributes) {
+			return NewBusinessRuleError("duplicate_variant", "同じバリアント軸の値を持つ子SKUが既に存在します",
+				fmt.Sprintf("親商品ID: %s, 既存の子SKU: %s", parentID, sibling.ID))
+		}
+	}
+	return nil
+}
+
+// sameVariantAxes reports whether two attribute sets have equal values on every variant axis
+// 2つの属性がすべてのバリアント軸で同じ値を持つかを判定（値は文字列表現で比較）
+func sameVariantAxes(axes []string, a, b map[string]interface{}) bool {
+	for _, axis := range axes {
+		if fmt.Sprint(a[axis]) != fmt.Sprint(b[axis]) {
+			return false
+		}
+	}
+	return true
+}
+
+// ===== ItemAttributeManager実装 =====
+
+// SetCategoryAttributeSchema defines the custom attributes allowed on items of a category
+// カテゴリの商品に設定できるカスタム属性スキーマを登録（既存のスキーマは上書き）
+//
+// 既存の商品は再検証されず、次回の作成・更新時に新しいスキーマで検証されます。
+func (m *Manager) SetCategoryAttributeSchema(ctx context.Context, schema *CategoryAttributeSchema) error {
+	if err := ValidateAttributeSchema(schema); err != nil {
+		return err
+	}
+
+	schema.UpdatedAt = time.Now()
+	if err := m.storage.SaveCategoryAttributeSchema(ctx, schema); err != nil {
+		return NewStorageError("save_category_attribute_schema", "属性スキーマ保存に失敗しました", err)
+	}
+
+	m.logger.Info("属性スキーマ登録完了",
+		zap.String("category", schema.Category),
+		zap.Int("attribute_count", len(schema.Attributes)),
+	)
+
+	return nil
+}
+
+// GetCategoryAttributeSchema gets the attribute schema of a category
+// カテゴリの属性スキーマを取得
+func (m *Manager) GetCategoryAttributeSchema(ctx context.Context, category string) (*CategoryAttributeSchema, error) {
+	return m.storage.GetCategoryAttributeSchema(ctx, category)
+}
+
+// ListCategoryAttributeSchemas lists the attribute schemas of all categories
+// 全カテゴリの属性スキーマ一覧を取得
+func (m *Manager) ListCategoryAttributeSchemas(ctx context.Context) ([]CategoryAttributeSchema, error) {
+	schemas, err := m.storage.ListCategoryAttributeSchemas(ctx)
+	if err != nil {
+		return nil, NewStorageError("list_category_attribute_schemas", "属性スキーマ一覧取得に失敗しました", err)
+	}
+	return schemas, nil
+}
+
+// SetVariantGroup makes an item the parent of a variant group with the given variant axes
+// 商品をバリアントグループの親商品として登録し、バリアント軸を設定
+//
+// 親商品自身はバリアントであってはならず、カテゴリに属性スキーマがある場合は軸がスキーマに定義されている必要があります。
+// 子SKUが存在するグループの軸は変更できません。
+func (m *Manager) SetVariantGroup(ctx context.Context, group *VariantGroup) error {
+	if err := ValidateVariantGroup(group); err != nil {
+		return err
+	}
+
+	parent, err := m.storage.GetItem(ctx, group.ParentItemID)
+	if err != nil {
+		if err == ErrItemNotFound {
+			return ErrItemNotFound
+		}
+		return NewStorageError("get_item", "商品取得に失敗しました", err)
+	}
+	if parent.ParentItemID != nil {
+		return NewBusinessRuleError("nested_variant", "バリアントを親商品にすることはできません",
+			fmt.Sprintf("商品ID: %s, 親商品ID: %s", parent.ID, *parent.ParentItemID))
+	}
+
+	schema, err := m.categoryAttributeSchema(ctx, parent.Category)
+	if err != nil {
+		return err
+	}
+	if schema != nil {
+		for _, axis := range group.Axes {
+			if schema.Attribute(axis) == nil {
+				return NewValidationError("axes", "バリアント軸がカテゴリの属性スキーマに定義されていません", axis)
+			}
+		}
+	}
+
+	now := time.Now()
+	group.CreatedAt = now
+	existing, err := m.storage.GetVariantGroup(ctx, group.ParentItemID)
+	switch {
+	case err == nil:
+		group.CreatedAt = existing.CreatedAt
+		if !equalStrings(existing.Axes, group.Axes) {
+			variants, err := m.listVariants(ctx, group.ParentItemID)
+			if err != nil {
+				return err
+			}
+			if len(variants) > 0 {
+				return NewBusinessRuleError("variant_axes_locked", "子SKUのあるバリアントグループの軸は変更できません",
+					fmt.Sprintf("親商品ID: %s, 子SKU数: %d", group.ParentItemID, len(variants)))
+			}
+		}
+	case err != ErrVariantGroupNotFound:
+		return NewStorageError("get_variant_group", "バリアントグループ取得に失敗しました", err)
+	}
+
+	group.UpdatedAt = now
+	group.Variants = nil
+	if err := m.storage.SaveVariantGroup(ctx, group); err != nil {
+		return NewStorageError("save_variant_group", "バリアントグループ保存に失敗しました", err)
+	}
+
+	m.logger.Info("バリアントグループ登録完了",
+		zap.String("parent_item_id", group.ParentItemID),
+		zap.Strings("axes", group.Axes),
+	)
+
+	return nil
+}
+
+// GetVariantGroup gets the variant group of a parent item together with its child SKUs
+// 親商品のバリアントグループを子SKUとともに取得
+func (m *Manager) GetVariantGroup(ctx context.Context, parentItemID string) (*VariantGroup, error) {
+	group, err := m.storage.GetVariantGroup(ctx, parentItemID)
+	if err != nil {
+		return nil, err
+	}
+	variants, err := m.listVariants(ctx, parentItemID)
+	if err != nil {
+		return nil, err
+	}
+	group.Variants = variants
+	return group, nil
+}
+
+// listVariants lists all child SKUs of a parent item
+// 親商品のすべての子SKUを取得
+func (m *Manager) listVariants(ctx context.Context, parentItemID string) ([]Item, error) {
+	page, err := m.storage.ListItems(ctx, ItemSearchFilter{ParentItemID: parentItemID})
+	if err != nil {
+		return nil, NewStorageError("list_items", "バリアント一覧取得に失敗しました", err)
+	}
+	return page.Items, nil
+}
+
+// equalStrings reports whether two string slices have the same elements in the same order
+// 2つの文字列スライスが同じ順序で同じ要素を持つかを判定
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ===== BarcodeManager実装 =====
+
+// AddItemBarcode registers a JAN/EAN/UPC/GTIN barcode for an item at a packaging level
+// 商品に包装レベル付きのバーコード（JAN/EAN/UPC/GTIN）を登録
+//
+// 包装レベルの省略時は単品（入数1）とします。バーコードはGTIN-14に正規化して一意性を判定するため、
+// 同じ番号のJAN-13とGTIN-14（先頭0）は重複として扱います。
+func (m *Manager) AddItemBarcode(ctx context.Context, barcode *ItemBarcode) error {
+	if barcode == nil {
+		return ValidateItemBarcode(nil)
+	}
+	barcode.Code = strings.TrimSpace(barcode.Code)
+	barcode.Type = BarcodeType(strings.ToUpper(string(barcode.Type)))
+	if barcode.PackageLevel == "" {
+		barcode.PackageLevel = PackageLevelEach
+	}
+	if barcode.PackageLevel == PackageLevelEach && barcode.UnitsPerPackage == 0 {
+		barcode.UnitsPerPackage = 1
+	}
+	if err := ValidateItemBarcode(barcode); err != nil {
+		return err
+	}
+
+	item, err := m.storage.GetItem(ctx, barcode.ItemID)
+	if err != nil {
+		if err == ErrItemNotFound {
+			return ErrItemNotFound
+		}
+		return NewStorageError("get_item", "商品取得に失敗しました", err)
+	}
+	if item.ArchivedAt != nil {
+		return ErrItemArchived
+	}
+
+	barcode.GTIN = NormalizeGTIN(barcode.Code)
+	barcode.CreatedAt = time.Now()
+	if err := m.storage.SaveItemBarcode(ctx, barcode); err != nil {
+		if err == ErrDuplicateBarcode {
+			return ErrDuplicateBarcode
+		}
+		return NewStorageError("save_item_barcode", "バーコード保存に失敗しました", err)
+	}
+
+	m.logger.Info("バーコード登録完了",
+		zap.String("item_id", barcode.ItemID),
+		zap.String("gtin", barcode.GTIN),
+		zap.String("package_level", string(barcode.PackageLevel)),
+		zap.Int64("units_per_package", barcode.UnitsPerPackage),
+	)
+
+	return nil
+}
+
+// ListItemBarcodes lists the barcodes registered for an item
+// 商品に登録されたバーコード一覧を取得
+func (m *Manager) ListItemBarcodes(ctx context.Context, itemID string) ([]ItemBarcode, error) {
+	barcodes, err := m.storage.ListItemBarcodes(ctx, itemID)
+	if err != nil {
+		return nil, NewStorageError("list_item_barcodes", "バーコード一覧取得に失敗しました", err)
+	}
+	return barcodes, nil
+}
+
+// RemoveItemBarcode removes a barcode from an item
+// 商品からバーコードを削除
+func (m *Manager) RemoveItemBarcode(ctx context.Context, itemID, code string) error {
+	code = strings.TrimSpace(code)
+	if err := ValidateGTIN(code); err != nil {
+		return err
+	}
+	if err := m.storage.DeleteItemBarcode(ctx, itemID, NormalizeGTIN(code)); err != nil {
+		if err == ErrBarcodeNotFound {
+			return ErrBarcodeNotFound
+		}
+		return NewStorageError("delete_item_barcode", "バーコード削除に失敗しました", err)
+	}
+	return nil
+}
+
+// ResolveScan resolves a scanned JAN/EAN/UPC/GTIN or GS1 label to the item, lot and suggested quantity
+// 読み取ったJAN/EAN/UPC/GTINまたはGS1ラベルから商品・ロット・推奨数量を解決
+//
+// 8・12・13・14桁の数字はバーコードとして、それ以外はGS1データ（(01)GTIN、(10)ロット、(17)有効期限、
+// (21)シリアル、(30)/(37)数量）として解析します。推奨数量は包装の入数×包装数を商品の保存単位で返します。
+func (m *Manager) ResolveScan(ctx context.Context, input string) (*ScanResult, error) {
+	text := strings.TrimSpace(input)
+	if text == "" {
+		return nil, NewValidationError("code", "読み取りデータが空です", input)
+	}
+
+	result := &ScanResult{Input: input, PackageCount: 1}
+	switch {
+	case isDigits(text) && (len(text) == 8 || len(text) == 12 || len(text) == 13 || len(text) == 14):
+		if err := ValidateGTIN(text); err != nil {
+			return nil, err
+		}
+		result.GTIN = NormalizeGTIN(text)
+	default:
+		data, err := ParseGS1(text)
+		if err != nil {
+			return nil, err
+		}
+		if data.GTIN == "" {
+			return nil, NewValidationError("code", "GS1データにGTIN（AI 01）が含まれていません", input)
+		}
+		result.GS1 = data
+		result.GTIN = NormalizeGTIN(data.GTIN)
+		result.LotNumber = data.LotNumber
+		result.ExpiryDate = data.ExpiryDate
+		result.SerialNumber = data.Serial
+		if data.Count > 0 {
+			result.PackageCount = data.Count
+		}
+	}
+
+	barcode, err := m.storage.GetItemBarcode(ctx, result.GTIN)
+	if err != nil {
+		if err == ErrBarcodeNotFound {
+			return nil, ErrBarcodeNotFound
+		}
+		return nil, NewStorageError("get_item_barcode", "バーコード取得に失敗しました", err)
+	}
+	item, err := m.storage.GetItem(ctx, barcode.ItemID)
+	if err != nil {
+		if err == ErrItemNotFound {
+			return nil, ErrItemNotFound
+		}
+		return nil, NewStorageError("get_item", "商品取得に失敗しました", err)
+	}
+	// アーカイブ済みの商品は入出庫できないため読み取り結果として返さない
+	if item.ArchivedAt != nil {
+		return nil, NewValidationError("code", "アーカイブ済みの商品のバーコードです", item.ID)
+	}
+	// 入数×包装数を保存単位に換算する前に、桁あふれしないよう数量の上限を確認
+	if barcode.UnitsPerPackage > 0 && result.PackageCount > maxQuantityUnits/barcode.UnitsPerPackage {
+		return nil, NewValidationError("quantity", "数量が有効範囲を超えています",
+			fmt.Sprintf("%d×%d", barcode.UnitsPerPackage, result.PackageCount))
+	}
+
+	result.Barcode = barcode
+	result.Item = item
+	result.PackageLevel = barcode.PackageLevel
+	result.Quantity = barcode.UnitsPerPackage * result.PackageCount * item.QuantityScale()
+
+	if result.LotNumber != "" {
+		lots, err := m.storage.GetLotsByItem(ctx, item.ID)
+		if err != nil {
+			return nil, NewStorageError("get_lots_by_item", "ロット取得に失敗しました", err)
+		}
+		for i := range lots {
+			if lots[i].Number == result.LotNumber {
+				result.Lot = &lots[i]
+				break
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// ===== LocationManager実装 =====
+
+// CreateLocation creates a new location
+// 新しいロケーションを作成
+func (m *Manager) CreateLocation(ctx context.Context, location *Location) error {
+	if err := ValidateLocation(location); err != nil {
+		return err
+	}
+	return m.storage.CreateLocation(ctx, location)
+}
+
+// GetLocation gets a location by ID
+// IDでロケーションを取得
+func (m *Manager) GetLocation(ctx context.Context, locationID string) (*Location, error) {
+	return m.storage.GetLocation(ctx, locationID)
+}
+
+// UpdateLocation updates an existing location
+// 既存のロケーションを更新
+func (m *Manager) UpdateLocation(ctx context.Context, location *Location) error {
+	if err := ValidateLocation(location); err != nil {
+		return err
+	}
+	return m.storage.UpdateLocation(ctx, location)
+}
+
+// DeleteLocation archives a location that no longer has stock, reservations or open documents
+// 在庫・引当・未完了の伝票がないロケーションをアーカイブ（論理削除）
+//
+// 台帳の履歴を保持するためロケーションの行は削除せず、アーカイブ日時を設定します。
+// 輸送中の仮想ロケーションはアーカイブできません。既にアーカイブ済みの場合は何もしません。
+func (m *Manager) DeleteLocation(ctx context.Context, locationID string) error {
+	location, err := m.storage.GetLocation(ctx, locationID)
+	if err != nil {
+		if err == ErrLocationNotFound {
+			return ErrLocationNotFound
+		}
+		return NewStorageError("get_location", "ロケーション取得に失敗しました", err)
+	}
+	if location.ArchivedAt != nil {
+		return nil
+	}
+	if locationID == m.inTransitLocation() {
+		return NewBusinessRuleError("in_transit_location", "輸送中の仮想ロケーションは削除できません",
+			fmt.Sprintf("ロケーションID: %s", locationID))
+	}
+
+	usage, err := m.storage.GetLocationUsage(ctx, locationID)
+	if err != nil {
+		return NewStorageError("get_location_usage", "ロケーションの使用状況の取得に失敗しました", err)
+	}
+	if blockers := usage.Blockers(); len(blockers) > 0 {
+		return NewBusinessRuleError("location_in_use", "在庫・引当または未完了の伝票があるロケーションは削除できません",
+			fmt.Sprintf("ロケーションID: %s, %s", locationID, strings.Join(blockers, ", ")))
+	}
+
+	now := time.Now()
+	if err := m.storage.SetLocationArchivedAt(ctx, locationID, &now); err != nil {
+		if err == ErrLocationNotFound {
+			return ErrLocationNotFound
+		}
+		return NewStorageError("set_location_archived_at", "ロケーションのアーカイブに失敗しました", err)
+	}
+
+	m.logger.Info("ロケーションアーカイブ完了",
+		zap.String("location_id", locationID),
+		zap.String("user", m.getUserFromContext(ctx)),
+	)
+
+	return nil
+}
+
+// ListLocations lists locations with pagination, excluding archived ones unless requested
+// ページネーション付きでロケーション一覧を取得（指定がない限りアーカイブ済みは除外）
+func (m *Manager) ListLocations(ctx context.Context, offset, limit int, includeArchived bool) ([]Location, error) {
+	return m.storage.ListLocations(ctx, offset, limit, includeArchived)
+}
+
+// ===== MasterArchiveManager実装 =====
+
+// GetItemUsage reports the stock, reservations and open documents that block archiving an item
+// 商品のアーカイブを妨げる在庫・引当・未完了の伝票の件数を取得
+func (m *Manager) GetItemUsage(ctx context.Context, itemID string) (*MasterUsage, error) {
+	if _, err := m.storage.GetItem(ctx, itemID); err != nil {
+		if err == ErrItemNotFound {
+			return nil, ErrItemNotFound
+		}
+		return nil, NewStorageError("get_item", "商品取得に失敗しました", err)
+	}
+
+	usage, err := m.storage.GetItemUsage(ctx, itemID)
+	if err != nil {
+		return nil, NewStorageError("get_item_usage", "商品の使用状況の取得に失敗しました", err)
+	}
+	return usage, nil
+}
+
+// GetLocationUsage reports the stock, reservations and open documents that block archiving a location
+// ロケーションのアーカイブを妨げる在庫・引当・未完了の伝票の件数を取得
+func (m *Manager) GetLocationUsage(ctx context.Context, locationID string) (*MasterUsage, error) {
+	if _, err := m.storage.GetLocation(ctx, locationID); err != nil {
+		if err == ErrLocationNotFound {
+			return nil, ErrLocationNotFound
+		}
+		return nil, NewStorageError("get_location", "ロケーション取得に失敗しました", err)
+	}
+
+	usage, err := m.storage.GetLocationUsage(ctx, locationID)
+	if err != nil {
+		return nil, NewStorageError("get_location_usage", "ロケーションの使用状況の取得に失敗しました", err)
+	}
+	return usage, nil
+}
+
+// RestoreItem clears the archive mark of an item so it can be used again
+// 商品のアーカイブを解除し再び使用できるようにする
+//
+// バリアントは親商品がアーカイブ済みの場合や、アーカイブ中に同じバリアント軸の値を持つ子SKUが作成された場合は
+// 解除できません。アーカイブされていない場合は何もしません。
+func (m *Manager) RestoreItem(ctx context.Context, itemID string) error {
+	item, err := m.storage.GetItem(ctx, itemID)
+	if err != nil {
+		if err == ErrItemNotFound {
+			return ErrItemNotFound
+		}
+		return NewStorageError("get_item", "商品取得に失敗しました", err)
+	}
+	if item.ArchivedAt == nil {
+		return nil
+	}
+	if item.ParentItemID != nil {
+		parent, err := m.storage.GetItem(ctx, *item.ParentItemID)
+		if err != nil {
+			return NewStorageError("get_item", "商品取得に失敗しました", err)
+		}
+		if parent.ArchivedAt != nil {
+			return NewBusinessRuleError("parent_item_archived", "親商品がアーカイブ済みのバリアントは復元できません",
+				fmt.Sprintf("商品ID: %s, 親商品ID: %s", itemID, parent.ID))
+		}
+		// アーカイブ済みの子SKUは重複確認の対象外のため、復元時に現在のバリアントグループで再検証
+		if err := m.validateVariant(ctx, item); err != nil {
+			return err
+		}
+	}
+
+	if err := m.storage.SetItemArchivedAt(ctx, itemID, nil); err != nil {
+		if err == ErrItemNotFound {
+			return ErrItemNotFound
+		}
+		return NewStorageError("set_item_archived_at", "商品のアーカイブ解除に失敗しました", err)
+	}
+
+	m.logger.Info("商品アーカイブ解除完了",
+		zap.String("item_id", itemID),
+		zap.String("user", m.getUserFromContext(ctx)),
+	)
+
+	return nil
+}
+
+// RestoreLocation clears the archive mark of a location so it can be used again
+// ロケーションのアーカイブを解除し再び使用できるようにする
+func (m *Manager) RestoreLocation(ctx context.Context, locationID string) error {
+	location, err := m.storage.GetLocation(ctx, locationID)
+	if err != nil {
+		if err == ErrLocationNotFound {
+			return ErrLocationNotFound
+		}
+		return NewStorageError("get_location", "ロケーション取得に失敗しました", err)
+	}
+	if location.ArchivedAt == nil {
+		return nil
+	}
+
+	if err := m.storage.SetLocationArchivedAt(ctx, locationID, nil); err != nil {
+		if err == ErrLocationNotFound {
+			return ErrLocationNotFound
+		}
+		return NewStorageError("set_location_archived_at", "ロケーションのアーカイブ解除に失敗しました", err)
+	}
+
+	m.logger.Info("ロケーションアーカイブ解除完了",
+		zap.String("location_id", locationID),
+		zap.String("user", m.getUserFromContext(ctx)),
+	)
+
+	return nil
 }
 
 // ===== LotManager実装 =====
@@ -1,7 +1,12 @@
 package inventory
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
+	"encoding/json"
+	"io"
+	"strings"
 	"testing"
 	"time"
 
@@ -74,6 +79,89 @@ func (m *MockStorage) UpdateItem(ctx context.Context, item *Item) error {
 	return args.Error(0)
 }
 
+func (m *MockStorage) ListItems(ctx context.Context, filter ItemSearchFilter) (*ItemPage, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ItemPage), args.Error(1)
+}
+
+func (m *MockStorage) SearchItems(ctx context.Context, filter ItemSearchFilter) (*ItemPage, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ItemPage), args.Error(1)
+}
+
+func (m *MockStorage) GetItemUsage(ctx context.Context, itemID string) (*MasterUsage, error) {
+	args := m.Called(ctx, itemID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*MasterUsage), args.Error(1)
+}
+
+func (m *MockStorage) SetItemArchivedAt(ctx context.Context, itemID string, archivedAt *time.Time) error {
+	args := m.Called(ctx, itemID, archivedAt)
+	return args.Error(0)
+}
+
+func (m *MockStorage) SaveItemBarcode(ctx context.Context, barcode *ItemBarcode) error {
+	args := m.Called(ctx, barcode)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetItemBarcode(ctx context.Context, gtin string) (*ItemBarcode, error) {
+	args := m.Called(ctx, gtin)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ItemBarcode), args.Error(1)
+}
+
+func (m *MockStorage) ListItemBarcodes(ctx context.Context, itemID string) ([]ItemBarcode, error) {
+	args := m.Called(ctx, itemID)
+	return args.Get(0).([]ItemBarcode), args.Error(1)
+}
+
+func (m *MockStorage) DeleteItemBarcode(ctx context.Context, itemID, gtin string) error {
+	args := m.Called(ctx, itemID, gtin)
+	return args.Error(0)
+}
+
+func (m *MockStorage) SaveCategoryAttributeSchema(ctx context.Context, schema *CategoryAttributeSchema) error {
+	args := m.Called(ctx, schema)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetCategoryAttributeSchema(ctx context.Context, category string) (*CategoryAttributeSchema, error) {
+	args := m.Called(ctx, category)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*CategoryAttributeSchema), args.Error(1)
+}
+
+func (m *MockStorage) ListCategoryAttributeSchemas(ctx context.Context) ([]CategoryAttributeSchema, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]CategoryAttributeSchema), args.Error(1)
+}
+
+func (m *MockStorage) SaveVariantGroup(ctx context.Context, group *VariantGroup) error {
+	args := m.Called(ctx, group)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetVariantGroup(ctx context.Context, parentItemID string) (*VariantGroup, error) {
+	args := m.Called(ctx, parentItemID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*VariantGroup), args.Error(1)
+}
+
 func (m *MockStorage) CreateLocation(ctx context.Context, location *Location) error {
 	args := m.Called(ctx, location)
 	return args.Error(0)
@@ -145,260 +233,3822 @@ func (m *MockStorage) GetTransactionHistoryByDateRange(ctx context.Context, item
 	return args.Get(0).([]Transaction), args.Error(1)
 }
 
-// TestManager_Add は在庫追加機能のテスト
-func TestManager_Add(t *testing.T) {
-	mockStorage := new(MockStorage)
-	logger := zap.NewNop()
-	config := &Config{
-		AllowNegativeStock: false,
-		DefaultLocation:    "DEFAULT",
-		AuditEnabled:       true,
-		LowStockThreshold:  10,
-	}
+func (m *MockStorage) CreateStockSnapshots(ctx context.Context, at time.Time) (int64, error) {
+	args := m.Called(ctx, at)
+	return args.Get(0).(int64), args.Error(1)
+}
 
-	manager := NewManager(mockStorage, nil, logger, config)
-	ctx := context.Background()
+func (m *MockStorage) GetStockAsOf(ctx context.Context, itemID, locationID string, at time.Time) (int64, error) {
+	args := m.Called(ctx, itemID, locationID, at)
+	return args.Get(0).(int64), args.Error(1)
+}
 
-	// テスト用のサンプルデータ
-	item := &Item{
-		ID:       "TEST-ITEM",
-		Name:     "テスト商品",
-		UnitCost: 1000.0,
-	}
-	location := &Location{
-		ID:   "TEST-LOC",
-		Name: "テストロケーション",
+func (m *MockStorage) ListStockAsOfByLocation(ctx context.Context, locationID string, at time.Time) ([]StockSnapshot, error) {
+	args := m.Called(ctx, locationID, at)
+	return args.Get(0).([]StockSnapshot), args.Error(1)
+}
+
+func (m *MockStorage) GetLatestStockSnapshotAt(ctx context.Context, locationID string) (*time.Time, error) {
+	args := m.Called(ctx, locationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
 	}
+	return args.Get(0).(*time.Time), args.Error(1)
+}
 
-	// モックの期待値設定
-	mockStorage.On("GetItem", ctx, "TEST-ITEM").Return(item, nil)
-	mockStorage.On("GetLocation", ctx, "TEST-LOC").Return(location, nil)
-	mockStorage.On("GetStock", ctx, "TEST-ITEM", "TEST-LOC").Return(nil, ErrStockNotFound)
-	mockStorage.On("CreateStock", ctx, mock.AnythingOfType("*inventory.Stock")).Return(nil)
-	mockStorage.On("CreateTransaction", ctx, mock.AnythingOfType("*inventory.Transaction")).Return(nil)
+func (m *MockStorage) ListLedgerEntries(ctx context.Context, itemID, locationID string, from, to time.Time) ([]LedgerEntry, error) {
+	args := m.Called(ctx, itemID, locationID, from, to)
+	return args.Get(0).([]LedgerEntry), args.Error(1)
+}
 
-	// テスト実行
-	err := manager.Add(ctx, "TEST-ITEM", "TEST-LOC", 100, "TEST-REF")
+func (m *MockStorage) IterateStock(ctx context.Context, filter ReportFilter, fn func(Stock, Item) error) error {
+	args := m.Called(ctx, filter)
+	if rows, ok := args.Get(0).([]Stock); ok {
+		items := args.Get(1).(map[string]Item)
+		for _, stock := range rows {
+			if err := fn(stock, items[stock.ItemID]); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(2)
+}
 
-	// アサーション
-	assert.NoError(t, err)
-	mockStorage.AssertExpectations(t)
+func (m *MockStorage) IterateTransactions(ctx context.Context, filter ReportFilter, fn func(Transaction, Item) error) error {
+	args := m.Called(ctx, filter)
+	if rows, ok := args.Get(0).([]Transaction); ok {
+		items := args.Get(1).(map[string]Item)
+		for _, tx := range rows {
+			if err := fn(tx, items[tx.ItemID]); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(2)
 }
 
-// TestManager_Remove は在庫削除機能のテスト
-func TestManager_Remove(t *testing.T) {
-	mockStorage := new(MockStorage)
-	logger := zap.NewNop()
-	config := &Config{
-		AllowNegativeStock: false,
-		DefaultLocation:    "DEFAULT",
-		AuditEnabled:       true,
-		LowStockThreshold:  10,
+func (m *MockStorage) IterateStockMovementSummaries(ctx context.Context, filter ReportFilter, fn func(StockMovementSummary, Item) error) error {
+	args := m.Called(ctx, filter)
+	if rows, ok := args.Get(0).([]StockMovementSummary); ok {
+		items := args.Get(1).(map[string]Item)
+		for _, summary := range rows {
+			if err := fn(summary, items[summary.ItemID]); err != nil {
+				return err
+			}
+		}
 	}
+	return args.Error(2)
+}
 
-	manager := NewManager(mockStorage, nil, logger, config)
-	ctx := context.Background()
+func (m *MockStorage) ListStockPositions(ctx context.Context, locationID string) ([]StockPosition, error) {
+	args := m.Called(ctx, locationID)
+	return args.Get(0).([]StockPosition), args.Error(1)
+}
 
-	// テスト用のサンプルデータ
-	item := &Item{
-		ID:       "TEST-ITEM",
-		Name:     "テスト商品",
-		UnitCost: 1000.0,
-	}
-	location := &Location{
-		ID:   "TEST-LOC",
-		Name: "テストロケーション",
-	}
-	stock := &Stock{
-		ItemID:     "TEST-ITEM",
-		LocationID: "TEST-LOC",
-		Quantity:   100,
-		Reserved:   0,
-		Available:  100,
-		Version:    1,
+func (m *MockStorage) ListReceiptLayers(ctx context.Context, locationID string) ([]ReceiptLayer, error) {
+	args := m.Called(ctx, locationID)
+	return args.Get(0).([]ReceiptLayer), args.Error(1)
+}
+
+func (m *MockStorage) CreateReportSchedule(ctx context.Context, schedule *ReportSchedule) error {
+	args := m.Called(ctx, schedule)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetReportSchedule(ctx context.Context, scheduleID string) (*ReportSchedule, error) {
+	args := m.Called(ctx, scheduleID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
 	}
+	return args.Get(0).(*ReportSchedule), args.Error(1)
+}
 
-	// モックの期待値設定
-	mockStorage.On("GetItem", ctx, "TEST-ITEM").Return(item, nil)
-	mockStorage.On("GetLocation", ctx, "TEST-LOC").Return(location, nil)
-	mockStorage.On("GetStock", ctx, "TEST-ITEM", "TEST-LOC").Return(stock, nil)
-	mockStorage.On("UpdateStock", ctx, mock.AnythingOfType("*inventory.Stock")).Return(nil)
-	mockStorage.On("CreateTransaction", ctx, mock.AnythingOfType("*inventory.Transaction")).Return(nil)
+func (m *MockStorage) ListReportSchedules(ctx context.Context) ([]ReportSchedule, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]ReportSchedule), args.Error(1)
+}
 
-	// テスト実行
-	err := manager.Remove(ctx, "TEST-ITEM", "TEST-LOC", 50, "TEST-REF")
+func (m *MockStorage) UpdateReportSchedule(ctx context.Context, schedule *ReportSchedule) error {
+	args := m.Called(ctx, schedule)
+	return args.Error(0)
+}
 
-	// アサーション
-	assert.NoError(t, err)
-	mockStorage.AssertExpectations(t)
+func (m *MockStorage) DeleteReportSchedule(ctx context.Context, scheduleID string) error {
+	args := m.Called(ctx, scheduleID)
+	return args.Error(0)
 }
 
-// TestManager_InsufficientStock は在庫不足エラーのテスト
-func TestManager_InsufficientStock(t *testing.T) {
-	mockStorage := new(MockStorage)
-	logger := zap.NewNop()
-	config := &Config{
-		AllowNegativeStock: false,
-		DefaultLocation:    "DEFAULT",
-		AuditEnabled:       true,
-		LowStockThreshold:  10,
-	}
+func (m *MockStorage) ListDueReportSchedules(ctx context.Context, now time.Time) ([]ReportSchedule, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).([]ReportSchedule), args.Error(1)
+}
 
-	manager := NewManager(mockStorage, nil, logger, config)
-	ctx := context.Background()
+func (m *MockStorage) ClaimReportScheduleRun(ctx context.Context, scheduleID string, scheduledAt, nextRunAt time.Time) (bool, error) {
+	args := m.Called(ctx, scheduleID, scheduledAt, nextRunAt)
+	return args.Bool(0), args.Error(1)
+}
 
-	// テスト用のサンプルデータ
-	item := &Item{
-		ID:       "TEST-ITEM",
-		Name:     "テスト商品",
-		UnitCost: 1000.0,
-	}
-	location := &Location{
-		ID:   "TEST-LOC",
-		Name: "テストロケーション",
-	}
-	stock := &Stock{
-		ItemID:     "TEST-ITEM",
-		LocationID: "TEST-LOC",
-		Quantity:   10,
-		Reserved:   0,
-		Available:  10,
-		Version:    1,
-	}
+func (m *MockStorage) CreateReportArchive(ctx context.Context, archive *ReportArchive) error {
+	args := m.Called(ctx, archive)
+	return args.Error(0)
+}
 
-	// モックの期待値設定
-	mockStorage.On("GetItem", ctx, "TEST-ITEM").Return(item, nil)
-	mockStorage.On("GetLocation", ctx, "TEST-LOC").Return(location, nil)
-	mockStorage.On("GetStock", ctx, "TEST-ITEM", "TEST-LOC").Return(stock, nil)
+func (m *MockStorage) GetReportArchive(ctx context.Context, archiveID string) (*ReportArchive, error) {
+	args := m.Called(ctx, archiveID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ReportArchive), args.Error(1)
+}
 
-	// テスト実行 - 在庫数を超える削除を試行
-	err := manager.Remove(ctx, "TEST-ITEM", "TEST-LOC", 50, "TEST-REF")
+func (m *MockStorage) ListReportArchives(ctx context.Context, scheduleID string, reportType ReportType, offset, limit int) ([]ReportArchive, error) {
+	args := m.Called(ctx, scheduleID, reportType, offset, limit)
+	return args.Get(0).([]ReportArchive), args.Error(1)
+}
 
-	// アサーション - 在庫不足エラーになることを確認
-	assert.Equal(t, ErrInsufficientStock, err)
-	mockStorage.AssertExpectations(t)
+func (m *MockStorage) ListStockDrifts(ctx context.Context) ([]StockDrift, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]StockDrift), args.Error(1)
 }
 
-// TestManager_Reserve は在庫予約機能のテスト
-func TestManager_Reserve(t *testing.T) {
-	mockStorage := new(MockStorage)
-	logger := zap.NewNop()
-	config := &Config{
-		AllowNegativeStock: false,
-		DefaultLocation:    "DEFAULT",
-		AuditEnabled:       true,
-		LowStockThreshold:  10,
+func (m *MockStorage) GetTransaction(ctx context.Context, transactionID string) (*Transaction, error) {
+	args := m.Called(ctx, transactionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
 	}
+	return args.Get(0).(*Transaction), args.Error(1)
+}
 
-	manager := NewManager(mockStorage, nil, logger, config)
-	ctx := context.Background()
+func (m *MockStorage) CreateBatchOperation(ctx context.Context, batch *BatchOperation) error {
+	args := m.Called(ctx, batch)
+	return args.Error(0)
+}
 
-	// テスト用のサンプルデータ
-	stock := &Stock{
-		ItemID:     "TEST-ITEM",
-		LocationID: "TEST-LOC",
-		Quantity:   100,
-		Reserved:   0,
-		Available:  100,
-		Version:    1,
+func (m *MockStorage) GetBatchOperation(ctx context.Context, batchID string) (*BatchOperation, error) {
+	args := m.Called(ctx, batchID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
 	}
+	return args.Get(0).(*BatchOperation), args.Error(1)
+}
 
-	// モックの期待値設定
-	mockStorage.On("GetStock", ctx, "TEST-ITEM", "TEST-LOC").Return(stock, nil)
-	mockStorage.On("UpdateStock", ctx, mock.AnythingOfType("*inventory.Stock")).Return(nil)
+func (m *MockStorage) ApplyReversal(ctx context.Context, transactionID, reversalID string, stocks []*Stock, transactions []*Transaction) error {
+	args := m.Called(ctx, transactionID, reversalID, stocks, transactions)
+	return args.Error(0)
+}
 
-	// テスト実行
-	err := manager.Reserve(ctx, "TEST-ITEM", "TEST-LOC", 30, "TEST-RESERVE")
+func (m *MockStorage) IsPeriodClosed(ctx context.Context, at time.Time) (bool, error) {
+	args := m.Called(ctx, at)
+	return args.Bool(0), args.Error(1)
+}
 
-	// アサーション
-	assert.NoError(t, err)
-	mockStorage.AssertExpectations(t)
+func (m *MockStorage) CreateFiscalPeriod(ctx context.Context, period *FiscalPeriod) error {
+	args := m.Called(ctx, period)
+	return args.Error(0)
 }
 
-// TestManager_BatchOperation はバッチ操作のテスト
-func TestManager_BatchOperation(t *testing.T) {
-	mockStorage := new(MockStorage)
-	logger := zap.NewNop()
-	config := &Config{
-		AllowNegativeStock: false,
-		DefaultLocation:    "DEFAULT",
-		AuditEnabled:       true,
-		LowStockThreshold:  10,
+func (m *MockStorage) GetFiscalPeriod(ctx context.Context, periodID string) (*FiscalPeriod, error) {
+	args := m.Called(ctx, periodID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
 	}
+	return args.Get(0).(*FiscalPeriod), args.Error(1)
+}
 
-	manager := NewManager(mockStorage, nil, logger, config)
-	ctx := context.Background()
+func (m *MockStorage) ListFiscalPeriods(ctx context.Context) ([]FiscalPeriod, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]FiscalPeriod), args.Error(1)
+}
 
-	// テスト用のサンプルデータ
+func (m *MockStorage) CloseFiscalPeriod(ctx context.Context, period *FiscalPeriod, event *FiscalPeriodEvent) (int64, error) {
+	args := m.Called(ctx, period, event)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockStorage) ReopenFiscalPeriod(ctx context.Context, periodID string, event *FiscalPeriodEvent) error {
+	args := m.Called(ctx, periodID, event)
+	return args.Error(0)
+}
+
+func (m *MockStorage) ListPeriodEndBalances(ctx context.Context, periodID, locationID string, offset, limit int) ([]PeriodEndBalance, error) {
+	args := m.Called(ctx, periodID, locationID, offset, limit)
+	return args.Get(0).([]PeriodEndBalance), args.Error(1)
+}
+
+func (m *MockStorage) ListFiscalPeriodEvents(ctx context.Context, periodID string) ([]FiscalPeriodEvent, error) {
+	args := m.Called(ctx, periodID)
+	return args.Get(0).([]FiscalPeriodEvent), args.Error(1)
+}
+
+func (m *MockStorage) SaveExchangeRate(ctx context.Context, rate *ExchangeRate) error {
+	args := m.Called(ctx, rate)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetExchangeRate(ctx context.Context, currency, baseCurrency string, at time.Time) (*ExchangeRate, error) {
+	args := m.Called(ctx, currency, baseCurrency, at)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ExchangeRate), args.Error(1)
+}
+
+func (m *MockStorage) ListExchangeRates(ctx context.Context, currency, baseCurrency string) ([]ExchangeRate, error) {
+	args := m.Called(ctx, currency, baseCurrency)
+	return args.Get(0).([]ExchangeRate), args.Error(1)
+}
+
+func (m *MockStorage) CreateTransferOrder(ctx context.Context, order *TransferOrder) error {
+	args := m.Called(ctx, order)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetTransferOrder(ctx context.Context, orderID string) (*TransferOrder, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*TransferOrder), args.Error(1)
+}
+
+func (m *MockStorage) UpdateTransferOrder(ctx context.Context, order *TransferOrder) error {
+	args := m.Called(ctx, order)
+	return args.Error(0)
+}
+
+func (m *MockStorage) ListTransferOrders(ctx context.Context, status TransferOrderStatus, offset, limit int) ([]TransferOrder, error) {
+	args := m.Called(ctx, status, offset, limit)
+	return args.Get(0).([]TransferOrder), args.Error(1)
+}
+
+func (m *MockStorage) CreateSupplier(ctx context.Context, supplier *Supplier) error {
+	args := m.Called(ctx, supplier)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetSupplier(ctx context.Context, supplierID string) (*Supplier, error) {
+	args := m.Called(ctx, supplierID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Supplier), args.Error(1)
+}
+
+func (m *MockStorage) UpdateSupplier(ctx context.Context, supplier *Supplier) error {
+	args := m.Called(ctx, supplier)
+	return args.Error(0)
+}
+
+func (m *MockStorage) ListSuppliers(ctx context.Context, offset, limit int) ([]Supplier, error) {
+	args := m.Called(ctx, offset, limit)
+	return args.Get(0).([]Supplier), args.Error(1)
+}
+
+func (m *MockStorage) CreatePurchaseOrder(ctx context.Context, order *PurchaseOrder) error {
+	args := m.Called(ctx, order)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetPurchaseOrder(ctx context.Context, orderID string) (*PurchaseOrder, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*PurchaseOrder), args.Error(1)
+}
+
+func (m *MockStorage) UpdatePurchaseOrder(ctx context.Context, order *PurchaseOrder) error {
+	args := m.Called(ctx, order)
+	return args.Error(0)
+}
+
+func (m *MockStorage) ApplyPurchaseReceipt(ctx context.Context, order *PurchaseOrder, stocks []*Stock, transactions []*Transaction, lots []*Lot) error {
+	args := m.Called(ctx, order, stocks, transactions, lots)
+	return args.Error(0)
+}
+
+func (m *MockStorage) ListPurchaseOrders(ctx context.Context, status PurchaseOrderStatus, supplierID string, offset, limit int) ([]PurchaseOrder, error) {
+	args := m.Called(ctx, status, supplierID, offset, limit)
+	return args.Get(0).([]PurchaseOrder), args.Error(1)
+}
+
+func (m *MockStorage) GetOnOrderQuantity(ctx context.Context, itemID, locationID string) (int64, error) {
+	args := m.Called(ctx, itemID, locationID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockStorage) ListStockByItem(ctx context.Context, itemID string) ([]Stock, error) {
+	args := m.Called(ctx, itemID)
+	return args.Get(0).([]Stock), args.Error(1)
+}
+
+func (m *MockStorage) GetItemLeadTimeDays(ctx context.Context, itemID string) (int, error) {
+	args := m.Called(ctx, itemID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockStorage) CreateOutboundOrder(ctx context.Context, order *OutboundOrder) error {
+	args := m.Called(ctx, order)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetOutboundOrder(ctx context.Context, orderID string) (*OutboundOrder, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*OutboundOrder), args.Error(1)
+}
+
+func (m *MockStorage) UpdateOutboundOrder(ctx context.Context, order *OutboundOrder) error {
+	args := m.Called(ctx, order)
+	return args.Error(0)
+}
+
+func (m *MockStorage) ListOutboundOrders(ctx context.Context, status OutboundOrderStatus, offset, limit int) ([]OutboundOrder, error) {
+	args := m.Called(ctx, status, offset, limit)
+	return args.Get(0).([]OutboundOrder), args.Error(1)
+}
+
+func (m *MockStorage) GetOrderFillQuantities(ctx context.Context, itemID string, from, to time.Time) (int64, int64, error) {
+	args := m.Called(ctx, itemID, from, to)
+	return args.Get(0).(int64), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockStorage) CreateWave(ctx context.Context, wave *Wave) error {
+	args := m.Called(ctx, wave)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetWave(ctx context.Context, waveID string) (*Wave, error) {
+	args := m.Called(ctx, waveID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Wave), args.Error(1)
+}
+
+func (m *MockStorage) ApplyStockChanges(ctx context.Context, stocks []*Stock, transactions []*Transaction) error {
+	args := m.Called(ctx, stocks, transactions)
+	return args.Error(0)
+}
+
+func (m *MockStorage) SetBillOfMaterials(ctx context.Context, bom *BillOfMaterials) error {
+	args := m.Called(ctx, bom)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetBillOfMaterials(ctx context.Context, itemID string) (*BillOfMaterials, error) {
+	args := m.Called(ctx, itemID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*BillOfMaterials), args.Error(1)
+}
+
+func (m *MockStorage) ListLocations(ctx context.Context, offset, limit int, includeArchived bool) ([]Location, error) {
+	args := m.Called(ctx, offset, limit, includeArchived)
+	return args.Get(0).([]Location), args.Error(1)
+}
+
+func (m *MockStorage) GetLocationUsage(ctx context.Context, locationID string) (*MasterUsage, error) {
+	args := m.Called(ctx, locationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*MasterUsage), args.Error(1)
+}
+
+func (m *MockStorage) SetLocationArchivedAt(ctx context.Context, locationID string, archivedAt *time.Time) error {
+	args := m.Called(ctx, locationID, archivedAt)
+	return args.Error(0)
+}
+
+func (m *MockStorage) UpdateLocation(ctx context.Context, location *Location) error {
+	args := m.Called(ctx, location)
+	return args.Error(0)
+}
+
+func (m *MockStorage) CreateMasterImportJob(ctx context.Context, job *MasterImportJob) error {
+	args := m.Called(ctx, job)
+	return args.Error(0)
+}
+
+func (m *MockStorage) UpdateMasterImportJob(ctx context.Context, job *MasterImportJob) error {
+	args := m.Called(ctx, job)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetMasterImportJob(ctx context.Context, jobID string) (*MasterImportJob, error) {
+	args := m.Called(ctx, jobID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*MasterImportJob), args.Error(1)
+}
+
+func (m *MockStorage) ListMasterImportJobs(ctx context.Context, offset, limit int) ([]MasterImportJob, error) {
+	args := m.Called(ctx, offset, limit)
+	return args.Get(0).([]MasterImportJob), args.Error(1)
+}
+
+func (m *MockStorage) DeleteLot(ctx context.Context, lotID string) error {
+	args := m.Called(ctx, lotID)
+	return args.Error(0)
+}
+
+func (m *MockStorage) CreateOpeningBalanceLoad(ctx context.Context, load *OpeningBalanceLoad) error {
+	args := m.Called(ctx, load)
+	return args.Error(0)
+}
+
+func (m *MockStorage) UpdateOpeningBalanceLoad(ctx context.Context, load *OpeningBalanceLoad) error {
+	args := m.Called(ctx, load)
+	return args.Error(0)
+}
+
+func (m *MockStorage) ApplyOpeningBalanceLoad(ctx context.Context, load *OpeningBalanceLoad, stocks []*Stock, transactions []*Transaction, lots []*Lot) error {
+	args := m.Called(ctx, load, stocks, transactions, lots)
+	return args.Error(0)
+}
+
+func (m *MockStorage) ApplyOpeningBalanceRollback(ctx context.Context, load *OpeningBalanceLoad, stocks []*Stock, transactions []*Transaction, lotIDs []string) error {
+	args := m.Called(ctx, load, stocks, transactions, lotIDs)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetOpeningBalanceLoad(ctx context.Context, loadID string) (*OpeningBalanceLoad, error) {
+	args := m.Called(ctx, loadID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*OpeningBalanceLoad), args.Error(1)
+}
+
+func (m *MockStorage) ListOpeningBalanceLoads(ctx context.Context, offset, limit int) ([]OpeningBalanceLoad, error) {
+	args := m.Called(ctx, offset, limit)
+	return args.Get(0).([]OpeningBalanceLoad), args.Error(1)
+}
+
+func (m *MockStorage) UpsertReplenishmentPolicy(ctx context.Context, policy *ReplenishmentPolicy) error {
+	args := m.Called(ctx, policy)
+	return args.Error(0)
+}
+
+func (m *MockStorage) ListReplenishmentPolicies(ctx context.Context, locationID string) ([]ReplenishmentPolicy, error) {
+	args := m.Called(ctx, locationID)
+	return args.Get(0).([]ReplenishmentPolicy), args.Error(1)
+}
+
+func (m *MockStorage) DeleteReplenishmentPolicy(ctx context.Context, locationID, itemID string) error {
+	args := m.Called(ctx, locationID, itemID)
+	return args.Error(0)
+}
+
+func (m *MockStorage) CreateReplenishmentProposal(ctx context.Context, proposal *ReplenishmentProposal) error {
+	args := m.Called(ctx, proposal)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetReplenishmentProposal(ctx context.Context, proposalID string) (*ReplenishmentProposal, error) {
+	args := m.Called(ctx, proposalID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ReplenishmentProposal), args.Error(1)
+}
+
+func (m *MockStorage) UpdateReplenishmentProposal(ctx context.Context, proposal *ReplenishmentProposal, fromStatus ReplenishmentProposalStatus) error {
+	args := m.Called(ctx, proposal, fromStatus)
+	return args.Error(0)
+}
+
+func (m *MockStorage) ListReplenishmentProposals(ctx context.Context, locationID string, status ReplenishmentProposalStatus, offset, limit int) ([]ReplenishmentProposal, error) {
+	args := m.Called(ctx, locationID, status, offset, limit)
+	return args.Get(0).([]ReplenishmentProposal), args.Error(1)
+}
+
+func (m *MockStorage) ListOpenReplenishmentProposals(ctx context.Context) ([]ReplenishmentProposal, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]ReplenishmentProposal), args.Error(1)
+}
+
+func (m *MockStorage) CreateCycleCountTask(ctx context.Context, task *CycleCountTask) error {
+	args := m.Called(ctx, task)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetCycleCountTask(ctx context.Context, taskID string) (*CycleCountTask, error) {
+	args := m.Called(ctx, taskID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*CycleCountTask), args.Error(1)
+}
+
+func (m *MockStorage) UpdateCycleCountTask(ctx context.Context, task *CycleCountTask, fromStatus CycleCountStatus) error {
+	args := m.Called(ctx, task, fromStatus)
+	return args.Error(0)
+}
+
+func (m *MockStorage) ApplyCycleCountSettlement(ctx context.Context, task *CycleCountTask, fromStatus CycleCountStatus, stocks []*Stock, transactions []*Transaction) error {
+	args := m.Called(ctx, task, fromStatus, stocks, transactions)
+	return args.Error(0)
+}
+
+func (m *MockStorage) ListCycleCountTasks(ctx context.Context, locationID string, status CycleCountStatus, offset, limit int) ([]CycleCountTask, error) {
+	args := m.Called(ctx, locationID, status, offset, limit)
+	return args.Get(0).([]CycleCountTask), args.Error(1)
+}
+
+func (m *MockStorage) ListLatestCycleCountTasks(ctx context.Context, locationID string) ([]CycleCountTask, error) {
+	args := m.Called(ctx, locationID)
+	return args.Get(0).([]CycleCountTask), args.Error(1)
+}
+
+func (m *MockStorage) ListOutboundTransactions(ctx context.Context, locationID string, from, to time.Time) ([]Transaction, error) {
+	args := m.Called(ctx, locationID, from, to)
+	return args.Get(0).([]Transaction), args.Error(1)
+}
+
+func (m *MockStorage) SaveItemClassifications(ctx context.Context, classifications []ItemClassification) error {
+	args := m.Called(ctx, classifications)
+	return args.Error(0)
+}
+
+func (m *MockStorage) ListLatestItemClassifications(ctx context.Context, locationID string) ([]ItemClassification, error) {
+	args := m.Called(ctx, locationID)
+	return args.Get(0).([]ItemClassification), args.Error(1)
+}
+
+func (m *MockStorage) ListItemClassificationHistory(ctx context.Context, itemID, locationID string, limit int) ([]ItemClassification, error) {
+	args := m.Called(ctx, itemID, locationID, limit)
+	return args.Get(0).([]ItemClassification), args.Error(1)
+}
+
+// TestManager_Add は在庫追加機能のテスト
+func TestManager_Add(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{
+		AllowNegativeStock: false,
+		DefaultLocation:    "DEFAULT",
+		AuditEnabled:       true,
+		LowStockThreshold:  10,
+	}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	// テスト用のサンプルデータ
 	item := &Item{
 		ID:       "TEST-ITEM",
 		Name:     "テスト商品",
-		UnitCost: 1000.0,
+		UnitCost: NewMoney(1000),
+	}
+	location := &Location{
+		ID:   "TEST-LOC",
+		Name: "テストロケーション",
+	}
+
+	// モックの期待値設定
+	mockStorage.On("GetItem", ctx, "TEST-ITEM").Return(item, nil)
+	mockStorage.On("GetLocation", ctx, "TEST-LOC").Return(location, nil)
+	mockStorage.On("GetStock", ctx, "TEST-ITEM", "TEST-LOC").Return(nil, ErrStockNotFound)
+	mockStorage.On("CreateStock", ctx, mock.AnythingOfType("*inventory.Stock")).Return(nil)
+	mockStorage.On("CreateTransaction", ctx, mock.AnythingOfType("*inventory.Transaction")).Return(nil)
+
+	// テスト実行
+	err := manager.Add(ctx, "TEST-ITEM", "TEST-LOC", 100, "TEST-REF")
+
+	// アサーション
+	assert.NoError(t, err)
+	mockStorage.AssertExpectations(t)
+}
+
+// TestManager_Remove は在庫削除機能のテスト
+func TestManager_Remove(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{
+		AllowNegativeStock: false,
+		DefaultLocation:    "DEFAULT",
+		AuditEnabled:       true,
+		LowStockThreshold:  10,
+	}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	// テスト用のサンプルデータ
+	item := &Item{
+		ID:       "TEST-ITEM",
+		Name:     "テスト商品",
+		UnitCost: NewMoney(1000),
+	}
+	location := &Location{
+		ID:   "TEST-LOC",
+		Name: "テストロケーション",
+	}
+	stock := &Stock{
+		ItemID:     "TEST-ITEM",
+		LocationID: "TEST-LOC",
+		Quantity:   100,
+		Reserved:   0,
+		Available:  100,
+		Version:    1,
+	}
+
+	// モックの期待値設定
+	mockStorage.On("GetItem", ctx, "TEST-ITEM").Return(item, nil)
+	mockStorage.On("GetLocation", ctx, "TEST-LOC").Return(location, nil)
+	mockStorage.On("GetStock", ctx, "TEST-ITEM", "TEST-LOC").Return(stock, nil)
+	mockStorage.On("UpdateStock", ctx, mock.AnythingOfType("*inventory.Stock")).Return(nil)
+	mockStorage.On("CreateTransaction", ctx, mock.AnythingOfType("*inventory.Transaction")).Return(nil)
+
+	// テスト実行
+	err := manager.Remove(ctx, "TEST-ITEM", "TEST-LOC", 50, "TEST-REF")
+
+	// アサーション
+	assert.NoError(t, err)
+	mockStorage.AssertExpectations(t)
+}
+
+// TestManager_InsufficientStock は在庫不足エラーのテスト
+func TestManager_InsufficientStock(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{
+		AllowNegativeStock: false,
+		DefaultLocation:    "DEFAULT",
+		AuditEnabled:       true,
+		LowStockThreshold:  10,
+	}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	// テスト用のサンプルデータ
+	item := &Item{
+		ID:       "TEST-ITEM",
+		Name:     "テスト商品",
+		UnitCost: NewMoney(1000),
+	}
+	location := &Location{
+		ID:   "TEST-LOC",
+		Name: "テストロケーション",
+	}
+	stock := &Stock{
+		ItemID:     "TEST-ITEM",
+		LocationID: "TEST-LOC",
+		Quantity:   10,
+		Reserved:   0,
+		Available:  10,
+		Version:    1,
+	}
+
+	// モックの期待値設定
+	mockStorage.On("GetItem", ctx, "TEST-ITEM").Return(item, nil)
+	mockStorage.On("GetLocation", ctx, "TEST-LOC").Return(location, nil)
+	mockStorage.On("GetStock", ctx, "TEST-ITEM", "TEST-LOC").Return(stock, nil)
+
+	// テスト実行 - 在庫数を超える削除を試行
+	err := manager.Remove(ctx, "TEST-ITEM", "TEST-LOC", 50, "TEST-REF")
+
+	// アサーション - 在庫不足エラーになることを確認
+	assert.Equal(t, ErrInsufficientStock, err)
+	mockStorage.AssertExpectations(t)
+}
+
+// TestManager_Reserve は在庫予約機能のテスト
+func TestManager_Reserve(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{
+		AllowNegativeStock: false,
+		DefaultLocation:    "DEFAULT",
+		AuditEnabled:       true,
+		LowStockThreshold:  10,
+	}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	// テスト用のサンプルデータ
+	stock := &Stock{
+		ItemID:     "TEST-ITEM",
+		LocationID: "TEST-LOC",
+		Quantity:   100,
+		Reserved:   0,
+		Available:  100,
+		Version:    1,
+	}
+
+	// モックの期待値設定
+	mockStorage.On("GetStock", ctx, "TEST-ITEM", "TEST-LOC").Return(stock, nil)
+	mockStorage.On("UpdateStock", ctx, mock.AnythingOfType("*inventory.Stock")).Return(nil)
+
+	// テスト実行
+	err := manager.Reserve(ctx, "TEST-ITEM", "TEST-LOC", 30, "TEST-RESERVE")
+
+	// アサーション
+	assert.NoError(t, err)
+	mockStorage.AssertExpectations(t)
+}
+
+// TestManager_BatchOperation はバッチ操作のテスト
+func TestManager_BatchOperation(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{
+		AllowNegativeStock: false,
+		DefaultLocation:    "DEFAULT",
+		AuditEnabled:       true,
+		LowStockThreshold:  10,
+	}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	// テスト用のサンプルデータ
+	item := &Item{
+		ID:       "TEST-ITEM",
+		Name:     "テスト商品",
+		UnitCost: NewMoney(1000),
+	}
+	location := &Location{
+		ID:   "TEST-LOC",
+		Name: "テストロケーション",
+	}
+
+	// バッチ操作
+	operations := []InventoryOperation{
+		{
+			Type:       OperationTypeAdd,
+			ItemID:     "TEST-ITEM",
+			LocationID: "TEST-LOC",
+			Quantity:   100,
+			Reference:  "BATCH-001",
+		},
+	}
+
+	// モックの期待値設定
+	mockStorage.On("GetItem", ctx, "TEST-ITEM").Return(item, nil)
+	mockStorage.On("GetLocation", ctx, "TEST-LOC").Return(location, nil)
+	mockStorage.On("GetStock", ctx, "TEST-ITEM", "TEST-LOC").Return(nil, ErrStockNotFound)
+	mockStorage.On("CreateStock", ctx, mock.AnythingOfType("*inventory.Stock")).Return(nil)
+	mockStorage.On("CreateTransaction", ctx, mock.AnythingOfType("*inventory.Transaction")).Return(nil)
+	var saved *BatchOperation
+	mockStorage.On("CreateBatchOperation", ctx, mock.AnythingOfType("*inventory.BatchOperation")).Run(func(args mock.Arguments) {
+		saved = args.Get(1).(*BatchOperation)
+	}).Return(nil)
+
+	// テスト実行
+	batch, err := manager.ExecuteBatch(ctx, operations)
+
+	// アサーション
+	assert.NoError(t, err)
+	assert.NotNil(t, batch)
+	assert.Equal(t, 1, batch.SuccessCount)
+	assert.Equal(t, 0, batch.FailureCount)
+
+	// 保存した実行結果をバッチIDで取得できる
+	assert.Same(t, batch, saved)
+	mockStorage.On("GetBatchOperation", ctx, batch.ID).Return(saved, nil)
+	mockStorage.On("GetBatchOperation", ctx, "UNKNOWN").Return(nil, ErrBatchNotFound)
+	status, err := manager.GetBatchStatus(ctx, batch.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, BatchStatusCompleted, status.Status)
+	_, err = manager.GetBatchStatus(ctx, "UNKNOWN")
+	assert.Equal(t, ErrBatchNotFound, err)
+	mockStorage.AssertExpectations(t)
+}
+
+// TestManager_GetTotalStock は総在庫取得のテスト
+func TestManager_GetTotalStock(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	// テスト用のサンプルデータ
+	item := &Item{
+		ID:       "TEST-ITEM",
+		Name:     "テスト商品",
+		UnitCost: NewMoney(1000),
+	}
+
+	// モックの期待値設定（輸送中ロケーション分を含む全ロケーション合計）
+	mockStorage.On("GetItem", ctx, "TEST-ITEM").Return(item, nil)
+	mockStorage.On("GetTotalStockByItem", ctx, "TEST-ITEM").Return(int64(150), nil)
+
+	// テスト実行
+	totalStock, err := manager.GetTotalStock(ctx, "TEST-ITEM")
+
+	// アサーション
+	assert.NoError(t, err)
+	assert.Equal(t, int64(150), totalStock)
+	mockStorage.AssertExpectations(t)
+}
+
+// TestManager_GetHistoryByDateRange は日付範囲履歴取得のテスト
+func TestManager_GetHistoryByDateRange(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	// テスト用のサンプルデータ
+	item := &Item{
+		ID:       "TEST-ITEM",
+		Name:     "テスト商品",
+		UnitCost: NewMoney(1000),
+	}
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 12, 31, 23, 59, 59, 0, time.UTC)
+
+	transactions := []Transaction{
+		{
+			ID:        "TX-001",
+			Type:      TransactionTypeInbound,
+			ItemID:    "TEST-ITEM",
+			Quantity:  100,
+			CreatedAt: time.Date(2024, 6, 15, 10, 0, 0, 0, time.UTC),
+		},
+	}
+
+	// モックの期待値設定
+	mockStorage.On("GetItem", ctx, "TEST-ITEM").Return(item, nil)
+	mockStorage.On("GetTransactionHistoryByDateRange", ctx, "TEST-ITEM", from, to).Return(transactions, nil)
+
+	// テスト実行
+	result, err := manager.GetHistoryByDateRange(ctx, "TEST-ITEM", from, to)
+
+	// アサーション
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "TX-001", result[0].ID)
+	mockStorage.AssertExpectations(t)
+}
+
+// TestManager_GetStockAsOf は時点在庫取得のテスト
+func TestManager_GetStockAsOf(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	// テスト用のサンプルデータ
+	item := &Item{
+		ID:   "TEST-ITEM",
+		Name: "テスト商品",
+	}
+	location := &Location{
+		ID:   "TEST-LOC",
+		Name: "テストロケーション",
+	}
+	asOf := time.Date(2024, 3, 31, 23, 59, 59, 0, time.UTC)
+
+	// モックの期待値設定
+	mockStorage.On("GetItem", ctx, "TEST-ITEM").Return(item, nil)
+	mockStorage.On("GetLocation", ctx, "TEST-LOC").Return(location, nil)
+	mockStorage.On("GetStockAsOf", ctx, "TEST-ITEM", "TEST-LOC", asOf).Return(int64(42), nil)
+
+	// テスト実行
+	snapshot, err := manager.GetStockAsOf(ctx, "TEST-ITEM", "TEST-LOC", asOf)
+
+	// アサーション
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), snapshot.Quantity)
+	assert.Equal(t, asOf, snapshot.AsOf)
+	mockStorage.AssertExpectations(t)
+}
+
+// TestManager_GetStockByLocationAsOf_LocationNotFound は存在しないロケーションの時点在庫取得のテスト
+func TestManager_GetStockByLocationAsOf_LocationNotFound(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	// モックの期待値設定
+	mockStorage.On("GetLocation", ctx, "NO-LOC").Return(nil, ErrLocationNotFound)
+
+	// テスト実行
+	_, err := manager.GetStockByLocationAsOf(ctx, "NO-LOC", time.Now())
+
+	// アサーション
+	assert.Equal(t, ErrLocationNotFound, err)
+	mockStorage.AssertNotCalled(t, "ListStockAsOfByLocation", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestManager_ReconcileLedger は台帳照合（レポートのみ）のテスト
+func TestManager_ReconcileLedger(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	drifts := []StockDrift{
+		{ItemID: "ITEM-1", LocationID: "LOC-1", StockQuantity: 100, LedgerQuantity: 90, Difference: 10},
+	}
+
+	// モックの期待値設定
+	mockStorage.On("ListStockDrifts", ctx).Return(drifts, nil)
+
+	// テスト実行
+	report, err := manager.ReconcileLedger(ctx, false)
+
+	// アサーション
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.DriftCount)
+	assert.Equal(t, 0, report.FixedCount)
+	assert.False(t, report.Drifts[0].Fixed)
+	mockStorage.AssertNotCalled(t, "CreateTransaction", mock.Anything, mock.Anything)
+}
+
+// TestManager_ReconcileLedger_Fix は台帳照合の補正トランザクション記録のテスト
+func TestManager_ReconcileLedger_Fix(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	drifts := []StockDrift{
+		{ItemID: "ITEM-1", LocationID: "LOC-1", StockQuantity: 100, LedgerQuantity: 90, Difference: 10, StockVersion: 3},
+		{ItemID: "ITEM-2", LocationID: "LOC-1", StockQuantity: 0, LedgerQuantity: 5, Difference: -5},
+	}
+
+	// モックの期待値設定
+	mockStorage.On("ListStockDrifts", ctx).Return(drifts, nil)
+	mockStorage.On("GetStock", ctx, "ITEM-1", "LOC-1").Return(&Stock{ItemID: "ITEM-1", LocationID: "LOC-1", Quantity: 100, Version: 3}, nil)
+	mockStorage.On("GetStock", ctx, "ITEM-2", "LOC-1").Return(nil, ErrStockNotFound)
+	mockStorage.On("ApplyStockChanges", ctx, mock.MatchedBy(func(stocks []*Stock) bool {
+		return len(stocks) == 1 && stocks[0].ItemID == "ITEM-1" && stocks[0].Quantity == 100 && stocks[0].Version == 4
+	}), mock.MatchedBy(func(txs []*Transaction) bool {
+		return len(txs) == 1 && txs[0].Type == TransactionTypeAdjust && txs[0].ItemID == "ITEM-1" && *txs[0].ToLocation == "LOC-1" && txs[0].Quantity == 10
+	})).Return(nil).Once()
+	mockStorage.On("ApplyStockChanges", ctx, mock.MatchedBy(func(stocks []*Stock) bool {
+		return len(stocks) == 1 && stocks[0].ItemID == "ITEM-2" && stocks[0].Quantity == 0 && stocks[0].Version == 1
+	}), mock.MatchedBy(func(txs []*Transaction) bool {
+		return len(txs) == 1 && txs[0].Type == TransactionTypeAdjust && txs[0].ItemID == "ITEM-2" && *txs[0].ToLocation == "LOC-1" && txs[0].Quantity == -5
+	})).Return(nil).Once()
+
+	// テスト実行
+	report, err := manager.ReconcileLedger(ctx, true)
+
+	// アサーション
+	assert.NoError(t, err)
+	assert.Equal(t, 2, report.FixedCount)
+	assert.True(t, report.Drifts[0].Fixed)
+	assert.True(t, report.Drifts[1].Fixed)
+	assert.Contains(t, report.Reference, ReconciliationReferencePrefix)
+	mockStorage.AssertExpectations(t)
+}
+
+// TestManager_ReconcileLedger_FixConflict は照合後に在庫が更新された商品の補正スキップのテスト
+func TestManager_ReconcileLedger_FixConflict(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	drifts := []StockDrift{
+		{ItemID: "ITEM-1", LocationID: "LOC-1", StockQuantity: 100, LedgerQuantity: 90, Difference: 10, StockVersion: 3},
+		{ItemID: "ITEM-2", LocationID: "LOC-1", StockQuantity: 20, LedgerQuantity: 15, Difference: 5, StockVersion: 1},
+	}
+
+	// ITEM-1は照合後に出庫されてバージョンが進み、ITEM-2は書き込み時に競合
+	mockStorage.On("ListStockDrifts", ctx).Return(drifts, nil)
+	mockStorage.On("GetStock", ctx, "ITEM-1", "LOC-1").Return(&Stock{ItemID: "ITEM-1", LocationID: "LOC-1", Quantity: 95, Version: 4}, nil)
+	mockStorage.On("GetStock", ctx, "ITEM-2", "LOC-1").Return(&Stock{ItemID: "ITEM-2", LocationID: "LOC-1", Quantity: 20, Version: 1}, nil)
+	mockStorage.On("ApplyStockChanges", ctx, mock.Anything, mock.Anything).Return(ErrVersionMismatch).Once()
+
+	// テスト実行
+	report, err := manager.ReconcileLedger(ctx, true)
+
+	// アサーション
+	assert.NoError(t, err)
+	assert.Equal(t, 2, report.DriftCount)
+	assert.Equal(t, 0, report.FixedCount)
+	assert.False(t, report.Drifts[0].Fixed)
+	assert.Equal(t, ErrVersionMismatch.Error(), report.Drifts[0].Error)
+	assert.False(t, report.Drifts[1].Fixed)
+	assert.Equal(t, ErrVersionMismatch.Error(), report.Drifts[1].Error)
+	mockStorage.AssertNumberOfCalls(t, "ApplyStockChanges", 1)
+	mockStorage.AssertNotCalled(t, "CreateTransaction", mock.Anything, mock.Anything)
+}
+
+// TestManager_ReverseTransaction_Inbound は入庫取消のテスト
+func TestManager_ReverseTransaction_Inbound(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	// テスト用のサンプルデータ
+	locationID := "TEST-LOC"
+	lotNumber := "LOT-001"
+	unitCost := NewMoney(250)
+	original := &Transaction{
+		ID:         "TX-1",
+		Type:       TransactionTypeInbound,
+		ItemID:     "TEST-ITEM",
+		ToLocation: &locationID,
+		Quantity:   30,
+		UnitCost:   &unitCost,
+		Reference:  "PO-001",
+		LotNumber:  &lotNumber,
+		CreatedAt:  time.Now(),
+	}
+	stock := &Stock{
+		ItemID:     "TEST-ITEM",
+		LocationID: "TEST-LOC",
+		Quantity:   100,
+		Available:  100,
+		Version:    1,
+	}
+
+	// モックの期待値設定
+	mockStorage.On("GetTransaction", ctx, "TX-1").Return(original, nil)
+	mockStorage.On("IsPeriodClosed", ctx, original.CreatedAt).Return(false, nil)
+	mockStorage.On("GetStock", ctx, "TEST-ITEM", "TEST-LOC").Return(stock, nil)
+	mockStorage.On("ApplyReversal", ctx, "TX-1", mock.AnythingOfType("string"),
+		mock.MatchedBy(func(stocks []*Stock) bool {
+			return len(stocks) == 1 && stocks[0].Quantity == 70 && stocks[0].Version == 2
+		}),
+		mock.MatchedBy(func(txs []*Transaction) bool {
+			tx := txs[0]
+			return len(txs) == 1 &&
+				tx.Type == TransactionTypeOutbound &&
+				*tx.FromLocation == "TEST-LOC" &&
+				tx.Quantity == 30 &&
+				*tx.LotNumber == "LOT-001" &&
+				*tx.UnitCost == NewMoney(250) &&
+				*tx.ReversalOf == "TX-1"
+		}),
+	).Return(nil)
+
+	// テスト実行
+	reversal, err := manager.ReverseTransaction(ctx, "TX-1", "", "誤入力")
+
+	// アサーション
+	assert.NoError(t, err)
+	assert.Equal(t, "REVERSAL-PO-001", reversal.Reference)
+	assert.Equal(t, reversal.ID, *original.ReversedBy)
+	mockStorage.AssertExpectations(t)
+}
+
+// TestManager_ReverseTransaction_Refused は二重取消・締め済み期間の取消拒否のテスト
+func TestManager_ReverseTransaction_Refused(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	// テスト用のサンプルデータ
+	locationID := "TEST-LOC"
+	reversalID := "TX-REV"
+	reversed := &Transaction{
+		ID:         "TX-1",
+		Type:       TransactionTypeInbound,
+		ItemID:     "TEST-ITEM",
+		ToLocation: &locationID,
+		Quantity:   30,
+		ReversedBy: &reversalID,
+	}
+	closedPeriod := &Transaction{
+		ID:         "TX-2",
+		Type:       TransactionTypeInbound,
+		ItemID:     "TEST-ITEM",
+		ToLocation: &locationID,
+		Quantity:   30,
+		CreatedAt:  time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+	}
+
+	// モックの期待値設定
+	mockStorage.On("GetTransaction", ctx, "TX-1").Return(reversed, nil)
+	mockStorage.On("GetTransaction", ctx, "TX-2").Return(closedPeriod, nil)
+	mockStorage.On("IsPeriodClosed", ctx, closedPeriod.CreatedAt).Return(true, nil)
+
+	// テスト実行とアサーション
+	_, err := manager.ReverseTransaction(ctx, "TX-1", "", "")
+	assert.Equal(t, ErrTransactionAlreadyReversed, err)
+
+	_, err = manager.ReverseTransaction(ctx, "TX-2", "", "")
+	assert.Equal(t, ErrPeriodClosed, err)
+
+	mockStorage.AssertNotCalled(t, "ApplyReversal", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestManager_ReverseTransaction_Transfer は移動の取消（レッグの紐付け・一括記録・レッグ単独取消の拒否）のテスト
+func TestManager_ReverseTransaction_Transfer(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	// テスト用のサンプルデータ
+	from, to := "WH-A", "WH-B"
+	createdAt := time.Now().Add(-time.Hour)
+	transfer := &Transaction{
+		ID:           "TX-T",
+		Type:         TransactionTypeTransfer,
+		ItemID:       "TEST-ITEM",
+		FromLocation: &from,
+		ToLocation:   &to,
+		Quantity:     20,
+		Reference:    "MOVE-1",
+		CreatedAt:    createdAt,
+	}
+	leg := &Transaction{
+		ID:           "TX-OUT",
+		Type:         TransactionTypeOutbound,
+		ItemID:       "TEST-ITEM",
+		FromLocation: &from,
+		Quantity:     20,
+		Metadata:     map[string]string{TransferMetadataKey: "TX-T"},
+		CreatedAt:    createdAt,
+	}
+
+	// モックの期待値設定
+	mockStorage.On("GetTransaction", ctx, "TX-T").Return(transfer, nil)
+	mockStorage.On("GetTransaction", ctx, "TX-OUT").Return(leg, nil)
+	mockStorage.On("IsPeriodClosed", ctx, createdAt).Return(false, nil)
+	toStock := &Stock{ItemID: "TEST-ITEM", LocationID: "WH-B", Quantity: 20, Available: 20, Version: 3}
+	mockStorage.On("GetStock", ctx, "TEST-ITEM", "WH-B").Return(toStock, nil)
+	mockStorage.On("GetStock", ctx, "TEST-ITEM", "WH-A").Return(nil, ErrStockNotFound)
+
+	var recorded []*Transaction
+	mockStorage.On("ApplyReversal", ctx, "TX-T", mock.AnythingOfType("string"),
+		mock.MatchedBy(func(stocks []*Stock) bool {
+			return len(stocks) == 2 &&
+				stocks[0].LocationID == "WH-B" && stocks[0].Quantity == 0 && stocks[0].Version == 4 &&
+				stocks[1].LocationID == "WH-A" && stocks[1].Quantity == 20 && stocks[1].Version == 1
+		}),
+		mock.AnythingOfType("[]*inventory.Transaction"),
+	).Run(func(args mock.Arguments) {
+		recorded = args.Get(4).([]*Transaction)
+	}).Return(nil).Once()
+
+	// テスト実行
+	reversal, err := manager.ReverseTransaction(ctx, "TX-T", "", "誤移動")
+
+	// アサーション
+	assert.NoError(t, err)
+	if assert.Len(t, recorded, 3) {
+		assert.Equal(t, reversal, recorded[0])
+		assert.Equal(t, "TX-T", *recorded[0].ReversalOf)
+		// 取消のレッグは取消トランザクションに紐付き、単独では取り消せない
+		for _, tx := range recorded[1:] {
+			assert.Equal(t, reversal.ID, tx.Metadata[TransferMetadataKey])
+		}
+		assert.Equal(t, TransactionTypeOutbound, recorded[1].Type)
+		assert.Equal(t, "WH-B", *recorded[1].FromLocation)
+		assert.Equal(t, TransactionTypeInbound, recorded[2].Type)
+		assert.Equal(t, "WH-A", *recorded[2].ToLocation)
+	}
+
+	// 移動のレッグ単独の取消は拒否
+	_, err = manager.ReverseTransaction(ctx, "TX-OUT", "", "")
+	assert.Equal(t, ErrTransferLegNotReversible, err)
+
+	// 同時に取り消された場合は記録全体が失敗し、エラーを返す
+	transfer.ReversedBy = nil
+	toStock.Quantity, toStock.Available, toStock.Version = 20, 20, 3
+	mockStorage.On("ApplyReversal", ctx, "TX-T", mock.Anything, mock.Anything, mock.Anything).Return(ErrTransactionAlreadyReversed).Once()
+	_, err = manager.ReverseTransaction(ctx, "TX-T", "", "")
+	assert.Equal(t, ErrTransactionAlreadyReversed, err)
+	mockStorage.AssertExpectations(t)
+}
+
+// TestManager_PostingDate は過去日付での計上と締め済み期間のロックのテスト
+func TestManager_PostingDate(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+
+	// テスト用のサンプルデータ
+	closedDate := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+	openDate := time.Date(2024, 2, 10, 9, 0, 0, 0, time.UTC)
+	snapshotAt := time.Date(2024, 2, 5, 0, 0, 0, 0, time.UTC)
+	beforeSnapshot := time.Date(2024, 2, 3, 9, 0, 0, 0, time.UTC)
+	item := &Item{ID: "TEST-ITEM", Name: "テスト商品"}
+	location := &Location{ID: "TEST-LOC", Name: "テストロケーション"}
+	stock := &Stock{ItemID: "TEST-ITEM", LocationID: "TEST-LOC", Quantity: 50, Available: 50, Version: 1}
+
+	// モックの期待値設定
+	mockStorage.On("IsPeriodClosed", mock.Anything, closedDate).Return(true, nil)
+	mockStorage.On("IsPeriodClosed", mock.Anything, openDate).Return(false, nil)
+	mockStorage.On("IsPeriodClosed", mock.Anything, beforeSnapshot).Return(false, nil)
+	mockStorage.On("GetLatestStockSnapshotAt", mock.Anything, "TEST-LOC").Return(&snapshotAt, nil)
+	mockStorage.On("GetItem", mock.Anything, "TEST-ITEM").Return(item, nil)
+	mockStorage.On("GetLocation", mock.Anything, "TEST-LOC").Return(location, nil)
+	mockStorage.On("GetStock", mock.Anything, "TEST-ITEM", "TEST-LOC").Return(stock, nil)
+	mockStorage.On("UpdateStock", mock.Anything, mock.AnythingOfType("*inventory.Stock")).Return(nil)
+	mockStorage.On("CreateTransaction", mock.Anything, mock.MatchedBy(func(tx *Transaction) bool {
+		return tx.Type == TransactionTypeAdjust && tx.Quantity == -10 && tx.CreatedAt.Equal(openDate)
+	})).Return(nil)
+
+	// 締め済み期間への調整は在庫を変更せずに拒否
+	err := manager.Adjust(WithPostingDate(context.Background(), closedDate), "TEST-ITEM", "TEST-LOC", 40, "ADJ-CLOSED")
+	assert.Equal(t, ErrPeriodClosed, err)
+	mockStorage.AssertNotCalled(t, "UpdateStock", mock.Anything, mock.Anything)
+
+	// 未来日付は計上不可
+	err = manager.Adjust(WithPostingDate(context.Background(), time.Now().Add(time.Hour)), "TEST-ITEM", "TEST-LOC", 40, "ADJ-FUTURE")
+	assert.IsType(t, &ValidationError{}, err)
+
+	// 在庫スナップショット以前の日付は時点在庫に反映されないため計上不可
+	err = manager.Adjust(WithPostingDate(context.Background(), beforeSnapshot), "TEST-ITEM", "TEST-LOC", 40, "ADJ-SNAPSHOT")
+	assert.Equal(t, ErrPostingBeforeSnapshot, err)
+	mockStorage.AssertNotCalled(t, "UpdateStock", mock.Anything, mock.Anything)
+
+	// 未締め期間への調整は計上日時で記録
+	err = manager.Adjust(WithPostingDate(context.Background(), openDate), "TEST-ITEM", "TEST-LOC", 40, "ADJ-OPEN")
+	assert.NoError(t, err)
+	mockStorage.AssertExpectations(t)
+}
+
+// TestManager_CloseFiscalPeriod は会計期間の締めのテスト
+func TestManager_CloseFiscalPeriod(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.WithValue(context.Background(), "user_id", "closer")
+
+	// テスト用のサンプルデータ
+	ended := &FiscalPeriod{
+		ID:        "FP-2024-01",
+		Name:      "2024年1月",
+		StartDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		Status:    FiscalPeriodStatusOpen,
+	}
+	today := truncateToDate(time.Now())
+	current := &FiscalPeriod{
+		ID:        "FP-CURRENT",
+		Name:      "当月",
+		StartDate: today.AddDate(0, 0, -1),
+		EndDate:   today,
+		Status:    FiscalPeriodStatusOpen,
+	}
+
+	// モックの期待値設定
+	mockStorage.On("GetFiscalPeriod", ctx, "FP-2024-01").Return(ended, nil)
+	mockStorage.On("GetFiscalPeriod", ctx, "FP-CURRENT").Return(current, nil)
+	mockStorage.On("CloseFiscalPeriod", ctx, ended, mock.MatchedBy(func(event *FiscalPeriodEvent) bool {
+		return event.PeriodID == "FP-2024-01" && event.Action == FiscalPeriodActionClose && event.PerformedBy == "closer"
+	})).Return(int64(42), nil)
+
+	// テスト実行
+	period, err := manager.CloseFiscalPeriod(ctx, "FP-2024-01")
+
+	// アサーション
+	assert.NoError(t, err)
+	assert.Equal(t, FiscalPeriodStatusClosed, period.Status)
+	assert.Equal(t, "closer", period.ClosedBy)
+	assert.NotNil(t, period.ClosedAt)
+	assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), period.PeriodEnd())
+
+	// 締め済み期間の再締めと終了日前の締めは不可
+	_, err = manager.CloseFiscalPeriod(ctx, "FP-2024-01")
+	assert.Equal(t, ErrInvalidFiscalPeriodStatus, err)
+	_, err = manager.CloseFiscalPeriod(ctx, "FP-CURRENT")
+	assert.IsType(t, &BusinessRuleError{}, err)
+	mockStorage.AssertNumberOfCalls(t, "CloseFiscalPeriod", 1)
+}
+
+// TestManager_ReopenFiscalPeriod は会計期間の締め解除と監査記録のテスト
+func TestManager_ReopenFiscalPeriod(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.WithValue(context.Background(), "user_id", "admin")
+
+	// テスト用のサンプルデータ
+	closedAt := time.Date(2024, 2, 3, 10, 0, 0, 0, time.UTC)
+	closed := &FiscalPeriod{
+		ID:       "FP-2024-01",
+		Name:     "2024年1月",
+		Status:   FiscalPeriodStatusClosed,
+		ClosedAt: &closedAt,
+		ClosedBy: "closer",
+	}
+
+	// モックの期待値設定
+	mockStorage.On("GetFiscalPeriod", ctx, "FP-2024-01").Return(closed, nil)
+	mockStorage.On("ReopenFiscalPeriod", ctx, "FP-2024-01", mock.MatchedBy(func(event *FiscalPeriodEvent) bool {
+		return event.Action == FiscalPeriodActionReopen && event.Reason == "監査指摘による修正" && event.PerformedBy == "admin"
+	})).Return(nil)
+
+	// 理由のない締め解除は不可
+	_, err := manager.ReopenFiscalPeriod(ctx, "FP-2024-01", "")
+	assert.IsType(t, &ValidationError{}, err)
+
+	// テスト実行
+	period, err := manager.ReopenFiscalPeriod(ctx, "FP-2024-01", "監査指摘による修正")
+
+	// アサーション
+	assert.NoError(t, err)
+	assert.Equal(t, FiscalPeriodStatusOpen, period.Status)
+	assert.Nil(t, period.ClosedAt)
+	assert.Empty(t, period.ClosedBy)
+	mockStorage.AssertExpectations(t)
+}
+
+// TestManager_ShipTransferOrder は移動指示出荷（輸送中ロケーションへの移動）のテスト
+func TestManager_ShipTransferOrder(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{LowStockThreshold: 10}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	// テスト用のサンプルデータ
+	order := &TransferOrder{
+		ID:           "TO-1",
+		ItemID:       "TEST-ITEM",
+		FromLocation: "WH-A",
+		ToLocation:   "WH-B",
+		Quantity:     40,
+		Status:       TransferOrderStatusPending,
+	}
+	item := &Item{ID: "TEST-ITEM", Name: "テスト商品"}
+	source := &Stock{ItemID: "TEST-ITEM", LocationID: "WH-A", Quantity: 100, Available: 100, Version: 1}
+
+	// モックの期待値設定
+	mockStorage.On("GetTransferOrder", ctx, "TO-1").Return(order, nil)
+	mockStorage.On("GetItem", ctx, "TEST-ITEM").Return(item, nil)
+	mockStorage.On("GetLocation", ctx, mock.AnythingOfType("string")).Return(&Location{}, nil)
+	mockStorage.On("GetStock", ctx, "TEST-ITEM", "WH-A").Return(source, nil)
+	mockStorage.On("GetStock", ctx, "TEST-ITEM", DefaultInTransitLocation).Return(nil, ErrStockNotFound)
+	mockStorage.On("UpdateStock", ctx, mock.MatchedBy(func(s *Stock) bool {
+		return s.LocationID == "WH-A" && s.Quantity == 60
+	})).Return(nil)
+	mockStorage.On("CreateStock", ctx, mock.MatchedBy(func(s *Stock) bool {
+		return s.LocationID == DefaultInTransitLocation && s.Quantity == 40
+	})).Return(nil)
+	mockStorage.On("CreateTransaction", ctx, mock.AnythingOfType("*inventory.Transaction")).Return(nil)
+	mockStorage.On("UpdateTransferOrder", ctx, order).Return(nil)
+
+	// テスト実行
+	shipped, err := manager.ShipTransferOrder(ctx, "TO-1")
+
+	// アサーション
+	assert.NoError(t, err)
+	assert.Equal(t, TransferOrderStatusInTransit, shipped.Status)
+	assert.NotNil(t, shipped.ShippedAt)
+	assert.Equal(t, int64(40), shipped.Outstanding())
+	mockStorage.AssertExpectations(t)
+}
+
+// TestManager_Transfer_LegsLinked は移動の出庫・入庫レッグが移動トランザクションに紐付くことのテスト
+func TestManager_Transfer_LegsLinked(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{LowStockThreshold: 10}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	// テスト用のサンプルデータ
+	item := &Item{ID: "TEST-ITEM", Name: "テスト商品"}
+	source := &Stock{ItemID: "TEST-ITEM", LocationID: "WH-A", Quantity: 100, Available: 100, Version: 1}
+	var recorded []*Transaction
+
+	// モックの期待値設定
+	mockStorage.On("GetItem", ctx, "TEST-ITEM").Return(item, nil)
+	mockStorage.On("GetLocation", ctx, mock.AnythingOfType("string")).Return(&Location{}, nil)
+	mockStorage.On("GetStock", ctx, "TEST-ITEM", "WH-A").Return(source, nil)
+	mockStorage.On("GetStock", ctx, "TEST-ITEM", "WH-B").Return(nil, ErrStockNotFound)
+	mockStorage.On("UpdateStock", ctx, mock.AnythingOfType("*inventory.Stock")).Return(nil)
+	mockStorage.On("CreateStock", ctx, mock.AnythingOfType("*inventory.Stock")).Return(nil)
+	mockStorage.On("CreateTransaction", ctx, mock.AnythingOfType("*inventory.Transaction")).Run(func(args mock.Arguments) {
+		recorded = append(recorded, args.Get(1).(*Transaction))
+	}).Return(nil)
+
+	// テスト実行
+	err := manager.Transfer(ctx, "TEST-ITEM", "WH-A", "WH-B", 25, "MOVE-1")
+
+	// アサーション（出庫・入庫レッグ・移動の順に記録）
+	assert.NoError(t, err)
+	if assert.Len(t, recorded, 3) {
+		transfer := recorded[2]
+		assert.Equal(t, TransactionTypeTransfer, transfer.Type)
+		assert.Equal(t, TransactionTypeOutbound, recorded[0].Type)
+		assert.Equal(t, transfer.ID, recorded[0].Metadata[TransferMetadataKey])
+		assert.Equal(t, TransactionTypeInbound, recorded[1].Type)
+		assert.Equal(t, transfer.ID, recorded[1].Metadata[TransferMetadataKey])
+	}
+	mockStorage.AssertExpectations(t)
+}
+
+// TestManager_ShipTransferOrder_Concurrent は同時出荷で確保に失敗した側が在庫を移動しないことのテスト
+func TestManager_ShipTransferOrder_Concurrent(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	// テスト用のサンプルデータ（他の出荷が先にバージョンを進めた状態）
+	order := &TransferOrder{
+		ID:           "TO-1",
+		ItemID:       "TEST-ITEM",
+		FromLocation: "WH-A",
+		ToLocation:   "WH-B",
+		Quantity:     40,
+		Status:       TransferOrderStatusPending,
+		Version:      1,
+	}
+
+	// モックの期待値設定
+	mockStorage.On("GetTransferOrder", ctx, "TO-1").Return(order, nil)
+	mockStorage.On("GetLocation", ctx, DefaultInTransitLocation).Return(&Location{}, nil)
+	mockStorage.On("UpdateTransferOrder", ctx, mock.MatchedBy(func(o *TransferOrder) bool {
+		return o.Version == 2 && o.Status == TransferOrderStatusInTransit
+	})).Return(ErrInvalidTransferOrderStatus)
+
+	// テスト実行
+	_, err := manager.ShipTransferOrder(ctx, "TO-1")
+
+	// アサーション（在庫は移動しない）
+	assert.Equal(t, ErrInvalidTransferOrderStatus, err)
+	mockStorage.AssertExpectations(t)
+	mockStorage.AssertNotCalled(t, "UpdateStock", mock.Anything, mock.Anything)
+	mockStorage.AssertNotCalled(t, "CreateTransaction", mock.Anything, mock.Anything)
+}
+
+// TestManager_ReceiveTransferOrder_Partial は移動指示の一部入荷と超過入荷拒否のテスト
+func TestManager_ReceiveTransferOrder_Partial(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	// テスト用のサンプルデータ
+	order := &TransferOrder{
+		ID:           "TO-1",
+		ItemID:       "TEST-ITEM",
+		FromLocation: "WH-A",
+		ToLocation:   "WH-B",
+		Quantity:     40,
+		Status:       TransferOrderStatusInTransit,
+	}
+	item := &Item{ID: "TEST-ITEM", Name: "テスト商品"}
+	inTransit := &Stock{ItemID: "TEST-ITEM", LocationID: DefaultInTransitLocation, Quantity: 40, Available: 40, Version: 1}
+	destination := &Stock{ItemID: "TEST-ITEM", LocationID: "WH-B", Quantity: 5, Available: 5, Version: 1}
+
+	// モックの期待値設定
+	mockStorage.On("GetTransferOrder", ctx, "TO-1").Return(order, nil)
+	mockStorage.On("GetItem", ctx, "TEST-ITEM").Return(item, nil)
+	mockStorage.On("GetLocation", ctx, mock.AnythingOfType("string")).Return(&Location{}, nil)
+	mockStorage.On("GetStock", ctx, "TEST-ITEM", DefaultInTransitLocation).Return(inTransit, nil)
+	mockStorage.On("GetStock", ctx, "TEST-ITEM", "WH-B").Return(destination, nil)
+	mockStorage.On("UpdateStock", ctx, mock.AnythingOfType("*inventory.Stock")).Return(nil)
+	mockStorage.On("CreateTransaction", ctx, mock.AnythingOfType("*inventory.Transaction")).Return(nil)
+	mockStorage.On("UpdateTransferOrder", ctx, order).Return(nil)
+
+	// テスト実行
+	received, err := manager.ReceiveTransferOrder(ctx, "TO-1", 25)
+
+	// アサーション
+	assert.NoError(t, err)
+	assert.Equal(t, TransferOrderStatusPartiallyReceived, received.Status)
+	assert.Equal(t, int64(15), received.Outstanding())
+	assert.Nil(t, received.CompletedAt)
+
+	// 輸送中数量を超える入荷は拒否
+	_, err = manager.ReceiveTransferOrder(ctx, "TO-1", 20)
+	assert.IsType(t, &ValidationError{}, err)
+}
+
+// TestManager_CreatePurchaseOrder は発注作成（明細番号付与・仕入先確認）のテスト
+func TestManager_CreatePurchaseOrder(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	// テスト用のサンプルデータ
+	order := &PurchaseOrder{
+		SupplierID: "SUP-1",
+		LocationID: "WH-A",
+		Lines: []PurchaseOrderLine{
+			{ItemID: "ITEM-A", Quantity: 100, UnitCost: NewMoney(120)},
+			{ItemID: "ITEM-B", Quantity: 50, UnitCost: NewMoney(80)},
+		},
+	}
+
+	// モックの期待値設定
+	mockStorage.On("GetSupplier", ctx, "SUP-1").Return(&Supplier{ID: "SUP-1", Name: "テスト仕入先"}, nil)
+	mockStorage.On("GetLocation", ctx, "WH-A").Return(&Location{ID: "WH-A"}, nil)
+	mockStorage.On("GetItem", ctx, mock.AnythingOfType("string")).Return(&Item{}, nil)
+	mockStorage.On("CreatePurchaseOrder", ctx, order).Return(nil)
+
+	// テスト実行
+	err := manager.CreatePurchaseOrder(ctx, order)
+
+	// アサーション
+	assert.NoError(t, err)
+	assert.NotEmpty(t, order.ID)
+	assert.Equal(t, PurchaseOrderStatusOpen, order.Status)
+	assert.Equal(t, 2, order.Lines[1].LineNumber)
+	assert.Equal(t, order.ID, order.Lines[1].PurchaseOrderID)
+	assert.Equal(t, int64(100), order.Lines[0].UnderReceived)
+	mockStorage.AssertExpectations(t)
+
+	// 存在しない仕入先は拒否
+	mockStorage.On("GetSupplier", ctx, "SUP-X").Return(nil, ErrSupplierNotFound)
+	err = manager.CreatePurchaseOrder(ctx, &PurchaseOrder{
+		SupplierID: "SUP-X",
+		LocationID: "WH-A",
+		Lines:      []PurchaseOrderLine{{ItemID: "ITEM-A", Quantity: 1}},
+	})
+	assert.Equal(t, ErrSupplierNotFound, err)
+
+	// 入荷先ロケーションの指定がない発注は商品の補充元ロケーションを入荷先にする
+	mockStorage.On("ListReplenishmentPolicies", ctx, "").Return([]ReplenishmentPolicy{
+		{LocationID: "STORE-1", ItemID: "ITEM-A", SourceLocationID: "WH-A", IsActive: true},
+		{LocationID: "STORE-1", ItemID: "ITEM-B", SourceLocationID: "WH-B", IsActive: true},
+		{LocationID: "STORE-2", ItemID: "ITEM-A", SourceLocationID: "WH-C", IsActive: false},
+	}, nil)
+	defaulted := &PurchaseOrder{
+		SupplierID: "SUP-1",
+		Lines:      []PurchaseOrderLine{{ItemID: "ITEM-A", Quantity: 1}},
+	}
+	mockStorage.On("CreatePurchaseOrder", ctx, defaulted).Return(nil)
+	err = manager.CreatePurchaseOrder(ctx, defaulted)
+	assert.NoError(t, err)
+	assert.Equal(t, "WH-A", defaulted.LocationID)
+
+	// 補充元が1つに決まらず、デフォルトロケーションも未設定の場合は拒否
+	err = manager.CreatePurchaseOrder(ctx, &PurchaseOrder{
+		SupplierID: "SUP-1",
+		Lines:      []PurchaseOrderLine{{ItemID: "ITEM-A", Quantity: 1}, {ItemID: "ITEM-B", Quantity: 1}},
+	})
+	assert.IsType(t, &ValidationError{}, err)
+}
+
+// TestManager_ReceivePurchaseOrder は発注入荷（単価・ロット付き入庫、過不足追跡）のテスト
+func TestManager_ReceivePurchaseOrder(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	// テスト用のサンプルデータ
+	order := &PurchaseOrder{
+		ID:         "PO-1",
+		SupplierID: "SUP-1",
+		Status:     PurchaseOrderStatusOpen,
+		Reference:  "PO-2024-001",
+		Lines: []PurchaseOrderLine{
+			{ID: "L1", ItemID: "ITEM-A", Quantity: 100, UnitCost: NewMoney(120)},
+			{ID: "L2", ItemID: "ITEM-B", Quantity: 50, UnitCost: NewMoney(80)},
+		},
+	}
+	lotNumber := "LOT-001"
+
+	// モックの期待値設定
+	mockStorage.On("GetPurchaseOrder", ctx, "PO-1").Return(order, nil)
+	mockStorage.On("GetItem", ctx, mock.AnythingOfType("string")).Return(&Item{}, nil)
+	mockStorage.On("GetLocation", ctx, "WH-A").Return(&Location{}, nil)
+	mockStorage.On("GetStock", ctx, mock.AnythingOfType("string"), "WH-A").Return(nil, ErrStockNotFound)
+	mockStorage.On("ApplyPurchaseReceipt", ctx, order, mock.MatchedBy(func(stocks []*Stock) bool {
+		return len(stocks) == 2 && stocks[0].Quantity == 60 && stocks[0].Version == 1 && stocks[1].Quantity == 55
+	}), mock.MatchedBy(func(txs []*Transaction) bool {
+		return len(txs) == 2 &&
+			txs[0].ItemID == "ITEM-A" && txs[0].Reference == "PO-2024-001" &&
+			txs[0].UnitCost != nil && *txs[0].UnitCost == NewMoney(120) &&
+			txs[0].LotNumber != nil && *txs[0].LotNumber == lotNumber &&
+			txs[1].ItemID == "ITEM-B" && txs[1].UnitCost != nil && *txs[1].UnitCost == NewMoney(80)
+	}), mock.MatchedBy(func(lots []*Lot) bool {
+		return len(lots) == 1 && lots[0].Number == lotNumber && lots[0].Quantity == 60
+	})).Return(nil)
+
+	// テスト実行（L1は一部入荷、L2は超過入荷）
+	received, err := manager.ReceivePurchaseOrder(ctx, "PO-1", "WH-A", []PurchaseReceipt{
+		{LineID: "L1", Quantity: 60, LotNumber: &lotNumber},
+		{LineID: "L2", Quantity: 55},
+	})
+
+	// アサーション
+	assert.NoError(t, err)
+	assert.Equal(t, PurchaseOrderStatusPartiallyReceived, received.Status)
+	assert.Equal(t, int64(40), received.Lines[0].UnderReceived)
+	assert.Equal(t, int64(5), received.Lines[1].OverReceived)
+	mockStorage.AssertExpectations(t)
+
+	// 存在しない明細は拒否
+	_, err = manager.ReceivePurchaseOrder(ctx, "PO-1", "WH-A", []PurchaseReceipt{{LineID: "L9", Quantity: 1}})
+	assert.IsType(t, &ValidationError{}, err)
+}
+
+// TestManager_ReceivePurchaseOrder_Conflict は同時入荷で発注が更新済みの場合のテスト
+func TestManager_ReceivePurchaseOrder_Conflict(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	// テスト用のサンプルデータ
+	order := &PurchaseOrder{
+		ID:         "PO-1",
+		SupplierID: "SUP-1",
+		Status:     PurchaseOrderStatusOpen,
+		Version:    2,
+		Lines:      []PurchaseOrderLine{{ID: "L1", ItemID: "ITEM-A", Quantity: 100, UnitCost: NewMoney(120)}},
+	}
+
+	// モックの期待値設定（他の入荷が先にバージョンを進めている）
+	mockStorage.On("GetPurchaseOrder", ctx, "PO-1").Return(order, nil)
+	mockStorage.On("GetItem", ctx, "ITEM-A").Return(&Item{}, nil)
+	mockStorage.On("GetLocation", ctx, "WH-A").Return(&Location{}, nil)
+	mockStorage.On("GetStock", ctx, "ITEM-A", "WH-A").Return(nil, ErrStockNotFound)
+	mockStorage.On("ApplyPurchaseReceipt", ctx, mock.MatchedBy(func(o *PurchaseOrder) bool {
+		return o.Version == 3
+	}), mock.Anything, mock.Anything, mock.Anything).Return(ErrVersionMismatch)
+
+	// テスト実行
+	_, err := manager.ReceivePurchaseOrder(ctx, "PO-1", "WH-A", []PurchaseReceipt{{LineID: "L1", Quantity: 60}})
+
+	// アサーション（在庫の計上・取消は個別に行わない）
+	assert.Equal(t, ErrVersionMismatch, err)
+	mockStorage.AssertNotCalled(t, "CreateStock", mock.Anything, mock.Anything)
+	mockStorage.AssertNotCalled(t, "UpdateStock", mock.Anything, mock.Anything)
+	mockStorage.AssertNotCalled(t, "CreateTransaction", mock.Anything, mock.Anything)
+}
+
+// TestManager_AllocateOutboundOrder はロケーション順の引当と引当不足のテスト
+func TestManager_AllocateOutboundOrder(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	// テスト用のサンプルデータ
+	order := &OutboundOrder{
+		ID:       "SO-1",
+		Customer: "テスト顧客",
+		Status:   OutboundOrderStatusPending,
+		Lines: []OutboundOrderLine{
+			{ID: "L1", ItemID: "ITEM-A", Quantity: 50},
+		},
+	}
+	stocks := []Stock{
+		{ItemID: "ITEM-A", LocationID: DefaultInTransitLocation, Quantity: 100, Available: 100},
+		{ItemID: "ITEM-A", LocationID: "A-01", Quantity: 30, Reserved: 10, Available: 20},
+		{ItemID: "ITEM-A", LocationID: "B-02", Quantity: 15, Available: 15},
+	}
+
+	// モックの期待値設定
+	mockStorage.On("GetOutboundOrder", ctx, "SO-1").Return(order, nil)
+	mockStorage.On("ListStockByItem", ctx, "ITEM-A").Return(stocks, nil)
+	mockStorage.On("GetStock", ctx, "ITEM-A", "A-01").Return(&Stock{ItemID: "ITEM-A", LocationID: "A-01", Quantity: 30, Reserved: 10, Available: 20}, nil)
+	mockStorage.On("GetStock", ctx, "ITEM-A", "B-02").Return(&Stock{ItemID: "ITEM-A", LocationID: "B-02", Quantity: 15, Available: 15}, nil)
+	mockStorage.On("UpdateStock", ctx, mock.MatchedBy(func(s *Stock) bool {
+		return (s.LocationID == "A-01" && s.Reserved == 30) || (s.LocationID == "B-02" && s.Reserved == 15)
+	})).Return(nil)
+	mockStorage.On("UpdateOutboundOrder", ctx, order).Return(nil)
+
+	// テスト実行
+	allocated, err := manager.AllocateOutboundOrder(ctx, "SO-1")
+
+	// アサーション（輸送中ロケーションは引当対象外、不足分は未引当のまま）
+	assert.NoError(t, err)
+	assert.Equal(t, OutboundOrderStatusAllocated, allocated.Status)
+	assert.Equal(t, int64(35), allocated.Lines[0].AllocatedQuantity)
+	assert.Len(t, allocated.Allocations, 2)
+	assert.Equal(t, "A-01", allocated.Allocations[0].LocationID)
+	assert.Equal(t, int64(20), allocated.Allocations[0].Quantity)
+	mockStorage.AssertExpectations(t)
+
+	// 引当済みの出荷指示は再引当不可
+	_, err = manager.AllocateOutboundOrder(ctx, "SO-1")
+	assert.Equal(t, ErrInvalidOutboundOrderStatus, err)
+}
+
+// TestManager_GeneratePickList はピッキングリストの巡回順ソートのテスト
+func TestManager_GeneratePickList(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	// テスト用のサンプルデータ
+	order := &OutboundOrder{
+		ID:     "SO-1",
+		Status: OutboundOrderStatusAllocated,
+		Allocations: []PickAllocation{
+			{ID: "AL-1", LineID: "L1", ItemID: "ITEM-A", LocationID: "WH-Z", Quantity: 5},
+			{ID: "AL-2", LineID: "L2", ItemID: "ITEM-B", LocationID: "WH-A", Quantity: 3},
+			{ID: "AL-3", LineID: "L3", ItemID: "ITEM-C", LocationID: "WH-M", Quantity: 1, Confirmed: true},
+		},
+	}
+
+	// モックの期待値設定
+	mockStorage.On("GetOutboundOrder", ctx, "SO-1").Return(order, nil)
+	mockStorage.On("GetLocation", ctx, "WH-Z").Return(&Location{ID: "WH-Z", PickPath: "01-01"}, nil)
+	mockStorage.On("GetLocation", ctx, "WH-A").Return(&Location{ID: "WH-A", PickPath: "03-02"}, nil)
+
+	// テスト実行
+	pickList, err := manager.GeneratePickList(ctx, "SO-1")
+
+	// アサーション（パス順に並び、確定済みの引当は含まれない）
+	assert.NoError(t, err)
+	assert.Len(t, pickList.Entries, 2)
+	assert.Equal(t, "WH-Z", pickList.Entries[0].LocationID)
+	assert.Equal(t, "WH-A", pickList.Entries[1].LocationID)
+	mockStorage.AssertExpectations(t)
+}
+
+// TestManager_ConfirmPicks_Backorder は実数量でのピッキング確定と欠品のバックオーダー化のテスト
+func TestManager_ConfirmPicks_Backorder(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	// テスト用のサンプルデータ
+	order := &OutboundOrder{
+		ID:        "SO-1",
+		Status:    OutboundOrderStatusAllocated,
+		Reference: "SO-2024-001",
+		Lines: []OutboundOrderLine{
+			{ID: "L1", ItemID: "ITEM-A", Quantity: 10, AllocatedQuantity: 10},
+		},
+		Allocations: []PickAllocation{
+			{ID: "AL-1", LineID: "L1", ItemID: "ITEM-A", LocationID: "A-01", Quantity: 10},
+		},
+	}
+
+	// モックの期待値設定（予約10を解除後、実数量8を出庫）
+	mockStorage.On("GetOutboundOrder", ctx, "SO-1").Return(order, nil)
+	mockStorage.On("GetStock", ctx, "ITEM-A", "A-01").Return(&Stock{ItemID: "ITEM-A", LocationID: "A-01", Quantity: 10, Reserved: 10, Available: 0}, nil).Once()
+	mockStorage.On("UpdateStock", ctx, mock.MatchedBy(func(s *Stock) bool {
+		return s.Reserved == 0 && s.Quantity == 10
+	})).Return(nil).Once()
+	mockStorage.On("GetItem", ctx, "ITEM-A").Return(&Item{}, nil)
+	mockStorage.On("GetLocation", ctx, "A-01").Return(&Location{}, nil)
+	mockStorage.On("GetStock", ctx, "ITEM-A", "A-01").Return(&Stock{ItemID: "ITEM-A", LocationID: "A-01", Quantity: 10, Available: 10}, nil).Once()
+	mockStorage.On("UpdateStock", ctx, mock.MatchedBy(func(s *Stock) bool {
+		return s.Quantity == 2
+	})).Return(nil).Once()
+	mockStorage.On("CreateTransaction", ctx, mock.MatchedBy(func(tx *Transaction) bool {
+		return tx.Type == TransactionTypeOutbound && tx.Quantity == 8 && tx.Reference == "SO-2024-001"
+	})).Return(nil)
+	mockStorage.On("UpdateOutboundOrder", ctx, order).Return(nil)
+
+	// テスト実行
+	confirmed, err := manager.ConfirmPicks(ctx, "SO-1", []PickConfirmation{{AllocationID: "AL-1", PickedQuantity: 8}})
+
+	// アサーション
+	assert.NoError(t, err)
+	assert.Equal(t, OutboundOrderStatusBackordered, confirmed.Status)
+	assert.Equal(t, int64(8), confirmed.Lines[0].PickedQuantity)
+	assert.Equal(t, int64(2), confirmed.Lines[0].BackorderedQuantity)
+	assert.True(t, confirmed.Allocations[0].Confirmed)
+	mockStorage.AssertExpectations(t)
+}
+
+// TestManager_ConfirmPicks_Conflict は同時のピッキング確定・キャンセルで出荷指示が更新済みの場合のテスト
+func TestManager_ConfirmPicks_Conflict(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	// テスト用のサンプルデータ
+	order := &OutboundOrder{
+		ID:      "SO-1",
+		Status:  OutboundOrderStatusAllocated,
+		Version: 2,
+		Lines: []OutboundOrderLine{
+			{ID: "L1", ItemID: "ITEM-A", Quantity: 10, AllocatedQuantity: 10},
+		},
+		Allocations: []PickAllocation{
+			{ID: "AL-1", LineID: "L1", ItemID: "ITEM-A", LocationID: "A-01", Quantity: 10},
+		},
+	}
+
+	// モックの期待値設定（他の処理が先にバージョンを進めている）
+	mockStorage.On("GetOutboundOrder", ctx, "SO-1").Return(order, nil)
+	mockStorage.On("UpdateOutboundOrder", ctx, mock.MatchedBy(func(o *OutboundOrder) bool {
+		return o.Version == 3
+	})).Return(ErrInvalidOutboundOrderStatus)
+
+	// テスト実行
+	_, err := manager.ConfirmPicks(ctx, "SO-1", []PickConfirmation{{AllocationID: "AL-1", PickedQuantity: 10}})
+
+	// アサーション（予約解除・出庫は行わない）
+	assert.Equal(t, ErrInvalidOutboundOrderStatus, err)
+	mockStorage.AssertNotCalled(t, "GetStock", mock.Anything, mock.Anything, mock.Anything)
+	mockStorage.AssertNotCalled(t, "UpdateStock", mock.Anything, mock.Anything)
+	mockStorage.AssertNotCalled(t, "CreateTransaction", mock.Anything, mock.Anything)
+}
+
+// TestManager_Assemble はキット組立（部品消費・キット入庫・原価積み上げ）のテスト
+func TestManager_Assemble(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	// テスト用のサンプルデータ
+	bom := &BillOfMaterials{
+		ItemID: "GIFT-SET",
+		Components: []BOMComponent{
+			{ItemID: "TEA", Quantity: 2},
+			{ItemID: "BOX", Quantity: 1},
+		},
+	}
+	teaCost := NewMoney(150)
+	locationID := "WH-A"
+	teaHistory := []Transaction{
+		{Type: TransactionTypeInbound, ItemID: "TEA", ToLocation: &locationID, Quantity: 10, UnitCost: &teaCost},
+	}
+
+	// モックの期待値設定
+	mockStorage.On("GetItem", ctx, "GIFT-SET").Return(&Item{ID: "GIFT-SET"}, nil)
+	mockStorage.On("GetItem", ctx, "TEA").Return(&Item{ID: "TEA"}, nil)
+	mockStorage.On("GetItem", ctx, "BOX").Return(&Item{ID: "BOX", UnitCost: NewMoney(80)}, nil)
+	mockStorage.On("GetLocation", ctx, "WH-A").Return(&Location{ID: "WH-A"}, nil)
+	mockStorage.On("GetBillOfMaterials", ctx, "GIFT-SET").Return(bom, nil)
+	mockStorage.On("GetTransactionHistory", ctx, "TEA", 1000).Return(teaHistory, nil)
+	mockStorage.On("GetTransactionHistory", ctx, "BOX", 1000).Return([]Transaction{}, nil)
+	mockStorage.On("GetStock", ctx, "GIFT-SET", "WH-A").Return(nil, ErrStockNotFound)
+	mockStorage.On("GetStock", ctx, "TEA", "WH-A").Return(&Stock{ItemID: "TEA", LocationID: "WH-A", Quantity: 20, Available: 20, Version: 3}, nil)
+	mockStorage.On("GetStock", ctx, "BOX", "WH-A").Return(&Stock{ItemID: "BOX", LocationID: "WH-A", Quantity: 5, Available: 5, Version: 1}, nil)
+	mockStorage.On("ApplyStockChanges", ctx,
+		mock.MatchedBy(func(stocks []*Stock) bool {
+			return len(stocks) == 3 &&
+				stocks[0].ItemID == "GIFT-SET" && stocks[0].Quantity == 4 && stocks[0].Version == 1 &&
+				stocks[1].ItemID == "TEA" && stocks[1].Quantity == 12 && stocks[1].Version == 4 &&
+				stocks[2].ItemID == "BOX" && stocks[2].Quantity == 1
+		}),
+		mock.MatchedBy(func(txs []*Transaction) bool {
+			// キット原価 = 150×2 + 80×1（BOXは入庫実績がないため商品マスタ単価）
+			return len(txs) == 3 &&
+				txs[0].Type == TransactionTypeInbound && txs[0].UnitCost != nil && *txs[0].UnitCost == NewMoney(380) &&
+				txs[1].Type == TransactionTypeOutbound && txs[1].Quantity == 8 &&
+				txs[2].Type == TransactionTypeOutbound && txs[2].Quantity == 4
+		}),
+	).Return(nil)
+
+	mockStorage.On("CreateBatchOperation", ctx, mock.AnythingOfType("*inventory.BatchOperation")).Return(nil)
+
+	// テスト実行（バッチ操作経由）
+	batch, err := manager.ExecuteBatch(ctx, []InventoryOperation{
+		{Type: OperationTypeAssemble, ItemID: "GIFT-SET", LocationID: "WH-A", Quantity: 4, Reference: "KIT-001"},
+	})
+
+	// アサーション
+	assert.NoError(t, err)
+	assert.Equal(t, BatchStatusCompleted, batch.Status)
+	mockStorage.AssertExpectations(t)
+}
+
+// TestManager_Assemble_InsufficientComponent は部品不足時に何も計上しないことのテスト
+func TestManager_Assemble_InsufficientComponent(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	// テスト用のサンプルデータ
+	bom := &BillOfMaterials{
+		ItemID:     "GIFT-SET",
+		Components: []BOMComponent{{ItemID: "TEA", Quantity: 2}},
+	}
+
+	// モックの期待値設定
+	mockStorage.On("GetItem", ctx, mock.AnythingOfType("string")).Return(&Item{UnitCost: NewMoney(100)}, nil)
+	mockStorage.On("GetLocation", ctx, "WH-A").Return(&Location{ID: "WH-A"}, nil)
+	mockStorage.On("GetBillOfMaterials", ctx, "GIFT-SET").Return(bom, nil)
+	mockStorage.On("GetTransactionHistory", ctx, "TEA", 1000).Return([]Transaction{}, nil)
+	mockStorage.On("GetStock", ctx, "GIFT-SET", "WH-A").Return(nil, ErrStockNotFound)
+	mockStorage.On("GetStock", ctx, "TEA", "WH-A").Return(&Stock{ItemID: "TEA", LocationID: "WH-A", Quantity: 3, Available: 3, Version: 1}, nil)
+
+	// テスト実行
+	err := manager.Assemble(ctx, "GIFT-SET", "WH-A", 2, "KIT-002")
+
+	// アサーション
+	assert.Equal(t, ErrInsufficientStock, err)
+	mockStorage.AssertNotCalled(t, "ApplyStockChanges", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestManager_GenerateCycleCountTasks はABC区分ごとの棚卸間隔に基づくタスク生成のテスト
+func TestManager_GenerateCycleCountTasks(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{CycleCountPolicy: DefaultCycleCountPolicy()}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	// テスト用のサンプルデータ（出庫金額 A1:80% / B1:15% / C1・C2:5%）
+	today := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	stocks := []Stock{
+		{ItemID: "A1", LocationID: "WH-A", Quantity: 5},
+		{ItemID: "B1", LocationID: "WH-A", Quantity: 5},
+		{ItemID: "C1", LocationID: "WH-A", Quantity: 5},
+		{ItemID: "C2", LocationID: "WH-A", Quantity: 5},
+	}
+	shippedAt := time.Now().AddDate(0, 0, -3)
+	outbound := []Transaction{
+		{Type: TransactionTypeOutbound, ItemID: "A1", Quantity: 80, CreatedAt: shippedAt},
+		{Type: TransactionTypeOutbound, ItemID: "B1", Quantity: 15, CreatedAt: shippedAt},
+		{Type: TransactionTypeOutbound, ItemID: "C1", Quantity: 3, CreatedAt: shippedAt},
+		{Type: TransactionTypeOutbound, ItemID: "C2", Quantity: 2, CreatedAt: shippedAt},
+	}
+	latest := []CycleCountTask{
+		{ItemID: "A1", Status: CycleCountStatusApproved, ScheduledDate: today.AddDate(0, 0, -31)},
+		{ItemID: "B1", Status: CycleCountStatusPending, ScheduledDate: today.AddDate(0, 0, -100)},
+		{ItemID: "C1", Status: CycleCountStatusApproved, ScheduledDate: today.AddDate(0, 0, -400)},
+	}
+
+	// モックの期待値設定
+	mockStorage.On("GetLocation", ctx, "WH-A").Return(&Location{ID: "WH-A"}, nil)
+	mockStorage.On("ListStockByLocation", ctx, "WH-A").Return(stocks, nil)
+	mockStorage.On("ListOutboundTransactions", ctx, "WH-A", mock.Anything, mock.Anything).Return(outbound, nil)
+	mockStorage.On("GetItem", ctx, mock.AnythingOfType("string")).Return(&Item{UnitCost: NewMoney(10)}, nil)
+	mockStorage.On("ListLatestCycleCountTasks", ctx, "WH-A").Return(latest, nil)
+	mockStorage.On("CreateCycleCountTask", ctx, mock.AnythingOfType("*inventory.CycleCountTask")).Return(nil)
+
+	// テスト実行
+	tasks, err := manager.GenerateCycleCountTasks(ctx, "WH-A", today.Add(9*time.Hour))
+
+	// アサーション（B1は未完了タスクあり、C区分は1日1件で未棚卸のC2を優先）
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 2)
+	assert.Equal(t, "A1", tasks[0].ItemID)
+	assert.Equal(t, "A", tasks[0].ABCClass)
+	assert.Equal(t, "C2", tasks[1].ItemID)
+	assert.Equal(t, "C", tasks[1].ABCClass)
+	assert.Equal(t, CycleCountStatusPending, tasks[1].Status)
+	assert.True(t, tasks[1].ScheduledDate.Equal(today))
+	mockStorage.AssertNumberOfCalls(t, "CreateCycleCountTask", 2)
+}
+
+// TestManager_RecordCycleCount_AutoApprove は許容差異内の棚卸結果が自動承認されることのテスト
+func TestManager_RecordCycleCount_AutoApprove(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{CycleCountPolicy: DefaultCycleCountPolicy()}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	// テスト用のサンプルデータ
+	task := &CycleCountTask{ID: "CC-1", LocationID: "WH-A", ItemID: "ITEM-1", ABCClass: "A", Status: CycleCountStatusPending}
+
+	// モックの期待値設定
+	mockStorage.On("GetCycleCountTask", ctx, "CC-1").Return(task, nil)
+	mockStorage.On("GetItem", ctx, "ITEM-1").Return(&Item{ID: "ITEM-1"}, nil)
+	mockStorage.On("GetLocation", ctx, "WH-A").Return(&Location{ID: "WH-A"}, nil)
+	mockStorage.On("GetStock", ctx, "ITEM-1", "WH-A").Return(&Stock{ItemID: "ITEM-1", LocationID: "WH-A", Quantity: 100, Version: 2}, nil)
+	mockStorage.On("ApplyCycleCountSettlement", ctx, mock.MatchedBy(func(t *CycleCountTask) bool {
+		return t.Status == CycleCountStatusApproved && t.ExpectedQuantity == 100 && t.Variance == -1
+	}), CycleCountStatusPending, mock.MatchedBy(func(stocks []*Stock) bool {
+		return len(stocks) == 1 && stocks[0].Quantity == 99 && stocks[0].Version == 3
+	}), mock.MatchedBy(func(txs []*Transaction) bool {
+		return len(txs) == 1 && txs[0].Type == TransactionTypeAdjust && txs[0].Quantity == -1 && txs[0].Reference == "CC-CC-1"
+	})).Return(nil)
+
+	// テスト実行（差異率1%、A区分の許容差異は2%）
+	result, err := manager.RecordCycleCount(ctx, "CC-1", 99)
+
+	// アサーション
+	assert.NoError(t, err)
+	assert.Equal(t, CycleCountStatusApproved, result.Status)
+	assert.Equal(t, 1.0, result.VariancePercent)
+	mockStorage.AssertExpectations(t)
+	mockStorage.AssertNotCalled(t, "CreateAlert", mock.Anything, mock.Anything)
+}
+
+// TestManager_RecordCycleCount_Discrepancy は許容差異を超える棚卸結果が承認待ちになることのテスト
+func TestManager_RecordCycleCount_Discrepancy(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{CycleCountPolicy: DefaultCycleCountPolicy()}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	// テスト用のサンプルデータ
+	task := &CycleCountTask{ID: "CC-2", LocationID: "WH-A", ItemID: "ITEM-1", ABCClass: "A", Status: CycleCountStatusPending}
+
+	// モックの期待値設定
+	mockStorage.On("GetCycleCountTask", ctx, "CC-2").Return(task, nil)
+	mockStorage.On("GetStock", ctx, "ITEM-1", "WH-A").Return(&Stock{ItemID: "ITEM-1", LocationID: "WH-A", Quantity: 1000, Version: 2}, nil)
+	mockStorage.On("UpdateCycleCountTask", ctx, mock.MatchedBy(func(t *CycleCountTask) bool {
+		return t.Status == CycleCountStatusDiscrepancy && t.Variance == -100
+	}), CycleCountStatusPending).Return(nil)
+	mockStorage.On("GetItem", ctx, "ITEM-1").Return(&Item{ID: "ITEM-1", QuantityPrecision: 1}, nil)
+	mockStorage.On("CreateAlert", ctx, mock.MatchedBy(func(a *StockAlert) bool {
+		return a.Type == AlertTypeDiscrepancy && a.ItemID == "ITEM-1" && a.CurrentQty == 900 && a.Threshold == 1000 &&
+			strings.Contains(a.Message, "帳簿: 100.0, 実棚: 90.0")
+	})).Return(nil)
+
+	// テスト実行（小数1桁の商品で帳簿100.0に対し実棚90.0、差異率10%、A区分の許容差異は2%）
+	result, err := manager.RecordCycleCount(ctx, "CC-2", 900)
+
+	// アサーション（在庫は変更しない）
+	assert.NoError(t, err)
+	assert.Equal(t, CycleCountStatusDiscrepancy, result.Status)
+	mockStorage.AssertExpectations(t)
+	mockStorage.AssertNotCalled(t, "UpdateStock", mock.Anything, mock.Anything)
+}
+
+// TestManager_ReviewCycleCount_Conflict は同時に承認された棚卸差異を二重に反映しないことのテスト
+func TestManager_ReviewCycleCount_Conflict(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{CycleCountPolicy: DefaultCycleCountPolicy()}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	// テスト用のサンプルデータ（読み込み後に別の承認で反映済み）
+	counted := int64(90)
+	task := &CycleCountTask{ID: "CC-3", LocationID: "WH-A", ItemID: "ITEM-1", ABCClass: "A", Status: CycleCountStatusDiscrepancy,
+		ExpectedQuantity: 100, CountedQuantity: &counted, Variance: -10}
+
+	// モックの期待値設定
+	mockStorage.On("GetCycleCountTask", ctx, "CC-3").Return(task, nil)
+	mockStorage.On("GetItem", ctx, "ITEM-1").Return(&Item{ID: "ITEM-1"}, nil)
+	mockStorage.On("GetLocation", ctx, "WH-A").Return(&Location{ID: "WH-A"}, nil)
+	mockStorage.On("GetStock", ctx, "ITEM-1", "WH-A").Return(&Stock{ItemID: "ITEM-1", LocationID: "WH-A", Quantity: 100, Version: 2}, nil)
+	mockStorage.On("ApplyCycleCountSettlement", ctx, task, CycleCountStatusDiscrepancy, mock.Anything, mock.Anything).Return(ErrInvalidCycleCountStatus)
+
+	// テスト実行
+	_, err := manager.ReviewCycleCount(ctx, "CC-3", true, "")
+
+	// アサーション（在庫は個別に更新しない）
+	assert.Equal(t, ErrInvalidCycleCountStatus, err)
+	mockStorage.AssertNotCalled(t, "UpdateStock", mock.Anything, mock.Anything)
+	mockStorage.AssertNotCalled(t, "GetActiveAlerts", mock.Anything, mock.Anything)
+}
+
+// TestManager_GenerateReplenishmentProposals は最小・最大在庫に基づく補充提案と補充元の利用可能数量による上限のテスト
+func TestManager_GenerateReplenishmentProposals(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+
+	manager := NewManager(mockStorage, nil, logger, nil)
+	ctx := context.Background()
+
+	// テスト用のサンプルデータ（補充元WHの利用可能数量70のうち20は未実行の提案で見込み済み）
+	policies := []ReplenishmentPolicy{
+		{LocationID: "STORE-1", ItemID: "ITEM", SourceLocationID: "WH", MinQuantity: 10, MaxQuantity: 50, IsActive: true},
+		{LocationID: "STORE-2", ItemID: "ITEM", SourceLocationID: "WH", MinQuantity: 10, MaxQuantity: 50, IsActive: true},
+		{LocationID: "STORE-3", ItemID: "ITEM", SourceLocationID: "WH", MinQuantity: 10, MaxQuantity: 50, IsActive: true},
+		{LocationID: "STORE-4", ItemID: "ITEM", SourceLocationID: "WH", MinQuantity: 10, MaxQuantity: 50, IsActive: false},
+		{LocationID: "STORE-5", ItemID: "ITEM", SourceLocationID: "WH", MinQuantity: 10, MaxQuantity: 50, IsActive: true},
+	}
+	open := []ReplenishmentProposal{
+		{ID: "RP-OPEN", LocationID: "STORE-3", SourceLocationID: "WH", ItemID: "ITEM", Status: ReplenishmentProposalStatusApproved, Quantity: 20},
+	}
+
+	// モックの期待値設定
+	mockStorage.On("ListReplenishmentPolicies", ctx, "").Return(policies, nil)
+	mockStorage.On("ListOpenReplenishmentProposals", ctx).Return(open, nil)
+	mockStorage.On("GetStock", ctx, "ITEM", "STORE-1").Return(&Stock{ItemID: "ITEM", LocationID: "STORE-1", Quantity: 4}, nil)
+	mockStorage.On("GetStock", ctx, "ITEM", "STORE-2").Return(nil, ErrStockNotFound)
+	mockStorage.On("GetStock", ctx, "ITEM", "STORE-5").Return(&Stock{ItemID: "ITEM", LocationID: "STORE-5", Quantity: 10}, nil)
+	mockStorage.On("GetStock", ctx, "ITEM", "WH").Return(&Stock{ItemID: "ITEM", LocationID: "WH", Quantity: 100, Reserved: 30, Available: 70}, nil)
+	mockStorage.On("CreateReplenishmentProposal", ctx, mock.AnythingOfType("*inventory.ReplenishmentProposal")).Return(nil)
+
+	// テスト実行
+	proposals, err := manager.GenerateReplenishmentProposals(ctx, "")
+
+	// アサーション（STORE-3は提案済み、STORE-4は無効、STORE-5は最小在庫ちょうどのため対象外）
+	assert.NoError(t, err)
+	assert.Len(t, proposals, 2)
+	assert.Equal(t, "STORE-1", proposals[0].LocationID)
+	assert.Equal(t, int64(46), proposals[0].RequiredQuantity)
+	assert.Equal(t, int64(46), proposals[0].Quantity)
+	assert.Equal(t, ReplenishmentProposalStatusPending, proposals[0].Status)
+	// 残りの利用可能数量 70 - 20 - 46 = 4 に制限
+	assert.Equal(t, "STORE-2", proposals[1].LocationID)
+	assert.Equal(t, int64(50), proposals[1].RequiredQuantity)
+	assert.Equal(t, int64(4), proposals[1].Quantity)
+	mockStorage.AssertNumberOfCalls(t, "CreateReplenishmentProposal", 2)
+}
+
+// TestManager_ExecuteReplenishmentProposals は承認済み補充提案のバッチ移動と失敗時の記録のテスト
+func TestManager_ExecuteReplenishmentProposals(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{LowStockThreshold: 0}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	// テスト用のサンプルデータ（2件目は1件目の移動後に補充元が不足する）
+	first := &ReplenishmentProposal{ID: "P-1", LocationID: "STORE-1", SourceLocationID: "WH", ItemID: "ITEM", Status: ReplenishmentProposalStatusApproved, Quantity: 46}
+	second := &ReplenishmentProposal{ID: "P-2", LocationID: "STORE-2", SourceLocationID: "WH", ItemID: "ITEM", Status: ReplenishmentProposalStatusApproved, Quantity: 10}
+	source := &Stock{ItemID: "ITEM", LocationID: "WH", Quantity: 50, Available: 50, Version: 1}
+
+	// モックの期待値設定
+	mockStorage.On("GetReplenishmentProposal", ctx, "P-1").Return(first, nil)
+	mockStorage.On("GetReplenishmentProposal", ctx, "P-2").Return(second, nil)
+	mockStorage.On("GetItem", ctx, "ITEM").Return(&Item{ID: "ITEM"}, nil)
+	mockStorage.On("GetLocation", ctx, mock.AnythingOfType("string")).Return(&Location{}, nil)
+	mockStorage.On("GetStock", ctx, "ITEM", "WH").Return(source, nil)
+	mockStorage.On("GetStock", ctx, "ITEM", "STORE-1").Return(nil, ErrStockNotFound)
+	mockStorage.On("UpdateStock", ctx, mock.AnythingOfType("*inventory.Stock")).Return(nil)
+	mockStorage.On("CreateStock", ctx, mock.MatchedBy(func(s *Stock) bool {
+		return s.LocationID == "STORE-1" && s.Quantity == 46
+	})).Return(nil)
+	mockStorage.On("CreateTransaction", ctx, mock.MatchedBy(func(tx *Transaction) bool {
+		return tx.Reference == "RP-P-1"
+	})).Return(nil)
+	mockStorage.On("UpdateReplenishmentProposal", ctx, mock.AnythingOfType("*inventory.ReplenishmentProposal"), ReplenishmentProposalStatusApproved).Return(nil).Twice()
+	mockStorage.On("UpdateReplenishmentProposal", ctx, mock.AnythingOfType("*inventory.ReplenishmentProposal"), ReplenishmentProposalStatusExecuting).Return(nil).Twice()
+	mockStorage.On("CreateBatchOperation", ctx, mock.MatchedBy(func(b *BatchOperation) bool {
+		return b.Status == BatchStatusFailed && len(b.Errors) == 1 && b.Errors[0].OperationIndex == 1
+	})).Return(nil)
+
+	// テスト実行
+	batch, err := manager.ExecuteReplenishmentProposals(ctx, []string{"P-1", "P-2"})
+
+	// アサーション
+	assert.NoError(t, err)
+	assert.Equal(t, 1, batch.SuccessCount)
+	assert.Equal(t, 1, batch.FailureCount)
+	assert.Equal(t, ReplenishmentProposalStatusExecuted, first.Status)
+	assert.Equal(t, batch.ID, first.BatchID)
+	assert.Equal(t, ReplenishmentProposalStatusFailed, second.Status)
+	assert.NotEmpty(t, second.Error)
+	assert.Equal(t, int64(4), source.Quantity)
+
+	// 実行済みの提案は再実行できない
+	_, err = manager.ExecuteReplenishmentProposals(ctx, []string{"P-1"})
+	assert.Equal(t, ErrInvalidReplenishmentStatus, err)
+}
+
+// TestManager_ExecuteReplenishmentProposals_Race は他の実行に先に確保された補充提案をスキップするテスト
+func TestManager_ExecuteReplenishmentProposals_Race(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{LowStockThreshold: 0}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	// テスト用のサンプルデータ（P-2は読み込み後に別の実行が確保済み）
+	first := &ReplenishmentProposal{ID: "P-1", LocationID: "STORE-1", SourceLocationID: "WH", ItemID: "ITEM", Status: ReplenishmentProposalStatusApproved, Quantity: 10}
+	second := &ReplenishmentProposal{ID: "P-2", LocationID: "STORE-2", SourceLocationID: "WH", ItemID: "ITEM", Status: ReplenishmentProposalStatusApproved, Quantity: 10}
+	source := &Stock{ItemID: "ITEM", LocationID: "WH", Quantity: 50, Available: 50, Version: 1}
+
+	// モックの期待値設定
+	mockStorage.On("GetReplenishmentProposal", ctx, "P-1").Return(first, nil)
+	mockStorage.On("GetReplenishmentProposal", ctx, "P-2").Return(second, nil)
+	mockStorage.On("UpdateReplenishmentProposal", ctx, first, ReplenishmentProposalStatusApproved).Return(nil).Once()
+	mockStorage.On("UpdateReplenishmentProposal", ctx, second, ReplenishmentProposalStatusApproved).Return(ErrInvalidReplenishmentStatus).Once()
+	mockStorage.On("UpdateReplenishmentProposal", ctx, first, ReplenishmentProposalStatusExecuting).Return(nil).Once()
+	mockStorage.On("GetItem", ctx, "ITEM").Return(&Item{ID: "ITEM"}, nil)
+	mockStorage.On("GetLocation", ctx, mock.AnythingOfType("string")).Return(&Location{}, nil)
+	mockStorage.On("GetStock", ctx, "ITEM", "WH").Return(source, nil)
+	mockStorage.On("GetStock", ctx, "ITEM", "STORE-1").Return(nil, ErrStockNotFound)
+	mockStorage.On("UpdateStock", ctx, mock.AnythingOfType("*inventory.Stock")).Return(nil)
+	mockStorage.On("CreateStock", ctx, mock.AnythingOfType("*inventory.Stock")).Return(nil)
+	mockStorage.On("CreateTransaction", ctx, mock.MatchedBy(func(tx *Transaction) bool {
+		return tx.Reference == "RP-P-1"
+	})).Return(nil)
+	mockStorage.On("CreateBatchOperation", ctx, mock.MatchedBy(func(b *BatchOperation) bool {
+		return len(b.Operations) == 1 && b.SuccessCount == 1
+	})).Return(nil)
+
+	// テスト実行
+	batch, err := manager.ExecuteReplenishmentProposals(ctx, []string{"P-1", "P-2"})
+
+	// アサーション（確保できたP-1のみ移動し、P-2は移動しない）
+	assert.NoError(t, err)
+	assert.Equal(t, 1, batch.SuccessCount)
+	assert.Equal(t, ReplenishmentProposalStatusExecuted, first.Status)
+	assert.Empty(t, second.BatchID)
+	assert.Equal(t, int64(40), source.Quantity)
+	mockStorage.AssertExpectations(t)
+}
+
+// TestAnalyticsEngine_ClassifyItems は出庫金額によるABC分類と需要変動によるXYZ分類のテスト
+func TestAnalyticsEngine_ClassifyItems(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+
+	engine := NewAnalyticsEngine(mockStorage, logger)
+	engine.SetClassificationConfig(ClassificationConfig{
+		WindowDays:     28,
+		ACutoffPercent: 70,
+		BCutoffPercent: 90,
+		BucketDays:     7,
+		XMaxVariation:  0.5,
+		YMaxVariation:  1.0,
+	})
+	ctx := context.Background()
+
+	// テスト用のサンプルデータ（4週間を週単位で集計）
+	windowStart := time.Now().AddDate(0, 0, -28)
+	week := func(n int) time.Time { return windowStart.AddDate(0, 0, 7*n+1) }
+	steadyCost, lumpyCost := NewMoney(20), NewMoney(5)
+	outbound := []Transaction{
+		// STEADY: 毎週10個（出庫金額800、変動なし）
+		{Type: TransactionTypeOutbound, ItemID: "STEADY", Quantity: 10, UnitCost: &steadyCost, CreatedAt: week(0)},
+		{Type: TransactionTypeOutbound, ItemID: "STEADY", Quantity: 10, UnitCost: &steadyCost, CreatedAt: week(1)},
+		{Type: TransactionTypeOutbound, ItemID: "STEADY", Quantity: 10, UnitCost: &steadyCost, CreatedAt: week(2)},
+		{Type: TransactionTypeOutbound, ItemID: "STEADY", Quantity: 10, UnitCost: &steadyCost, CreatedAt: week(3)},
+		// LUMPY: 1週目にまとめて40個（出庫金額200）
+		{Type: TransactionTypeOutbound, ItemID: "LUMPY", Quantity: 40, UnitCost: &lumpyCost, CreatedAt: week(0)},
+		// RARE: 単価なしの出庫は商品マスタ単価10で評価（出庫金額100）
+		{Type: TransactionTypeOutbound, ItemID: "RARE", Quantity: 5, CreatedAt: week(0)},
+		{Type: TransactionTypeOutbound, ItemID: "RARE", Quantity: 5, CreatedAt: week(1)},
+	}
+	stocks := []Stock{
+		{ItemID: "SHELF", LocationID: "WH-A", Quantity: 1000},
+		{ItemID: "STEADY", LocationID: "WH-A", Quantity: 30},
+	}
+
+	// モックの期待値設定
+	mockStorage.On("ListOutboundTransactions", ctx, "WH-A", mock.Anything, mock.Anything).Return(outbound, nil)
+	mockStorage.On("ListStockByLocation", ctx, "WH-A").Return(stocks, nil)
+	mockStorage.On("GetItem", ctx, "RARE").Return(&Item{ID: "RARE", UnitCost: NewMoney(10)}, nil)
+	mockStorage.On("GetItem", ctx, "STEADY").Return(&Item{ID: "STEADY"}, nil)
+	mockStorage.On("GetItem", ctx, "LUMPY").Return(&Item{ID: "LUMPY"}, nil)
+	mockStorage.On("SaveItemClassifications", ctx, mock.MatchedBy(func(c []ItemClassification) bool {
+		return len(c) == 4 && c[0].ItemID == "STEADY" && !c[0].ClassifiedAt.IsZero()
+	})).Return(nil)
+
+	// テスト実行
+	classifications, err := engine.ClassifyItems(ctx, "WH-A")
+
+	// アサーション
+	assert.NoError(t, err)
+	byItem := make(map[string]ItemClassification)
+	for _, c := range classifications {
+		byItem[c.ItemID] = c
+	}
+	// 出庫金額 800 / 200 / 100 / 0 → 累積構成比の閾値 70% / 90%
+	assert.Equal(t, "A", byItem["STEADY"].ABCClass)
+	assert.Equal(t, "X", byItem["STEADY"].XYZClass)
+	assert.Equal(t, NewMoney(800), byItem["STEADY"].ConsumptionValue)
+	assert.Equal(t, "B", byItem["LUMPY"].ABCClass)
+	assert.Equal(t, "Z", byItem["LUMPY"].XYZClass)
+	assert.Equal(t, "C", byItem["RARE"].ABCClass)
+	assert.Equal(t, "Y", byItem["RARE"].XYZClass)
+	assert.Equal(t, NewMoney(100), byItem["RARE"].ConsumptionValue)
+	// 在庫はあるが出庫のない商品は棚にある数量に関係なくC・Z
+	assert.Equal(t, "C", byItem["SHELF"].ABCClass)
+	assert.Equal(t, "Z", byItem["SHELF"].XYZClass)
+	mockStorage.AssertExpectations(t)
+}
+
+// TestAnalyticsEngine_CalculateKPIs は台帳から再構築した平均在庫による回転率・在庫日数・欠品日数・充足率のテスト
+func TestAnalyticsEngine_CalculateKPIs(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+
+	engine := NewAnalyticsEngine(mockStorage, logger)
+	ctx := context.Background()
+
+	// テスト用のサンプルデータ（4日間、期首在庫100）
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 4)
+	entries := []LedgerEntry{
+		// 1日目の12時に全量出庫して欠品
+		{TransactionID: "TX-1", Type: TransactionTypeOutbound, ItemID: "ITEM-1", LocationID: "WH-A", Delta: -100, CreatedAt: from.Add(12 * time.Hour), Consumption: true},
+		// 1日目の終わりに200入庫
+		{TransactionID: "TX-2", Type: TransactionTypeInbound, ItemID: "ITEM-1", LocationID: "WH-A", Delta: 200, CreatedAt: from.Add(24 * time.Hour)},
+		// 3日目の終わりに50出庫
+		{TransactionID: "TX-3", Type: TransactionTypeOutbound, ItemID: "ITEM-1", LocationID: "WH-A", Delta: -50, CreatedAt: from.Add(72 * time.Hour), Consumption: true},
+	}
+
+	// モックの期待値設定
+	mockStorage.On("GetStockAsOf", ctx, "ITEM-1", "WH-A", from).Return(int64(100), nil)
+	mockStorage.On("ListLedgerEntries", ctx, "ITEM-1", "WH-A", from, to).Return(entries, nil)
+	mockStorage.On("GetOrderFillQuantities", ctx, "ITEM-1", from, to).Return(int64(200), int64(180), nil)
+
+	// テスト実行
+	report, err := engine.CalculateKPIs(ctx, "ITEM-1", "WH-A", from, to)
+
+	// アサーション
+	assert.NoError(t, err)
+	assert.Len(t, report.Items, 1)
+	kpi := report.Items[0]
+	assert.Equal(t, int64(100), kpi.OpeningQuantity)
+	assert.Equal(t, int64(150), kpi.ClosingQuantity)
+	// (100×12h + 0×12h + 200×48h + 150×24h) / 96h = 150
+	assert.InDelta(t, 150.0, kpi.AverageInventory, 1e-9)
+	assert.Equal(t, int64(150), kpi.OutboundQuantity)
+	assert.InDelta(t, 1.0, kpi.TurnoverRate, 1e-9)
+	assert.InDelta(t, 365.0/4, kpi.AnnualizedTurnover, 1e-9)
+	// 1日平均出庫 37.5 に対して期末在庫150 → 4日分
+	if assert.NotNil(t, kpi.DaysOfSupply) {
+		assert.InDelta(t, 4.0, *kpi.DaysOfSupply, 1e-9)
+	}
+	assert.Equal(t, 1, kpi.StockoutDays)
+	if assert.NotNil(t, kpi.FillRate) {
+		assert.InDelta(t, 0.9, *kpi.FillRate, 1e-9)
+	}
+	assert.Equal(t, 1, report.Summary.StockoutItems)
+	assert.InDelta(t, 1.0, report.Summary.TurnoverRate, 1e-9)
+	mockStorage.AssertExpectations(t)
+}
+
+// TestAnalyticsEngine_CalculateKPIs_TransfersAndReversals は移動レッグと取消が出庫実績に含まれないことのテスト
+func TestAnalyticsEngine_CalculateKPIs_TransfersAndReversals(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+
+	engine := NewAnalyticsEngine(mockStorage, logger)
+	ctx := context.Background()
+
+	// テスト用のサンプルデータ（2日間、期首在庫100）
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 2)
+	entries := []LedgerEntry{
+		// 出荷（消費）
+		{TransactionID: "TX-1", Type: TransactionTypeOutbound, ItemID: "ITEM-1", LocationID: "WH-A", Delta: -30, CreatedAt: from.Add(2 * time.Hour), Consumption: true},
+		// 別ロケーションへの移動の出庫レッグ
+		{TransactionID: "TX-2", Type: TransactionTypeOutbound, ItemID: "ITEM-1", LocationID: "WH-A", Delta: -20, CreatedAt: from.Add(4 * time.Hour)},
+		// 誤出庫とその取消
+		{TransactionID: "TX-3", Type: TransactionTypeOutbound, ItemID: "ITEM-1", LocationID: "WH-A", Delta: -5, CreatedAt: from.Add(6 * time.Hour)},
+		{TransactionID: "TX-4", Type: TransactionTypeInbound, ItemID: "ITEM-1", LocationID: "WH-A", Delta: 5, CreatedAt: from.Add(7 * time.Hour)},
+		// 入庫の取消（出庫として記録）
+		{TransactionID: "TX-5", Type: TransactionTypeOutbound, ItemID: "ITEM-1", LocationID: "WH-A", Delta: -10, CreatedAt: from.Add(30 * time.Hour)},
+	}
+
+	// モックの期待値設定
+	mockStorage.On("GetStockAsOf", ctx, "ITEM-1", "WH-A", from).Return(int64(100), nil)
+	mockStorage.On("ListLedgerEntries", ctx, "ITEM-1", "WH-A", from, to).Return(entries, nil)
+	mockStorage.On("GetOrderFillQuantities", ctx, "ITEM-1", from, to).Return(int64(0), int64(0), nil)
+
+	// テスト実行
+	report, err := engine.CalculateKPIs(ctx, "ITEM-1", "WH-A", from, to)
+
+	// アサーション（在庫推移には全件を含め、出庫実績は消費分のみ）
+	assert.NoError(t, err)
+	assert.Len(t, report.Items, 1)
+	assert.Equal(t, int64(40), report.Items[0].ClosingQuantity)
+	assert.Equal(t, int64(30), report.Items[0].OutboundQuantity)
+	mockStorage.AssertExpectations(t)
+}
+
+// TestForecastEngine_ForecastDemand_HoltWinters は週次の季節性を持つ需要のHolt-Winters予測のテスト
+func TestAnalyticsEngine_WriteReport_MovementCSV(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+
+	engine := NewAnalyticsEngine(mockStorage, logger)
+	ctx := context.Background()
+
+	// テスト用のサンプルデータ（カンマを含む商品名と入庫・出庫）
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+	filter := ReportFilter{LocationID: "WH-A", Category: "食品", From: from, To: to}
+	location := "WH-A"
+	unitCost := MustParseMoney("120.5")
+	transactions := []Transaction{
+		{ID: "TX-1", Type: TransactionTypeInbound, ItemID: "ITEM-1", ToLocation: &location, Quantity: 10, UnitCost: &unitCost, Reference: "PO-1", CreatedAt: from.Add(time.Hour), CreatedBy: "user"},
+		{ID: "TX-2", Type: TransactionTypeOutbound, ItemID: "ITEM-1", FromLocation: &location, Quantity: 4, CreatedAt: from.Add(2 * time.Hour), CreatedBy: "user"},
+	}
+	items := map[string]Item{"ITEM-1": {ID: "ITEM-1", Name: "りんご, 青森産", Category: "食品"}}
+
+	// モックの期待値設定
+	mockStorage.On("IterateTransactions", ctx, filter).Return(transactions, items, nil)
+
+	// テスト実行
+	var buffer bytes.Buffer
+	err := engine.WriteReport(ctx, &buffer, ReportRequest{Type: ReportTypeMovement, Format: ReportFormatCSV, Filter: filter})
+
+	// アサーション
+	assert.NoError(t, err)
+	output := buffer.String()
+	assert.True(t, strings.HasPrefix(output, "\xEF\xBB\xBF"))
+	lines := strings.Split(strings.TrimSuffix(strings.TrimPrefix(output, "\xEF\xBB\xBF"), "\n"), "\n")
+	assert.Len(t, lines, 3)
+	assert.Equal(t, "日時,トランザクションID,種別,商品ID,商品名,カテゴリ,移動元,移動先,数量,単価,参照番号,ロット番号,実行者", lines[0])
+	assert.Equal(t, `2026-01-01 01:00:00,TX-1,inbound,ITEM-1,"りんご, 青森産",食品,,WH-A,10,120.5,PO-1,,user`, lines[1])
+	assert.Equal(t, `2026-01-01 02:00:00,TX-2,outbound,ITEM-1,"りんご, 青森産",食品,WH-A,,4,,,,user`, lines[2])
+	mockStorage.AssertExpectations(t)
+}
+
+func TestAnalyticsEngine_WriteReport_ValuationJSON(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+
+	engine := NewAnalyticsEngine(mockStorage, logger)
+	ctx := context.Background()
+
+	// テスト用のサンプルデータ
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	filter := ReportFilter{From: from, To: to}
+	summaries := []StockMovementSummary{
+		{ItemID: "ITEM-1", LocationID: "WH-A", OpeningQuantity: 100, InboundQuantity: 50, OutboundQuantity: 30, AdjustmentQuantity: -5, ClosingQuantity: 115},
+	}
+	items := map[string]Item{"ITEM-1": {ID: "ITEM-1", Name: "商品1", Category: "雑貨", UnitCost: NewMoney(200)}}
+
+	// モックの期待値設定
+	mockStorage.On("IterateStockMovementSummaries", ctx, filter).Return(summaries, items, nil)
+
+	// テスト実行
+	var buffer bytes.Buffer
+	err := engine.WriteReport(ctx, &buffer, ReportRequest{Type: ReportTypeValuation, Format: ReportFormatJSON, Filter: filter})
+
+	// アサーション
+	assert.NoError(t, err)
+	var rows []map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buffer.Bytes(), &rows))
+	assert.Len(t, rows, 1)
+	assert.Equal(t, "ITEM-1", rows[0]["item_id"])
+	assert.Equal(t, float64(100), rows[0]["opening_quantity"])
+	assert.Equal(t, float64(115), rows[0]["closing_quantity"])
+	assert.Equal(t, float64(20000), rows[0]["opening_value"])
+	assert.Equal(t, float64(23000), rows[0]["closing_value"])
+	mockStorage.AssertExpectations(t)
+}
+
+// TestAnalyticsEngine_GenerateStockReportAsOf は時点在庫レポートの数量を商品の小数桁数で出力するテスト
+func TestAnalyticsEngine_GenerateStockReportAsOf(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+
+	engine := NewAnalyticsEngine(mockStorage, logger)
+	ctx := context.Background()
+	asOf := time.Date(2024, 3, 31, 23, 59, 59, 0, time.UTC)
+
+	mockStorage.On("ListStockAsOfByLocation", ctx, "WH-A", asOf).Return([]StockSnapshot{
+		{ItemID: "FLOUR", LocationID: "WH-A", Quantity: 1250, AsOf: asOf},
+	}, nil)
+	mockStorage.On("GetItem", ctx, "FLOUR").Return(&Item{ID: "FLOUR", QuantityPrecision: 3}, nil)
+
+	data, err := engine.GenerateStockReportAsOf(ctx, "WH-A", asOf)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "FLOUR,1.250,")
+	mockStorage.AssertExpectations(t)
+}
+
+func TestAnalyticsEngine_WriteReport_StockXLSX(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+
+	engine := NewAnalyticsEngine(mockStorage, logger)
+	ctx := context.Background()
+
+	// テスト用のサンプルデータ（XMLのエスケープが必要な商品名）
+	filter := ReportFilter{LocationID: "WH-A"}
+	stocks := []Stock{{ItemID: "ITEM-1", LocationID: "WH-A", Quantity: 12, Available: 12, UpdatedAt: time.Now()}}
+	items := map[string]Item{"ITEM-1": {ID: "ITEM-1", Name: "A&B <セット>", UnitCost: NewMoney(10)}}
+
+	// モックの期待値設定
+	mockStorage.On("IterateStock", ctx, filter).Return(stocks, items, nil)
+
+	// テスト実行
+	var buffer bytes.Buffer
+	err := engine.WriteReport(ctx, &buffer, ReportRequest{Type: ReportTypeStock, Format: ReportFormatXLSX, Filter: filter})
+
+	// アサーション
+	assert.NoError(t, err)
+	archive, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	assert.NoError(t, err)
+	parts := map[string]string{}
+	for _, file := range archive.File {
+		reader, err := file.Open()
+		assert.NoError(t, err)
+		content, err := io.ReadAll(reader)
+		assert.NoError(t, err)
+		reader.Close()
+		parts[file.Name] = string(content)
+	}
+	assert.Contains(t, parts, "[Content_Types].xml")
+	assert.Contains(t, parts, "xl/workbook.xml")
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, sheet, `<c r="A1" t="inlineStr"><is><t>商品ID</t></is></c>`)
+	assert.Contains(t, sheet, `<c r="B2" t="inlineStr"><is><t>A&amp;B &lt;セット&gt;</t></is></c>`)
+	assert.Contains(t, sheet, `<c r="F2"><v>12</v></c>`)
+	assert.Contains(t, sheet, `<c r="J2"><v>120</v></c>`)
+	mockStorage.AssertExpectations(t)
+}
+
+func TestAnalyticsEngine_WriteReport_Validation(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+
+	engine := NewAnalyticsEngine(mockStorage, logger)
+	ctx := context.Background()
+	now := time.Now()
+
+	// 期間が逆転している場合
+	var buffer bytes.Buffer
+	err := engine.WriteReport(ctx, &buffer, ReportRequest{Type: ReportTypeTurnover, Format: ReportFormatCSV, Filter: ReportFilter{From: now, To: now.AddDate(0, 0, -1)}})
+	assert.IsType(t, &ValidationError{}, err)
+
+	// 未対応の出力形式
+	err = engine.WriteReport(ctx, &buffer, ReportRequest{Type: ReportTypeStock, Format: "pdf"})
+	assert.IsType(t, &ValidationError{}, err)
+
+	// 未対応のレポートタイプ
+	err = engine.WriteReport(ctx, &buffer, ReportRequest{Type: "unknown", Format: ReportFormatCSV})
+	assert.IsType(t, &ValidationError{}, err)
+
+	// 出力前に失敗したため何も書き込まれていない
+	assert.Zero(t, buffer.Len())
+	mockStorage.AssertExpectations(t)
+}
+
+func TestAnalyticsEngine_GetAgingReport(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+
+	engine := NewAnalyticsEngine(mockStorage, logger)
+	ctx := context.Background()
+
+	// テスト用のサンプルデータ
+	now := time.Now()
+	daysAgo := func(days int) time.Time { return now.Add(-time.Duration(days)*24*time.Hour - time.Hour) }
+	recentMovement := daysAgo(3)
+	oldMovement := daysAgo(200)
+	cost := NewMoney(120)
+	positions := []StockPosition{
+		// 在庫100：直近の受入60（10日前）と40（100日前、残りは受入履歴なし）
+		{ItemID: "ITEM-1", LocationID: "WH-A", Quantity: 120, UnitCost: NewMoney(100), LastMovementAt: &recentMovement},
+		// 200日間移動のない在庫
+		{ItemID: "ITEM-2", LocationID: "WH-A", Quantity: 5, UnitCost: NewMoney(50), LastMovementAt: &oldMovement},
+		{ItemID: "ITEM-1", LocationID: "WH-B", Quantity: 10, UnitCost: NewMoney(100)},
+	}
+	layers := []ReceiptLayer{
+		{ItemID: "ITEM-1", LocationID: "WH-A", TransactionID: "TX-3", Quantity: 60, UnitCost: &cost, ReceivedAt: daysAgo(10)},
+		{ItemID: "ITEM-1", LocationID: "WH-A", TransactionID: "TX-2", Quantity: 40, ReceivedAt: daysAgo(100)},
+		{ItemID: "ITEM-2", LocationID: "WH-A", TransactionID: "TX-1", Quantity: 8, ReceivedAt: daysAgo(200)},
+		{ItemID: "ITEM-1", LocationID: "WH-B", TransactionID: "TX-4", Quantity: 10, ReceivedAt: daysAgo(31)},
+	}
+
+	// モックの期待値設定
+	mockStorage.On("ListStockPositions", ctx, "").Return(positions, nil)
+	mockStorage.On("ListReceiptLayers", ctx, "").Return(layers, nil)
+
+	// テスト実行
+	report, err := engine.GetAgingReport(ctx, "", 0)
+
+	// アサーション
+	assert.NoError(t, err)
+	assert.Equal(t, 180, report.DeadStockDays)
+	assert.Len(t, report.Items, 3)
+
+	item := report.Items[0]
+	assert.Equal(t, []string{"0-30", "31-90", "91-180", "181+"},
+		[]string{item.Buckets[0].Label, item.Buckets[1].Label, item.Buckets[2].Label, item.Buckets[3].Label})
+	assert.Equal(t, int64(60), item.Buckets[0].Quantity)
+	assert.Equal(t, NewMoney(7200), item.Buckets[0].Value)
+	assert.Equal(t, int64(40), item.Buckets[2].Quantity)
+	assert.Equal(t, int64(20), item.Buckets[3].Quantity) // 受入履歴で説明できない数量
+	assert.Equal(t, NewMoney(7200+4000+2000), item.Value)
+	assert.InDelta(t, 46.0, item.AverageAgeDays, 0.001) // (60×10 + 40×100) / 100
+	assert.False(t, item.IsDeadStock)
+
+	dead := report.Items[1]
+	assert.True(t, dead.IsDeadStock)
+	assert.Equal(t, 200, *dead.DaysSinceMovement)
+	assert.Equal(t, int64(5), dead.Buckets[3].Quantity)
+
+	// 移動履歴のない在庫は滞留在庫
+	assert.True(t, report.Items[2].IsDeadStock)
+	assert.Equal(t, int64(10), report.Items[2].Buckets[1].Quantity)
+
+	assert.Len(t, report.Locations, 2)
+	assert.Equal(t, "WH-A", report.Locations[0].LocationID)
+	assert.Equal(t, 1, report.Locations[0].DeadStockItems)
+	assert.Equal(t, NewMoney(250), report.Locations[0].ValueAtRisk)
+	assert.Equal(t, NewMoney(1000), report.Locations[1].ValueAtRisk)
+	assert.Equal(t, NewMoney(1250), report.ValueAtRisk)
+	assert.Equal(t, NewMoney(13200+250+1000), report.TotalValue)
+	mockStorage.AssertExpectations(t)
+}
+
+func TestCronSchedule_Next(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	tests := []struct {
+		name       string
+		expression string
+		after      time.Time
+		expected   time.Time
+	}{
+		{"毎日6時", "0 6 * * *", time.Date(2026, 3, 10, 6, 0, 0, 0, jst), time.Date(2026, 3, 11, 6, 0, 0, 0, jst)},
+		{"15分間隔", "*/15 * * * *", time.Date(2026, 3, 10, 6, 7, 30, 0, jst), time.Date(2026, 3, 10, 6, 15, 0, 0, jst)},
+		{"平日9時", "0 9 * * 1-5", time.Date(2026, 3, 13, 10, 0, 0, 0, jst), time.Date(2026, 3, 16, 9, 0, 0, 0, jst)},
+		{"月末23時（閏年）", "0 23 L * *", time.Date(2028, 2, 1, 0, 0, 0, 0, jst), time.Date(2028, 2, 29, 23, 0, 0, 0, jst)},
+		{"月末マクロ", "@monthend", time.Date(2026, 4, 30, 0, 0, 0, 0, jst), time.Date(2026, 5, 31, 0, 0, 0, 0, jst)},
+		{"日と曜日はいずれかに一致", "0 0 1 * 0", time.Date(2026, 3, 2, 0, 0, 0, 0, jst), time.Date(2026, 3, 8, 0, 0, 0, 0, jst)},
+		{"曜日の7は日曜", "30 8 * * 7", time.Date(2026, 3, 10, 0, 0, 0, 0, jst), time.Date(2026, 3, 15, 8, 30, 0, 0, jst)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseCronSchedule(tt.expression)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, schedule.Next(tt.after))
+		})
+	}
+
+	// 不正なcron式
+	for _, expression := range []string{"", "0 6 * *", "60 * * * *", "0 0 0 * *", "*/0 * * * *", "0 0 5-1 * *"} {
+		_, err := ParseCronSchedule(expression)
+		assert.IsType(t, &ValidationError{}, err, expression)
+	}
+
+	// 存在しない日付はゼロ値
+	schedule, err := ParseCronSchedule("0 0 30 2 *")
+	assert.NoError(t, err)
+	assert.True(t, schedule.Next(time.Now()).IsZero())
+}
+
+func TestReportScheduler_RunDueReportSchedules(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+
+	archiveDir := t.TempDir()
+	scheduler := NewReportScheduler(mockStorage, NewAnalyticsEngine(mockStorage, logger), archiveDir, logger)
+	ctx := context.Background()
+
+	// 月末に前回実行以降の評価レポートを生成するスケジュール
+	lastRunAt := time.Date(2026, 2, 28, 23, 0, 0, 0, time.UTC)
+	scheduledAt := time.Date(2026, 3, 31, 23, 0, 0, 0, time.UTC)
+	now := scheduledAt.Add(30 * time.Second)
+	schedule := ReportSchedule{
+		ID:             "RS-1",
+		Name:           "月末評価レポート",
+		CronExpression: "0 23 L * *",
+		Timezone:       "UTC",
+		ReportType:     ReportTypeValuation,
+		Format:         ReportFormatCSV,
+		LocationID:     "WH-A",
+		IsActive:       true,
+		NextRunAt:      &scheduledAt,
+		LastRunAt:      &lastRunAt,
+	}
+	nextRunAt := time.Date(2026, 4, 30, 23, 0, 0, 0, time.UTC)
+	filter := ReportFilter{LocationID: "WH-A", From: lastRunAt, To: scheduledAt}
+	summaries := []StockMovementSummary{{ItemID: "ITEM-1", LocationID: "WH-A", OpeningQuantity: 10, InboundQuantity: 5, ClosingQuantity: 15}}
+	items := map[string]Item{"ITEM-1": {ID: "ITEM-1", Name: "商品1", UnitCost: NewMoney(100)}}
+
+	// モックの期待値設定
+	mockStorage.On("ListDueReportSchedules", ctx, now).Return([]ReportSchedule{schedule}, nil)
+	mockStorage.On("ClaimReportScheduleRun", ctx, "RS-1", scheduledAt, nextRunAt).Return(true, nil)
+	mockStorage.On("IterateStockMovementSummaries", ctx, filter).Return(summaries, items, nil)
+	var archived *ReportArchive
+	mockStorage.On("CreateReportArchive", ctx, mock.AnythingOfType("*inventory.ReportArchive")).
+		Run(func(args mock.Arguments) { archived = args.Get(1).(*ReportArchive) }).Return(nil)
+	mockStorage.On("UpdateReportSchedule", ctx, mock.MatchedBy(func(s *ReportSchedule) bool {
+		return s.ID == "RS-1" && s.NextRunAt.Equal(nextRunAt) && s.LastRunAt.Equal(scheduledAt) && s.LastError == ""
+	})).Return(nil)
+
+	// テスト実行
+	generated, err := scheduler.RunDueReportSchedules(ctx, now)
+
+	// アサーション
+	assert.NoError(t, err)
+	assert.Equal(t, 1, generated)
+	assert.Equal(t, "RS-1", archived.ScheduleID)
+	assert.Equal(t, "valuation_WH-A_20260331-2300.csv", archived.FileName)
+	assert.Equal(t, lastRunAt, archived.PeriodFrom)
+	assert.Len(t, archived.Checksum, 64)
+
+	// アーカイブしたファイルを開ける
+	mockStorage.On("GetReportArchive", ctx, archived.ID).Return(archived, nil)
+	_, file, err := scheduler.OpenReportArchive(ctx, archived.ID)
+	assert.NoError(t, err)
+	content, err := io.ReadAll(file)
+	file.Close()
+	assert.NoError(t, err)
+	assert.Equal(t, archived.SizeBytes, int64(len(content)))
+	assert.Contains(t, string(content), "ITEM-1,商品1")
+	mockStorage.AssertExpectations(t)
+}
+
+func TestReportScheduler_CreateReportSchedule_Validation(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+
+	scheduler := NewReportScheduler(mockStorage, NewAnalyticsEngine(mockStorage, logger), t.TempDir(), logger)
+	ctx := context.Background()
+
+	invalid := []*ReportSchedule{
+		{Name: "", CronExpression: "0 6 * * *", ReportType: ReportTypeStock},
+		{Name: "日次", CronExpression: "0 6 * *", ReportType: ReportTypeStock},
+		{Name: "日次", CronExpression: "0 6 * * *", ReportType: "unknown"},
+		{Name: "日次", CronExpression: "0 6 * * *", ReportType: ReportTypeStock, Format: "pdf"},
+		{Name: "日次", CronExpression: "0 6 * * *", ReportType: ReportTypeABC},
+		{Name: "日次", CronExpression: "0 6 * * *", ReportType: ReportTypeStock, Timezone: "Mars/Olympus"},
+		{Name: "日次", CronExpression: "0 0 31 2 *", ReportType: ReportTypeStock},
+	}
+	for _, schedule := range invalid {
+		err := scheduler.CreateReportSchedule(ctx, schedule)
+		assert.IsType(t, &ValidationError{}, err, schedule.CronExpression)
+	}
+
+	// 正常なスケジュールは既定の出力形式と次回実行予定日時が設定される
+	mockStorage.On("CreateReportSchedule", ctx, mock.AnythingOfType("*inventory.ReportSchedule")).Return(nil)
+	schedule := &ReportSchedule{Name: "日次在庫", CronExpression: "@daily", ReportType: ReportTypeStock, IsActive: true}
+	err := scheduler.CreateReportSchedule(ctx, schedule)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, schedule.ID)
+	assert.Equal(t, ReportFormatCSV, schedule.Format)
+	assert.NotNil(t, schedule.NextRunAt)
+	assert.True(t, schedule.NextRunAt.After(time.Now()))
+	mockStorage.AssertExpectations(t)
+}
+
+func TestForecastEngine_ForecastDemand_HoltWinters(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+
+	engine := NewForecastEngine(mockStorage, logger)
+	config := DefaultForecastConfig()
+	config.HistoryDays = 28
+	engine.SetForecastConfig(config)
+	ctx := context.Background()
+
+	// テスト用のサンプルデータ（4週間、週末のみ出庫）
+	start := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -28)
+	pattern := []int64{0, 0, 0, 0, 0, 10, 20}
+	var outbound []Transaction
+	for day := 0; day < 28; day++ {
+		if quantity := pattern[day%7]; quantity > 0 {
+			outbound = append(outbound, Transaction{Type: TransactionTypeOutbound, ItemID: "ITEM-S", Quantity: quantity, CreatedAt: start.AddDate(0, 0, day).Add(12 * time.Hour)})
+		}
+	}
+
+	// モックの期待値設定
+	mockStorage.On("ListOutboundTransactions", ctx, "WH-A", start, start.AddDate(0, 0, 28)).Return(outbound, nil)
+
+	// テスト実行
+	forecast, err := engine.ForecastDemand(ctx, "ITEM-S", "WH-A", ForecastMethodHoltWinters, 7)
+
+	// アサーション（季節パターンがそのまま予測され、予測誤差はない）
+	assert.NoError(t, err)
+	assert.Equal(t, ForecastMethodHoltWinters, forecast.Method)
+	for i, quantity := range pattern {
+		assert.InDelta(t, float64(quantity), forecast.Forecast[i], 1e-9)
+	}
+	assert.InDelta(t, 30.0/7, forecast.ForecastDailyDemand, 1e-9)
+	assert.InDelta(t, 0.0, forecast.DemandStdDev, 1e-9)
+
+	// 未対応の予測手法
+	_, err = engine.ForecastDemand(ctx, "ITEM-S", "WH-A", ForecastMethod("arima"), 7)
+	assert.IsType(t, &ValidationError{}, err)
+	mockStorage.AssertExpectations(t)
+}
+
+// TestForecastEngine_GetReorderSuggestions は発注点・EOQに基づく発注提案のテスト
+func TestForecastEngine_GetReorderSuggestions(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+
+	engine := NewForecastEngine(mockStorage, logger)
+	config := DefaultForecastConfig()
+	config.HistoryDays = 28
+	config.DefaultLeadTimeDays = 7
+	config.OrderingCost = 50
+	engine.SetForecastConfig(config)
+	ctx := context.Background()
+
+	// テスト用のサンプルデータ（LOW・HIGHとも毎日10個出庫、IDLEは出庫なし）
+	start := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -28)
+	var outbound []Transaction
+	for day := 0; day < 28; day++ {
+		for _, itemID := range []string{"LOW", "HIGH"} {
+			outbound = append(outbound, Transaction{Type: TransactionTypeOutbound, ItemID: itemID, Quantity: 10, CreatedAt: start.AddDate(0, 0, day).Add(12 * time.Hour)})
+		}
+	}
+	stocks := []Stock{
+		{ItemID: "LOW", LocationID: "WH-A", Quantity: 55, Reserved: 5},
+		{ItemID: "HIGH", LocationID: "WH-A", Quantity: 500},
+		{ItemID: "IDLE", LocationID: "WH-A", Quantity: 3},
+	}
+
+	// モックの期待値設定
+	mockStorage.On("ListOutboundTransactions", ctx, "WH-A", mock.Anything, mock.Anything).Return(outbound, nil)
+	mockStorage.On("ListStockByLocation", ctx, "WH-A").Return(stocks, nil)
+	mockStorage.On("GetItemLeadTimeDays", ctx, "LOW").Return(0, nil)
+	mockStorage.On("GetItemLeadTimeDays", ctx, "HIGH").Return(10, nil)
+	mockStorage.On("GetOnOrderQuantity", ctx, "LOW", "WH-A").Return(int64(10), nil)
+	mockStorage.On("GetOnOrderQuantity", ctx, "HIGH", "WH-A").Return(int64(0), nil)
+	mockStorage.On("GetItem", ctx, "LOW").Return(&Item{ID: "LOW", UnitCost: NewMoney(20)}, nil)
+	mockStorage.On("GetItem", ctx, "HIGH").Return(&Item{ID: "HIGH", UnitCost: NewMoney(20)}, nil)
+
+	// テスト実行
+	report, err := engine.GetReorderSuggestions(ctx, "WH-A", ForecastMethodMovingAverage)
+
+	// アサーション
+	assert.NoError(t, err)
+	assert.Len(t, report.Items, 1)
+	suggestion := report.Items[0]
+	assert.Equal(t, "LOW", suggestion.ItemID)
+	// 在庫ポジション 55 - 5 + 10 = 60、リードタイム7日（既定値）の需要70、需要が一定のため安全在庫0
+	assert.Equal(t, int64(60), suggestion.InventoryPosition)
+	assert.Equal(t, 7, suggestion.LeadTimeDays)
+	assert.InDelta(t, 70.0, suggestion.LeadTimeDemand, 1e-9)
+	assert.InDelta(t, 0.0, suggestion.SafetyStock, 1e-9)
+	assert.InDelta(t, 70.0, suggestion.ReorderPoint, 1e-9)
+	// EOQ = √(2 × 3650 × 50 / (20 × 0.25)) ≈ 270.2
+	assert.InDelta(t, 270.185, suggestion.EOQ, 1e-3)
+	assert.True(t, suggestion.NeedsOrder)
+	assert.Equal(t, int64(271), suggestion.SuggestedQuantity)
+	mockStorage.AssertNotCalled(t, "GetItem", ctx, "IDLE")
+}
+
+// TestForecastEngine_GetReorderSuggestions_OnOrderByLocation は発注残を入荷先ロケーションにのみ計上するテスト
+func TestForecastEngine_GetReorderSuggestions_OnOrderByLocation(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+
+	engine := NewForecastEngine(mockStorage, logger)
+	config := DefaultForecastConfig()
+	config.HistoryDays = 28
+	config.DefaultLeadTimeDays = 7
+	engine.SetForecastConfig(config)
+	ctx := context.Background()
+
+	// テスト用のサンプルデータ（両ロケーションとも毎日10個出庫・在庫50、発注残100はWH-B宛て）
+	start := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -28)
+	var outbound []Transaction
+	for day := 0; day < 28; day++ {
+		outbound = append(outbound, Transaction{Type: TransactionTypeOutbound, ItemID: "ITEM-M", Quantity: 10, CreatedAt: start.AddDate(0, 0, day).Add(12 * time.Hour)})
+	}
+
+	// モックの期待値設定
+	for _, locationID := range []string{"WH-A", "WH-B"} {
+		mockStorage.On("ListOutboundTransactions", ctx, locationID, mock.Anything, mock.Anything).Return(outbound, nil)
+		mockStorage.On("ListStockByLocation", ctx, locationID).Return([]Stock{{ItemID: "ITEM-M", LocationID: locationID, Quantity: 50}}, nil)
+	}
+	mockStorage.On("GetItemLeadTimeDays", ctx, "ITEM-M").Return(0, nil)
+	mockStorage.On("GetOnOrderQuantity", ctx, "ITEM-M", "WH-A").Return(int64(0), nil)
+	mockStorage.On("GetOnOrderQuantity", ctx, "ITEM-M", "WH-B").Return(int64(100), nil)
+	mockStorage.On("GetItem", ctx, "ITEM-M").Return(&Item{ID: "ITEM-M", UnitCost: NewMoney(20)}, nil)
+
+	// テスト実行・アサーション
+	// WH-Aは在庫ポジション50が発注点70を下回るため発注対象
+	report, err := engine.GetReorderSuggestions(ctx, "WH-A", ForecastMethodMovingAverage)
+	assert.NoError(t, err)
+	assert.Len(t, report.Items, 1)
+	assert.Equal(t, int64(50), report.Items[0].InventoryPosition)
+
+	// WH-Bは自ロケーション宛ての発注残により在庫ポジション150となり発注不要
+	report, err = engine.GetReorderSuggestions(ctx, "WH-B", ForecastMethodMovingAverage)
+	assert.NoError(t, err)
+	assert.Empty(t, report.Items)
+	mockStorage.AssertExpectations(t)
+}
+
+// TestMoney_ParseAndFormat は金額の解析・表示・JSON・DB読み込みのテスト
+func TestMoney_ParseAndFormat(t *testing.T) {
+	for input, expected := range map[string]string{
+		"1234.5678":   "1234.5678",
+		"0.1":         "0.1",
+		"-12.30":      "-12.3",
+		"100":         "100",
+		".5":          "0.5",
+		"0.00005":     "0.0001", // 5桁目は四捨五入
+		"-0.00004999": "0",
+	} {
+		m, err := ParseMoney(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, m.String(), input)
+	}
+
+	for _, input := range []string{"", "abc", "1.2.3", "1,000", "-"} {
+		_, err := ParseMoney(input)
+		assert.Error(t, err, input)
+	}
+
+	// JSONは数値として正確に往復する
+	item := Item{ID: "ITEM-1", UnitCost: MustParseMoney("1234.5678")}
+	encoded, err := json.Marshal(item)
+	assert.NoError(t, err)
+	assert.Contains(t, string(encoded), `"unit_cost":1234.5678`)
+
+	var decoded Item
+	assert.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, item.UnitCost, decoded.UnitCost)
+	assert.NoError(t, json.Unmarshal([]byte(`{"unit_cost":"0.1"}`), &decoded))
+	assert.Equal(t, MustParseMoney("0.1"), decoded.UnitCost)
+
+	// DECIMAL列は文字列表現から読み込む
+	var scanned Money
+	assert.NoError(t, scanned.Scan([]byte("1234.5678")))
+	assert.Equal(t, Money(12345678), scanned)
+	assert.NoError(t, scanned.Scan(nil))
+	assert.Equal(t, Money(0), scanned)
+}
+
+// TestMoney_Round は通貨ごとの丸めルールのテスト
+func TestMoney_Round(t *testing.T) {
+	jpy, ok := LookupCurrencyRule("jpy")
+	assert.True(t, ok)
+	eur, _ := LookupCurrencyRule("EUR")
+	_, ok = LookupCurrencyRule("XXX")
+	assert.False(t, ok)
+
+	// 円は整数に四捨五入
+	assert.Equal(t, "101", MustParseMoney("100.5").Round(jpy).String())
+	assert.Equal(t, "100", MustParseMoney("100.4999").Round(jpy).String())
+	assert.Equal(t, "-101", MustParseMoney("-100.5").Round(jpy).String())
+
+	// ユーロは小数2桁に偶数丸め
+	assert.Equal(t, "0.12", MustParseMoney("0.125").Round(eur).String())
+	assert.Equal(t, "0.14", MustParseMoney("0.135").Round(eur).String())
+	assert.Equal(t, "0.13", MustParseMoney("0.1251").Round(eur).String())
+
+	down := CurrencyRule{Code: "JPY", MinorDigits: 0, Rounding: RoundingDown}
+	assert.Equal(t, "99", MustParseMoney("99.99").Round(down).String())
+	up := CurrencyRule{Code: "JPY", MinorDigits: 0, Rounding: RoundingUp}
+	assert.Equal(t, "100", MustParseMoney("99.01").Round(up).String())
+
+	// 平均原価の除算は小数4桁に四捨五入
+	assert.Equal(t, "100.6667", NewMoney(302).Div(3).String())
+}
+
+// TestValuationEngine_CalculateTotalValue_MatchesDatabaseSum は評価額合計がDBのSUM(quantity * unit_cost)と一致することのテスト
+func TestValuationEngine_CalculateTotalValue_MatchesDatabaseSum(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+
+	engine := NewValuationEngine(mockStorage, logger)
+	ctx := context.Background()
+
+	// 浮動小数点数では誤差が出る単価（0.1 × 3 + 0.2 × 3 ≠ 0.9）
+	items := []struct {
+		id       string
+		cost     string
+		quantity int64
+	}{
+		{"ITEM-1", "0.1", 3},
+		{"ITEM-2", "0.2", 3},
+		{"ITEM-3", "1234.5678", 7},
+		{"ITEM-4", "0.0333", 1000003},
+	}
+
+	var stocks []Stock
+	for _, item := range items {
+		stock := Stock{ItemID: item.id, LocationID: "WH-A", Quantity: item.quantity}
+		stocks = append(stocks, stock)
+		mockStorage.On("GetStock", ctx, item.id, "WH-A").Return(&stock, nil)
+		mockStorage.On("GetItem", ctx, item.id).Return(&Item{ID: item.id, UnitCost: MustParseMoney(item.cost)}, nil)
+	}
+	mockStorage.On("ListStockByLocation", ctx, "WH-A").Return(stocks, nil)
+
+	total, err := engine.CalculateTotalValue(ctx, "WH-A", ValuationMethodStandard)
+
+	// SELECT SUM(s.quantity * i.unit_cost) の結果:
+	// 0.3 + 0.6 + 8641.9746 + 33300.0999 = 41942.9745
+	assert.NoError(t, err)
+	assert.Equal(t, "41942.9745", total.String())
+	assert.Equal(t, MustParseMoney("41942.9745"), total)
+
+	// 表示用の丸めは設定通貨に従う
+	assert.Equal(t, "JPY", engine.Currency().Code)
+	assert.Equal(t, "41943", total.Round(engine.Currency()).String())
+	assert.NoError(t, engine.SetCurrency("EUR"))
+	assert.Equal(t, "41942.97", total.Round(engine.Currency()).String())
+	assert.Error(t, engine.SetCurrency("XXX"))
+	mockStorage.AssertExpectations(t)
+}
+
+// TestQuantity_ParseAndFormat は小数数量の解析と表示のテスト
+func TestQuantity_ParseAndFormat(t *testing.T) {
+	units, err := ParseQuantity("1.25", 3)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1250), units)
+	assert.Equal(t, "1.250", FormatQuantity(units, 3))
+
+	units, err = ParseQuantity("-0.5", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(-5), units)
+	assert.Equal(t, "-0.5", FormatQuantity(units, 1))
+
+	// 末尾の0は精度を超えても許可、有効桁の超過は丸めずにエラー
+	units, err = ParseQuantity("2.500", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(25), units)
+	_, err = ParseQuantity("0.0001", 3)
+	assert.IsType(t, &ValidationError{}, err)
+	_, err = ParseQuantity("1.5", 0)
+	assert.IsType(t, &ValidationError{}, err)
+	_, err = ParseQuantity("abc", 2)
+	assert.IsType(t, &ValidationError{}, err)
+
+	assert.Equal(t, "42", FormatQuantity(42, 0))
+	assert.Equal(t, "0.007", DecimalQuantity{Units: 7, Precision: 3}.String())
+
+	// 重量1.5kg × 単価1200/kg = 1800
+	assert.Equal(t, NewMoney(1800), NewMoney(1200).MulQuantity(1500, 3))
+	assert.Equal(t, NewMoney(3600), NewMoney(1200).MulQuantity(3, 0))
+}
+
+// TestManager_Remove_FractionalLowStock は小数数量の商品で閾値が保存単位に換算されることのテスト
+func TestManager_Remove_FractionalLowStock(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{
+		AllowNegativeStock: false,
+		DefaultLocation:    "DEFAULT",
+		LowStockThreshold:  10,
+	}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	// 小数桁数3（kg単位、0.001kg刻み）の商品: 20.000kg → 9.500kg
+	item := &Item{ID: "FLOUR", Name: "小麦粉", UnitCost: NewMoney(300), QuantityPrecision: 3}
+	stock := &Stock{ItemID: "FLOUR", LocationID: "WH-A", Quantity: 20000, Available: 20000, Version: 1}
+
+	mockStorage.On("GetItem", ctx, "FLOUR").Return(item, nil)
+	mockStorage.On("GetLocation", ctx, "WH-A").Return(&Location{ID: "WH-A"}, nil)
+	mockStorage.On("GetStock", ctx, "FLOUR", "WH-A").Return(stock, nil)
+	mockStorage.On("UpdateStock", ctx, mock.AnythingOfType("*inventory.Stock")).Return(nil)
+	mockStorage.On("CreateTransaction", ctx, mock.MatchedBy(func(tx *Transaction) bool {
+		return tx.Quantity == 10500
+	})).Return(nil)
+	mockStorage.On("CreateAlert", ctx, mock.MatchedBy(func(alert *StockAlert) bool {
+		return alert.CurrentQty == 9500 && alert.Threshold == 10000 &&
+			strings.Contains(alert.Message, "9.500") && strings.Contains(alert.Message, "10.000")
+	})).Return(nil)
+
+	err := manager.Remove(ctx, "FLOUR", "WH-A", 10500, "TEST-REF")
+
+	assert.NoError(t, err)
+	mockStorage.AssertExpectations(t)
+}
+
+// TestManager_Add_QuantityPrecisionRange は上限が商品の小数桁数に応じて換算されることのテスト
+func TestManager_Add_QuantityPrecisionRange(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{DefaultLocation: "DEFAULT"}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	mockStorage.On("GetItem", ctx, "FLOUR").Return(&Item{ID: "FLOUR", QuantityPrecision: 3}, nil)
+	mockStorage.On("GetLocation", ctx, "WH-A").Return(&Location{ID: "WH-A"}, nil)
+
+	// 小数桁数0では上限を超える保存値でも、小数桁数3では999,999.999kgとして許可
+	assert.IsType(t, &ValidationError{}, ValidateQuantity(999999999000, false))
+	assert.NoError(t, ValidateItemQuantity(999999999000, 3, false))
+
+	err := manager.Add(ctx, "FLOUR", "WH-A", 999999999001*1000, "TEST-REF")
+	assert.IsType(t, &ValidationError{}, err)
+}
+
+// TestValuationEngine_CalculateValue_FractionalQuantity は小数数量の評価額計算のテスト
+func TestValuationEngine_CalculateValue_FractionalQuantity(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+
+	engine := NewValuationEngine(mockStorage, logger)
+	ctx := context.Background()
+
+	mockStorage.On("GetItem", ctx, "FLOUR").Return(&Item{ID: "FLOUR", UnitCost: NewMoney(1200), QuantityPrecision: 3}, nil)
+	mockStorage.On("GetStock", ctx, "FLOUR", "WH-A").Return(&Stock{ItemID: "FLOUR", LocationID: "WH-A", Quantity: 1500}, nil)
+
+	value, err := engine.CalculateValue(ctx, "FLOUR", "WH-A", ValuationMethodStandard)
+
+	assert.NoError(t, err)
+	assert.Equal(t, NewMoney(1800), value)
+	mockStorage.AssertExpectations(t)
+}
+
+// TestManager_UpdateItem_QuantityPrecisionLocked は取引のある商品の小数桁数変更が拒否されることのテスト
+func TestManager_UpdateItem_QuantityPrecisionLocked(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{DefaultLocation: "DEFAULT"}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	current := &Item{ID: "FLOUR", Name: "小麦粉", QuantityPrecision: 0}
+	mockStorage.On("GetItem", ctx, "FLOUR").Return(current, nil)
+	mockStorage.On("GetTransactionHistory", ctx, "FLOUR", 1).Return([]Transaction{{ID: "TX-1", ItemID: "FLOUR"}}, nil)
+
+	err := manager.UpdateItem(ctx, &Item{ID: "FLOUR", Name: "小麦粉", QuantityPrecision: 3})
+	assert.IsType(t, &BusinessRuleError{}, err)
+
+	// 小数桁数を変更しない更新は許可
+	mockStorage.On("UpdateItem", ctx, mock.AnythingOfType("*inventory.Item")).Return(nil)
+	err = manager.UpdateItem(ctx, &Item{ID: "FLOUR", Name: "強力粉", QuantityPrecision: 0})
+	assert.NoError(t, err)
+	mockStorage.AssertExpectations(t)
+}
+
+// TestRate_ParseAndConvert は為替レートの解析と金額換算のテスト
+func TestRate_ParseAndConvert(t *testing.T) {
+	rate, err := ParseRate("150.25")
+	assert.NoError(t, err)
+	assert.Equal(t, "150.25", rate.String())
+
+	// 12.5 USD × 150.25 = 1878.125 JPY（小数4桁で保持）
+	assert.Equal(t, MustParseMoney("1878.125"), MustParseMoney("12.5").Convert(rate))
+
+	// 1円未満のレートも小数8桁まで正確に保持
+	small, err := ParseRate("0.00673412")
+	assert.NoError(t, err)
+	assert.Equal(t, "0.00673412", small.String())
+	assert.Equal(t, MustParseMoney("67.3412"), NewMoney(10000).Convert(small))
+
+	_, err = ParseRate("abc")
+	assert.Error(t, err)
+}
+
+// TestManager_AddWithCost_ForeignCurrency は外貨建て仕入単価が計上日のレートで基準通貨に換算されることのテスト
+func TestManager_AddWithCost_ForeignCurrency(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{DefaultLocation: "DEFAULT", BaseCurrency: "JPY"}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	postingDate := time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)
+	ctx := WithPostingDate(context.Background(), postingDate)
+
+	rate := &ExchangeRate{ID: "FX-1", Currency: "USD", BaseCurrency: "JPY", Rate: Rate(15025000000), EffectiveDate: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}
+
+	mockStorage.On("IsPeriodClosed", ctx, postingDate).Return(false, nil)
+	mockStorage.On("GetLatestStockSnapshotAt", mock.Anything, mock.Anything).Return(nil, nil)
+	mockStorage.On("GetItem", ctx, "IMPORT").Return(&Item{ID: "IMPORT", UnitCost: NewMoney(1800)}, nil)
+	mockStorage.On("GetLocation", ctx, "WH-A").Return(&Location{ID: "WH-A"}, nil)
+	mockStorage.On("GetExchangeRate", ctx, "USD", "JPY", postingDate).Return(rate, nil)
+	mockStorage.On("GetStock", ctx, "IMPORT", "WH-A").Return(nil, ErrStockNotFound)
+	mockStorage.On("CreateStock", ctx, mock.AnythingOfType("*inventory.Stock")).Return(nil)
+	mockStorage.On("CreateTransaction", ctx, mock.MatchedBy(func(tx *Transaction) bool {
+		// 評価に使う単価は基準通貨、仕入通貨建ての単価とレートはそのまま保持
+		return *tx.UnitCost == MustParseMoney("1878.125") &&
+			*tx.SourceUnitCost == MustParseMoney("12.5") &&
+			*tx.CostCurrency == "USD" &&
+			*tx.ExchangeRate == rate.Rate
+	})).Return(nil)
+
+	err := manager.AddWithCost(ctx, "IMPORT", "WH-A", 10, MustParseMoney("12.5"), "usd", "PO-US-1")
+
+	assert.NoError(t, err)
+	mockStorage.AssertExpectations(t)
+}
+
+// TestManager_AddWithCost_MissingRate は為替レート未登録の場合に在庫を更新しないことのテスト
+func TestManager_AddWithCost_MissingRate(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{DefaultLocation: "DEFAULT", BaseCurrency: "JPY"}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	mockStorage.On("GetItem", ctx, "IMPORT").Return(&Item{ID: "IMPORT"}, nil)
+	mockStorage.On("GetLocation", ctx, "WH-A").Return(&Location{ID: "WH-A"}, nil)
+	mockStorage.On("GetExchangeRate", ctx, "EUR", "JPY", mock.AnythingOfType("time.Time")).Return(nil, ErrExchangeRateNotFound)
+
+	err := manager.AddWithCost(ctx, "IMPORT", "WH-A", 10, NewMoney(8), "EUR", "PO-EU-1")
+	assert.Equal(t, ErrExchangeRateNotFound, err)
+
+	// 基準通貨建ての単価は換算せずに計上
+	mockStorage.On("GetStock", ctx, "IMPORT", "WH-A").Return(nil, ErrStockNotFound)
+	mockStorage.On("CreateStock", ctx, mock.AnythingOfType("*inventory.Stock")).Return(nil)
+	mockStorage.On("CreateTransaction", ctx, mock.MatchedBy(func(tx *Transaction) bool {
+		return *tx.UnitCost == NewMoney(1200) && tx.CostCurrency == nil && tx.ExchangeRate == nil
+	})).Return(nil)
+
+	err = manager.AddWithCost(ctx, "IMPORT", "WH-A", 10, NewMoney(1200), "JPY", "PO-JP-1")
+	assert.NoError(t, err)
+	mockStorage.AssertNumberOfCalls(t, "CreateStock", 1)
+	mockStorage.AssertExpectations(t)
+}
+
+// TestManager_SetExchangeRate は為替レート登録のバリデーションと正規化のテスト
+func TestManager_SetExchangeRate(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{DefaultLocation: "DEFAULT", BaseCurrency: "JPY"}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	// 基準通貨・未対応通貨・0以下のレートは拒否
+	err := manager.SetExchangeRate(ctx, &ExchangeRate{Currency: "JPY", Rate: Rate(rateScale), EffectiveDate: time.Now()})
+	assert.IsType(t, &ValidationError{}, err)
+	err = manager.SetExchangeRate(ctx, &ExchangeRate{Currency: "XXX", Rate: Rate(rateScale), EffectiveDate: time.Now()})
+	assert.IsType(t, &ValidationError{}, err)
+	err = manager.SetExchangeRate(ctx, &ExchangeRate{Currency: "USD", Rate: 0, EffectiveDate: time.Now()})
+	assert.IsType(t, &ValidationError{}, err)
+
+	mockStorage.On("SaveExchangeRate", ctx, mock.MatchedBy(func(rate *ExchangeRate) bool {
+		return rate.Currency == "USD" && rate.BaseCurrency == "JPY" &&
+			rate.EffectiveDate.Equal(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC))
+	})).Return(nil)
+
+	rate := &ExchangeRate{Currency: "usd", Rate: Rate(15100000000), EffectiveDate: time.Date(2024, 4, 1, 15, 30, 0, 0, time.UTC)}
+	assert.NoError(t, manager.SetExchangeRate(ctx, rate))
+	assert.NotEmpty(t, rate.ID)
+
+	// 基準通貨自身のレートは1
+	base, err := manager.GetExchangeRate(ctx, "JPY", time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, "1", base.Rate.String())
+	mockStorage.AssertExpectations(t)
+}
+
+// TestValidateItemAttributes はカテゴリ属性スキーマによるカスタム属性検証のテスト
+func TestValidateItemAttributes(t *testing.T) {
+	schema := &CategoryAttributeSchema{
+		Category: "apparel",
+		Attributes: []AttributeDefinition{
+			{Name: "brand", Type: AttributeTypeString, Required: true},
+			{Name: "width_mm", Type: AttributeTypeNumber},
+			{Name: "hazardous", Type: AttributeTypeBoolean},
+			{Name: "size", Type: AttributeTypeEnum, Options: []string{"S", "M", "L"}},
+		},
+	}
+	assert.NoError(t, ValidateAttributeSchema(schema))
+
+	valid := map[string]interface{}{"brand": "Acme", "width_mm": float64(120), "hazardous": false, "size": "M"}
+	assert.NoError(t, ValidateItemAttributes(valid, schema))
+
+	invalid := []map[string]interface{}{
+		{"brand": "Acme", "colour": "red"},   // 未定義の属性
+		{"brand": "Acme", "width_mm": "120"}, // 型違い
+		{"brand": "Acme", "size": "XL"},      // 選択肢外
+		{"size": "M"},                        // 必須属性の欠落
+	}
+	for _, attributes := range invalid {
+		err := ValidateItemAttributes(attributes, schema)
+		assert.IsType(t, &ValidationError{}, err, "%v", attributes)
+	}
+
+	// スキーマがない場合は任意の属性を許可するが、値はスカラーに限る
+	assert.NoError(t, ValidateItemAttributes(map[string]interface{}{"colour": "red"}, nil))
+	err := ValidateItemAttributes(map[string]interface{}{"dimensions": map[string]interface{}{"w": 1}}, nil)
+	assert.IsType(t, &ValidationError{}, err)
+
+	// enum型には選択肢が必要
+	err = ValidateAttributeSchema(&CategoryAttributeSchema{Category: "apparel", Attributes: []AttributeDefinition{{Name: "size", Type: AttributeTypeEnum}}})
+	assert.IsType(t, &ValidationError{}, err)
+}
+
+// TestManager_CreateItem_Variant は子SKUのバリアント軸検証のテスト
+func TestManager_CreateItem_Variant(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{DefaultLocation: "DEFAULT"}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	parentID := "TSHIRT"
+	mockStorage.On("GetCategoryAttributeSchema", ctx, "apparel").Return(nil, ErrAttributeSchemaNotFound)
+	mockStorage.On("GetVariantGroup", ctx, parentID).Return(&VariantGroup{ParentItemID: parentID, Axes: []string{"size", "colour"}}, nil)
+	mockStorage.On("GetItem", ctx, parentID).Return(&Item{ID: parentID, Name: "Tシャツ", Category: "apparel"}, nil)
+	mockStorage.On("ListItems", ctx, ItemSearchFilter{ParentItemID: parentID}).Return(&ItemPage{Items: []Item{
+		{ID: "TSHIRT-M-RED", ParentItemID: &parentID, Category: "apparel", Attributes: map[string]interface{}{"size": "M", "colour": "red"}},
+	}, TotalCount: 1}, nil)
+
+	// バリアント軸の値が欠けている
+	err := manager.CreateItem(ctx, &Item{ID: "TSHIRT-M", Name: "Tシャツ M", Category: "apparel", ParentItemID: &parentID,
+		Attributes: map[string]interface{}{"size": "M"}})
+	assert.IsType(t, &ValidationError{}, err)
+
+	// 既存の子SKUと軸の値が重複
+	mockStorage.On("GetVariantGroup", ctx, "TSHIRT-M-RED2").Return(nil, ErrVariantGroupNotFound)
+	err = manager.CreateItem(ctx, &Item{ID: "TSHIRT-M-RED2", Name: "Tシャツ M 赤", Category: "apparel", ParentItemID: &parentID,
+		Attributes: map[string]interface{}{"size": "M", "colour": "red"}})
+	assert.IsType(t, &BusinessRuleError{}, err)
+
+	// 親商品とカテゴリが異なる
+	mockStorage.On("GetCategoryAttributeSchema", ctx, "shoes").Return(nil, ErrAttributeSchemaNotFound)
+	err = manager.CreateItem(ctx, &Item{ID: "TSHIRT-M-BLUE", Name: "Tシャツ M 青", Category: "shoes", ParentItemID: &parentID,
+		Attributes: map[string]interface{}{"size": "M", "colour": "blue"}})
+	assert.IsType(t, &ValidationError{}, err)
+
+	mockStorage.On("GetVariantGroup", ctx, "TSHIRT-M-BLUE").Return(nil, ErrVariantGroupNotFound)
+	mockStorage.On("CreateItem", ctx, mock.AnythingOfType("*inventory.Item")).Return(nil)
+	err = manager.CreateItem(ctx, &Item{ID: "TSHIRT-M-BLUE", Name: "Tシャツ M 青", Category: "apparel", ParentItemID: &parentID,
+		Attributes: map[string]interface{}{"size": "M", "colour": "blue"}})
+	assert.NoError(t, err)
+	mockStorage.AssertExpectations(t)
+}
+
+// TestManager_SetVariantGroup_AxesLocked は子SKUのあるバリアントグループの軸変更が拒否されることのテスト
+func TestManager_SetVariantGroup_AxesLocked(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{DefaultLocation: "DEFAULT"}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	parentID := "TSHIRT"
+	mockStorage.On("GetItem", ctx, parentID).Return(&Item{ID: parentID, Name: "Tシャツ", Category: "apparel"}, nil)
+	mockStorage.On("GetCategoryAttributeSchema", ctx, "apparel").Return(&CategoryAttributeSchema{
+		Category: "apparel",
+		Attributes: []AttributeDefinition{
+			{Name: "size", Type: AttributeTypeEnum, Options: []string{"S", "M", "L"}},
+			{Name: "colour", Type: AttributeTypeString},
+		},
+	}, nil)
+
+	// スキーマに定義されていない軸
+	err := manager.SetVariantGroup(ctx, &VariantGroup{ParentItemID: parentID, Axes: []string{"fit"}})
+	assert.IsType(t, &ValidationError{}, err)
+
+	mockStorage.On("GetVariantGroup", ctx, parentID).Return(&VariantGroup{ParentItemID: parentID, Axes: []string{"size"}}, nil)
+	mockStorage.On("ListItems", ctx, ItemSearchFilter{ParentItemID: parentID}).Return(&ItemPage{Items: []Item{{ID: "TSHIRT-M", ParentItemID: &parentID}}, TotalCount: 1}, nil)
+	err = manager.SetVariantGroup(ctx, &VariantGroup{ParentItemID: parentID, Axes: []string{"size", "colour"}})
+	assert.IsType(t, &BusinessRuleError{}, err)
+
+	// 軸を変更しない更新は許可
+	mockStorage.On("SaveVariantGroup", ctx, mock.AnythingOfType("*inventory.VariantGroup")).Return(nil)
+	err = manager.SetVariantGroup(ctx, &VariantGroup{ParentItemID: parentID, Axes: []string{"size"}})
+	assert.NoError(t, err)
+	mockStorage.AssertExpectations(t)
+}
+
+// TestManager_SearchItems_Attributes は属性条件付き商品検索のテスト
+func TestManager_SearchItems_Attributes(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{DefaultLocation: "DEFAULT"}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	_, err := manager.SearchItems(ctx, ItemSearchFilter{})
+	assert.IsType(t, &ValidationError{}, err)
+
+	_, err = manager.SearchItems(ctx, ItemSearchFilter{Attributes: map[string]string{"hazard-class": "3"}})
+	assert.IsType(t, &ValidationError{}, err)
+
+	// キーワードがある場合は類似度順・既定の件数で検索
+	filter := ItemSearchFilter{Query: "シャツ", Category: "apparel", Attributes: map[string]string{"colour": "red"},
+		Sort: ItemSortRelevance, Limit: DefaultItemPageSize}
+	expected := &ItemPage{Items: []Item{{ID: "TSHIRT-M-RED", Name: "Tシャツ M 赤", Category: "apparel", Attributes: map[string]interface{}{"colour": "red"}}}, TotalCount: 1}
+	mockStorage.On("SearchItems", ctx, filter).Return(expected, nil)
+
+	page, err := manager.SearchItems(ctx, ItemSearchFilter{Query: " シャツ ", Category: "apparel", Attributes: map[string]string{"colour": "red"}})
+	assert.NoError(t, err)
+	assert.Equal(t, expected, page)
+	mockStorage.AssertExpectations(t)
+}
+
+// TestManager_ListItems_Filters は商品一覧の条件検証と既定値のテスト
+func TestManager_ListItems_Filters(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{DefaultLocation: "DEFAULT"}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	minCost, maxCost := NewMoney(500), NewMoney(100)
+	nameCursor := EncodeItemCursor(ItemCursor{Sort: ItemSortName, Key: "りんご", ID: "APPLE"})
+	invalid := []ItemSearchFilter{
+		{MinUnitCost: &minCost, MaxUnitCost: &maxCost}, // 単価の下限が上限を超える
+		{Limit: MaxItemPageSize + 1},                   // 件数が上限を超える
+		{Sort: ItemSortRelevance},                      // 類似度順は検索のみ
+		{Sort: "price"},                                // 無効な並び順
+		{Sort: ItemSortUnitCost, Cursor: nameCursor},   // 並び順の異なるカーソル
+		{Cursor: "not-a-cursor"},                       // 無効なカーソル
 	}
-	location := &Location{
-		ID:   "TEST-LOC",
-		Name: "テストロケーション",
+	for _, filter := range invalid {
+		_, err := manager.ListItems(ctx, filter)
+		assert.IsType(t, &ValidationError{}, err, "%+v", filter)
 	}
 
-	// バッチ操作
-	operations := []InventoryOperation{
-		{
-			Type:       OperationTypeAdd,
-			ItemID:     "TEST-ITEM",
-			LocationID: "TEST-LOC",
-			Quantity:   100,
-			Reference:  "BATCH-001",
-		},
+	active := true
+	expected := &ItemPage{Items: []Item{{ID: "BANANA", Name: "バナナ", IsActive: true}}, TotalCount: 3,
+		NextCursor: EncodeItemCursor(ItemCursor{Sort: ItemSortName, Key: "バナナ", ID: "BANANA"})}
+	mockStorage.On("ListItems", ctx, ItemSearchFilter{Category: "果物", Active: &active, Sort: ItemSortName, Cursor: nameCursor, Limit: 1}).
+		Return(expected, nil)
+
+	page, err := manager.ListItems(ctx, ItemSearchFilter{Category: "果物", Active: &active, Cursor: nameCursor, Limit: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, expected, page)
+
+	// 既定の並び順と件数
+	mockStorage.On("ListItems", ctx, ItemSearchFilter{Sort: ItemSortName, Limit: DefaultItemPageSize}).Return(&ItemPage{}, nil)
+	_, err = manager.ListItems(ctx, ItemSearchFilter{})
+	assert.NoError(t, err)
+	mockStorage.AssertExpectations(t)
+}
+
+// TestItemCursor_RoundTrip は商品一覧カーソルの生成と復元のテスト
+func TestItemCursor_RoundTrip(t *testing.T) {
+	cursor := ItemCursor{Sort: ItemSortCreatedAt, Desc: true, Key: "2024-01-15 10:30:00.123456", ID: "ITEM-001"}
+	encoded := EncodeItemCursor(cursor)
+	assert.NotContains(t, encoded, "ITEM-001")
+
+	decoded, err := DecodeItemCursor(encoded)
+	assert.NoError(t, err)
+	assert.Equal(t, cursor, *decoded)
+
+	for _, value := range []string{"%%%", EncodeItemCursor(ItemCursor{Sort: "price", ID: "X"}), EncodeItemCursor(ItemCursor{Sort: ItemSortName})} {
+		_, err := DecodeItemCursor(value)
+		assert.IsType(t, &ValidationError{}, err, value)
 	}
+}
 
-	// モックの期待値設定
-	mockStorage.On("GetItem", ctx, "TEST-ITEM").Return(item, nil)
-	mockStorage.On("GetLocation", ctx, "TEST-LOC").Return(location, nil)
-	mockStorage.On("GetStock", ctx, "TEST-ITEM", "TEST-LOC").Return(nil, ErrStockNotFound)
-	mockStorage.On("CreateStock", ctx, mock.AnythingOfType("*inventory.Stock")).Return(nil)
-	mockStorage.On("CreateTransaction", ctx, mock.AnythingOfType("*inventory.Transaction")).Return(nil)
+// TestParseGS1 はGS1アプリケーション識別子の解析とGTINチェックデジットのテスト
+func TestParseGS1(t *testing.T) {
+	assert.NoError(t, ValidateGTIN("4901234567894"))
+	assert.IsType(t, &ValidationError{}, ValidateGTIN("4901234567895"))
+	assert.Equal(t, "04901234567894", NormalizeGTIN("4901234567894"))
 
-	// テスト実行
-	batch, err := manager.ExecuteBatch(ctx, operations)
+	// 括弧付き表記（有効期限の日が00は月末日）
+	data, err := ParseGS1("(01)14901234567891(10)LOT-A(17)261200(21)SN001(30)3")
+	assert.NoError(t, err)
+	assert.Equal(t, "14901234567891", data.GTIN)
+	assert.Equal(t, "LOT-A", data.LotNumber)
+	assert.Equal(t, "SN001", data.Serial)
+	assert.Equal(t, int64(3), data.Count)
+	assert.Equal(t, time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC), *data.ExpiryDate)
 
-	// アサーション
+	// スキャナ出力形式（シンボル識別子と可変長データ後のGS区切り）
+	data, err = ParseGS1("]C10114901234567891" + "17261215" + "10LOT-A\x1d21SN001")
 	assert.NoError(t, err)
-	assert.NotNil(t, batch)
-	assert.Equal(t, 1, batch.SuccessCount)
-	assert.Equal(t, 0, batch.FailureCount)
+	assert.Equal(t, "14901234567891", data.GTIN)
+	assert.Equal(t, "LOT-A", data.LotNumber)
+	assert.Equal(t, "SN001", data.Serial)
+	assert.Equal(t, time.Date(2026, 12, 15, 0, 0, 0, 0, time.UTC), *data.ExpiryDate)
+
+	for _, input := range []string{
+		"(01)14901234567890",      // チェックデジット不一致
+		"(99)ABC",                 // 未対応のAI
+		"(01)1490123456789",       // 固定長の桁数不足
+		"(17)261332",              // 無効な日付
+		"0114901234567891" + "17", // 有効期限の桁数不足
+	} {
+		_, err := ParseGS1(input)
+		assert.IsType(t, &ValidationError{}, err, input)
+	}
+}
+
+// TestManager_AddItemBarcode はバーコード登録時の既定値と桁数検証のテスト
+func TestManager_AddItemBarcode(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{DefaultLocation: "DEFAULT"}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	// UPC-Aは12桁
+	err := manager.AddItemBarcode(ctx, &ItemBarcode{ItemID: "BEER", Code: "4901234567894", Type: BarcodeTypeUPC})
+	assert.IsType(t, &ValidationError{}, err)
+
+	// ケースには入数が必要
+	err = manager.AddItemBarcode(ctx, &ItemBarcode{ItemID: "BEER", Code: "14901234567891", Type: BarcodeTypeGTIN, PackageLevel: PackageLevelCase})
+	assert.IsType(t, &ValidationError{}, err)
+
+	mockStorage.On("GetItem", ctx, "BEER").Return(&Item{ID: "BEER", Name: "ビール"}, nil)
+	mockStorage.On("SaveItemBarcode", ctx, mock.AnythingOfType("*inventory.ItemBarcode")).Return(nil).Once()
+	barcode := &ItemBarcode{ItemID: "BEER", Code: "4901234567894", Type: "jan"}
+	err = manager.AddItemBarcode(ctx, barcode)
+	assert.NoError(t, err)
+	assert.Equal(t, "04901234567894", barcode.GTIN)
+	assert.Equal(t, BarcodeTypeJAN, barcode.Type)
+	assert.Equal(t, PackageLevelEach, barcode.PackageLevel)
+	assert.Equal(t, int64(1), barcode.UnitsPerPackage)
+
+	mockStorage.On("SaveItemBarcode", ctx, mock.AnythingOfType("*inventory.ItemBarcode")).Return(ErrDuplicateBarcode).Once()
+	err = manager.AddItemBarcode(ctx, &ItemBarcode{ItemID: "BEER", Code: "04901234567894", Type: BarcodeTypeGTIN})
+	assert.Equal(t, ErrDuplicateBarcode, err)
 	mockStorage.AssertExpectations(t)
 }
 
-// TestManager_GetTotalStock は総在庫取得のテスト
-func TestManager_GetTotalStock(t *testing.T) {
+// TestManager_ResolveScan はGS1ラベルから商品・ロット・推奨数量を解決するテスト
+func TestManager_ResolveScan(t *testing.T) {
 	mockStorage := new(MockStorage)
 	logger := zap.NewNop()
-	config := &Config{}
+	config := &Config{DefaultLocation: "DEFAULT"}
 
 	manager := NewManager(mockStorage, nil, logger, config)
 	ctx := context.Background()
 
-	// テスト用のサンプルデータ
-	item := &Item{
-		ID:       "TEST-ITEM",
-		Name:     "テスト商品",
-		UnitCost: 1000.0,
+	caseBarcode := &ItemBarcode{GTIN: "14901234567891", Code: "14901234567891", ItemID: "BEER", Type: BarcodeTypeGTIN,
+		PackageLevel: PackageLevelCase, UnitsPerPackage: 24}
+	mockStorage.On("GetItemBarcode", ctx, "14901234567891").Return(caseBarcode, nil)
+	mockStorage.On("GetItem", ctx, "BEER").Return(&Item{ID: "BEER", Name: "ビール"}, nil)
+	mockStorage.On("GetLotsByItem", ctx, "BEER").Return([]Lot{
+		{ID: "LOT-1", Number: "LOT-A", ItemID: "BEER"},
+		{ID: "LOT-2", Number: "LOT-B", ItemID: "BEER"},
+	}, nil)
+
+	result, err := manager.ResolveScan(ctx, "(01)14901234567891(10)LOT-B(17)270300(30)2")
+	assert.NoError(t, err)
+	assert.Equal(t, "BEER", result.Item.ID)
+	assert.Equal(t, PackageLevelCase, result.PackageLevel)
+	assert.Equal(t, int64(2), result.PackageCount)
+	assert.Equal(t, int64(48), result.Quantity)
+	assert.Equal(t, "LOT-2", result.Lot.ID)
+	assert.Equal(t, time.Date(2027, 3, 31, 0, 0, 0, 0, time.UTC), *result.ExpiryDate)
+
+	// 未登録のJANコード
+	mockStorage.On("GetItemBarcode", ctx, "04901234567894").Return(nil, ErrBarcodeNotFound)
+	_, err = manager.ResolveScan(ctx, "4901234567894")
+	assert.Equal(t, ErrBarcodeNotFound, err)
+
+	// GTINを含まないGS1データ
+	_, err = manager.ResolveScan(ctx, "(10)LOT-A")
+	assert.IsType(t, &ValidationError{}, err)
+
+	// 入数×包装数が数量の上限を超えるラベル
+	_, err = manager.ResolveScan(ctx, "(01)14901234567891(30)99999999")
+	if assert.IsType(t, &ValidationError{}, err) {
+		assert.Equal(t, "quantity", err.(*ValidationError).Field)
 	}
 
-	// モックの期待値設定
-	mockStorage.On("GetItem", ctx, "TEST-ITEM").Return(item, nil)
+	// アーカイブ済みの商品のバーコード
+	archivedAt := time.Now()
+	mockStorage.On("GetItemBarcode", ctx, "04512345678906").Return(&ItemBarcode{GTIN: "04512345678906", ItemID: "OLD", UnitsPerPackage: 1}, nil)
+	mockStorage.On("GetItem", ctx, "OLD").Return(&Item{ID: "OLD", ArchivedAt: &archivedAt}, nil)
+	_, err = manager.ResolveScan(ctx, "4512345678906")
+	assert.IsType(t, &ValidationError{}, err)
+	mockStorage.AssertExpectations(t)
+}
 
-	// テスト実行
-	totalStock, err := manager.GetTotalStock(ctx, "TEST-ITEM")
+// TestManager_DeleteItem_InUse は在庫・未完了の伝票がある商品の削除を拒否するテスト
+func TestManager_DeleteItem_InUse(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{DefaultLocation: "DEFAULT"}
 
-	// アサーション
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	mockStorage.On("GetItem", ctx, "WIDGET").Return(&Item{ID: "WIDGET", Name: "ウィジェット"}, nil)
+	mockStorage.On("GetItemUsage", ctx, "WIDGET").Return(&MasterUsage{StockQuantity: 5, OpenPurchaseOrders: 1}, nil)
+
+	err := manager.DeleteItem(ctx, "WIDGET")
+	assert.IsType(t, &BusinessRuleError{}, err)
+	assert.Contains(t, err.Error(), "在庫数量: 5")
+	assert.Contains(t, err.Error(), "未完了の発注: 1件")
+	mockStorage.AssertNotCalled(t, "SetItemArchivedAt", mock.Anything, mock.Anything, mock.Anything)
+	mockStorage.AssertExpectations(t)
+}
+
+// TestManager_DeleteItem_Archives は使用されていない商品をアーカイブし、以降の入庫を拒否するテスト
+func TestManager_DeleteItem_Archives(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{DefaultLocation: "DEFAULT"}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	mockStorage.On("GetItem", ctx, "WIDGET").Return(&Item{ID: "WIDGET", Name: "ウィジェット"}, nil).Once()
+	mockStorage.On("GetItemUsage", ctx, "WIDGET").Return(&MasterUsage{}, nil)
+	mockStorage.On("SetItemArchivedAt", ctx, "WIDGET", mock.MatchedBy(func(at *time.Time) bool { return at != nil })).Return(nil)
+
+	err := manager.DeleteItem(ctx, "WIDGET")
+	assert.NoError(t, err)
+
+	// アーカイブ済みの商品は新しい在庫移動に使用できない
+	archivedAt := time.Now()
+	mockStorage.On("GetItem", ctx, "WIDGET").Return(&Item{ID: "WIDGET", Name: "ウィジェット", ArchivedAt: &archivedAt}, nil)
+	mockStorage.On("GetLocation", ctx, "DEFAULT").Return(&Location{ID: "DEFAULT"}, nil)
+
+	err = manager.Add(ctx, "WIDGET", "DEFAULT", 10, "PO-1")
+	assert.Equal(t, ErrItemArchived, err)
+
+	// 既にアーカイブ済みの場合は何もしない
+	err = manager.DeleteItem(ctx, "WIDGET")
+	assert.NoError(t, err)
+	mockStorage.AssertNumberOfCalls(t, "SetItemArchivedAt", 1)
+	mockStorage.AssertNotCalled(t, "CreateTransaction", mock.Anything, mock.Anything)
+}
+
+// TestManager_DeleteLocation はロケーションのアーカイブと、輸送中ロケーション・使用中ロケーションの拒否のテスト
+func TestManager_DeleteLocation(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{DefaultLocation: "DEFAULT"}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	mockStorage.On("GetLocation", ctx, DefaultInTransitLocation).Return(&Location{ID: DefaultInTransitLocation}, nil)
+	err := manager.DeleteLocation(ctx, DefaultInTransitLocation)
+	assert.IsType(t, &BusinessRuleError{}, err)
+
+	mockStorage.On("GetLocation", ctx, "WH-A").Return(&Location{ID: "WH-A", Name: "倉庫A"}, nil)
+	mockStorage.On("GetLocationUsage", ctx, "WH-A").Return(&MasterUsage{OpenTransferOrders: 2}, nil)
+	err = manager.DeleteLocation(ctx, "WH-A")
+	assert.IsType(t, &BusinessRuleError{}, err)
+	assert.Contains(t, err.Error(), "未完了の移動指示: 2件")
+
+	mockStorage.On("GetLocation", ctx, "WH-B").Return(&Location{ID: "WH-B", Name: "倉庫B"}, nil)
+	mockStorage.On("GetLocationUsage", ctx, "WH-B").Return(&MasterUsage{}, nil)
+	mockStorage.On("SetLocationArchivedAt", ctx, "WH-B", mock.MatchedBy(func(at *time.Time) bool { return at != nil })).Return(nil)
+	err = manager.DeleteLocation(ctx, "WH-B")
 	assert.NoError(t, err)
-	assert.Equal(t, int64(0), totalStock) // 現在の実装では0を返す
+
+	mockStorage.On("GetLocation", ctx, "MISSING").Return(nil, ErrLocationNotFound)
+	err = manager.DeleteLocation(ctx, "MISSING")
+	assert.Equal(t, ErrLocationNotFound, err)
 	mockStorage.AssertExpectations(t)
 }
 
-// TestManager_GetHistoryByDateRange は日付範囲履歴取得のテスト
-func TestManager_GetHistoryByDateRange(t *testing.T) {
+// TestManager_RestoreItem は親商品がアーカイブ済みのバリアントや軸の値が重複するバリアントの復元を拒否するテスト
+func TestManager_RestoreItem(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{DefaultLocation: "DEFAULT"}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	archivedAt := time.Now()
+	parentID := "TSHIRT"
+	mockStorage.On("GetItem", ctx, "TSHIRT").Return(&Item{ID: "TSHIRT", ArchivedAt: &archivedAt}, nil)
+	mockStorage.On("GetItem", ctx, "TSHIRT-RED-M").Return(&Item{ID: "TSHIRT-RED-M", ParentItemID: &parentID, ArchivedAt: &archivedAt}, nil)
+
+	err := manager.RestoreItem(ctx, "TSHIRT-RED-M")
+	assert.IsType(t, &BusinessRuleError{}, err)
+
+	mockStorage.On("SetItemArchivedAt", ctx, "TSHIRT", (*time.Time)(nil)).Return(nil)
+	err = manager.RestoreItem(ctx, "TSHIRT")
+	assert.NoError(t, err)
+
+	// アーカイブ中に同じ軸の値の子SKUが作成されたバリアント
+	shoesID := "SHOES"
+	mockStorage.On("GetItem", ctx, "SHOES").Return(&Item{ID: "SHOES", Category: "shoes"}, nil)
+	mockStorage.On("GetItem", ctx, "SHOES-26").Return(&Item{ID: "SHOES-26", ParentItemID: &shoesID, Category: "shoes",
+		Attributes: map[string]interface{}{"size": "26"}, ArchivedAt: &archivedAt}, nil)
+	mockStorage.On("GetVariantGroup", ctx, "SHOES").Return(&VariantGroup{ParentItemID: "SHOES", Axes: []string{"size"}}, nil)
+	mockStorage.On("GetVariantGroup", ctx, "SHOES-26").Return(nil, ErrVariantGroupNotFound)
+	mockStorage.On("ListItems", ctx, ItemSearchFilter{ParentItemID: "SHOES"}).Return(&ItemPage{Items: []Item{
+		{ID: "SHOES-26-NEW", ParentItemID: &shoesID, Category: "shoes", Attributes: map[string]interface{}{"size": "26"}},
+	}, TotalCount: 1}, nil)
+	err = manager.RestoreItem(ctx, "SHOES-26")
+	assert.IsType(t, &BusinessRuleError{}, err)
+	mockStorage.AssertNotCalled(t, "SetItemArchivedAt", ctx, "SHOES-26", (*time.Time)(nil))
+	mockStorage.AssertExpectations(t)
+}
+
+// TestParseMasterFile はマスタ取り込みファイルの解析のテスト
+func TestParseMasterFile(t *testing.T) {
+	// BOM付きCSV・日本語の見出し・空行
+	data := []byte("\xEF\xBB\xBF商品ID,商品名,単価\nAPPLE,りんご,120.5\n,,\nBANANA,バナナ,\n")
+	table, err := parseMasterFile(MasterDataItems, ReportFormatCSV, data)
+	assert.NoError(t, err)
+	assert.Len(t, table.rows, 2)
+	assert.Equal(t, 2, table.rows[0].number)
+	assert.Equal(t, 4, table.rows[1].number)
+	assert.Equal(t, "120.5", table.rows[0].values["unit_cost"])
+	assert.False(t, table.rows[0].has("sku"))
+
+	// 不明な列・必須列の欠落・未対応の形式
+	_, err = parseMasterFile(MasterDataItems, ReportFormatCSV, []byte("id,name,price\nA,B,1\n"))
+	assert.IsType(t, &ValidationError{}, err)
+	_, err = parseMasterFile(MasterDataLocations, ReportFormatCSV, []byte("id,type\nWH,warehouse\n"))
+	assert.IsType(t, &ValidationError{}, err)
+	_, err = parseMasterFile(MasterDataItems, ReportFormatJSON, data)
+	assert.IsType(t, &ValidationError{}, err)
+
+	// 出力したXLSXはそのまま取り込める
+	var buf bytes.Buffer
+	sink, err := newReportSink(&buf, ReportFormatXLSX, masterColumns[MasterDataLocations])
+	assert.NoError(t, err)
+	assert.NoError(t, sink.writeRow([]interface{}{"WH-01", "東京倉庫", "warehouse", "", int64(5000), "true", "A-01"}))
+	assert.NoError(t, sink.close())
+
+	table, err = parseMasterFile(MasterDataLocations, ReportFormatXLSX, buf.Bytes())
+	assert.NoError(t, err)
+	assert.Len(t, table.rows, 1)
+	location := &Location{}
+	assert.Empty(t, locationFromRow(table.rows[0], location))
+	assert.Equal(t, "東京倉庫", location.Name)
+	assert.Equal(t, int64(5000), location.Capacity)
+	assert.Equal(t, "A-01", location.PickPath)
+	assert.True(t, location.IsActive)
+}
+
+// TestParseImportFile_XLSX はXLSX取り込みの列・行・サイズの上限と数値セルの丸めのテスト
+func TestParseImportFile_XLSX(t *testing.T) {
+	workbook := func(rows string) []byte {
+		var buf bytes.Buffer
+		writer := zip.NewWriter(&buf)
+		part, err := writer.Create("xl/worksheets/sheet1.xml")
+		assert.NoError(t, err)
+		_, err = part.Write([]byte(`<worksheet><sheetData>` + rows + `</sheetData></worksheet>`))
+		assert.NoError(t, err)
+		assert.NoError(t, writer.Close())
+		return buf.Bytes()
+	}
+	header := `<row r="1"><c r="A1" t="inlineStr"><is><t>item_id</t></is></c><c r="B1" t="inlineStr"><is><t>location_id</t></is></c><c r="C1" t="inlineStr"><is><t>quantity</t></is></c></row>`
+
+	// 浮動小数点で保存された数値は小数桁数に丸め、桁数を超える値は拒否
+	table, err := parseImportFile(openingBalanceColumns, ReportFormatXLSX, workbook(header+
+		`<row r="2"><c r="A2" t="inlineStr"><is><t>FLOUR</t></is></c><c r="C2"><v>1.1000000000000001</v></c></row>`+
+		`<row r="3"><c r="A3" t="inlineStr"><is><t>FLOUR</t></is></c><c r="C3"><v>0.30000000000000004</v></c></row>`+
+		`<row r="4"><c r="A4" t="inlineStr"><is><t>FLOUR</t></is></c><c r="C4"><v>1.2345</v></c></row>`))
+	assert.NoError(t, err)
+	quantity, err := table.rows[0].quantity("quantity", 3)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1100), quantity)
+	quantity, err = table.rows[1].quantity("quantity", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), quantity)
+	_, err = table.rows[2].quantity("quantity", 3)
+	assert.Error(t, err)
+
+	// CSVの文字列はそのまま解析
+	table, err = parseImportFile(openingBalanceColumns, ReportFormatCSV, []byte("item_id,location_id,quantity\nFLOUR,WH-01,1.1000000000000001\n"))
+	assert.NoError(t, err)
+	_, err = table.rows[0].quantity("quantity", 3)
+	assert.Error(t, err)
+
+	// XFDを超える列・列名のない参照は拒否
+	assert.Equal(t, 16383, xlsxColumnIndex("XFD1"))
+	for _, ref := range []string{"XFE1", "ZZZZZZZZZZZZZZ1", "1"} {
+		_, err = parseImportFile(openingBalanceColumns, ReportFormatXLSX, workbook(header+
+			`<row r="2"><c r="`+ref+`" t="inlineStr"><is><t>x</t></is></c></row>`))
+		assert.IsType(t, &ValidationError{}, err, ref)
+	}
+
+	// 上限を超える行数はデコード中に拒否
+	var rows strings.Builder
+	rows.WriteString(header)
+	for i := 0; i <= MaxImportRows; i++ {
+		rows.WriteString(`<row><c t="inlineStr"><is><t>A</t></is></c></row>`)
+	}
+	_, err = parseImportFile(openingBalanceColumns, ReportFormatXLSX, workbook(rows.String()))
+	assert.IsType(t, &ValidationError{}, err)
+
+	// 展開後サイズが上限を超える部品は読み込まない
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	part, err := writer.CreateRaw(&zip.FileHeader{Name: "xl/worksheets/sheet1.xml", Method: zip.Store, UncompressedSize64: xlsxMaxPartSize + 1})
+	assert.NoError(t, err)
+	_, err = part.Write([]byte("<worksheet/>"))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+	_, err = parseImportFile(openingBalanceColumns, ReportFormatXLSX, buf.Bytes())
+	assert.IsType(t, &ValidationError{}, err)
+}
+
+// TestManager_RunMasterImport はマスタ取り込みジョブの検証と書き込みのテスト
+func TestManager_RunMasterImport(t *testing.T) {
 	mockStorage := new(MockStorage)
 	logger := zap.NewNop()
 	config := &Config{}
@@ -406,37 +4056,214 @@ func TestManager_GetHistoryByDateRange(t *testing.T) {
 	manager := NewManager(mockStorage, nil, logger, config)
 	ctx := context.Background()
 
-	// テスト用のサンプルデータ
-	item := &Item{
-		ID:       "TEST-ITEM",
-		Name:     "テスト商品",
-		UnitCost: 1000.0,
+	mockStorage.On("UpdateMasterImportJob", ctx, mock.Anything).Return(nil)
+	mockStorage.On("GetItem", ctx, "APPLE").Return(&Item{ID: "APPLE", Name: "りんご", SKU: "APL", IsActive: true}, nil)
+	mockStorage.On("GetItem", ctx, "BANANA").Return(nil, ErrItemNotFound)
+	mockStorage.On("GetItem", ctx, "CHERRY").Return(nil, ErrItemNotFound)
+
+	// 新規登録モードでは既存IDの行・ファイル内の重複・変換エラーを行エラーとし、1件も書き込まない
+	data := []byte("id,name,sku,unit_cost\nAPPLE,りんご,APL,100\nBANANA,バナナ,BNN,abc\nCHERRY,さくらんぼ,CHR,300\nCHERRY,さくらんぼ,CHR,300\n")
+	table, err := parseMasterFile(MasterDataItems, ReportFormatCSV, data)
+	assert.NoError(t, err)
+	job := &MasterImportJob{ID: "JOB-1", Target: MasterDataItems, Mode: MasterImportModeCreate, TotalRows: len(table.rows)}
+	manager.runMasterImport(ctx, job, table)
+	assert.Equal(t, MasterImportStatusFailed, job.Status)
+	assert.Equal(t, 3, job.ErrorCount)
+	assert.Equal(t, 2, job.Errors[0].Row)
+	assert.Equal(t, "unit_cost", job.Errors[1].Field)
+	assert.Equal(t, 5, job.Errors[2].Row)
+	assert.Equal(t, 0, job.CreatedCount)
+	mockStorage.AssertNotCalled(t, "CreateItem", mock.Anything, mock.Anything)
+
+	// ドライランは検証のみ
+	data = []byte("id,name,unit_cost\nAPPLE,青りんご,150\nBANANA,バナナ,80\n")
+	table, err = parseMasterFile(MasterDataItems, ReportFormatCSV, data)
+	assert.NoError(t, err)
+	job = &MasterImportJob{ID: "JOB-2", Target: MasterDataItems, Mode: MasterImportModeUpsert, DryRun: true, TotalRows: len(table.rows)}
+	manager.runMasterImport(ctx, job, table)
+	assert.Equal(t, MasterImportStatusCompleted, job.Status)
+	assert.Equal(t, 1, job.CreatedCount)
+	assert.Equal(t, 1, job.UpdatedCount)
+	mockStorage.AssertNotCalled(t, "UpdateItem", mock.Anything, mock.Anything)
+
+	// upsertモードでは既存IDを更新し（ファイルにない列は維持）、新規IDを登録する
+	mockStorage.On("UpdateItem", ctx, mock.MatchedBy(func(item *Item) bool {
+		return item.ID == "APPLE" && item.Name == "青りんご" && item.SKU == "APL" && item.UnitCost == NewMoney(150)
+	})).Return(nil)
+	mockStorage.On("CreateItem", ctx, mock.MatchedBy(func(item *Item) bool {
+		return item.ID == "BANANA" && item.IsActive
+	})).Return(nil)
+	job = &MasterImportJob{ID: "JOB-3", Target: MasterDataItems, Mode: MasterImportModeUpsert, TotalRows: len(table.rows)}
+	manager.runMasterImport(ctx, job, table)
+	assert.Equal(t, MasterImportStatusCompleted, job.Status)
+	assert.Equal(t, 0, job.ErrorCount)
+	assert.Equal(t, 2, job.ProcessedRows)
+	assert.NotNil(t, job.CompletedAt)
+	mockStorage.AssertExpectations(t)
+}
+
+// TestManager_ImportOpeningBalances は期首在庫の検証と計上のテスト
+func TestManager_ImportOpeningBalances(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+	asOf := time.Now().AddDate(0, -1, 0).Truncate(time.Second)
+
+	snapshotAt := asOf.Add(time.Hour)
+
+	mockStorage.On("IsPeriodClosed", mock.Anything, asOf).Return(false, nil)
+	mockStorage.On("GetLatestStockSnapshotAt", mock.Anything, "WH-02").Return(&snapshotAt, nil)
+	mockStorage.On("GetLatestStockSnapshotAt", mock.Anything, mock.Anything).Return(nil, nil)
+	mockStorage.On("GetItem", mock.Anything, "APPLE").Return(&Item{ID: "APPLE", UnitCost: NewMoney(100), IsActive: true}, nil)
+	mockStorage.On("GetItem", mock.Anything, "GHOST").Return(nil, ErrItemNotFound)
+	mockStorage.On("GetLocation", mock.Anything, "WH-01").Return(&Location{ID: "WH-01", IsActive: true}, nil)
+	mockStorage.On("GetLocation", mock.Anything, "WH-02").Return(&Location{ID: "WH-02", IsActive: true}, nil)
+	mockStorage.On("GetStock", mock.Anything, "APPLE", "WH-01").Return(nil, ErrStockNotFound)
+
+	// マスタにない商品・重複・無効な数量・移行基準日時以降にスナップショットがあるロケーションは行エラーとし、計上しない
+	data := []byte("商品ID,ロケーションID,数量,ロット番号,有効期限,単価\n" +
+		"APPLE,WH-01,10,LOT-A,2027-01-31,120\n" +
+		"GHOST,WH-01,5,,,\n" +
+		"APPLE,WH-01,3,LOT-A,,\n" +
+		"APPLE,WH-01,-1,LOT-C,,\n" +
+		"APPLE,WH-02,4,,,\n")
+	load, err := manager.ImportOpeningBalances(ctx, OpeningBalanceRequest{AsOf: asOf}, data)
+	assert.NoError(t, err)
+	assert.Equal(t, OpeningBalanceStatusRejected, load.Status)
+	assert.Equal(t, 4, load.ErrorCount)
+	assert.Equal(t, 3, load.Errors[0].Row)
+	assert.Equal(t, "lot_number", load.Errors[1].Field)
+	assert.Equal(t, "quantity", load.Errors[2].Field)
+	assert.Equal(t, "location_id", load.Errors[3].Field)
+	mockStorage.AssertNotCalled(t, "CreateOpeningBalanceLoad", mock.Anything, mock.Anything)
+
+	// ドライランは検証のみ（単価の指定がない場合は商品マスタの単価）
+	data = []byte("item_id,location_id,quantity,lot_number,expiry_date,unit_cost\n" +
+		"APPLE,WH-01,10,LOT-A,2027-01-31,120\n" +
+		"APPLE,WH-01,5,LOT-B,,\n")
+	load, err = manager.ImportOpeningBalances(ctx, OpeningBalanceRequest{AsOf: asOf, DryRun: true}, data)
+	assert.NoError(t, err)
+	assert.Equal(t, OpeningBalanceStatusValidated, load.Status)
+	assert.Equal(t, 2, load.LineCount)
+	assert.Equal(t, NewMoney(1700), load.TotalValue)
+	assert.Equal(t, OpeningBalanceReferencePrefix+asOf.Format("20060102"), load.Reference)
+
+	// 計上は移行基準日時の単価付き入庫として、取り込みIDをメタデータに記録
+	// 同じ商品・ロケーションの入庫は1件の在庫記録にまとめ、取り込み・入庫・ロットを一括で書き込む
+	mockStorage.On("ApplyOpeningBalanceLoad", ctx, mock.MatchedBy(func(l *OpeningBalanceLoad) bool {
+		return l.Status == OpeningBalanceStatusPosted && len(l.Lines) == 2
+	}), mock.MatchedBy(func(stocks []*Stock) bool {
+		return len(stocks) == 1 && stocks[0].Quantity == 15 && stocks[0].Version == 1
+	}), mock.MatchedBy(func(txs []*Transaction) bool {
+		for _, tx := range txs {
+			if tx.Type != TransactionTypeInbound || !tx.CreatedAt.Equal(asOf) || tx.UnitCost == nil ||
+				tx.Metadata[OpeningBalanceMetadataKey] == "" || tx.LotNumber == nil {
+				return false
+			}
+		}
+		return len(txs) == 2
+	}), mock.MatchedBy(func(lots []*Lot) bool {
+		return len(lots) == 2 && lots[0].Number == "LOT-A" && lots[0].UnitCost == NewMoney(120) &&
+			lots[1].Number == "LOT-B" && lots[1].UnitCost == NewMoney(100)
+	})).Return(nil).Once()
+
+	load, err = manager.ImportOpeningBalances(ctx, OpeningBalanceRequest{AsOf: asOf, Reference: "MIGRATION-1"}, data)
+	assert.NoError(t, err)
+	assert.Equal(t, OpeningBalanceStatusPosted, load.Status)
+	assert.Equal(t, "MIGRATION-1", load.Reference)
+	for _, line := range load.Lines {
+		assert.NotNil(t, line.TransactionID)
+		assert.NotNil(t, line.LotID)
 	}
+	mockStorage.AssertExpectations(t)
+	mockStorage.AssertNotCalled(t, "CreateStock", mock.Anything, mock.Anything)
+	mockStorage.AssertNotCalled(t, "CreateTransaction", mock.Anything, mock.Anything)
 
-	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
-	to := time.Date(2024, 12, 31, 23, 59, 59, 0, time.UTC)
+	// 書き込みに失敗した場合は何も計上せず、失敗した取り込みを記録して返す
+	mockStorage.On("ApplyOpeningBalanceLoad", ctx, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(ErrVersionMismatch).Once()
+	mockStorage.On("CreateOpeningBalanceLoad", ctx, mock.MatchedBy(func(l *OpeningBalanceLoad) bool {
+		return l.Status == OpeningBalanceStatusFailed && l.Lines[0].TransactionID == nil && l.Lines[0].LotID == nil
+	})).Return(nil)
+	load, err = manager.ImportOpeningBalances(ctx, OpeningBalanceRequest{AsOf: asOf}, data)
+	assert.Equal(t, ErrVersionMismatch, err)
+	assert.Equal(t, OpeningBalanceStatusFailed, load.Status)
+	assert.NotEmpty(t, load.Error)
+	assert.Nil(t, load.PostedAt)
 
-	transactions := []Transaction{
-		{
-			ID:        "TX-001",
-			Type:      TransactionTypeInbound,
-			ItemID:    "TEST-ITEM",
-			Quantity:  100,
-			CreatedAt: time.Date(2024, 6, 15, 10, 0, 0, 0, time.UTC),
+	// 未来日付は計上できない
+	_, err = manager.ImportOpeningBalances(ctx, OpeningBalanceRequest{AsOf: time.Now().Add(time.Hour)}, data)
+	assert.IsType(t, &ValidationError{}, err)
+}
+
+// TestManager_RollbackOpeningBalanceLoad は期首在庫の一括取消のテスト
+func TestManager_RollbackOpeningBalanceLoad(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+	asOf := time.Now().AddDate(0, -1, 0).Truncate(time.Second)
+
+	transactionID, lotID, lotNumber, locationID := "TX-1", "LOT-1", "LOT-A", "WH-01"
+	unitCost := NewMoney(120)
+	load := &OpeningBalanceLoad{
+		ID:        "LOAD-1",
+		Reference: "OPENING-TEST",
+		AsOf:      asOf,
+		Status:    OpeningBalanceStatusPosted,
+		LineCount: 1,
+		Lines: []OpeningBalanceLine{
+			{Row: 2, ItemID: "APPLE", LocationID: locationID, Quantity: 10, UnitCost: unitCost, LotNumber: &lotNumber, TransactionID: &transactionID, LotID: &lotID},
 		},
 	}
+	original := &Transaction{
+		ID:         transactionID,
+		Type:       TransactionTypeInbound,
+		ItemID:     "APPLE",
+		ToLocation: &locationID,
+		Quantity:   10,
+		UnitCost:   &unitCost,
+		LotNumber:  &lotNumber,
+		Reference:  "OPENING-TEST",
+		CreatedAt:  asOf,
+	}
 
-	// モックの期待値設定
-	mockStorage.On("GetItem", ctx, "TEST-ITEM").Return(item, nil)
-	mockStorage.On("GetTransactionHistory", ctx, "TEST-ITEM", 10000).Return(transactions, nil)
+	mockStorage.On("GetOpeningBalanceLoad", ctx, "LOAD-1").Return(load, nil)
+	mockStorage.On("IsPeriodClosed", mock.Anything, asOf).Return(false, nil)
+	mockStorage.On("GetLatestStockSnapshotAt", mock.Anything, mock.Anything).Return(nil, nil)
 
-	// テスト実行
-	result, err := manager.GetHistoryByDateRange(ctx, "TEST-ITEM", from, to)
+	// 取り込んだ在庫が出庫・引当済みの場合は1件も取り消さない（数量は商品の小数桁数で表示）
+	mockStorage.On("GetStock", mock.Anything, "APPLE", "WH-01").Return(&Stock{ItemID: "APPLE", LocationID: "WH-01", Quantity: 10, Reserved: 6, Available: 4}, nil).Once()
+	mockStorage.On("GetItem", mock.Anything, "APPLE").Return(&Item{ID: "APPLE", QuantityPrecision: 1}, nil).Once()
+	_, err := manager.RollbackOpeningBalanceLoad(ctx, "LOAD-1", "")
+	assert.IsType(t, &BusinessRuleError{}, err)
+	assert.Contains(t, err.Error(), "取込数量: 1.0, 引当可能数量: 0.4")
+	mockStorage.AssertNotCalled(t, "GetTransaction", mock.Anything, mock.Anything)
 
-	// アサーション
+	// 移行基準日時で逆仕訳を計上し、ロットを削除
+	mockStorage.On("GetStock", mock.Anything, "APPLE", "WH-01").Return(&Stock{ItemID: "APPLE", LocationID: "WH-01", Quantity: 10, Available: 10}, nil)
+	mockStorage.On("GetTransaction", mock.Anything, "TX-1").Return(original, nil)
+	mockStorage.On("ApplyOpeningBalanceRollback", ctx, mock.MatchedBy(func(l *OpeningBalanceLoad) bool {
+		return l.Status == OpeningBalanceStatusRolledBack && l.Lines[0].ReversalID != nil && l.Lines[0].LotID == nil
+	}), mock.MatchedBy(func(stocks []*Stock) bool {
+		return len(stocks) == 1 && stocks[0].Quantity == 0
+	}), mock.MatchedBy(func(txs []*Transaction) bool {
+		return len(txs) == 1 && txs[0].Type == TransactionTypeOutbound && *txs[0].ReversalOf == "TX-1" && txs[0].CreatedAt.Equal(asOf)
+	}), []string{"LOT-1"}).Return(nil)
+
+	rolledBack, err := manager.RollbackOpeningBalanceLoad(ctx, "LOAD-1", "移行データ誤り")
 	assert.NoError(t, err)
-	assert.Len(t, result, 1)
-	assert.Equal(t, "TX-001", result[0].ID)
+	assert.Equal(t, OpeningBalanceStatusRolledBack, rolledBack.Status)
+	assert.Equal(t, "移行データ誤り", rolledBack.RollbackReason)
+	assert.NotNil(t, rolledBack.RolledBackAt)
+
+	// 取消済みの取り込みは再度取り消せない
+	_, err = manager.RollbackOpeningBalanceLoad(ctx, "LOAD-1", "")
+	assert.IsType(t, &BusinessRuleError{}, err)
 	mockStorage.AssertExpectations(t)
 }
 
@@ -485,7 +4312,7 @@ func BenchmarkManager_Add(b *testing.B) {
 	item := &Item{
 		ID:       "TEST-ITEM",
 		Name:     "テスト商品",
-		UnitCost: 1000.0,
+		UnitCost: NewMoney(1000),
 	}
 	location := &Location{
 		ID:   "TEST-LOC",
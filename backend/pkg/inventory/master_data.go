@@ -0,0 +1,596 @@
+package inventory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// masterImportProgressInterval is the number of rows between progress updates of a running job
+// 実行中のジョブの進捗を記録する行数の間隔
+const masterImportProgressInterval = 500
+
+// MasterDataType represents the kind of master data handled by bulk import and export
+// 一括取り込み・出力の対象マスタを表現
+type MasterDataType string
+
+const (
+	MasterDataItems     MasterDataType = "items"     // 商品マスタ
+	MasterDataLocations MasterDataType = "locations" // ロケーションマスタ
+)
+
+// MasterImportMode represents how rows whose ID already exists are handled
+// 既存IDの行の扱いを表現
+type MasterImportMode string
+
+const (
+	MasterImportModeCreate MasterImportMode = "create" // 新規登録のみ（既存IDの行はエラー）
+	MasterImportModeUpsert MasterImportMode = "upsert" // 既存IDは更新、新規IDは登録
+)
+
+// MasterImportStatus represents the state of a master import job
+// マスタ取り込みジョブの状態を表現
+type MasterImportStatus string
+
+const (
+	MasterImportStatusPending   MasterImportStatus = "pending"   // 実行待ち
+	MasterImportStatusRunning   MasterImportStatus = "running"   // 実行中
+	MasterImportStatusCompleted MasterImportStatus = "completed" // 完了（ドライランは検証完了）
+	MasterImportStatusFailed    MasterImportStatus = "failed"    // 失敗（検証エラーがある場合は1件も取り込まない）
+)
+
+// MasterImportRequest represents the options of a master import
+// マスタ取り込みの指定を表現
+type MasterImportRequest struct {
+	Target MasterDataType   `json:"target"`  // 対象マスタ
+	Format ReportFormat     `json:"format"`  // ファイル形式（csv / xlsx）
+	Mode   MasterImportMode `json:"mode"`    // 既存IDの扱い
+	DryRun bool             `json:"dry_run"` // 検証のみで取り込まない
+}
+
+// MasterImportJob represents a tracked background import of item or location masters
+// 商品・ロケーションマスタのバックグラウンド取り込みジョブを表現
+//
+// 全行を検証し、エラーが1件もない場合のみ取り込みます（ドライランは検証のみ）。
+// ドライランのCreatedCount・UpdatedCountは取り込んだ場合の登録・更新件数です。
+type MasterImportJob struct {
+	ID            string             `json:"id" db:"id"`                               // ジョブID
+	Target        MasterDataType     `json:"target" db:"target"`                       // 対象マスタ
+	Format        ReportFormat       `json:"format" db:"format"`                       // ファイル形式
+	Mode          MasterImportMode   `json:"mode" db:"mode"`                           // 既存IDの扱い
+	DryRun        bool               `json:"dry_run" db:"dry_run"`                     // 検証のみかどうか
+	Status        MasterImportStatus `json:"status" db:"status"`                       // 状態
+	TotalRows     int                `json:"total_rows" db:"total_rows"`               // 明細行数
+	ProcessedRows int                `json:"processed_rows" db:"processed_rows"`       // 処理済みの行数
+	CreatedCount  int                `json:"created_count" db:"created_count"`         // 登録件数
+	UpdatedCount  int                `json:"updated_count" db:"updated_count"`         // 更新件数
+	ErrorCount    int                `json:"error_count" db:"error_count"`             // エラー行数
+	Errors        []ImportRowError   `json:"errors" db:"errors"`                       // 行エラー（最大MaxImportErrors件）
+	Error         string             `json:"error,omitempty" db:"error"`               // ジョブ全体のエラー
+	CreatedBy     string             `json:"created_by" db:"created_by"`               // 実行者
+	CreatedAt     time.Time          `json:"created_at" db:"created_at"`               // 受付日時
+	StartedAt     *time.Time         `json:"started_at,omitempty" db:"started_at"`     // 開始日時
+	CompletedAt   *time.Time         `json:"completed_at,omitempty" db:"completed_at"` // 終了日時
+}
+
+// addRowError records a row error, keeping at most MaxImportErrors entries
+// 行エラーを記録（保持するのはMaxImportErrors件まで）
+func (j *MasterImportJob) addRowError(rowError ImportRowError) {
+	j.Errors = appendImportRowError(j.Errors, rowError)
+}
+
+// masterColumns defines the import and export columns of each master type
+// マスタごとの取り込み・出力の列定義（見出しはキーと日本語名のどちらでも可）
+var masterColumns = map[MasterDataType][]reportColumn{
+	MasterDataItems: {
+		{"id", "商品ID"}, {"name", "商品名"}, {"sku", "SKU"}, {"description", "商品説明"}, {"category", "カテゴリ"},
+		{"unit_cost", "単価"}, {"quantity_precision", "数量の小数桁数"}, {"parent_item_id", "親商品ID"},
+		{"attributes", "カスタム属性"}, {"is_active", "有効"},
+	},
+	MasterDataLocations: {
+		{"id", "ロケーションID"}, {"name", "ロケーション名"}, {"type", "タイプ"}, {"address", "住所"},
+		{"capacity", "最大収容量"}, {"is_active", "有効"}, {"pick_path", "ピッキングパス"},
+	},
+}
+
+// parseMasterFile reads a CSV or XLSX import file and maps its header to the master columns
+// CSV・XLSXの取り込みファイルを読み込み、見出しをマスタの列に対応付ける（IDと名前の列は必須）
+func parseMasterFile(target MasterDataType, format ReportFormat, data []byte) (*importTable, error) {
+	columns, ok := masterColumns[target]
+	if !ok {
+		return nil, NewValidationError("target", "未対応の取り込み対象です", string(target))
+	}
+	return parseImportFile(columns, format, data, "id", "name")
+}
+
+// itemFromRow overlays the columns present in a row onto an item and returns the conversion errors
+// 行に含まれる列の値を商品に反映し、変換エラーを返す（ファイルにない列は元の値を維持）
+func itemFromRow(row importRow, item *Item) []ImportRowError {
+	var errors []ImportRowError
+	fail := func(field, message, value string) {
+		errors = append(errors, ImportRowError{Row: row.number, ID: item.ID, Field: field, Message: message, Value: value})
+	}
+
+	item.Name = row.values["name"]
+	for key, target := range map[string]*string{"sku": &item.SKU, "description": &item.Description, "category": &item.Category} {
+		if row.has(key) {
+			*target = row.values[key]
+		}
+	}
+	if value := row.values["unit_cost"]; row.has("unit_cost") {
+		item.UnitCost = 0
+		if value != "" {
+			cost, err := ParseMoney(value)
+			if err != nil {
+				fail("unit_cost", "単価が無効な金額です", value)
+			}
+			item.UnitCost = cost
+		}
+	}
+	if value := row.values["quantity_precision"]; row.has("quantity_precision") {
+		item.QuantityPrecision = 0
+		if value != "" {
+			precision, err := strconv.Atoi(value)
+			if err != nil {
+				fail("quantity_precision", "数量の小数桁数は整数で指定してください", value)
+			}
+			item.QuantityPrecision = precision
+		}
+	}
+	if value := row.values["parent_item_id"]; row.has("parent_item_id") {
+		item.ParentItemID = nil
+		if value != "" {
+			item.ParentItemID = &value
+		}
+	}
+	if value := row.values["attributes"]; row.has("attributes") {
+		item.Attributes = nil
+		if value != "" {
+			if err := json.Unmarshal([]byte(value), &item.Attributes); err != nil {
+				fail("attributes", "カスタム属性はJSONオブジェクトで指定してください", value)
+			}
+		}
+	}
+	if value := row.values["is_active"]; row.has("is_active") {
+		active, err := parseImportBool(value, true)
+		if err != nil {
+			fail("is_active", "有効はtrueまたはfalseで指定してください", value)
+		}
+		item.IsActive = active
+	}
+	return errors
+}
+
+// locationFromRow overlays the columns present in a row onto a location and returns the conversion errors
+// 行に含まれる列の値をロケーションに反映し、変換エラーを返す（ファイルにない列は元の値を維持）
+func locationFromRow(row importRow, location *Location) []ImportRowError {
+	var errors []ImportRowError
+	fail := func(field, message, value string) {
+		errors = append(errors, ImportRowError{Row: row.number, ID: location.ID, Field: field, Message: message, Value: value})
+	}
+
+	location.Name = row.values["name"]
+	for key, target := range map[string]*string{"type": &location.Type, "address": &location.Address, "pick_path": &location.PickPath} {
+		if row.has(key) {
+			*target = row.values[key]
+		}
+	}
+	if value := row.values["capacity"]; row.has("capacity") {
+		location.Capacity = 0
+		if value != "" {
+			capacity, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				fail("capacity", "最大収容量は整数で指定してください", value)
+			}
+			location.Capacity = capacity
+		}
+	}
+	if value := row.values["is_active"]; row.has("is_active") {
+		active, err := parseImportBool(value, true)
+		if err != nil {
+			fail("is_active", "有効はtrueまたはfalseで指定してください", value)
+		}
+		location.IsActive = active
+	}
+	return errors
+}
+
+// ===== MasterDataManager実装 =====
+
+// StartMasterImport parses an import file, registers a job and runs it in the background
+// 取り込みファイルを解析してジョブを登録し、バックグラウンドで実行
+//
+// ファイル形式・見出しの誤りはジョブを登録せずにエラーを返します。行の検証結果はジョブの行エラーとして記録されます。
+func (m *Manager) StartMasterImport(ctx context.Context, request MasterImportRequest, data []byte) (*MasterImportJob, error) {
+	if request.Mode == "" {
+		request.Mode = MasterImportModeCreate
+	}
+	if request.Mode != MasterImportModeCreate && request.Mode != MasterImportModeUpsert {
+		return nil, NewValidationError("mode", "取り込みモードはcreateまたはupsertで指定してください", string(request.Mode))
+	}
+	if request.Format == "" {
+		request.Format = ReportFormatCSV
+	}
+	table, err := parseMasterFile(request.Target, request.Format, data)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &MasterImportJob{
+		ID:        NewBatchID(),
+		Target:    request.Target,
+		Format:    request.Format,
+		Mode:      request.Mode,
+		DryRun:    request.DryRun,
+		Status:    MasterImportStatusPending,
+		TotalRows: len(table.rows),
+		Errors:    make([]ImportRowError, 0),
+		CreatedBy: m.getUserFromContext(ctx),
+		CreatedAt: time.Now(),
+	}
+	if err := m.storage.CreateMasterImportJob(ctx, job); err != nil {
+		return nil, NewStorageError("create_master_import_job", "取り込みジョブ登録に失敗しました", err)
+	}
+
+	m.logger.Info("マスタ取り込み受付",
+		zap.String("job_id", job.ID),
+		zap.String("target", string(job.Target)),
+		zap.String("mode", string(job.Mode)),
+		zap.Bool("dry_run", job.DryRun),
+		zap.Int("rows", job.TotalRows),
+	)
+
+	// リクエスト終了後も継続するよう、キャンセルを引き継がないコンテキストで実行
+	background := *job
+	go m.runMasterImport(context.WithoutCancel(ctx), &background, table)
+
+	return job, nil
+}
+
+// GetMasterImportJob gets a master import job with its progress and row errors
+// マスタ取り込みジョブを進捗・行エラーとともに取得
+func (m *Manager) GetMasterImportJob(ctx context.Context, jobID string) (*MasterImportJob, error) {
+	job, err := m.storage.GetMasterImportJob(ctx, jobID)
+	if err != nil {
+		if err == ErrMasterImportJobNotFound {
+			return nil, ErrMasterImportJobNotFound
+		}
+		return nil, NewStorageError("get_master_import_job", "取り込みジョブ取得に失敗しました", err)
+	}
+	return job, nil
+}
+
+// ListMasterImportJobs lists master import jobs, newest first
+// マスタ取り込みジョブ一覧を新しい順に取得
+func (m *Manager) ListMasterImportJobs(ctx context.Context, offset, limit int) ([]MasterImportJob, error) {
+	jobs, err := m.storage.ListMasterImportJobs(ctx, offset, limit)
+	if err != nil {
+		return nil, NewStorageError("list_master_import_jobs", "取り込みジョブ一覧取得に失敗しました", err)
+	}
+	return jobs, nil
+}
+
+// ExportMasterData writes every item or location in the import file layout
+// 商品・ロケーションの全件を取り込みファイルと同じ列構成で出力
+//
+// 出力したファイルはそのままupsertモードで取り込めます。アーカイブ済みはincludeArchivedの場合のみ出力します。
+func (m *Manager) ExportMasterData(ctx context.Context, w io.Writer, target MasterDataType, format ReportFormat, includeArchived bool) error {
+	columns, ok := masterColumns[target]
+	if !ok {
+		return NewValidationError("target", "未対応の出力対象です", string(target))
+	}
+
+	var rows [][]interface{}
+	switch target {
+	case MasterDataItems:
+		page, err := m.storage.ListItems(ctx, ItemSearchFilter{Sort: ItemSortName, IncludeArchived: includeArchived})
+		if err != nil {
+			return NewStorageError("list_items", "商品一覧取得に失敗しました", err)
+		}
+		for _, item := range page.Items {
+			attributes := ""
+			if len(item.Attributes) > 0 {
+				encoded, err := json.Marshal(item.Attributes)
+				if err != nil {
+					return fmt.Errorf("商品属性のJSON変換に失敗しました: %w", err)
+				}
+				attributes = string(encoded)
+			}
+			rows = append(rows, []interface{}{
+				item.ID, item.Name, item.SKU, item.Description, item.Category, item.UnitCost,
+				item.QuantityPrecision, item.ParentItemID, attributes, strconv.FormatBool(item.IsActive),
+			})
+		}
+	case MasterDataLocations:
+		const pageSize = 500
+		for offset := 0; ; offset += pageSize {
+			locations, err := m.storage.ListLocations(ctx, offset, pageSize, includeArchived)
+			if err != nil {
+				return NewStorageError("list_locations", "ロケーション一覧取得に失敗しました", err)
+			}
+			for _, location := range locations {
+				rows = append(rows, []interface{}{
+					location.ID, location.Name, location.Type, location.Address, location.Capacity,
+					strconv.FormatBool(location.IsActive), location.PickPath,
+				})
+			}
+			if len(locations) < pageSize {
+				break
+			}
+		}
+	}
+
+	sink, err := newReportSink(w, format, columns)
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := sink.writeRow(row); err != nil {
+			return err
+		}
+	}
+	return sink.close()
+}
+
+// masterImportPlan is a validated row ready to be written
+// 検証済みで書き込み可能な行
+type masterImportPlan struct {
+	row      int
+	item     *Item
+	location *Location
+	exists   bool
+}
+
+// runMasterImport validates every row and, unless it is a dry run or any row failed, writes them
+// 全行を検証し、ドライランでなくエラーが1件もない場合に書き込む
+func (m *Manager) runMasterImport(ctx context.Context, job *MasterImportJob, table *importTable) {
+	defer func() {
+		if r := recover(); r != nil {
+			m.finishMasterImport(ctx, job, MasterImportStatusFailed, fmt.Sprintf("取り込み中に予期しないエラーが発生しました: %v", r))
+		}
+	}()
+
+	started := time.Now()
+	job.Status = MasterImportStatusRunning
+	job.StartedAt = &started
+	m.saveMasterImportProgress(ctx, job)
+
+	var plans []masterImportPlan
+	var err error
+	switch job.Target {
+	case MasterDataItems:
+		plans, err = m.planItemImport(ctx, job, table)
+	case MasterDataLocations:
+		plans, err = m.planLocationImport(ctx, job, table)
+	}
+	if err != nil {
+		m.finishMasterImport(ctx, job, MasterImportStatusFailed, err.Error())
+		return
+	}
+
+	for _, plan := range plans {
+		if plan.exists {
+			job.UpdatedCount++
+		} else {
+			job.CreatedCount++
+		}
+	}
+	if job.ErrorCount > 0 {
+		job.CreatedCount, job.UpdatedCount = 0, 0
+		if job.DryRun {
+			m.finishMasterImport(ctx, job, MasterImportStatusCompleted, "")
+		} else {
+			m.finishMasterImport(ctx, job, MasterImportStatusFailed, "検証エラーがあるため取り込みませんでした")
+		}
+		return
+	}
+	if job.DryRun {
+		m.finishMasterImport(ctx, job, MasterImportStatusCompleted, "")
+		return
+	}
+
+	// 書き込み
+	job.CreatedCount, job.UpdatedCount, job.ProcessedRows = 0, 0, 0
+	for i, plan := range plans {
+		if err := m.writeMasterImportPlan(ctx, plan); err != nil {
+			id := ""
+			if plan.item != nil {
+				id = plan.item.ID
+			} else {
+				id = plan.location.ID
+			}
+			job.ErrorCount++
+			job.addRowError(rowErrorFrom(plan.row, id, err))
+		} else if plan.exists {
+			job.UpdatedCount++
+		} else {
+			job.CreatedCount++
+		}
+		job.ProcessedRows = i + 1
+		if job.ProcessedRows%masterImportProgressInterval == 0 {
+			m.saveMasterImportProgress(ctx, job)
+		}
+	}
+
+	if job.ErrorCount > 0 {
+		m.finishMasterImport(ctx, job, MasterImportStatusFailed, "一部の行の書き込みに失敗しました")
+		return
+	}
+	m.finishMasterImport(ctx, job, MasterImportStatusCompleted, "")
+}
+
+// planItemImport validates item rows against ValidateItem, the category schemas and the existing items
+// 商品の行をValidateItem・カテゴリ属性スキーマ・既存の商品に照らして検証
+func (m *Manager) planItemImport(ctx context.Context, job *MasterImportJob, table *importTable) ([]masterImportPlan, error) {
+	var plans []masterImportPlan
+	ids := make(map[string]int)
+	skus := make(map[string]int)
+	now := time.Now()
+
+	for i, row := range table.rows {
+		job.ProcessedRows = i + 1
+		id := row.values["id"]
+		reject := func(rowError ImportRowError) {
+			job.ErrorCount++
+			job.addRowError(rowError)
+		}
+
+		if first, ok := ids[id]; ok && id != "" {
+			reject(ImportRowError{Row: row.number, ID: id, Field: "id", Message: fmt.Sprintf("%d行目と商品IDが重複しています", first), Value: id})
+			continue
+		}
+		ids[id] = row.number
+
+		current, err := m.storage.GetItem(ctx, id)
+		exists := err == nil
+		if err != nil && err != ErrItemNotFound {
+			return nil, NewStorageError("get_item", "商品取得に失敗しました", err)
+		}
+		if exists && job.Mode == MasterImportModeCreate {
+			reject(ImportRowError{Row: row.number, ID: id, Field: "id", Message: "商品IDは既に存在します", Value: id})
+			continue
+		}
+		if exists && current.ArchivedAt != nil {
+			reject(rowErrorFrom(row.number, id, ErrItemArchived))
+			continue
+		}
+
+		item := &Item{ID: id, IsActive: true, CreatedAt: now}
+		if exists {
+			copied := *current
+			item = &copied
+		}
+		item.UpdatedAt = now
+		if rowErrors := itemFromRow(row, item); len(rowErrors) > 0 {
+			for _, rowError := range rowErrors {
+				reject(rowError)
+			}
+			continue
+		}
+		if first, ok := skus[item.SKU]; ok && item.SKU != "" {
+			reject(ImportRowError{Row: row.number, ID: id, Field: "sku", Message: fmt.Sprintf("%d行目とSKUが重複しています", first), Value: item.SKU})
+			continue
+		}
+		skus[item.SKU] = row.number
+
+		err = m.validateItem(ctx, item)
+		if err == nil && exists {
+			err = m.checkItemUpdate(ctx, current, item)
+		}
+		if err != nil {
+			if _, isStorage := err.(*StorageError); isStorage {
+				return nil, err
+			}
+			reject(rowErrorFrom(row.number, id, err))
+			continue
+		}
+		plans = append(plans, masterImportPlan{row: row.number, item: item, exists: exists})
+	}
+	return plans, nil
+}
+
+// planLocationImport validates location rows against ValidateLocation and the existing locations
+// ロケーションの行をValidateLocation・既存のロケーションに照らして検証
+func (m *Manager) planLocationImport(ctx context.Context, job *MasterImportJob, table *importTable) ([]masterImportPlan, error) {
+	var plans []masterImportPlan
+	ids := make(map[string]int)
+	now := time.Now()
+
+	for i, row := range table.rows {
+		job.ProcessedRows = i + 1
+		id := row.values["id"]
+		reject := func(rowError ImportRowError) {
+			job.ErrorCount++
+			job.addRowError(rowError)
+		}
+
+		if first, ok := ids[id]; ok && id != "" {
+			reject(ImportRowError{Row: row.number, ID: id, Field: "id", Message: fmt.Sprintf("%d行目とロケーションIDが重複しています", first), Value: id})
+			continue
+		}
+		ids[id] = row.number
+
+		current, err := m.storage.GetLocation(ctx, id)
+		exists := err == nil
+		if err != nil && err != ErrLocationNotFound {
+			return nil, NewStorageError("get_location", "ロケーション取得に失敗しました", err)
+		}
+		if exists && job.Mode == MasterImportModeCreate {
+			reject(ImportRowError{Row: row.number, ID: id, Field: "id", Message: "ロケーションIDは既に存在します", Value: id})
+			continue
+		}
+		if exists && current.ArchivedAt != nil {
+			reject(rowErrorFrom(row.number, id, ErrLocationArchived))
+			continue
+		}
+
+		location := &Location{ID: id, IsActive: true, CreatedAt: now}
+		if exists {
+			copied := *current
+			location = &copied
+		}
+		location.UpdatedAt = now
+		if rowErrors := locationFromRow(row, location); len(rowErrors) > 0 {
+			for _, rowError := range rowErrors {
+				reject(rowError)
+			}
+			continue
+		}
+		if err := ValidateLocation(location); err != nil {
+			reject(rowErrorFrom(row.number, id, err))
+			continue
+		}
+		plans = append(plans, masterImportPlan{row: row.number, location: location, exists: exists})
+	}
+	return plans, nil
+}
+
+// writeMasterImportPlan creates or updates the master of a validated row
+// 検証済みの行のマスタを登録または更新
+func (m *Manager) writeMasterImportPlan(ctx context.Context, plan masterImportPlan) error {
+	switch {
+	case plan.item != nil && plan.exists:
+		return m.storage.UpdateItem(ctx, plan.item)
+	case plan.item != nil:
+		return m.storage.CreateItem(ctx, plan.item)
+	case plan.exists:
+		return m.storage.UpdateLocation(ctx, plan.location)
+	default:
+		return m.storage.CreateLocation(ctx, plan.location)
+	}
+}
+
+// saveMasterImportProgress records the progress of a running job; failures are only logged
+// 実行中のジョブの進捗を記録（失敗はログ出力のみ）
+func (m *Manager) saveMasterImportProgress(ctx context.Context, job *MasterImportJob) {
+	if err := m.storage.UpdateMasterImportJob(ctx, job); err != nil {
+		m.logger.Error("取り込みジョブの進捗記録に失敗しました",
+			zap.String("job_id", job.ID),
+			zap.Error(err),
+		)
+	}
+}
+
+// finishMasterImport records the final state of a job
+// ジョブの最終状態を記録
+func (m *Manager) finishMasterImport(ctx context.Context, job *MasterImportJob, status MasterImportStatus, message string) {
+	completed := time.Now()
+	job.Status = status
+	job.Error = message
+	job.CompletedAt = &completed
+	m.saveMasterImportProgress(ctx, job)
+
+	m.logger.Info("マスタ取り込み終了",
+		zap.String("job_id", job.ID),
+		zap.String("status", string(job.Status)),
+		zap.Bool("dry_run", job.DryRun),
+		zap.Int("created", job.CreatedCount),
+		zap.Int("updated", job.UpdatedCount),
+		zap.Int("errors", job.ErrorCount),
+	)
+}
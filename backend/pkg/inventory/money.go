@@ -0,0 +1,428 @@
+package inventory
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Money is an exact monetary amount held in ten-thousandths, the scale of the DECIMAL(12,4) cost columns
+// DECIMAL(12,4)の原価列と同じ1万分の1単位で保持する正確な金額
+//
+// 加算と数量倍は誤差なく計算されるため、合計はデータベースのSUMと一致します。
+// 除算（平均原価など）は小数4桁に四捨五入し、通貨の最小単位への丸めはRoundで明示的に行います。
+type Money int64
+
+const (
+	moneyDecimals = 4     // 保持する小数桁数
+	moneyScale    = 10000 // 1通貨単位あたりの内部単位数
+)
+
+// NewMoney creates an amount of whole currency units
+// 通貨単位の整数から金額を作成
+func NewMoney(units int64) Money {
+	return Money(units * moneyScale)
+}
+
+// ParseMoney parses a decimal string such as "1234.5678" exactly, rounding extra digits half away from zero
+// "1234.5678"のような10進数文字列を正確に解析（5桁目以降は0から遠い方向へ四捨五入）
+func ParseMoney(s string) (Money, error) {
+	units, err := parseDecimal(s, moneyDecimals, "金額")
+	return Money(units), err
+}
+
+// parseDecimal parses a decimal string into an integer of 10^-decimals units, rounding half away from zero
+// 10進数文字列を10^-decimals単位の整数に解析（超過桁は0から遠い方向へ四捨五入）
+func parseDecimal(s string, decimals int, label string) (int64, error) {
+	text := strings.TrimSpace(s)
+	if text == "" {
+		return 0, fmt.Errorf("%sが空です", label)
+	}
+
+	negative := false
+	switch text[0] {
+	case '-':
+		negative = true
+		text = text[1:]
+	case '+':
+		text = text[1:]
+	}
+
+	whole, fraction, _ := strings.Cut(text, ".")
+	if whole == "" && fraction == "" {
+		return 0, fmt.Errorf("無効な%sです: %s", label, s)
+	}
+	for _, part := range []string{whole, fraction} {
+		for _, r := range part {
+			if r < '0' || r > '9' {
+				return 0, fmt.Errorf("無効な%sです: %s", label, s)
+			}
+		}
+	}
+
+	scale := int64(math.Pow10(decimals))
+	var units int64
+	if whole != "" {
+		parsed, err := strconv.ParseInt(whole, 10, 64)
+		if err != nil || parsed > math.MaxInt64/scale-1 {
+			return 0, fmt.Errorf("%sが範囲外です: %s", label, s)
+		}
+		units = parsed * scale
+	}
+
+	// 小数部は指定桁数に揃え、次の桁で四捨五入
+	roundUp := len(fraction) > decimals && fraction[decimals] >= '5'
+	if len(fraction) > decimals {
+		fraction = fraction[:decimals]
+	}
+	fraction += strings.Repeat("0", decimals-len(fraction))
+	if fraction != "" {
+		fractionUnits, _ := strconv.ParseInt(fraction, 10, 64)
+		units += fractionUnits
+	}
+	if roundUp {
+		units++
+	}
+
+	if negative {
+		units = -units
+	}
+	return units, nil
+}
+
+// MustParseMoney parses a decimal string and panics if it is invalid (for constants and tests)
+// 10進数文字列を解析し、無効な場合はpanic（定数・テスト用）
+func MustParseMoney(s string) Money {
+	m, err := ParseMoney(s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// MoneyFromFloat converts a float using its shortest decimal representation, rounded to four decimals
+// 浮動小数点数を最短の10進表現で変換（小数4桁に四捨五入）
+func MoneyFromFloat(f float64) Money {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0
+	}
+	m, err := ParseMoney(strconv.FormatFloat(f, 'f', -1, 64))
+	if err != nil {
+		return Money(math.Round(f * moneyScale))
+	}
+	return m
+}
+
+// Float64 returns the amount as a float for ratios and statistics (not for further money arithmetic)
+// 比率・統計計算用に金額を浮動小数点数で返す（金額計算には使用しない）
+func (m Money) Float64() float64 {
+	return float64(m) / moneyScale
+}
+
+// Mul returns the amount multiplied by a quantity
+// 金額を数量倍した値を返す
+func (m Money) Mul(quantity int64) Money {
+	return m * Money(quantity)
+}
+
+// MulQuantity returns the amount per whole unit multiplied by a stored quantity of the given precision
+// 単位あたりの金額に保存単位の数量を掛けた値を返す（小数4桁に0から遠い方向へ四捨五入）
+func (m Money) MulQuantity(quantity int64, precision int) Money {
+	if precision <= 0 {
+		return m.Mul(quantity)
+	}
+	return m.MulDiv(quantity, QuantityScale(precision))
+}
+
+// MulDiv returns amount × numerator ÷ denominator without intermediate overflow, rounded half away from zero
+// 金額 × 分子 ÷ 分母を途中の桁あふれなしで計算（小数4桁に0から遠い方向へ四捨五入）
+func (m Money) MulDiv(numerator, denominator int64) Money {
+	if denominator == 0 {
+		return 0
+	}
+	product := new(big.Int).Mul(big.NewInt(int64(m)), big.NewInt(numerator))
+	divisor := big.NewInt(denominator)
+	if divisor.Sign() < 0 {
+		product.Neg(product)
+		divisor.Neg(divisor)
+	}
+
+	quotient, remainder := new(big.Int).QuoRem(product, divisor, new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(remainder), big.NewInt(2)).Cmp(divisor) >= 0 {
+		quotient.Add(quotient, big.NewInt(int64(product.Sign())))
+	}
+	return Money(quotient.Int64())
+}
+
+// Div divides the amount by a quantity, rounding half away from zero to four decimals
+// 金額を数量で割った値を返す（小数4桁に0から遠い方向へ四捨五入）
+func (m Money) Div(quantity int64) Money {
+	if quantity == 0 {
+		return 0
+	}
+	return Money(divRound(int64(m), quantity, RoundingHalfUp))
+}
+
+// Convert converts the amount into another currency at an exchange rate, rounding half away from zero to four decimals
+// 為替レートで別通貨の金額に換算（小数4桁に0から遠い方向へ四捨五入）
+func (m Money) Convert(rate Rate) Money {
+	return m.MulDiv(int64(rate), rateScale)
+}
+
+// Round rounds the amount to the minor unit of a currency using the currency's rounding mode
+// 通貨の最小単位まで、通貨ごとの丸め方法で丸める
+func (m Money) Round(rule CurrencyRule) Money {
+	digits := rule.MinorDigits
+	if digits >= moneyDecimals || digits < 0 {
+		return m
+	}
+	unit := int64(math.Pow10(moneyDecimals - digits))
+	return Money(divRound(int64(m), unit, rule.Rounding) * unit)
+}
+
+// String formats the amount as a plain decimal without trailing zeros, e.g. "1234.5"
+// 末尾の0を除いた10進数表記で返す（例: "1234.5"）
+func (m Money) String() string {
+	return formatDecimal(int64(m), moneyDecimals)
+}
+
+// formatDecimal formats an integer of 10^-decimals units as a decimal without trailing zeros
+// 10^-decimals単位の整数を末尾の0を除いた10進数表記にする
+func formatDecimal(units int64, decimals int) string {
+	sign := ""
+	if units < 0 {
+		sign = "-"
+		units = -units
+	}
+	scale := int64(math.Pow10(decimals))
+	whole := units / scale
+	fraction := units % scale
+	if fraction == 0 {
+		return sign + strconv.FormatInt(whole, 10)
+	}
+	digits := strings.TrimRight(fmt.Sprintf("%0*d", decimals, fraction), "0")
+	return sign + strconv.FormatInt(whole, 10) + "." + digits
+}
+
+// MarshalJSON encodes the amount as an exact JSON number
+// 金額を正確なJSON数値としてエンコード
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON decodes a JSON number or numeric string without going through float64
+// JSON数値または数値文字列をfloat64を経由せずにデコード
+func (m *Money) UnmarshalJSON(data []byte) error {
+	text := strings.TrimSpace(string(data))
+	if text == "null" {
+		*m = 0
+		return nil
+	}
+	text = strings.Trim(text, `"`)
+
+	// 指数表記はfloat64で解釈
+	if strings.ContainsAny(text, "eE") {
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return fmt.Errorf("無効な金額です: %s", text)
+		}
+		*m = MoneyFromFloat(f)
+		return nil
+	}
+
+	parsed, err := ParseMoney(text)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Scan reads a DECIMAL column exactly from its text representation
+// DECIMAL列をテキスト表現から正確に読み込む
+func (m *Money) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*m = 0
+		return nil
+	case []byte:
+		parsed, err := ParseMoney(string(v))
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	case string:
+		parsed, err := ParseMoney(v)
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	case int64:
+		*m = NewMoney(v)
+		return nil
+	case float64:
+		*m = MoneyFromFloat(v)
+		return nil
+	default:
+		return fmt.Errorf("金額に変換できない型です: %T", src)
+	}
+}
+
+// Value writes the amount as a decimal string so the database stores it without float conversion
+// 金額を10進数文字列として書き込み、浮動小数点数を経由せずに保存
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// Rate is an exact exchange rate held in units of 10^-8, the scale of the DECIMAL(18,8) rate column
+// DECIMAL(18,8)の為替レート列と同じ1億分の1単位で保持する正確な為替レート
+//
+// 外貨1単位あたりの基準通貨の金額を表します（例: USD→JPYの150.25）。
+type Rate int64
+
+const (
+	rateDecimals = 8         // 保持する小数桁数
+	rateScale    = 100000000 // レート1あたりの内部単位数
+)
+
+// ParseRate parses a decimal exchange rate such as "0.00673" exactly, rounding extra digits half away from zero
+// "0.00673"のような10進数の為替レートを正確に解析（9桁目以降は0から遠い方向へ四捨五入）
+func ParseRate(s string) (Rate, error) {
+	units, err := parseDecimal(s, rateDecimals, "為替レート")
+	return Rate(units), err
+}
+
+// String formats the rate as a plain decimal without trailing zeros
+// 末尾の0を除いた10進数表記で返す
+func (r Rate) String() string {
+	return formatDecimal(int64(r), rateDecimals)
+}
+
+// MarshalJSON encodes the rate as an exact JSON number
+// 為替レートを正確なJSON数値としてエンコード
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalJSON decodes a JSON number or numeric string without going through float64
+// JSON数値または数値文字列をfloat64を経由せずにデコード
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	text := strings.Trim(strings.TrimSpace(string(data)), `"`)
+	if text == "null" {
+		*r = 0
+		return nil
+	}
+	parsed, err := ParseRate(text)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+// Scan reads a DECIMAL rate column exactly from its text representation
+// DECIMAL列の為替レートをテキスト表現から正確に読み込む
+func (r *Rate) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*r = 0
+		return nil
+	case []byte:
+		return r.UnmarshalJSON(v)
+	case string:
+		return r.UnmarshalJSON([]byte(v))
+	case int64:
+		*r = Rate(v * rateScale)
+		return nil
+	default:
+		return fmt.Errorf("為替レートに変換できない型です: %T", src)
+	}
+}
+
+// Value writes the rate as a decimal string
+// 為替レートを10進数文字列として書き込む
+func (r Rate) Value() (driver.Value, error) {
+	return r.String(), nil
+}
+
+// RoundingMode defines how an amount is rounded to a currency's minor unit
+// 通貨の最小単位への丸め方法を定義
+type RoundingMode string
+
+const (
+	RoundingHalfUp   RoundingMode = "half_up"   // 四捨五入（0から遠い方向）
+	RoundingHalfEven RoundingMode = "half_even" // 偶数丸め（銀行丸め）
+	RoundingDown     RoundingMode = "down"      // 切り捨て（0方向）
+	RoundingUp       RoundingMode = "up"        // 切り上げ（0から遠い方向）
+)
+
+// CurrencyRule defines the minor unit digits and the rounding mode of a currency
+// 通貨の最小単位の桁数と丸め方法を定義
+type CurrencyRule struct {
+	Code        string       `json:"code"`         // 通貨コード（ISO 4217）
+	MinorDigits int          `json:"minor_digits"` // 最小単位の小数桁数（円は0）
+	Rounding    RoundingMode `json:"rounding"`     // 丸め方法
+}
+
+// DefaultCurrency is the currency used when none is configured
+// 設定がない場合に使用する通貨
+const DefaultCurrency = "JPY"
+
+// currencyRules holds the rounding rules of supported currencies
+// 対応通貨の丸めルール
+var currencyRules = map[string]CurrencyRule{
+	"JPY": {Code: "JPY", MinorDigits: 0, Rounding: RoundingHalfUp},
+	"KRW": {Code: "KRW", MinorDigits: 0, Rounding: RoundingHalfUp},
+	"USD": {Code: "USD", MinorDigits: 2, Rounding: RoundingHalfUp},
+	"CNY": {Code: "CNY", MinorDigits: 2, Rounding: RoundingHalfUp},
+	"EUR": {Code: "EUR", MinorDigits: 2, Rounding: RoundingHalfEven},
+	"GBP": {Code: "GBP", MinorDigits: 2, Rounding: RoundingHalfEven},
+}
+
+// LookupCurrencyRule returns the rounding rule of a currency code
+// 通貨コードの丸めルールを返す
+func LookupCurrencyRule(code string) (CurrencyRule, bool) {
+	rule, ok := currencyRules[strings.ToUpper(code)]
+	return rule, ok
+}
+
+// divRound divides two integers, rounding the quotient with the given mode
+// 整数の除算を指定の丸め方法で行う
+func divRound(numerator, denominator int64, mode RoundingMode) int64 {
+	if denominator < 0 {
+		numerator, denominator = -numerator, -denominator
+	}
+	quotient := numerator / denominator
+	remainder := numerator % denominator
+	if remainder == 0 {
+		return quotient
+	}
+
+	sign := int64(1)
+	if numerator < 0 {
+		sign = -1
+		remainder = -remainder
+	}
+
+	switch mode {
+	case RoundingDown:
+		return quotient
+	case RoundingUp:
+		return quotient + sign
+	case RoundingHalfEven:
+		if twice := remainder * 2; twice > denominator || (twice == denominator && quotient%2 != 0) {
+			return quotient + sign
+		}
+		return quotient
+	default:
+		if remainder*2 >= denominator {
+			return quotient + sign
+		}
+		return quotient
+	}
+}
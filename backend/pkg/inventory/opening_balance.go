@@ -0,0 +1,627 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// OpeningBalanceReferencePrefix prefixes the default reference of opening balance transactions
+// 期首在庫の入庫トランザクションの既定の参照番号の接頭辞
+const OpeningBalanceReferencePrefix = "OPENING-"
+
+// OpeningBalanceMetadataKey is the transaction metadata key holding the opening balance load ID
+// 期首在庫の取り込みIDを保持するトランザクションメタデータのキー
+const OpeningBalanceMetadataKey = "opening_balance_load"
+
+// OpeningBalanceStatus represents the state of an opening balance load
+// 期首在庫の取り込みの状態を表現
+type OpeningBalanceStatus string
+
+const (
+	OpeningBalanceStatusValidated  OpeningBalanceStatus = "validated"   // 検証済み（ドライラン。計上していない）
+	OpeningBalanceStatusRejected   OpeningBalanceStatus = "rejected"    // 検証エラーのため計上していない
+	OpeningBalanceStatusPosted     OpeningBalanceStatus = "posted"      // 計上済み
+	OpeningBalanceStatusFailed     OpeningBalanceStatus = "failed"      // 計上に失敗（何も計上していない）
+	OpeningBalanceStatusRolledBack OpeningBalanceStatus = "rolled_back" // 一括取消済み
+)
+
+// OpeningBalanceRequest represents the options of an opening balance import
+// 期首在庫の取り込みの指定を表現
+type OpeningBalanceRequest struct {
+	Format    ReportFormat `json:"format"`    // ファイル形式（csv / xlsx）
+	AsOf      time.Time    `json:"as_of"`     // 移行基準日時（入庫の計上日時）
+	Reference string       `json:"reference"` // 参照番号（空の場合はOPENING-基準日）
+	DryRun    bool         `json:"dry_run"`   // 検証のみで計上しない
+}
+
+// OpeningBalanceLine represents the starting stock of one item, location and lot
+// 商品・ロケーション・ロットごとの期首在庫の明細を表現
+type OpeningBalanceLine struct {
+	Row           int        `json:"row" db:"row_number"`                          // 取り込みファイルの行番号
+	ItemID        string     `json:"item_id" db:"item_id"`                         // 商品ID
+	LocationID    string     `json:"location_id" db:"location_id"`                 // ロケーションID
+	Quantity      int64      `json:"quantity" db:"quantity"`                       // 数量（商品の保存単位）
+	UnitCost      Money      `json:"unit_cost" db:"unit_cost"`                     // 単価（取り込みファイルの通貨）
+	Currency      string     `json:"currency" db:"currency"`                       // 単価の通貨
+	Value         Money      `json:"value" db:"value"`                             // 基準通貨での金額
+	LotNumber     *string    `json:"lot_number,omitempty" db:"lot_number"`         // ロット番号
+	ExpiryDate    *time.Time `json:"expiry_date,omitempty" db:"expiry_date"`       // 有効期限
+	TransactionID *string    `json:"transaction_id,omitempty" db:"transaction_id"` // 入庫トランザクションID
+	LotID         *string    `json:"lot_id,omitempty" db:"lot_id"`                 // 作成したロットID
+	ReversalID    *string    `json:"reversal_id,omitempty" db:"reversal_id"`       // 取消トランザクションID
+}
+
+// OpeningBalanceLoad represents one load of starting stock migrated from another system
+// 旧システムから移行する期首在庫の1回分の取り込みを表現
+//
+// 明細ごとに移行基準日時で入庫を計上し、単価付きの入庫が先入先出の最初の原価層になります。
+// 計上した入庫はまとめて取り消せます（RollbackOpeningBalanceLoad）。
+type OpeningBalanceLoad struct {
+	ID             string               `json:"id" db:"id"`                                     // 取り込みID
+	Reference      string               `json:"reference" db:"reference"`                       // 参照番号
+	AsOf           time.Time            `json:"as_of" db:"as_of"`                               // 移行基準日時
+	Status         OpeningBalanceStatus `json:"status" db:"status"`                             // 状態
+	LineCount      int                  `json:"line_count" db:"line_count"`                     // 明細数
+	TotalValue     Money                `json:"total_value" db:"total_value"`                   // 基準通貨での合計金額
+	Lines          []OpeningBalanceLine `json:"lines,omitempty" db:"-"`                         // 明細
+	ErrorCount     int                  `json:"error_count" db:"-"`                             // エラー行数（計上前の検証のみ）
+	Errors         []ImportRowError     `json:"errors,omitempty" db:"-"`                        // 行エラー（最大MaxImportErrors件）
+	Error          string               `json:"error,omitempty" db:"error"`                     // 計上・取消の失敗理由
+	CreatedBy      string               `json:"created_by" db:"created_by"`                     // 取り込み実行者
+	CreatedAt      time.Time            `json:"created_at" db:"created_at"`                     // 取り込み日時
+	PostedAt       *time.Time           `json:"posted_at,omitempty" db:"posted_at"`             // 計上完了日時
+	RolledBackAt   *time.Time           `json:"rolled_back_at,omitempty" db:"rolled_back_at"`   // 一括取消日時
+	RolledBackBy   string               `json:"rolled_back_by,omitempty" db:"rolled_back_by"`   // 一括取消の実行者
+	RollbackReason string               `json:"rollback_reason,omitempty" db:"rollback_reason"` // 一括取消の理由
+}
+
+// addRowError records a row error, keeping at most MaxImportErrors entries
+// 行エラーを記録（保持するのはMaxImportErrors件まで）
+func (l *OpeningBalanceLoad) addRowError(rowError ImportRowError) {
+	l.ErrorCount++
+	l.Errors = appendImportRowError(l.Errors, rowError)
+}
+
+// openingBalanceColumns defines the columns of an opening balance import file
+// 期首在庫の取り込みファイルの列定義（見出しはキーと日本語名のどちらでも可）
+var openingBalanceColumns = []reportColumn{
+	{"item_id", "商品ID"}, {"location_id", "ロケーションID"}, {"quantity", "数量"}, {"lot_number", "ロット番号"},
+	{"expiry_date", "有効期限"}, {"unit_cost", "単価"}, {"currency", "通貨"},
+}
+
+// parseImportDate parses a date cell (date or RFC3339 timestamp)
+// 日付のセルを解析（日付またはRFC3339形式）
+func parseImportDate(value string) (time.Time, error) {
+	if at, err := time.Parse(time.RFC3339, value); err == nil {
+		return at, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// ===== OpeningBalanceManager実装 =====
+
+// ImportOpeningBalances validates an opening balance file against the masters and posts it as inbound transactions
+// 期首在庫の取り込みファイルをマスタに照らして検証し、入庫トランザクションとして計上
+//
+// 全行を検証し、エラーが1件でもある場合やドライランの場合は計上しません（状態rejected / validatedで返します）。
+// 入庫は移行基準日時で計上し、取り込みIDをメタデータに記録します。在庫のある商品・ロケーションや、移行基準日時以降に在庫スナップショットがあるロケーションには取り込めません。
+// 全明細の入庫・ロットと取り込みの登録は1つのデータベーストランザクションで書き込みます。失敗した場合は何も計上せず、状態failedの取り込みとエラーを返します。
+func (m *Manager) ImportOpeningBalances(ctx context.Context, request OpeningBalanceRequest, data []byte) (*OpeningBalanceLoad, error) {
+	if request.AsOf.IsZero() {
+		return nil, NewValidationError("as_of", "移行基準日時は必須です", "")
+	}
+	if request.Reference == "" {
+		request.Reference = OpeningBalanceReferencePrefix + request.AsOf.Format("20060102")
+	}
+	if err := ValidateReference(request.Reference); err != nil {
+		return nil, err
+	}
+	if request.Format == "" {
+		request.Format = ReportFormatCSV
+	}
+
+	// 移行基準日時が未来日付・締め済み期間でないことを確認
+	postingCtx := WithPostingDate(ctx, request.AsOf)
+	if _, err := m.postingTime(postingCtx); err != nil {
+		return nil, err
+	}
+
+	table, err := parseImportFile(openingBalanceColumns, request.Format, data, "item_id", "location_id", "quantity")
+	if err != nil {
+		return nil, err
+	}
+
+	load := &OpeningBalanceLoad{
+		ID:        NewBatchID(),
+		Reference: request.Reference,
+		AsOf:      request.AsOf,
+		Status:    OpeningBalanceStatusValidated,
+		CreatedBy: m.getUserFromContext(ctx),
+		CreatedAt: time.Now(),
+	}
+	if err := m.validateOpeningBalances(ctx, load, table); err != nil {
+		return nil, err
+	}
+	if load.ErrorCount > 0 {
+		load.Status = OpeningBalanceStatusRejected
+		return load, nil
+	}
+	if request.DryRun {
+		return load, nil
+	}
+
+	// 全明細の入庫・ロットと取り込みの登録はまとめて1トランザクションで書き込む
+	changes := newStockChangeSet()
+	var lots []*Lot
+	for i := range load.Lines {
+		line := &load.Lines[i]
+		lot, err := m.stageOpeningBalanceLine(postingCtx, changes, load, line)
+		if err != nil {
+			return m.failOpeningBalanceLoad(ctx, load, fmt.Sprintf("%d行目の計上に失敗しました: %v", line.Row, err), err)
+		}
+		if lot != nil {
+			lots = append(lots, lot)
+		}
+	}
+
+	posted := time.Now()
+	load.Status = OpeningBalanceStatusPosted
+	load.PostedAt = &posted
+	if err := m.storage.ApplyOpeningBalanceLoad(ctx, load, changes.stocks, changes.transactions, lots); err != nil {
+		if err != ErrVersionMismatch {
+			err = NewStorageError("apply_opening_balance_load", "期首在庫の計上に失敗しました", err)
+		}
+		return m.failOpeningBalanceLoad(ctx, load, fmt.Sprintf("期首在庫の計上に失敗しました: %v", err), err)
+	}
+	m.publishStockChanges(ctx, changes, "add", load.Reference)
+
+	m.logger.Info("期首在庫計上完了",
+		zap.String("load_id", load.ID),
+		zap.String("reference", load.Reference),
+		zap.Time("as_of", load.AsOf),
+		zap.Int("lines", load.LineCount),
+	)
+
+	return load, nil
+}
+
+// GetOpeningBalanceLoad gets an opening balance load with its lines
+// 期首在庫の取り込みを明細付きで取得
+func (m *Manager) GetOpeningBalanceLoad(ctx context.Context, loadID string) (*OpeningBalanceLoad, error) {
+	load, err := m.storage.GetOpeningBalanceLoad(ctx, loadID)
+	if err != nil {
+		if err == ErrOpeningBalanceLoadNotFound {
+			return nil, ErrOpeningBalanceLoadNotFound
+		}
+		return nil, NewStorageError("get_opening_balance_load", "期首在庫の取り込み取得に失敗しました", err)
+	}
+	return load, nil
+}
+
+// ListOpeningBalanceLoads lists opening balance loads without their lines, newest first
+// 期首在庫の取り込み一覧を新しい順に取得（明細は含まない）
+func (m *Manager) ListOpeningBalanceLoads(ctx context.Context, offset, limit int) ([]OpeningBalanceLoad, error) {
+	loads, err := m.storage.ListOpeningBalanceLoads(ctx, offset, limit)
+	if err != nil {
+		return nil, NewStorageError("list_opening_balance_loads", "期首在庫の取り込み一覧取得に失敗しました", err)
+	}
+	return loads, nil
+}
+
+// RollbackOpeningBalanceLoad reverses every inbound transaction of an opening balance load as a unit
+// 期首在庫の取り込みで計上した入庫をまとめて取り消す
+//
+// 取消は移行基準日時で計上し、取り込みで作成したロットも削除します。
+// 取り込んだ在庫が出庫・引当済みの場合、移行基準日時の会計期間が締め済みの場合や以降に在庫スナップショットがある場合は、1件も取り消さずにエラーを返します。
+// 全明細の取消・ロット削除と取り込みの更新は1つのデータベーストランザクションで書き込み、失敗した場合は何も取り消しません。
+func (m *Manager) RollbackOpeningBalanceLoad(ctx context.Context, loadID, reason string) (*OpeningBalanceLoad, error) {
+	load, err := m.GetOpeningBalanceLoad(ctx, loadID)
+	if err != nil {
+		return nil, err
+	}
+	if load.Status != OpeningBalanceStatusPosted {
+		return nil, NewBusinessRuleError("opening_balance_not_posted", "計上済みの期首在庫のみ取り消せます",
+			fmt.Sprintf("取り込みID: %s, 状態: %s", load.ID, load.Status))
+	}
+
+	closed, err := m.storage.IsPeriodClosed(ctx, load.AsOf)
+	if err != nil {
+		return nil, NewStorageError("is_period_closed", "会計期間の確認に失敗しました", err)
+	}
+	if closed {
+		return nil, ErrPeriodClosed
+	}
+
+	// 取り込んだ数量がすべて引当可能な在庫として残っていることを確認
+	type stockKey struct{ itemID, locationID string }
+	outstanding := make(map[stockKey]int64)
+	var keys []stockKey
+	checked := make(map[string]bool)
+	for _, line := range load.Lines {
+		if line.TransactionID == nil || line.ReversalID != nil {
+			continue
+		}
+		// 取消も移行基準日時で計上するため、以降に在庫スナップショットがあるロケーションは取り消せない
+		if !checked[line.LocationID] {
+			if err := m.checkSnapshotCutoff(ctx, line.LocationID, load.AsOf); err != nil {
+				return nil, err
+			}
+			checked[line.LocationID] = true
+		}
+		key := stockKey{line.ItemID, line.LocationID}
+		if _, ok := outstanding[key]; !ok {
+			keys = append(keys, key)
+		}
+		outstanding[key] += line.Quantity
+	}
+	var consumed []string
+	for _, key := range keys {
+		available := int64(0)
+		stock, err := m.storage.GetStock(ctx, key.itemID, key.locationID)
+		if err != nil && err != ErrStockNotFound {
+			return nil, NewStorageError("get_stock", "在庫取得に失敗しました", err)
+		}
+		if stock != nil {
+			available = stock.Available
+		}
+		if available < outstanding[key] {
+			item, err := m.storage.GetItem(ctx, key.itemID)
+			if err != nil {
+				return nil, NewStorageError("get_item", "商品取得に失敗しました", err)
+			}
+			consumed = append(consumed, fmt.Sprintf("%s@%s（取込数量: %s, 引当可能数量: %s）", key.itemID, key.locationID,
+				FormatQuantity(outstanding[key], item.QuantityPrecision), FormatQuantity(available, item.QuantityPrecision)))
+		}
+	}
+	if len(consumed) > 0 {
+		return nil, NewBusinessRuleError("opening_balance_consumed", "取り込み後に出庫・引当された在庫があるため期首在庫を取り消せません",
+			fmt.Sprintf("取り込みID: %s, %s", load.ID, strings.Join(consumed, ", ")))
+	}
+
+	if reason == "" {
+		reason = "期首在庫の取り込み取消"
+	}
+	// 取消後の状態は複製に組み立て、書き込みに失敗した場合は取り込みを変更しない
+	rolledBack := *load
+	rolledBack.Lines = append([]OpeningBalanceLine(nil), load.Lines...)
+	changes, lotIDs, err := m.stageOpeningBalanceReversals(WithPostingDate(ctx, load.AsOf), &rolledBack, reason)
+	if err != nil {
+		return nil, m.recordOpeningBalanceRollbackError(ctx, load, err)
+	}
+
+	rolledBackAt := time.Now()
+	rolledBack.Status = OpeningBalanceStatusRolledBack
+	rolledBack.Error = ""
+	rolledBack.RolledBackAt = &rolledBackAt
+	rolledBack.RolledBackBy = m.getUserFromContext(ctx)
+	rolledBack.RollbackReason = reason
+	if err := m.storage.ApplyOpeningBalanceRollback(ctx, &rolledBack, changes.stocks, changes.transactions, lotIDs); err != nil {
+		if err != ErrTransactionAlreadyReversed && err != ErrVersionMismatch {
+			err = NewStorageError("apply_opening_balance_rollback", "期首在庫の一括取消に失敗しました", err)
+		}
+		return nil, m.recordOpeningBalanceRollbackError(ctx, load, err)
+	}
+	m.publishStockChanges(ctx, changes, "remove", "REVERSAL-"+load.Reference)
+	*load = rolledBack
+
+	m.logger.Info("期首在庫の一括取消完了",
+		zap.String("load_id", load.ID),
+		zap.String("reference", load.Reference),
+		zap.Int("lines", load.LineCount),
+		zap.String("reason", reason),
+	)
+
+	return load, nil
+}
+
+// validateOpeningBalances converts and validates every row of an opening balance file, collecting row errors on the load
+// 期首在庫の取り込みファイルの全行を変換・検証し、明細と行エラーを取り込みに設定
+func (m *Manager) validateOpeningBalances(ctx context.Context, load *OpeningBalanceLoad, table *importTable) error {
+	items := make(map[string]*Item)
+	locations := make(map[string]*Location)
+	rates := make(map[string]*ExchangeRate)
+	stocked := make(map[string]bool)
+	seen := make(map[string]int)
+	snapshotted := make(map[string]bool)
+
+	for _, row := range table.rows {
+		itemID, locationID := row.values["item_id"], row.values["location_id"]
+		reject := func(field, message, value string) {
+			load.addRowError(ImportRowError{Row: row.number, ID: itemID, Field: field, Message: message, Value: value})
+		}
+
+		// マスタの確認
+		item, ok := items[itemID]
+		if !ok {
+			var err error
+			item, err = m.storage.GetItem(ctx, itemID)
+			if err != nil && err != ErrItemNotFound {
+				return NewStorageError("get_item", "商品取得に失敗しました", err)
+			}
+			items[itemID] = item
+		}
+		if item == nil {
+			reject("item_id", "商品が見つかりません", itemID)
+			continue
+		}
+		if item.ArchivedAt != nil {
+			reject("item_id", ErrItemArchived.Error(), itemID)
+			continue
+		}
+		location, ok := locations[locationID]
+		if !ok {
+			var err error
+			location, err = m.storage.GetLocation(ctx, locationID)
+			if err != nil && err != ErrLocationNotFound {
+				return NewStorageError("get_location", "ロケーション取得に失敗しました", err)
+			}
+			locations[locationID] = location
+		}
+		if location == nil {
+			reject("location_id", "ロケーションが見つかりません", locationID)
+			continue
+		}
+		if location.ArchivedAt != nil {
+			reject("location_id", ErrLocationArchived.Error(), locationID)
+			continue
+		}
+		if locationID == m.inTransitLocation() {
+			reject("location_id", "輸送中ロケーションには期首在庫を取り込めません", locationID)
+			continue
+		}
+		// 移行基準日時以降に在庫スナップショットがあると時点在庫に反映されない
+		blocked, ok := snapshotted[locationID]
+		if !ok {
+			if err := m.checkSnapshotCutoff(ctx, locationID, load.AsOf); err != nil {
+				if err != ErrPostingBeforeSnapshot {
+					return err
+				}
+				blocked = true
+			}
+			snapshotted[locationID] = blocked
+		}
+		if blocked {
+			reject("location_id", ErrPostingBeforeSnapshot.Error(), locationID)
+			continue
+		}
+
+		line := OpeningBalanceLine{Row: row.number, ItemID: itemID, LocationID: locationID, UnitCost: item.UnitCost, Currency: m.baseCurrency()}
+
+		// 数量・ロット・有効期限・単価の変換
+		valid := true
+		quantity, err := row.quantity("quantity", item.QuantityPrecision)
+		if err == nil && quantity <= 0 {
+			err = NewValidationError("quantity", "数量は正の値である必要があります", row.values["quantity"])
+		}
+		if err != nil {
+			load.addRowError(rowErrorFrom(row.number, itemID, err))
+			valid = false
+		}
+		line.Quantity = quantity
+		if value := row.values["lot_number"]; value != "" {
+			if err := ValidateLotNumber(value); err != nil {
+				load.addRowError(rowErrorFrom(row.number, itemID, err))
+				valid = false
+			}
+			line.LotNumber = &value
+		}
+		if value := row.values["expiry_date"]; value != "" {
+			expiry, err := parseImportDate(value)
+			if err != nil {
+				reject("expiry_date", "有効期限は2006-01-02またはRFC3339形式で指定してください", value)
+				valid = false
+			}
+			line.ExpiryDate = &expiry
+		}
+		if value := row.values["unit_cost"]; value != "" {
+			unitCost, err := ParseMoney(value)
+			if err == nil {
+				err = ValidateUnitCost(unitCost)
+			}
+			if err != nil {
+				reject("unit_cost", "単価が無効です", value)
+				valid = false
+			}
+			line.UnitCost = unitCost
+		}
+		if value := strings.ToUpper(row.values["currency"]); value != "" {
+			line.Currency = value
+		}
+		rate, ok := rates[line.Currency]
+		if !ok {
+			rate, err = m.GetExchangeRate(ctx, line.Currency, load.AsOf)
+			if err != nil {
+				if _, isStorage := err.(*StorageError); isStorage {
+					return err
+				}
+				rate = nil
+			}
+			rates[line.Currency] = rate
+		}
+		if rate == nil {
+			reject("currency", "移行基準日時に有効な為替レートがないか、通貨コードが無効です", line.Currency)
+			valid = false
+		}
+		if !valid {
+			continue
+		}
+		line.Value = line.UnitCost.Convert(rate.Rate).MulQuantity(line.Quantity, item.QuantityPrecision)
+
+		// ファイル内の重複と既存の在庫
+		lotNumber := ""
+		if line.LotNumber != nil {
+			lotNumber = *line.LotNumber
+		}
+		key := itemID + "\x00" + locationID + "\x00" + lotNumber
+		if first, ok := seen[key]; ok {
+			reject("lot_number", fmt.Sprintf("%d行目と商品・ロケーション・ロットが重複しています", first), lotNumber)
+			continue
+		}
+		seen[key] = row.number
+
+		stockKey := itemID + "\x00" + locationID
+		hasStock, ok := stocked[stockKey]
+		if !ok {
+			stock, err := m.storage.GetStock(ctx, itemID, locationID)
+			if err != nil && err != ErrStockNotFound {
+				return NewStorageError("get_stock", "在庫取得に失敗しました", err)
+			}
+			hasStock = stock != nil && (stock.Quantity != 0 || stock.Reserved != 0)
+			stocked[stockKey] = hasStock
+		}
+		if hasStock {
+			reject("location_id", "在庫のある商品・ロケーションには期首在庫を取り込めません", locationID)
+			continue
+		}
+
+		load.Lines = append(load.Lines, line)
+		load.TotalValue += line.Value
+	}
+
+	load.LineCount = len(load.Lines)
+	return nil
+}
+
+// stageOpeningBalanceLine stages the inbound transaction of one opening balance line and returns its lot, if any
+// 期首在庫の明細1件の入庫を在庫変更セットに追加し、ロット番号がある場合は作成するロットを返す
+func (m *Manager) stageOpeningBalanceLine(ctx context.Context, changes *stockChangeSet, load *OpeningBalanceLoad, line *OpeningBalanceLine) (*Lot, error) {
+	unitCost := line.UnitCost
+	details := &inboundDetails{
+		UnitCost:   &unitCost,
+		Currency:   line.Currency,
+		LotNumber:  line.LotNumber,
+		ExpiryDate: line.ExpiryDate,
+		Metadata:   map[string]string{OpeningBalanceMetadataKey: load.ID},
+	}
+	tx, err := m.stageInbound(ctx, changes, line.ItemID, line.LocationID, line.Quantity, load.Reference, details)
+	if err != nil {
+		return nil, err
+	}
+	line.TransactionID = &tx.ID
+
+	if line.LotNumber == nil {
+		return nil, nil
+	}
+	lot := &Lot{
+		ID:         NewTransactionID(),
+		Number:     *line.LotNumber,
+		ItemID:     line.ItemID,
+		Quantity:   line.Quantity,
+		UnitCost:   *tx.UnitCost,
+		ExpiryDate: line.ExpiryDate,
+		CreatedAt:  time.Now(),
+	}
+	line.LotID = &lot.ID
+	return lot, nil
+}
+
+// stageOpeningBalanceReversals stages the reversals of the posted lines of a load that are not reversed yet,
+// returning the lots to delete
+// 取り込みの計上済みで未取消の明細の取消を在庫変更セットに追加し、削除するロットIDを返す
+func (m *Manager) stageOpeningBalanceReversals(ctx context.Context, load *OpeningBalanceLoad, reason string) (*stockChangeSet, []string, error) {
+	reference := "REVERSAL-" + load.Reference
+	changes := newStockChangeSet()
+	var lotIDs []string
+	for i := range load.Lines {
+		line := &load.Lines[i]
+		if line.LotID != nil {
+			lotIDs = append(lotIDs, *line.LotID)
+			line.LotID = nil
+		}
+		if line.TransactionID == nil || line.ReversalID != nil {
+			continue
+		}
+
+		original, err := m.storage.GetTransaction(ctx, *line.TransactionID)
+		if err != nil {
+			return nil, nil, NewStorageError("get_transaction", "トランザクション取得に失敗しました", err)
+		}
+		// 個別に取り消し済みの明細は取消IDのみ記録
+		if original.ReversedBy != nil {
+			line.ReversalID = original.ReversedBy
+			continue
+		}
+
+		postedAt, err := m.postingTime(ctx, line.LocationID)
+		if err != nil {
+			return nil, nil, err
+		}
+		stock, err := m.stagedStock(ctx, changes, line.ItemID, line.LocationID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !m.config.AllowNegativeStock && stock.Available < original.Quantity {
+			return nil, nil, ErrInsufficientStock
+		}
+
+		reversal := &Transaction{
+			ID:           NewTransactionID(),
+			Type:         TransactionTypeOutbound,
+			ItemID:       original.ItemID,
+			FromLocation: original.ToLocation,
+			Quantity:     original.Quantity,
+			UnitCost:     original.UnitCost,
+			Reference:    reference,
+			LotNumber:    original.LotNumber,
+			ExpiryDate:   original.ExpiryDate,
+			Metadata:     map[string]string{"reason": reason},
+			CreatedAt:    postedAt,
+			CreatedBy:    m.getUserFromContext(ctx),
+			ReversalOf:   &original.ID,
+
+			// 外貨建ての入庫は計上時の為替レートのまま取り消す
+			CostCurrency:   original.CostCurrency,
+			SourceUnitCost: original.SourceUnitCost,
+			ExchangeRate:   original.ExchangeRate,
+		}
+		stock.Quantity -= original.Quantity
+		stock.UpdatedAt = time.Now()
+		stock.UpdatedBy = reversal.CreatedBy
+		stock.CalculateAvailable()
+		changes.transactions = append(changes.transactions, reversal)
+		line.ReversalID = &reversal.ID
+	}
+	return changes, lotIDs, nil
+}
+
+// failOpeningBalanceLoad records a load whose posting failed without posting anything and returns it with the error
+// 計上に失敗した取り込みを何も計上していない状態で記録し、エラーとともに返す（記録の失敗はログ出力のみ）
+func (m *Manager) failOpeningBalanceLoad(ctx context.Context, load *OpeningBalanceLoad, message string, err error) (*OpeningBalanceLoad, error) {
+	load.Status = OpeningBalanceStatusFailed
+	load.Error = message
+	load.PostedAt = nil
+	for i := range load.Lines {
+		load.Lines[i].TransactionID = nil
+		load.Lines[i].LotID = nil
+	}
+	if createErr := m.storage.CreateOpeningBalanceLoad(ctx, load); createErr != nil {
+		m.logger.Error("期首在庫の取り込み登録に失敗しました",
+			zap.String("load_id", load.ID),
+			zap.Error(createErr),
+		)
+	}
+	return load, err
+}
+
+// recordOpeningBalanceRollbackError records why a rollback failed on the unchanged load and returns the error
+// 一括取消の失敗理由を変更前の取り込みに記録し、エラーを返す
+func (m *Manager) recordOpeningBalanceRollbackError(ctx context.Context, load *OpeningBalanceLoad, err error) error {
+	load.Error = fmt.Sprintf("一括取消に失敗しました: %v", err)
+	m.saveOpeningBalanceLoad(ctx, load)
+	return err
+}
+
+// saveOpeningBalanceLoad records the state of a load after a failure; failures are only logged
+// 失敗後の取り込みの状態を記録（記録の失敗はログ出力のみ）
+func (m *Manager) saveOpeningBalanceLoad(ctx context.Context, load *OpeningBalanceLoad) {
+	if err := m.storage.UpdateOpeningBalanceLoad(ctx, load); err != nil {
+		m.logger.Error("期首在庫の取り込み更新に失敗しました",
+			zap.String("load_id", load.ID),
+			zap.Error(err),
+		)
+	}
+}
@@ -0,0 +1,146 @@
+package inventory
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MaxQuantityPrecision is the largest number of decimal places an item's quantities may have
+// 商品の数量に設定できる小数桁数の上限
+const MaxQuantityPrecision = 6
+
+// maxQuantityUnits is the largest absolute quantity in whole units accepted by validation
+// バリデーションで許可する数量の絶対値の上限（通貨単位ではなく商品の単位）
+const maxQuantityUnits = 999999999
+
+// QuantityScale returns the number of stored quantity units in one whole unit of an item
+// 商品の単位1あたりの保存上の数量（10の小数桁数乗）を返す
+//
+// 在庫・トランザクション・予約などの数量は、商品の最小単位（小数桁数が3なら0.001）の
+// 整数として保存されます。小数桁数0の商品では保存値と数量が一致します。
+func QuantityScale(precision int) int64 {
+	scale := int64(1)
+	for i := 0; i < precision; i++ {
+		scale *= 10
+	}
+	return scale
+}
+
+// QuantityScale returns the number of stored quantity units in one whole unit of the item
+// 商品の単位1あたりの保存上の数量を返す
+func (i *Item) QuantityScale() int64 {
+	return QuantityScale(i.QuantityPrecision)
+}
+
+// ParseQuantity parses a decimal quantity such as "1.25" into stored units of the given precision
+// "1.25"のような10進数の数量を、指定の小数桁数の保存単位に変換
+//
+// 小数桁数を超える桁がある場合は丸めずにエラーとします。
+func ParseQuantity(s string, precision int) (int64, error) {
+	text := strings.TrimSpace(s)
+	negative := strings.HasPrefix(text, "-")
+	text = strings.TrimPrefix(strings.TrimPrefix(text, "-"), "+")
+
+	whole, fraction, _ := strings.Cut(text, ".")
+	if whole == "" && fraction == "" {
+		return 0, NewValidationError("quantity", "数量が空です", s)
+	}
+	for _, part := range []string{whole, fraction} {
+		for _, r := range part {
+			if r < '0' || r > '9' {
+				return 0, NewValidationError("quantity", "無効な数量です", s)
+			}
+		}
+	}
+
+	// 末尾の0は精度を超えていても許可（"1.500"は小数桁数1でも1.5）
+	fraction = strings.TrimRight(fraction, "0")
+	if len(fraction) > precision {
+		return 0, NewValidationError("quantity", fmt.Sprintf("数量の小数桁数は%d桁までです", precision), s)
+	}
+
+	var units int64
+	if whole != "" {
+		parsed, err := strconv.ParseInt(whole, 10, 64)
+		if err != nil || parsed > maxQuantityUnits {
+			return 0, NewValidationError("quantity", "数量が有効範囲を超えています", s)
+		}
+		units = parsed * QuantityScale(precision)
+	}
+	if fraction != "" {
+		parsed, _ := strconv.ParseInt(fraction, 10, 64)
+		units += parsed * QuantityScale(precision-len(fraction))
+	}
+
+	if negative {
+		units = -units
+	}
+	return units, nil
+}
+
+// FormatQuantity formats stored quantity units as a decimal with the given precision, e.g. 1500 → "1.500"
+// 保存単位の数量を小数桁数に従って10進数表記にする（例: 1500 → "1.500"）
+func FormatQuantity(quantity int64, precision int) string {
+	if precision <= 0 {
+		return strconv.FormatInt(quantity, 10)
+	}
+
+	sign := ""
+	if quantity < 0 {
+		sign = "-"
+		quantity = -quantity
+	}
+	scale := QuantityScale(precision)
+	return fmt.Sprintf("%s%d.%0*d", sign, quantity/scale, precision, quantity%scale)
+}
+
+// DecimalQuantity is a stored quantity paired with its item's precision, rendered as a decimal
+// 保存単位の数量と商品の小数桁数の組（10進数として表示・出力）
+type DecimalQuantity struct {
+	Units     int64 // 保存単位の数量
+	Precision int   // 商品の小数桁数
+}
+
+// String formats the quantity as a decimal
+// 数量を10進数表記で返す
+func (q DecimalQuantity) String() string {
+	return FormatQuantity(q.Units, q.Precision)
+}
+
+// Float64 returns the quantity in whole units for statistics
+// 統計計算用に数量を商品の単位で返す
+func (q DecimalQuantity) Float64() float64 {
+	return float64(q.Units) / float64(QuantityScale(q.Precision))
+}
+
+// MarshalJSON encodes the quantity as an exact JSON number
+// 数量を正確なJSON数値としてエンコード
+func (q DecimalQuantity) MarshalJSON() ([]byte, error) {
+	return []byte(q.String()), nil
+}
+
+// QuantityInput is a decimal quantity received from an API client, kept as text until the item's precision is known
+// APIクライアントから受け取った10進数の数量（商品の小数桁数が分かるまで文字列のまま保持）
+type QuantityInput string
+
+// UnmarshalJSON accepts a JSON number or numeric string without going through float64
+// JSON数値または数値文字列をfloat64を経由せずに受け取る
+func (q *QuantityInput) UnmarshalJSON(data []byte) error {
+	text := strings.TrimSpace(string(data))
+	if text == "null" {
+		*q = ""
+		return nil
+	}
+	*q = QuantityInput(strings.Trim(text, `"`))
+	return nil
+}
+
+// Units converts the quantity to stored units of the given precision (an omitted quantity is 0)
+// 指定の小数桁数の保存単位に変換（省略時は0）
+func (q QuantityInput) Units(precision int) (int64, error) {
+	if q == "" {
+		return 0, nil
+	}
+	return ParseQuantity(string(q), precision)
+}
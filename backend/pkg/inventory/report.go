@@ -0,0 +1,462 @@
+package inventory
+
+import (
+	"archive/zip"
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// reportColumn is one column of a report: a JSON key and a header label
+// レポートの列（JSONのキーとCSV・Excelの見出し）
+type reportColumn struct {
+	key   string
+	label string
+}
+
+// reportColumns defines the columns of each report type
+// レポートタイプごとの列定義
+var reportColumns = map[ReportType][]reportColumn{
+	ReportTypeStock: {
+		{"item_id", "商品ID"}, {"item_name", "商品名"}, {"sku", "SKU"}, {"category", "カテゴリ"},
+		{"location_id", "ロケーションID"}, {"quantity", "在庫数量"}, {"reserved", "予約済み"},
+		{"available", "利用可能"}, {"unit_cost", "単価"}, {"value", "在庫金額"}, {"updated_at", "最終更新"},
+	},
+	ReportTypeMovement: {
+		{"created_at", "日時"}, {"transaction_id", "トランザクションID"}, {"type", "種別"},
+		{"item_id", "商品ID"}, {"item_name", "商品名"}, {"category", "カテゴリ"},
+		{"from_location", "移動元"}, {"to_location", "移動先"}, {"quantity", "数量"},
+		{"unit_cost", "単価"}, {"reference", "参照番号"}, {"lot_number", "ロット番号"}, {"created_by", "実行者"},
+	},
+	ReportTypeValuation: {
+		{"item_id", "商品ID"}, {"item_name", "商品名"}, {"category", "カテゴリ"}, {"location_id", "ロケーションID"},
+		{"opening_quantity", "期首数量"}, {"inbound_quantity", "入庫数量"}, {"outbound_quantity", "出庫数量"},
+		{"adjustment_quantity", "調整数量"}, {"closing_quantity", "期末数量"}, {"unit_cost", "単価"},
+		{"opening_value", "期首金額"}, {"closing_value", "期末金額"},
+	},
+	ReportTypeABC: {
+		{"item_id", "商品ID"}, {"abc_class", "ABC分類"}, {"xyz_class", "XYZ分類"}, {"consumption_value", "出庫金額"},
+		{"consumption_quantity", "出庫数量"}, {"cumulative_percent", "累積構成比"}, {"variation_coefficient", "変動係数"},
+	},
+	ReportTypeTurnover: {
+		{"item_id", "商品ID"}, {"item_name", "商品名"}, {"category", "カテゴリ"}, {"location_id", "ロケーションID"},
+		{"opening_quantity", "期首数量"}, {"closing_quantity", "期末数量"}, {"average_inventory", "平均在庫"},
+		{"outbound_quantity", "出庫数量"}, {"turnover_rate", "回転率"}, {"annualized_turnover", "年換算回転率"},
+		{"days_of_supply", "在庫日数"}, {"stockout_days", "欠品日数"},
+	},
+}
+
+// WriteReport streams a filtered report of the requested type and format to w
+// 指定タイプ・形式のレポートを絞り込み条件に従ってwへストリーム出力
+//
+// 在庫レポートは現在庫、ABCレポートは分析設定の集計期間を対象とし、
+// 入出庫・評価・回転率レポートは Filter.From〜Filter.To の期間を対象とします。
+func (a *AnalyticsEngineImpl) WriteReport(ctx context.Context, w io.Writer, request ReportRequest) error {
+	columns, ok := reportColumns[request.Type]
+	if !ok {
+		return NewValidationError("type", "未対応のレポートタイプです", string(request.Type))
+	}
+	if request.Type != ReportTypeStock && request.Type != ReportTypeABC && !request.Filter.From.Before(request.Filter.To) {
+		return NewValidationError("period", "集計開始日時は終了日時より前である必要があります",
+			fmt.Sprintf("%s - %s", request.Filter.From.Format(time.RFC3339), request.Filter.To.Format(time.RFC3339)))
+	}
+	if request.Type == ReportTypeABC && request.Filter.LocationID == "" {
+		return NewValidationError("location_id", "ABCレポートにはロケーションIDが必要です", "")
+	}
+
+	sink, err := newReportSink(w, request.Format, columns)
+	if err != nil {
+		return err
+	}
+
+	switch request.Type {
+	case ReportTypeStock:
+		err = a.writeStockRows(ctx, sink, request.Filter)
+	case ReportTypeMovement:
+		err = a.writeMovementRows(ctx, sink, request.Filter)
+	case ReportTypeValuation:
+		err = a.writeValuationRows(ctx, sink, request.Filter)
+	case ReportTypeABC:
+		err = a.writeABCRows(ctx, sink, request.Filter)
+	case ReportTypeTurnover:
+		err = a.writeTurnoverRows(ctx, sink, request.Filter)
+	}
+	if err != nil {
+		return err
+	}
+
+	return sink.close()
+}
+
+// writeStockRows writes current stock with its value at the item's unit cost
+// 現在庫と商品単価による在庫金額を出力
+func (a *AnalyticsEngineImpl) writeStockRows(ctx context.Context, sink reportSink, filter ReportFilter) error {
+	err := a.storage.IterateStock(ctx, filter, func(stock Stock, item Item) error {
+		return sink.writeRow([]interface{}{
+			stock.ItemID, item.Name, item.SKU, item.Category, stock.LocationID,
+			reportQuantity(stock.Quantity, item), reportQuantity(stock.Reserved, item), reportQuantity(stock.Available, item),
+			item.UnitCost, item.UnitCost.MulQuantity(stock.Quantity, item.QuantityPrecision), stock.UpdatedAt,
+		})
+	})
+	if err != nil {
+		return NewStorageError("iterate_stock", "レポート用在庫取得に失敗しました", err)
+	}
+	return nil
+}
+
+// writeMovementRows writes each inbound, outbound and adjustment transaction of the period
+// 期間中の入庫・出庫・調整トランザクションを1件ずつ出力
+func (a *AnalyticsEngineImpl) writeMovementRows(ctx context.Context, sink reportSink, filter ReportFilter) error {
+	err := a.storage.IterateTransactions(ctx, filter, func(tx Transaction, item Item) error {
+		return sink.writeRow([]interface{}{
+			tx.CreatedAt, tx.ID, string(tx.Type), tx.ItemID, item.Name, item.Category,
+			tx.FromLocation, tx.ToLocation, reportQuantity(tx.Quantity, item), tx.UnitCost, tx.Reference, tx.LotNumber, tx.CreatedBy,
+		})
+	})
+	if err != nil {
+		return NewStorageError("iterate_transactions", "レポート用トランザクション取得に失敗しました", err)
+	}
+	return nil
+}
+
+// writeValuationRows writes opening and closing quantities and values with the movements in between
+// 期首・期末の数量と金額、期間中の入出庫を出力
+func (a *AnalyticsEngineImpl) writeValuationRows(ctx context.Context, sink reportSink, filter ReportFilter) error {
+	err := a.storage.IterateStockMovementSummaries(ctx, filter, func(summary StockMovementSummary, item Item) error {
+		return sink.writeRow([]interface{}{
+			summary.ItemID, item.Name, item.Category, summary.LocationID,
+			reportQuantity(summary.OpeningQuantity, item), reportQuantity(summary.InboundQuantity, item),
+			reportQuantity(summary.OutboundQuantity, item), reportQuantity(summary.AdjustmentQuantity, item),
+			reportQuantity(summary.ClosingQuantity, item), item.UnitCost,
+			item.UnitCost.MulQuantity(summary.OpeningQuantity, item.QuantityPrecision),
+			item.UnitCost.MulQuantity(summary.ClosingQuantity, item.QuantityPrecision),
+		})
+	})
+	if err != nil {
+		return NewStorageError("iterate_stock_movement_summaries", "入出庫集計の取得に失敗しました", err)
+	}
+	return nil
+}
+
+// writeTurnoverRows writes ledger-based turnover KPIs of each item and location with activity in the period
+// 期間中に在庫のあった商品・ロケーションごとに台帳に基づく回転率KPIを出力
+func (a *AnalyticsEngineImpl) writeTurnoverRows(ctx context.Context, sink reportSink, filter ReportFilter) error {
+	var kpiErr error
+	err := a.storage.IterateStockMovementSummaries(ctx, filter, func(summary StockMovementSummary, item Item) error {
+		kpi, err := a.calculateItemKPI(ctx, summary.ItemID, summary.LocationID, filter.From, filter.To)
+		if err != nil {
+			kpiErr = err
+			return err
+		}
+		return sink.writeRow([]interface{}{
+			summary.ItemID, item.Name, item.Category, summary.LocationID,
+			reportQuantity(kpi.OpeningQuantity, item), reportQuantity(kpi.ClosingQuantity, item),
+			kpi.AverageInventory / float64(item.QuantityScale()), reportQuantity(kpi.OutboundQuantity, item),
+			kpi.TurnoverRate, kpi.AnnualizedTurnover, kpi.DaysOfSupply, kpi.StockoutDays,
+		})
+	})
+	if kpiErr != nil {
+		return kpiErr
+	}
+	if err != nil {
+		return NewStorageError("iterate_stock_movement_summaries", "入出庫集計の取得に失敗しました", err)
+	}
+	return nil
+}
+
+// writeABCRows writes the ABC/XYZ classification of a location, filtered by category
+// ロケーションのABC/XYZ分類を出力（カテゴリ指定時は絞り込み）
+func (a *AnalyticsEngineImpl) writeABCRows(ctx context.Context, sink reportSink, filter ReportFilter) error {
+	classifications, err := a.classifyItems(ctx, filter.LocationID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, c := range classifications {
+		item, err := a.storage.GetItem(ctx, c.ItemID)
+		if err != nil {
+			return NewStorageError("get_item", "商品取得に失敗しました", err)
+		}
+		if filter.Category != "" && item.Category != filter.Category {
+			continue
+		}
+		if err := sink.writeRow([]interface{}{
+			c.ItemID, c.ABCClass, c.XYZClass, c.ConsumptionValue, reportQuantity(c.ConsumptionQuantity, *item),
+			c.CumulativePercent, c.VariationCoefficient,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reportQuantity renders a stored quantity as a decimal with the item's precision
+// 保存単位の数量を商品の小数桁数に従った10進数として出力
+func reportQuantity(quantity int64, item Item) DecimalQuantity {
+	return DecimalQuantity{Units: quantity, Precision: item.QuantityPrecision}
+}
+
+// reportSink receives report rows one at a time and encodes them in an output format
+// レポートの行を1行ずつ受け取り出力形式に変換する
+type reportSink interface {
+	writeRow(values []interface{}) error
+	close() error
+}
+
+// newReportSink creates the sink of a format and writes its header
+// 出力形式に応じた出力先を作成し、見出しを書き込む
+func newReportSink(w io.Writer, format ReportFormat, columns []reportColumn) (reportSink, error) {
+	switch format {
+	case ReportFormatCSV, "":
+		return newCSVReportSink(w, columns)
+	case ReportFormatJSON:
+		return newJSONReportSink(w, columns)
+	case ReportFormatXLSX:
+		return newXLSXReportSink(w, columns)
+	default:
+		return nil, NewValidationError("format", "未対応の出力形式です", string(format))
+	}
+}
+
+// reportText formats a cell value as text; nil pointers become empty strings
+// セルの値を文字列に変換（nilのポインタは空文字）
+func reportText(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case *string:
+		if v == nil {
+			return ""
+		}
+		return *v
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case *float64:
+		if v == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*v, 'f', -1, 64)
+	case Money:
+		return v.String()
+	case DecimalQuantity:
+		return v.String()
+	case *Money:
+		if v == nil {
+			return ""
+		}
+		return v.String()
+	case time.Time:
+		return v.Format("2006-01-02 15:04:05")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// csvReportSink writes BOM-prefixed UTF-8 CSV so that Excel opens Japanese text correctly
+// Excelで日本語が文字化けしないようBOM付きUTF-8のCSVを出力
+type csvReportSink struct {
+	buffer *bufio.Writer
+	writer *csv.Writer
+	record []string
+}
+
+func newCSVReportSink(w io.Writer, columns []reportColumn) (*csvReportSink, error) {
+	buffer := bufio.NewWriter(w)
+	if _, err := buffer.Write([]byte{0xEF, 0xBB, 0xBF}); err != nil {
+		return nil, err
+	}
+
+	sink := &csvReportSink{buffer: buffer, writer: csv.NewWriter(buffer), record: make([]string, len(columns))}
+	for i, column := range columns {
+		sink.record[i] = column.label
+	}
+	if err := sink.writer.Write(sink.record); err != nil {
+		return nil, err
+	}
+	return sink, nil
+}
+
+func (s *csvReportSink) writeRow(values []interface{}) error {
+	for i, value := range values {
+		s.record[i] = reportText(value)
+	}
+	return s.writer.Write(s.record)
+}
+
+func (s *csvReportSink) close() error {
+	s.writer.Flush()
+	if err := s.writer.Error(); err != nil {
+		return err
+	}
+	return s.buffer.Flush()
+}
+
+// jsonReportSink writes a JSON array of row objects whose keys follow the column order
+// 列順のキーを持つ行オブジェクトのJSON配列を出力
+type jsonReportSink struct {
+	buffer  *bufio.Writer
+	keys    [][]byte
+	written bool
+}
+
+func newJSONReportSink(w io.Writer, columns []reportColumn) (*jsonReportSink, error) {
+	sink := &jsonReportSink{buffer: bufio.NewWriter(w), keys: make([][]byte, len(columns))}
+	for i, column := range columns {
+		key, err := json.Marshal(column.key)
+		if err != nil {
+			return nil, err
+		}
+		sink.keys[i] = key
+	}
+	if _, err := sink.buffer.WriteString("["); err != nil {
+		return nil, err
+	}
+	return sink, nil
+}
+
+func (s *jsonReportSink) writeRow(values []interface{}) error {
+	if s.written {
+		s.buffer.WriteString(",")
+	}
+	s.written = true
+
+	s.buffer.WriteString("\n  {")
+	for i, value := range values {
+		if i > 0 {
+			s.buffer.WriteString(",")
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		s.buffer.Write(s.keys[i])
+		s.buffer.WriteString(":")
+		s.buffer.Write(encoded)
+	}
+	_, err := s.buffer.WriteString("}")
+	return err
+}
+
+func (s *jsonReportSink) close() error {
+	if s.written {
+		s.buffer.WriteString("\n")
+	}
+	if _, err := s.buffer.WriteString("]\n"); err != nil {
+		return err
+	}
+	return s.buffer.Flush()
+}
+
+// xlsxReportSink writes a single-sheet Office Open XML workbook, streaming rows into the sheet part
+// 1シートのExcelブック（Office Open XML）を出力し、行はシートに逐次書き込む
+type xlsxReportSink struct {
+	archive *zip.Writer
+	sheet   *bufio.Writer
+	row     int
+}
+
+// xlsxStaticParts are the package parts that do not depend on the report rows
+// レポートの行に依存しないパッケージ構成部品
+var xlsxStaticParts = []struct {
+	name    string
+	content string
+}{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+	{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="report" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`},
+}
+
+func newXLSXReportSink(w io.Writer, columns []reportColumn) (*xlsxReportSink, error) {
+	archive := zip.NewWriter(w)
+	for _, part := range xlsxStaticParts {
+		file, err := archive.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(file, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	// シートは最後の部品として作成し、行を逐次書き込む
+	file, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	sink := &xlsxReportSink{archive: archive, sheet: bufio.NewWriter(file)}
+	sink.sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	header := make([]interface{}, len(columns))
+	for i, column := range columns {
+		header[i] = column.label
+	}
+	if err := sink.writeRow(header); err != nil {
+		return nil, err
+	}
+	return sink, nil
+}
+
+func (s *xlsxReportSink) writeRow(values []interface{}) error {
+	s.row++
+	fmt.Fprintf(s.sheet, `<row r="%d">`, s.row)
+	for i, value := range values {
+		ref := xlsxColumnName(i) + strconv.Itoa(s.row)
+		switch v := value.(type) {
+		case int, int64, float64, Money, DecimalQuantity:
+			fmt.Fprintf(s.sheet, `<c r="%s"><v>%s</v></c>`, ref, reportText(v))
+		case *float64:
+			if v != nil {
+				fmt.Fprintf(s.sheet, `<c r="%s"><v>%s</v></c>`, ref, reportText(v))
+			}
+		case *Money:
+			if v != nil {
+				fmt.Fprintf(s.sheet, `<c r="%s"><v>%s</v></c>`, ref, reportText(v))
+			}
+		default:
+			fmt.Fprintf(s.sheet, `<c r="%s" t="inlineStr"><is><t>`, ref)
+			if err := xml.EscapeText(s.sheet, []byte(reportText(v))); err != nil {
+				return err
+			}
+			s.sheet.WriteString(`</t></is></c>`)
+		}
+	}
+	_, err := s.sheet.WriteString(`</row>`)
+	return err
+}
+
+func (s *xlsxReportSink) close() error {
+	if _, err := s.sheet.WriteString(`</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	if err := s.sheet.Flush(); err != nil {
+		return err
+	}
+	return s.archive.Close()
+}
+
+// xlsxColumnName converts a zero-based column index to a spreadsheet column name (0 → A, 26 → AA)
+// 0始まりの列番号を列名に変換（0 → A、26 → AA）
+func xlsxColumnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
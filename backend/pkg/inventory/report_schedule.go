@@ -0,0 +1,410 @@
+package inventory
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ReportSchedulerImpl implements the ReportScheduler interface
+// ReportSchedulerインターフェースの実装
+//
+// 生成したレポートはアーカイブディレクトリ配下に年/月ごとに保存し、
+// ファイル名・期間・チェックサムなどのメタデータはストレージに記録します。
+type ReportSchedulerImpl struct {
+	storage    Storage
+	reports    ReportWriter
+	logger     *zap.Logger
+	archiveDir string
+}
+
+// NewReportScheduler creates a new report scheduler that stores generated files under archiveDir
+// 生成したレポートをarchiveDir配下に保存する新しいレポートスケジューラーを作成
+func NewReportScheduler(storage Storage, reports ReportWriter, archiveDir string, logger *zap.Logger) *ReportSchedulerImpl {
+	return &ReportSchedulerImpl{
+		storage:    storage,
+		reports:    reports,
+		logger:     logger,
+		archiveDir: archiveDir,
+	}
+}
+
+// CreateReportSchedule validates a schedule and registers it with its first run time
+// スケジュールを検証し、初回実行予定日時とともに登録
+func (s *ReportSchedulerImpl) CreateReportSchedule(ctx context.Context, schedule *ReportSchedule) error {
+	now := time.Now()
+	if err := s.prepareSchedule(schedule, now); err != nil {
+		return err
+	}
+
+	schedule.ID = NewTransactionID()
+	schedule.LastRunAt = nil
+	schedule.LastError = ""
+	schedule.CreatedAt = now
+	schedule.CreatedBy = s.getUserFromContext(ctx)
+	schedule.UpdatedAt = now
+
+	if err := s.storage.CreateReportSchedule(ctx, schedule); err != nil {
+		return NewStorageError("create_report_schedule", "レポートスケジュール作成に失敗しました", err)
+	}
+
+	s.logger.Info("レポートスケジュール作成",
+		zap.String("schedule_id", schedule.ID),
+		zap.String("cron_expression", schedule.CronExpression),
+		zap.String("report_type", string(schedule.ReportType)),
+	)
+
+	return nil
+}
+
+// GetReportSchedule retrieves a report schedule by ID
+// IDでレポートスケジュールを取得
+func (s *ReportSchedulerImpl) GetReportSchedule(ctx context.Context, scheduleID string) (*ReportSchedule, error) {
+	schedule, err := s.storage.GetReportSchedule(ctx, scheduleID)
+	if err != nil {
+		if err == ErrReportScheduleNotFound {
+			return nil, ErrReportScheduleNotFound
+		}
+		return nil, NewStorageError("get_report_schedule", "レポートスケジュール取得に失敗しました", err)
+	}
+	return schedule, nil
+}
+
+// ListReportSchedules lists all report schedules
+// レポートスケジュール一覧を取得
+func (s *ReportSchedulerImpl) ListReportSchedules(ctx context.Context) ([]ReportSchedule, error) {
+	schedules, err := s.storage.ListReportSchedules(ctx)
+	if err != nil {
+		return nil, NewStorageError("list_report_schedules", "レポートスケジュール一覧取得に失敗しました", err)
+	}
+	return schedules, nil
+}
+
+// UpdateReportSchedule replaces the settings of a schedule and recalculates its next run time
+// スケジュールの設定を更新し、次回実行予定日時を再計算
+//
+// 実行状況（前回実行日時・エラー）と作成者は更新前の値を引き継ぎます。
+func (s *ReportSchedulerImpl) UpdateReportSchedule(ctx context.Context, schedule *ReportSchedule) error {
+	existing, err := s.GetReportSchedule(ctx, schedule.ID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if err := s.prepareSchedule(schedule, now); err != nil {
+		return err
+	}
+
+	schedule.LastRunAt = existing.LastRunAt
+	schedule.LastError = existing.LastError
+	schedule.CreatedAt = existing.CreatedAt
+	schedule.CreatedBy = existing.CreatedBy
+	schedule.UpdatedAt = now
+
+	if err := s.storage.UpdateReportSchedule(ctx, schedule); err != nil {
+		if err == ErrReportScheduleNotFound {
+			return ErrReportScheduleNotFound
+		}
+		return NewStorageError("update_report_schedule", "レポートスケジュール更新に失敗しました", err)
+	}
+
+	return nil
+}
+
+// DeleteReportSchedule deletes a report schedule; reports already archived are kept
+// レポートスケジュールを削除（アーカイブ済みレポートは残す）
+func (s *ReportSchedulerImpl) DeleteReportSchedule(ctx context.Context, scheduleID string) error {
+	if err := s.storage.DeleteReportSchedule(ctx, scheduleID); err != nil {
+		if err == ErrReportScheduleNotFound {
+			return ErrReportScheduleNotFound
+		}
+		return NewStorageError("delete_report_schedule", "レポートスケジュール削除に失敗しました", err)
+	}
+	return nil
+}
+
+// RunReportSchedule generates the report of a schedule immediately without changing its next run time
+// スケジュールのレポートを即時生成（次回実行予定日時は変更しない）
+func (s *ReportSchedulerImpl) RunReportSchedule(ctx context.Context, scheduleID string) (*ReportArchive, error) {
+	schedule, err := s.GetReportSchedule(ctx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	return s.generate(ctx, schedule, time.Now(), s.getUserFromContext(ctx))
+}
+
+// RunDueReportSchedules generates the reports of all active schedules whose run time has come
+// 実行予定日時を迎えた有効なスケジュールのレポートを生成
+//
+// 停止中に過ぎた実行予定はまとめて1回だけ実行し、次回実行予定日時はnow以降に進めます。
+// 複数のプロセスで実行しても、実行予定日時を先に進めたプロセスだけがレポートを生成します。
+// 戻り値は生成したレポートの件数です。
+func (s *ReportSchedulerImpl) RunDueReportSchedules(ctx context.Context, now time.Time) (int, error) {
+	schedules, err := s.storage.ListDueReportSchedules(ctx, now)
+	if err != nil {
+		return 0, NewStorageError("list_due_report_schedules", "実行対象のレポートスケジュール取得に失敗しました", err)
+	}
+
+	generated := 0
+	for i := range schedules {
+		schedule := &schedules[i]
+		if schedule.NextRunAt == nil {
+			continue
+		}
+		scheduledAt := *schedule.NextRunAt
+
+		nextRunAt, err := nextReportRun(schedule, now)
+		if err != nil {
+			s.logger.Error("レポートスケジュールの次回実行日時を計算できません",
+				zap.String("schedule_id", schedule.ID), zap.Error(err))
+			continue
+		}
+
+		claimed, err := s.storage.ClaimReportScheduleRun(ctx, schedule.ID, scheduledAt, nextRunAt)
+		if err != nil {
+			s.logger.Error("レポートスケジュールの実行開始に失敗しました",
+				zap.String("schedule_id", schedule.ID), zap.Error(err))
+			continue
+		}
+		if !claimed {
+			// 他のプロセスが実行済み
+			continue
+		}
+
+		schedule.NextRunAt = &nextRunAt
+		if _, err := s.generate(ctx, schedule, scheduledAt, "scheduler"); err != nil {
+			// 失敗した場合は前回実行日時を進めず、次回に同じ期間から集計する
+			schedule.LastError = err.Error()
+			s.logger.Error("定期レポート生成に失敗しました",
+				zap.String("schedule_id", schedule.ID), zap.Error(err))
+		} else {
+			schedule.LastRunAt = &scheduledAt
+			schedule.LastError = ""
+			generated++
+		}
+
+		if err := s.storage.UpdateReportSchedule(ctx, schedule); err != nil {
+			s.logger.Error("レポートスケジュールの実行結果の記録に失敗しました",
+				zap.String("schedule_id", schedule.ID), zap.Error(err))
+		}
+	}
+
+	return generated, nil
+}
+
+// ListReportArchives lists archived reports, optionally filtered by schedule and report type
+// アーカイブ済みレポート一覧を取得（スケジュール・レポートタイプで絞り込み可能）
+func (s *ReportSchedulerImpl) ListReportArchives(ctx context.Context, scheduleID string, reportType ReportType, offset, limit int) ([]ReportArchive, error) {
+	archives, err := s.storage.ListReportArchives(ctx, scheduleID, reportType, offset, limit)
+	if err != nil {
+		return nil, NewStorageError("list_report_archives", "アーカイブ済みレポート一覧取得に失敗しました", err)
+	}
+	return archives, nil
+}
+
+// GetReportArchive retrieves the metadata of an archived report
+// アーカイブ済みレポートのメタデータを取得
+func (s *ReportSchedulerImpl) GetReportArchive(ctx context.Context, archiveID string) (*ReportArchive, error) {
+	archive, err := s.storage.GetReportArchive(ctx, archiveID)
+	if err != nil {
+		if err == ErrReportArchiveNotFound {
+			return nil, ErrReportArchiveNotFound
+		}
+		return nil, NewStorageError("get_report_archive", "アーカイブ済みレポート取得に失敗しました", err)
+	}
+	return archive, nil
+}
+
+// OpenReportArchive opens the file of an archived report; the caller must close it
+// アーカイブ済みレポートのファイルを開く（呼び出し側で閉じる必要があります）
+func (s *ReportSchedulerImpl) OpenReportArchive(ctx context.Context, archiveID string) (*ReportArchive, io.ReadCloser, error) {
+	archive, err := s.GetReportArchive(ctx, archiveID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, err := os.Open(filepath.Join(s.archiveDir, filepath.Clean(archive.FilePath)))
+	if err != nil {
+		if os.IsNotExist(err) {
+			s.logger.Warn("アーカイブファイルが存在しません",
+				zap.String("archive_id", archive.ID), zap.String("file_path", archive.FilePath))
+			return nil, nil, ErrReportArchiveNotFound
+		}
+		return nil, nil, NewStorageError("open_report_archive", "アーカイブファイルを開けませんでした", err)
+	}
+
+	return archive, file, nil
+}
+
+// prepareSchedule validates a schedule, fills in defaults and sets its next run time after now
+// スケジュールを検証して既定値を補い、now以降の次回実行予定日時を設定
+func (s *ReportSchedulerImpl) prepareSchedule(schedule *ReportSchedule, now time.Time) error {
+	if schedule.Name == "" {
+		return NewValidationError("name", "スケジュール名は必須です", schedule.Name)
+	}
+	if _, ok := reportColumns[schedule.ReportType]; !ok {
+		return NewValidationError("report_type", "未対応のレポートタイプです", string(schedule.ReportType))
+	}
+	if schedule.Format == "" {
+		schedule.Format = ReportFormatCSV
+	}
+	if schedule.Format != ReportFormatCSV && schedule.Format != ReportFormatJSON && schedule.Format != ReportFormatXLSX {
+		return NewValidationError("format", "未対応の出力形式です", string(schedule.Format))
+	}
+	if schedule.ReportType == ReportTypeABC && schedule.LocationID == "" {
+		return NewValidationError("location_id", "ABCレポートにはロケーションIDが必要です", "")
+	}
+	if schedule.PeriodDays < 0 {
+		return NewValidationError("period_days", "集計日数は0以上である必要があります", fmt.Sprintf("%d", schedule.PeriodDays))
+	}
+
+	nextRunAt, err := nextReportRun(schedule, now)
+	if err != nil {
+		return err
+	}
+	schedule.NextRunAt = &nextRunAt
+
+	return nil
+}
+
+// nextReportRun returns the first run time of a schedule after the given time, in UTC
+// スケジュールの指定日時より後の最初の実行日時をUTCで返す
+func nextReportRun(schedule *ReportSchedule, after time.Time) (time.Time, error) {
+	cron, err := ParseCronSchedule(schedule.CronExpression)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	loc := time.Local
+	if schedule.Timezone != "" {
+		if loc, err = time.LoadLocation(schedule.Timezone); err != nil {
+			return time.Time{}, NewValidationError("timezone", "タイムゾーンが不正です", schedule.Timezone)
+		}
+	}
+
+	next := cron.Next(after.In(loc))
+	if next.IsZero() {
+		return time.Time{}, NewValidationError("cron_expression", "実行日時が存在しないcron式です", schedule.CronExpression)
+	}
+
+	return next.UTC(), nil
+}
+
+// generate writes the report of a schedule for the period ending at runAt and archives it
+// runAtを集計終了日時としてスケジュールのレポートを生成しアーカイブに保存
+//
+// 集計日数が0の場合は前回正常に生成した時点（初回はスケジュール作成日時）以降を集計します。
+func (s *ReportSchedulerImpl) generate(ctx context.Context, schedule *ReportSchedule, runAt time.Time, generatedBy string) (*ReportArchive, error) {
+	from := runAt.AddDate(0, 0, -schedule.PeriodDays)
+	if schedule.PeriodDays == 0 {
+		from = schedule.CreatedAt
+		if schedule.LastRunAt != nil {
+			from = *schedule.LastRunAt
+		}
+	}
+	if schedule.ReportType == ReportTypeStock || schedule.ReportType == ReportTypeABC {
+		// 在庫・ABCレポートは生成時点の状態を出力する
+		from = runAt
+	}
+
+	request := ReportRequest{
+		Type:   schedule.ReportType,
+		Format: schedule.Format,
+		Filter: ReportFilter{
+			LocationID: schedule.LocationID,
+			Category:   schedule.Category,
+			From:       from,
+			To:         runAt,
+		},
+	}
+
+	return s.archive(ctx, request, schedule.ID, generatedBy)
+}
+
+// archive writes a report to a temporary file, moves it into the archive directory and records its metadata
+// レポートを一時ファイルに書き出し、アーカイブディレクトリへ移動してメタデータを記録
+func (s *ReportSchedulerImpl) archive(ctx context.Context, request ReportRequest, scheduleID, generatedBy string) (*ReportArchive, error) {
+	generatedAt := time.Now()
+	archive := &ReportArchive{
+		ID:          NewTransactionID(),
+		ScheduleID:  scheduleID,
+		ReportType:  request.Type,
+		Format:      request.Format,
+		LocationID:  request.Filter.LocationID,
+		Category:    request.Filter.Category,
+		PeriodFrom:  request.Filter.From,
+		PeriodTo:    request.Filter.To,
+		GeneratedAt: generatedAt,
+		GeneratedBy: generatedBy,
+	}
+
+	location := request.Filter.LocationID
+	if location == "" {
+		location = "all"
+	}
+	archive.FileName = string(request.Type) + "_" + location + "_" + request.Filter.To.Format("20060102-1504") + "." + string(request.Format)
+	archive.FilePath = filepath.Join(generatedAt.Format("2006"), generatedAt.Format("01"), archive.ID+"."+string(request.Format))
+
+	fullPath := filepath.Join(s.archiveDir, archive.FilePath)
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0o750); err != nil {
+		return nil, NewStorageError("create_report_archive", "アーカイブディレクトリ作成に失敗しました", err)
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(fullPath), archive.ID+"-*.tmp")
+	if err != nil {
+		return nil, NewStorageError("create_report_archive", "アーカイブファイル作成に失敗しました", err)
+	}
+
+	hash := sha256.New()
+	writeErr := s.reports.WriteReport(ctx, io.MultiWriter(file, hash), request)
+	closeErr := file.Close()
+	if writeErr == nil && closeErr != nil {
+		writeErr = NewStorageError("create_report_archive", "アーカイブファイル書き込みに失敗しました", closeErr)
+	}
+	if writeErr != nil {
+		os.Remove(file.Name())
+		return nil, writeErr
+	}
+
+	if err := os.Rename(file.Name(), fullPath); err != nil {
+		os.Remove(file.Name())
+		return nil, NewStorageError("create_report_archive", "アーカイブファイル保存に失敗しました", err)
+	}
+
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		return nil, NewStorageError("create_report_archive", "アーカイブファイル確認に失敗しました", err)
+	}
+	archive.SizeBytes = info.Size()
+	archive.Checksum = hex.EncodeToString(hash.Sum(nil))
+
+	if err := s.storage.CreateReportArchive(ctx, archive); err != nil {
+		os.Remove(fullPath)
+		return nil, NewStorageError("create_report_archive", "アーカイブ済みレポート登録に失敗しました", err)
+	}
+
+	s.logger.Info("レポートをアーカイブしました",
+		zap.String("archive_id", archive.ID),
+		zap.String("schedule_id", scheduleID),
+		zap.String("report_type", string(request.Type)),
+		zap.Int64("size_bytes", archive.SizeBytes),
+	)
+
+	return archive, nil
+}
+
+// getUserFromContext extracts user ID from context
+// コンテキストからユーザーIDを取得
+func (s *ReportSchedulerImpl) getUserFromContext(ctx context.Context) string {
+	if userID, ok := ctx.Value("user_id").(string); ok {
+		return userID
+	}
+	return "system"
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/lib/pq"
@@ -188,6 +189,43 @@ func (s *PostgreSQLStorage) ListStockByLocation(ctx context.Context, locationID
 	return stocks, nil
 }
 
+// ListStockByItem retrieves the stock of an item at every location
+// 商品の全ロケーションの在庫を取得
+func (s *PostgreSQLStorage) ListStockByItem(ctx context.Context, itemID string) ([]inventory.Stock, error) {
+	query := `
+		SELECT item_id, location_id, quantity, reserved, available, version, updated_at, updated_by
+		FROM stocks 
+		WHERE item_id = $1
+		ORDER BY location_id`
+
+	rows, err := s.db.QueryContext(ctx, query, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("商品在庫取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	var stocks []inventory.Stock
+	for rows.Next() {
+		var stock inventory.Stock
+		err := rows.Scan(
+			&stock.ItemID,
+			&stock.LocationID,
+			&stock.Quantity,
+			&stock.Reserved,
+			&stock.Available,
+			&stock.Version,
+			&stock.UpdatedAt,
+			&stock.UpdatedBy,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("在庫スキャンに失敗しました: %w", err)
+		}
+		stocks = append(stocks, stock)
+	}
+
+	return stocks, nil
+}
+
 // GetTotalStockByItem retrieves total stock quantity for an item across all locations
 // 商品の全ロケーションでの合計在庫数を取得
 func (s *PostgreSQLStorage) GetTotalStockByItem(ctx context.Context, itemID string) (int64, error) {
@@ -205,16 +243,29 @@ func (s *PostgreSQLStorage) GetTotalStockByItem(ctx context.Context, itemID stri
 // CreateTransaction creates a new transaction record
 // 新しいトランザクション記録を作成
 func (s *PostgreSQLStorage) CreateTransaction(ctx context.Context, tx *inventory.Transaction) error {
+	return insertTransaction(ctx, s.db, tx)
+}
+
+// execer is satisfied by both *sql.DB and *sql.Tx
+// *sql.DB と *sql.Tx の共通インターフェース
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// insertTransaction inserts a transaction record using the given connection or transaction
+// 指定された接続またはトランザクションでトランザクション記録を挿入
+func insertTransaction(ctx context.Context, db execer, tx *inventory.Transaction) error {
 	metadataJSON, err := json.Marshal(tx.Metadata)
 	if err != nil {
 		return fmt.Errorf("メタデータのJSON変換に失敗しました: %w", err)
 	}
 
 	query := `
-		INSERT INTO transactions (id, type, item_id, from_location, to_location, quantity, unit_cost, reference, lot_number, expiry_date, metadata, created_at, created_by)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
+		INSERT INTO transactions (id, type, item_id, from_location, to_location, quantity, unit_cost, reference, lot_number, expiry_date, metadata, created_at, created_by, reversal_of,
+			cost_currency, source_unit_cost, exchange_rate)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)`
 
-	_, err = s.db.ExecContext(ctx, query,
+	_, err = db.ExecContext(ctx, query,
 		tx.ID,
 		tx.Type,
 		tx.ItemID,
@@ -228,6 +279,10 @@ func (s *PostgreSQLStorage) CreateTransaction(ctx context.Context, tx *inventory
 		metadataJSON,
 		tx.CreatedAt,
 		tx.CreatedBy,
+		tx.ReversalOf,
+		tx.CostCurrency,
+		tx.SourceUnitCost,
+		tx.ExchangeRate,
 	)
 
 	if err != nil {
@@ -237,11 +292,127 @@ func (s *PostgreSQLStorage) CreateTransaction(ctx context.Context, tx *inventory
 	return nil
 }
 
+// ApplyStockChanges writes several stock records and their transactions in one database transaction
+// 複数の在庫記録とトランザクションを1つのデータベーストランザクションで書き込み
+func (s *PostgreSQLStorage) ApplyStockChanges(ctx context.Context, stocks []*inventory.Stock, transactions []*inventory.Transaction) error {
+	dbTx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("トランザクション開始に失敗しました: %w", err)
+	}
+	defer dbTx.Rollback()
+
+	if err := writeStockChanges(ctx, dbTx, stocks, transactions); err != nil {
+		return err
+	}
+
+	if err := dbTx.Commit(); err != nil {
+		return fmt.Errorf("トランザクションコミットに失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// ApplyReversal writes the stock records and transactions of a reversal and links the original
+// transaction to it in one database transaction
+// 取消の在庫記録・トランザクションの書き込みと元トランザクションへの取消参照の記録を1つのデータベーストランザクションで実行
+func (s *PostgreSQLStorage) ApplyReversal(ctx context.Context, transactionID, reversalID string, stocks []*inventory.Stock, transactions []*inventory.Transaction) error {
+	dbTx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("トランザクション開始に失敗しました: %w", err)
+	}
+	defer dbTx.Rollback()
+
+	if err := writeStockChanges(ctx, dbTx, stocks, transactions); err != nil {
+		return err
+	}
+
+	// 取消済みでない場合のみ記録（同時に取り消された場合は全体をロールバック）
+	if err := markTransactionReversed(ctx, dbTx, transactionID, reversalID); err != nil {
+		return err
+	}
+
+	if err := dbTx.Commit(); err != nil {
+		return fmt.Errorf("トランザクションコミットに失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// markTransactionReversed links a transaction to its reversal within dbTx unless it is already reversed
+// データベーストランザクション内で元トランザクションに取消参照を記録（取消済みの場合はErrTransactionAlreadyReversed）
+func markTransactionReversed(ctx context.Context, dbTx *sql.Tx, transactionID, reversalID string) error {
+	query := `
+		UPDATE transactions
+		SET reversed_by = $2
+		WHERE id = $1 AND reversed_by IS NULL`
+
+	result, err := dbTx.ExecContext(ctx, query, transactionID, reversalID)
+	if err != nil {
+		return fmt.Errorf("取消参照の記録に失敗しました: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("更新行数の取得に失敗しました: %w", err)
+	}
+	if rowsAffected == 0 {
+		return inventory.ErrTransactionAlreadyReversed
+	}
+
+	return nil
+}
+
+// writeStockChanges writes stock records with a version check and inserts transactions within dbTx
+// データベーストランザクション内で在庫記録を楽観的ロック付きで書き込み、トランザクションを記録
+func writeStockChanges(ctx context.Context, dbTx *sql.Tx, stocks []*inventory.Stock, transactions []*inventory.Transaction) error {
+	// 新規（version=1）は作成、既存は楽観的ロック付きで更新
+	query := `
+		INSERT INTO stocks (item_id, location_id, quantity, reserved, available, version, updated_at, updated_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (item_id, location_id) DO UPDATE
+		SET quantity = EXCLUDED.quantity, reserved = EXCLUDED.reserved, available = EXCLUDED.available,
+			version = EXCLUDED.version, updated_at = EXCLUDED.updated_at, updated_by = EXCLUDED.updated_by
+		WHERE stocks.version = EXCLUDED.version - 1`
+
+	for _, stock := range stocks {
+		result, err := dbTx.ExecContext(ctx, query,
+			stock.ItemID,
+			stock.LocationID,
+			stock.Quantity,
+			stock.Reserved,
+			stock.Available,
+			stock.Version,
+			stock.UpdatedAt,
+			stock.UpdatedBy,
+		)
+		if err != nil {
+			return fmt.Errorf("在庫記録更新に失敗しました: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("更新行数の取得に失敗しました: %w", err)
+		}
+		if rowsAffected == 0 {
+			return inventory.ErrVersionMismatch
+		}
+	}
+
+	for _, tx := range transactions {
+		if err := insertTransaction(ctx, dbTx, tx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // GetTransactionHistory retrieves transaction history for an item
 // 商品のトランザクション履歴を取得
 func (s *PostgreSQLStorage) GetTransactionHistory(ctx context.Context, itemID string, limit int) ([]inventory.Transaction, error) {
 	query := `
-		SELECT id, type, item_id, from_location, to_location, quantity, unit_cost, reference, lot_number, expiry_date, metadata, created_at, created_by
+		SELECT id, type, item_id, from_location, to_location, quantity, unit_cost, reference, lot_number, expiry_date, metadata, created_at, created_by, reversal_of, reversed_by,
+			cost_currency, source_unit_cost, exchange_rate
 		FROM transactions 
 		WHERE item_id = $1
 		ORDER BY created_at DESC
@@ -272,6 +443,11 @@ func (s *PostgreSQLStorage) GetTransactionHistory(ctx context.Context, itemID st
 			&metadataJSON,
 			&tx.CreatedAt,
 			&tx.CreatedBy,
+			&tx.ReversalOf,
+			&tx.ReversedBy,
+			&tx.CostCurrency,
+			&tx.SourceUnitCost,
+			&tx.ExchangeRate,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("トランザクションスキャンに失敗しました: %w", err)
@@ -294,7 +470,8 @@ func (s *PostgreSQLStorage) GetTransactionHistory(ctx context.Context, itemID st
 // ロケーションのトランザクション履歴を取得
 func (s *PostgreSQLStorage) GetTransactionHistoryByLocation(ctx context.Context, locationID string, limit int) ([]inventory.Transaction, error) {
 	query := `
-		SELECT id, type, item_id, from_location, to_location, quantity, unit_cost, reference, lot_number, expiry_date, metadata, created_at, created_by
+		SELECT id, type, item_id, from_location, to_location, quantity, unit_cost, reference, lot_number, expiry_date, metadata, created_at, created_by, reversal_of, reversed_by,
+			cost_currency, source_unit_cost, exchange_rate
 		FROM transactions 
 		WHERE from_location = $1 OR to_location = $1
 		ORDER BY created_at DESC
@@ -325,6 +502,11 @@ func (s *PostgreSQLStorage) GetTransactionHistoryByLocation(ctx context.Context,
 			&metadataJSON,
 			&tx.CreatedAt,
 			&tx.CreatedBy,
+			&tx.ReversalOf,
+			&tx.ReversedBy,
+			&tx.CostCurrency,
+			&tx.SourceUnitCost,
+			&tx.ExchangeRate,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("トランザクションスキャンに失敗しました: %w", err)
@@ -347,7 +529,8 @@ func (s *PostgreSQLStorage) GetTransactionHistoryByLocation(ctx context.Context,
 // 商品の指定日付範囲のトランザクション履歴を取得
 func (s *PostgreSQLStorage) GetTransactionHistoryByDateRange(ctx context.Context, itemID string, from, to time.Time) ([]inventory.Transaction, error) {
 	query := `
-		SELECT id, type, item_id, from_location, to_location, quantity, unit_cost, reference, lot_number, expiry_date, metadata, created_at, created_by
+		SELECT id, type, item_id, from_location, to_location, quantity, unit_cost, reference, lot_number, expiry_date, metadata, created_at, created_by, reversal_of, reversed_by,
+			cost_currency, source_unit_cost, exchange_rate
 		FROM transactions 
 		WHERE item_id = $1 AND created_at >= $2 AND created_at <= $3
 		ORDER BY created_at DESC`
@@ -377,6 +560,11 @@ func (s *PostgreSQLStorage) GetTransactionHistoryByDateRange(ctx context.Context
 			&metadataJSON,
 			&tx.CreatedAt,
 			&tx.CreatedBy,
+			&tx.ReversalOf,
+			&tx.ReversedBy,
+			&tx.CostCurrency,
+			&tx.SourceUnitCost,
+			&tx.ExchangeRate,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("トランザクションスキャンに失敗しました: %w", err)
@@ -395,201 +583,3095 @@ func (s *PostgreSQLStorage) GetTransactionHistoryByDateRange(ctx context.Context
 	return transactions, nil
 }
 
-// CreateItem creates a new item
-// 新しい商品を作成
-func (s *PostgreSQLStorage) CreateItem(ctx context.Context, item *inventory.Item) error {
+// ListOutboundTransactions retrieves non-reversed outbound transactions from a location within a date range, excluding reversals and transfer legs
+// 指定ロケーションから指定期間に出庫された（取消されていない）トランザクションを取得（取消・移動レッグは除外）
+func (s *PostgreSQLStorage) ListOutboundTransactions(ctx context.Context, locationID string, from, to time.Time) ([]inventory.Transaction, error) {
 	query := `
-		INSERT INTO items (id, name, sku, description, category, unit_cost, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+		SELECT id, type, item_id, from_location, to_location, quantity, unit_cost, reference, lot_number, expiry_date, metadata, created_at, created_by, reversal_of, reversed_by,
+			cost_currency, source_unit_cost, exchange_rate
+		FROM transactions
+		WHERE type = 'outbound' AND from_location = $1 AND created_at >= $2 AND created_at < $3
+			AND reversed_by IS NULL AND reversal_of IS NULL AND NOT COALESCE(metadata ? $4, FALSE)
+		ORDER BY created_at ASC`
+
+	rows, err := s.db.QueryContext(ctx, query, locationID, from, to, inventory.TransferMetadataKey)
+	if err != nil {
+		return nil, fmt.Errorf("出庫トランザクション取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
 
-	_, err := s.db.ExecContext(ctx, query,
-		item.ID,
-		item.Name,
-		item.SKU,
-		item.Description,
-		item.Category,
-		item.UnitCost,
-		item.CreatedAt,
-		item.UpdatedAt,
-	)
+	var transactions []inventory.Transaction
+	for rows.Next() {
+		var tx inventory.Transaction
+		var metadataJSON []byte
 
-	if err != nil {
-		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
-			return inventory.ErrDuplicateItem
+		err := rows.Scan(
+			&tx.ID,
+			&tx.Type,
+			&tx.ItemID,
+			&tx.FromLocation,
+			&tx.ToLocation,
+			&tx.Quantity,
+			&tx.UnitCost,
+			&tx.Reference,
+			&tx.LotNumber,
+			&tx.ExpiryDate,
+			&metadataJSON,
+			&tx.CreatedAt,
+			&tx.CreatedBy,
+			&tx.ReversalOf,
+			&tx.ReversedBy,
+			&tx.CostCurrency,
+			&tx.SourceUnitCost,
+			&tx.ExchangeRate,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("トランザクションスキャンに失敗しました: %w", err)
 		}
-		return fmt.Errorf("商品作成に失敗しました: %w", err)
+
+		// メタデータのデシリアライズ
+		if len(metadataJSON) > 0 {
+			if err := json.Unmarshal(metadataJSON, &tx.Metadata); err != nil {
+				s.logger.Warn("メタデータのパースに失敗しました", zap.Error(err))
+			}
+		}
+
+		transactions = append(transactions, tx)
 	}
 
-	return nil
+	return transactions, nil
 }
 
-// GetItem retrieves an item by ID
-// IDで商品を取得
-func (s *PostgreSQLStorage) GetItem(ctx context.Context, itemID string) (*inventory.Item, error) {
+// GetTransaction retrieves a transaction by ID
+// IDでトランザクションを取得
+func (s *PostgreSQLStorage) GetTransaction(ctx context.Context, transactionID string) (*inventory.Transaction, error) {
 	query := `
-		SELECT id, name, sku, description, category, unit_cost, created_at, updated_at
-		FROM items 
+		SELECT id, type, item_id, from_location, to_location, quantity, unit_cost, reference, lot_number, expiry_date, metadata, created_at, created_by, reversal_of, reversed_by,
+			cost_currency, source_unit_cost, exchange_rate
+		FROM transactions
 		WHERE id = $1`
 
-	item := &inventory.Item{}
-	err := s.db.QueryRowContext(ctx, query, itemID).Scan(
-		&item.ID,
-		&item.Name,
-		&item.SKU,
-		&item.Description,
-		&item.Category,
-		&item.UnitCost,
-		&item.CreatedAt,
-		&item.UpdatedAt,
+	var tx inventory.Transaction
+	var metadataJSON []byte
+
+	err := s.db.QueryRowContext(ctx, query, transactionID).Scan(
+		&tx.ID,
+		&tx.Type,
+		&tx.ItemID,
+		&tx.FromLocation,
+		&tx.ToLocation,
+		&tx.Quantity,
+		&tx.UnitCost,
+		&tx.Reference,
+		&tx.LotNumber,
+		&tx.ExpiryDate,
+		&metadataJSON,
+		&tx.CreatedAt,
+		&tx.CreatedBy,
+		&tx.ReversalOf,
+		&tx.ReversedBy,
+		&tx.CostCurrency,
+		&tx.SourceUnitCost,
+		&tx.ExchangeRate,
 	)
-
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, inventory.ErrItemNotFound
+			return nil, inventory.ErrTransactionNotFound
 		}
-		return nil, fmt.Errorf("商品取得に失敗しました: %w", err)
+		return nil, fmt.Errorf("トランザクション取得に失敗しました: %w", err)
 	}
 
-	return item, nil
+	// メタデータのデシリアライズ
+	if len(metadataJSON) > 0 {
+		if err := json.Unmarshal(metadataJSON, &tx.Metadata); err != nil {
+			s.logger.Warn("メタデータのパースに失敗しました", zap.Error(err))
+		}
+	}
+
+	return &tx, nil
 }
 
-// UpdateItem updates an existing item
-// 既存の商品を更新
-func (s *PostgreSQLStorage) UpdateItem(ctx context.Context, item *inventory.Item) error {
+// IsPeriodClosed reports whether the given time falls within a closed fiscal period
+// 指定日時が締め済みの会計期間に含まれるかを判定
+func (s *PostgreSQLStorage) IsPeriodClosed(ctx context.Context, at time.Time) (bool, error) {
 	query := `
-		UPDATE items 
-		SET name = $2, sku = $3, description = $4, category = $5, unit_cost = $6, updated_at = $7
-		WHERE id = $1`
+		SELECT EXISTS (
+			SELECT 1 FROM fiscal_periods
+			WHERE status = 'closed' AND $1::date BETWEEN start_date AND end_date
+		)`
+
+	var closed bool
+	if err := s.db.QueryRowContext(ctx, query, at).Scan(&closed); err != nil {
+		return false, fmt.Errorf("会計期間の確認に失敗しました: %w", err)
+	}
 
-	result, err := s.db.ExecContext(ctx, query,
-		item.ID,
-		item.Name,
-		item.SKU,
-		item.Description,
-		item.Category,
-		item.UnitCost,
-		item.UpdatedAt,
-	)
+	return closed, nil
+}
 
+// fiscalPeriodColumns is the column list shared by fiscal period queries
+// 会計期間クエリで共通の列リスト
+const fiscalPeriodColumns = `id, name, start_date, end_date, status, closed_at, COALESCE(closed_by, ''), created_at, created_by`
+
+// scanFiscalPeriod scans a fiscal period row
+// 会計期間の行をスキャン
+func scanFiscalPeriod(row interface{ Scan(dest ...any) error }) (*inventory.FiscalPeriod, error) {
+	var period inventory.FiscalPeriod
+	err := row.Scan(
+		&period.ID,
+		&period.Name,
+		&period.StartDate,
+		&period.EndDate,
+		&period.Status,
+		&period.ClosedAt,
+		&period.ClosedBy,
+		&period.CreatedAt,
+		&period.CreatedBy,
+	)
 	if err != nil {
-		return fmt.Errorf("商品更新に失敗しました: %w", err)
+		return nil, err
 	}
+	return &period, nil
+}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("更新行数の取得に失敗しました: %w", err)
-	}
+// CreateFiscalPeriod creates a new fiscal period
+// 新しい会計期間を作成
+func (s *PostgreSQLStorage) CreateFiscalPeriod(ctx context.Context, period *inventory.FiscalPeriod) error {
+	query := `
+		INSERT INTO fiscal_periods (id, name, start_date, end_date, status, created_at, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
 
-	if rowsAffected == 0 {
-		return inventory.ErrItemNotFound
+	_, err := s.db.ExecContext(ctx, query,
+		period.ID,
+		period.Name,
+		period.StartDate,
+		period.EndDate,
+		period.Status,
+		period.CreatedAt,
+		period.CreatedBy,
+	)
+	if err != nil {
+		return fmt.Errorf("会計期間作成に失敗しました: %w", err)
 	}
 
 	return nil
 }
 
-// DeleteItem deletes an item by ID
-// IDで商品を削除
-func (s *PostgreSQLStorage) DeleteItem(ctx context.Context, itemID string) error {
-	query := `DELETE FROM items WHERE id = $1`
-
-	result, err := s.db.ExecContext(ctx, query, itemID)
-	if err != nil {
-		return fmt.Errorf("商品削除に失敗しました: %w", err)
-	}
+// GetFiscalPeriod retrieves a fiscal period by ID
+// IDで会計期間を取得
+func (s *PostgreSQLStorage) GetFiscalPeriod(ctx context.Context, periodID string) (*inventory.FiscalPeriod, error) {
+	query := `SELECT ` + fiscalPeriodColumns + ` FROM fiscal_periods WHERE id = $1`
 
-	rowsAffected, err := result.RowsAffected()
+	period, err := scanFiscalPeriod(s.db.QueryRowContext(ctx, query, periodID))
 	if err != nil {
-		return fmt.Errorf("削除行数の取得に失敗しました: %w", err)
-	}
-
-	if rowsAffected == 0 {
-		return inventory.ErrItemNotFound
+		if err == sql.ErrNoRows {
+			return nil, inventory.ErrFiscalPeriodNotFound
+		}
+		return nil, fmt.Errorf("会計期間取得に失敗しました: %w", err)
 	}
 
-	return nil
+	return period, nil
 }
 
-// ListItems retrieves items with pagination
-// ページネーション付きで商品一覧を取得
-func (s *PostgreSQLStorage) ListItems(ctx context.Context, offset, limit int) ([]inventory.Item, error) {
-	query := `
-		SELECT id, name, sku, description, category, unit_cost, created_at, updated_at
-		FROM items 
-		ORDER BY created_at DESC
-		OFFSET $1 LIMIT $2`
+// ListFiscalPeriods retrieves all fiscal periods in start date order
+// 全ての会計期間を開始日順に取得
+func (s *PostgreSQLStorage) ListFiscalPeriods(ctx context.Context) ([]inventory.FiscalPeriod, error) {
+	query := `SELECT ` + fiscalPeriodColumns + ` FROM fiscal_periods ORDER BY start_date`
 
-	rows, err := s.db.QueryContext(ctx, query, offset, limit)
+	rows, err := s.db.QueryContext(ctx, query)
 	if err != nil {
-		return nil, fmt.Errorf("商品一覧取得に失敗しました: %w", err)
+		return nil, fmt.Errorf("会計期間一覧取得に失敗しました: %w", err)
 	}
 	defer rows.Close()
 
-	var items []inventory.Item
+	var periods []inventory.FiscalPeriod
 	for rows.Next() {
-		var item inventory.Item
-		err := rows.Scan(
-			&item.ID,
-			&item.Name,
-			&item.SKU,
-			&item.Description,
-			&item.Category,
-			&item.UnitCost,
-			&item.CreatedAt,
-			&item.UpdatedAt,
-		)
+		period, err := scanFiscalPeriod(rows)
 		if err != nil {
-			return nil, fmt.Errorf("商品スキャンに失敗しました: %w", err)
+			return nil, fmt.Errorf("会計期間スキャンに失敗しました: %w", err)
 		}
-		items = append(items, item)
+		periods = append(periods, *period)
 	}
 
-	return items, nil
+	return periods, rows.Err()
 }
 
-// SearchItems searches for items by query string
-// クエリ文字列で商品を検索
-func (s *PostgreSQLStorage) SearchItems(ctx context.Context, query string) ([]inventory.Item, error) {
-	sqlQuery := `
-		SELECT id, name, sku, description, category, unit_cost, created_at, updated_at
-		FROM items 
-		WHERE name ILIKE $1 OR sku ILIKE $1 OR description ILIKE $1 OR category ILIKE $1
-		ORDER BY name`
+// CloseFiscalPeriod closes an open period and stores its period-end balances and the audit record atomically
+// 未締めの会計期間を締め、期末残高と監査記録を一括で保存
+func (s *PostgreSQLStorage) CloseFiscalPeriod(ctx context.Context, period *inventory.FiscalPeriod, event *inventory.FiscalPeriodEvent) (int64, error) {
+	dbTx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("トランザクション開始に失敗しました: %w", err)
+	}
+	defer dbTx.Rollback()
+
+	// 締めと同時に期間内への計上をロックする（未締めの場合のみ更新）
+	result, err := dbTx.ExecContext(ctx, `
+		UPDATE fiscal_periods
+		SET status = 'closed', closed_at = $2, closed_by = $3
+		WHERE id = $1 AND status = 'open'`,
+		period.ID, period.ClosedAt, period.ClosedBy)
+	if err != nil {
+		return 0, fmt.Errorf("会計期間の締めに失敗しました: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("更新行数の取得に失敗しました: %w", err)
+	}
+	if rowsAffected == 0 {
+		return 0, inventory.ErrInvalidFiscalPeriodStatus
+	}
 
-	searchPattern := "%" + query + "%"
-	rows, err := s.db.QueryContext(ctx, sqlQuery, searchPattern)
+	// 期末（終了日の翌日0時より前）までの台帳から数量を集計し、締め時点の商品単価で評価
+	if _, err := dbTx.ExecContext(ctx, `DELETE FROM period_end_balances WHERE period_id = $1`, period.ID); err != nil {
+		return 0, fmt.Errorf("期末残高の削除に失敗しました: %w", err)
+	}
+	result, err = dbTx.ExecContext(ctx, `
+		INSERT INTO period_end_balances (period_id, item_id, location_id, quantity, unit_cost, value)
+		SELECT $1, b.item_id, b.location_id, b.quantity, i.unit_cost,
+			ROUND(b.quantity * i.unit_cost / power(10::numeric, i.quantity_precision), 4)
+		FROM (
+			SELECT item_id, location_id, SUM(delta) AS quantity
+			FROM ledger_entries
+			WHERE created_at < $2
+			GROUP BY item_id, location_id
+		) b
+		JOIN items i ON i.id = b.item_id
+		WHERE b.quantity <> 0`,
+		period.ID, period.PeriodEnd())
 	if err != nil {
-		return nil, fmt.Errorf("商品検索に失敗しました: %w", err)
+		return 0, fmt.Errorf("期末残高の保存に失敗しました: %w", err)
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("保存件数の取得に失敗しました: %w", err)
 	}
-	defer rows.Close()
 
-	var items []inventory.Item
-	for rows.Next() {
-		var item inventory.Item
-		err := rows.Scan(
-			&item.ID,
-			&item.Name,
-			&item.SKU,
-			&item.Description,
-			&item.Category,
-			&item.UnitCost,
-			&item.CreatedAt,
-			&item.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("商品スキャンに失敗しました: %w", err)
-		}
-		items = append(items, item)
+	event.BalanceCount = count
+	if err := insertFiscalPeriodEvent(ctx, dbTx, event); err != nil {
+		return 0, err
+	}
+
+	if err := dbTx.Commit(); err != nil {
+		return 0, fmt.Errorf("トランザクションのコミットに失敗しました: %w", err)
 	}
 
-	return items, nil
+	return count, nil
+}
+
+// ReopenFiscalPeriod reopens a closed period, discarding its period-end balances, and records the audit entry
+// 締め済みの会計期間を未締めに戻し、期末残高を削除して締め解除の監査記録を保存
+func (s *PostgreSQLStorage) ReopenFiscalPeriod(ctx context.Context, periodID string, event *inventory.FiscalPeriodEvent) error {
+	dbTx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("トランザクション開始に失敗しました: %w", err)
+	}
+	defer dbTx.Rollback()
+
+	result, err := dbTx.ExecContext(ctx, `
+		UPDATE fiscal_periods
+		SET status = 'open', closed_at = NULL, closed_by = NULL
+		WHERE id = $1 AND status = 'closed'`,
+		periodID)
+	if err != nil {
+		return fmt.Errorf("会計期間の締め解除に失敗しました: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("更新行数の取得に失敗しました: %w", err)
+	}
+	if rowsAffected == 0 {
+		return inventory.ErrInvalidFiscalPeriodStatus
+	}
+
+	if _, err := dbTx.ExecContext(ctx, `DELETE FROM period_end_balances WHERE period_id = $1`, periodID); err != nil {
+		return fmt.Errorf("期末残高の削除に失敗しました: %w", err)
+	}
+	if err := insertFiscalPeriodEvent(ctx, dbTx, event); err != nil {
+		return err
+	}
+
+	if err := dbTx.Commit(); err != nil {
+		return fmt.Errorf("トランザクションのコミットに失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// insertFiscalPeriodEvent stores a close or reopen audit record within a database transaction
+// 会計期間の締め・締め解除の監査記録をトランザクション内で保存
+func insertFiscalPeriodEvent(ctx context.Context, dbTx *sql.Tx, event *inventory.FiscalPeriodEvent) error {
+	_, err := dbTx.ExecContext(ctx, `
+		INSERT INTO fiscal_period_events (id, period_id, action, reason, balance_count, performed_at, performed_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		event.ID,
+		event.PeriodID,
+		event.Action,
+		event.Reason,
+		event.BalanceCount,
+		event.PerformedAt,
+		event.PerformedBy,
+	)
+	if err != nil {
+		return fmt.Errorf("会計期間の監査記録の保存に失敗しました: %w", err)
+	}
+	return nil
+}
+
+// ListPeriodEndBalances retrieves the period-end balances of a period, optionally filtered by location
+// 会計期間の期末残高を取得（ロケーションで絞り込み可能）
+func (s *PostgreSQLStorage) ListPeriodEndBalances(ctx context.Context, periodID, locationID string, offset, limit int) ([]inventory.PeriodEndBalance, error) {
+	query := `
+		SELECT period_id, item_id, location_id, quantity, unit_cost, value
+		FROM period_end_balances
+		WHERE period_id = $1 AND ($2 = '' OR location_id = $2)
+		ORDER BY location_id, item_id
+		LIMIT $3 OFFSET $4`
+
+	rows, err := s.db.QueryContext(ctx, query, periodID, locationID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("期末残高取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	var balances []inventory.PeriodEndBalance
+	for rows.Next() {
+		var balance inventory.PeriodEndBalance
+		if err := rows.Scan(
+			&balance.PeriodID,
+			&balance.ItemID,
+			&balance.LocationID,
+			&balance.Quantity,
+			&balance.UnitCost,
+			&balance.Value,
+		); err != nil {
+			return nil, fmt.Errorf("期末残高スキャンに失敗しました: %w", err)
+		}
+		balances = append(balances, balance)
+	}
+
+	return balances, rows.Err()
+}
+
+// ListFiscalPeriodEvents retrieves the close and reopen audit records of a period in chronological order
+// 会計期間の締め・締め解除の監査記録を古い順に取得
+func (s *PostgreSQLStorage) ListFiscalPeriodEvents(ctx context.Context, periodID string) ([]inventory.FiscalPeriodEvent, error) {
+	query := `
+		SELECT id, period_id, action, reason, balance_count, performed_at, performed_by
+		FROM fiscal_period_events
+		WHERE period_id = $1
+		ORDER BY performed_at`
+
+	rows, err := s.db.QueryContext(ctx, query, periodID)
+	if err != nil {
+		return nil, fmt.Errorf("会計期間の監査記録取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	var events []inventory.FiscalPeriodEvent
+	for rows.Next() {
+		var event inventory.FiscalPeriodEvent
+		if err := rows.Scan(
+			&event.ID,
+			&event.PeriodID,
+			&event.Action,
+			&event.Reason,
+			&event.BalanceCount,
+			&event.PerformedAt,
+			&event.PerformedBy,
+		); err != nil {
+			return nil, fmt.Errorf("会計期間の監査記録スキャンに失敗しました: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// SaveExchangeRate stores an exchange rate, replacing the rate with the same currencies and effective date
+// 為替レートを保存（同じ通貨・基準通貨・適用開始日のレートは上書き）
+func (s *PostgreSQLStorage) SaveExchangeRate(ctx context.Context, rate *inventory.ExchangeRate) error {
+	query := `
+		INSERT INTO exchange_rates (id, currency, base_currency, rate, effective_date, created_at, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (currency, base_currency, effective_date) DO UPDATE
+		SET rate = EXCLUDED.rate, created_at = EXCLUDED.created_at, created_by = EXCLUDED.created_by
+		RETURNING id`
+
+	err := s.db.QueryRowContext(ctx, query,
+		rate.ID,
+		rate.Currency,
+		rate.BaseCurrency,
+		rate.Rate,
+		rate.EffectiveDate,
+		rate.CreatedAt,
+		rate.CreatedBy,
+	).Scan(&rate.ID)
+	if err != nil {
+		return fmt.Errorf("為替レート保存に失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// GetExchangeRate retrieves the latest rate whose effective date is on or before the given time
+// 指定日時以前に適用開始された最新の為替レートを取得
+func (s *PostgreSQLStorage) GetExchangeRate(ctx context.Context, currency, baseCurrency string, at time.Time) (*inventory.ExchangeRate, error) {
+	query := `
+		SELECT id, currency, base_currency, rate, effective_date, created_at, created_by
+		FROM exchange_rates
+		WHERE currency = $1 AND base_currency = $2 AND effective_date <= $3
+		ORDER BY effective_date DESC
+		LIMIT 1`
+
+	var rate inventory.ExchangeRate
+	err := s.db.QueryRowContext(ctx, query, currency, baseCurrency, at).Scan(
+		&rate.ID,
+		&rate.Currency,
+		&rate.BaseCurrency,
+		&rate.Rate,
+		&rate.EffectiveDate,
+		&rate.CreatedAt,
+		&rate.CreatedBy,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, inventory.ErrExchangeRateNotFound
+		}
+		return nil, fmt.Errorf("為替レート取得に失敗しました: %w", err)
+	}
+
+	return &rate, nil
+}
+
+// ListExchangeRates retrieves rates into the base currency, newest effective date first
+// 基準通貨への為替レートを通貨・適用開始日の新しい順に取得
+func (s *PostgreSQLStorage) ListExchangeRates(ctx context.Context, currency, baseCurrency string) ([]inventory.ExchangeRate, error) {
+	query := `
+		SELECT id, currency, base_currency, rate, effective_date, created_at, created_by
+		FROM exchange_rates
+		WHERE ($1 = '' OR currency = $1) AND base_currency = $2
+		ORDER BY currency, effective_date DESC`
+
+	rows, err := s.db.QueryContext(ctx, query, currency, baseCurrency)
+	if err != nil {
+		return nil, fmt.Errorf("為替レート一覧取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	var rates []inventory.ExchangeRate
+	for rows.Next() {
+		var rate inventory.ExchangeRate
+		if err := rows.Scan(
+			&rate.ID,
+			&rate.Currency,
+			&rate.BaseCurrency,
+			&rate.Rate,
+			&rate.EffectiveDate,
+			&rate.CreatedAt,
+			&rate.CreatedBy,
+		); err != nil {
+			return nil, fmt.Errorf("為替レートスキャンに失敗しました: %w", err)
+		}
+		rates = append(rates, rate)
+	}
+
+	return rates, rows.Err()
+}
+
+// CreateStockSnapshots stores ledger-based stock snapshots for all current stock records
+// 現在の全在庫記録について台帳ベースの在庫スナップショットを保存
+func (s *PostgreSQLStorage) CreateStockSnapshots(ctx context.Context, at time.Time) (int64, error) {
+	// 直前のスナップショットにそれ以降の台帳差分を加算して新しいスナップショットを作成
+	query := `
+		WITH prev AS (
+			SELECT DISTINCT ON (item_id, location_id) item_id, location_id, quantity, snapshot_at
+			FROM stock_snapshots
+			WHERE snapshot_at <= $1
+			ORDER BY item_id, location_id, snapshot_at DESC
+		),
+		deltas AS (
+			SELECT le.item_id, le.location_id, SUM(le.delta) AS delta
+			FROM ledger_entries le
+			LEFT JOIN prev p ON p.item_id = le.item_id AND p.location_id = le.location_id
+			WHERE le.created_at <= $1 AND (p.snapshot_at IS NULL OR le.created_at > p.snapshot_at)
+			GROUP BY le.item_id, le.location_id
+		)
+		INSERT INTO stock_snapshots (item_id, location_id, quantity, snapshot_at)
+		SELECT st.item_id, st.location_id, COALESCE(p.quantity, 0) + COALESCE(d.delta, 0), $1
+		FROM stocks st
+		LEFT JOIN prev p ON p.item_id = st.item_id AND p.location_id = st.location_id
+		LEFT JOIN deltas d ON d.item_id = st.item_id AND d.location_id = st.location_id
+		ON CONFLICT (item_id, location_id, snapshot_at) DO NOTHING`
+
+	result, err := s.db.ExecContext(ctx, query, at)
+	if err != nil {
+		return 0, fmt.Errorf("在庫スナップショット作成に失敗しました: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("作成行数の取得に失敗しました: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// GetStockAsOf reconstructs the stock quantity of an item at a location at the given time
+// 指定時点における商品・ロケーションの在庫数量を再構築
+func (s *PostgreSQLStorage) GetStockAsOf(ctx context.Context, itemID, locationID string, at time.Time) (int64, error) {
+	query := `
+		WITH snap AS (
+			SELECT quantity, snapshot_at
+			FROM stock_snapshots
+			WHERE item_id = $1 AND location_id = $2 AND snapshot_at <= $3
+			ORDER BY snapshot_at DESC
+			LIMIT 1
+		)
+		SELECT COALESCE((SELECT quantity FROM snap), 0) + COALESCE(SUM(delta), 0)
+		FROM ledger_entries
+		WHERE item_id = $1 AND location_id = $2 AND created_at <= $3
+			AND created_at > COALESCE((SELECT snapshot_at FROM snap), '-infinity'::timestamp)`
+
+	var quantity int64
+	if err := s.db.QueryRowContext(ctx, query, itemID, locationID, at).Scan(&quantity); err != nil {
+		return 0, fmt.Errorf("時点在庫取得に失敗しました: %w", err)
+	}
+
+	return quantity, nil
+}
+
+// ListStockAsOfByLocation reconstructs stock quantities of all items at a location at the given time
+// 指定時点におけるロケーション内の全商品の在庫数量を再構築
+func (s *PostgreSQLStorage) ListStockAsOfByLocation(ctx context.Context, locationID string, at time.Time) ([]inventory.StockSnapshot, error) {
+	query := `
+		WITH snap_time AS (
+			SELECT MAX(snapshot_at) AS at
+			FROM stock_snapshots
+			WHERE location_id = $1 AND snapshot_at <= $2
+		),
+		snap AS (
+			SELECT ss.item_id, ss.quantity
+			FROM stock_snapshots ss, snap_time st
+			WHERE ss.location_id = $1 AND ss.snapshot_at = st.at
+		),
+		deltas AS (
+			SELECT le.item_id, SUM(le.delta) AS delta
+			FROM ledger_entries le, snap_time st
+			WHERE le.location_id = $1 AND le.created_at <= $2 AND (st.at IS NULL OR le.created_at > st.at)
+			GROUP BY le.item_id
+		)
+		SELECT item_id, quantity FROM (
+			SELECT COALESCE(snap.item_id, deltas.item_id) AS item_id,
+				COALESCE(snap.quantity, 0) + COALESCE(deltas.delta, 0) AS quantity
+			FROM snap
+			FULL OUTER JOIN deltas ON deltas.item_id = snap.item_id
+		) balances
+		WHERE quantity <> 0
+		ORDER BY item_id`
+
+	rows, err := s.db.QueryContext(ctx, query, locationID, at)
+	if err != nil {
+		return nil, fmt.Errorf("ロケーション時点在庫取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	var snapshots []inventory.StockSnapshot
+	for rows.Next() {
+		snapshot := inventory.StockSnapshot{
+			LocationID: locationID,
+			AsOf:       at,
+		}
+		if err := rows.Scan(&snapshot.ItemID, &snapshot.Quantity); err != nil {
+			return nil, fmt.Errorf("時点在庫スキャンに失敗しました: %w", err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return snapshots, nil
+}
+
+// GetLatestStockSnapshotAt returns the time of the latest stock snapshot at a location, or nil if none exists
+// ロケーションの最新の在庫スナップショット日時を取得（スナップショットがない場合はnil）
+func (s *PostgreSQLStorage) GetLatestStockSnapshotAt(ctx context.Context, locationID string) (*time.Time, error) {
+	query := `SELECT MAX(snapshot_at) FROM stock_snapshots WHERE location_id = $1`
+
+	var latest *time.Time
+	if err := s.db.QueryRowContext(ctx, query, locationID).Scan(&latest); err != nil {
+		return nil, fmt.Errorf("最新スナップショット日時取得に失敗しました: %w", err)
+	}
+
+	return latest, nil
+}
+
+// ListLedgerEntries retrieves the ledger movements of an item at a location within a date range
+// 指定された商品・ロケーションの期間内の台帳明細を取得（古い順）
+func (s *PostgreSQLStorage) ListLedgerEntries(ctx context.Context, itemID, locationID string, from, to time.Time) ([]inventory.LedgerEntry, error) {
+	query := `
+		SELECT transaction_id, type, item_id, location_id, delta, created_at, consumption
+		FROM ledger_entries
+		WHERE item_id = $1 AND location_id = $2 AND created_at > $3 AND created_at <= $4
+		ORDER BY created_at, transaction_id`
+
+	rows, err := s.db.QueryContext(ctx, query, itemID, locationID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("台帳明細取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []inventory.LedgerEntry
+	for rows.Next() {
+		var entry inventory.LedgerEntry
+		if err := rows.Scan(
+			&entry.TransactionID,
+			&entry.Type,
+			&entry.ItemID,
+			&entry.LocationID,
+			&entry.Delta,
+			&entry.CreatedAt,
+			&entry.Consumption,
+		); err != nil {
+			return nil, fmt.Errorf("台帳明細スキャンに失敗しました: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// IterateStock streams stock rows joined with their items, filtered by location and category
+// ロケーション・カテゴリで絞り込んだ在庫を商品マスタとともに1行ずつ渡す
+func (s *PostgreSQLStorage) IterateStock(ctx context.Context, filter inventory.ReportFilter, fn func(inventory.Stock, inventory.Item) error) error {
+	query := `
+		SELECT st.item_id, st.location_id, st.quantity, st.reserved, st.available, st.version, st.updated_at, st.updated_by,
+			i.id, i.name, i.sku, i.description, i.category, i.unit_cost, i.quantity_precision, i.created_at, i.updated_at
+		FROM stocks st
+		JOIN items i ON i.id = st.item_id
+		WHERE ($1 = '' OR st.location_id = $1) AND ($2 = '' OR i.category = $2)
+		ORDER BY st.location_id, st.item_id`
+
+	rows, err := s.db.QueryContext(ctx, query, filter.LocationID, filter.Category)
+	if err != nil {
+		return fmt.Errorf("レポート用在庫取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var stock inventory.Stock
+		var item inventory.Item
+		err := rows.Scan(
+			&stock.ItemID,
+			&stock.LocationID,
+			&stock.Quantity,
+			&stock.Reserved,
+			&stock.Available,
+			&stock.Version,
+			&stock.UpdatedAt,
+			&stock.UpdatedBy,
+			&item.ID,
+			&item.Name,
+			&item.SKU,
+			&item.Description,
+			&item.Category,
+			&item.UnitCost,
+			&item.QuantityPrecision,
+			&item.CreatedAt,
+			&item.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("在庫スキャンに失敗しました: %w", err)
+		}
+		if err := fn(stock, item); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// IterateTransactions streams the inbound, outbound and adjustment transactions of a period joined with their items
+// 期間中の入庫・出庫・調整トランザクションを商品マスタとともに古い順に1行ずつ渡す
+//
+// 移動は出庫と入庫の2行で台帳に計上されるため、監査用の移動行（type = 'transfer'）は含めません。
+func (s *PostgreSQLStorage) IterateTransactions(ctx context.Context, filter inventory.ReportFilter, fn func(inventory.Transaction, inventory.Item) error) error {
+	query := `
+		SELECT t.id, t.type, t.item_id, t.from_location, t.to_location, t.quantity, t.unit_cost, t.reference, t.lot_number,
+			t.expiry_date, t.metadata, t.created_at, t.created_by, t.reversal_of, t.reversed_by,
+			t.cost_currency, t.source_unit_cost, t.exchange_rate,
+			i.id, i.name, i.sku, i.description, i.category, i.unit_cost, i.quantity_precision, i.created_at, i.updated_at
+		FROM transactions t
+		JOIN items i ON i.id = t.item_id
+		WHERE t.type <> 'transfer' AND t.created_at > $1 AND t.created_at <= $2
+			AND ($3 = '' OR t.from_location = $3 OR t.to_location = $3) AND ($4 = '' OR i.category = $4)
+		ORDER BY t.created_at, t.id`
+
+	rows, err := s.db.QueryContext(ctx, query, filter.From, filter.To, filter.LocationID, filter.Category)
+	if err != nil {
+		return fmt.Errorf("レポート用トランザクション取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tx inventory.Transaction
+		var item inventory.Item
+		var metadataJSON []byte
+
+		err := rows.Scan(
+			&tx.ID,
+			&tx.Type,
+			&tx.ItemID,
+			&tx.FromLocation,
+			&tx.ToLocation,
+			&tx.Quantity,
+			&tx.UnitCost,
+			&tx.Reference,
+			&tx.LotNumber,
+			&tx.ExpiryDate,
+			&metadataJSON,
+			&tx.CreatedAt,
+			&tx.CreatedBy,
+			&tx.ReversalOf,
+			&tx.ReversedBy,
+			&tx.CostCurrency,
+			&tx.SourceUnitCost,
+			&tx.ExchangeRate,
+			&item.ID,
+			&item.Name,
+			&item.SKU,
+			&item.Description,
+			&item.Category,
+			&item.UnitCost,
+			&item.QuantityPrecision,
+			&item.CreatedAt,
+			&item.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("トランザクションスキャンに失敗しました: %w", err)
+		}
+
+		// メタデータのデシリアライズ
+		if len(metadataJSON) > 0 {
+			if err := json.Unmarshal(metadataJSON, &tx.Metadata); err != nil {
+				s.logger.Warn("メタデータのパースに失敗しました", zap.Error(err))
+			}
+		}
+
+		if err := fn(tx, item); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// IterateStockMovementSummaries streams per item and location opening, movements and closing aggregated from the ledger
+// 台帳から集計した商品・ロケーション別の期首・入出庫・調整・期末数量を商品マスタとともに1行ずつ渡す
+func (s *PostgreSQLStorage) IterateStockMovementSummaries(ctx context.Context, filter inventory.ReportFilter, fn func(inventory.StockMovementSummary, inventory.Item) error) error {
+	query := `
+		SELECT le.item_id, le.location_id,
+			COALESCE(SUM(le.delta) FILTER (WHERE le.created_at <= $1), 0),
+			COALESCE(SUM(le.delta) FILTER (WHERE le.created_at > $1 AND le.type = 'inbound'), 0),
+			COALESCE(-SUM(le.delta) FILTER (WHERE le.created_at > $1 AND le.type = 'outbound'), 0),
+			COALESCE(SUM(le.delta) FILTER (WHERE le.created_at > $1 AND le.type = 'adjust'), 0),
+			COALESCE(SUM(le.delta), 0),
+			i.id, i.name, i.sku, i.description, i.category, i.unit_cost, i.quantity_precision, i.created_at, i.updated_at
+		FROM ledger_entries le
+		JOIN items i ON i.id = le.item_id
+		WHERE le.created_at <= $2 AND ($3 = '' OR le.location_id = $3) AND ($4 = '' OR i.category = $4)
+		GROUP BY le.item_id, le.location_id, i.id
+		ORDER BY le.location_id, le.item_id`
+
+	rows, err := s.db.QueryContext(ctx, query, filter.From, filter.To, filter.LocationID, filter.Category)
+	if err != nil {
+		return fmt.Errorf("入出庫集計の取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var summary inventory.StockMovementSummary
+		var item inventory.Item
+		err := rows.Scan(
+			&summary.ItemID,
+			&summary.LocationID,
+			&summary.OpeningQuantity,
+			&summary.InboundQuantity,
+			&summary.OutboundQuantity,
+			&summary.AdjustmentQuantity,
+			&summary.ClosingQuantity,
+			&item.ID,
+			&item.Name,
+			&item.SKU,
+			&item.Description,
+			&item.Category,
+			&item.UnitCost,
+			&item.QuantityPrecision,
+			&item.CreatedAt,
+			&item.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("入出庫集計スキャンに失敗しました: %w", err)
+		}
+		if err := fn(summary, item); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// transferOrderColumns is the column list shared by transfer order queries
+// 移動指示クエリ共通のカラム一覧
+const transferOrderColumns = `id, item_id, from_location, to_location, quantity, received_quantity, discrepancy_quantity,
+	COALESCE(discrepancy_reason, ''), status, COALESCE(reference, ''), shipped_at, completed_at, created_at, created_by, updated_at, version`
+
+// scanTransferOrder scans a transfer order row
+// 移動指示の行をスキャン
+func scanTransferOrder(row interface{ Scan(dest ...any) error }) (*inventory.TransferOrder, error) {
+	var order inventory.TransferOrder
+	err := row.Scan(
+		&order.ID,
+		&order.ItemID,
+		&order.FromLocation,
+		&order.ToLocation,
+		&order.Quantity,
+		&order.ReceivedQuantity,
+		&order.DiscrepancyQuantity,
+		&order.DiscrepancyReason,
+		&order.Status,
+		&order.Reference,
+		&order.ShippedAt,
+		&order.CompletedAt,
+		&order.CreatedAt,
+		&order.CreatedBy,
+		&order.UpdatedAt,
+		&order.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// CreateTransferOrder creates a new transfer order
+// 新しい移動指示を作成
+func (s *PostgreSQLStorage) CreateTransferOrder(ctx context.Context, order *inventory.TransferOrder) error {
+	query := `
+		INSERT INTO transfer_orders (id, item_id, from_location, to_location, quantity, received_quantity, discrepancy_quantity,
+			discrepancy_reason, status, reference, shipped_at, completed_at, created_at, created_by, updated_at, version)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`
+
+	_, err := s.db.ExecContext(ctx, query,
+		order.ID,
+		order.ItemID,
+		order.FromLocation,
+		order.ToLocation,
+		order.Quantity,
+		order.ReceivedQuantity,
+		order.DiscrepancyQuantity,
+		order.DiscrepancyReason,
+		order.Status,
+		order.Reference,
+		order.ShippedAt,
+		order.CompletedAt,
+		order.CreatedAt,
+		order.CreatedBy,
+		order.UpdatedAt,
+		order.Version,
+	)
+
+	if err != nil {
+		return fmt.Errorf("移動指示作成に失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// GetTransferOrder retrieves a transfer order by ID
+// IDで移動指示を取得
+func (s *PostgreSQLStorage) GetTransferOrder(ctx context.Context, orderID string) (*inventory.TransferOrder, error) {
+	query := `SELECT ` + transferOrderColumns + ` FROM transfer_orders WHERE id = $1`
+
+	order, err := scanTransferOrder(s.db.QueryRowContext(ctx, query, orderID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, inventory.ErrTransferOrderNotFound
+		}
+		return nil, fmt.Errorf("移動指示取得に失敗しました: %w", err)
+	}
+
+	return order, nil
+}
+
+// UpdateTransferOrder updates quantities and status of a transfer order with optimistic locking
+// 移動指示の数量とステータスを楽観的ロック付きで更新
+func (s *PostgreSQLStorage) UpdateTransferOrder(ctx context.Context, order *inventory.TransferOrder) error {
+	query := `
+		UPDATE transfer_orders
+		SET received_quantity = $2, discrepancy_quantity = $3, discrepancy_reason = $4, status = $5,
+			shipped_at = $6, completed_at = $7, updated_at = $8, version = $9
+		WHERE id = $1 AND version = $10`
+
+	result, err := s.db.ExecContext(ctx, query,
+		order.ID,
+		order.ReceivedQuantity,
+		order.DiscrepancyQuantity,
+		order.DiscrepancyReason,
+		order.Status,
+		order.ShippedAt,
+		order.CompletedAt,
+		order.UpdatedAt,
+		order.Version,
+		order.Version-1, // 楽観的ロックのための前バージョン
+	)
+
+	if err != nil {
+		return fmt.Errorf("移動指示更新に失敗しました: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("更新行数の取得に失敗しました: %w", err)
+	}
+
+	// 読み込み後に他の処理が出荷・入荷などで更新済み
+	if rowsAffected == 0 {
+		return inventory.ErrInvalidTransferOrderStatus
+	}
+
+	return nil
+}
+
+// ListTransferOrders retrieves transfer orders, optionally filtered by status
+// 移動指示一覧を取得（ステータス指定時は絞り込み）
+func (s *PostgreSQLStorage) ListTransferOrders(ctx context.Context, status inventory.TransferOrderStatus, offset, limit int) ([]inventory.TransferOrder, error) {
+	query := `SELECT ` + transferOrderColumns + `
+		FROM transfer_orders
+		WHERE ($1 = '' OR status = $1)
+		ORDER BY created_at DESC
+		OFFSET $2 LIMIT $3`
+
+	rows, err := s.db.QueryContext(ctx, query, string(status), offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("移動指示一覧取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	var orders []inventory.TransferOrder
+	for rows.Next() {
+		order, err := scanTransferOrder(rows)
+		if err != nil {
+			return nil, fmt.Errorf("移動指示スキャンに失敗しました: %w", err)
+		}
+		orders = append(orders, *order)
+	}
+
+	return orders, nil
+}
+
+// CreateSupplier creates a new supplier
+// 新しい仕入先を作成
+func (s *PostgreSQLStorage) CreateSupplier(ctx context.Context, supplier *inventory.Supplier) error {
+	query := `
+		INSERT INTO suppliers (id, name, contact_name, email, phone, address, lead_time_days, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+	_, err := s.db.ExecContext(ctx, query,
+		supplier.ID,
+		supplier.Name,
+		supplier.ContactName,
+		supplier.Email,
+		supplier.Phone,
+		supplier.Address,
+		supplier.LeadTimeDays,
+		supplier.IsActive,
+		supplier.CreatedAt,
+		supplier.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("仕入先作成に失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// GetSupplier retrieves a supplier by ID
+// IDで仕入先を取得
+func (s *PostgreSQLStorage) GetSupplier(ctx context.Context, supplierID string) (*inventory.Supplier, error) {
+	query := `
+		SELECT id, name, COALESCE(contact_name, ''), COALESCE(email, ''), COALESCE(phone, ''), COALESCE(address, ''),
+			lead_time_days, is_active, created_at, updated_at
+		FROM suppliers
+		WHERE id = $1`
+
+	var supplier inventory.Supplier
+	err := s.db.QueryRowContext(ctx, query, supplierID).Scan(
+		&supplier.ID,
+		&supplier.Name,
+		&supplier.ContactName,
+		&supplier.Email,
+		&supplier.Phone,
+		&supplier.Address,
+		&supplier.LeadTimeDays,
+		&supplier.IsActive,
+		&supplier.CreatedAt,
+		&supplier.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, inventory.ErrSupplierNotFound
+		}
+		return nil, fmt.Errorf("仕入先取得に失敗しました: %w", err)
+	}
+
+	return &supplier, nil
+}
+
+// UpdateSupplier updates an existing supplier
+// 既存の仕入先を更新
+func (s *PostgreSQLStorage) UpdateSupplier(ctx context.Context, supplier *inventory.Supplier) error {
+	query := `
+		UPDATE suppliers
+		SET name = $2, contact_name = $3, email = $4, phone = $5, address = $6, lead_time_days = $7, is_active = $8, updated_at = $9
+		WHERE id = $1`
+
+	result, err := s.db.ExecContext(ctx, query,
+		supplier.ID,
+		supplier.Name,
+		supplier.ContactName,
+		supplier.Email,
+		supplier.Phone,
+		supplier.Address,
+		supplier.LeadTimeDays,
+		supplier.IsActive,
+		supplier.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("仕入先更新に失敗しました: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("更新行数の取得に失敗しました: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return inventory.ErrSupplierNotFound
+	}
+
+	return nil
+}
+
+// ListSuppliers retrieves suppliers with pagination
+// ページネーション付きで仕入先一覧を取得
+func (s *PostgreSQLStorage) ListSuppliers(ctx context.Context, offset, limit int) ([]inventory.Supplier, error) {
+	query := `
+		SELECT id, name, COALESCE(contact_name, ''), COALESCE(email, ''), COALESCE(phone, ''), COALESCE(address, ''),
+			lead_time_days, is_active, created_at, updated_at
+		FROM suppliers
+		ORDER BY name
+		OFFSET $1 LIMIT $2`
+
+	rows, err := s.db.QueryContext(ctx, query, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("仕入先一覧取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	var suppliers []inventory.Supplier
+	for rows.Next() {
+		var supplier inventory.Supplier
+		err := rows.Scan(
+			&supplier.ID,
+			&supplier.Name,
+			&supplier.ContactName,
+			&supplier.Email,
+			&supplier.Phone,
+			&supplier.Address,
+			&supplier.LeadTimeDays,
+			&supplier.IsActive,
+			&supplier.CreatedAt,
+			&supplier.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("仕入先スキャンに失敗しました: %w", err)
+		}
+		suppliers = append(suppliers, supplier)
+	}
+
+	return suppliers, nil
+}
+
+// CreatePurchaseOrder creates a purchase order together with its lines
+// 発注を明細とともに作成
+func (s *PostgreSQLStorage) CreatePurchaseOrder(ctx context.Context, order *inventory.PurchaseOrder) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("トランザクション開始に失敗しました: %w", err)
+	}
+	defer tx.Rollback()
+
+	headerQuery := `
+		INSERT INTO purchase_orders (id, supplier_id, status, reference, order_date, notes, created_at, created_by, updated_at, currency, location_id, version)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NULLIF($11, ''), $12)`
+
+	if _, err := tx.ExecContext(ctx, headerQuery,
+		order.ID,
+		order.SupplierID,
+		order.Status,
+		order.Reference,
+		order.OrderDate,
+		order.Notes,
+		order.CreatedAt,
+		order.CreatedBy,
+		order.UpdatedAt,
+		order.Currency,
+		order.LocationID,
+		order.Version,
+	); err != nil {
+		return fmt.Errorf("発注作成に失敗しました: %w", err)
+	}
+
+	lineQuery := `
+		INSERT INTO purchase_order_lines (id, purchase_order_id, line_number, item_id, quantity, received_quantity, unit_cost, expected_date)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	for _, line := range order.Lines {
+		if _, err := tx.ExecContext(ctx, lineQuery,
+			line.ID,
+			order.ID,
+			line.LineNumber,
+			line.ItemID,
+			line.Quantity,
+			line.ReceivedQuantity,
+			line.UnitCost,
+			line.ExpectedDate,
+		); err != nil {
+			return fmt.Errorf("発注明細作成に失敗しました: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("トランザクションコミットに失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// GetPurchaseOrder retrieves a purchase order with its lines
+// 発注を明細付きで取得
+func (s *PostgreSQLStorage) GetPurchaseOrder(ctx context.Context, orderID string) (*inventory.PurchaseOrder, error) {
+	query := `
+		SELECT id, supplier_id, status, COALESCE(reference, ''), order_date, COALESCE(notes, ''), created_at, created_by, updated_at, currency, COALESCE(location_id, ''), version
+		FROM purchase_orders
+		WHERE id = $1`
+
+	var order inventory.PurchaseOrder
+	err := s.db.QueryRowContext(ctx, query, orderID).Scan(
+		&order.ID,
+		&order.SupplierID,
+		&order.Status,
+		&order.Reference,
+		&order.OrderDate,
+		&order.Notes,
+		&order.CreatedAt,
+		&order.CreatedBy,
+		&order.UpdatedAt,
+		&order.Currency,
+		&order.LocationID,
+		&order.Version,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, inventory.ErrPurchaseOrderNotFound
+		}
+		return nil, fmt.Errorf("発注取得に失敗しました: %w", err)
+	}
+
+	lines, err := s.listPurchaseOrderLines(ctx, []string{order.ID})
+	if err != nil {
+		return nil, err
+	}
+	order.Lines = lines[order.ID]
+
+	return &order, nil
+}
+
+// UpdatePurchaseOrder updates the status and received quantities of a purchase order with optimistic locking
+// 発注のステータスと明細の入荷済み数量を楽観的ロック付きで更新
+func (s *PostgreSQLStorage) UpdatePurchaseOrder(ctx context.Context, order *inventory.PurchaseOrder) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("トランザクション開始に失敗しました: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := updatePurchaseOrder(ctx, tx, order); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("トランザクションコミットに失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// ApplyPurchaseReceipt writes the stock records, transactions and lots of a receipt and the updated purchase order
+// in one database transaction
+// 入荷の在庫記録・トランザクション・ロットと更新後の発注を1つのデータベーストランザクションで書き込み
+func (s *PostgreSQLStorage) ApplyPurchaseReceipt(ctx context.Context, order *inventory.PurchaseOrder, stocks []*inventory.Stock, transactions []*inventory.Transaction, lots []*inventory.Lot) error {
+	dbTx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("トランザクション開始に失敗しました: %w", err)
+	}
+	defer dbTx.Rollback()
+
+	// 発注を先に更新し、同時入荷で負けた場合は在庫を書き込まない
+	if err := updatePurchaseOrder(ctx, dbTx, order); err != nil {
+		return err
+	}
+
+	if err := writeStockChanges(ctx, dbTx, stocks, transactions); err != nil {
+		return err
+	}
+
+	for _, lot := range lots {
+		if err := insertLot(ctx, dbTx, lot); err != nil {
+			return err
+		}
+	}
+
+	if err := dbTx.Commit(); err != nil {
+		return fmt.Errorf("トランザクションコミットに失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// updatePurchaseOrder updates a purchase order and its lines within dbTx, checking the previous version
+// データベーストランザクション内で発注と明細を更新（前バージョンでない場合はErrVersionMismatch）
+func updatePurchaseOrder(ctx context.Context, dbTx *sql.Tx, order *inventory.PurchaseOrder) error {
+	result, err := dbTx.ExecContext(ctx,
+		`UPDATE purchase_orders SET status = $2, notes = $3, updated_at = $4, version = $5 WHERE id = $1 AND version = $6`,
+		order.ID, order.Status, order.Notes, order.UpdatedAt, order.Version,
+		order.Version-1, // 楽観的ロックのための前バージョン
+	)
+	if err != nil {
+		return fmt.Errorf("発注更新に失敗しました: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("更新行数の取得に失敗しました: %w", err)
+	}
+
+	// 読み込み後に他の処理が入荷・打ち切りなどで更新済み
+	if rowsAffected == 0 {
+		return inventory.ErrVersionMismatch
+	}
+
+	for _, line := range order.Lines {
+		if _, err := dbTx.ExecContext(ctx,
+			`UPDATE purchase_order_lines SET received_quantity = $2 WHERE id = $1`,
+			line.ID, line.ReceivedQuantity,
+		); err != nil {
+			return fmt.Errorf("発注明細更新に失敗しました: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ListPurchaseOrders retrieves purchase orders with their lines
+// 発注一覧を明細付きで取得
+func (s *PostgreSQLStorage) ListPurchaseOrders(ctx context.Context, status inventory.PurchaseOrderStatus, supplierID string, offset, limit int) ([]inventory.PurchaseOrder, error) {
+	query := `
+		SELECT id, supplier_id, status, COALESCE(reference, ''), order_date, COALESCE(notes, ''), created_at, created_by, updated_at, currency, COALESCE(location_id, ''), version
+		FROM purchase_orders
+		WHERE ($1 = '' OR status = $1) AND ($2 = '' OR supplier_id = $2)
+		ORDER BY created_at DESC
+		OFFSET $3 LIMIT $4`
+
+	rows, err := s.db.QueryContext(ctx, query, string(status), supplierID, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("発注一覧取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	var orders []inventory.PurchaseOrder
+	var orderIDs []string
+	for rows.Next() {
+		var order inventory.PurchaseOrder
+		err := rows.Scan(
+			&order.ID,
+			&order.SupplierID,
+			&order.Status,
+			&order.Reference,
+			&order.OrderDate,
+			&order.Notes,
+			&order.CreatedAt,
+			&order.CreatedBy,
+			&order.UpdatedAt,
+			&order.Currency,
+			&order.LocationID,
+			&order.Version,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("発注スキャンに失敗しました: %w", err)
+		}
+		orders = append(orders, order)
+		orderIDs = append(orderIDs, order.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("発注一覧取得に失敗しました: %w", err)
+	}
+
+	if len(orderIDs) == 0 {
+		return orders, nil
+	}
+
+	lines, err := s.listPurchaseOrderLines(ctx, orderIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range orders {
+		orders[i].Lines = lines[orders[i].ID]
+	}
+
+	return orders, nil
+}
+
+// listPurchaseOrderLines retrieves lines for the given purchase orders keyed by order ID
+// 指定発注の明細を発注IDごとに取得
+func (s *PostgreSQLStorage) listPurchaseOrderLines(ctx context.Context, orderIDs []string) (map[string][]inventory.PurchaseOrderLine, error) {
+	query := `
+		SELECT id, purchase_order_id, line_number, item_id, quantity, received_quantity, unit_cost, expected_date
+		FROM purchase_order_lines
+		WHERE purchase_order_id = ANY($1)
+		ORDER BY purchase_order_id, line_number`
+
+	rows, err := s.db.QueryContext(ctx, query, pq.Array(orderIDs))
+	if err != nil {
+		return nil, fmt.Errorf("発注明細取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	lines := make(map[string][]inventory.PurchaseOrderLine)
+	for rows.Next() {
+		var line inventory.PurchaseOrderLine
+		err := rows.Scan(
+			&line.ID,
+			&line.PurchaseOrderID,
+			&line.LineNumber,
+			&line.ItemID,
+			&line.Quantity,
+			&line.ReceivedQuantity,
+			&line.UnitCost,
+			&line.ExpectedDate,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("発注明細スキャンに失敗しました: %w", err)
+		}
+		line.CalculateReceiptVariance()
+		lines[line.PurchaseOrderID] = append(lines[line.PurchaseOrderID], line)
+	}
+
+	return lines, rows.Err()
+}
+
+// GetOnOrderQuantity returns the outstanding quantity of an item on open purchase orders,
+// limited to orders destined for locationID unless it is empty
+// 未完了の発注における商品の発注残数量を取得（ロケーション指定時は入荷先が一致する発注のみ）
+func (s *PostgreSQLStorage) GetOnOrderQuantity(ctx context.Context, itemID, locationID string) (int64, error) {
+	query := `
+		SELECT COALESCE(SUM(GREATEST(l.quantity - l.received_quantity, 0)), 0)
+		FROM purchase_order_lines l
+		JOIN purchase_orders po ON po.id = l.purchase_order_id
+		WHERE l.item_id = $1 AND po.status IN ('open', 'partially_received')
+			AND ($2 = '' OR po.location_id = $2)`
+
+	var onOrder int64
+	if err := s.db.QueryRowContext(ctx, query, itemID, locationID).Scan(&onOrder); err != nil {
+		return 0, fmt.Errorf("発注残数量取得に失敗しました: %w", err)
+	}
+
+	return onOrder, nil
+}
+
+// GetItemLeadTimeDays returns the lead time of the supplier the item was most recently ordered from
+// 指定商品を直近に発注した仕入先の標準リードタイムを取得
+func (s *PostgreSQLStorage) GetItemLeadTimeDays(ctx context.Context, itemID string) (int, error) {
+	query := `
+		SELECT s.lead_time_days
+		FROM purchase_order_lines l
+		JOIN purchase_orders po ON po.id = l.purchase_order_id
+		JOIN suppliers s ON s.id = po.supplier_id
+		WHERE l.item_id = $1
+		ORDER BY po.order_date DESC, po.created_at DESC
+		LIMIT 1`
+
+	var leadTimeDays int
+	err := s.db.QueryRowContext(ctx, query, itemID).Scan(&leadTimeDays)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("リードタイム取得に失敗しました: %w", err)
+	}
+
+	return leadTimeDays, nil
+}
+
+// CreateOutboundOrder creates an outbound order together with its lines
+// 出荷指示を明細とともに作成
+func (s *PostgreSQLStorage) CreateOutboundOrder(ctx context.Context, order *inventory.OutboundOrder) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("トランザクション開始に失敗しました: %w", err)
+	}
+	defer tx.Rollback()
+
+	headerQuery := `
+		INSERT INTO outbound_orders (id, customer, status, reference, wave_id, notes, created_at, created_by, updated_at, version)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+	if _, err := tx.ExecContext(ctx, headerQuery,
+		order.ID,
+		order.Customer,
+		order.Status,
+		order.Reference,
+		order.WaveID,
+		order.Notes,
+		order.CreatedAt,
+		order.CreatedBy,
+		order.UpdatedAt,
+		order.Version,
+	); err != nil {
+		return fmt.Errorf("出荷指示作成に失敗しました: %w", err)
+	}
+
+	lineQuery := `
+		INSERT INTO outbound_order_lines (id, order_id, line_number, item_id, quantity, allocated_quantity, picked_quantity, backordered_quantity)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	for _, line := range order.Lines {
+		if _, err := tx.ExecContext(ctx, lineQuery,
+			line.ID,
+			order.ID,
+			line.LineNumber,
+			line.ItemID,
+			line.Quantity,
+			line.AllocatedQuantity,
+			line.PickedQuantity,
+			line.BackorderedQuantity,
+		); err != nil {
+			return fmt.Errorf("出荷指示明細作成に失敗しました: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("トランザクションコミットに失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// GetOutboundOrder retrieves an outbound order with its lines and allocations
+// 出荷指示を明細・引当付きで取得
+func (s *PostgreSQLStorage) GetOutboundOrder(ctx context.Context, orderID string) (*inventory.OutboundOrder, error) {
+	query := `
+		SELECT id, customer, status, COALESCE(reference, ''), wave_id, COALESCE(notes, ''), created_at, created_by, updated_at, version
+		FROM outbound_orders
+		WHERE id = $1`
+
+	order, err := scanOutboundOrder(s.db.QueryRowContext(ctx, query, orderID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, inventory.ErrOutboundOrderNotFound
+		}
+		return nil, fmt.Errorf("出荷指示取得に失敗しました: %w", err)
+	}
+
+	if err := s.loadOutboundOrderDetails(ctx, []*inventory.OutboundOrder{order}); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// UpdateOutboundOrder updates the status, line quantities and allocations of an outbound order with optimistic locking
+// 出荷指示のステータス・明細数量・引当を楽観的ロック付きで更新
+func (s *PostgreSQLStorage) UpdateOutboundOrder(ctx context.Context, order *inventory.OutboundOrder) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("トランザクション開始に失敗しました: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`UPDATE outbound_orders SET status = $2, wave_id = $3, notes = $4, updated_at = $5, version = $6 WHERE id = $1 AND version = $7`,
+		order.ID, order.Status, order.WaveID, order.Notes, order.UpdatedAt, order.Version,
+		order.Version-1, // 楽観的ロックのための前バージョン
+	)
+	if err != nil {
+		return fmt.Errorf("出荷指示更新に失敗しました: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("更新行数の取得に失敗しました: %w", err)
+	}
+
+	// 読み込み後に他の処理が引当・キャンセル・ウェーブ割り当てなどで更新済み
+	if rowsAffected == 0 {
+		return inventory.ErrInvalidOutboundOrderStatus
+	}
+
+	for _, line := range order.Lines {
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE outbound_order_lines SET allocated_quantity = $2, picked_quantity = $3, backordered_quantity = $4 WHERE id = $1`,
+			line.ID, line.AllocatedQuantity, line.PickedQuantity, line.BackorderedQuantity,
+		); err != nil {
+			return fmt.Errorf("出荷指示明細更新に失敗しました: %w", err)
+		}
+	}
+
+	allocationQuery := `
+		INSERT INTO pick_allocations (id, order_id, line_id, item_id, location_id, quantity, picked_quantity, confirmed)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (id) DO UPDATE SET picked_quantity = EXCLUDED.picked_quantity, confirmed = EXCLUDED.confirmed`
+
+	for _, allocation := range order.Allocations {
+		if _, err := tx.ExecContext(ctx, allocationQuery,
+			allocation.ID,
+			order.ID,
+			allocation.LineID,
+			allocation.ItemID,
+			allocation.LocationID,
+			allocation.Quantity,
+			allocation.PickedQuantity,
+			allocation.Confirmed,
+		); err != nil {
+			return fmt.Errorf("引当更新に失敗しました: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("トランザクションコミットに失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// ListOutboundOrders retrieves outbound orders with their lines and allocations
+// 出荷指示一覧を明細・引当付きで取得
+func (s *PostgreSQLStorage) ListOutboundOrders(ctx context.Context, status inventory.OutboundOrderStatus, offset, limit int) ([]inventory.OutboundOrder, error) {
+	query := `
+		SELECT id, customer, status, COALESCE(reference, ''), wave_id, COALESCE(notes, ''), created_at, created_by, updated_at, version
+		FROM outbound_orders
+		WHERE ($1 = '' OR status = $1)
+		ORDER BY created_at DESC
+		OFFSET $2 LIMIT $3`
+
+	rows, err := s.db.QueryContext(ctx, query, string(status), offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("出荷指示一覧取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	var orders []*inventory.OutboundOrder
+	for rows.Next() {
+		order, err := scanOutboundOrder(rows)
+		if err != nil {
+			return nil, fmt.Errorf("出荷指示スキャンに失敗しました: %w", err)
+		}
+		orders = append(orders, order)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("出荷指示一覧取得に失敗しました: %w", err)
+	}
+
+	if err := s.loadOutboundOrderDetails(ctx, orders); err != nil {
+		return nil, err
+	}
+
+	result := make([]inventory.OutboundOrder, 0, len(orders))
+	for _, order := range orders {
+		result = append(result, *order)
+	}
+	return result, nil
+}
+
+// GetOrderFillQuantities sums ordered and shipped quantities of an item on orders completed within a date range
+// 期間内に出荷完了・一部出荷となった出荷指示における商品の受注数量と出荷数量を集計
+func (s *PostgreSQLStorage) GetOrderFillQuantities(ctx context.Context, itemID string, from, to time.Time) (int64, int64, error) {
+	query := `
+		SELECT COALESCE(SUM(l.quantity), 0), COALESCE(SUM(l.picked_quantity), 0)
+		FROM outbound_order_lines l
+		JOIN outbound_orders o ON o.id = l.order_id
+		WHERE l.item_id = $1 AND o.status IN ('shipped', 'backordered')
+			AND o.updated_at > $2 AND o.updated_at <= $3`
+
+	var ordered, shipped int64
+	if err := s.db.QueryRowContext(ctx, query, itemID, from, to).Scan(&ordered, &shipped); err != nil {
+		return 0, 0, fmt.Errorf("充足数量の集計に失敗しました: %w", err)
+	}
+
+	return ordered, shipped, nil
+}
+
+// CreateWave creates a wave and assigns the given outbound orders to it
+// ウェーブを作成し、対象の出荷指示を割り当て
+func (s *PostgreSQLStorage) CreateWave(ctx context.Context, wave *inventory.Wave) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("トランザクション開始に失敗しました: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO waves (id, reference, created_at, created_by) VALUES ($1, $2, $3, $4)`,
+		wave.ID, wave.Reference, wave.CreatedAt, wave.CreatedBy,
+	); err != nil {
+		return fmt.Errorf("ウェーブ作成に失敗しました: %w", err)
+	}
+
+	// 引当済みで他のウェーブに割り当てられていない出荷指示のみ対象（バージョンを進めて同時のピッキング確定などと競合させる）
+	result, err := tx.ExecContext(ctx,
+		`UPDATE outbound_orders SET wave_id = $1, updated_at = $2, version = version + 1
+		WHERE id = ANY($3) AND wave_id IS NULL AND status = 'allocated'`,
+		wave.ID, wave.CreatedAt, pq.Array(wave.OrderIDs),
+	)
+	if err != nil {
+		return fmt.Errorf("出荷指示のウェーブ割り当てに失敗しました: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("更新行数の取得に失敗しました: %w", err)
+	}
+
+	if rowsAffected != int64(len(wave.OrderIDs)) {
+		return inventory.ErrInvalidOutboundOrderStatus
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("トランザクションコミットに失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// GetWave retrieves a wave with the IDs of its outbound orders
+// ウェーブを対象の出荷指示ID付きで取得
+func (s *PostgreSQLStorage) GetWave(ctx context.Context, waveID string) (*inventory.Wave, error) {
+	var wave inventory.Wave
+	err := s.db.QueryRowContext(ctx,
+		`SELECT id, COALESCE(reference, ''), created_at, created_by FROM waves WHERE id = $1`,
+		waveID,
+	).Scan(&wave.ID, &wave.Reference, &wave.CreatedAt, &wave.CreatedBy)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, inventory.ErrWaveNotFound
+		}
+		return nil, fmt.Errorf("ウェーブ取得に失敗しました: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT id FROM outbound_orders WHERE wave_id = $1 ORDER BY created_at`, waveID)
+	if err != nil {
+		return nil, fmt.Errorf("ウェーブの出荷指示取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var orderID string
+		if err := rows.Scan(&orderID); err != nil {
+			return nil, fmt.Errorf("出荷指示スキャンに失敗しました: %w", err)
+		}
+		wave.OrderIDs = append(wave.OrderIDs, orderID)
+	}
+
+	return &wave, rows.Err()
+}
+
+// scanOutboundOrder scans an outbound order header row
+// 出荷指示のヘッダー行をスキャン
+func scanOutboundOrder(row interface{ Scan(dest ...any) error }) (*inventory.OutboundOrder, error) {
+	var order inventory.OutboundOrder
+	err := row.Scan(
+		&order.ID,
+		&order.Customer,
+		&order.Status,
+		&order.Reference,
+		&order.WaveID,
+		&order.Notes,
+		&order.CreatedAt,
+		&order.CreatedBy,
+		&order.UpdatedAt,
+		&order.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// loadOutboundOrderDetails loads lines and allocations into the given outbound orders
+// 出荷指示に明細と引当を読み込み
+func (s *PostgreSQLStorage) loadOutboundOrderDetails(ctx context.Context, orders []*inventory.OutboundOrder) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*inventory.OutboundOrder, len(orders))
+	orderIDs := make([]string, 0, len(orders))
+	for _, order := range orders {
+		byID[order.ID] = order
+		orderIDs = append(orderIDs, order.ID)
+	}
+
+	lineRows, err := s.db.QueryContext(ctx, `
+		SELECT id, order_id, line_number, item_id, quantity, allocated_quantity, picked_quantity, backordered_quantity
+		FROM outbound_order_lines
+		WHERE order_id = ANY($1)
+		ORDER BY order_id, line_number`, pq.Array(orderIDs))
+	if err != nil {
+		return fmt.Errorf("出荷指示明細取得に失敗しました: %w", err)
+	}
+	defer lineRows.Close()
+
+	for lineRows.Next() {
+		var line inventory.OutboundOrderLine
+		err := lineRows.Scan(
+			&line.ID,
+			&line.OrderID,
+			&line.LineNumber,
+			&line.ItemID,
+			&line.Quantity,
+			&line.AllocatedQuantity,
+			&line.PickedQuantity,
+			&line.BackorderedQuantity,
+		)
+		if err != nil {
+			return fmt.Errorf("出荷指示明細スキャンに失敗しました: %w", err)
+		}
+		byID[line.OrderID].Lines = append(byID[line.OrderID].Lines, line)
+	}
+	if err := lineRows.Err(); err != nil {
+		return fmt.Errorf("出荷指示明細取得に失敗しました: %w", err)
+	}
+
+	allocationRows, err := s.db.QueryContext(ctx, `
+		SELECT id, order_id, line_id, item_id, location_id, quantity, picked_quantity, confirmed
+		FROM pick_allocations
+		WHERE order_id = ANY($1)
+		ORDER BY order_id, location_id, item_id`, pq.Array(orderIDs))
+	if err != nil {
+		return fmt.Errorf("引当取得に失敗しました: %w", err)
+	}
+	defer allocationRows.Close()
+
+	for allocationRows.Next() {
+		var allocation inventory.PickAllocation
+		err := allocationRows.Scan(
+			&allocation.ID,
+			&allocation.OrderID,
+			&allocation.LineID,
+			&allocation.ItemID,
+			&allocation.LocationID,
+			&allocation.Quantity,
+			&allocation.PickedQuantity,
+			&allocation.Confirmed,
+		)
+		if err != nil {
+			return fmt.Errorf("引当スキャンに失敗しました: %w", err)
+		}
+		byID[allocation.OrderID].Allocations = append(byID[allocation.OrderID].Allocations, allocation)
+	}
+
+	return allocationRows.Err()
+}
+
+// SetBillOfMaterials replaces the components of a kit item
+// キット商品の構成部品を置き換え
+func (s *PostgreSQLStorage) SetBillOfMaterials(ctx context.Context, bom *inventory.BillOfMaterials) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("トランザクション開始に失敗しました: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM bom_components WHERE kit_item_id = $1`, bom.ItemID); err != nil {
+		return fmt.Errorf("部品表削除に失敗しました: %w", err)
+	}
+
+	query := `
+		INSERT INTO bom_components (kit_item_id, component_item_id, quantity, updated_at)
+		VALUES ($1, $2, $3, $4)`
+
+	for _, component := range bom.Components {
+		if _, err := tx.ExecContext(ctx, query,
+			bom.ItemID,
+			component.ItemID,
+			component.Quantity,
+			bom.UpdatedAt,
+		); err != nil {
+			return fmt.Errorf("部品表作成に失敗しました: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("トランザクションコミットに失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// GetBillOfMaterials retrieves the components of a kit item
+// キット商品の構成部品を取得
+func (s *PostgreSQLStorage) GetBillOfMaterials(ctx context.Context, itemID string) (*inventory.BillOfMaterials, error) {
+	query := `
+		SELECT component_item_id, quantity, updated_at
+		FROM bom_components
+		WHERE kit_item_id = $1
+		ORDER BY component_item_id`
+
+	rows, err := s.db.QueryContext(ctx, query, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("部品表取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	bom := &inventory.BillOfMaterials{ItemID: itemID}
+	for rows.Next() {
+		var component inventory.BOMComponent
+		if err := rows.Scan(&component.ItemID, &component.Quantity, &bom.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("部品表スキャンに失敗しました: %w", err)
+		}
+		bom.Components = append(bom.Components, component)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("部品表取得に失敗しました: %w", err)
+	}
+
+	if len(bom.Components) == 0 {
+		return nil, inventory.ErrBOMNotFound
+	}
+
+	return bom, nil
+}
+
+// cycleCountTaskColumns is the column list shared by cycle count task queries
+// 棚卸タスククエリ共通のカラム一覧
+const cycleCountTaskColumns = `id, location_id, item_id, abc_class, status, scheduled_date, expected_quantity, counted_quantity,
+	variance, variance_percent, COALESCE(counted_by, ''), counted_at, COALESCE(reviewed_by, ''), reviewed_at,
+	COALESCE(review_note, ''), created_at`
+
+// scanCycleCountTask scans a cycle count task row
+// 棚卸タスクの行をスキャン
+func scanCycleCountTask(row interface{ Scan(dest ...any) error }) (*inventory.CycleCountTask, error) {
+	var task inventory.CycleCountTask
+	err := row.Scan(
+		&task.ID,
+		&task.LocationID,
+		&task.ItemID,
+		&task.ABCClass,
+		&task.Status,
+		&task.ScheduledDate,
+		&task.ExpectedQuantity,
+		&task.CountedQuantity,
+		&task.Variance,
+		&task.VariancePercent,
+		&task.CountedBy,
+		&task.CountedAt,
+		&task.ReviewedBy,
+		&task.ReviewedAt,
+		&task.ReviewNote,
+		&task.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// CreateCycleCountTask creates a new cycle count task
+// 新しい棚卸タスクを作成
+func (s *PostgreSQLStorage) CreateCycleCountTask(ctx context.Context, task *inventory.CycleCountTask) error {
+	query := `
+		INSERT INTO cycle_count_tasks (id, location_id, item_id, abc_class, status, scheduled_date, expected_quantity, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	_, err := s.db.ExecContext(ctx, query,
+		task.ID,
+		task.LocationID,
+		task.ItemID,
+		task.ABCClass,
+		task.Status,
+		task.ScheduledDate,
+		task.ExpectedQuantity,
+		task.CreatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("棚卸タスク作成に失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// GetCycleCountTask retrieves a cycle count task by ID
+// IDで棚卸タスクを取得
+func (s *PostgreSQLStorage) GetCycleCountTask(ctx context.Context, taskID string) (*inventory.CycleCountTask, error) {
+	query := `SELECT ` + cycleCountTaskColumns + ` FROM cycle_count_tasks WHERE id = $1`
+
+	task, err := scanCycleCountTask(s.db.QueryRowContext(ctx, query, taskID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, inventory.ErrCycleCountTaskNotFound
+		}
+		return nil, fmt.Errorf("棚卸タスク取得に失敗しました: %w", err)
+	}
+
+	return task, nil
+}
+
+// UpdateCycleCountTask updates the count result and status of a cycle count task that is still in the given status
+// 指定ステータスのままの棚卸タスクの実棚結果とステータスを更新（他の処理が先に更新した場合はErrInvalidCycleCountStatus）
+func (s *PostgreSQLStorage) UpdateCycleCountTask(ctx context.Context, task *inventory.CycleCountTask, fromStatus inventory.CycleCountStatus) error {
+	return updateCycleCountTask(ctx, s.db, task, fromStatus)
+}
+
+// ApplyCycleCountSettlement saves an approved cycle count task and posts its variance adjustment in one database transaction
+// 承認済みの棚卸タスクの保存と差異の在庫調整を1つのデータベーストランザクションで反映
+func (s *PostgreSQLStorage) ApplyCycleCountSettlement(ctx context.Context, task *inventory.CycleCountTask, fromStatus inventory.CycleCountStatus, stocks []*inventory.Stock, transactions []*inventory.Transaction) error {
+	dbTx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("トランザクション開始に失敗しました: %w", err)
+	}
+	defer dbTx.Rollback()
+
+	// タスクを先に更新し、同時承認で負けた場合は在庫を書き込まない
+	if err := updateCycleCountTask(ctx, dbTx, task, fromStatus); err != nil {
+		return err
+	}
+
+	if err := writeStockChanges(ctx, dbTx, stocks, transactions); err != nil {
+		return err
+	}
+
+	if err := dbTx.Commit(); err != nil {
+		return fmt.Errorf("トランザクションコミットに失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// updateCycleCountTask updates a cycle count task using the given connection or transaction, checking the previous status
+// 指定された接続またはトランザクションで棚卸タスクを更新（前ステータスでない場合はErrInvalidCycleCountStatus）
+func updateCycleCountTask(ctx context.Context, db execer, task *inventory.CycleCountTask, fromStatus inventory.CycleCountStatus) error {
+	query := `
+		UPDATE cycle_count_tasks
+		SET status = $2, expected_quantity = $3, counted_quantity = $4, variance = $5, variance_percent = $6,
+			counted_by = NULLIF($7, ''), counted_at = $8, reviewed_by = NULLIF($9, ''), reviewed_at = $10, review_note = NULLIF($11, '')
+		WHERE id = $1 AND status = $12`
+
+	result, err := db.ExecContext(ctx, query,
+		task.ID,
+		task.Status,
+		task.ExpectedQuantity,
+		task.CountedQuantity,
+		task.Variance,
+		task.VariancePercent,
+		task.CountedBy,
+		task.CountedAt,
+		task.ReviewedBy,
+		task.ReviewedAt,
+		task.ReviewNote,
+		fromStatus,
+	)
+
+	if err != nil {
+		return fmt.Errorf("棚卸タスク更新に失敗しました: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("更新行数の取得に失敗しました: %w", err)
+	}
+
+	// 読み込み後に他の処理が実棚記録・承認などで更新済み
+	if rowsAffected == 0 {
+		return inventory.ErrInvalidCycleCountStatus
+	}
+
+	return nil
+}
+
+// ListCycleCountTasks retrieves cycle count tasks, optionally filtered by location and status
+// 棚卸タスク一覧を取得（ロケーション・ステータス指定時は絞り込み）
+func (s *PostgreSQLStorage) ListCycleCountTasks(ctx context.Context, locationID string, status inventory.CycleCountStatus, offset, limit int) ([]inventory.CycleCountTask, error) {
+	query := `SELECT ` + cycleCountTaskColumns + `
+		FROM cycle_count_tasks
+		WHERE ($1 = '' OR location_id = $1) AND ($2 = '' OR status = $2)
+		ORDER BY scheduled_date DESC, created_at DESC
+		OFFSET $3 LIMIT $4`
+
+	rows, err := s.db.QueryContext(ctx, query, locationID, string(status), offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("棚卸タスク一覧取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	var tasks []inventory.CycleCountTask
+	for rows.Next() {
+		task, err := scanCycleCountTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("棚卸タスクスキャンに失敗しました: %w", err)
+		}
+		tasks = append(tasks, *task)
+	}
+
+	return tasks, nil
+}
+
+// ListLatestCycleCountTasks retrieves the most recently scheduled task of each item at a location
+// ロケーションの商品ごとに予定日が最も新しい棚卸タスクを取得
+func (s *PostgreSQLStorage) ListLatestCycleCountTasks(ctx context.Context, locationID string) ([]inventory.CycleCountTask, error) {
+	query := `SELECT DISTINCT ON (item_id) ` + cycleCountTaskColumns + `
+		FROM cycle_count_tasks
+		WHERE location_id = $1
+		ORDER BY item_id, scheduled_date DESC, created_at DESC`
+
+	rows, err := s.db.QueryContext(ctx, query, locationID)
+	if err != nil {
+		return nil, fmt.Errorf("最新棚卸タスク取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	var tasks []inventory.CycleCountTask
+	for rows.Next() {
+		task, err := scanCycleCountTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("棚卸タスクスキャンに失敗しました: %w", err)
+		}
+		tasks = append(tasks, *task)
+	}
+
+	return tasks, nil
+}
+
+// UpsertReplenishmentPolicy creates or updates the replenishment policy of an item at a store
+// 店舗・商品単位の補充ポリシーを作成または更新
+func (s *PostgreSQLStorage) UpsertReplenishmentPolicy(ctx context.Context, policy *inventory.ReplenishmentPolicy) error {
+	query := `
+		INSERT INTO replenishment_policies (location_id, item_id, source_location_id, min_quantity, max_quantity, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (location_id, item_id) DO UPDATE
+		SET source_location_id = EXCLUDED.source_location_id, min_quantity = EXCLUDED.min_quantity,
+			max_quantity = EXCLUDED.max_quantity, is_active = EXCLUDED.is_active, updated_at = EXCLUDED.updated_at
+		RETURNING created_at`
+
+	err := s.db.QueryRowContext(ctx, query,
+		policy.LocationID,
+		policy.ItemID,
+		policy.SourceLocationID,
+		policy.MinQuantity,
+		policy.MaxQuantity,
+		policy.IsActive,
+		policy.CreatedAt,
+		policy.UpdatedAt,
+	).Scan(&policy.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("補充ポリシー登録に失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// ListReplenishmentPolicies retrieves replenishment policies, optionally filtered by store location
+// 補充ポリシー一覧を取得（補充先ロケーション指定時は絞り込み）
+func (s *PostgreSQLStorage) ListReplenishmentPolicies(ctx context.Context, locationID string) ([]inventory.ReplenishmentPolicy, error) {
+	query := `
+		SELECT location_id, item_id, source_location_id, min_quantity, max_quantity, is_active, created_at, updated_at
+		FROM replenishment_policies
+		WHERE ($1 = '' OR location_id = $1)
+		ORDER BY location_id, item_id`
+
+	rows, err := s.db.QueryContext(ctx, query, locationID)
+	if err != nil {
+		return nil, fmt.Errorf("補充ポリシー一覧取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	var policies []inventory.ReplenishmentPolicy
+	for rows.Next() {
+		var policy inventory.ReplenishmentPolicy
+		err := rows.Scan(
+			&policy.LocationID,
+			&policy.ItemID,
+			&policy.SourceLocationID,
+			&policy.MinQuantity,
+			&policy.MaxQuantity,
+			&policy.IsActive,
+			&policy.CreatedAt,
+			&policy.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("補充ポリシースキャンに失敗しました: %w", err)
+		}
+		policies = append(policies, policy)
+	}
+
+	return policies, nil
+}
+
+// DeleteReplenishmentPolicy deletes the replenishment policy of an item at a store
+// 店舗・商品単位の補充ポリシーを削除
+func (s *PostgreSQLStorage) DeleteReplenishmentPolicy(ctx context.Context, locationID, itemID string) error {
+	query := `DELETE FROM replenishment_policies WHERE location_id = $1 AND item_id = $2`
+
+	result, err := s.db.ExecContext(ctx, query, locationID, itemID)
+	if err != nil {
+		return fmt.Errorf("補充ポリシー削除に失敗しました: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("削除行数の取得に失敗しました: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return inventory.ErrReplenishmentPolicyNotFound
+	}
+
+	return nil
+}
+
+// replenishmentProposalColumns is the column list shared by replenishment proposal queries
+// 補充提案クエリ共通のカラム一覧
+const replenishmentProposalColumns = `id, location_id, source_location_id, item_id, status, current_quantity, min_quantity, max_quantity,
+	required_quantity, source_available, quantity, COALESCE(batch_id, ''), COALESCE(error, ''), created_at, created_by,
+	COALESCE(reviewed_by, ''), reviewed_at, executed_at`
+
+// scanReplenishmentProposal scans a replenishment proposal row
+// 補充提案の行をスキャン
+func scanReplenishmentProposal(row interface{ Scan(dest ...any) error }) (*inventory.ReplenishmentProposal, error) {
+	var proposal inventory.ReplenishmentProposal
+	err := row.Scan(
+		&proposal.ID,
+		&proposal.LocationID,
+		&proposal.SourceLocationID,
+		&proposal.ItemID,
+		&proposal.Status,
+		&proposal.CurrentQuantity,
+		&proposal.MinQuantity,
+		&proposal.MaxQuantity,
+		&proposal.RequiredQuantity,
+		&proposal.SourceAvailable,
+		&proposal.Quantity,
+		&proposal.BatchID,
+		&proposal.Error,
+		&proposal.CreatedAt,
+		&proposal.CreatedBy,
+		&proposal.ReviewedBy,
+		&proposal.ReviewedAt,
+		&proposal.ExecutedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &proposal, nil
+}
+
+// CreateReplenishmentProposal creates a new replenishment proposal
+// 新しい補充提案を作成
+func (s *PostgreSQLStorage) CreateReplenishmentProposal(ctx context.Context, proposal *inventory.ReplenishmentProposal) error {
+	query := `
+		INSERT INTO replenishment_proposals (id, location_id, source_location_id, item_id, status, current_quantity,
+			min_quantity, max_quantity, required_quantity, source_available, quantity, created_at, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
+
+	_, err := s.db.ExecContext(ctx, query,
+		proposal.ID,
+		proposal.LocationID,
+		proposal.SourceLocationID,
+		proposal.ItemID,
+		proposal.Status,
+		proposal.CurrentQuantity,
+		proposal.MinQuantity,
+		proposal.MaxQuantity,
+		proposal.RequiredQuantity,
+		proposal.SourceAvailable,
+		proposal.Quantity,
+		proposal.CreatedAt,
+		proposal.CreatedBy,
+	)
+
+	if err != nil {
+		return fmt.Errorf("補充提案作成に失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// GetReplenishmentProposal retrieves a replenishment proposal by ID
+// IDで補充提案を取得
+func (s *PostgreSQLStorage) GetReplenishmentProposal(ctx context.Context, proposalID string) (*inventory.ReplenishmentProposal, error) {
+	query := `SELECT ` + replenishmentProposalColumns + ` FROM replenishment_proposals WHERE id = $1`
+
+	proposal, err := scanReplenishmentProposal(s.db.QueryRowContext(ctx, query, proposalID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, inventory.ErrReplenishmentProposalNotFound
+		}
+		return nil, fmt.Errorf("補充提案取得に失敗しました: %w", err)
+	}
+
+	return proposal, nil
+}
+
+// UpdateReplenishmentProposal updates the status, quantity and execution result of a replenishment proposal
+// that is still in the given status
+// 指定ステータスのままの補充提案のステータス・数量・実行結果を更新（他の処理が先に更新した場合はErrInvalidReplenishmentStatus）
+func (s *PostgreSQLStorage) UpdateReplenishmentProposal(ctx context.Context, proposal *inventory.ReplenishmentProposal, fromStatus inventory.ReplenishmentProposalStatus) error {
+	query := `
+		UPDATE replenishment_proposals
+		SET status = $2, quantity = $3, batch_id = NULLIF($4, ''), error = NULLIF($5, ''),
+			reviewed_by = NULLIF($6, ''), reviewed_at = $7, executed_at = $8
+		WHERE id = $1 AND status = $9`
+
+	result, err := s.db.ExecContext(ctx, query,
+		proposal.ID,
+		proposal.Status,
+		proposal.Quantity,
+		proposal.BatchID,
+		proposal.Error,
+		proposal.ReviewedBy,
+		proposal.ReviewedAt,
+		proposal.ExecutedAt,
+		fromStatus,
+	)
+
+	if err != nil {
+		return fmt.Errorf("補充提案更新に失敗しました: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("更新行数の取得に失敗しました: %w", err)
+	}
+
+	// 読み込み後に他の処理が承認・実行などで更新済み
+	if rowsAffected == 0 {
+		return inventory.ErrInvalidReplenishmentStatus
+	}
+
+	return nil
+}
+
+// ListReplenishmentProposals retrieves replenishment proposals, optionally filtered by store location and status
+// 補充提案一覧を取得（補充先ロケーション・ステータス指定時は絞り込み）
+func (s *PostgreSQLStorage) ListReplenishmentProposals(ctx context.Context, locationID string, status inventory.ReplenishmentProposalStatus, offset, limit int) ([]inventory.ReplenishmentProposal, error) {
+	query := `SELECT ` + replenishmentProposalColumns + `
+		FROM replenishment_proposals
+		WHERE ($1 = '' OR location_id = $1) AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC
+		OFFSET $3 LIMIT $4`
+
+	rows, err := s.db.QueryContext(ctx, query, locationID, string(status), offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("補充提案一覧取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	return scanReplenishmentProposals(rows)
+}
+
+// ListOpenReplenishmentProposals retrieves all pending, approved and executing replenishment proposals
+// 承認待ち・承認済み（未実行）・実行中の補充提案をすべて取得
+func (s *PostgreSQLStorage) ListOpenReplenishmentProposals(ctx context.Context) ([]inventory.ReplenishmentProposal, error) {
+	query := `SELECT ` + replenishmentProposalColumns + `
+		FROM replenishment_proposals
+		WHERE status IN ('pending', 'approved', 'executing')
+		ORDER BY created_at`
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("未実行の補充提案取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	return scanReplenishmentProposals(rows)
+}
+
+// scanReplenishmentProposals scans replenishment proposal rows
+// 補充提案の複数行をスキャン
+func scanReplenishmentProposals(rows *sql.Rows) ([]inventory.ReplenishmentProposal, error) {
+	var proposals []inventory.ReplenishmentProposal
+	for rows.Next() {
+		proposal, err := scanReplenishmentProposal(rows)
+		if err != nil {
+			return nil, fmt.Errorf("補充提案スキャンに失敗しました: %w", err)
+		}
+		proposals = append(proposals, *proposal)
+	}
+	return proposals, nil
+}
+
+// itemClassificationColumns is the column list shared by item classification queries
+// 商品分類クエリ共通のカラム一覧
+const itemClassificationColumns = `item_id, location_id, abc_class, xyz_class, consumption_value, consumption_quantity,
+	cumulative_percent, variation_coefficient, window_days, classified_at`
+
+// scanItemClassifications scans item classification rows
+// 商品分類の行をスキャン
+func scanItemClassifications(rows *sql.Rows) ([]inventory.ItemClassification, error) {
+	var classifications []inventory.ItemClassification
+	for rows.Next() {
+		var c inventory.ItemClassification
+		err := rows.Scan(
+			&c.ItemID,
+			&c.LocationID,
+			&c.ABCClass,
+			&c.XYZClass,
+			&c.ConsumptionValue,
+			&c.ConsumptionQuantity,
+			&c.CumulativePercent,
+			&c.VariationCoefficient,
+			&c.WindowDays,
+			&c.ClassifiedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("商品分類スキャンに失敗しました: %w", err)
+		}
+		classifications = append(classifications, c)
+	}
+	return classifications, nil
+}
+
+// SaveItemClassifications stores the results of one classification run as history
+// 1回の分類結果を履歴として保存
+func (s *PostgreSQLStorage) SaveItemClassifications(ctx context.Context, classifications []inventory.ItemClassification) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("トランザクション開始に失敗しました: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO item_classifications (item_id, location_id, abc_class, xyz_class, consumption_value, consumption_quantity,
+			cumulative_percent, variation_coefficient, window_days, classified_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+	for _, c := range classifications {
+		if _, err := tx.ExecContext(ctx, query,
+			c.ItemID,
+			c.LocationID,
+			c.ABCClass,
+			c.XYZClass,
+			c.ConsumptionValue,
+			c.ConsumptionQuantity,
+			c.CumulativePercent,
+			c.VariationCoefficient,
+			c.WindowDays,
+			c.ClassifiedAt,
+		); err != nil {
+			return fmt.Errorf("商品分類保存に失敗しました: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("トランザクションコミットに失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// ListLatestItemClassifications retrieves the most recent classification run of a location
+// ロケーションの最新の分類結果を取得
+func (s *PostgreSQLStorage) ListLatestItemClassifications(ctx context.Context, locationID string) ([]inventory.ItemClassification, error) {
+	query := `SELECT ` + itemClassificationColumns + `
+		FROM item_classifications
+		WHERE location_id = $1
+			AND classified_at = (SELECT MAX(classified_at) FROM item_classifications WHERE location_id = $1)
+		ORDER BY cumulative_percent, item_id`
+
+	rows, err := s.db.QueryContext(ctx, query, locationID)
+	if err != nil {
+		return nil, fmt.Errorf("最新商品分類取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	return scanItemClassifications(rows)
+}
+
+// ListItemClassificationHistory retrieves the classification history of an item at a location
+// 商品・ロケーションの分類履歴を取得（新しい順）
+func (s *PostgreSQLStorage) ListItemClassificationHistory(ctx context.Context, itemID, locationID string, limit int) ([]inventory.ItemClassification, error) {
+	query := `SELECT ` + itemClassificationColumns + `
+		FROM item_classifications
+		WHERE item_id = $1 AND location_id = $2
+		ORDER BY classified_at DESC
+		LIMIT $3`
+
+	rows, err := s.db.QueryContext(ctx, query, itemID, locationID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("商品分類履歴取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	return scanItemClassifications(rows)
+}
+
+// ListStockDrifts returns (item, location) pairs whose stock quantity differs from the ledger balance
+// 在庫数量が台帳残高と一致しない(商品, ロケーション)を取得
+func (s *PostgreSQLStorage) ListStockDrifts(ctx context.Context) ([]inventory.StockDrift, error) {
+	query := `
+		WITH ledger AS (
+			SELECT item_id, location_id, SUM(delta) AS quantity
+			FROM ledger_entries
+			GROUP BY item_id, location_id
+		)
+		SELECT COALESCE(st.item_id, l.item_id) AS item_id,
+			COALESCE(st.location_id, l.location_id) AS location_id,
+			COALESCE(st.quantity, 0) AS stock_quantity,
+			COALESCE(l.quantity, 0) AS ledger_quantity,
+			COALESCE(st.version, 0) AS stock_version
+		FROM stocks st
+		FULL OUTER JOIN ledger l ON l.item_id = st.item_id AND l.location_id = st.location_id
+		WHERE COALESCE(st.quantity, 0) <> COALESCE(l.quantity, 0)
+		ORDER BY item_id, location_id`
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("在庫差異取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	var drifts []inventory.StockDrift
+	for rows.Next() {
+		var drift inventory.StockDrift
+		if err := rows.Scan(&drift.ItemID, &drift.LocationID, &drift.StockQuantity, &drift.LedgerQuantity, &drift.StockVersion); err != nil {
+			return nil, fmt.Errorf("在庫差異スキャンに失敗しました: %w", err)
+		}
+		drift.Difference = drift.StockQuantity - drift.LedgerQuantity
+		drifts = append(drifts, drift)
+	}
+
+	return drifts, rows.Err()
+}
+
+// itemColumns is the column list scanned by scanItem
+// scanItemでスキャンする商品の列
+const itemColumns = `id, name, sku, description, category, unit_cost, quantity_precision, parent_item_id, attributes, is_active, archived_at, created_at, updated_at`
+
+// scanItem scans an item row selected with itemColumns, followed by any extra columns
+// itemColumnsで選択した商品行（および後続の追加列）をスキャン
+func scanItem(row interface{ Scan(dest ...any) error }, extra ...any) (*inventory.Item, error) {
+	var item inventory.Item
+	var attributesJSON []byte
+	dest := []any{
+		&item.ID,
+		&item.Name,
+		&item.SKU,
+		&item.Description,
+		&item.Category,
+		&item.UnitCost,
+		&item.QuantityPrecision,
+		&item.ParentItemID,
+		&attributesJSON,
+		&item.IsActive,
+		&item.ArchivedAt,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	if len(attributesJSON) > 0 {
+		if err := json.Unmarshal(attributesJSON, &item.Attributes); err != nil {
+			return nil, fmt.Errorf("商品属性のパースに失敗しました: %w", err)
+		}
+	}
+	return &item, nil
+}
+
+// marshalItemAttributes converts item attributes to JSON, using an empty object for nil
+// 商品属性をJSONに変換（nilの場合は空オブジェクト）
+func marshalItemAttributes(attributes map[string]interface{}) ([]byte, error) {
+	if attributes == nil {
+		return []byte("{}"), nil
+	}
+	attributesJSON, err := json.Marshal(attributes)
+	if err != nil {
+		return nil, fmt.Errorf("商品属性のJSON変換に失敗しました: %w", err)
+	}
+	return attributesJSON, nil
+}
+
+// CreateItem creates a new item
+// 新しい商品を作成
+func (s *PostgreSQLStorage) CreateItem(ctx context.Context, item *inventory.Item) error {
+	attributesJSON, err := marshalItemAttributes(item.Attributes)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO items (id, name, sku, description, category, unit_cost, quantity_precision, parent_item_id, attributes, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+
+	_, err = s.db.ExecContext(ctx, query,
+		item.ID,
+		item.Name,
+		item.SKU,
+		item.Description,
+		item.Category,
+		item.UnitCost,
+		item.QuantityPrecision,
+		item.ParentItemID,
+		attributesJSON,
+		item.IsActive,
+		item.CreatedAt,
+		item.UpdatedAt,
+	)
+
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return inventory.ErrDuplicateItem
+		}
+		return fmt.Errorf("商品作成に失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// GetItem retrieves an item by ID
+// IDで商品を取得
+func (s *PostgreSQLStorage) GetItem(ctx context.Context, itemID string) (*inventory.Item, error) {
+	query := `
+		SELECT ` + itemColumns + `
+		FROM items 
+		WHERE id = $1`
+
+	item, err := scanItem(s.db.QueryRowContext(ctx, query, itemID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, inventory.ErrItemNotFound
+		}
+		return nil, fmt.Errorf("商品取得に失敗しました: %w", err)
+	}
+
+	return item, nil
+}
+
+// UpdateItem updates an existing item
+// 既存の商品を更新
+func (s *PostgreSQLStorage) UpdateItem(ctx context.Context, item *inventory.Item) error {
+	attributesJSON, err := marshalItemAttributes(item.Attributes)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE items 
+		SET name = $2, sku = $3, description = $4, category = $5, unit_cost = $6, quantity_precision = $7,
+		    parent_item_id = $8, attributes = $9, is_active = $10, updated_at = $11
+		WHERE id = $1`
+
+	result, err := s.db.ExecContext(ctx, query,
+		item.ID,
+		item.Name,
+		item.SKU,
+		item.Description,
+		item.Category,
+		item.UnitCost,
+		item.QuantityPrecision,
+		item.ParentItemID,
+		attributesJSON,
+		item.IsActive,
+		item.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("商品更新に失敗しました: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("更新行数の取得に失敗しました: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return inventory.ErrItemNotFound
+	}
+
+	return nil
+}
+
+// GetItemUsage counts the stock, reservations, open documents, variants and kits referencing an item
+// 商品を参照している在庫・引当・未完了の伝票・バリアント・キットの件数を集計
+func (s *PostgreSQLStorage) GetItemUsage(ctx context.Context, itemID string) (*inventory.MasterUsage, error) {
+	query := `
+		SELECT
+			COALESCE((SELECT SUM(ABS(quantity)) FROM stocks WHERE item_id = $1), 0),
+			COALESCE((SELECT SUM(reserved) FROM stocks WHERE item_id = $1), 0),
+			(SELECT COUNT(DISTINCT po.id) FROM purchase_orders po
+			 JOIN purchase_order_lines l ON l.purchase_order_id = po.id
+			 WHERE l.item_id = $1 AND po.status IN ('open', 'partially_received')),
+			(SELECT COUNT(*) FROM transfer_orders
+			 WHERE item_id = $1 AND status IN ('pending', 'in_transit', 'partially_received')),
+			(SELECT COUNT(DISTINCT o.id) FROM outbound_orders o
+			 JOIN outbound_order_lines l ON l.order_id = o.id
+			 WHERE l.item_id = $1 AND o.status IN ('pending', 'allocated', 'backordered')),
+			(SELECT COUNT(*) FROM cycle_count_tasks WHERE item_id = $1 AND status IN ('pending', 'discrepancy')),
+			(SELECT COUNT(*) FROM replenishment_proposals WHERE item_id = $1 AND status IN ('pending', 'approved', 'executing')),
+			(SELECT COUNT(*) FROM items WHERE parent_item_id = $1 AND archived_at IS NULL),
+			(SELECT COUNT(DISTINCT b.kit_item_id) FROM bom_components b
+			 JOIN items k ON k.id = b.kit_item_id
+			 WHERE b.component_item_id = $1 AND k.archived_at IS NULL)`
+
+	var usage inventory.MasterUsage
+	err := s.db.QueryRowContext(ctx, query, itemID).Scan(
+		&usage.StockQuantity,
+		&usage.ReservedQuantity,
+		&usage.OpenPurchaseOrders,
+		&usage.OpenTransferOrders,
+		&usage.OpenOutboundOrders,
+		&usage.PendingCycleCounts,
+		&usage.PendingReplenishments,
+		&usage.ActiveVariants,
+		&usage.ActiveKits,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("商品の使用状況の取得に失敗しました: %w", err)
+	}
+
+	return &usage, nil
+}
+
+// SetItemArchivedAt sets or clears (nil) the archive time of an item
+// 商品のアーカイブ日時を設定（nilの場合はアーカイブ解除）
+func (s *PostgreSQLStorage) SetItemArchivedAt(ctx context.Context, itemID string, archivedAt *time.Time) error {
+	query := `UPDATE items SET archived_at = $2, updated_at = NOW() WHERE id = $1`
+
+	result, err := s.db.ExecContext(ctx, query, itemID, archivedAt)
+	if err != nil {
+		return fmt.Errorf("商品のアーカイブ日時の更新に失敗しました: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("更新行数の取得に失敗しました: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return inventory.ErrItemNotFound
+	}
+
+	return nil
+}
+
+// itemSortColumns maps item sort fields to their SQL key expression and the type the cursor key is cast to
+// 商品の並び順のキーごとのSQL式と、カーソルのキー値をキャストする型
+var itemSortColumns = map[inventory.ItemSortField][2]string{
+	inventory.ItemSortName:      {"name", "text"},
+	inventory.ItemSortSKU:       {"COALESCE(sku, '')", "text"},
+	inventory.ItemSortUnitCost:  {"unit_cost", "numeric"},
+	inventory.ItemSortCreatedAt: {"created_at", "timestamp"},
+	inventory.ItemSortUpdatedAt: {"updated_at", "timestamp"},
+	inventory.ItemSortRelevance: {"{score}", "real"},
+}
+
+// ListItems retrieves items matching the filter with keyword substring matching
+// キーワードの部分一致で条件に一致する商品を取得
+func (s *PostgreSQLStorage) ListItems(ctx context.Context, filter inventory.ItemSearchFilter) (*inventory.ItemPage, error) {
+	return s.queryItems(ctx, filter, false)
+}
+
+// SearchItems retrieves items matching the filter with trigram fuzzy keyword matching
+// キーワードのトライグラム類似度によるあいまい一致で条件に一致する商品を取得
+func (s *PostgreSQLStorage) SearchItems(ctx context.Context, filter inventory.ItemSearchFilter) (*inventory.ItemPage, error) {
+	return s.queryItems(ctx, filter, true)
+}
+
+// queryItems builds and runs the filtered, keyset-paginated item query and its total count
+// 条件・キーセットページング付きの商品クエリと全件数のクエリを組み立てて実行
+//
+// あいまい検索ではpg_trgmの類似度演算子（%）と部分一致のいずれかで一致を判定し、
+// 商品名・SKU・説明の類似度の最大値を類似度順の並び順のキーとします。
+func (s *PostgreSQLStorage) queryItems(ctx context.Context, filter inventory.ItemSearchFilter, fuzzy bool) (*inventory.ItemPage, error) {
+	var conditions []string
+	var args []any
+	arg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	score := "0"
+	if filter.Query != "" {
+		q := arg(filter.Query)
+		if fuzzy {
+			conditions = append(conditions, strings.ReplaceAll(`(name % {q} OR sku % {q} OR description % {q}
+			     OR name ILIKE '%' || {q} || '%' OR sku ILIKE '%' || {q} || '%')`, "{q}", q))
+			score = strings.ReplaceAll(`GREATEST(similarity(name, {q}), similarity(COALESCE(sku, ''), {q}), similarity(COALESCE(description, ''), {q}))`, "{q}", q)
+		} else {
+			conditions = append(conditions, strings.ReplaceAll(`(name ILIKE '%' || {q} || '%' OR sku ILIKE '%' || {q} || '%'
+			     OR description ILIKE '%' || {q} || '%' OR category ILIKE '%' || {q} || '%')`, "{q}", q))
+		}
+	}
+	if filter.Category != "" {
+		conditions = append(conditions, "category = "+arg(filter.Category))
+	}
+	if filter.ParentItemID != "" {
+		conditions = append(conditions, "parent_item_id = "+arg(filter.ParentItemID))
+	}
+	if len(filter.Attributes) > 0 {
+		attributesJSON, err := json.Marshal(filter.Attributes)
+		if err != nil {
+			return nil, fmt.Errorf("属性条件のJSON変換に失敗しました: %w", err)
+		}
+		conditions = append(conditions, `NOT EXISTS (
+			SELECT 1 FROM jsonb_each_text(`+arg(attributesJSON)+`::jsonb) AS f
+			WHERE items.attributes ->> f.key IS DISTINCT FROM f.value)`)
+	}
+	if filter.Active != nil {
+		conditions = append(conditions, "is_active = "+arg(*filter.Active))
+	}
+	if filter.MinUnitCost != nil {
+		conditions = append(conditions, "unit_cost >= "+arg(*filter.MinUnitCost))
+	}
+	if filter.MaxUnitCost != nil {
+		conditions = append(conditions, "unit_cost <= "+arg(*filter.MaxUnitCost))
+	}
+	if !filter.IncludeArchived {
+		conditions = append(conditions, "archived_at IS NULL")
+	}
+	if filter.HasStock != nil {
+		exists := "EXISTS (SELECT 1 FROM stocks st WHERE st.item_id = items.id AND st.quantity > 0)"
+		if !*filter.HasStock {
+			exists = "NOT " + exists
+		}
+		conditions = append(conditions, exists)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	page := &inventory.ItemPage{}
+	countQuery := `SELECT COUNT(*) FROM items ` + where
+	if err := s.db.QueryRowContext(ctx, countQuery, args...).Scan(&page.TotalCount); err != nil {
+		return nil, fmt.Errorf("商品件数取得に失敗しました: %w", err)
+	}
+
+	sortField := filter.Sort
+	if sortField == "" {
+		sortField = inventory.ItemSortName
+	}
+	sortColumn, ok := itemSortColumns[sortField]
+	if !ok {
+		return nil, fmt.Errorf("無効な並び順です: %s", sortField)
+	}
+	sortKey := strings.ReplaceAll(sortColumn[0], "{score}", score)
+	direction, comparison := "ASC", ">"
+	if filter.Desc {
+		direction, comparison = "DESC", "<"
+	}
+
+	if filter.Cursor != "" {
+		cursor, err := inventory.DecodeItemCursor(filter.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		keyset := fmt.Sprintf("(%s, id) %s (%s::%s, %s)", sortKey, comparison, arg(cursor.Key), sortColumn[1], arg(cursor.ID))
+		if where == "" {
+			where = "WHERE " + keyset
+		} else {
+			where += " AND " + keyset
+		}
+	}
+
+	query := `
+		SELECT ` + itemColumns + `, (` + sortKey + `)::text
+		FROM items
+		` + where + `
+		ORDER BY ` + sortKey + ` ` + direction + `, id ` + direction
+	if filter.Limit > 0 {
+		// 次ページの有無を判定するため1件多く取得
+		query += ` LIMIT ` + arg(filter.Limit+1)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("商品一覧取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		item, err := scanItem(rows, &key)
+		if err != nil {
+			return nil, fmt.Errorf("商品スキャンに失敗しました: %w", err)
+		}
+		page.Items = append(page.Items, *item)
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("商品一覧取得に失敗しました: %w", err)
+	}
+
+	if filter.Limit > 0 && len(page.Items) > filter.Limit {
+		page.Items = page.Items[:filter.Limit]
+		last := page.Items[filter.Limit-1]
+		page.NextCursor = inventory.EncodeItemCursor(inventory.ItemCursor{
+			Sort: sortField,
+			Desc: filter.Desc,
+			Key:  keys[filter.Limit-1],
+			ID:   last.ID,
+		})
+	}
+
+	return page, nil
+}
+
+// SaveCategoryAttributeSchema upserts the attribute schema of a category
+// カテゴリの属性スキーマを保存（既存のスキーマは上書き）
+func (s *PostgreSQLStorage) SaveCategoryAttributeSchema(ctx context.Context, schema *inventory.CategoryAttributeSchema) error {
+	attributesJSON, err := json.Marshal(schema.Attributes)
+	if err != nil {
+		return fmt.Errorf("属性定義のJSON変換に失敗しました: %w", err)
+	}
+
+	query := `
+		INSERT INTO category_attribute_schemas (category, attributes, updated_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (category) DO UPDATE SET attributes = EXCLUDED.attributes, updated_at = EXCLUDED.updated_at`
+
+	if _, err := s.db.ExecContext(ctx, query, schema.Category, attributesJSON, schema.UpdatedAt); err != nil {
+		return fmt.Errorf("属性スキーマ保存に失敗しました: %w", err)
+	}
+	return nil
+}
+
+// scanCategoryAttributeSchema scans a category attribute schema row
+// カテゴリ属性スキーマ行をスキャン
+func scanCategoryAttributeSchema(row interface{ Scan(dest ...any) error }) (*inventory.CategoryAttributeSchema, error) {
+	var schema inventory.CategoryAttributeSchema
+	var attributesJSON []byte
+	if err := row.Scan(&schema.Category, &attributesJSON, &schema.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(attributesJSON, &schema.Attributes); err != nil {
+		return nil, fmt.Errorf("属性定義のパースに失敗しました: %w", err)
+	}
+	return &schema, nil
+}
+
+// GetCategoryAttributeSchema retrieves the attribute schema of a category
+// カテゴリの属性スキーマを取得
+func (s *PostgreSQLStorage) GetCategoryAttributeSchema(ctx context.Context, category string) (*inventory.CategoryAttributeSchema, error) {
+	query := `
+		SELECT category, attributes, updated_at
+		FROM category_attribute_schemas
+		WHERE category = $1`
+
+	schema, err := scanCategoryAttributeSchema(s.db.QueryRowContext(ctx, query, category))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, inventory.ErrAttributeSchemaNotFound
+		}
+		return nil, fmt.Errorf("属性スキーマ取得に失敗しました: %w", err)
+	}
+	return schema, nil
+}
+
+// ListCategoryAttributeSchemas retrieves the attribute schemas of all categories
+// 全カテゴリの属性スキーマを取得
+func (s *PostgreSQLStorage) ListCategoryAttributeSchemas(ctx context.Context) ([]inventory.CategoryAttributeSchema, error) {
+	query := `
+		SELECT category, attributes, updated_at
+		FROM category_attribute_schemas
+		ORDER BY category`
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("属性スキーマ一覧取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	var schemas []inventory.CategoryAttributeSchema
+	for rows.Next() {
+		schema, err := scanCategoryAttributeSchema(rows)
+		if err != nil {
+			return nil, fmt.Errorf("属性スキーマスキャンに失敗しました: %w", err)
+		}
+		schemas = append(schemas, *schema)
+	}
+
+	return schemas, rows.Err()
+}
+
+// SaveVariantGroup upserts the variant group of a parent item
+// 親商品のバリアントグループを保存（既存のグループは上書き）
+func (s *PostgreSQLStorage) SaveVariantGroup(ctx context.Context, group *inventory.VariantGroup) error {
+	axesJSON, err := json.Marshal(group.Axes)
+	if err != nil {
+		return fmt.Errorf("バリアント軸のJSON変換に失敗しました: %w", err)
+	}
+
+	query := `
+		INSERT INTO item_variant_groups (parent_item_id, axes, created_at, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (parent_item_id) DO UPDATE SET axes = EXCLUDED.axes, updated_at = EXCLUDED.updated_at`
+
+	if _, err := s.db.ExecContext(ctx, query, group.ParentItemID, axesJSON, group.CreatedAt, group.UpdatedAt); err != nil {
+		return fmt.Errorf("バリアントグループ保存に失敗しました: %w", err)
+	}
+	return nil
+}
+
+// GetVariantGroup retrieves the variant group of a parent item
+// 親商品のバリアントグループを取得
+func (s *PostgreSQLStorage) GetVariantGroup(ctx context.Context, parentItemID string) (*inventory.VariantGroup, error) {
+	query := `
+		SELECT parent_item_id, axes, created_at, updated_at
+		FROM item_variant_groups
+		WHERE parent_item_id = $1`
+
+	var group inventory.VariantGroup
+	var axesJSON []byte
+	err := s.db.QueryRowContext(ctx, query, parentItemID).Scan(&group.ParentItemID, &axesJSON, &group.CreatedAt, &group.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, inventory.ErrVariantGroupNotFound
+		}
+		return nil, fmt.Errorf("バリアントグループ取得に失敗しました: %w", err)
+	}
+	if err := json.Unmarshal(axesJSON, &group.Axes); err != nil {
+		return nil, fmt.Errorf("バリアント軸のパースに失敗しました: %w", err)
+	}
+	return &group, nil
+}
+
+// SaveItemBarcode inserts an item barcode
+// 商品バーコードを保存
+func (s *PostgreSQLStorage) SaveItemBarcode(ctx context.Context, barcode *inventory.ItemBarcode) error {
+	query := `
+		INSERT INTO item_barcodes (gtin, code, item_id, type, package_level, units_per_package, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	_, err := s.db.ExecContext(ctx, query,
+		barcode.GTIN,
+		barcode.Code,
+		barcode.ItemID,
+		barcode.Type,
+		barcode.PackageLevel,
+		barcode.UnitsPerPackage,
+		barcode.CreatedAt,
+	)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return inventory.ErrDuplicateBarcode
+		}
+		return fmt.Errorf("バーコード保存に失敗しました: %w", err)
+	}
+	return nil
+}
+
+// scanItemBarcode scans an item barcode row
+// 商品バーコード行をスキャン
+func scanItemBarcode(row interface{ Scan(dest ...any) error }) (*inventory.ItemBarcode, error) {
+	var barcode inventory.ItemBarcode
+	err := row.Scan(
+		&barcode.GTIN,
+		&barcode.Code,
+		&barcode.ItemID,
+		&barcode.Type,
+		&barcode.PackageLevel,
+		&barcode.UnitsPerPackage,
+		&barcode.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &barcode, nil
+}
+
+// GetItemBarcode retrieves a barcode by its normalized GTIN-14
+// 14桁に正規化したGTINでバーコードを取得
+func (s *PostgreSQLStorage) GetItemBarcode(ctx context.Context, gtin string) (*inventory.ItemBarcode, error) {
+	query := `
+		SELECT gtin, code, item_id, type, package_level, units_per_package, created_at
+		FROM item_barcodes
+		WHERE gtin = $1`
+
+	barcode, err := scanItemBarcode(s.db.QueryRowContext(ctx, query, gtin))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, inventory.ErrBarcodeNotFound
+		}
+		return nil, fmt.Errorf("バーコード取得に失敗しました: %w", err)
+	}
+	return barcode, nil
+}
+
+// ListItemBarcodes retrieves the barcodes of an item in registration order
+// 商品のバーコードを登録順に取得
+func (s *PostgreSQLStorage) ListItemBarcodes(ctx context.Context, itemID string) ([]inventory.ItemBarcode, error) {
+	query := `
+		SELECT gtin, code, item_id, type, package_level, units_per_package, created_at
+		FROM item_barcodes
+		WHERE item_id = $1
+		ORDER BY created_at, gtin`
+
+	rows, err := s.db.QueryContext(ctx, query, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("バーコード一覧取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	var barcodes []inventory.ItemBarcode
+	for rows.Next() {
+		barcode, err := scanItemBarcode(rows)
+		if err != nil {
+			return nil, fmt.Errorf("バーコードスキャンに失敗しました: %w", err)
+		}
+		barcodes = append(barcodes, *barcode)
+	}
+
+	return barcodes, rows.Err()
+}
+
+// DeleteItemBarcode deletes a barcode of an item
+// 商品のバーコードを削除
+func (s *PostgreSQLStorage) DeleteItemBarcode(ctx context.Context, itemID, gtin string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM item_barcodes WHERE item_id = $1 AND gtin = $2`, itemID, gtin)
+	if err != nil {
+		return fmt.Errorf("バーコード削除に失敗しました: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("削除行数の取得に失敗しました: %w", err)
+	}
+	if rowsAffected == 0 {
+		return inventory.ErrBarcodeNotFound
+	}
+	return nil
 }
 
 // CreateLocation creates a new location
 // 新しいロケーションを作成
 func (s *PostgreSQLStorage) CreateLocation(ctx context.Context, location *inventory.Location) error {
 	query := `
-		INSERT INTO locations (id, name, type, address, capacity, is_active, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+		INSERT INTO locations (id, name, type, address, capacity, is_active, pick_path, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
 
 	_, err := s.db.ExecContext(ctx, query,
 		location.ID,
@@ -598,70 +3680,584 @@ func (s *PostgreSQLStorage) CreateLocation(ctx context.Context, location *invent
 		location.Address,
 		location.Capacity,
 		location.IsActive,
+		location.PickPath,
 		location.CreatedAt,
 		location.UpdatedAt,
 	)
 
-	if err != nil {
-		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
-			return inventory.ErrDuplicateLocation
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return inventory.ErrDuplicateLocation
+		}
+		return fmt.Errorf("ロケーション作成に失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// GetLocation retrieves a location by ID
+// IDでロケーションを取得
+func (s *PostgreSQLStorage) GetLocation(ctx context.Context, locationID string) (*inventory.Location, error) {
+	query := `
+		SELECT id, name, type, address, capacity, is_active, pick_path, archived_at, created_at, updated_at
+		FROM locations 
+		WHERE id = $1`
+
+	location := &inventory.Location{}
+	err := s.db.QueryRowContext(ctx, query, locationID).Scan(
+		&location.ID,
+		&location.Name,
+		&location.Type,
+		&location.Address,
+		&location.Capacity,
+		&location.IsActive,
+		&location.PickPath,
+		&location.ArchivedAt,
+		&location.CreatedAt,
+		&location.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, inventory.ErrLocationNotFound
+		}
+		return nil, fmt.Errorf("ロケーション取得に失敗しました: %w", err)
+	}
+
+	return location, nil
+}
+
+// UpdateLocation updates an existing location
+// 既存のロケーションを更新
+func (s *PostgreSQLStorage) UpdateLocation(ctx context.Context, location *inventory.Location) error {
+	query := `
+		UPDATE locations 
+		SET name = $2, type = $3, address = $4, capacity = $5, is_active = $6, pick_path = $7, updated_at = $8
+		WHERE id = $1`
+
+	result, err := s.db.ExecContext(ctx, query,
+		location.ID,
+		location.Name,
+		location.Type,
+		location.Address,
+		location.Capacity,
+		location.IsActive,
+		location.PickPath,
+		location.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("ロケーション更新に失敗しました: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("更新行数の取得に失敗しました: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return inventory.ErrLocationNotFound
+	}
+
+	return nil
+}
+
+// GetLocationUsage counts the stock, reservations and open documents referencing a location
+// ロケーションを参照している在庫・引当・未完了の伝票の件数を集計
+func (s *PostgreSQLStorage) GetLocationUsage(ctx context.Context, locationID string) (*inventory.MasterUsage, error) {
+	query := `
+		SELECT
+			COALESCE((SELECT SUM(ABS(quantity)) FROM stocks WHERE location_id = $1), 0),
+			COALESCE((SELECT SUM(reserved) FROM stocks WHERE location_id = $1), 0),
+			(SELECT COUNT(*) FROM transfer_orders
+			 WHERE (from_location = $1 OR to_location = $1) AND status IN ('pending', 'in_transit', 'partially_received')),
+			(SELECT COUNT(DISTINCT o.id) FROM outbound_orders o
+			 JOIN pick_allocations a ON a.order_id = o.id
+			 WHERE a.location_id = $1 AND NOT a.confirmed AND o.status IN ('pending', 'allocated', 'backordered')),
+			(SELECT COUNT(*) FROM cycle_count_tasks WHERE location_id = $1 AND status IN ('pending', 'discrepancy')),
+			(SELECT COUNT(*) FROM replenishment_proposals
+			 WHERE (location_id = $1 OR source_location_id = $1) AND status IN ('pending', 'approved', 'executing'))`
+
+	var usage inventory.MasterUsage
+	err := s.db.QueryRowContext(ctx, query, locationID).Scan(
+		&usage.StockQuantity,
+		&usage.ReservedQuantity,
+		&usage.OpenTransferOrders,
+		&usage.OpenOutboundOrders,
+		&usage.PendingCycleCounts,
+		&usage.PendingReplenishments,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ロケーションの使用状況の取得に失敗しました: %w", err)
+	}
+
+	return &usage, nil
+}
+
+// SetLocationArchivedAt sets or clears (nil) the archive time of a location
+// ロケーションのアーカイブ日時を設定（nilの場合はアーカイブ解除）
+func (s *PostgreSQLStorage) SetLocationArchivedAt(ctx context.Context, locationID string, archivedAt *time.Time) error {
+	query := `UPDATE locations SET archived_at = $2, updated_at = NOW() WHERE id = $1`
+
+	result, err := s.db.ExecContext(ctx, query, locationID, archivedAt)
+	if err != nil {
+		return fmt.Errorf("ロケーションのアーカイブ日時の更新に失敗しました: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("更新行数の取得に失敗しました: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return inventory.ErrLocationNotFound
+	}
+
+	return nil
+}
+
+// masterImportJobColumns is the column list shared by master import job queries
+// マスタ取り込みジョブの取得で共通の列
+const masterImportJobColumns = `id, target, format, mode, dry_run, status, total_rows, processed_rows,
+	created_count, updated_count, error_count, errors, error, created_by, created_at, started_at, completed_at`
+
+// scanMasterImportJob scans a master import job row
+// マスタ取り込みジョブの行をスキャン
+func scanMasterImportJob(row interface{ Scan(dest ...any) error }) (*inventory.MasterImportJob, error) {
+	var job inventory.MasterImportJob
+	var errorsJSON []byte
+	err := row.Scan(
+		&job.ID,
+		&job.Target,
+		&job.Format,
+		&job.Mode,
+		&job.DryRun,
+		&job.Status,
+		&job.TotalRows,
+		&job.ProcessedRows,
+		&job.CreatedCount,
+		&job.UpdatedCount,
+		&job.ErrorCount,
+		&errorsJSON,
+		&job.Error,
+		&job.CreatedBy,
+		&job.CreatedAt,
+		&job.StartedAt,
+		&job.CompletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(errorsJSON, &job.Errors); err != nil {
+		return nil, fmt.Errorf("取り込みジョブの行エラーのパースに失敗しました: %w", err)
+	}
+	return &job, nil
+}
+
+// CreateBatchOperation stores the result of a batch operation
+// バッチ操作の実行結果を保存
+func (s *PostgreSQLStorage) CreateBatchOperation(ctx context.Context, batch *inventory.BatchOperation) error {
+	operationsJSON, err := json.Marshal(batch.Operations)
+	if err != nil {
+		return fmt.Errorf("操作リストのシリアライズに失敗しました: %w", err)
+	}
+	errorsJSON, err := json.Marshal(batch.Errors)
+	if err != nil {
+		return fmt.Errorf("エラーリストのシリアライズに失敗しました: %w", err)
+	}
+
+	query := `
+		INSERT INTO batch_operations (id, status, operations, success_count, failure_count, errors, created_at, completed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	_, err = s.db.ExecContext(ctx, query,
+		batch.ID,
+		batch.Status,
+		operationsJSON,
+		batch.SuccessCount,
+		batch.FailureCount,
+		errorsJSON,
+		batch.CreatedAt,
+		batch.CompletedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("バッチ操作の保存に失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// GetBatchOperation retrieves the result of a batch operation by ID
+// IDでバッチ操作の実行結果を取得
+func (s *PostgreSQLStorage) GetBatchOperation(ctx context.Context, batchID string) (*inventory.BatchOperation, error) {
+	query := `
+		SELECT id, status, operations, success_count, failure_count, errors, created_at, completed_at
+		FROM batch_operations
+		WHERE id = $1`
+
+	var batch inventory.BatchOperation
+	var operationsJSON, errorsJSON []byte
+	err := s.db.QueryRowContext(ctx, query, batchID).Scan(
+		&batch.ID,
+		&batch.Status,
+		&operationsJSON,
+		&batch.SuccessCount,
+		&batch.FailureCount,
+		&errorsJSON,
+		&batch.CreatedAt,
+		&batch.CompletedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, inventory.ErrBatchNotFound
+		}
+		return nil, fmt.Errorf("バッチ操作取得に失敗しました: %w", err)
+	}
+
+	if err := json.Unmarshal(operationsJSON, &batch.Operations); err != nil {
+		return nil, fmt.Errorf("操作リストのデシリアライズに失敗しました: %w", err)
+	}
+	if err := json.Unmarshal(errorsJSON, &batch.Errors); err != nil {
+		return nil, fmt.Errorf("エラーリストのデシリアライズに失敗しました: %w", err)
+	}
+
+	return &batch, nil
+}
+
+// CreateMasterImportJob registers a master import job
+// マスタ取り込みジョブを登録
+func (s *PostgreSQLStorage) CreateMasterImportJob(ctx context.Context, job *inventory.MasterImportJob) error {
+	errorsJSON, err := json.Marshal(job.Errors)
+	if err != nil {
+		return fmt.Errorf("行エラーのシリアライズに失敗しました: %w", err)
+	}
+
+	query := `
+		INSERT INTO master_import_jobs (id, target, format, mode, dry_run, status, total_rows, processed_rows,
+			created_count, updated_count, error_count, errors, error, created_by, created_at, started_at, completed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)`
+
+	_, err = s.db.ExecContext(ctx, query,
+		job.ID,
+		job.Target,
+		job.Format,
+		job.Mode,
+		job.DryRun,
+		job.Status,
+		job.TotalRows,
+		job.ProcessedRows,
+		job.CreatedCount,
+		job.UpdatedCount,
+		job.ErrorCount,
+		errorsJSON,
+		job.Error,
+		job.CreatedBy,
+		job.CreatedAt,
+		job.StartedAt,
+		job.CompletedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("取り込みジョブ登録に失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateMasterImportJob updates the status, progress and row errors of a master import job
+// マスタ取り込みジョブの状態・進捗・行エラーを更新
+func (s *PostgreSQLStorage) UpdateMasterImportJob(ctx context.Context, job *inventory.MasterImportJob) error {
+	errorsJSON, err := json.Marshal(job.Errors)
+	if err != nil {
+		return fmt.Errorf("行エラーのシリアライズに失敗しました: %w", err)
+	}
+
+	query := `
+		UPDATE master_import_jobs
+		SET status = $2, total_rows = $3, processed_rows = $4, created_count = $5, updated_count = $6,
+			error_count = $7, errors = $8, error = $9, started_at = $10, completed_at = $11
+		WHERE id = $1`
+
+	result, err := s.db.ExecContext(ctx, query,
+		job.ID,
+		job.Status,
+		job.TotalRows,
+		job.ProcessedRows,
+		job.CreatedCount,
+		job.UpdatedCount,
+		job.ErrorCount,
+		errorsJSON,
+		job.Error,
+		job.StartedAt,
+		job.CompletedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("取り込みジョブ更新に失敗しました: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("更新行数の取得に失敗しました: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return inventory.ErrMasterImportJobNotFound
+	}
+
+	return nil
+}
+
+// GetMasterImportJob retrieves a master import job by ID
+// IDでマスタ取り込みジョブを取得
+func (s *PostgreSQLStorage) GetMasterImportJob(ctx context.Context, jobID string) (*inventory.MasterImportJob, error) {
+	query := `SELECT ` + masterImportJobColumns + ` FROM master_import_jobs WHERE id = $1`
+
+	job, err := scanMasterImportJob(s.db.QueryRowContext(ctx, query, jobID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, inventory.ErrMasterImportJobNotFound
+		}
+		return nil, fmt.Errorf("取り込みジョブ取得に失敗しました: %w", err)
+	}
+
+	return job, nil
+}
+
+// ListMasterImportJobs retrieves master import jobs, newest first
+// マスタ取り込みジョブ一覧を受付日時の新しい順に取得
+func (s *PostgreSQLStorage) ListMasterImportJobs(ctx context.Context, offset, limit int) ([]inventory.MasterImportJob, error) {
+	query := `SELECT ` + masterImportJobColumns + `
+		FROM master_import_jobs
+		ORDER BY created_at DESC
+		OFFSET $1 LIMIT $2`
+
+	rows, err := s.db.QueryContext(ctx, query, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("取り込みジョブ一覧取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []inventory.MasterImportJob
+	for rows.Next() {
+		job, err := scanMasterImportJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("取り込みジョブスキャンに失敗しました: %w", err)
+		}
+		jobs = append(jobs, *job)
+	}
+
+	return jobs, nil
+}
+
+// openingBalanceLoadColumns is the column list shared by opening balance load queries
+// 期首在庫の取り込みの取得で共通の列
+const openingBalanceLoadColumns = `id, reference, as_of, status, line_count, total_value, error, created_by, created_at,
+	posted_at, rolled_back_at, rolled_back_by, rollback_reason`
+
+// scanOpeningBalanceLoad scans an opening balance load row
+// 期首在庫の取り込みの行をスキャン
+func scanOpeningBalanceLoad(row interface{ Scan(dest ...any) error }) (*inventory.OpeningBalanceLoad, error) {
+	var load inventory.OpeningBalanceLoad
+	err := row.Scan(
+		&load.ID,
+		&load.Reference,
+		&load.AsOf,
+		&load.Status,
+		&load.LineCount,
+		&load.TotalValue,
+		&load.Error,
+		&load.CreatedBy,
+		&load.CreatedAt,
+		&load.PostedAt,
+		&load.RolledBackAt,
+		&load.RolledBackBy,
+		&load.RollbackReason,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &load, nil
+}
+
+// CreateOpeningBalanceLoad registers an opening balance load with its lines
+// 期首在庫の取り込みを明細付きで登録
+func (s *PostgreSQLStorage) CreateOpeningBalanceLoad(ctx context.Context, load *inventory.OpeningBalanceLoad) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("トランザクション開始に失敗しました: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := insertOpeningBalanceLoad(ctx, tx, load); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("トランザクションコミットに失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateOpeningBalanceLoad updates the status of an opening balance load and the posting results of its lines
+// 期首在庫の取り込みの状態と明細の計上・取消結果を更新
+func (s *PostgreSQLStorage) UpdateOpeningBalanceLoad(ctx context.Context, load *inventory.OpeningBalanceLoad) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("トランザクション開始に失敗しました: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := updateOpeningBalanceLoad(ctx, tx, load); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("トランザクションコミットに失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// ApplyOpeningBalanceLoad registers a posted opening balance load with its lines, stock records, inbound
+// transactions and lots in one database transaction
+// 計上済みの期首在庫の取り込み・明細と在庫記録・入庫トランザクション・ロットを1つのデータベーストランザクションで書き込み
+func (s *PostgreSQLStorage) ApplyOpeningBalanceLoad(ctx context.Context, load *inventory.OpeningBalanceLoad, stocks []*inventory.Stock, transactions []*inventory.Transaction, lots []*inventory.Lot) error {
+	dbTx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("トランザクション開始に失敗しました: %w", err)
+	}
+	defer dbTx.Rollback()
+
+	if err := insertOpeningBalanceLoad(ctx, dbTx, load); err != nil {
+		return err
+	}
+
+	if err := writeStockChanges(ctx, dbTx, stocks, transactions); err != nil {
+		return err
+	}
+
+	for _, lot := range lots {
+		if err := insertLot(ctx, dbTx, lot); err != nil {
+			return err
+		}
+	}
+
+	if err := dbTx.Commit(); err != nil {
+		return fmt.Errorf("トランザクションコミットに失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// ApplyOpeningBalanceRollback writes the reversals of an opening balance load, links them to the original
+// transactions, deletes the load's lots and updates the load in one database transaction
+// 期首在庫の取消の在庫記録・取消トランザクション・元トランザクションへの取消参照・ロット削除と取り込みの更新を
+// 1つのデータベーストランザクションで実行
+func (s *PostgreSQLStorage) ApplyOpeningBalanceRollback(ctx context.Context, load *inventory.OpeningBalanceLoad, stocks []*inventory.Stock, transactions []*inventory.Transaction, lotIDs []string) error {
+	dbTx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("トランザクション開始に失敗しました: %w", err)
+	}
+	defer dbTx.Rollback()
+
+	if err := writeStockChanges(ctx, dbTx, stocks, transactions); err != nil {
+		return err
+	}
+
+	// 取消済みでない場合のみ記録（同時に取り消された場合は全体をロールバック）
+	for _, tx := range transactions {
+		if tx.ReversalOf == nil {
+			continue
+		}
+		if err := markTransactionReversed(ctx, dbTx, *tx.ReversalOf, tx.ID); err != nil {
+			return err
+		}
+	}
+
+	if len(lotIDs) > 0 {
+		if _, err := dbTx.ExecContext(ctx, `DELETE FROM lots WHERE id = ANY($1)`, pq.Array(lotIDs)); err != nil {
+			return fmt.Errorf("ロット削除に失敗しました: %w", err)
 		}
-		return fmt.Errorf("ロケーション作成に失敗しました: %w", err)
+	}
+
+	if err := updateOpeningBalanceLoad(ctx, dbTx, load); err != nil {
+		return err
+	}
+
+	if err := dbTx.Commit(); err != nil {
+		return fmt.Errorf("トランザクションコミットに失敗しました: %w", err)
 	}
 
 	return nil
 }
 
-// GetLocation retrieves a location by ID
-// IDでロケーションを取得
-func (s *PostgreSQLStorage) GetLocation(ctx context.Context, locationID string) (*inventory.Location, error) {
-	query := `
-		SELECT id, name, type, address, capacity, is_active, created_at, updated_at
-		FROM locations 
-		WHERE id = $1`
+// insertOpeningBalanceLoad inserts an opening balance load and its lines within dbTx
+// データベーストランザクション内で期首在庫の取り込みと明細を挿入
+func insertOpeningBalanceLoad(ctx context.Context, dbTx *sql.Tx, load *inventory.OpeningBalanceLoad) error {
+	headerQuery := `
+		INSERT INTO opening_balance_loads (id, reference, as_of, status, line_count, total_value, error, created_by, created_at,
+			posted_at, rolled_back_at, rolled_back_by, rollback_reason)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
 
-	location := &inventory.Location{}
-	err := s.db.QueryRowContext(ctx, query, locationID).Scan(
-		&location.ID,
-		&location.Name,
-		&location.Type,
-		&location.Address,
-		&location.Capacity,
-		&location.IsActive,
-		&location.CreatedAt,
-		&location.UpdatedAt,
-	)
+	if _, err := dbTx.ExecContext(ctx, headerQuery,
+		load.ID,
+		load.Reference,
+		load.AsOf,
+		load.Status,
+		load.LineCount,
+		load.TotalValue,
+		load.Error,
+		load.CreatedBy,
+		load.CreatedAt,
+		load.PostedAt,
+		load.RolledBackAt,
+		load.RolledBackBy,
+		load.RollbackReason,
+	); err != nil {
+		return fmt.Errorf("期首在庫の取り込み登録に失敗しました: %w", err)
+	}
 
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, inventory.ErrLocationNotFound
+	lineQuery := `
+		INSERT INTO opening_balance_lines (load_id, row_number, item_id, location_id, quantity, unit_cost, currency, value,
+			lot_number, expiry_date, transaction_id, lot_id, reversal_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
+
+	for _, line := range load.Lines {
+		if _, err := dbTx.ExecContext(ctx, lineQuery,
+			load.ID,
+			line.Row,
+			line.ItemID,
+			line.LocationID,
+			line.Quantity,
+			line.UnitCost,
+			line.Currency,
+			line.Value,
+			line.LotNumber,
+			line.ExpiryDate,
+			line.TransactionID,
+			line.LotID,
+			line.ReversalID,
+		); err != nil {
+			return fmt.Errorf("期首在庫の明細登録に失敗しました: %w", err)
 		}
-		return nil, fmt.Errorf("ロケーション取得に失敗しました: %w", err)
 	}
 
-	return location, nil
+	return nil
 }
 
-// UpdateLocation updates an existing location
-// 既存のロケーションを更新
-func (s *PostgreSQLStorage) UpdateLocation(ctx context.Context, location *inventory.Location) error {
-	query := `
-		UPDATE locations 
-		SET name = $2, type = $3, address = $4, capacity = $5, is_active = $6, updated_at = $7
-		WHERE id = $1`
-
-	result, err := s.db.ExecContext(ctx, query,
-		location.ID,
-		location.Name,
-		location.Type,
-		location.Address,
-		location.Capacity,
-		location.IsActive,
-		location.UpdatedAt,
+// updateOpeningBalanceLoad updates an opening balance load and the posting results of its lines within dbTx
+// データベーストランザクション内で期首在庫の取り込みの状態と明細の計上・取消結果を更新
+func updateOpeningBalanceLoad(ctx context.Context, dbTx *sql.Tx, load *inventory.OpeningBalanceLoad) error {
+	result, err := dbTx.ExecContext(ctx, `
+		UPDATE opening_balance_loads
+		SET status = $2, error = $3, posted_at = $4, rolled_back_at = $5, rolled_back_by = $6, rollback_reason = $7
+		WHERE id = $1`,
+		load.ID, load.Status, load.Error, load.PostedAt, load.RolledBackAt, load.RolledBackBy, load.RollbackReason,
 	)
-
 	if err != nil {
-		return fmt.Errorf("ロケーション更新に失敗しました: %w", err)
+		return fmt.Errorf("期首在庫の取り込み更新に失敗しました: %w", err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -670,44 +4266,106 @@ func (s *PostgreSQLStorage) UpdateLocation(ctx context.Context, location *invent
 	}
 
 	if rowsAffected == 0 {
-		return inventory.ErrLocationNotFound
+		return inventory.ErrOpeningBalanceLoadNotFound
+	}
+
+	for _, line := range load.Lines {
+		if _, err := dbTx.ExecContext(ctx,
+			`UPDATE opening_balance_lines SET transaction_id = $3, lot_id = $4, reversal_id = $5 WHERE load_id = $1 AND row_number = $2`,
+			load.ID, line.Row, line.TransactionID, line.LotID, line.ReversalID,
+		); err != nil {
+			return fmt.Errorf("期首在庫の明細更新に失敗しました: %w", err)
+		}
 	}
 
 	return nil
 }
 
-// DeleteLocation deletes a location by ID
-// IDでロケーションを削除
-func (s *PostgreSQLStorage) DeleteLocation(ctx context.Context, locationID string) error {
-	query := `DELETE FROM locations WHERE id = $1`
+// GetOpeningBalanceLoad retrieves an opening balance load with its lines
+// 期首在庫の取り込みを明細付きで取得
+func (s *PostgreSQLStorage) GetOpeningBalanceLoad(ctx context.Context, loadID string) (*inventory.OpeningBalanceLoad, error) {
+	query := `SELECT ` + openingBalanceLoadColumns + ` FROM opening_balance_loads WHERE id = $1`
 
-	result, err := s.db.ExecContext(ctx, query, locationID)
+	load, err := scanOpeningBalanceLoad(s.db.QueryRowContext(ctx, query, loadID))
 	if err != nil {
-		return fmt.Errorf("ロケーション削除に失敗しました: %w", err)
+		if err == sql.ErrNoRows {
+			return nil, inventory.ErrOpeningBalanceLoadNotFound
+		}
+		return nil, fmt.Errorf("期首在庫の取り込み取得に失敗しました: %w", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT row_number, item_id, location_id, quantity, unit_cost, currency, value, lot_number, expiry_date,
+			transaction_id, lot_id, reversal_id
+		FROM opening_balance_lines
+		WHERE load_id = $1
+		ORDER BY row_number`, loadID)
 	if err != nil {
-		return fmt.Errorf("削除行数の取得に失敗しました: %w", err)
+		return nil, fmt.Errorf("期首在庫の明細取得に失敗しました: %w", err)
 	}
+	defer rows.Close()
 
-	if rowsAffected == 0 {
-		return inventory.ErrLocationNotFound
+	for rows.Next() {
+		var line inventory.OpeningBalanceLine
+		if err := rows.Scan(
+			&line.Row,
+			&line.ItemID,
+			&line.LocationID,
+			&line.Quantity,
+			&line.UnitCost,
+			&line.Currency,
+			&line.Value,
+			&line.LotNumber,
+			&line.ExpiryDate,
+			&line.TransactionID,
+			&line.LotID,
+			&line.ReversalID,
+		); err != nil {
+			return nil, fmt.Errorf("期首在庫の明細スキャンに失敗しました: %w", err)
+		}
+		load.Lines = append(load.Lines, line)
 	}
 
-	return nil
+	return load, nil
+}
+
+// ListOpeningBalanceLoads retrieves opening balance loads without their lines, newest first
+// 期首在庫の取り込み一覧を取り込み日時の新しい順に取得（明細なし）
+func (s *PostgreSQLStorage) ListOpeningBalanceLoads(ctx context.Context, offset, limit int) ([]inventory.OpeningBalanceLoad, error) {
+	query := `SELECT ` + openingBalanceLoadColumns + `
+		FROM opening_balance_loads
+		ORDER BY created_at DESC
+		OFFSET $1 LIMIT $2`
+
+	rows, err := s.db.QueryContext(ctx, query, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("期首在庫の取り込み一覧取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	var loads []inventory.OpeningBalanceLoad
+	for rows.Next() {
+		load, err := scanOpeningBalanceLoad(rows)
+		if err != nil {
+			return nil, fmt.Errorf("期首在庫の取り込みスキャンに失敗しました: %w", err)
+		}
+		loads = append(loads, *load)
+	}
+
+	return loads, nil
 }
 
-// ListLocations retrieves locations with pagination
-// ページネーション付きでロケーション一覧を取得
-func (s *PostgreSQLStorage) ListLocations(ctx context.Context, offset, limit int) ([]inventory.Location, error) {
+// ListLocations retrieves locations with pagination, excluding archived ones unless includeArchived is set
+// ページネーション付きでロケーション一覧を取得（includeArchivedがfalseの場合はアーカイブ済みを除外）
+func (s *PostgreSQLStorage) ListLocations(ctx context.Context, offset, limit int, includeArchived bool) ([]inventory.Location, error) {
 	query := `
-		SELECT id, name, type, address, capacity, is_active, created_at, updated_at
+		SELECT id, name, type, address, capacity, is_active, pick_path, archived_at, created_at, updated_at
 		FROM locations 
+		WHERE $3 OR archived_at IS NULL
 		ORDER BY created_at DESC
 		OFFSET $1 LIMIT $2`
 
-	rows, err := s.db.QueryContext(ctx, query, offset, limit)
+	rows, err := s.db.QueryContext(ctx, query, offset, limit, includeArchived)
 	if err != nil {
 		return nil, fmt.Errorf("ロケーション一覧取得に失敗しました: %w", err)
 	}
@@ -723,6 +4381,8 @@ func (s *PostgreSQLStorage) ListLocations(ctx context.Context, offset, limit int
 			&location.Address,
 			&location.Capacity,
 			&location.IsActive,
+			&location.PickPath,
+			&location.ArchivedAt,
 			&location.CreatedAt,
 			&location.UpdatedAt,
 		)
@@ -738,11 +4398,17 @@ func (s *PostgreSQLStorage) ListLocations(ctx context.Context, offset, limit int
 // CreateLot creates a new lot record
 // 新しいロット記録を作成
 func (s *PostgreSQLStorage) CreateLot(ctx context.Context, lot *inventory.Lot) error {
+	return insertLot(ctx, s.db, lot)
+}
+
+// insertLot inserts a lot record using the given connection or transaction
+// 指定された接続またはトランザクションでロット記録を挿入
+func insertLot(ctx context.Context, db execer, lot *inventory.Lot) error {
 	query := `
 		INSERT INTO lots (id, number, item_id, quantity, unit_cost, expiry_date, created_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7)`
 
-	_, err := s.db.ExecContext(ctx, query,
+	_, err := db.ExecContext(ctx, query,
 		lot.ID,
 		lot.Number,
 		lot.ItemID,
@@ -824,6 +4490,26 @@ func (s *PostgreSQLStorage) GetLotsByItem(ctx context.Context, itemID string) ([
 	return lots, nil
 }
 
+// DeleteLot deletes a lot record
+// ロットを削除
+func (s *PostgreSQLStorage) DeleteLot(ctx context.Context, lotID string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM lots WHERE id = $1`, lotID)
+	if err != nil {
+		return fmt.Errorf("ロット削除に失敗しました: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("削除行数の取得に失敗しました: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return inventory.ErrLotNotFound
+	}
+
+	return nil
+}
+
 // GetExpiringLots retrieves lots that are expiring within the specified duration
 // 指定期間内に期限切れになるロットを取得
 func (s *PostgreSQLStorage) GetExpiringLots(ctx context.Context, within time.Duration) ([]inventory.Lot, error) {
@@ -1000,3 +4686,406 @@ func (s *PostgreSQLStorage) Ping(ctx context.Context) error {
 func (s *PostgreSQLStorage) Close() error {
 	return s.db.Close()
 }
+
+// reportScheduleColumns is the column list shared by report schedule queries
+// レポートスケジュールクエリ共通のカラム一覧
+const reportScheduleColumns = `id, name, cron_expression, timezone, report_type, format, location_id, category, period_days,
+	is_active, next_run_at, last_run_at, COALESCE(last_error, ''), created_at, created_by, updated_at`
+
+// scanReportSchedule scans a report schedule row
+// レポートスケジュールの行をスキャン
+func scanReportSchedule(row interface{ Scan(dest ...any) error }) (*inventory.ReportSchedule, error) {
+	var schedule inventory.ReportSchedule
+	err := row.Scan(
+		&schedule.ID,
+		&schedule.Name,
+		&schedule.CronExpression,
+		&schedule.Timezone,
+		&schedule.ReportType,
+		&schedule.Format,
+		&schedule.LocationID,
+		&schedule.Category,
+		&schedule.PeriodDays,
+		&schedule.IsActive,
+		&schedule.NextRunAt,
+		&schedule.LastRunAt,
+		&schedule.LastError,
+		&schedule.CreatedAt,
+		&schedule.CreatedBy,
+		&schedule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// scanReportSchedules scans report schedule rows
+// レポートスケジュールの複数行をスキャン
+func scanReportSchedules(rows *sql.Rows) ([]inventory.ReportSchedule, error) {
+	var schedules []inventory.ReportSchedule
+	for rows.Next() {
+		schedule, err := scanReportSchedule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("レポートスケジュールスキャンに失敗しました: %w", err)
+		}
+		schedules = append(schedules, *schedule)
+	}
+	return schedules, nil
+}
+
+// CreateReportSchedule creates a new report schedule
+// 新しいレポートスケジュールを作成
+func (s *PostgreSQLStorage) CreateReportSchedule(ctx context.Context, schedule *inventory.ReportSchedule) error {
+	query := `
+		INSERT INTO report_schedules (id, name, cron_expression, timezone, report_type, format, location_id, category,
+			period_days, is_active, next_run_at, created_at, created_by, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`
+
+	_, err := s.db.ExecContext(ctx, query,
+		schedule.ID,
+		schedule.Name,
+		schedule.CronExpression,
+		schedule.Timezone,
+		schedule.ReportType,
+		schedule.Format,
+		schedule.LocationID,
+		schedule.Category,
+		schedule.PeriodDays,
+		schedule.IsActive,
+		schedule.NextRunAt,
+		schedule.CreatedAt,
+		schedule.CreatedBy,
+		schedule.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("レポートスケジュール作成に失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// GetReportSchedule retrieves a report schedule by ID
+// IDでレポートスケジュールを取得
+func (s *PostgreSQLStorage) GetReportSchedule(ctx context.Context, scheduleID string) (*inventory.ReportSchedule, error) {
+	query := `SELECT ` + reportScheduleColumns + ` FROM report_schedules WHERE id = $1`
+
+	schedule, err := scanReportSchedule(s.db.QueryRowContext(ctx, query, scheduleID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, inventory.ErrReportScheduleNotFound
+		}
+		return nil, fmt.Errorf("レポートスケジュール取得に失敗しました: %w", err)
+	}
+
+	return schedule, nil
+}
+
+// ListReportSchedules retrieves all report schedules in creation order
+// レポートスケジュール一覧を作成日時順に取得
+func (s *PostgreSQLStorage) ListReportSchedules(ctx context.Context) ([]inventory.ReportSchedule, error) {
+	query := `SELECT ` + reportScheduleColumns + ` FROM report_schedules ORDER BY created_at`
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("レポートスケジュール一覧取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	return scanReportSchedules(rows)
+}
+
+// UpdateReportSchedule updates the settings and run status of a report schedule
+// レポートスケジュールの設定と実行状況を更新
+func (s *PostgreSQLStorage) UpdateReportSchedule(ctx context.Context, schedule *inventory.ReportSchedule) error {
+	query := `
+		UPDATE report_schedules
+		SET name = $2, cron_expression = $3, timezone = $4, report_type = $5, format = $6, location_id = $7,
+			category = $8, period_days = $9, is_active = $10, next_run_at = $11, last_run_at = $12,
+			last_error = NULLIF($13, ''), updated_at = $14
+		WHERE id = $1`
+
+	result, err := s.db.ExecContext(ctx, query,
+		schedule.ID,
+		schedule.Name,
+		schedule.CronExpression,
+		schedule.Timezone,
+		schedule.ReportType,
+		schedule.Format,
+		schedule.LocationID,
+		schedule.Category,
+		schedule.PeriodDays,
+		schedule.IsActive,
+		schedule.NextRunAt,
+		schedule.LastRunAt,
+		schedule.LastError,
+		schedule.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("レポートスケジュール更新に失敗しました: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("更新行数の取得に失敗しました: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return inventory.ErrReportScheduleNotFound
+	}
+
+	return nil
+}
+
+// DeleteReportSchedule deletes a report schedule
+// レポートスケジュールを削除
+func (s *PostgreSQLStorage) DeleteReportSchedule(ctx context.Context, scheduleID string) error {
+	query := `DELETE FROM report_schedules WHERE id = $1`
+
+	result, err := s.db.ExecContext(ctx, query, scheduleID)
+	if err != nil {
+		return fmt.Errorf("レポートスケジュール削除に失敗しました: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("削除行数の取得に失敗しました: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return inventory.ErrReportScheduleNotFound
+	}
+
+	return nil
+}
+
+// ListDueReportSchedules retrieves active report schedules whose next run time is at or before now
+// 次回実行予定日時がnow以前の有効なレポートスケジュールを取得
+func (s *PostgreSQLStorage) ListDueReportSchedules(ctx context.Context, now time.Time) ([]inventory.ReportSchedule, error) {
+	query := `SELECT ` + reportScheduleColumns + `
+		FROM report_schedules
+		WHERE is_active = TRUE AND next_run_at <= $1
+		ORDER BY next_run_at`
+
+	rows, err := s.db.QueryContext(ctx, query, now.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("実行対象のレポートスケジュール取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	return scanReportSchedules(rows)
+}
+
+// ClaimReportScheduleRun advances the next run time only if it is still scheduledAt
+// 次回実行予定日時がscheduledAtのままの場合に限りnextRunAtへ進める
+func (s *PostgreSQLStorage) ClaimReportScheduleRun(ctx context.Context, scheduleID string, scheduledAt, nextRunAt time.Time) (bool, error) {
+	query := `UPDATE report_schedules SET next_run_at = $3 WHERE id = $1 AND next_run_at = $2`
+
+	result, err := s.db.ExecContext(ctx, query, scheduleID, scheduledAt.UTC(), nextRunAt.UTC())
+	if err != nil {
+		return false, fmt.Errorf("レポートスケジュールの実行開始に失敗しました: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("更新行数の取得に失敗しました: %w", err)
+	}
+
+	return rowsAffected == 1, nil
+}
+
+// reportArchiveColumns is the column list shared by report archive queries
+// アーカイブ済みレポートクエリ共通のカラム一覧
+const reportArchiveColumns = `id, COALESCE(schedule_id, ''), report_type, format, location_id, category, period_from, period_to,
+	file_name, file_path, size_bytes, checksum, generated_at, generated_by`
+
+// scanReportArchive scans a report archive row
+// アーカイブ済みレポートの行をスキャン
+func scanReportArchive(row interface{ Scan(dest ...any) error }) (*inventory.ReportArchive, error) {
+	var archive inventory.ReportArchive
+	err := row.Scan(
+		&archive.ID,
+		&archive.ScheduleID,
+		&archive.ReportType,
+		&archive.Format,
+		&archive.LocationID,
+		&archive.Category,
+		&archive.PeriodFrom,
+		&archive.PeriodTo,
+		&archive.FileName,
+		&archive.FilePath,
+		&archive.SizeBytes,
+		&archive.Checksum,
+		&archive.GeneratedAt,
+		&archive.GeneratedBy,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &archive, nil
+}
+
+// CreateReportArchive records the metadata of an archived report
+// アーカイブ済みレポートのメタデータを登録
+func (s *PostgreSQLStorage) CreateReportArchive(ctx context.Context, archive *inventory.ReportArchive) error {
+	query := `
+		INSERT INTO report_archives (id, schedule_id, report_type, format, location_id, category, period_from, period_to,
+			file_name, file_path, size_bytes, checksum, generated_at, generated_by)
+		VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`
+
+	_, err := s.db.ExecContext(ctx, query,
+		archive.ID,
+		archive.ScheduleID,
+		archive.ReportType,
+		archive.Format,
+		archive.LocationID,
+		archive.Category,
+		archive.PeriodFrom,
+		archive.PeriodTo,
+		archive.FileName,
+		archive.FilePath,
+		archive.SizeBytes,
+		archive.Checksum,
+		archive.GeneratedAt,
+		archive.GeneratedBy,
+	)
+
+	if err != nil {
+		return fmt.Errorf("アーカイブ済みレポート登録に失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// GetReportArchive retrieves an archived report by ID
+// IDでアーカイブ済みレポートを取得
+func (s *PostgreSQLStorage) GetReportArchive(ctx context.Context, archiveID string) (*inventory.ReportArchive, error) {
+	query := `SELECT ` + reportArchiveColumns + ` FROM report_archives WHERE id = $1`
+
+	archive, err := scanReportArchive(s.db.QueryRowContext(ctx, query, archiveID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, inventory.ErrReportArchiveNotFound
+		}
+		return nil, fmt.Errorf("アーカイブ済みレポート取得に失敗しました: %w", err)
+	}
+
+	return archive, nil
+}
+
+// ListReportArchives retrieves archived reports, optionally filtered by schedule and report type
+// アーカイブ済みレポート一覧を取得（スケジュール・レポートタイプ指定時は絞り込み）
+func (s *PostgreSQLStorage) ListReportArchives(ctx context.Context, scheduleID string, reportType inventory.ReportType, offset, limit int) ([]inventory.ReportArchive, error) {
+	query := `SELECT ` + reportArchiveColumns + `
+		FROM report_archives
+		WHERE ($1 = '' OR schedule_id = $1) AND ($2 = '' OR report_type = $2)
+		ORDER BY generated_at DESC
+		OFFSET $3 LIMIT $4`
+
+	rows, err := s.db.QueryContext(ctx, query, scheduleID, string(reportType), offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("アーカイブ済みレポート一覧取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	var archives []inventory.ReportArchive
+	for rows.Next() {
+		archive, err := scanReportArchive(rows)
+		if err != nil {
+			return nil, fmt.Errorf("アーカイブ済みレポートスキャンに失敗しました: %w", err)
+		}
+		archives = append(archives, *archive)
+	}
+
+	return archives, nil
+}
+
+// ListStockPositions retrieves on-hand stock with the latest movement and outbound times from the ledger
+// 在庫のある商品・ロケーションの現在庫と台帳上の最終移動・最終出庫日時を取得
+func (s *PostgreSQLStorage) ListStockPositions(ctx context.Context, locationID string) ([]inventory.StockPosition, error) {
+	query := `
+		SELECT st.item_id, st.location_id, st.quantity, i.unit_cost, i.quantity_precision,
+			MAX(le.created_at) AS last_movement_at,
+			MAX(le.created_at) FILTER (WHERE le.type = 'outbound') AS last_outbound_at
+		FROM stocks st
+		JOIN items i ON i.id = st.item_id
+		LEFT JOIN ledger_entries le ON le.item_id = st.item_id AND le.location_id = st.location_id
+		WHERE st.quantity > 0 AND ($1 = '' OR st.location_id = $1)
+		GROUP BY st.item_id, st.location_id, st.quantity, i.unit_cost, i.quantity_precision
+		ORDER BY st.location_id, st.item_id`
+
+	rows, err := s.db.QueryContext(ctx, query, locationID)
+	if err != nil {
+		return nil, fmt.Errorf("在庫状況の取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	var positions []inventory.StockPosition
+	for rows.Next() {
+		var position inventory.StockPosition
+		err := rows.Scan(
+			&position.ItemID,
+			&position.LocationID,
+			&position.Quantity,
+			&position.UnitCost,
+			&position.QuantityPrecision,
+			&position.LastMovementAt,
+			&position.LastOutboundAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("在庫状況スキャンに失敗しました: %w", err)
+		}
+		positions = append(positions, position)
+	}
+
+	return positions, nil
+}
+
+// ListReceiptLayers retrieves the receipts that make up on-hand stock under FIFO, newest first
+// 先入先出で現在庫を構成する受入を新しい順に取得
+//
+// 入庫（移動入庫を含む）と増加調整を受入とし、取消済みの受入は除きます。
+// 新しい受入から累計して現在庫に達するまでの分だけを返します。
+func (s *PostgreSQLStorage) ListReceiptLayers(ctx context.Context, locationID string) ([]inventory.ReceiptLayer, error) {
+	query := `
+		SELECT item_id, location_id, transaction_id, lot_number, quantity, unit_cost, received_at
+		FROM (
+			SELECT t.item_id, t.to_location AS location_id, t.id AS transaction_id, COALESCE(t.lot_number, '') AS lot_number,
+				t.quantity, t.unit_cost, t.created_at AS received_at, st.quantity AS on_hand,
+				SUM(t.quantity) OVER (PARTITION BY t.item_id, t.to_location ORDER BY t.created_at DESC, t.id DESC) AS received_since
+			FROM transactions t
+			JOIN stocks st ON st.item_id = t.item_id AND st.location_id = t.to_location
+			WHERE t.type IN ('inbound', 'adjust') AND t.quantity > 0 AND t.reversed_by IS NULL
+				AND st.quantity > 0 AND ($1 = '' OR t.to_location = $1)
+		) layers
+		WHERE received_since - quantity < on_hand
+		ORDER BY location_id, item_id, received_at DESC, transaction_id DESC`
+
+	rows, err := s.db.QueryContext(ctx, query, locationID)
+	if err != nil {
+		return nil, fmt.Errorf("受入履歴の取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	var layers []inventory.ReceiptLayer
+	for rows.Next() {
+		var layer inventory.ReceiptLayer
+		err := rows.Scan(
+			&layer.ItemID,
+			&layer.LocationID,
+			&layer.TransactionID,
+			&layer.LotNumber,
+			&layer.Quantity,
+			&layer.UnitCost,
+			&layer.ReceivedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("受入履歴スキャンに失敗しました: %w", err)
+		}
+		layers = append(layers, layer)
+	}
+
+	return layers, nil
+}
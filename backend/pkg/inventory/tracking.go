@@ -26,7 +26,7 @@ func NewTrackingManager(storage Storage, logger *zap.Logger) *TrackingManager {
 
 // CreateLot creates a new lot with expiry tracking
 // 有効期限追跡付きの新しいロットを作成
-func (tm *TrackingManager) CreateLot(ctx context.Context, itemID, lotNumber string, quantity int64, unitCost float64, expiryDate *time.Time) (*Lot, error) {
+func (tm *TrackingManager) CreateLot(ctx context.Context, itemID, lotNumber string, quantity int64, unitCost Money, expiryDate *time.Time) (*Lot, error) {
 	// 商品の存在確認
 	if _, err := tm.storage.GetItem(ctx, itemID); err != nil {
 		if err == ErrItemNotFound {
@@ -121,7 +121,7 @@ func (tm *TrackingManager) GetLot(ctx context.Context, lotID string) (*Lot, erro
 
 // TrackInventoryMovement creates a detailed transaction record with lot information
 // ロット情報付きの詳細な在庫移動記録を作成
-func (tm *TrackingManager) TrackInventoryMovement(ctx context.Context, txType TransactionType, itemID string, fromLocation, toLocation *string, quantity int64, reference string, lotNumber *string, unitCost *float64) error {
+func (tm *TrackingManager) TrackInventoryMovement(ctx context.Context, txType TransactionType, itemID string, fromLocation, toLocation *string, quantity int64, reference string, lotNumber *string, unitCost *Money) error {
 	tx := &Transaction{
 		ID:           NewTransactionID(),
 		Type:         txType,
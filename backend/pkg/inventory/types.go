@@ -2,6 +2,11 @@
 package inventory
 
 import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,27 +15,210 @@ import (
 // Item represents a product or SKU in the inventory system
 // 在庫システムにおける商品またはSKUを表現
 type Item struct {
-	ID          string    `json:"id" db:"id"`                   // 商品ID
-	Name        string    `json:"name" db:"name"`               // 商品名
-	SKU         string    `json:"sku" db:"sku"`                 // SKU（在庫管理単位）
-	Description string    `json:"description" db:"description"` // 商品説明
-	Category    string    `json:"category" db:"category"`       // カテゴリ
-	UnitCost    float64   `json:"unit_cost" db:"unit_cost"`     // 単価
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`   // 作成日時
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`   // 更新日時
+	ID                string                 `json:"id" db:"id"`                                   // 商品ID
+	Name              string                 `json:"name" db:"name"`                               // 商品名
+	SKU               string                 `json:"sku" db:"sku"`                                 // SKU（在庫管理単位）
+	Description       string                 `json:"description" db:"description"`                 // 商品説明
+	Category          string                 `json:"category" db:"category"`                       // カテゴリ
+	UnitCost          Money                  `json:"unit_cost" db:"unit_cost"`                     // 単価（単位1あたり）
+	QuantityPrecision int                    `json:"quantity_precision" db:"quantity_precision"`   // 数量の小数桁数（0は整数管理、3ならkgをg単位で保存）
+	ParentItemID      *string                `json:"parent_item_id,omitempty" db:"parent_item_id"` // バリアントの親商品ID（バリアントでない場合はnil）
+	Attributes        map[string]interface{} `json:"attributes,omitempty" db:"attributes"`         // カスタム属性（カテゴリの属性スキーマで型を検証）
+	IsActive          bool                   `json:"is_active" db:"is_active"`                     // 有効フラグ（一覧・検索の絞り込みに使用）
+	ArchivedAt        *time.Time             `json:"archived_at,omitempty" db:"archived_at"`       // アーカイブ日時（削除済み。nilの場合は現役の商品）
+	CreatedAt         time.Time              `json:"created_at" db:"created_at"`                   // 作成日時
+	UpdatedAt         time.Time              `json:"updated_at" db:"updated_at"`                   // 更新日時
+}
+
+// AttributeType represents the value type of a custom item attribute
+// 商品カスタム属性の値の型を表現
+type AttributeType string
+
+const (
+	AttributeTypeString  AttributeType = "string"  // 文字列
+	AttributeTypeNumber  AttributeType = "number"  // 数値
+	AttributeTypeBoolean AttributeType = "boolean" // 真偽値
+	AttributeTypeEnum    AttributeType = "enum"    // 選択肢のいずれかの文字列
+)
+
+// AttributeDefinition defines a custom attribute allowed on items of a category
+// カテゴリの商品に設定できるカスタム属性を定義
+type AttributeDefinition struct {
+	Name     string        `json:"name"`              // 属性名（brand, hazard_class, width_mmなど）
+	Type     AttributeType `json:"type"`              // 値の型
+	Required bool          `json:"required"`          // 必須属性かどうか
+	Options  []string      `json:"options,omitempty"` // enum型の選択肢
+	Unit     string        `json:"unit,omitempty"`    // 数値の単位（mm, kgなど、表示用）
+}
+
+// CategoryAttributeSchema defines the custom attributes of items in a category
+// カテゴリに属する商品のカスタム属性スキーマを定義
+//
+// スキーマが定義されたカテゴリでは、未定義の属性・型の異なる値・必須属性の欠落は商品の検証で拒否されます。
+type CategoryAttributeSchema struct {
+	Category   string                `json:"category" db:"category"`     // カテゴリ
+	Attributes []AttributeDefinition `json:"attributes" db:"attributes"` // 属性定義
+	UpdatedAt  time.Time             `json:"updated_at" db:"updated_at"` // 更新日時
+}
+
+// Attribute returns the definition of the named attribute, or nil if it is not defined
+// 指定名の属性定義を返す（未定義の場合はnil）
+func (s *CategoryAttributeSchema) Attribute(name string) *AttributeDefinition {
+	for i := range s.Attributes {
+		if s.Attributes[i].Name == name {
+			return &s.Attributes[i]
+		}
+	}
+	return nil
+}
+
+// VariantGroup links child SKUs (variants) to a parent item along variant axes such as size and colour
+// サイズ・色などのバリアント軸で子SKU（バリアント）を親商品に紐付けるバリアントグループを表現
+//
+// 子SKUは親商品と同じカテゴリに属し、すべての軸の値をカスタム属性として持ち、軸の値の組み合わせはグループ内で一意です。
+type VariantGroup struct {
+	ParentItemID string    `json:"parent_item_id" db:"parent_item_id"` // 親商品ID
+	Axes         []string  `json:"axes" db:"axes"`                     // バリアント軸となる属性名（size, colourなど）
+	Variants     []Item    `json:"variants,omitempty"`                 // 子SKU（取得時のみ設定）
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`         // 作成日時
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`         // 更新日時
+}
+
+// ItemSearchFilter represents the criteria, ordering and page of an item list or search
+// 商品一覧・検索の条件・並び順・ページを表現
+//
+// 指定された条件はすべてAND結合されます。属性条件は値の文字列表現で一致を判定します（数値10は"10"、真偽値は"true"/"false"）。
+// 一覧ではキーワードを部分一致、検索ではトライグラムによるあいまい一致で判定します。
+type ItemSearchFilter struct {
+	Query           string            `json:"query,omitempty"`            // 商品名・SKU・説明（一覧ではカテゴリも）のキーワード
+	Category        string            `json:"category,omitempty"`         // カテゴリの完全一致
+	ParentItemID    string            `json:"parent_item_id,omitempty"`   // 指定した親商品のバリアントのみ
+	Attributes      map[string]string `json:"attributes,omitempty"`       // カスタム属性の一致条件
+	Active          *bool             `json:"active,omitempty"`           // 有効フラグ（nilの場合は条件なし）
+	MinUnitCost     *Money            `json:"min_unit_cost,omitempty"`    // 単価の下限（以上）
+	MaxUnitCost     *Money            `json:"max_unit_cost,omitempty"`    // 単価の上限（以下）
+	HasStock        *bool             `json:"has_stock,omitempty"`        // 在庫数量が正のロケーションの有無（nilの場合は条件なし）
+	IncludeArchived bool              `json:"include_archived,omitempty"` // アーカイブ済みの商品を含めるか（既定では除外）
+	Sort            ItemSortField     `json:"sort,omitempty"`             // 並び順のキー
+	Desc            bool              `json:"desc,omitempty"`             // 降順かどうか
+	Cursor          string            `json:"cursor,omitempty"`           // 前ページのNextCursor
+	Limit           int               `json:"limit,omitempty"`            // 1ページの件数（ストレージでは0の場合は全件）
+}
+
+// BarcodeType represents the symbology family of an item barcode
+// 商品バーコードの種別を表現
+type BarcodeType string
+
+const (
+	BarcodeTypeJAN  BarcodeType = "JAN"  // JANコード（13桁・短縮8桁）
+	BarcodeTypeEAN  BarcodeType = "EAN"  // EAN-13・EAN-8
+	BarcodeTypeUPC  BarcodeType = "UPC"  // UPC-A（12桁）
+	BarcodeTypeGTIN BarcodeType = "GTIN" // GTIN（集合包装用のGTIN-14を含む）
+)
+
+// PackageLevel represents the packaging level a barcode is printed on
+// バーコードが印字された包装レベルを表現
+type PackageLevel string
+
+const (
+	PackageLevelEach   PackageLevel = "each"   // 単品
+	PackageLevelInner  PackageLevel = "inner"  // 内箱
+	PackageLevelCase   PackageLevel = "case"   // ケース
+	PackageLevelPallet PackageLevel = "pallet" // パレット
+)
+
+// ItemBarcode represents a barcode (JAN/EAN/UPC/GTIN) identifying an item at a packaging level
+// 包装レベルごとに商品を識別するバーコード（JAN/EAN/UPC/GTIN）を表現
+//
+// 1つの商品に単品・ケースなど複数のバーコードを登録でき、GTIN-14に正規化した値で一意です。
+type ItemBarcode struct {
+	GTIN            string       `json:"gtin" db:"gtin"`                           // 14桁に正規化したGTIN（検索キー）
+	Code            string       `json:"code" db:"code"`                           // 登録されたバーコード（元の桁数）
+	ItemID          string       `json:"item_id" db:"item_id"`                     // 商品ID
+	Type            BarcodeType  `json:"type" db:"type"`                           // バーコード種別
+	PackageLevel    PackageLevel `json:"package_level" db:"package_level"`         // 包装レベル
+	UnitsPerPackage int64        `json:"units_per_package" db:"units_per_package"` // 1包装あたりの入数（商品の単位）
+	CreatedAt       time.Time    `json:"created_at" db:"created_at"`               // 登録日時
+}
+
+// ScanResult represents an item resolved from a scanned barcode or GS1 label
+// 読み取ったバーコードまたはGS1ラベルから解決した商品を表現
+type ScanResult struct {
+	Input        string       `json:"input"`                   // 読み取った文字列
+	GTIN         string       `json:"gtin"`                    // 14桁に正規化したGTIN
+	Barcode      *ItemBarcode `json:"barcode"`                 // 一致したバーコード
+	Item         *Item        `json:"item"`                    // 一致した商品
+	LotNumber    string       `json:"lot_number,omitempty"`    // ラベルのロット番号
+	Lot          *Lot         `json:"lot,omitempty"`           // ロット番号が一致する登録済みロット
+	ExpiryDate   *time.Time   `json:"expiry_date,omitempty"`   // ラベルの有効期限
+	SerialNumber string       `json:"serial_number,omitempty"` // ラベルのシリアル番号
+	PackageLevel PackageLevel `json:"package_level"`           // 読み取った包装レベル
+	PackageCount int64        `json:"package_count"`           // 包装数（ラベルの数量、なければ1）
+	Quantity     int64        `json:"quantity"`                // 推奨数量（保存単位: 入数×包装数）
+	GS1          *GS1Data     `json:"gs1,omitempty"`           // GS1ラベルの解析結果
 }
 
 // Location represents a storage location or warehouse
 // 保管場所または倉庫を表現
 type Location struct {
-	ID        string    `json:"id" db:"id"`                 // ロケーションID
-	Name      string    `json:"name" db:"name"`             // ロケーション名
-	Type      string    `json:"type" db:"type"`             // タイプ（倉庫、店舗など）
-	Address   string    `json:"address" db:"address"`       // 住所
-	Capacity  int64     `json:"capacity" db:"capacity"`     // 最大収容量
-	IsActive  bool      `json:"is_active" db:"is_active"`   // アクティブ状態
-	CreatedAt time.Time `json:"created_at" db:"created_at"` // 作成日時
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"` // 更新日時
+	ID         string     `json:"id" db:"id"`                             // ロケーションID
+	Name       string     `json:"name" db:"name"`                         // ロケーション名
+	Type       string     `json:"type" db:"type"`                         // タイプ（倉庫、店舗など）
+	Address    string     `json:"address" db:"address"`                   // 住所
+	Capacity   int64      `json:"capacity" db:"capacity"`                 // 最大収容量
+	IsActive   bool       `json:"is_active" db:"is_active"`               // アクティブ状態
+	PickPath   string     `json:"pick_path" db:"pick_path"`               // ピッキング巡回順のパス（例: A-01-03）
+	ArchivedAt *time.Time `json:"archived_at,omitempty" db:"archived_at"` // アーカイブ日時（削除済み。nilの場合は現役のロケーション）
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`             // 作成日時
+	UpdatedAt  time.Time  `json:"updated_at" db:"updated_at"`             // 更新日時
+}
+
+// MasterUsage summarizes what still references an item or location and blocks archiving it
+// 商品・ロケーションを参照しておりアーカイブを妨げる在庫・引当・未完了の伝票の件数を表現
+type MasterUsage struct {
+	StockQuantity         int64 `json:"stock_quantity"`         // 在庫数量の絶対値の合計（保存単位）
+	ReservedQuantity      int64 `json:"reserved_quantity"`      // 引当数量の合計（保存単位）
+	OpenPurchaseOrders    int   `json:"open_purchase_orders"`   // 未入荷・一部入荷の発注
+	OpenTransferOrders    int   `json:"open_transfer_orders"`   // 出荷待ち・輸送中・一部入荷の移動指示
+	OpenOutboundOrders    int   `json:"open_outbound_orders"`   // 引当待ち・引当済み・欠品待ちの出荷指示
+	PendingCycleCounts    int   `json:"pending_cycle_counts"`   // 棚卸待ち・差異承認待ちの棚卸タスク
+	PendingReplenishments int   `json:"pending_replenishments"` // 承認待ち・承認済みの補充提案
+	ActiveVariants        int   `json:"active_variants"`        // アーカイブされていないバリアント（商品のみ）
+	ActiveKits            int   `json:"active_kits"`            // 部品として使用しているアーカイブされていないキット（商品のみ）
+}
+
+// Blockers lists the reasons the master cannot be archived, empty when it is unused
+// アーカイブできない理由を列挙（使用されていない場合は空）
+func (u MasterUsage) Blockers() []string {
+	var blockers []string
+	if u.StockQuantity != 0 {
+		blockers = append(blockers, fmt.Sprintf("在庫数量: %d", u.StockQuantity))
+	}
+	if u.ReservedQuantity != 0 {
+		blockers = append(blockers, fmt.Sprintf("引当数量: %d", u.ReservedQuantity))
+	}
+	if u.OpenPurchaseOrders > 0 {
+		blockers = append(blockers, fmt.Sprintf("未完了の発注: %d件", u.OpenPurchaseOrders))
+	}
+	if u.OpenTransferOrders > 0 {
+		blockers = append(blockers, fmt.Sprintf("未完了の移動指示: %d件", u.OpenTransferOrders))
+	}
+	if u.OpenOutboundOrders > 0 {
+		blockers = append(blockers, fmt.Sprintf("未完了の出荷指示: %d件", u.OpenOutboundOrders))
+	}
+	if u.PendingCycleCounts > 0 {
+		blockers = append(blockers, fmt.Sprintf("未完了の棚卸タスク: %d件", u.PendingCycleCounts))
+	}
+	if u.PendingReplenishments > 0 {
+		blockers = append(blockers, fmt.Sprintf("未実行の補充提案: %d件", u.PendingReplenishments))
+	}
+	if u.ActiveVariants > 0 {
+		blockers = append(blockers, fmt.Sprintf("バリアント: %d件", u.ActiveVariants))
+	}
+	if u.ActiveKits > 0 {
+		blockers = append(blockers, fmt.Sprintf("使用先キット: %d件", u.ActiveKits))
+	}
+	return blockers
 }
 
 // Stock represents current inventory levels at a location
@@ -46,22 +234,59 @@ type Stock struct {
 	UpdatedBy  string    `json:"updated_by" db:"updated_by"`   // 更新者
 }
 
+// StockSnapshot represents the stock quantity of an item at a location at a point in time
+// 特定時点における商品・ロケーション別の在庫数量を表現
+type StockSnapshot struct {
+	ItemID     string    `json:"item_id" db:"item_id"`         // 商品ID
+	LocationID string    `json:"location_id" db:"location_id"` // ロケーションID
+	Quantity   int64     `json:"quantity" db:"quantity"`       // 在庫数量
+	AsOf       time.Time `json:"as_of" db:"snapshot_at"`       // 基準日時
+}
+
+// StockDrift represents a mismatch between the stocks table and the ledger balance
+// 在庫テーブルの数量と取引台帳から再計算した残高の不一致を表現
+type StockDrift struct {
+	ItemID         string `json:"item_id" db:"item_id"`                 // 商品ID
+	LocationID     string `json:"location_id" db:"location_id"`         // ロケーションID
+	StockQuantity  int64  `json:"stock_quantity" db:"stock_quantity"`   // 在庫テーブル上の数量
+	LedgerQuantity int64  `json:"ledger_quantity" db:"ledger_quantity"` // 台帳から再計算した数量
+	Difference     int64  `json:"difference"`                           // 差異（在庫数量 - 台帳数量）
+	StockVersion   int64  `json:"stock_version" db:"stock_version"`     // 照合時の在庫バージョン（在庫記録なしは0）
+	Fixed          bool   `json:"fixed"`                                // 補正トランザクション記録済みか
+	Error          string `json:"error,omitempty"`                      // 補正に失敗した場合のエラー
+}
+
+// ReconciliationReport represents the result of a ledger reconciliation run
+// 台帳照合の実行結果を表現
+type ReconciliationReport struct {
+	Reference   string       `json:"reference"`    // 補正トランザクションの参照番号
+	GeneratedAt time.Time    `json:"generated_at"` // 照合日時
+	DriftCount  int          `json:"drift_count"`  // 差異件数
+	FixedCount  int          `json:"fixed_count"`  // 補正件数
+	Drifts      []StockDrift `json:"drifts"`       // 差異一覧
+}
+
 // Transaction represents an inventory movement record
 // 在庫移動記録を表現
 type Transaction struct {
-	ID           string            `json:"id" db:"id"`                       // トランザクションID
-	Type         TransactionType   `json:"type" db:"type"`                   // トランザクションタイプ
-	ItemID       string            `json:"item_id" db:"item_id"`             // 商品ID
-	FromLocation *string           `json:"from_location" db:"from_location"` // 移動元ロケーション（nilの場合は入庫）
-	ToLocation   *string           `json:"to_location" db:"to_location"`     // 移動先ロケーション（nilの場合は出庫）
-	Quantity     int64             `json:"quantity" db:"quantity"`           // 数量
-	UnitCost     *float64          `json:"unit_cost" db:"unit_cost"`         // 単価
-	Reference    string            `json:"reference" db:"reference"`         // 参照番号（発注書番号など）
-	LotNumber    *string           `json:"lot_number" db:"lot_number"`       // ロット番号
-	ExpiryDate   *time.Time        `json:"expiry_date" db:"expiry_date"`     // 有効期限
-	Metadata     map[string]string `json:"metadata" db:"metadata"`           // 追加メタデータ
-	CreatedAt    time.Time         `json:"created_at" db:"created_at"`       // 作成日時
-	CreatedBy    string            `json:"created_by" db:"created_by"`       // 作成者
+	ID             string            `json:"id" db:"id"`                                       // トランザクションID
+	Type           TransactionType   `json:"type" db:"type"`                                   // トランザクションタイプ
+	ItemID         string            `json:"item_id" db:"item_id"`                             // 商品ID
+	FromLocation   *string           `json:"from_location" db:"from_location"`                 // 移動元ロケーション（nilの場合は入庫）
+	ToLocation     *string           `json:"to_location" db:"to_location"`                     // 移動先ロケーション（nilの場合は出庫）
+	Quantity       int64             `json:"quantity" db:"quantity"`                           // 数量
+	UnitCost       *Money            `json:"unit_cost" db:"unit_cost"`                         // 単価（基準通貨）
+	Reference      string            `json:"reference" db:"reference"`                         // 参照番号（発注書番号など）
+	LotNumber      *string           `json:"lot_number" db:"lot_number"`                       // ロット番号
+	ExpiryDate     *time.Time        `json:"expiry_date" db:"expiry_date"`                     // 有効期限
+	Metadata       map[string]string `json:"metadata" db:"metadata"`                           // 追加メタデータ
+	CreatedAt      time.Time         `json:"created_at" db:"created_at"`                       // 作成日時
+	CreatedBy      string            `json:"created_by" db:"created_by"`                       // 作成者
+	ReversalOf     *string           `json:"reversal_of,omitempty" db:"reversal_of"`           // 取消対象のトランザクションID
+	ReversedBy     *string           `json:"reversed_by,omitempty" db:"reversed_by"`           // 取消トランザクションID
+	CostCurrency   *string           `json:"cost_currency,omitempty" db:"cost_currency"`       // 仕入通貨（外貨建ての入庫のみ）
+	SourceUnitCost *Money            `json:"source_unit_cost,omitempty" db:"source_unit_cost"` // 仕入通貨建ての単価（UnitCostは基準通貨に換算済み）
+	ExchangeRate   *Rate             `json:"exchange_rate,omitempty" db:"exchange_rate"`       // 換算に使用した為替レート（仕入通貨1単位あたりの基準通貨額）
 }
 
 // TransactionType defines the type of inventory movement
@@ -75,6 +300,10 @@ const (
 	TransactionTypeAdjust   TransactionType = "adjust"   // 調整
 )
 
+// ReconciliationReferencePrefix prefixes the reference of corrective reconciliation entries
+// 台帳照合による補正トランザクションの参照番号プレフィックス
+const ReconciliationReferencePrefix = "RECONCILE-"
+
 // Lot represents a batch of items with the same characteristics
 // 同じ特性を持つ商品のバッチを表現
 type Lot struct {
@@ -82,11 +311,703 @@ type Lot struct {
 	Number     string     `json:"number" db:"number"`           // ロット番号
 	ItemID     string     `json:"item_id" db:"item_id"`         // 商品ID
 	Quantity   int64      `json:"quantity" db:"quantity"`       // 数量
-	UnitCost   float64    `json:"unit_cost" db:"unit_cost"`     // 単価
+	UnitCost   Money      `json:"unit_cost" db:"unit_cost"`     // 単価
 	ExpiryDate *time.Time `json:"expiry_date" db:"expiry_date"` // 有効期限
 	CreatedAt  time.Time  `json:"created_at" db:"created_at"`   // 作成日時
 }
 
+// TransferOrder represents a two-step inter-location transfer (ship, then receive)
+// 出荷と入荷の2段階で行うロケーション間移動指示を表現
+type TransferOrder struct {
+	ID                  string              `json:"id" db:"id"`                                     // 移動指示ID
+	ItemID              string              `json:"item_id" db:"item_id"`                           // 商品ID
+	FromLocation        string              `json:"from_location" db:"from_location"`               // 出荷元ロケーション
+	ToLocation          string              `json:"to_location" db:"to_location"`                   // 入荷先ロケーション
+	Quantity            int64               `json:"quantity" db:"quantity"`                         // 移動数量
+	ReceivedQuantity    int64               `json:"received_quantity" db:"received_quantity"`       // 入荷済み数量
+	DiscrepancyQuantity int64               `json:"discrepancy_quantity" db:"discrepancy_quantity"` // 差異数量（未着・破損など）
+	DiscrepancyReason   string              `json:"discrepancy_reason" db:"discrepancy_reason"`     // 差異理由
+	Status              TransferOrderStatus `json:"status" db:"status"`                             // ステータス
+	Reference           string              `json:"reference" db:"reference"`                       // 参照番号
+	ShippedAt           *time.Time          `json:"shipped_at" db:"shipped_at"`                     // 出荷日時
+	CompletedAt         *time.Time          `json:"completed_at" db:"completed_at"`                 // 完了日時
+	CreatedAt           time.Time           `json:"created_at" db:"created_at"`                     // 作成日時
+	CreatedBy           string              `json:"created_by" db:"created_by"`                     // 作成者
+	UpdatedAt           time.Time           `json:"updated_at" db:"updated_at"`                     // 更新日時
+	Version             int64               `json:"version" db:"version"`                           // 楽観的ロック用バージョン
+}
+
+// TransferOrderStatus defines the lifecycle status of a transfer order
+// 移動指示のステータスを定義
+type TransferOrderStatus string
+
+const (
+	TransferOrderStatusPending           TransferOrderStatus = "pending"            // 出荷待ち
+	TransferOrderStatusInTransit         TransferOrderStatus = "in_transit"         // 輸送中
+	TransferOrderStatusPartiallyReceived TransferOrderStatus = "partially_received" // 一部入荷
+	TransferOrderStatusReceived          TransferOrderStatus = "received"           // 入荷完了
+	TransferOrderStatusClosed            TransferOrderStatus = "closed"             // 差異計上して完了
+)
+
+// Outstanding returns the shipped quantity that is still in transit
+// 輸送中のまま未入荷の数量を返す
+func (o *TransferOrder) Outstanding() int64 {
+	return o.Quantity - o.ReceivedQuantity - o.DiscrepancyQuantity
+}
+
+// Supplier represents a vendor that purchase orders are placed with
+// 発注先となる仕入先を表現
+type Supplier struct {
+	ID           string    `json:"id" db:"id"`                         // 仕入先ID
+	Name         string    `json:"name" db:"name"`                     // 仕入先名
+	ContactName  string    `json:"contact_name" db:"contact_name"`     // 担当者名
+	Email        string    `json:"email" db:"email"`                   // メールアドレス
+	Phone        string    `json:"phone" db:"phone"`                   // 電話番号
+	Address      string    `json:"address" db:"address"`               // 住所
+	LeadTimeDays int       `json:"lead_time_days" db:"lead_time_days"` // 標準リードタイム（日）
+	IsActive     bool      `json:"is_active" db:"is_active"`           // アクティブ状態
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`         // 作成日時
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`         // 更新日時
+}
+
+// PurchaseOrder represents an order placed with a supplier
+// 仕入先への発注を表現
+type PurchaseOrder struct {
+	ID         string              `json:"id" db:"id"`                   // 発注ID
+	SupplierID string              `json:"supplier_id" db:"supplier_id"` // 仕入先ID
+	LocationID string              `json:"location_id" db:"location_id"` // 入荷先ロケーションID
+	Currency   string              `json:"currency" db:"currency"`       // 発注単価の通貨（空の場合は基準通貨）
+	Status     PurchaseOrderStatus `json:"status" db:"status"`           // ステータス
+	Reference  string              `json:"reference" db:"reference"`     // 発注番号
+	OrderDate  time.Time           `json:"order_date" db:"order_date"`   // 発注日
+	Notes      string              `json:"notes" db:"notes"`             // 備考
+	Lines      []PurchaseOrderLine `json:"lines"`                        // 発注明細
+	CreatedAt  time.Time           `json:"created_at" db:"created_at"`   // 作成日時
+	CreatedBy  string              `json:"created_by" db:"created_by"`   // 作成者
+	UpdatedAt  time.Time           `json:"updated_at" db:"updated_at"`   // 更新日時
+	Version    int64               `json:"version" db:"version"`         // 楽観的ロック用バージョン
+}
+
+// PurchaseOrderLine represents an expected receipt of one item on a purchase order
+// 発注明細（商品ごとの入荷予定）を表現
+type PurchaseOrderLine struct {
+	ID               string     `json:"id" db:"id"`                               // 明細ID
+	PurchaseOrderID  string     `json:"purchase_order_id" db:"purchase_order_id"` // 発注ID
+	LineNumber       int        `json:"line_number" db:"line_number"`             // 行番号
+	ItemID           string     `json:"item_id" db:"item_id"`                     // 商品ID
+	Quantity         int64      `json:"quantity" db:"quantity"`                   // 発注数量
+	ReceivedQuantity int64      `json:"received_quantity" db:"received_quantity"` // 入荷済み数量
+	UnitCost         Money      `json:"unit_cost" db:"unit_cost"`                 // 発注単価
+	ExpectedDate     *time.Time `json:"expected_date" db:"expected_date"`         // 入荷予定日
+	OverReceived     int64      `json:"over_received"`                            // 過剰入荷数量
+	UnderReceived    int64      `json:"under_received"`                           // 未入荷数量
+}
+
+// PurchaseOrderStatus defines the lifecycle status of a purchase order
+// 発注のステータスを定義
+type PurchaseOrderStatus string
+
+const (
+	PurchaseOrderStatusOpen              PurchaseOrderStatus = "open"               // 発注済み（未入荷）
+	PurchaseOrderStatusPartiallyReceived PurchaseOrderStatus = "partially_received" // 一部入荷
+	PurchaseOrderStatusReceived          PurchaseOrderStatus = "received"           // 入荷完了
+	PurchaseOrderStatusClosed            PurchaseOrderStatus = "closed"             // 打ち切り（未入荷分は取消）
+)
+
+// IsOpen reports whether the order still has quantities on order
+// 発注残が計上される状態かを判定
+func (s PurchaseOrderStatus) IsOpen() bool {
+	return s == PurchaseOrderStatusOpen || s == PurchaseOrderStatusPartiallyReceived
+}
+
+// PurchaseReceipt represents a quantity received against a purchase order line
+// 発注明細に対する入荷実績を表現
+type PurchaseReceipt struct {
+	LineID     string     `json:"line_id"`     // 明細ID
+	Quantity   int64      `json:"quantity"`    // 入荷数量
+	LotNumber  *string    `json:"lot_number"`  // ロット番号
+	ExpiryDate *time.Time `json:"expiry_date"` // 有効期限
+}
+
+// CalculateReceiptVariance sets the over- and under-received quantities of the line
+// 明細の過剰入荷・未入荷数量を計算
+func (l *PurchaseOrderLine) CalculateReceiptVariance() {
+	l.OverReceived = 0
+	l.UnderReceived = 0
+	if l.ReceivedQuantity > l.Quantity {
+		l.OverReceived = l.ReceivedQuantity - l.Quantity
+	} else {
+		l.UnderReceived = l.Quantity - l.ReceivedQuantity
+	}
+}
+
+// OutboundOrder represents a customer order to be allocated, picked and shipped
+// 引当・ピッキング・出荷を行う出荷指示（受注）を表現
+type OutboundOrder struct {
+	ID          string              `json:"id" db:"id"`                 // 出荷指示ID
+	Customer    string              `json:"customer" db:"customer"`     // 出荷先（顧客）
+	Status      OutboundOrderStatus `json:"status" db:"status"`         // ステータス
+	Reference   string              `json:"reference" db:"reference"`   // 受注番号
+	WaveID      *string             `json:"wave_id" db:"wave_id"`       // ウェーブID
+	Notes       string              `json:"notes" db:"notes"`           // 備考
+	Lines       []OutboundOrderLine `json:"lines"`                      // 出荷指示明細
+	Allocations []PickAllocation    `json:"allocations"`                // 引当明細
+	CreatedAt   time.Time           `json:"created_at" db:"created_at"` // 作成日時
+	CreatedBy   string              `json:"created_by" db:"created_by"` // 作成者
+	UpdatedAt   time.Time           `json:"updated_at" db:"updated_at"` // 更新日時
+	Version     int64               `json:"version" db:"version"`       // 楽観的ロック用バージョン
+}
+
+// clone returns a copy of the order whose lines and allocations can be changed independently
+// 明細・引当を独立して変更できる出荷指示のコピーを返す
+func (o *OutboundOrder) clone() *OutboundOrder {
+	copied := *o
+	copied.Lines = append([]OutboundOrderLine(nil), o.Lines...)
+	copied.Allocations = append([]PickAllocation(nil), o.Allocations...)
+	return &copied
+}
+
+// OutboundOrderLine represents one ordered item on an outbound order
+// 出荷指示明細（商品ごとの出荷数量）を表現
+type OutboundOrderLine struct {
+	ID                  string `json:"id" db:"id"`                                     // 明細ID
+	OrderID             string `json:"order_id" db:"order_id"`                         // 出荷指示ID
+	LineNumber          int    `json:"line_number" db:"line_number"`                   // 行番号
+	ItemID              string `json:"item_id" db:"item_id"`                           // 商品ID
+	Quantity            int64  `json:"quantity" db:"quantity"`                         // 受注数量
+	AllocatedQuantity   int64  `json:"allocated_quantity" db:"allocated_quantity"`     // 引当数量
+	PickedQuantity      int64  `json:"picked_quantity" db:"picked_quantity"`           // 出荷済み数量
+	BackorderedQuantity int64  `json:"backordered_quantity" db:"backordered_quantity"` // バックオーダー数量
+}
+
+// PickAllocation represents stock reserved at one location for an order line
+// 出荷指示明細に対してロケーション別に引当（予約）した在庫を表現
+type PickAllocation struct {
+	ID             string `json:"id" db:"id"`                           // 引当ID
+	OrderID        string `json:"order_id" db:"order_id"`               // 出荷指示ID
+	LineID         string `json:"line_id" db:"line_id"`                 // 明細ID
+	ItemID         string `json:"item_id" db:"item_id"`                 // 商品ID
+	LocationID     string `json:"location_id" db:"location_id"`         // ピッキングロケーション
+	Quantity       int64  `json:"quantity" db:"quantity"`               // 引当数量
+	PickedQuantity int64  `json:"picked_quantity" db:"picked_quantity"` // 実ピッキング数量
+	Confirmed      bool   `json:"confirmed" db:"confirmed"`             // ピッキング確定済み
+}
+
+// OutboundOrderStatus defines the lifecycle status of an outbound order
+// 出荷指示のステータスを定義
+type OutboundOrderStatus string
+
+const (
+	OutboundOrderStatusPending     OutboundOrderStatus = "pending"     // 引当待ち
+	OutboundOrderStatusAllocated   OutboundOrderStatus = "allocated"   // 引当済み（ピッキング待ち）
+	OutboundOrderStatusShipped     OutboundOrderStatus = "shipped"     // 出荷完了
+	OutboundOrderStatusBackordered OutboundOrderStatus = "backordered" // 一部出荷（欠品分は入荷待ち）
+	OutboundOrderStatusCancelled   OutboundOrderStatus = "cancelled"   // キャンセル
+)
+
+// Wave represents a group of outbound orders picked together
+// まとめてピッキングする出荷指示のグループ（ウェーブ）を表現
+type Wave struct {
+	ID        string    `json:"id" db:"id"`                 // ウェーブID
+	Reference string    `json:"reference" db:"reference"`   // 参照番号
+	OrderIDs  []string  `json:"order_ids"`                  // 対象の出荷指示ID
+	CreatedAt time.Time `json:"created_at" db:"created_at"` // 作成日時
+	CreatedBy string    `json:"created_by" db:"created_by"` // 作成者
+}
+
+// PickList represents the picking instructions for an order or a wave
+// 出荷指示またはウェーブのピッキングリストを表現
+type PickList struct {
+	OrderID     string          `json:"order_id,omitempty"` // 出荷指示ID
+	WaveID      string          `json:"wave_id,omitempty"`  // ウェーブID
+	GeneratedAt time.Time       `json:"generated_at"`       // 作成日時
+	Entries     []PickListEntry `json:"entries"`            // ピッキング指示
+}
+
+// PickListEntry represents one pick at one location
+// ロケーション単位のピッキング指示を表現
+type PickListEntry struct {
+	AllocationID string `json:"allocation_id"` // 引当ID
+	OrderID      string `json:"order_id"`      // 出荷指示ID
+	LineID       string `json:"line_id"`       // 明細ID
+	ItemID       string `json:"item_id"`       // 商品ID
+	LocationID   string `json:"location_id"`   // ロケーションID
+	PickPath     string `json:"pick_path"`     // 巡回パス
+	Quantity     int64  `json:"quantity"`      // ピッキング数量
+}
+
+// PickConfirmation represents the quantity actually picked for an allocation
+// 引当に対する実ピッキング数量を表現
+type PickConfirmation struct {
+	AllocationID   string `json:"allocation_id"`   // 引当ID
+	PickedQuantity int64  `json:"picked_quantity"` // 実ピッキング数量
+}
+
+// SortPickListEntries orders entries along the warehouse pick path
+// ピッキング指示を巡回順（ロケーションのパス順）に並べ替え
+func SortPickListEntries(entries []PickListEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].PickPath != entries[j].PickPath {
+			return entries[i].PickPath < entries[j].PickPath
+		}
+		if entries[i].LocationID != entries[j].LocationID {
+			return entries[i].LocationID < entries[j].LocationID
+		}
+		return entries[i].ItemID < entries[j].ItemID
+	})
+}
+
+// BillOfMaterials represents the components consumed to assemble one unit of a kit item
+// キット商品1個の組立に必要な構成部品（部品表）を表現
+type BillOfMaterials struct {
+	ItemID     string         `json:"item_id"`    // キット商品ID
+	Components []BOMComponent `json:"components"` // 構成部品
+	UpdatedAt  time.Time      `json:"updated_at"` // 更新日時
+}
+
+// BOMComponent represents one component item and its quantity per kit
+// 構成部品とキット1個あたりの数量を表現
+type BOMComponent struct {
+	ItemID   string `json:"item_id" db:"component_item_id"` // 部品の商品ID
+	Quantity int64  `json:"quantity" db:"quantity"`         // キット1個あたりの数量
+}
+
+// ReplenishmentPolicy defines the min/max stock levels of an item at a store and where it is replenished from
+// 店舗ロケーションにおける商品の最小・最大在庫と補充元ロケーションを定義
+type ReplenishmentPolicy struct {
+	LocationID       string    `json:"location_id" db:"location_id"`               // 補充先ロケーションID
+	ItemID           string    `json:"item_id" db:"item_id"`                       // 商品ID
+	SourceLocationID string    `json:"source_location_id" db:"source_location_id"` // 補充元ロケーションID
+	MinQuantity      int64     `json:"min_quantity" db:"min_quantity"`             // 最小在庫（これを下回ると補充）
+	MaxQuantity      int64     `json:"max_quantity" db:"max_quantity"`             // 最大在庫（補充後の目標数量）
+	IsActive         bool      `json:"is_active" db:"is_active"`                   // 有効フラグ
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`                 // 作成日時
+	UpdatedAt        time.Time `json:"updated_at" db:"updated_at"`                 // 更新日時
+}
+
+// ReplenishmentProposal represents a proposed transfer that brings a store back up to its max level
+// 店舗在庫を最大在庫まで戻すための移動提案を表現
+type ReplenishmentProposal struct {
+	ID               string                      `json:"id" db:"id"`                                 // 提案ID
+	LocationID       string                      `json:"location_id" db:"location_id"`               // 補充先ロケーションID
+	SourceLocationID string                      `json:"source_location_id" db:"source_location_id"` // 補充元ロケーションID
+	ItemID           string                      `json:"item_id" db:"item_id"`                       // 商品ID
+	Status           ReplenishmentProposalStatus `json:"status" db:"status"`                         // ステータス
+	CurrentQuantity  int64                       `json:"current_quantity" db:"current_quantity"`     // 提案時の補充先在庫
+	MinQuantity      int64                       `json:"min_quantity" db:"min_quantity"`             // 提案時の最小在庫
+	MaxQuantity      int64                       `json:"max_quantity" db:"max_quantity"`             // 提案時の最大在庫
+	RequiredQuantity int64                       `json:"required_quantity" db:"required_quantity"`   // 最大在庫までの不足数量
+	SourceAvailable  int64                       `json:"source_available" db:"source_available"`     // 提案時の補充元の利用可能数量
+	Quantity         int64                       `json:"quantity" db:"quantity"`                     // 移動数量（補充元の利用可能数量が上限）
+	BatchID          string                      `json:"batch_id,omitempty" db:"batch_id"`           // 実行したバッチID
+	Error            string                      `json:"error,omitempty" db:"error"`                 // 実行失敗時のエラー
+	CreatedAt        time.Time                   `json:"created_at" db:"created_at"`                 // 作成日時
+	CreatedBy        string                      `json:"created_by" db:"created_by"`                 // 作成者
+	ReviewedBy       string                      `json:"reviewed_by,omitempty" db:"reviewed_by"`     // 承認・却下者
+	ReviewedAt       *time.Time                  `json:"reviewed_at,omitempty" db:"reviewed_at"`     // 承認・却下日時
+	ExecutedAt       *time.Time                  `json:"executed_at,omitempty" db:"executed_at"`     // 実行日時
+}
+
+// ReplenishmentProposalStatus defines the lifecycle status of a replenishment proposal
+// 補充提案のステータスを定義
+type ReplenishmentProposalStatus string
+
+const (
+	ReplenishmentProposalStatusPending   ReplenishmentProposalStatus = "pending"   // 承認待ち
+	ReplenishmentProposalStatusApproved  ReplenishmentProposalStatus = "approved"  // 承認済み（未実行）
+	ReplenishmentProposalStatusExecuting ReplenishmentProposalStatus = "executing" // 移動実行中
+	ReplenishmentProposalStatusRejected  ReplenishmentProposalStatus = "rejected"  // 却下
+	ReplenishmentProposalStatusExecuted  ReplenishmentProposalStatus = "executed"  // 移動実行済み
+	ReplenishmentProposalStatusFailed    ReplenishmentProposalStatus = "failed"    // 移動実行失敗
+)
+
+// IsOpen reports whether the proposal still reserves source quantity for a future transfer
+// 今後の移動のために補充元の数量を見込んでいる状態かを判定
+func (s ReplenishmentProposalStatus) IsOpen() bool {
+	return s == ReplenishmentProposalStatusPending || s == ReplenishmentProposalStatusApproved || s == ReplenishmentProposalStatusExecuting
+}
+
+// ItemClassification represents the ABC/XYZ class of an item at a location from one classification run
+// 1回の分類実行におけるロケーション別商品のABC/XYZ区分を表現
+type ItemClassification struct {
+	ItemID               string    `json:"item_id" db:"item_id"`                             // 商品ID
+	LocationID           string    `json:"location_id" db:"location_id"`                     // ロケーションID
+	ABCClass             string    `json:"abc_class" db:"abc_class"`                         // ABC区分（出庫金額）
+	XYZClass             string    `json:"xyz_class" db:"xyz_class"`                         // XYZ区分（需要変動）
+	ConsumptionValue     Money     `json:"consumption_value" db:"consumption_value"`         // 集計期間の出庫金額
+	ConsumptionQuantity  int64     `json:"consumption_quantity" db:"consumption_quantity"`   // 集計期間の出庫数量
+	CumulativePercent    float64   `json:"cumulative_percent" db:"cumulative_percent"`       // 出庫金額の累積構成比（%）
+	VariationCoefficient float64   `json:"variation_coefficient" db:"variation_coefficient"` // 期間別出庫数量の変動係数
+	WindowDays           int       `json:"window_days" db:"window_days"`                     // 集計期間（日）
+	ClassifiedAt         time.Time `json:"classified_at" db:"classified_at"`                 // 分類日時
+}
+
+// LedgerEntry represents one stock movement of the ledger at a location
+// ロケーション単位の台帳の入出庫明細を表現
+type LedgerEntry struct {
+	TransactionID string          `json:"transaction_id" db:"transaction_id"` // トランザクションID
+	Type          TransactionType `json:"type" db:"type"`                     // トランザクションタイプ
+	ItemID        string          `json:"item_id" db:"item_id"`               // 商品ID
+	LocationID    string          `json:"location_id" db:"location_id"`       // ロケーションID
+	Delta         int64           `json:"delta" db:"delta"`                   // 増減数量
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`         // 計上日時
+	Consumption   bool            `json:"consumption" db:"consumption"`       // 出庫実績（消費）として集計するか（移動レッグ・取消は除く）
+}
+
+// ItemKPI represents turnover and availability KPIs of an item at a location over a period
+// 期間中のロケーション別商品の回転率・在庫日数・欠品などのKPIを表現
+type ItemKPI struct {
+	ItemID             string   `json:"item_id"`             // 商品ID
+	LocationID         string   `json:"location_id"`         // ロケーションID
+	OpeningQuantity    int64    `json:"opening_quantity"`    // 期首在庫
+	ClosingQuantity    int64    `json:"closing_quantity"`    // 期末在庫
+	AverageInventory   float64  `json:"average_inventory"`   // 平均在庫（台帳から再構築した時間加重平均）
+	OutboundQuantity   int64    `json:"outbound_quantity"`   // 期間中の出庫数量
+	TurnoverRate       float64  `json:"turnover_rate"`       // 期間回転率（出庫数量 / 平均在庫）
+	AnnualizedTurnover float64  `json:"annualized_turnover"` // 年換算回転率
+	DaysOfSupply       *float64 `json:"days_of_supply"`      // 在庫日数（期末在庫 / 1日平均出庫、出庫がない場合はnull）
+	StockoutDays       int      `json:"stockout_days"`       // 在庫が0以下になった日数
+	FillRate           *float64 `json:"fill_rate"`           // 充足率（出荷指示の出荷数量 / 受注数量、商品単位）
+}
+
+// KPIReport represents the KPIs of the requested items and locations with a summary
+// 対象の商品・ロケーションのKPIと集計値を表現
+type KPIReport struct {
+	ItemID     string     `json:"item_id,omitempty"`     // 商品ID（指定時）
+	LocationID string     `json:"location_id,omitempty"` // ロケーションID（指定時）
+	From       time.Time  `json:"from"`                  // 集計開始日時
+	To         time.Time  `json:"to"`                    // 集計終了日時
+	Items      []ItemKPI  `json:"items"`                 // 商品・ロケーション別KPI
+	Summary    KPISummary `json:"summary"`               // 集計値
+}
+
+// KPISummary represents KPIs aggregated over all rows of a report
+// レポート全体で集計したKPIを表現
+type KPISummary struct {
+	ItemCount        int      `json:"item_count"`        // 対象の商品・ロケーション数
+	OutboundQuantity int64    `json:"outbound_quantity"` // 出庫数量合計
+	AverageInventory float64  `json:"average_inventory"` // 平均在庫合計
+	TurnoverRate     float64  `json:"turnover_rate"`     // 全体の期間回転率
+	StockoutItems    int      `json:"stockout_items"`    // 期間中に欠品した商品・ロケーション数
+	FillRate         *float64 `json:"fill_rate"`         // 全体の充足率
+}
+
+// ReportFilter narrows the rows of an inventory report
+// 在庫レポートの対象行の絞り込み条件を表現
+type ReportFilter struct {
+	LocationID string    `json:"location_id"` // ロケーションID（空の場合は全ロケーション）
+	Category   string    `json:"category"`    // 商品カテゴリ（空の場合は全カテゴリ）
+	From       time.Time `json:"from"`        // 集計開始日時（この時点を含まない）
+	To         time.Time `json:"to"`          // 集計終了日時（この時点を含む）
+}
+
+// StockMovementSummary represents the ledger movements of an item at a location over a period
+// 期間中のロケーション別商品の期首・入出庫・調整・期末数量を表現
+type StockMovementSummary struct {
+	ItemID             string `json:"item_id"`             // 商品ID
+	LocationID         string `json:"location_id"`         // ロケーションID
+	OpeningQuantity    int64  `json:"opening_quantity"`    // 期首数量
+	InboundQuantity    int64  `json:"inbound_quantity"`    // 入庫数量
+	OutboundQuantity   int64  `json:"outbound_quantity"`   // 出庫数量
+	AdjustmentQuantity int64  `json:"adjustment_quantity"` // 調整数量（増減の純額）
+	ClosingQuantity    int64  `json:"closing_quantity"`    // 期末数量
+}
+
+// StockPosition represents the on-hand quantity of an item at a location with its latest movements
+// ロケーション別商品の現在庫と最終移動日時を表現
+type StockPosition struct {
+	ItemID            string     `json:"item_id"`            // 商品ID
+	LocationID        string     `json:"location_id"`        // ロケーションID
+	Quantity          int64      `json:"quantity"`           // 在庫数量
+	UnitCost          Money      `json:"unit_cost"`          // 商品マスタの単価
+	QuantityPrecision int        `json:"quantity_precision"` // 商品の数量の小数桁数
+	LastMovementAt    *time.Time `json:"last_movement_at"`   // 最終移動日時（入庫・出庫・調整）
+	LastOutboundAt    *time.Time `json:"last_outbound_at"`   // 最終出庫日時
+}
+
+// ReceiptLayer represents a receipt that still makes up part of the on-hand quantity under FIFO
+// 先入先出で現在庫を構成している受入（原価層）を表現
+type ReceiptLayer struct {
+	ItemID        string    `json:"item_id"`        // 商品ID
+	LocationID    string    `json:"location_id"`    // ロケーションID
+	TransactionID string    `json:"transaction_id"` // 受入トランザクションID
+	LotNumber     string    `json:"lot_number"`     // ロット番号
+	Quantity      int64     `json:"quantity"`       // 受入数量
+	UnitCost      *Money    `json:"unit_cost"`      // 受入単価（未設定の場合は商品マスタの単価）
+	ReceivedAt    time.Time `json:"received_at"`    // 受入日時
+}
+
+// AgingBucket represents the on-hand quantity and value whose receipt age falls in a range of days
+// 受入からの経過日数が範囲内にある在庫の数量と金額を表現
+type AgingBucket struct {
+	Label    string `json:"label"`    // 区分名（例：0-30）
+	MinDays  int    `json:"min_days"` // 経過日数の下限
+	MaxDays  *int   `json:"max_days"` // 経過日数の上限（nilの場合は上限なし）
+	Quantity int64  `json:"quantity"` // 数量
+	Value    Money  `json:"value"`    // 金額
+}
+
+// ItemAging represents the receipt age profile and dead-stock status of an item at a location
+// ロケーション別商品の受入経過日数の分布と滞留在庫判定を表現
+type ItemAging struct {
+	ItemID            string        `json:"item_id"`             // 商品ID
+	LocationID        string        `json:"location_id"`         // ロケーションID
+	Quantity          int64         `json:"quantity"`            // 在庫数量（商品の保存単位）
+	QuantityPrecision int           `json:"quantity_precision"`  // 商品の数量の小数桁数
+	Value             Money         `json:"value"`               // 在庫金額（受入単価による）
+	Buckets           []AgingBucket `json:"buckets"`             // 経過日数区分ごとの数量・金額
+	AverageAgeDays    float64       `json:"average_age_days"`    // 数量加重平均の経過日数
+	OldestReceiptAt   *time.Time    `json:"oldest_receipt_at"`   // 現在庫を構成する最も古い受入日時
+	LastMovementAt    *time.Time    `json:"last_movement_at"`    // 最終移動日時
+	DaysSinceMovement *int          `json:"days_since_movement"` // 最終移動からの経過日数（移動履歴がない場合はnil）
+	IsDeadStock       bool          `json:"is_dead_stock"`       // 滞留在庫（一定期間移動なし）
+}
+
+// LocationAging summarizes the aging buckets and the value at risk of dead stock at a location
+// ロケーションごとの経過日数区分の合計と滞留在庫の金額を集計
+type LocationAging struct {
+	LocationID        string        `json:"location_id"`         // ロケーションID
+	Quantity          int64         `json:"quantity"`            // 在庫数量
+	Value             Money         `json:"value"`               // 在庫金額
+	Buckets           []AgingBucket `json:"buckets"`             // 経過日数区分ごとの数量・金額
+	DeadStockItems    int           `json:"dead_stock_items"`    // 滞留在庫の商品数
+	DeadStockQuantity int64         `json:"dead_stock_quantity"` // 滞留在庫の数量
+	ValueAtRisk       Money         `json:"value_at_risk"`       // 滞留在庫の金額
+}
+
+// AgingReport represents the inventory aging and dead-stock report of one or all locations
+// 在庫エイジング・滞留在庫レポートを表現
+type AgingReport struct {
+	LocationID    string          `json:"location_id,omitempty"` // ロケーションID（空の場合は全ロケーション）
+	AsOf          time.Time       `json:"as_of"`                 // 基準日時
+	DeadStockDays int             `json:"dead_stock_days"`       // 滞留在庫とみなす移動なし日数
+	Items         []ItemAging     `json:"items"`                 // 商品・ロケーション別の明細
+	Locations     []LocationAging `json:"locations"`             // ロケーション別の集計
+	TotalValue    Money           `json:"total_value"`           // 在庫金額の合計
+	ValueAtRisk   Money           `json:"value_at_risk"`         // 滞留在庫の金額の合計
+}
+
+// FiscalPeriod represents a fiscal period whose postings are locked once it is closed
+// 締め後は計上がロックされる会計期間を表現
+type FiscalPeriod struct {
+	ID        string             `json:"id" db:"id"`                 // 会計期間ID
+	Name      string             `json:"name" db:"name"`             // 期間名（例：2026年9月）
+	StartDate time.Time          `json:"start_date" db:"start_date"` // 開始日
+	EndDate   time.Time          `json:"end_date" db:"end_date"`     // 終了日（当日を含む）
+	Status    FiscalPeriodStatus `json:"status" db:"status"`         // ステータス
+	ClosedAt  *time.Time         `json:"closed_at" db:"closed_at"`   // 締め日時
+	ClosedBy  string             `json:"closed_by" db:"closed_by"`   // 締め実行者
+	CreatedAt time.Time          `json:"created_at" db:"created_at"` // 作成日時
+	CreatedBy string             `json:"created_by" db:"created_by"` // 作成者
+}
+
+// FiscalPeriodStatus defines the status of a fiscal period
+// 会計期間のステータスを定義
+type FiscalPeriodStatus string
+
+const (
+	FiscalPeriodStatusOpen   FiscalPeriodStatus = "open"   // 未締め
+	FiscalPeriodStatusClosed FiscalPeriodStatus = "closed" // 締め済み
+)
+
+// PeriodEnd returns the first instant after the fiscal period (exclusive end)
+// 会計期間の終了直後の日時（この日時を含まない）を返す
+func (p *FiscalPeriod) PeriodEnd() time.Time {
+	return p.EndDate.AddDate(0, 0, 1)
+}
+
+// PeriodEndBalance represents the quantity and value of an item at a location at the end of a closed period
+// 締め済み会計期間末における商品・ロケーション別の数量と金額を表現
+type PeriodEndBalance struct {
+	PeriodID   string `json:"period_id" db:"period_id"`     // 会計期間ID
+	ItemID     string `json:"item_id" db:"item_id"`         // 商品ID
+	LocationID string `json:"location_id" db:"location_id"` // ロケーションID
+	Quantity   int64  `json:"quantity" db:"quantity"`       // 期末数量
+	UnitCost   Money  `json:"unit_cost" db:"unit_cost"`     // 締め時点の単価
+	Value      Money  `json:"value" db:"value"`             // 期末金額
+}
+
+// FiscalPeriodEvent records a close or reopen of a fiscal period for auditing
+// 会計期間の締め・締め解除を監査のために記録
+type FiscalPeriodEvent struct {
+	ID           string             `json:"id" db:"id"`                       // イベントID
+	PeriodID     string             `json:"period_id" db:"period_id"`         // 会計期間ID
+	Action       FiscalPeriodAction `json:"action" db:"action"`               // 操作
+	Reason       string             `json:"reason" db:"reason"`               // 理由（締め解除時は必須）
+	BalanceCount int64              `json:"balance_count" db:"balance_count"` // 締め時に保存した期末残高の件数
+	PerformedAt  time.Time          `json:"performed_at" db:"performed_at"`   // 実行日時
+	PerformedBy  string             `json:"performed_by" db:"performed_by"`   // 実行者
+}
+
+// FiscalPeriodAction defines the audited operations on a fiscal period
+// 監査対象の会計期間操作を定義
+type FiscalPeriodAction string
+
+const (
+	FiscalPeriodActionClose  FiscalPeriodAction = "close"  // 締め
+	FiscalPeriodActionReopen FiscalPeriodAction = "reopen" // 締め解除
+)
+
+// ExchangeRate represents the rate converting a foreign currency into the base currency from an effective date
+// 適用開始日から有効な外貨から基準通貨への為替レートを表現
+type ExchangeRate struct {
+	ID            string    `json:"id" db:"id"`                         // 為替レートID
+	Currency      string    `json:"currency" db:"currency"`             // 換算元の通貨（ISO 4217）
+	BaseCurrency  string    `json:"base_currency" db:"base_currency"`   // 換算先の基準通貨
+	Rate          Rate      `json:"rate" db:"rate"`                     // 換算元通貨1単位あたりの基準通貨額
+	EffectiveDate time.Time `json:"effective_date" db:"effective_date"` // 適用開始日（次のレートの適用開始日の前日まで有効）
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`         // 登録日時
+	CreatedBy     string    `json:"created_by" db:"created_by"`         // 登録者
+}
+
+// ReportSchedule defines a report generated periodically by a cron expression and stored in the report archive
+// cron式で定期生成しレポートアーカイブに保存するレポートを定義
+type ReportSchedule struct {
+	ID             string       `json:"id" db:"id"`                           // スケジュールID
+	Name           string       `json:"name" db:"name"`                       // スケジュール名
+	CronExpression string       `json:"cron_expression" db:"cron_expression"` // cron式（分 時 日 月 曜日、日にLで月末）
+	Timezone       string       `json:"timezone" db:"timezone"`               // cron式を評価するタイムゾーン（空の場合はサーバーのタイムゾーン）
+	ReportType     ReportType   `json:"report_type" db:"report_type"`         // レポートタイプ
+	Format         ReportFormat `json:"format" db:"format"`                   // 出力形式
+	LocationID     string       `json:"location_id" db:"location_id"`         // ロケーションID（空の場合は全ロケーション）
+	Category       string       `json:"category" db:"category"`               // 商品カテゴリ（空の場合は全カテゴリ）
+	PeriodDays     int          `json:"period_days" db:"period_days"`         // 集計日数（0の場合は前回実行以降）
+	IsActive       bool         `json:"is_active" db:"is_active"`             // 有効フラグ
+	NextRunAt      *time.Time   `json:"next_run_at" db:"next_run_at"`         // 次回実行予定日時
+	LastRunAt      *time.Time   `json:"last_run_at" db:"last_run_at"`         // 前回実行予定日時
+	LastError      string       `json:"last_error,omitempty" db:"last_error"` // 前回実行時のエラー
+	CreatedAt      time.Time    `json:"created_at" db:"created_at"`           // 作成日時
+	CreatedBy      string       `json:"created_by" db:"created_by"`           // 作成者
+	UpdatedAt      time.Time    `json:"updated_at" db:"updated_at"`           // 更新日時
+}
+
+// ReportArchive represents a generated report file kept in the report archive
+// レポートアーカイブに保存された生成済みレポートファイルを表現
+type ReportArchive struct {
+	ID          string       `json:"id" db:"id"`                             // アーカイブID
+	ScheduleID  string       `json:"schedule_id,omitempty" db:"schedule_id"` // 生成元スケジュールID
+	ReportType  ReportType   `json:"report_type" db:"report_type"`           // レポートタイプ
+	Format      ReportFormat `json:"format" db:"format"`                     // 出力形式
+	LocationID  string       `json:"location_id" db:"location_id"`           // ロケーションID（空の場合は全ロケーション）
+	Category    string       `json:"category" db:"category"`                 // 商品カテゴリ（空の場合は全カテゴリ）
+	PeriodFrom  time.Time    `json:"period_from" db:"period_from"`           // 集計開始日時
+	PeriodTo    time.Time    `json:"period_to" db:"period_to"`               // 集計終了日時
+	FileName    string       `json:"file_name" db:"file_name"`               // ダウンロード時のファイル名
+	FilePath    string       `json:"-" db:"file_path"`                       // アーカイブディレクトリからの相対パス
+	SizeBytes   int64        `json:"size_bytes" db:"size_bytes"`             // ファイルサイズ
+	Checksum    string       `json:"checksum" db:"checksum"`                 // SHA-256チェックサム
+	GeneratedAt time.Time    `json:"generated_at" db:"generated_at"`         // 生成日時
+	GeneratedBy string       `json:"generated_by" db:"generated_by"`         // 生成者
+}
+
+// ForecastMethod defines demand forecasting methods
+// 需要予測の手法を定義
+type ForecastMethod string
+
+const (
+	ForecastMethodMovingAverage        ForecastMethod = "moving_average"        // 移動平均
+	ForecastMethodExponentialSmoothing ForecastMethod = "exponential_smoothing" // 単純指数平滑
+	ForecastMethodHoltWinters          ForecastMethod = "holt_winters"          // Holt-Winters（加法型季節性）
+)
+
+// DemandForecast represents the daily demand forecast of an item at a location
+// ロケーション別商品の日次需要予測を表現
+type DemandForecast struct {
+	ItemID              string         `json:"item_id"`               // 商品ID
+	LocationID          string         `json:"location_id"`           // ロケーションID
+	Method              ForecastMethod `json:"method"`                // 実際に使用した予測手法（履歴不足時は単純指数平滑に切り替え）
+	HistoryDays         int            `json:"history_days"`          // 予測に用いた出庫履歴の日数
+	AverageDailyDemand  float64        `json:"average_daily_demand"`  // 履歴期間の1日平均出庫数量
+	ForecastDailyDemand float64        `json:"forecast_daily_demand"` // 予測期間の1日平均需要
+	DemandStdDev        float64        `json:"demand_std_dev"`        // 1日あたり予測誤差の標準偏差
+	Forecast            []float64      `json:"forecast"`              // 翌日以降の日次需要予測
+	GeneratedAt         time.Time      `json:"generated_at"`          // 予測日時
+}
+
+// ReorderSuggestion represents the reorder point, safety stock and order quantity suggested for an item
+// 商品の発注点・安全在庫・推奨発注数量を表現
+type ReorderSuggestion struct {
+	ItemID              string         `json:"item_id"`               // 商品ID
+	LocationID          string         `json:"location_id"`           // ロケーションID
+	Method              ForecastMethod `json:"method"`                // 使用した予測手法
+	OnHand              int64          `json:"on_hand"`               // 現在庫数量
+	Reserved            int64          `json:"reserved"`              // 予約済み数量
+	OnOrder             int64          `json:"on_order"`              // 発注残数量（商品単位）
+	InventoryPosition   int64          `json:"inventory_position"`    // 在庫ポジション（現在庫 - 予約 + 発注残）
+	ForecastDailyDemand float64        `json:"forecast_daily_demand"` // リードタイム中の1日平均需要予測
+	DemandStdDev        float64        `json:"demand_std_dev"`        // 1日あたり予測誤差の標準偏差
+	LeadTimeDays        int            `json:"lead_time_days"`        // 調達リードタイム（日）
+	LeadTimeDemand      float64        `json:"lead_time_demand"`      // リードタイム中の需要予測
+	SafetyStock         float64        `json:"safety_stock"`          // 安全在庫
+	ReorderPoint        float64        `json:"reorder_point"`         // 発注点（リードタイム需要 + 安全在庫）
+	EOQ                 float64        `json:"eoq"`                   // 経済的発注量
+	NeedsOrder          bool           `json:"needs_order"`           // 在庫ポジションが発注点以下か
+	SuggestedQuantity   int64          `json:"suggested_quantity"`    // 推奨発注数量
+}
+
+// ReorderReport represents the items of a location that need ordering
+// ロケーションで発注が必要な商品の一覧を表現
+type ReorderReport struct {
+	LocationID   string              `json:"location_id"`   // ロケーションID
+	Method       ForecastMethod      `json:"method"`        // 予測手法
+	ServiceLevel float64             `json:"service_level"` // 安全在庫の目標サービス率
+	GeneratedAt  time.Time           `json:"generated_at"`  // 作成日時
+	Items        []ReorderSuggestion `json:"items"`         // 発注が必要な商品（不足数量の大きい順）
+}
+
+// CycleCountTask represents one scheduled count of an item at a location
+// ロケーション・商品単位の循環棚卸タスクを表現
+type CycleCountTask struct {
+	ID               string           `json:"id" db:"id"`                               // タスクID
+	LocationID       string           `json:"location_id" db:"location_id"`             // ロケーションID
+	ItemID           string           `json:"item_id" db:"item_id"`                     // 商品ID
+	ABCClass         string           `json:"abc_class" db:"abc_class"`                 // 計画時のABC区分
+	Status           CycleCountStatus `json:"status" db:"status"`                       // ステータス
+	ScheduledDate    time.Time        `json:"scheduled_date" db:"scheduled_date"`       // 棚卸予定日
+	ExpectedQuantity int64            `json:"expected_quantity" db:"expected_quantity"` // 帳簿数量（実棚記録時点）
+	CountedQuantity  *int64           `json:"counted_quantity" db:"counted_quantity"`   // 実棚数量
+	Variance         int64            `json:"variance" db:"variance"`                   // 差異数量（実棚 - 帳簿）
+	VariancePercent  float64          `json:"variance_percent" db:"variance_percent"`   // 差異率（%）
+	CountedBy        string           `json:"counted_by" db:"counted_by"`               // 棚卸担当者
+	CountedAt        *time.Time       `json:"counted_at" db:"counted_at"`               // 実棚記録日時
+	ReviewedBy       string           `json:"reviewed_by" db:"reviewed_by"`             // 承認者
+	ReviewedAt       *time.Time       `json:"reviewed_at" db:"reviewed_at"`             // 承認日時
+	ReviewNote       string           `json:"review_note" db:"review_note"`             // 承認コメント
+	CreatedAt        time.Time        `json:"created_at" db:"created_at"`               // 作成日時
+}
+
+// CycleCountStatus defines the lifecycle status of a cycle count task
+// 循環棚卸タスクのステータスを定義
+type CycleCountStatus string
+
+const (
+	CycleCountStatusPending     CycleCountStatus = "pending"     // 棚卸待ち
+	CycleCountStatusApproved    CycleCountStatus = "approved"    // 承認済み（差異は在庫に反映）
+	CycleCountStatusDiscrepancy CycleCountStatus = "discrepancy" // 許容差異超過（承認待ち）
+	CycleCountStatusRejected    CycleCountStatus = "rejected"    // 却下（帳簿数量を維持し再棚卸）
+)
+
+// CycleCountClassPolicy defines the count frequency and variance tolerance of one ABC class
+// ABC区分ごとの棚卸頻度と許容差異率を定義
+type CycleCountClassPolicy struct {
+	IntervalDays     int     `json:"interval_days"`     // 棚卸間隔（日）
+	TolerancePercent float64 `json:"tolerance_percent"` // 許容差異率（%）
+}
+
+// CycleCountPolicy maps ABC classes ("A", "B", "C") to their count policy
+// ABC区分から棚卸ポリシーへの対応を表現
+type CycleCountPolicy map[string]CycleCountClassPolicy
+
+// DefaultCycleCountPolicy returns the default policy: A monthly, B quarterly, C yearly
+// デフォルトの棚卸ポリシー（A:毎月、B:四半期、C:年次）を返す
+func DefaultCycleCountPolicy() CycleCountPolicy {
+	return CycleCountPolicy{
+		"A": {IntervalDays: 30, TolerancePercent: 2},
+		"B": {IntervalDays: 90, TolerancePercent: 5},
+		"C": {IntervalDays: 365, TolerancePercent: 10},
+	}
+}
+
 // StockAlert represents low stock or other inventory alerts
 // 低在庫やその他の在庫アラートを表現
 type StockAlert struct {
@@ -143,10 +1064,12 @@ type InventoryOperation struct {
 type OperationType string
 
 const (
-	OperationTypeAdd      OperationType = "add"      // 追加
-	OperationTypeRemove   OperationType = "remove"   // 削除
-	OperationTypeTransfer OperationType = "transfer" // 移動
-	OperationTypeAdjust   OperationType = "adjust"   // 調整
+	OperationTypeAdd         OperationType = "add"         // 追加
+	OperationTypeRemove      OperationType = "remove"      // 削除
+	OperationTypeTransfer    OperationType = "transfer"    // 移動
+	OperationTypeAdjust      OperationType = "adjust"      // 調整
+	OperationTypeAssemble    OperationType = "assemble"    // キット組立
+	OperationTypeDisassemble OperationType = "disassemble" // キット分解
 )
 
 // BatchStatus defines the status of a batch operation
@@ -178,6 +1101,37 @@ func NewBatchID() string {
 	return uuid.New().String()
 }
 
+// NewReconciliationReference generates the reference used for corrective reconciliation entries
+// 台帳照合の補正トランザクション用参照番号を生成
+func NewReconciliationReference(at time.Time) string {
+	return ReconciliationReferencePrefix + at.Format("20060102T150405")
+}
+
+// WriteCSV writes the drift report as CSV
+// 差異レポートをCSV形式で出力
+func (r *ReconciliationReport) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"商品ID", "ロケーションID", "在庫数量", "台帳数量", "差異", "補正済み", "エラー"}); err != nil {
+		return err
+	}
+	for _, drift := range r.Drifts {
+		record := []string{
+			drift.ItemID,
+			drift.LocationID,
+			strconv.FormatInt(drift.StockQuantity, 10),
+			strconv.FormatInt(drift.LedgerQuantity, 10),
+			strconv.FormatInt(drift.Difference, 10),
+			strconv.FormatBool(drift.Fixed),
+			drift.Error,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
 // Calculate available quantity (total - reserved)
 // 利用可能数量を計算（総数量 - 予約済み数量）
 func (s *Stock) CalculateAvailable() {
@@ -1,8 +1,10 @@
 package inventory
 
 import (
+	"encoding/json"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 	"unicode"
 )
@@ -23,13 +25,27 @@ func ValidateItemID(itemID string) error {
 	return nil
 }
 
-// ValidateQuantity 数量をバリデーション
+// ValidateQuantity 数量をバリデーション（整数管理の商品）
 func ValidateQuantity(quantity int64, allowNegative bool) error {
+	return ValidateItemQuantity(quantity, 0, allowNegative)
+}
+
+// ValidateItemQuantity 商品の小数桁数に応じた保存単位の数量をバリデーション
+func ValidateItemQuantity(quantity int64, precision int, allowNegative bool) error {
 	if !allowNegative && quantity < 0 {
-		return NewValidationError("quantity", "負の数量は許可されていません", fmt.Sprintf("%d", quantity))
+		return NewValidationError("quantity", "負の数量は許可されていません", FormatQuantity(quantity, precision))
+	}
+	limit := maxQuantityUnits * QuantityScale(precision)
+	if quantity < -limit || quantity > limit {
+		return NewValidationError("quantity", "数量が有効範囲を超えています", FormatQuantity(quantity, precision))
 	}
-	if quantity < -999999999 || quantity > 999999999 {
-		return NewValidationError("quantity", "数量が有効範囲を超えています", fmt.Sprintf("%d", quantity))
+	return nil
+}
+
+// ValidateQuantityPrecision 数量の小数桁数をバリデーション
+func ValidateQuantityPrecision(precision int) error {
+	if precision < 0 || precision > MaxQuantityPrecision {
+		return NewValidationError("quantity_precision", fmt.Sprintf("数量の小数桁数は0〜%d桁である必要があります", MaxQuantityPrecision), fmt.Sprintf("%d", precision))
 	}
 	return nil
 }
@@ -137,13 +153,16 @@ func ValidateLotNumber(lotNumber string) error {
 	return nil
 }
 
+// maxUnitCost 単価の上限（999999.9999）
+const maxUnitCost Money = 999999*moneyScale + 9999
+
 // ValidateUnitCost 単価をバリデーション
-func ValidateUnitCost(unitCost float64) error {
+func ValidateUnitCost(unitCost Money) error {
 	if unitCost < 0 {
-		return NewValidationError("unit_cost", "単価は0以上である必要があります", fmt.Sprintf("%.2f", unitCost))
+		return NewValidationError("unit_cost", "単価は0以上である必要があります", unitCost.String())
 	}
-	if unitCost > 999999.9999 {
-		return NewValidationError("unit_cost", "単価が有効範囲を超えています", fmt.Sprintf("%.2f", unitCost))
+	if unitCost > maxUnitCost {
+		return NewValidationError("unit_cost", "単価が有効範囲を超えています", unitCost.String())
 	}
 	return nil
 }
@@ -223,10 +242,12 @@ func ValidateAlertType(alertType AlertType) error {
 // ValidateOperationType オペレーション種別をバリデーション
 func ValidateOperationType(operationType OperationType) error {
 	validTypes := map[OperationType]bool{
-		OperationTypeAdd:      true,
-		OperationTypeRemove:   true,
-		OperationTypeTransfer: true,
-		OperationTypeAdjust:   true,
+		OperationTypeAdd:         true,
+		OperationTypeRemove:      true,
+		OperationTypeTransfer:    true,
+		OperationTypeAdjust:      true,
+		OperationTypeAssemble:    true,
+		OperationTypeDisassemble: true,
 	}
 
 	if !validTypes[operationType] {
@@ -236,7 +257,9 @@ func ValidateOperationType(operationType OperationType) error {
 }
 
 // ValidateItem 商品全体をバリデーション
-func ValidateItem(item *Item) error {
+//
+// schemaには商品カテゴリの属性スキーマを指定します（スキーマが未定義の場合はnil）。
+func ValidateItem(item *Item, schema *CategoryAttributeSchema) error {
 	if item == nil {
 		return NewValidationError("item", "商品が指定されていません", "nil")
 	}
@@ -259,7 +282,254 @@ func ValidateItem(item *Item) error {
 	if err := ValidateUnitCost(item.UnitCost); err != nil {
 		return err
 	}
+	if err := ValidateQuantityPrecision(item.QuantityPrecision); err != nil {
+		return err
+	}
+	if item.ParentItemID != nil {
+		if err := ValidateItemID(*item.ParentItemID); err != nil {
+			return err
+		}
+		if *item.ParentItemID == item.ID {
+			return NewValidationError("parent_item_id", "商品自身を親商品に指定できません", *item.ParentItemID)
+		}
+	}
+	if err := ValidateItemAttributes(item.Attributes, schema); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ValidateAttributeName カスタム属性名の形式をバリデーション
+func ValidateAttributeName(name string) error {
+	if name == "" {
+		return NewValidationError("attributes", "属性名が空です", name)
+	}
+	if len(name) > 64 {
+		return NewValidationError("attributes", "属性名が長すぎます", name)
+	}
+	// 英字で始まる英数字とアンダースコアのみ許可
+	validPattern := regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*$`)
+	if !validPattern.MatchString(name) {
+		return NewValidationError("attributes", "属性名に無効な文字が含まれています", name)
+	}
+	return nil
+}
 
+// attributeValueType カスタム属性値の型を判定（スカラー以外はfalse）
+func attributeValueType(value interface{}) (AttributeType, bool) {
+	switch value.(type) {
+	case string:
+		return AttributeTypeString, true
+	case bool:
+		return AttributeTypeBoolean, true
+	case float64, float32, int, int32, int64, json.Number:
+		return AttributeTypeNumber, true
+	}
+	return "", false
+}
+
+// ValidateItemAttributes 商品のカスタム属性をバリデーション
+//
+// スキーマがnilの場合は属性名の形式と値がスカラー（文字列・数値・真偽値）であることのみ検証します。
+// スキーマがある場合は未定義の属性、型の異なる値、enumの選択肢外の値、必須属性の欠落を拒否します。
+func ValidateItemAttributes(attributes map[string]interface{}, schema *CategoryAttributeSchema) error {
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := ValidateAttributeName(name); err != nil {
+			return err
+		}
+		value := attributes[name]
+		valueType, ok := attributeValueType(value)
+		if !ok {
+			return NewValidationError("attributes", "属性値は文字列・数値・真偽値のいずれかである必要があります", fmt.Sprintf("%s=%v", name, value))
+		}
+		if text, isString := value.(string); isString && len(text) > 1000 {
+			return NewValidationError("attributes", "属性値が長すぎます", name)
+		}
+		if schema == nil {
+			continue
+		}
+
+		def := schema.Attribute(name)
+		if def == nil {
+			return NewValidationError("attributes", "カテゴリの属性スキーマに定義されていない属性です", name)
+		}
+		expected := def.Type
+		if expected == AttributeTypeEnum {
+			expected = AttributeTypeString
+		}
+		if valueType != expected {
+			return NewValidationError("attributes", fmt.Sprintf("属性%sは%s型である必要があります", name, def.Type), fmt.Sprintf("%v", value))
+		}
+		if def.Type == AttributeTypeEnum && !containsString(def.Options, value.(string)) {
+			return NewValidationError("attributes", fmt.Sprintf("属性%sの値が選択肢にありません", name), value.(string))
+		}
+	}
+
+	if schema != nil {
+		for _, def := range schema.Attributes {
+			if _, ok := attributes[def.Name]; def.Required && !ok {
+				return NewValidationError("attributes", "必須属性が指定されていません", def.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// containsString スライスに文字列が含まれるかを判定
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateAttributeSchema カテゴリ属性スキーマ全体をバリデーション
+func ValidateAttributeSchema(schema *CategoryAttributeSchema) error {
+	if schema == nil {
+		return NewValidationError("attribute_schema", "属性スキーマが指定されていません", "nil")
+	}
+	if strings.TrimSpace(schema.Category) == "" {
+		return NewValidationError("category", "カテゴリが空です", schema.Category)
+	}
+	if err := ValidateCategory(schema.Category); err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool, len(schema.Attributes))
+	for _, def := range schema.Attributes {
+		if err := ValidateAttributeName(def.Name); err != nil {
+			return err
+		}
+		if seen[def.Name] {
+			return NewValidationError("attributes", "属性名が重複しています", def.Name)
+		}
+		seen[def.Name] = true
+
+		switch def.Type {
+		case AttributeTypeString, AttributeTypeNumber, AttributeTypeBoolean:
+			if len(def.Options) > 0 {
+				return NewValidationError("options", "選択肢はenum型の属性にのみ指定できます", def.Name)
+			}
+		case AttributeTypeEnum:
+			if len(def.Options) == 0 {
+				return NewValidationError("options", "enum型の属性には選択肢が必要です", def.Name)
+			}
+			options := make(map[string]bool, len(def.Options))
+			for _, option := range def.Options {
+				if strings.TrimSpace(option) == "" || options[option] {
+					return NewValidationError("options", "選択肢が空または重複しています", fmt.Sprintf("%s=%s", def.Name, option))
+				}
+				options[option] = true
+			}
+		default:
+			return NewValidationError("type", "無効な属性の型です", string(def.Type))
+		}
+	}
+	return nil
+}
+
+// ValidateVariantGroup バリアントグループ全体をバリデーション
+func ValidateVariantGroup(group *VariantGroup) error {
+	if group == nil {
+		return NewValidationError("variant_group", "バリアントグループが指定されていません", "nil")
+	}
+	if err := ValidateItemID(group.ParentItemID); err != nil {
+		return err
+	}
+	if len(group.Axes) == 0 {
+		return NewValidationError("axes", "バリアント軸が指定されていません", "")
+	}
+
+	seen := make(map[string]bool, len(group.Axes))
+	for _, axis := range group.Axes {
+		if err := ValidateAttributeName(axis); err != nil {
+			return err
+		}
+		if seen[axis] {
+			return NewValidationError("axes", "バリアント軸が重複しています", axis)
+		}
+		seen[axis] = true
+	}
+	return nil
+}
+
+// ValidateGTIN バーコード（GTIN-8/12/13/14）の桁数とチェックデジットをバリデーション
+func ValidateGTIN(code string) error {
+	if !isDigits(code) {
+		return NewValidationError("code", "バーコードは数字のみ指定できます", code)
+	}
+	switch len(code) {
+	case 8, 12, 13, 14:
+	default:
+		return NewValidationError("code", "バーコードの桁数は8・12・13・14桁のいずれかである必要があります", code)
+	}
+
+	// モジュラス10ウェイト3-1（右端のチェックデジットを除き、右から奇数桁に3を掛ける）
+	sum := 0
+	for i := len(code) - 2; i >= 0; i-- {
+		digit := int(code[i] - '0')
+		if (len(code)-2-i)%2 == 0 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	if check := (10 - sum%10) % 10; check != int(code[len(code)-1]-'0') {
+		return NewValidationError("code", "バーコードのチェックデジットが一致しません", code)
+	}
+	return nil
+}
+
+// ValidateItemBarcode 商品バーコード全体をバリデーション
+func ValidateItemBarcode(barcode *ItemBarcode) error {
+	if barcode == nil {
+		return NewValidationError("barcode", "バーコードが指定されていません", "nil")
+	}
+	if err := ValidateItemID(barcode.ItemID); err != nil {
+		return err
+	}
+	if err := ValidateGTIN(barcode.Code); err != nil {
+		return err
+	}
+
+	lengths := map[BarcodeType][]int{
+		BarcodeTypeJAN:  {8, 13},
+		BarcodeTypeEAN:  {8, 13},
+		BarcodeTypeUPC:  {12},
+		BarcodeTypeGTIN: {8, 12, 13, 14},
+	}
+	allowed, ok := lengths[barcode.Type]
+	if !ok {
+		return NewValidationError("type", "無効なバーコード種別です", string(barcode.Type))
+	}
+	validLength := false
+	for _, length := range allowed {
+		validLength = validLength || len(barcode.Code) == length
+	}
+	if !validLength {
+		return NewValidationError("code", fmt.Sprintf("%sの桁数が不正です", barcode.Type), barcode.Code)
+	}
+
+	switch barcode.PackageLevel {
+	case PackageLevelEach:
+		if barcode.UnitsPerPackage != 1 {
+			return NewValidationError("units_per_package", "単品バーコードの入数は1である必要があります", fmt.Sprintf("%d", barcode.UnitsPerPackage))
+		}
+	case PackageLevelInner, PackageLevelCase, PackageLevelPallet:
+		if barcode.UnitsPerPackage <= 0 {
+			return NewValidationError("units_per_package", "入数は正の値である必要があります", fmt.Sprintf("%d", barcode.UnitsPerPackage))
+		}
+	default:
+		return NewValidationError("package_level", "無効な包装レベルです", string(barcode.PackageLevel))
+	}
 	return nil
 }
 
@@ -412,6 +682,139 @@ func ValidateStockAlert(alert *StockAlert) error {
 	return nil
 }
 
+// ValidateSupplier 仕入先全体をバリデーション
+func ValidateSupplier(supplier *Supplier) error {
+	if supplier == nil {
+		return NewValidationError("supplier", "仕入先が指定されていません", "nil")
+	}
+
+	if strings.TrimSpace(supplier.Name) == "" {
+		return NewValidationError("name", "仕入先名が空です", supplier.Name)
+	}
+	if len(supplier.Name) > 500 {
+		return NewValidationError("name", "仕入先名が長すぎます", supplier.Name)
+	}
+	if supplier.Email != "" && !IsValidEmail(supplier.Email) {
+		return NewValidationError("email", "メールアドレスの形式が無効です", supplier.Email)
+	}
+	if supplier.LeadTimeDays < 0 {
+		return NewValidationError("lead_time_days", "リードタイムは0以上である必要があります", fmt.Sprintf("%d", supplier.LeadTimeDays))
+	}
+
+	return nil
+}
+
+// ValidatePurchaseOrder 発注全体をバリデーション
+func ValidatePurchaseOrder(order *PurchaseOrder) error {
+	if order == nil {
+		return NewValidationError("purchase_order", "発注が指定されていません", "nil")
+	}
+
+	if order.SupplierID == "" {
+		return NewValidationError("supplier_id", "仕入先IDが空です", order.SupplierID)
+	}
+	// 入荷先ロケーションは省略可能（省略時は商品の補充元ロケーションを使用）
+	if order.LocationID != "" {
+		if err := ValidateLocationID(order.LocationID); err != nil {
+			return err
+		}
+	}
+	if len(order.Lines) == 0 {
+		return NewValidationError("lines", "発注明細がありません", "0")
+	}
+	if err := ValidateReference(order.Reference); err != nil {
+		return err
+	}
+	if order.Currency != "" {
+		if err := ValidateCurrency(order.Currency); err != nil {
+			return err
+		}
+	}
+
+	for _, line := range order.Lines {
+		if err := ValidateItemID(line.ItemID); err != nil {
+			return err
+		}
+		if line.Quantity <= 0 {
+			return NewValidationError("quantity", "数量は正の値である必要があります", fmt.Sprintf("%d", line.Quantity))
+		}
+		if err := ValidateUnitCost(line.UnitCost); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ValidateCurrency 通貨コードが対応通貨かをバリデーション
+func ValidateCurrency(code string) error {
+	if _, ok := LookupCurrencyRule(code); !ok {
+		return NewValidationError("currency", "未対応の通貨です", code)
+	}
+	return nil
+}
+
+// ValidateOutboundOrder 出荷指示全体をバリデーション
+func ValidateOutboundOrder(order *OutboundOrder) error {
+	if order == nil {
+		return NewValidationError("outbound_order", "出荷指示が指定されていません", "nil")
+	}
+
+	if strings.TrimSpace(order.Customer) == "" {
+		return NewValidationError("customer", "出荷先が空です", order.Customer)
+	}
+	if len(order.Lines) == 0 {
+		return NewValidationError("lines", "出荷指示明細がありません", "0")
+	}
+	if err := ValidateReference(order.Reference); err != nil {
+		return err
+	}
+
+	for _, line := range order.Lines {
+		if err := ValidateItemID(line.ItemID); err != nil {
+			return err
+		}
+		if line.Quantity <= 0 {
+			return NewValidationError("quantity", "数量は正の値である必要があります", fmt.Sprintf("%d", line.Quantity))
+		}
+	}
+
+	return nil
+}
+
+// ValidateBillOfMaterials 部品表全体をバリデーション
+func ValidateBillOfMaterials(bom *BillOfMaterials) error {
+	if bom == nil {
+		return NewValidationError("bom", "部品表が指定されていません", "nil")
+	}
+
+	if err := ValidateItemID(bom.ItemID); err != nil {
+		return err
+	}
+	if len(bom.Components) == 0 {
+		return NewValidationError("components", "構成部品がありません", "0")
+	}
+
+	seen := make(map[string]bool, len(bom.Components))
+	for _, component := range bom.Components {
+		if err := ValidateItemID(component.ItemID); err != nil {
+			return err
+		}
+		if component.ItemID == bom.ItemID {
+			return NewValidationError("components", "キット自身を構成部品にはできません", component.ItemID)
+		}
+		if seen[component.ItemID] {
+			return NewValidationError("components", "構成部品が重複しています", component.ItemID)
+		}
+		seen[component.ItemID] = true
+		if component.Quantity <= 0 {
+			return NewValidationError("quantity", "数量は正の値である必要があります", fmt.Sprintf("%d", component.Quantity))
+		}
+	}
+
+	return nil
+}
+
 // IsASCII 文字列がASCII文字のみかをチェック
 func IsASCII(s string) bool {
 	for _, r := range s {
@@ -1,8 +1,10 @@
 package inventory
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"math"
 	"sort"
 	"time"
 
@@ -12,22 +14,41 @@ import (
 // ValuationEngineImpl implements the ValuationEngine interface
 // ValuationEngineインターフェースの実装
 type ValuationEngineImpl struct {
-	storage Storage
-	logger  *zap.Logger
+	storage  Storage
+	logger   *zap.Logger
+	currency CurrencyRule
 }
 
 // NewValuationEngine creates a new valuation engine
 // 新しい在庫評価エンジンを作成
 func NewValuationEngine(storage Storage, logger *zap.Logger) *ValuationEngineImpl {
 	return &ValuationEngineImpl{
-		storage: storage,
-		logger:  logger,
+		storage:  storage,
+		logger:   logger,
+		currency: currencyRules[DefaultCurrency],
 	}
 }
 
+// SetCurrency sets the currency whose rounding rule is applied to reported values
+// 評価額の表示に適用する通貨の丸めルールを設定
+func (v *ValuationEngineImpl) SetCurrency(code string) error {
+	rule, ok := LookupCurrencyRule(code)
+	if !ok {
+		return NewValidationError("currency", "未対応の通貨です", code)
+	}
+	v.currency = rule
+	return nil
+}
+
+// Currency returns the currency rule applied to reported values
+// 評価額の表示に適用する通貨の丸めルールを返す
+func (v *ValuationEngineImpl) Currency() CurrencyRule {
+	return v.currency
+}
+
 // CalculateValue calculates inventory value using specified method
 // 指定された方法で在庫価値を計算
-func (v *ValuationEngineImpl) CalculateValue(ctx context.Context, itemID, locationID string, method ValuationMethod) (float64, error) {
+func (v *ValuationEngineImpl) CalculateValue(ctx context.Context, itemID, locationID string, method ValuationMethod) (Money, error) {
 	// 現在の在庫を取得
 	stock, err := v.storage.GetStock(ctx, itemID, locationID)
 	if err != nil {
@@ -38,16 +59,22 @@ func (v *ValuationEngineImpl) CalculateValue(ctx context.Context, itemID, locati
 		return 0, nil
 	}
 
+	// 単価は単位あたりのため、保存単位の数量の換算に商品の小数桁数を使用
+	item, err := v.storage.GetItem(ctx, itemID)
+	if err != nil {
+		return 0, NewStorageError("get_item", "商品取得に失敗しました", err)
+	}
+
 	// 評価方法に応じて計算
 	switch method {
 	case ValuationMethodFIFO:
-		return v.calculateFIFO(ctx, itemID, locationID, stock.Quantity)
+		return v.calculateFIFO(ctx, item, locationID, stock.Quantity)
 	case ValuationMethodLIFO:
-		return v.calculateLIFO(ctx, itemID, locationID, stock.Quantity)
+		return v.calculateLIFO(ctx, item, locationID, stock.Quantity)
 	case ValuationMethodAverage:
-		return v.calculateAverage(ctx, itemID, locationID, stock.Quantity)
+		return v.calculateAverage(ctx, item, stock.Quantity)
 	case ValuationMethodStandard:
-		return v.calculateStandard(ctx, itemID, stock.Quantity)
+		return v.calculateStandard(item, stock.Quantity)
 	default:
 		return 0, fmt.Errorf("未対応の評価方法です: %s", method)
 	}
@@ -55,14 +82,14 @@ func (v *ValuationEngineImpl) CalculateValue(ctx context.Context, itemID, locati
 
 // CalculateTotalValue calculates total inventory value for a location
 // ロケーションの総在庫価値を計算
-func (v *ValuationEngineImpl) CalculateTotalValue(ctx context.Context, locationID string, method ValuationMethod) (float64, error) {
+func (v *ValuationEngineImpl) CalculateTotalValue(ctx context.Context, locationID string, method ValuationMethod) (Money, error) {
 	// ロケーションの全在庫を取得
 	stocks, err := v.storage.ListStockByLocation(ctx, locationID)
 	if err != nil {
 		return 0, NewStorageError("list_stock_by_location", "ロケーション在庫取得に失敗しました", err)
 	}
 
-	totalValue := 0.0
+	var totalValue Money
 	for _, stock := range stocks {
 		if stock.Quantity > 0 {
 			value, err := v.CalculateValue(ctx, stock.ItemID, locationID, method)
@@ -83,19 +110,29 @@ func (v *ValuationEngineImpl) CalculateTotalValue(ctx context.Context, locationI
 
 // GetAverageCost calculates average cost for an item
 // 商品の平均原価を計算
-func (v *ValuationEngineImpl) GetAverageCost(ctx context.Context, itemID string) (float64, error) {
+func (v *ValuationEngineImpl) GetAverageCost(ctx context.Context, itemID string) (Money, error) {
+	item, err := v.storage.GetItem(ctx, itemID)
+	if err != nil {
+		return 0, NewStorageError("get_item", "商品取得に失敗しました", err)
+	}
+	return v.averageCost(ctx, itemID, item.QuantityPrecision)
+}
+
+// averageCost calculates the inbound-weighted average cost per whole unit of an item
+// 入庫数量で加重した単位あたりの平均原価を計算
+func (v *ValuationEngineImpl) averageCost(ctx context.Context, itemID string, precision int) (Money, error) {
 	// 入庫トランザクションから平均原価を計算
 	transactions, err := v.storage.GetTransactionHistory(ctx, itemID, 1000)
 	if err != nil {
 		return 0, NewStorageError("get_transaction_history", "トランザクション履歴取得に失敗しました", err)
 	}
 
-	totalCost := 0.0
+	var totalCost Money
 	totalQuantity := int64(0)
 
 	for _, tx := range transactions {
 		if tx.Type == TransactionTypeInbound && tx.UnitCost != nil && *tx.UnitCost > 0 {
-			totalCost += *tx.UnitCost * float64(tx.Quantity)
+			totalCost += tx.UnitCost.MulQuantity(tx.Quantity, precision)
 			totalQuantity += tx.Quantity
 		}
 	}
@@ -104,14 +141,15 @@ func (v *ValuationEngineImpl) GetAverageCost(ctx context.Context, itemID string)
 		return 0, fmt.Errorf("平均原価計算用のデータが不足しています")
 	}
 
-	return totalCost / float64(totalQuantity), nil
+	// 合計金額 ÷ 合計数量（単位換算）
+	return totalCost.MulDiv(QuantityScale(precision), totalQuantity), nil
 }
 
 // calculateFIFO calculates inventory value using FIFO method
 // FIFO法で在庫価値を計算
-func (v *ValuationEngineImpl) calculateFIFO(ctx context.Context, itemID, locationID string, quantity int64) (float64, error) {
+func (v *ValuationEngineImpl) calculateFIFO(ctx context.Context, item *Item, locationID string, quantity int64) (Money, error) {
 	// 入庫トランザクションを古い順に取得
-	transactions, err := v.getInboundTransactions(ctx, itemID, locationID)
+	transactions, err := v.getInboundTransactions(ctx, item.ID, locationID)
 	if err != nil {
 		return 0, err
 	}
@@ -121,14 +159,14 @@ func (v *ValuationEngineImpl) calculateFIFO(ctx context.Context, itemID, locatio
 		return transactions[i].CreatedAt.Before(transactions[j].CreatedAt)
 	})
 
-	return v.calculateValueFromTransactions(transactions, quantity), nil
+	return v.calculateValueFromTransactions(transactions, quantity, item.QuantityPrecision), nil
 }
 
 // calculateLIFO calculates inventory value using LIFO method
 // LIFO法で在庫価値を計算
-func (v *ValuationEngineImpl) calculateLIFO(ctx context.Context, itemID, locationID string, quantity int64) (float64, error) {
+func (v *ValuationEngineImpl) calculateLIFO(ctx context.Context, item *Item, locationID string, quantity int64) (Money, error) {
 	// 入庫トランザクションを新しい順に取得
-	transactions, err := v.getInboundTransactions(ctx, itemID, locationID)
+	transactions, err := v.getInboundTransactions(ctx, item.ID, locationID)
 	if err != nil {
 		return 0, err
 	}
@@ -138,34 +176,29 @@ func (v *ValuationEngineImpl) calculateLIFO(ctx context.Context, itemID, locatio
 		return transactions[i].CreatedAt.After(transactions[j].CreatedAt)
 	})
 
-	return v.calculateValueFromTransactions(transactions, quantity), nil
+	return v.calculateValueFromTransactions(transactions, quantity, item.QuantityPrecision), nil
 }
 
 // calculateAverage calculates inventory value using weighted average method
 // 加重平均法で在庫価値を計算
-func (v *ValuationEngineImpl) calculateAverage(ctx context.Context, itemID string, _ string, quantity int64) (float64, error) {
-	averageCost, err := v.GetAverageCost(ctx, itemID)
+func (v *ValuationEngineImpl) calculateAverage(ctx context.Context, item *Item, quantity int64) (Money, error) {
+	averageCost, err := v.averageCost(ctx, item.ID, item.QuantityPrecision)
 	if err != nil {
 		return 0, err
 	}
 
-	return averageCost * float64(quantity), nil
+	return averageCost.MulQuantity(quantity, item.QuantityPrecision), nil
 }
 
 // calculateStandard calculates inventory value using standard cost method
 // 標準原価法で在庫価値を計算
-func (v *ValuationEngineImpl) calculateStandard(ctx context.Context, itemID string, quantity int64) (float64, error) {
-	// 商品の標準原価を取得
-	item, err := v.storage.GetItem(ctx, itemID)
-	if err != nil {
-		return 0, NewStorageError("get_item", "商品取得に失敗しました", err)
-	}
-
+func (v *ValuationEngineImpl) calculateStandard(item *Item, quantity int64) (Money, error) {
+	// 商品の標準原価を使用
 	if item.UnitCost <= 0 {
 		return 0, fmt.Errorf("商品に標準原価が設定されていません")
 	}
 
-	return item.UnitCost * float64(quantity), nil
+	return item.UnitCost.MulQuantity(quantity, item.QuantityPrecision), nil
 }
 
 // getInboundTransactions gets inbound transactions for an item at a location
@@ -193,8 +226,8 @@ func (v *ValuationEngineImpl) getInboundTransactions(ctx context.Context, itemID
 
 // calculateValueFromTransactions calculates value from sorted transactions
 // ソートされたトランザクションから価値を計算
-func (v *ValuationEngineImpl) calculateValueFromTransactions(transactions []Transaction, quantity int64) float64 {
-	totalValue := 0.0
+func (v *ValuationEngineImpl) calculateValueFromTransactions(transactions []Transaction, quantity int64, precision int) Money {
+	var totalValue Money
 	remainingQty := quantity
 
 	for _, tx := range transactions {
@@ -212,7 +245,7 @@ func (v *ValuationEngineImpl) calculateValueFromTransactions(transactions []Tran
 			useQty = remainingQty
 		}
 
-		totalValue += *tx.UnitCost * float64(useQty)
+		totalValue += tx.UnitCost.MulQuantity(useQty, precision)
 		remainingQty -= useQty
 	}
 
@@ -222,204 +255,508 @@ func (v *ValuationEngineImpl) calculateValueFromTransactions(transactions []Tran
 // AnalyticsEngineImpl implements the AnalyticsEngine interface
 // AnalyticsEngineインターフェースの実装
 type AnalyticsEngineImpl struct {
-	storage Storage
-	logger  *zap.Logger
+	storage        Storage
+	logger         *zap.Logger
+	classification ClassificationConfig
+	aging          AgingConfig
+}
+
+// ClassificationConfig holds the parameters of ABC/XYZ classification
+// ABC/XYZ分析のパラメータを保持
+type ClassificationConfig struct {
+	WindowDays     int     `yaml:"window_days"`      // 出庫実績の集計期間（日）
+	ACutoffPercent float64 `yaml:"a_cutoff_percent"` // A区分とする出庫金額の累積構成比の上限（%）
+	BCutoffPercent float64 `yaml:"b_cutoff_percent"` // B区分とする出庫金額の累積構成比の上限（%）
+	BucketDays     int     `yaml:"bucket_days"`      // 需要変動を測る集計単位（日）
+	XMaxVariation  float64 `yaml:"x_max_variation"`  // X区分とする変動係数の上限
+	YMaxVariation  float64 `yaml:"y_max_variation"`  // Y区分とする変動係数の上限
+}
+
+// DefaultClassificationConfig returns a 1-year window, 80/95% cut-offs and weekly demand buckets
+// デフォルトの分析パラメータ（集計期間1年、80/95%、週単位の需要変動）を返す
+func DefaultClassificationConfig() ClassificationConfig {
+	return ClassificationConfig{
+		WindowDays:     365,
+		ACutoffPercent: 80,
+		BCutoffPercent: 95,
+		BucketDays:     7,
+		XMaxVariation:  0.5,
+		YMaxVariation:  1.0,
+	}
 }
 
 // NewAnalyticsEngine creates a new analytics engine
 // 新しい分析エンジンを作成
 func NewAnalyticsEngine(storage Storage, logger *zap.Logger) *AnalyticsEngineImpl {
 	return &AnalyticsEngineImpl{
-		storage: storage,
-		logger:  logger,
+		storage:        storage,
+		logger:         logger,
+		classification: DefaultClassificationConfig(),
+		aging:          DefaultAgingConfig(),
 	}
 }
 
-// CalculateABCClassification performs ABC analysis on inventory
-// 在庫のABC分析を実行
+// SetClassificationConfig sets the window and cut-offs used for ABC/XYZ classification
+// ABC/XYZ分析の集計期間と区分の閾値を設定
+func (a *AnalyticsEngineImpl) SetClassificationConfig(config ClassificationConfig) {
+	a.classification = config
+}
+
+// CalculateABCClassification performs ABC analysis on outbound consumption value
+// 出庫金額に基づく在庫のABC分析を実行
 func (a *AnalyticsEngineImpl) CalculateABCClassification(ctx context.Context, locationID string) (map[string]string, error) {
-	// ロケーションの全在庫を取得
+	classifications, err := a.classifyItems(ctx, locationID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string, len(classifications))
+	for _, c := range classifications {
+		result[c.ItemID] = c.ABCClass
+	}
+	return result, nil
+}
+
+// ClassifyItems runs ABC/XYZ classification for a location and stores the result as history
+// ロケーションのABC/XYZ分類を実行し、結果を履歴として保存
+func (a *AnalyticsEngineImpl) ClassifyItems(ctx context.Context, locationID string) ([]ItemClassification, error) {
+	classifications, err := a.classifyItems(ctx, locationID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if len(classifications) == 0 {
+		return classifications, nil
+	}
+
+	if err := a.storage.SaveItemClassifications(ctx, classifications); err != nil {
+		return nil, NewStorageError("save_item_classifications", "商品分類の保存に失敗しました", err)
+	}
+
+	a.logger.Info("商品分類完了",
+		zap.String("location_id", locationID),
+		zap.Int("items", len(classifications)),
+		zap.Int("window_days", a.classification.WindowDays),
+	)
+
+	return classifications, nil
+}
+
+// GetItemClassifications returns the latest stored classification of a location
+// ロケーションの最新の分類結果を取得
+func (a *AnalyticsEngineImpl) GetItemClassifications(ctx context.Context, locationID string) ([]ItemClassification, error) {
+	classifications, err := a.storage.ListLatestItemClassifications(ctx, locationID)
+	if err != nil {
+		return nil, NewStorageError("list_latest_item_classifications", "商品分類取得に失敗しました", err)
+	}
+	return classifications, nil
+}
+
+// GetItemClassificationHistory returns how an item's classes changed over past runs
+// 商品・ロケーションの分類履歴を取得（新しい順）
+func (a *AnalyticsEngineImpl) GetItemClassificationHistory(ctx context.Context, itemID, locationID string, limit int) ([]ItemClassification, error) {
+	history, err := a.storage.ListItemClassificationHistory(ctx, itemID, locationID, limit)
+	if err != nil {
+		return nil, NewStorageError("list_item_classification_history", "商品分類履歴取得に失敗しました", err)
+	}
+	return history, nil
+}
+
+// itemConsumption accumulates the outbound value and per-bucket demand of an item
+// 商品ごとの出庫金額と期間別出庫数量の集計
+type itemConsumption struct {
+	itemID   string
+	value    Money
+	quantity int64
+	demand   []float64
+}
+
+// classifyItems computes ABC classes from outbound value and XYZ classes from demand variability
+// 出庫金額からABC区分、期間別出庫数量の変動係数からXYZ区分を算出
+//
+// 集計期間内に出庫のない在庫商品はC区分・Z区分となります。
+func (a *AnalyticsEngineImpl) classifyItems(ctx context.Context, locationID string, now time.Time) ([]ItemClassification, error) {
+	config := a.classification
+	if config.WindowDays <= 0 || config.BucketDays <= 0 {
+		return nil, NewValidationError("window_days", "集計期間と集計単位は1日以上である必要があります", fmt.Sprintf("%d/%d", config.WindowDays, config.BucketDays))
+	}
+
+	from := now.AddDate(0, 0, -config.WindowDays)
+	transactions, err := a.storage.ListOutboundTransactions(ctx, locationID, from, now)
+	if err != nil {
+		return nil, NewStorageError("list_outbound_transactions", "出庫トランザクション取得に失敗しました", err)
+	}
+
+	// 出庫実績のない在庫商品も分類対象に含める
 	stocks, err := a.storage.ListStockByLocation(ctx, locationID)
 	if err != nil {
 		return nil, NewStorageError("list_stock_by_location", "ロケーション在庫取得に失敗しました", err)
 	}
 
-	// 各商品の年間売上高を計算（簡略化版）
-	itemValues := make(map[string]float64)
+	buckets := (config.WindowDays + config.BucketDays - 1) / config.BucketDays
+	bucketSize := time.Duration(config.BucketDays) * 24 * time.Hour
+
+	consumption := make(map[string]*itemConsumption)
+	entry := func(itemID string) *itemConsumption {
+		c, ok := consumption[itemID]
+		if !ok {
+			c = &itemConsumption{itemID: itemID, demand: make([]float64, buckets)}
+			consumption[itemID] = c
+		}
+		return c
+	}
 	for _, stock := range stocks {
-		// 実際には過去12ヶ月の出庫データから計算すべき
-		// ここでは簡略化して在庫数量 × 単価で代用
-		item, err := a.storage.GetItem(ctx, stock.ItemID)
-		if err != nil {
-			continue
+		entry(stock.ItemID)
+	}
+
+	itemMasters := make(map[string]Item)
+	for _, tx := range transactions {
+		c := entry(tx.ItemID)
+
+		item, ok := itemMasters[tx.ItemID]
+		if !ok {
+			if found, err := a.storage.GetItem(ctx, tx.ItemID); err == nil {
+				item = *found
+			}
+			itemMasters[tx.ItemID] = item
 		}
 
-		// 年間出庫予想値として在庫数量の10倍を使用（仮定）
-		estimatedAnnualSales := float64(stock.Quantity*10) * item.UnitCost
-		itemValues[stock.ItemID] = estimatedAnnualSales
-	}
+		// 出庫時の単価がない場合は商品マスタの単価で評価
+		unitCost := item.UnitCost
+		if tx.UnitCost != nil {
+			unitCost = *tx.UnitCost
+		}
 
-	// 値でソートして分類
-	return a.classifyABC(itemValues), nil
-}
+		c.value += unitCost.MulQuantity(tx.Quantity, item.QuantityPrecision)
+		c.quantity += tx.Quantity
 
-// classifyABC classifies items into A, B, C categories
-// 商品をA、B、Cカテゴリに分類
-func (a *AnalyticsEngineImpl) classifyABC(itemValues map[string]float64) map[string]string {
-	// 値の順序でアイテムをソート
-	type ItemValue struct {
-		ItemID string
-		Value  float64
+		bucket := int(tx.CreatedAt.Sub(from) / bucketSize)
+		if bucket < 0 {
+			bucket = 0
+		} else if bucket >= buckets {
+			bucket = buckets - 1
+		}
+		c.demand[bucket] += float64(tx.Quantity)
 	}
 
-	var items []ItemValue
-	totalValue := 0.0
-	for itemID, value := range itemValues {
-		items = append(items, ItemValue{ItemID: itemID, Value: value})
-		totalValue += value
+	items := make([]*itemConsumption, 0, len(consumption))
+	var totalValue Money
+	for _, c := range consumption {
+		items = append(items, c)
+		totalValue += c.value
 	}
-
 	sort.Slice(items, func(i, j int) bool {
-		return items[i].Value > items[j].Value
+		if items[i].value != items[j].value {
+			return items[i].value > items[j].value
+		}
+		return items[i].itemID < items[j].itemID
 	})
 
-	// ABC分類（80-15-5の法則）
-	classification := make(map[string]string)
-	cumulativeValue := 0.0
+	classifications := make([]ItemClassification, 0, len(items))
+	var cumulativeValue Money
+	for _, c := range items {
+		// 自身より上位の商品だけで閾値に達していなければその区分に含める
+		// （1商品で閾値を超える場合もA区分とするため）
+		previousPercent := 100.0
+		if totalValue > 0 {
+			previousPercent = cumulativeValue.Float64() / totalValue.Float64() * 100
+		}
+		cumulativeValue += c.value
 
-	for _, item := range items {
-		cumulativeValue += item.Value
-		percentage := cumulativeValue / totalValue
+		abcClass := "C"
+		if c.value > 0 && previousPercent < config.ACutoffPercent {
+			abcClass = "A"
+		} else if c.value > 0 && previousPercent < config.BCutoffPercent {
+			abcClass = "B"
+		}
 
-		if percentage <= 0.8 {
-			classification[item.ItemID] = "A"
-		} else if percentage <= 0.95 {
-			classification[item.ItemID] = "B"
-		} else {
-			classification[item.ItemID] = "C"
+		cumulativePercent := 0.0
+		if totalValue > 0 {
+			cumulativePercent = cumulativeValue.Float64() / totalValue.Float64() * 100
 		}
-	}
 
-	return classification
+		variation := variationCoefficient(c.demand)
+		xyzClass := "Z"
+		if c.quantity > 0 && variation <= config.XMaxVariation {
+			xyzClass = "X"
+		} else if c.quantity > 0 && variation <= config.YMaxVariation {
+			xyzClass = "Y"
+		}
+
+		classifications = append(classifications, ItemClassification{
+			ItemID:               c.itemID,
+			LocationID:           locationID,
+			ABCClass:             abcClass,
+			XYZClass:             xyzClass,
+			ConsumptionValue:     c.value,
+			ConsumptionQuantity:  c.quantity,
+			CumulativePercent:    cumulativePercent,
+			VariationCoefficient: variation,
+			WindowDays:           config.WindowDays,
+			ClassifiedAt:         now,
+		})
+	}
+
+	return classifications, nil
 }
 
-// GetTurnoverRate calculates inventory turnover rate for an item
-// 商品の在庫回転率を計算
-func (a *AnalyticsEngineImpl) GetTurnoverRate(ctx context.Context, itemID string, period time.Duration) (float64, error) {
-	// 指定期間の出庫量を計算
-	transactions, err := a.storage.GetTransactionHistory(ctx, itemID, 10000)
-	if err != nil {
-		return 0, NewStorageError("get_transaction_history", "トランザクション履歴取得に失敗しました", err)
+// variationCoefficient returns the coefficient of variation (stddev / mean) of per-bucket demand
+// 期間別出庫数量の変動係数（標準偏差 / 平均）を返す。出庫がない場合は0
+func variationCoefficient(demand []float64) float64 {
+	if len(demand) == 0 {
+		return 0
 	}
 
-	cutoffDate := time.Now().Add(-period)
-	outboundQuantity := int64(0)
+	sum := 0.0
+	for _, d := range demand {
+		sum += d
+	}
+	mean := sum / float64(len(demand))
+	if mean == 0 {
+		return 0
+	}
 
-	for _, tx := range transactions {
-		if tx.CreatedAt.After(cutoffDate) && tx.Type == TransactionTypeOutbound {
-			outboundQuantity += tx.Quantity
-		}
+	variance := 0.0
+	for _, d := range demand {
+		variance += (d - mean) * (d - mean)
 	}
+	variance /= float64(len(demand))
 
-	// 平均在庫量を計算（簡略化：現在の総在庫量を使用）
-	// TODO: より正確な平均在庫計算を実装
-	avgInventory := int64(100) // 仮の値
+	return math.Sqrt(variance) / mean
+}
 
-	if avgInventory == 0 {
-		return 0, nil
+// GetTurnoverRate calculates the annualized turnover of an item across all locations
+// 商品の全ロケーション合計の在庫回転率（年換算）を計算
+func (a *AnalyticsEngineImpl) GetTurnoverRate(ctx context.Context, itemID string, period time.Duration) (float64, error) {
+	to := time.Now()
+	report, err := a.CalculateKPIs(ctx, itemID, "", to.Add(-period), to)
+	if err != nil {
+		return 0, err
 	}
 
-	// 回転率 = 期間中の出庫量 / 平均在庫量
-	turnoverRate := float64(outboundQuantity) / float64(avgInventory)
-
-	// 年間回転率に換算
+	// 回転率 = 期間中の出庫量 / 平均在庫量 を年間回転率に換算
 	daysInPeriod := period.Hours() / 24
-	yearlyTurnoverRate := turnoverRate * (365 / daysInPeriod)
-
-	return yearlyTurnoverRate, nil
+	return report.Summary.TurnoverRate * (365 / daysInPeriod), nil
 }
 
-// GetSlowMovingItems identifies slow-moving items
-// 動きの遅い商品を特定
-func (a *AnalyticsEngineImpl) GetSlowMovingItems(ctx context.Context, locationID string, threshold time.Duration) ([]string, error) {
-	stocks, err := a.storage.ListStockByLocation(ctx, locationID)
+// CalculateKPIs calculates turnover, days of supply, stockout days and fill rate over a period
+// 期間中の回転率・在庫日数・欠品日数・充足率を計算
+//
+// 商品のみ指定時は全ロケーション、ロケーションのみ指定時はロケーション内の全商品が対象です。
+// 平均在庫と欠品日数は期首の時点在庫と台帳明細から在庫推移を再構築して求めます。
+func (a *AnalyticsEngineImpl) CalculateKPIs(ctx context.Context, itemID, locationID string, from, to time.Time) (*KPIReport, error) {
+	if itemID == "" && locationID == "" {
+		return nil, NewValidationError("item_id", "商品IDまたはロケーションIDを指定してください", "")
+	}
+	if !from.Before(to) {
+		return nil, NewValidationError("period", "集計開始日時は終了日時より前である必要があります",
+			fmt.Sprintf("%s - %s", from.Format(time.RFC3339), to.Format(time.RFC3339)))
+	}
+
+	var stocks []Stock
+	var err error
+	switch {
+	case itemID != "" && locationID != "":
+		stocks = []Stock{{ItemID: itemID, LocationID: locationID}}
+	case itemID != "":
+		stocks, err = a.storage.ListStockByItem(ctx, itemID)
+	default:
+		stocks, err = a.storage.ListStockByLocation(ctx, locationID)
+	}
 	if err != nil {
-		return nil, NewStorageError("list_stock_by_location", "ロケーション在庫取得に失敗しました", err)
+		return nil, NewStorageError("list_stock", "在庫取得に失敗しました", err)
 	}
 
-	var slowMovingItems []string
-	cutoffDate := time.Now().Add(-threshold)
+	report := &KPIReport{
+		ItemID:     itemID,
+		LocationID: locationID,
+		From:       from,
+		To:         to,
+		Items:      make([]ItemKPI, 0, len(stocks)),
+	}
+
+	// 充足率は出荷指示の明細から商品単位で求める
+	fillRates := make(map[string]*float64)
+	var totalOrdered, totalShipped int64
 
 	for _, stock := range stocks {
-		// 各商品の最新出庫日を確認
-		transactions, err := a.storage.GetTransactionHistory(ctx, stock.ItemID, 100)
+		kpi, err := a.calculateItemKPI(ctx, stock.ItemID, stock.LocationID, from, to)
 		if err != nil {
-			continue
+			return nil, err
 		}
 
-		hasRecentActivity := false
-		for _, tx := range transactions {
-			if tx.Type == TransactionTypeOutbound && tx.CreatedAt.After(cutoffDate) {
-				hasRecentActivity = true
-				break
+		fillRate, ok := fillRates[stock.ItemID]
+		if !ok {
+			ordered, shipped, err := a.storage.GetOrderFillQuantities(ctx, stock.ItemID, from, to)
+			if err != nil {
+				return nil, NewStorageError("get_order_fill_quantities", "充足数量の集計に失敗しました", err)
+			}
+			if ordered > 0 {
+				rate := float64(shipped) / float64(ordered)
+				fillRate = &rate
 			}
+			fillRates[stock.ItemID] = fillRate
+			totalOrdered += ordered
+			totalShipped += shipped
 		}
+		kpi.FillRate = fillRate
 
-		if !hasRecentActivity && stock.Quantity > 0 {
-			slowMovingItems = append(slowMovingItems, stock.ItemID)
+		report.Items = append(report.Items, *kpi)
+		report.Summary.OutboundQuantity += kpi.OutboundQuantity
+		report.Summary.AverageInventory += kpi.AverageInventory
+		if kpi.StockoutDays > 0 {
+			report.Summary.StockoutItems++
 		}
 	}
 
-	return slowMovingItems, nil
+	report.Summary.ItemCount = len(report.Items)
+	if report.Summary.AverageInventory > 0 {
+		report.Summary.TurnoverRate = float64(report.Summary.OutboundQuantity) / report.Summary.AverageInventory
+	}
+	if totalOrdered > 0 {
+		rate := float64(totalShipped) / float64(totalOrdered)
+		report.Summary.FillRate = &rate
+	}
+
+	return report, nil
 }
 
-// GenerateStockReport generates inventory reports
-// 在庫レポートを生成
-func (a *AnalyticsEngineImpl) GenerateStockReport(ctx context.Context, locationID string, reportType ReportType) ([]byte, error) {
-	switch reportType {
-	case ReportTypeStock:
-		return a.generateStockReport(ctx, locationID)
-	case ReportTypeABC:
-		return a.generateABCReport(ctx, locationID)
-	default:
-		return nil, fmt.Errorf("未対応のレポートタイプです: %s", reportType)
+// calculateItemKPI reconstructs the stock level of an item at a location from the ledger and derives its KPIs
+// 台帳から商品・ロケーションの在庫推移を再構築してKPIを算出
+func (a *AnalyticsEngineImpl) calculateItemKPI(ctx context.Context, itemID, locationID string, from, to time.Time) (*ItemKPI, error) {
+	opening, err := a.storage.GetStockAsOf(ctx, itemID, locationID, from)
+	if err != nil {
+		return nil, NewStorageError("get_stock_as_of", "時点在庫取得に失敗しました", err)
 	}
-}
 
-// generateStockReport generates basic stock report
-// 基本在庫レポートを生成
-func (a *AnalyticsEngineImpl) generateStockReport(ctx context.Context, locationID string) ([]byte, error) {
-	stocks, err := a.storage.ListStockByLocation(ctx, locationID)
+	entries, err := a.storage.ListLedgerEntries(ctx, itemID, locationID, from, to)
 	if err != nil {
-		return nil, err
+		return nil, NewStorageError("list_ledger_entries", "台帳明細取得に失敗しました", err)
 	}
 
-	// 簡略化：CSVフォーマットで出力
-	report := "商品ID,在庫数量,予約済み,利用可能,最終更新\n"
-	for _, stock := range stocks {
-		line := fmt.Sprintf("%s,%d,%d,%d,%s\n",
-			stock.ItemID, stock.Quantity, stock.Reserved, stock.Available,
-			stock.UpdatedAt.Format("2006-01-02 15:04:05"))
-		report += line
+	kpi := &ItemKPI{
+		ItemID:          itemID,
+		LocationID:      locationID,
+		OpeningQuantity: opening,
+	}
+
+	// 在庫数量×保持時間を積分して時間加重平均を求め、日ごとの最小在庫で欠品日を判定
+	balance := opening
+	area := 0.0
+	cursor := from
+	next := 0
+	for dayStart := from; dayStart.Before(to); dayStart = dayStart.Add(24 * time.Hour) {
+		dayEnd := dayStart.Add(24 * time.Hour)
+		if dayEnd.After(to) {
+			dayEnd = to
+		}
+
+		minBalance := balance
+		for ; next < len(entries) && !entries[next].CreatedAt.After(dayEnd); next++ {
+			entry := entries[next]
+			area += float64(balance) * entry.CreatedAt.Sub(cursor).Seconds()
+			cursor = entry.CreatedAt
+			balance += entry.Delta
+			if balance < minBalance {
+				minBalance = balance
+			}
+			// 移動レッグ・取消は在庫推移には含めるが出庫実績には含めない
+			if entry.Consumption {
+				kpi.OutboundQuantity -= entry.Delta
+			}
+		}
+
+		if minBalance <= 0 {
+			kpi.StockoutDays++
+		}
+	}
+	area += float64(balance) * to.Sub(cursor).Seconds()
+
+	periodSeconds := to.Sub(from).Seconds()
+	kpi.ClosingQuantity = balance
+	kpi.AverageInventory = area / periodSeconds
+
+	if kpi.AverageInventory > 0 {
+		kpi.TurnoverRate = float64(kpi.OutboundQuantity) / kpi.AverageInventory
+		kpi.AnnualizedTurnover = kpi.TurnoverRate * (365 * 24 * 60 * 60) / periodSeconds
 	}
 
-	return []byte(report), nil
+	// 在庫日数 = 期末在庫 / 1日平均出庫数量
+	if kpi.OutboundQuantity > 0 {
+		dailyOutbound := float64(kpi.OutboundQuantity) / (periodSeconds / (24 * 60 * 60))
+		daysOfSupply := math.Max(float64(balance), 0) / dailyOutbound
+		kpi.DaysOfSupply = &daysOfSupply
+	}
+
+	return kpi, nil
 }
 
-// generateABCReport generates ABC analysis report
-// ABC分析レポートを生成
-func (a *AnalyticsEngineImpl) generateABCReport(ctx context.Context, locationID string) ([]byte, error) {
-	classification, err := a.CalculateABCClassification(ctx, locationID)
+// GetSlowMovingItems identifies items in stock at a location with no outbound within the threshold
+// 閾値の期間内にロケーションからの出庫がない在庫商品を特定
+func (a *AnalyticsEngineImpl) GetSlowMovingItems(ctx context.Context, locationID string, threshold time.Duration) ([]string, error) {
+	positions, err := a.storage.ListStockPositions(ctx, locationID)
 	if err != nil {
+		return nil, NewStorageError("list_stock_positions", "在庫状況の取得に失敗しました", err)
+	}
+
+	var slowMovingItems []string
+	cutoffDate := time.Now().Add(-threshold)
+
+	for _, position := range positions {
+		if position.LastOutboundAt == nil || !position.LastOutboundAt.After(cutoffDate) {
+			slowMovingItems = append(slowMovingItems, position.ItemID)
+		}
+	}
+
+	return slowMovingItems, nil
+}
+
+// GenerateStockReport generates inventory reports as CSV
+// 在庫レポートをCSVで生成
+//
+// 期間を伴うレポートは直近30日間を対象とします。期間・カテゴリ・出力形式を
+// 指定する場合は WriteReport を使用してください。
+func (a *AnalyticsEngineImpl) GenerateStockReport(ctx context.Context, locationID string, reportType ReportType) ([]byte, error) {
+	to := time.Now()
+	request := ReportRequest{
+		Type:   reportType,
+		Format: ReportFormatCSV,
+		Filter: ReportFilter{LocationID: locationID, From: to.AddDate(0, 0, -30), To: to},
+	}
+
+	var buffer bytes.Buffer
+	if err := a.WriteReport(ctx, &buffer, request); err != nil {
 		return nil, err
 	}
+	return buffer.Bytes(), nil
+}
+
+// GenerateStockReportAsOf generates a stock report reconstructed as of the given time
+// 指定時点の在庫を台帳から再構築した在庫レポートを生成
+func (a *AnalyticsEngineImpl) GenerateStockReportAsOf(ctx context.Context, locationID string, asOf time.Time) ([]byte, error) {
+	snapshots, err := a.storage.ListStockAsOfByLocation(ctx, locationID, asOf)
+	if err != nil {
+		return nil, NewStorageError("list_stock_as_of_by_location", "ロケーション時点在庫取得に失敗しました", err)
+	}
 
-	// 簡略化：CSVフォーマットで出力
-	report := "商品ID,分類\n"
-	for itemID, class := range classification {
-		line := fmt.Sprintf("%s,%s\n", itemID, class)
-		report += line
+	var buffer bytes.Buffer
+	sink, err := newCSVReportSink(&buffer, []reportColumn{
+		{"item_id", "商品ID"}, {"quantity", "在庫数量"}, {"as_of", "基準日時"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, snapshot := range snapshots {
+		item, err := a.storage.GetItem(ctx, snapshot.ItemID)
+		if err != nil {
+			return nil, NewStorageError("get_item", "商品取得に失敗しました", err)
+		}
+		if err := sink.writeRow([]interface{}{snapshot.ItemID, reportQuantity(snapshot.Quantity, *item), snapshot.AsOf}); err != nil {
+			return nil, err
+		}
+	}
+	if err := sink.close(); err != nil {
+		return nil, err
 	}
 
-	return []byte(report), nil
+	return buffer.Bytes(), nil
 }
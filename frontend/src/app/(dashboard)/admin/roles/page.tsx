@@ -25,7 +25,7 @@ const ROLES: {
                 'inventory:read', 'inventory:write',
                 'master:read', 'master:write',
                 'stocktaking:read', 'stocktaking:write',
-                'report:read', 'audit:read', 'user:manage',
+                'report:read', 'report:write', 'audit:read', 'user:manage',
             ],
         },
         {
@@ -37,7 +37,7 @@ const ROLES: {
                 'inventory:read', 'inventory:write',
                 'master:read', 'master:write',
                 'stocktaking:read', 'stocktaking:write',
-                'report:read',
+                'report:read', 'report:write',
             ],
         },
         {
@@ -81,6 +81,7 @@ const ALL_PERMISSIONS: { key: Permission; label: string; category: string }[] =
     { key: 'stocktaking:read', label: '棚卸参照', category: '棚卸' },
     { key: 'stocktaking:write', label: '棚卸操作', category: '棚卸' },
     { key: 'report:read', label: 'レポート参照', category: 'レポート' },
+    { key: 'report:write', label: 'レポートスケジュール編集', category: 'レポート' },
     { key: 'audit:read', label: '監査ログ参照', category: '管理' },
     { key: 'user:manage', label: 'ユーザー管理', category: '管理' },
 ];
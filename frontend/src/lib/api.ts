@@ -95,6 +95,64 @@ class ApiClient {
             this.request<import('@/types').StockAlert[]>(`/alerts/${locationId}`),
     };
 
+    // 分析関連
+    analytics = {
+        getKPIs: (params: import('@/types').KPIParams) => {
+            const query = new URLSearchParams();
+            if (params.itemId) query.set('item_id', params.itemId);
+            if (params.locationId) query.set('location_id', params.locationId);
+            if (params.from) query.set('from', params.from);
+            if (params.to) query.set('to', params.to);
+            if (params.periodDays) query.set('period_days', String(params.periodDays));
+            return this.request<import('@/types').KPIReport>(`/analytics/kpis?${query.toString()}`);
+        },
+        getAging: (locationId: string = 'all', deadStockDays?: number) => {
+            const query = deadStockDays ? `?dead_stock_days=${deadStockDays}` : '';
+            return this.request<import('@/types').AgingReport>(`/analytics/aging/${locationId}${query}`);
+        },
+    };
+
+    // 定期レポート・レポートアーカイブ
+    reports = {
+        listSchedules: () =>
+            this.request<{ schedules: import('@/types').ReportSchedule[]; count: number }>('/reports/schedules'),
+        createSchedule: (data: import('@/types').ReportScheduleInput) =>
+            this.request<import('@/types').ReportSchedule>('/reports/schedules', { method: 'POST', body: data }),
+        updateSchedule: (id: string, data: import('@/types').ReportScheduleInput) =>
+            this.request<import('@/types').ReportSchedule>(`/reports/schedules/${id}`, { method: 'PUT', body: data }),
+        deleteSchedule: (id: string) => this.request(`/reports/schedules/${id}`, { method: 'DELETE' }),
+        runSchedule: (id: string) =>
+            this.request<import('@/types').ReportArchive>(`/reports/schedules/${id}/run`, { method: 'POST' }),
+        listArchives: (params: { scheduleId?: string; type?: import('@/types').ReportType; page?: number; pageSize?: number } = {}) => {
+            const { page = 1, pageSize = 20 } = params;
+            const query = new URLSearchParams({ offset: String((page - 1) * pageSize), limit: String(pageSize) });
+            if (params.scheduleId) query.set('schedule_id', params.scheduleId);
+            if (params.type) query.set('type', params.type);
+            return this.request<{ archives: import('@/types').ReportArchive[]; count: number }>(`/reports/archive?${query.toString()}`);
+        },
+        archiveDownloadUrl: (id: string) => `${this.baseUrl}/reports/archive/${id}/download`,
+    };
+
+    // 会計期間・締め
+    periods = {
+        list: () =>
+            this.request<{ periods: import('@/types').FiscalPeriod[]; count: number }>('/periods'),
+        create: (data: import('@/types').FiscalPeriodInput) =>
+            this.request<import('@/types').FiscalPeriod>('/periods', { method: 'POST', body: data }),
+        close: (id: string) =>
+            this.request<import('@/types').FiscalPeriod>(`/periods/${id}/close`, { method: 'POST' }),
+        reopen: (id: string, reason: string) =>
+            this.request<import('@/types').FiscalPeriod>(`/admin/periods/${id}/reopen`, { method: 'POST', body: { reason } }),
+        listBalances: (id: string, params: { locationId?: string; page?: number; pageSize?: number } = {}) => {
+            const { page = 1, pageSize = 100 } = params;
+            const query = new URLSearchParams({ offset: String((page - 1) * pageSize), limit: String(pageSize) });
+            if (params.locationId) query.set('location_id', params.locationId);
+            return this.request<{ balances: import('@/types').PeriodEndBalance[]; count: number }>(`/periods/${id}/balances?${query.toString()}`);
+        },
+        listEvents: (id: string) =>
+            this.request<{ events: import('@/types').FiscalPeriodEvent[]; count: number }>(`/periods/${id}/events`),
+    };
+
     // 商品マスタ（バックエンドは /items を使用）
     products = {
         list: (page = 1, pageSize = 20) =>
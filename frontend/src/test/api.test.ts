@@ -174,6 +174,44 @@ describe('ApiClient', () => {
         });
     });
 
+    describe('analytics', () => {
+        it('getKPIs should map params to snake_case query', async () => {
+            mockFetch.mockResolvedValueOnce({
+                ok: true,
+                json: async () => ({ items: [], summary: { item_count: 0 } }),
+            });
+
+            await api.analytics.getKPIs({ locationId: 'loc-1', periodDays: 90 });
+
+            expect(mockFetch).toHaveBeenCalledWith(
+                'http://localhost:8080/api/v1/analytics/kpis?location_id=loc-1&period_days=90',
+                expect.objectContaining({ method: 'GET' })
+            );
+        });
+    });
+
+    describe('reports', () => {
+        it('listArchives should map filters and pagination to query', async () => {
+            mockFetch.mockResolvedValueOnce({
+                ok: true,
+                json: async () => ({ archives: [], count: 0 }),
+            });
+
+            await api.reports.listArchives({ scheduleId: 'rs-1', type: 'valuation', page: 2, pageSize: 10 });
+
+            expect(mockFetch).toHaveBeenCalledWith(
+                'http://localhost:8080/api/v1/reports/archive?offset=10&limit=10&schedule_id=rs-1&type=valuation',
+                expect.objectContaining({ method: 'GET' })
+            );
+        });
+
+        it('archiveDownloadUrl should point to the download endpoint', () => {
+            expect(api.reports.archiveDownloadUrl('ra-1')).toBe(
+                'http://localhost:8080/api/v1/reports/archive/ra-1/download'
+            );
+        });
+    });
+
     describe('auth', () => {
         it('login should post credentials', async () => {
             const mockResponse = {
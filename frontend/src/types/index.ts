@@ -28,7 +28,11 @@ export interface Product {
     category: string;
     unit: string;
     unitCost: number;
+    quantityPrecision: number; // 数量の小数桁数（0-6、数量は10^-quantityPrecision単位の整数）
+    parentItemId?: string; // バリアントの親商品ID
+    attributes?: Record<string, string | number | boolean>; // カスタム属性
     isActive: boolean;
+    archivedAt?: string; // アーカイブ日時（削除済みの商品のみ）
     createdAt: string;
     updatedAt: string;
 }
@@ -42,6 +46,7 @@ export interface Location {
     parentId?: string;
     capacity?: number;
     isActive: boolean;
+    archivedAt?: string; // アーカイブ日時（削除済みのロケーションのみ）
     createdAt: string;
     updatedAt: string;
 }
@@ -88,6 +93,10 @@ export interface Transaction {
     quantity: number;
     reference: string;
     lotNumber?: string;
+    unitCost?: number; // 基準通貨建ての単価
+    costCurrency?: string; // 仕入通貨（外貨建ての入庫のみ）
+    sourceUnitCost?: number; // 仕入通貨建ての単価
+    exchangeRate?: number; // 換算に使用した為替レート
     metadata?: Record<string, string>;
     createdAt: string;
     createdBy: string;
@@ -135,6 +144,325 @@ export interface StockAlert {
 
 export type AlertType = 'low_stock' | 'over_stock' | 'expiring' | 'expired' | 'discrepancy';
 
+// 在庫KPI（/analytics/kpis のレスポンスをそのまま扱うためスネークケース）
+export interface ItemKPI {
+    item_id: string;
+    location_id: string;
+    opening_quantity: number;
+    closing_quantity: number;
+    average_inventory: number;
+    outbound_quantity: number;
+    turnover_rate: number;
+    annualized_turnover: number;
+    days_of_supply: number | null;
+    stockout_days: number;
+    fill_rate: number | null;
+}
+
+export interface KPISummary {
+    item_count: number;
+    outbound_quantity: number;
+    average_inventory: number;
+    turnover_rate: number;
+    stockout_items: number;
+    fill_rate: number | null;
+}
+
+export interface KPIReport {
+    item_id?: string;
+    location_id?: string;
+    from: string;
+    to: string;
+    items: ItemKPI[];
+    summary: KPISummary;
+}
+
+export interface KPIParams {
+    itemId?: string;
+    locationId?: string;
+    from?: string;
+    to?: string;
+    periodDays?: number;
+}
+
+// 在庫エイジング
+export interface AgingBucket {
+    label: string;
+    min_days: number;
+    max_days: number | null;
+    quantity: number;
+    value: number;
+}
+
+export interface ItemAging {
+    item_id: string;
+    location_id: string;
+    quantity: number;
+    value: number;
+    buckets: AgingBucket[];
+    average_age_days: number;
+    oldest_receipt_at: string | null;
+    last_movement_at: string | null;
+    days_since_movement: number | null;
+    is_dead_stock: boolean;
+}
+
+export interface LocationAging {
+    location_id: string;
+    quantity: number;
+    value: number;
+    buckets: AgingBucket[];
+    dead_stock_items: number;
+    dead_stock_quantity: number;
+    value_at_risk: number;
+}
+
+export interface AgingReport {
+    location_id?: string;
+    as_of: string;
+    dead_stock_days: number;
+    items: ItemAging[];
+    locations: LocationAging[];
+    total_value: number;
+    value_at_risk: number;
+}
+
+// 会計期間・締め
+export type FiscalPeriodStatus = 'open' | 'closed';
+
+export interface FiscalPeriod {
+    id: string;
+    name: string;
+    start_date: string;
+    end_date: string;
+    status: FiscalPeriodStatus;
+    closed_at: string | null;
+    closed_by: string;
+    created_at: string;
+    created_by: string;
+}
+
+export interface FiscalPeriodInput {
+    name: string;
+    start_date: string; // YYYY-MM-DD
+    end_date: string; // YYYY-MM-DD
+}
+
+export interface PeriodEndBalance {
+    period_id: string;
+    item_id: string;
+    location_id: string;
+    quantity: number;
+    unit_cost: number;
+    value: number;
+}
+
+export type AttributeType = 'string' | 'number' | 'boolean' | 'enum';
+
+export interface AttributeDefinition {
+    name: string;
+    type: AttributeType;
+    required: boolean;
+    options?: string[]; // enum型の選択肢
+    unit?: string;
+}
+
+export interface CategoryAttributeSchema {
+    category: string;
+    attributes: AttributeDefinition[];
+    updated_at: string;
+}
+
+export interface VariantGroup {
+    parent_item_id: string;
+    axes: string[]; // バリアント軸となる属性名（size, colourなど）
+    variants?: Product[];
+    created_at: string;
+    updated_at: string;
+}
+
+export interface ItemPage {
+    items: Product[];
+    total_count: number; // 条件に一致する全件数
+    next_cursor?: string; // 次ページのカーソル（最終ページは空）
+    count: number;
+}
+
+// 商品・ロケーションのアーカイブを妨げる在庫・伝票の件数
+export interface MasterUsage {
+    stock_quantity: number;
+    reserved_quantity: number;
+    open_purchase_orders: number;
+    open_transfer_orders: number;
+    open_outbound_orders: number;
+    pending_cycle_counts: number;
+    pending_replenishments: number;
+    active_variants: number; // 商品のみ
+    active_kits: number; // 商品のみ
+}
+
+// 商品・ロケーションマスタの一括取り込みジョブ
+export type MasterDataType = 'items' | 'locations';
+
+export type MasterImportMode = 'create' | 'upsert';
+
+export type MasterImportStatus = 'pending' | 'running' | 'completed' | 'failed';
+
+export interface ImportRowError {
+    row: number; // 見出し行が1行目
+    id?: string;
+    field?: string;
+    message: string;
+    value?: string;
+}
+
+export interface MasterImportJob {
+    id: string;
+    target: MasterDataType;
+    format: 'csv' | 'xlsx';
+    mode: MasterImportMode;
+    dry_run: boolean;
+    status: MasterImportStatus;
+    total_rows: number;
+    processed_rows: number;
+    created_count: number;
+    updated_count: number;
+    error_count: number;
+    errors: ImportRowError[]; // 最大1000件
+    error?: string;
+    created_by: string;
+    created_at: string;
+    started_at?: string;
+    completed_at?: string;
+}
+
+// 期首在庫の取り込み
+export type OpeningBalanceStatus = 'validated' | 'rejected' | 'posting' | 'posted' | 'failed' | 'rolled_back';
+
+export interface OpeningBalanceLine {
+    row: number;
+    item_id: string;
+    location_id: string;
+    quantity: number;
+    unit_cost: number; // 取り込みファイルの通貨建ての単価
+    currency: string;
+    value: number; // 基準通貨での金額
+    lot_number?: string;
+    expiry_date?: string;
+    transaction_id?: string;
+    lot_id?: string;
+    reversal_id?: string; // 取消時の逆仕訳ID
+}
+
+export interface OpeningBalanceLoad {
+    id: string;
+    reference: string;
+    as_of: string; // 移行基準日時（入庫の計上日時）
+    status: OpeningBalanceStatus;
+    line_count: number;
+    total_value: number;
+    lines?: OpeningBalanceLine[];
+    error_count: number;
+    errors?: ImportRowError[]; // 最大1000件
+    error?: string;
+    created_by: string;
+    created_at: string;
+    posted_at?: string;
+    rolled_back_at?: string;
+    rolled_back_by?: string;
+    rollback_reason?: string;
+}
+
+export type BarcodeType = 'JAN' | 'EAN' | 'UPC' | 'GTIN';
+
+export type PackageLevel = 'each' | 'inner' | 'case' | 'pallet';
+
+export interface ItemBarcode {
+    gtin: string; // 14桁に正規化したGTIN
+    code: string;
+    item_id: string;
+    type: BarcodeType;
+    package_level: PackageLevel;
+    units_per_package: number;
+    created_at: string;
+}
+
+export interface ScanResult {
+    input: string;
+    gtin: string;
+    barcode: ItemBarcode;
+    item: Product;
+    lot_number?: string;
+    lot?: Lot;
+    expiry_date?: string;
+    serial_number?: string;
+    package_level: PackageLevel;
+    package_count: number;
+    quantity: number; // 推奨数量（入数×包装数）
+}
+
+export interface ExchangeRate {
+    id: string;
+    currency: string;
+    base_currency: string;
+    rate: number; // 外貨1単位あたりの基準通貨額
+    effective_date: string;
+    created_at: string;
+    created_by: string;
+}
+
+export interface FiscalPeriodEvent {
+    id: string;
+    period_id: string;
+    action: 'close' | 'reopen';
+    reason: string;
+    balance_count: number;
+    performed_at: string;
+    performed_by: string;
+}
+
+// 定期レポート
+export type ReportType = 'stock' | 'movement' | 'valuation' | 'abc' | 'turnover';
+export type ReportFormat = 'csv' | 'json' | 'xlsx';
+
+export interface ReportSchedule {
+    id: string;
+    name: string;
+    cron_expression: string;
+    timezone: string;
+    report_type: ReportType;
+    format: ReportFormat;
+    location_id: string;
+    category: string;
+    period_days: number;
+    is_active: boolean;
+    next_run_at: string | null;
+    last_run_at: string | null;
+    last_error?: string;
+    created_at: string;
+    created_by: string;
+    updated_at: string;
+}
+
+export type ReportScheduleInput = Pick<ReportSchedule, 'name' | 'cron_expression' | 'report_type'> &
+    Partial<Pick<ReportSchedule, 'timezone' | 'format' | 'location_id' | 'category' | 'period_days' | 'is_active'>>;
+
+export interface ReportArchive {
+    id: string;
+    schedule_id?: string;
+    report_type: ReportType;
+    format: ReportFormat;
+    location_id: string;
+    category: string;
+    period_from: string;
+    period_to: string;
+    file_name: string;
+    size_bytes: number;
+    checksum: string;
+    generated_at: string;
+    generated_by: string;
+}
+
 // 監査ログ
 export interface AuditLog {
     id: string;
@@ -157,6 +485,7 @@ export type Permission =
     | 'stocktaking:read'
     | 'stocktaking:write'
     | 'report:read'
+    | 'report:write'
     | 'audit:read'
     | 'user:manage';
 